@@ -0,0 +1,44 @@
+package alerts
+
+// Template provides the base template for the `linkerd alerts` command. It
+// renders a Prometheus rules file with a group of alerting rules for each
+// resource. The objectives set to zero disable their alerts.
+const Template = `groups:
+{{- range .Resources}}
+- name: {{.GroupName}}
+  rules:
+{{- if $.SuccessRate}}
+  - alert: LinkerdLowSuccessRate
+    expr: |-
+      sum(rate(response_total{classification="success", direction="inbound", {{.Selector}}}[{{$.Window}}])) by ({{.By}})
+      / sum(rate(response_total{direction="inbound", {{.Selector}}}[{{$.Window}}])) by ({{.By}})
+      < {{$.SuccessRate}}
+    for: {{$.For}}
+    labels:
+      severity: {{$.Severity}}
+    annotations:
+      summary: "The success rate of {{.Target}} is below {{$.SuccessRate}}"
+{{- end}}
+{{- if $.LatencyP99Ms}}
+  - alert: LinkerdHighLatency
+    expr: |-
+      histogram_quantile(0.99, sum(rate(response_latency_ms_bucket{direction="inbound", {{.Selector}}}[{{$.Window}}])) by (le, {{.By}}))
+      > {{$.LatencyP99Ms}}
+    for: {{$.For}}
+    labels:
+      severity: {{$.Severity}}
+    annotations:
+      summary: "The p99 latency of {{.Target}} is above {{$.LatencyP99Ms}}ms"
+{{- end}}
+{{- if $.Restarts}}
+  - alert: LinkerdProxyRestartLoop
+    expr: |-
+      changes(process_start_time_seconds{job="linkerd-proxy", {{.Selector}}}[{{$.RestartWindow}}])
+      > {{$.Restarts}}
+    labels:
+      severity: {{$.Severity}}
+    annotations:
+      summary: "The proxy of pod {{"{{"}} $labels.namespace {{"}}"}}/{{"{{"}} $labels.pod {{"}}"}} restarted more than {{$.Restarts}} times in {{$.RestartWindow}}"
+{{- end}}
+{{- end}}
+`
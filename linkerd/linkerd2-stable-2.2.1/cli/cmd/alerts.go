@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/linkerd/linkerd2/cli/alerts"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+	"github.com/spf13/cobra"
+)
+
+type alertsOptions struct {
+	namespace     string
+	successRate   float64
+	latencyP99    time.Duration
+	restarts      uint
+	restartWindow time.Duration
+	forDuration   time.Duration
+	window        time.Duration
+	severity      string
+	templateFile  string
+}
+
+// alertsConfig is the data the alerts template is rendered with.
+type alertsConfig struct {
+	Resources     []alertsResource
+	SuccessRate   float64
+	LatencyP99Ms  int64
+	Restarts      uint
+	RestartWindow string
+	For           string
+	Window        string
+	Severity      string
+}
+
+// alertsResource holds the PromQL selecting the metrics of a resource, or of
+// all the resources of a type.
+type alertsResource struct {
+	// GroupName is the name of the group of the alerting rules of the resource
+	GroupName string
+	// Selector holds the label matchers of the metrics of the resource
+	Selector string
+	// By holds the labels identifying each resource in the alerts
+	By string
+	// Target describes the resource in the alert summaries
+	Target string
+}
+
+// alertsResourceTypes are the resource types whose pods alerts can be
+// rendered for.
+var alertsResourceTypes = map[string]bool{
+	k8s.DaemonSet:             true,
+	k8s.Deployment:            true,
+	k8s.Job:                   true,
+	k8s.Namespace:             true,
+	k8s.Pod:                   true,
+	k8s.ReplicationController: true,
+	k8s.StatefulSet:           true,
+}
+
+func newAlertsOptions() *alertsOptions {
+	return &alertsOptions{
+		namespace:     "default",
+		successRate:   0.95,
+		latencyP99:    500 * time.Millisecond,
+		restarts:      3,
+		restartWindow: 15 * time.Minute,
+		forDuration:   5 * time.Minute,
+		window:        time.Minute,
+		severity:      "warning",
+		templateFile:  "",
+	}
+}
+
+func newCmdAlerts() *cobra.Command {
+	options := newAlertsOptions()
+
+	cmd := &cobra.Command{
+		Use:   "alerts [flags] (RESOURCES)",
+		Short: "Output Prometheus alerting rules for the meshed resources",
+		Long: `Output Prometheus alerting rules for the meshed resources.
+
+  The RESOURCES argument specifies the resources to render alerting rules for,
+  each in its own group:
+  (TYPE [NAME] | TYPE/NAME)
+
+  Without a NAME, the rules alert for each resource of the TYPE in the
+  namespace, or in all the namespaces for the namespace TYPE.
+
+  The rules alert when the success rate of the inbound requests of a resource is
+  below --success-rate, when their p99 latency is above --latency-p99, and when
+  a proxy restarted more than --restarts times in --restart-window. Setting one
+  of these flags to zero disables its alert.
+
+  Valid resource types include:
+  * daemonsets
+  * deployments
+  * jobs
+  * namespaces
+  * pods
+  * replicationcontrollers
+  * statefulsets
+
+  The rules can be customized with a Go template, given with --template, which
+  is rendered with the same data as the default template.`,
+		Example: `  # Alerting rules for the web deployment in the emojivoto namespace.
+  linkerd alerts deploy/web -n emojivoto
+
+  # Alerting rules for each deployment of the emojivoto namespace, with stricter objectives.
+  linkerd alerts deploy -n emojivoto --success-rate 0.99 --latency-p99 250ms
+
+  # Alerting rules for each namespace, without latency alerts, loaded in the rule_files of Prometheus.
+  linkerd alerts ns --latency-p99 0 > linkerd_alerts.yml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renderAlerts(os.Stdout, args, options)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resources")
+	cmd.PersistentFlags().Float64Var(&options.successRate, "success-rate", options.successRate, "Alert when the success rate of a resource is below this value, between 0 and 1")
+	cmd.PersistentFlags().DurationVar(&options.latencyP99, "latency-p99", options.latencyP99, "Alert when the p99 latency of a resource is above this value")
+	cmd.PersistentFlags().UintVar(&options.restarts, "restarts", options.restarts, "Alert when a proxy restarted more than this many times in the restart window")
+	cmd.PersistentFlags().DurationVar(&options.restartWindow, "restart-window", options.restartWindow, "Window over which the restarts of the proxies are counted")
+	cmd.PersistentFlags().DurationVar(&options.forDuration, "for", options.forDuration, "How long the success rate or latency must be out of bounds before the alert fires")
+	cmd.PersistentFlags().DurationVar(&options.window, "window", options.window, "Window over which the success rate and latency are computed")
+	cmd.PersistentFlags().StringVar(&options.severity, "severity", options.severity, "Severity label of the alerts")
+	cmd.PersistentFlags().StringVar(&options.templateFile, "template", options.templateFile, "Path to a Go template to render the rules with instead of the default one")
+
+	return cmd
+}
+
+func (o *alertsOptions) validate() error {
+	if o.successRate < 0 || o.successRate >= 1 {
+		return fmt.Errorf("--success-rate must be between 0 and 1, was %v", o.successRate)
+	}
+	if o.latencyP99 < 0 {
+		return fmt.Errorf("--latency-p99 must not be negative, was %s", o.latencyP99)
+	}
+	if o.successRate == 0 && o.latencyP99 == 0 && o.restarts == 0 {
+		return errors.New("at least one of --success-rate, --latency-p99 and --restarts must be set")
+	}
+	for flag, d := range map[string]time.Duration{
+		"--restart-window": o.restartWindow,
+		"--for":            o.forDuration,
+		"--window":         o.window,
+	} {
+		if d <= 0 {
+			return fmt.Errorf("%s must be positive, was %s", flag, d)
+		}
+	}
+	return nil
+}
+
+func renderAlerts(w io.Writer, args []string, options *alertsOptions) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	resources, err := util.BuildResources(options.namespace, args)
+	if err != nil {
+		return err
+	}
+
+	config := alertsConfig{
+		Resources:     make([]alertsResource, 0, len(resources)),
+		SuccessRate:   options.successRate,
+		LatencyP99Ms:  int64(options.latencyP99 / time.Millisecond),
+		Restarts:      options.restarts,
+		RestartWindow: model.Duration(options.restartWindow).String(),
+		For:           model.Duration(options.forDuration).String(),
+		Window:        model.Duration(options.window).String(),
+		Severity:      options.severity,
+	}
+	for _, resource := range resources {
+		r, err := buildAlertsResource(resource)
+		if err != nil {
+			return err
+		}
+		config.Resources = append(config.Resources, r)
+	}
+
+	text := alerts.Template
+	if options.templateFile != "" {
+		b, err := ioutil.ReadFile(options.templateFile)
+		if err != nil {
+			return err
+		}
+		text = string(b)
+	}
+
+	tmpl, err := template.New("alerts").Parse(text)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, config)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// buildAlertsResource returns the PromQL of the metrics of a resource, or of
+// each resource of its type if it has no name.
+func buildAlertsResource(resource pb.Resource) (alertsResource, error) {
+	if !alertsResourceTypes[resource.Type] {
+		return alertsResource{}, fmt.Errorf("alerts are not supported for the %s resource type", resource.Type)
+	}
+
+	if resource.Type == k8s.Namespace {
+		if resource.Name == "" {
+			return alertsResource{
+				GroupName: "linkerd-namespaces",
+				Selector:  `namespace!=""`,
+				By:        "namespace",
+				Target:    "namespace {{ $labels.namespace }}",
+			}, nil
+		}
+		return alertsResource{
+			GroupName: "linkerd-namespace-" + resource.Name,
+			Selector:  fmt.Sprintf("namespace=%q", resource.Name),
+			By:        "namespace",
+			Target:    "namespace " + resource.Name,
+		}, nil
+	}
+
+	label := k8s.KindToL5DLabel(resource.Type)
+	selector := []string{fmt.Sprintf("namespace=%q", resource.Namespace)}
+	groupName := fmt.Sprintf("linkerd-%s-%s", resource.Type, resource.Namespace)
+	target := fmt.Sprintf("%s %s/{{ $labels.%s }}", resource.Type, resource.Namespace, label)
+	if resource.Name == "" {
+		selector = append(selector, fmt.Sprintf(`%s!=""`, label))
+	} else {
+		selector = append(selector, fmt.Sprintf("%s=%q", label, resource.Name))
+		groupName += "-" + resource.Name
+		target = fmt.Sprintf("%s %s/%s", resource.Type, resource.Namespace, resource.Name)
+	}
+
+	return alertsResource{
+		GroupName: groupName,
+		Selector:  strings.Join(selector, ", "),
+		By:        "namespace, " + label,
+		Target:    target,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenderAlerts(t *testing.T) {
+	t.Run("Renders the alerting rules of resources", func(t *testing.T) {
+		options := newAlertsOptions()
+		options.namespace = "emojivoto"
+
+		var buf bytes.Buffer
+		if err := renderAlerts(&buf, []string{"deploy/web", "sts"}, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompareFile(t, buf.String(), "alerts_output.golden")
+	})
+
+	t.Run("Renders the alerting rules with a custom template", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "alerts")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		templateFile := filepath.Join(dir, "alerts.tmpl")
+		tmpl := "{{range .Resources}}{{.GroupName}}: {{.Selector}} by {{.By}} < {{$.SuccessRate}} over {{$.Window}}\n{{end}}"
+		if err := ioutil.WriteFile(templateFile, []byte(tmpl), 0600); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		options := newAlertsOptions()
+		options.templateFile = templateFile
+		options.window = 10 * time.Minute
+
+		var buf bytes.Buffer
+		if err := renderAlerts(&buf, []string{"ns/emojivoto", "ns"}, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `linkerd-namespace-emojivoto: namespace="emojivoto" by namespace < 0.95 over 10m
+linkerd-namespaces: namespace!="" by namespace < 0.95 over 10m
+`
+		diffCompare(t, buf.String(), expected)
+	})
+
+	t.Run("Rejects invalid options and resources", func(t *testing.T) {
+		testCases := []struct {
+			args          []string
+			successRate   float64
+			latencyP99    time.Duration
+			restarts      uint
+			expectedError string
+		}{
+			{[]string{"svc/web"}, 0.95, time.Second, 3, "alerts are not supported for the service resource type"},
+			{[]string{"deploy/web"}, 95, time.Second, 3, "--success-rate must be between 0 and 1, was 95"},
+			{[]string{"deploy/web"}, 0, 0, 0, "at least one of --success-rate, --latency-p99 and --restarts must be set"},
+		}
+
+		for i, tc := range testCases {
+			options := newAlertsOptions()
+			options.successRate = tc.successRate
+			options.latencyP99 = tc.latencyP99
+			options.restarts = tc.restarts
+
+			var buf bytes.Buffer
+			err := renderAlerts(&buf, tc.args, options)
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expectedError, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,413 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	certKindTrustAnchor = "trust-anchor"
+	certKindIssuer      = "issuer"
+	certKindWebhook     = "webhook"
+	certKindProxy       = "proxy"
+
+	certStatusOK         = "ok"
+	certStatusExpiring   = "expiring"
+	certStatusExpired    = "expired"
+	certStatusUntrusted  = "untrusted"
+	certStatusMismatched = "mismatched"
+
+	// proxySecretSuffix ends the names of the secrets holding the proxy
+	// certificates, see TLSIdentity.ToSecretName.
+	proxySecretSuffix = "-tls-linkerd-io"
+)
+
+type certsOptions struct {
+	namespace     string
+	outputFormat  string
+	expiryWarning time.Duration
+}
+
+func newCertsOptions() *certsOptions {
+	return &certsOptions{
+		namespace:     "",
+		outputFormat:  "",
+		expiryWarning: 30 * 24 * time.Hour,
+	}
+}
+
+// validate performs all validation on the command-line options.
+// It returns the first error encountered, or `nil` if the options are valid.
+func (o *certsOptions) validate() error {
+	switch o.outputFormat {
+	case "table", "json", "":
+		return nil
+	}
+
+	return errors.New("--output currently only supports table and json")
+}
+
+func newCmdCerts() *cobra.Command {
+	options := newCertsOptions()
+
+	example := `  # list the certificates of the control plane and of all the meshed pods
+  linkerd certs
+
+  # list the certificates of the meshed pods in the emojivoto namespace
+  linkerd certs -n emojivoto
+
+  # flag the certificates expiring within a week
+  linkerd certs --expiry-warning 168h`
+
+	cmd := &cobra.Command{
+		Use:   "certs [flags]",
+		Short: "Inspect the TLS certificates of the mesh",
+		Long: `Inspect the TLS certificates of the mesh.
+
+This command lists the trust anchors of the mesh, the operator-provided issuer
+if there's one, the certificates of the webhooks of the control plane, and the
+certificates of the meshed pods, with their subject, issuer and expiry.
+
+The status of each certificate is one of:
+  * ok
+  * expiring: it expires within --expiry-warning
+  * expired
+  * untrusted: it isn't verified by the trust anchors of its namespace
+  * mismatched: it doesn't match the identity of the workload it's for`,
+		Example: example,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, clientAuth())
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			rows, err := collectCerts(clientset, controlPlaneNamespace, options, time.Now())
+			if err != nil {
+				return err
+			}
+
+			return renderCerts(rows, options, os.Stdout)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the meshed pods to list the certificates of (default: all namespaces)")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" and \"json\" are supported (default \"table\")")
+	cmd.PersistentFlags().DurationVar(&options.expiryWarning, "expiry-warning", options.expiryWarning, "Flag the certificates that expire within this duration")
+
+	return cmd
+}
+
+type rowCert struct {
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	Expires   time.Time `json:"expires"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// collectCerts returns a row for each certificate of the mesh installed in
+// controlPlaneNamespace.
+func collectCerts(client kubernetes.Interface, controlPlaneNamespace string, options *certsOptions, now time.Time) ([]rowCert, error) {
+	rows := []rowCert{}
+	newRow := func(namespace, kind, name string, cert *x509.Certificate) rowCert {
+		row := rowCert{
+			Namespace: namespace,
+			Kind:      kind,
+			Name:      name,
+			Subject:   certSubject(cert),
+			Issuer:    cert.Issuer.CommonName,
+			Expires:   cert.NotAfter,
+			Status:    certStatusOK,
+		}
+		if now.After(cert.NotAfter) {
+			row.Status = certStatusExpired
+		} else if cert.NotAfter.Sub(now) < options.expiryWarning {
+			row.Status = certStatusExpiring
+		}
+		return row
+	}
+
+	// the trust anchors are published to every meshed namespace, and these
+	// are the ones of the control plane
+	trustAnchors, err := client.CoreV1().ConfigMaps(controlPlaneNamespace).Get(k8s.TLSTrustAnchorConfigMapName, meta_v1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		certs, err := parseCertificates([]byte(trustAnchors.Data[k8s.TLSTrustAnchorFileName]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust anchors in %s: %s", k8s.TLSTrustAnchorConfigMapName, err)
+		}
+		for _, cert := range certs {
+			rows = append(rows, newRow(controlPlaneNamespace, certKindTrustAnchor, k8s.TLSTrustAnchorConfigMapName, cert))
+		}
+	}
+
+	issuerRows, err := collectIssuerCert(client, controlPlaneNamespace, newRow)
+	if err != nil {
+		return nil, err
+	}
+	rows = append(rows, issuerRows...)
+
+	for _, secretName := range []string{k8s.ProxyInjectorTLSSecret, k8s.SPValidatorTLSSecret} {
+		secret, err := client.CoreV1().Secrets(controlPlaneNamespace).Get(secretName, meta_v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		certs, err := parseCertificates(secret.Data[k8s.TLSCertFileName])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %s", secretName, err)
+		}
+		row := newRow(controlPlaneNamespace, certKindWebhook, secretName, certs[0])
+		if err := verifyCert(certs[0], secret.Data[k8s.TLSTrustAnchorFileName], ""); err != nil {
+			row.Status = certStatusUntrusted
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	proxyRows, err := collectProxyCerts(client, controlPlaneNamespace, options.namespace, newRow)
+	if err != nil {
+		return nil, err
+	}
+	rows = append(rows, proxyRows...)
+
+	return rows, nil
+}
+
+// collectIssuerCert returns a row for the operator-provided issuer mounted to
+// the CA, if there's one.
+func collectIssuerCert(client kubernetes.Interface, controlPlaneNamespace string, newRow func(string, string, string, *x509.Certificate) rowCert) ([]rowCert, error) {
+	deploy, err := client.AppsV1().Deployments(controlPlaneNamespace).Get("linkerd-ca", meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, volume := range deploy.Spec.Template.Spec.Volumes {
+		if volume.Name != k8s.IdentityIssuerVolumeName || volume.Secret == nil {
+			continue
+		}
+
+		secretName := volume.Secret.SecretName
+		secret, err := client.CoreV1().Secrets(controlPlaneNamespace).Get(secretName, meta_v1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		certs, err := parseCertificates(secret.Data[k8s.IssuerCertFileName])
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuer certificate in %s: %s", secretName, err)
+		}
+		row := newRow(controlPlaneNamespace, certKindIssuer, secretName, certs[0])
+		if trustAnchors := secret.Data[k8s.IssuerTrustAnchorsFileName]; len(trustAnchors) > 0 {
+			if err := verifyCert(certs[0], trustAnchors, ""); err != nil {
+				row.Status = certStatusUntrusted
+				row.Error = err.Error()
+			}
+		}
+		return []rowCert{row}, nil
+	}
+	return nil, nil
+}
+
+// collectProxyCerts returns a row for each certificate issued by the CA to the
+// meshed workloads of the given namespace, or of all namespaces if it's empty.
+func collectProxyCerts(client kubernetes.Interface, controlPlaneNamespace, namespace string, newRow func(string, string, string, *x509.Certificate) rowCert) ([]rowCert, error) {
+	secrets, err := client.CoreV1().Secrets(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	trustAnchors := map[string][]byte{}
+	rows := []rowCert{}
+	for _, secret := range secrets.Items {
+		if !strings.HasSuffix(secret.Name, proxySecretSuffix) {
+			continue
+		}
+		der, ok := secret.Data[k8s.TLSCertFileName]
+		if !ok {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			rows = append(rows, rowCert{
+				Namespace: secret.Namespace,
+				Kind:      certKindProxy,
+				Name:      secret.Name,
+				Status:    certStatusUntrusted,
+				Error:     err.Error(),
+			})
+			continue
+		}
+
+		bundle, ok := trustAnchors[secret.Namespace]
+		if !ok {
+			configMap, err := client.CoreV1().ConfigMaps(secret.Namespace).Get(k8s.TLSTrustAnchorConfigMapName, meta_v1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			if err == nil {
+				bundle = []byte(configMap.Data[k8s.TLSTrustAnchorFileName])
+			}
+			trustAnchors[secret.Namespace] = bundle
+		}
+
+		row := newRow(secret.Namespace, certKindProxy, secret.Name, cert)
+		if err := verifyCert(cert, bundle, proxyIdentity(secret.Name, secret.Namespace, controlPlaneNamespace)); err != nil {
+			row.Status = certStatusUntrusted
+			if _, mismatched := err.(x509.HostnameError); mismatched {
+				row.Status = certStatusMismatched
+			}
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, nil
+}
+
+// proxyIdentity returns the DNS name the certificate held by the given proxy
+// secret must be valid for.
+func proxyIdentity(secretName, namespace, controlPlaneNamespace string) string {
+	owner := strings.TrimSuffix(secretName, proxySecretSuffix)
+	i := strings.LastIndex(owner, "-")
+	if i < 0 {
+		return ""
+	}
+	return k8s.TLSIdentity{
+		Name:                owner[:i],
+		Kind:                owner[i+1:],
+		Namespace:           namespace,
+		ControllerNamespace: controlPlaneNamespace,
+	}.ToDNSName()
+}
+
+// verifyCert checks that cert is verified by the given PEM-encoded trust
+// anchors, and is valid for dnsName, if it's not empty.
+func verifyCert(cert *x509.Certificate, trustAnchorsPEM []byte, dnsName string) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(trustAnchorsPEM) {
+		return errors.New("no trust anchors found")
+	}
+	// the expiry is reported separately
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		DNSName:     dnsName,
+		CurrentTime: cert.NotBefore,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	return certs, nil
+}
+
+// certSubject returns the names a certificate is valid for, or its common
+// name if it has none, as for CA certificates.
+func certSubject(cert *x509.Certificate) string {
+	if len(cert.DNSNames) > 0 {
+		return strings.Join(cert.DNSNames, ",")
+	}
+	return cert.Subject.CommonName
+}
+
+func renderCerts(rows []rowCert, options *certsOptions, w io.Writer) error {
+	if options.outputFormat == "json" {
+		b, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stderr, "No certificates found.")
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{namespaceHeader, "KIND", "NAME", "SUBJECT", "ISSUER", "EXPIRES", "STATUS"}, "\t"))
+	for _, row := range rows {
+		expires := "-"
+		if !row.Expires.IsZero() {
+			expires = row.Expires.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Namespace, row.Kind, row.Name, valueOrDash(row.Subject), valueOrDash(row.Issuer), expires, row.Status)
+	}
+	tw.Flush()
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollectCerts(t *testing.T) {
+	ca, err := tls.NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	otherCA, err := tls.NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	bundle := func(namespace string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{Name: k8s.TLSTrustAnchorConfigMapName, Namespace: namespace},
+			Data:       map[string]string{k8s.TLSTrustAnchorFileName: ca.TrustAnchorPEM()},
+		}
+	}
+	proxySecret := func(issuer *tls.CA, owner, dnsName string) *v1.Secret {
+		cert, err := issuer.IssueEndEntityCertificate(dnsName)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return &v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{Name: owner + "-deployment-tls-linkerd-io", Namespace: "emojivoto"},
+			Data: map[string][]byte{
+				k8s.TLSCertFileName:       cert.Certificate,
+				k8s.TLSPrivateKeyFileName: cert.PrivateKey,
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		bundle("linkerd"),
+		bundle("emojivoto"),
+		proxySecret(ca, "web", "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"),
+		proxySecret(ca, "voting", "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"),
+		proxySecret(otherCA, "emoji", "emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"),
+		&v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "default-token", Namespace: "emojivoto"},
+		},
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	rows, err := collectCerts(client, "linkerd", newCertsOptions(), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []struct {
+		kind   string
+		name   string
+		status string
+	}{
+		{certKindTrustAnchor, k8s.TLSTrustAnchorConfigMapName, certStatusOK},
+		{certKindProxy, "emoji-deployment-tls-linkerd-io", certStatusUntrusted},
+		{certKindProxy, "voting-deployment-tls-linkerd-io", certStatusMismatched},
+		{certKindProxy, "web-deployment-tls-linkerd-io", certStatusOK},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d certificates, got %d: %+v", len(expected), len(rows), rows)
+	}
+	for i, exp := range expected {
+		if rows[i].Kind != exp.kind || rows[i].Name != exp.name || rows[i].Status != exp.status {
+			t.Fatalf("Expected certificate %d to be %s %s with status %s, got %+v", i, exp.kind, exp.name, exp.status, rows[i])
+		}
+	}
+
+	rows, err = collectCerts(client, "linkerd", newCertsOptions(), time.Now().Add(2*365*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if rows[0].Status != certStatusExpired {
+		t.Fatalf("Expected the trust anchor to be expired, got %s", rows[0].Status)
+	}
+}
@@ -10,26 +10,69 @@ import (
 
 	"github.com/briandowns/spinner"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 type checkOptions struct {
 	versionOverride string
 	preInstallOnly  bool
+	preUpgradeOnly  bool
+	toVersion       string
 	dataPlaneOnly   bool
 	wait            time.Duration
 	namespace       string
 	singleNamespace bool
+	severity        string
+}
+
+const (
+	severityError = "error"
+	severityWarn  = "warn"
+)
+
+// Exit codes of the check command, by the class of the first failed check, so
+// that CI pipelines can tell the failures apart.
+const (
+	checkExitFailure      = 2
+	checkExitKubeconfig   = 3
+	checkExitRBAC         = 4
+	checkExitControlPlane = 5
+	checkExitDataPlane    = 6
+	checkExitWarning      = 7
+)
+
+// checkExitCodes maps the categories of checks to the exit code of their
+// failures; the failures of the other categories exit with checkExitFailure.
+var checkExitCodes = map[healthcheck.CategoryID]int{
+	healthcheck.KubernetesAPIChecks:                    checkExitKubeconfig,
+	healthcheck.LinkerdPreInstallClusterChecks:         checkExitRBAC,
+	healthcheck.LinkerdPreInstallSingleNamespaceChecks: checkExitRBAC,
+	healthcheck.LinkerdPreInstallChecks:                checkExitRBAC,
+	healthcheck.LinkerdControlPlaneExistenceChecks:     checkExitControlPlane,
+	healthcheck.LinkerdAPIChecks:                       checkExitControlPlane,
+	healthcheck.LinkerdDataPlaneChecks:                 checkExitDataPlane,
+}
+
+// checkSummary records the outcome of the checks.
+type checkSummary struct {
+	// failedCategory is the category of the first failed check, if any
+	failedCategory healthcheck.CategoryID
+	failed         bool
+	warned         bool
 }
 
 func newCheckOptions() *checkOptions {
 	return &checkOptions{
 		versionOverride: "",
 		preInstallOnly:  false,
+		preUpgradeOnly:  false,
+		toVersion:       version.Version,
 		dataPlaneOnly:   false,
 		wait:            300 * time.Second,
 		namespace:       "",
 		singleNamespace: false,
+		severity:        severityError,
 	}
 }
 
@@ -44,15 +87,28 @@ func newCmdCheck() *cobra.Command {
 The check command will perform a series of checks to validate that the linkerd
 CLI and control plane are configured correctly. If the command encounters a
 failure it will print additional information about the failure and exit with a
-non-zero exit code.`,
+non-zero exit code, depending on the kind of the first failed check:
+
+  2  another check failed
+  3  the Kubernetes API could not be reached with the kubeconfig
+  4  the permissions to install the control plane are missing (--pre)
+  5  the control plane is missing or not serving its API
+  6  the data plane is degraded (--proxy)
+  7  only checks reporting warnings failed, with --severity-threshold warn`,
 		Example: `  # Check that the Linkerd control plane is up and running
   linkerd check
 
   # Check that the Linkerd control plane can be installed in the "test" namespace
   linkerd check --pre --linkerd-namespace test
 
+  # Check that the Linkerd control plane can be upgraded to the version of the CLI
+  linkerd check --pre-upgrade
+
   # Check that the Linkerd data plane proxies in the "app" namespace are up and running
-  linkerd check --proxy --namespace app`,
+  linkerd check --proxy --namespace app
+
+  # Fail on warnings too, such as a control plane that is not up-to-date
+  linkerd check --severity-threshold warn`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return configureAndRunChecks(stdout, options)
@@ -62,10 +118,13 @@ non-zero exit code.`,
 	cmd.Args = cobra.NoArgs
 	cmd.PersistentFlags().StringVar(&options.versionOverride, "expected-version", options.versionOverride, "Overrides the version used when checking if Linkerd is running the latest version (mostly for testing)")
 	cmd.PersistentFlags().BoolVar(&options.preInstallOnly, "pre", options.preInstallOnly, "Only run pre-installation checks, to determine if the control plane can be installed")
+	cmd.PersistentFlags().BoolVar(&options.preUpgradeOnly, "pre-upgrade", options.preUpgradeOnly, "Only run pre-upgrade checks, to determine if the control plane can be upgraded")
+	cmd.PersistentFlags().StringVar(&options.toVersion, "to-version", options.toVersion, "Version the control plane is upgraded to when running pre-upgrade checks (--pre-upgrade)")
 	cmd.PersistentFlags().BoolVar(&options.dataPlaneOnly, "proxy", options.dataPlaneOnly, "Only run data-plane checks, to determine if the data plane is healthy")
 	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Maximum allowed time for all tests to pass")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace to use for --proxy checks (default: all namespaces)")
 	cmd.PersistentFlags().BoolVar(&options.singleNamespace, "single-namespace", options.singleNamespace, "When running pre-installation checks (--pre), only check the permissions required to operate the control plane in a single namespace")
+	cmd.PersistentFlags().StringVar(&options.severity, "severity-threshold", options.severity, "Lowest severity of the failed checks that makes the command fail, one of: warn, error")
 
 	return cmd
 }
@@ -77,11 +136,18 @@ func configureAndRunChecks(w io.Writer, options *checkOptions) error {
 	}
 	checks := []healthcheck.CategoryID{
 		healthcheck.KubernetesAPIChecks,
-		healthcheck.KubernetesVersionChecks,
-		healthcheck.LinkerdVersionChecks,
 	}
+	// the pre-upgrade checks validate the Kubernetes version against the target
+	// version themselves
+	if !options.preUpgradeOnly {
+		checks = append(checks, healthcheck.KubernetesVersionChecks)
+	}
+	checks = append(checks, healthcheck.LinkerdVersionChecks)
 
-	if options.preInstallOnly {
+	if options.preUpgradeOnly {
+		checks = append(checks, healthcheck.LinkerdControlPlaneExistenceChecks)
+		checks = append(checks, healthcheck.LinkerdPreUpgradeChecks)
+	} else if options.preInstallOnly {
 		if options.singleNamespace {
 			checks = append(checks, healthcheck.LinkerdPreInstallSingleNamespaceChecks)
 		} else {
@@ -91,6 +157,7 @@ func configureAndRunChecks(w io.Writer, options *checkOptions) error {
 	} else {
 		checks = append(checks, healthcheck.LinkerdControlPlaneExistenceChecks)
 		checks = append(checks, healthcheck.LinkerdAPIChecks)
+		checks = append(checks, healthcheck.LinkerdIdentityChecks)
 
 		if !options.singleNamespace {
 			checks = append(checks, healthcheck.LinkerdServiceProfileChecks)
@@ -108,19 +175,25 @@ func configureAndRunChecks(w io.Writer, options *checkOptions) error {
 		DataPlaneNamespace:    options.namespace,
 		KubeConfig:            kubeconfigPath,
 		KubeContext:           kubeContext,
+		ClientAuth:            clientAuth(),
 		APIAddr:               apiAddr,
 		VersionOverride:       options.versionOverride,
+		UpgradeVersion:        options.toVersion,
 		RetryDeadline:         time.Now().Add(options.wait),
 	})
 
-	success := runChecks(w, hc)
+	summary := runChecks(w, hc)
 
 	// this empty line separates final results from the checks list in the output
 	fmt.Fprintln(w, "")
 
-	if !success {
-		fmt.Fprintf(w, "Status check results are %s\n", failStatus)
-		os.Exit(2)
+	if code := summary.exitCode(options.severity); code != 0 {
+		status := failStatus
+		if !summary.failed {
+			status = warnStatus
+		}
+		fmt.Fprintf(w, "Status check results are %s\n", status)
+		os.Exit(code)
 	}
 
 	fmt.Fprintf(w, "Status check results are %s\n", okStatus)
@@ -132,10 +205,32 @@ func (o *checkOptions) validate() error {
 	if o.preInstallOnly && o.dataPlaneOnly {
 		return errors.New("--pre and --proxy flags are mutually exclusive")
 	}
+	if o.preUpgradeOnly && (o.preInstallOnly || o.dataPlaneOnly) {
+		return errors.New("--pre-upgrade flag is mutually exclusive with --pre and --proxy")
+	}
+	if o.severity != severityError && o.severity != severityWarn {
+		return fmt.Errorf("--severity-threshold must be one of: %s, %s", severityWarn, severityError)
+	}
 	return nil
 }
 
-func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
+// exitCode returns the exit code of the check command for the summary, where
+// the failed warnings only make it fail with the warn severity threshold.
+func (s checkSummary) exitCode(severity string) int {
+	if s.failed {
+		if code, ok := checkExitCodes[s.failedCategory]; ok {
+			return code
+		}
+		return checkExitFailure
+	}
+	if s.warned && severity == severityWarn {
+		return checkExitWarning
+	}
+	return 0
+}
+
+func runChecks(w io.Writer, hc *healthcheck.HealthChecker) checkSummary {
+	var summary checkSummary
 	var lastCategory healthcheck.CategoryID
 	spin := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
 	spin.Writer = w
@@ -164,6 +259,10 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 			status = failStatus
 			if result.Warning {
 				status = warnStatus
+				summary.warned = true
+			} else if !summary.failed {
+				summary.failed = true
+				summary.failedCategory = result.Category
 			}
 		}
 
@@ -176,5 +275,6 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 		}
 	}
 
-	return hc.RunChecks(prettyPrintResults)
+	hc.RunChecks(prettyPrintResults)
+	return summary
 }
@@ -38,3 +38,88 @@ func TestCheckStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckExitCode(t *testing.T) {
+	t.Run("Exits with the code of the first failed check", func(t *testing.T) {
+		hc := healthcheck.NewHealthChecker(
+			[]healthcheck.CategoryID{},
+			&healthcheck.Options{},
+		)
+		hc.Add(healthcheck.LinkerdAPIChecks, "check1", "", func(context.Context) error {
+			return nil
+		})
+		hc.Add(healthcheck.LinkerdDataPlaneChecks, "check2", "", func(context.Context) error {
+			return fmt.Errorf("data plane failure")
+		})
+		hc.Add(healthcheck.LinkerdControlPlaneVersionChecks, "check3", "", func(context.Context) error {
+			return fmt.Errorf("version failure")
+		})
+
+		summary := runChecks(ioutil.Discard, hc)
+		if code := summary.exitCode(severityError); code != checkExitDataPlane {
+			t.Fatalf("Expected exit code %d, got %d", checkExitDataPlane, code)
+		}
+	})
+
+	t.Run("Exits with the code of the severity threshold", func(t *testing.T) {
+		testCases := []struct {
+			summary  checkSummary
+			severity string
+			expected int
+		}{
+			{checkSummary{}, severityWarn, 0},
+			{checkSummary{warned: true}, severityError, 0},
+			{checkSummary{warned: true}, severityWarn, checkExitWarning},
+			{checkSummary{failed: true, failedCategory: healthcheck.KubernetesAPIChecks}, severityError, checkExitKubeconfig},
+			{checkSummary{failed: true, failedCategory: healthcheck.LinkerdPreInstallChecks, warned: true}, severityWarn, checkExitRBAC},
+			{checkSummary{failed: true, failedCategory: healthcheck.LinkerdControlPlaneExistenceChecks}, severityError, checkExitControlPlane},
+			{checkSummary{failed: true, failedCategory: healthcheck.LinkerdVersionChecks}, severityError, checkExitFailure},
+		}
+
+		for i, tc := range testCases {
+			if code := tc.summary.exitCode(tc.severity); code != tc.expected {
+				t.Fatalf("test case %d: expected exit code %d, got %d", i, tc.expected, code)
+			}
+		}
+	})
+
+	t.Run("Rejects an invalid severity threshold", func(t *testing.T) {
+		options := newCheckOptions()
+		options.severity = "info"
+
+		expectedError := "--severity-threshold must be one of: warn, error"
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestCheckOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		preInstallOnly bool
+		preUpgradeOnly bool
+		dataPlaneOnly  bool
+		expectedError  string
+	}{
+		{false, true, false, ""},
+		{true, false, true, "--pre and --proxy flags are mutually exclusive"},
+		{true, true, false, "--pre-upgrade flag is mutually exclusive with --pre and --proxy"},
+		{false, true, true, "--pre-upgrade flag is mutually exclusive with --pre and --proxy"},
+	}
+
+	for i, tc := range testCases {
+		options := newCheckOptions()
+		options.preInstallOnly = tc.preInstallOnly
+		options.preUpgradeOnly = tc.preUpgradeOnly
+		options.dataPlaneOnly = tc.dataPlaneOnly
+
+		err := options.validate()
+		if tc.expectedError == "" && err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if tc.expectedError != "" && (err == nil || err.Error() != tc.expectedError) {
+			t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expectedError, err)
+		}
+	}
+}
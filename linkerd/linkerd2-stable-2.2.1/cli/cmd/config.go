@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// proxyConfigTimeout is how long inject waits for the persisted configuration.
+const proxyConfigTimeout = 5 * time.Second
+
+// proxyConfig is the configuration of the injected proxies chosen at install
+// time. It's persisted in the linkerd-config ConfigMap of the control plane
+// namespace, and used by inject as the defaults of its flags, so that they
+// don't have to be repeated.
+type proxyConfig struct {
+	LinkerdVersion          string   `json:"linkerdVersion"`
+	ProxyImage              string   `json:"proxyImage"`
+	InitImage               string   `json:"initImage"`
+	DockerRegistry          string   `json:"dockerRegistry"`
+	ImagePullPolicy         string   `json:"imagePullPolicy"`
+	InboundPort             uint     `json:"inboundPort"`
+	OutboundPort            uint     `json:"outboundPort"`
+	IgnoreInboundPorts      []string `json:"ignoreInboundPorts"`
+	IgnoreOutboundPorts     []string `json:"ignoreOutboundPorts"`
+	IgnoreInboundSubnets    []string `json:"ignoreInboundSubnets"`
+	IgnoreOutboundSubnets   []string `json:"ignoreOutboundSubnets"`
+	IgnoreMarks             []string `json:"ignoreMarks"`
+	IptablesMode            string   `json:"iptablesMode"`
+	ProxyUID                int64    `json:"proxyUID"`
+	ProxyLogLevel           string   `json:"proxyLogLevel"`
+	ProxyAPIPort            uint     `json:"proxyAPIPort"`
+	ProxyControlPort        uint     `json:"proxyControlPort"`
+	ProxyMetricsPort        uint     `json:"proxyMetricsPort"`
+	ProxyCPURequest         string   `json:"proxyCPURequest"`
+	ProxyMemoryRequest      string   `json:"proxyMemoryRequest"`
+	TLS                     string   `json:"tls"`
+	DisableExternalProfiles bool     `json:"disableExternalProfiles"`
+	ProxyAwait              bool     `json:"proxyAwait"`
+	ProxyJobShutdown        bool     `json:"proxyJobShutdown"`
+	NoInitContainer         bool     `json:"noInitContainer"`
+}
+
+func newProxyConfig(options *proxyConfigOptions) *proxyConfig {
+	config := &proxyConfig{
+		LinkerdVersion:          options.linkerdVersion,
+		ProxyImage:              options.proxyImage,
+		InitImage:               options.initImage,
+		DockerRegistry:          options.dockerRegistry,
+		ImagePullPolicy:         options.imagePullPolicy,
+		InboundPort:             options.inboundPort,
+		OutboundPort:            options.outboundPort,
+		IgnoreInboundPorts:      formatPortRanges(skipPorts(options.ignoreInboundPorts)),
+		IgnoreOutboundPorts:     formatPortRanges(skipPorts(options.ignoreOutboundPorts)),
+		IgnoreInboundSubnets:    options.ignoreInboundSubnets,
+		IgnoreOutboundSubnets:   options.ignoreOutboundSubnets,
+		IgnoreMarks:             options.ignoreMarks,
+		IptablesMode:            options.iptablesMode,
+		ProxyUID:                options.proxyUID,
+		ProxyLogLevel:           options.proxyLogLevel,
+		ProxyAPIPort:            options.proxyAPIPort,
+		ProxyControlPort:        options.proxyControlPort,
+		ProxyMetricsPort:        options.proxyMetricsPort,
+		ProxyCPURequest:         options.proxyCPURequest,
+		ProxyMemoryRequest:      options.proxyMemoryRequest,
+		TLS:                     options.tls,
+		DisableExternalProfiles: options.disableExternalProfiles,
+		ProxyAwait:              options.proxyAwait,
+		ProxyJobShutdown:        options.proxyJobShutdown,
+		NoInitContainer:         options.noInitContainer,
+	}
+	if config.IgnoreInboundSubnets == nil {
+		config.IgnoreInboundSubnets = []string{}
+	}
+	if config.IgnoreOutboundSubnets == nil {
+		config.IgnoreOutboundSubnets = []string{}
+	}
+	if config.IgnoreMarks == nil {
+		config.IgnoreMarks = []string{}
+	}
+	return config
+}
+
+// applyTo sets the options whose flags weren't set on the command line to the
+// persisted configuration.
+func (c *proxyConfig) applyTo(options *proxyConfigOptions, flags *pflag.FlagSet) {
+	apply := func(flag string, set func()) {
+		if !flags.Changed(flag) {
+			set()
+		}
+	}
+
+	apply("linkerd-version", func() { options.linkerdVersion = c.LinkerdVersion })
+	apply("proxy-image", func() { options.proxyImage = c.ProxyImage })
+	apply("init-image", func() { options.initImage = c.InitImage })
+	apply("registry", func() { options.dockerRegistry = c.DockerRegistry })
+	apply("image-pull-policy", func() { options.imagePullPolicy = c.ImagePullPolicy })
+	apply("inbound-port", func() { options.inboundPort = c.InboundPort })
+	apply("outbound-port", func() { options.outboundPort = c.OutboundPort })
+	apply("skip-inbound-ports", func() { options.ignoreInboundPorts = c.IgnoreInboundPorts })
+	apply("skip-outbound-ports", func() { options.ignoreOutboundPorts = c.IgnoreOutboundPorts })
+	apply("skip-inbound-subnets", func() { options.ignoreInboundSubnets = c.IgnoreInboundSubnets })
+	apply("skip-outbound-subnets", func() { options.ignoreOutboundSubnets = c.IgnoreOutboundSubnets })
+	apply("skip-marks", func() { options.ignoreMarks = c.IgnoreMarks })
+	apply("iptables-mode", func() {
+		// the configurations persisted by older versions have no mode
+		if c.IptablesMode != "" {
+			options.iptablesMode = c.IptablesMode
+		}
+	})
+	apply("proxy-uid", func() { options.proxyUID = c.ProxyUID })
+	apply("proxy-log-level", func() { options.proxyLogLevel = c.ProxyLogLevel })
+	apply("api-port", func() { options.proxyAPIPort = c.ProxyAPIPort })
+	apply("control-port", func() { options.proxyControlPort = c.ProxyControlPort })
+	apply("metrics-port", func() { options.proxyMetricsPort = c.ProxyMetricsPort })
+	apply("proxy-cpu", func() { options.proxyCPURequest = c.ProxyCPURequest })
+	apply("proxy-memory", func() { options.proxyMemoryRequest = c.ProxyMemoryRequest })
+	apply("tls", func() { options.tls = c.TLS })
+	apply("disable-external-profiles", func() { options.disableExternalProfiles = c.DisableExternalProfiles })
+	apply("proxy-await", func() { options.proxyAwait = c.ProxyAwait })
+	apply("proxy-job-shutdown", func() { options.proxyJobShutdown = c.ProxyJobShutdown })
+	apply("linkerd-cni-enabled", func() { options.noInitContainer = c.NoInitContainer })
+}
+
+// validate checks the persisted configuration the same way as the flags it
+// replaces.
+func (c *proxyConfig) validate() error {
+	options := newProxyConfigOptions()
+	c.applyTo(options, pflag.NewFlagSet("config", pflag.ContinueOnError))
+	return options.validate()
+}
+
+// fetchProxyConfig returns the persisted configuration of the control plane
+// of the namespace, along with the ConfigMap it was read from.
+func fetchProxyConfig(client kubernetes.Interface, namespace string) (*proxyConfig, *v1.ConfigMap, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(k8s.ConfigConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, ok := cm.Data[k8s.ConfigProxyKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("ConfigMap %s/%s has no %q key", namespace, k8s.ConfigConfigMapName, k8s.ConfigProxyKey)
+	}
+
+	var config proxyConfig
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration in ConfigMap %s/%s: %s", namespace, k8s.ConfigConfigMapName, err)
+	}
+	return &config, cm, nil
+}
+
+// editProxyConfig updates the persisted configuration of the control plane of
+// the namespace with the result of edit, which is passed the indented JSON of
+// the configuration. It returns false if edit didn't change the
+// configuration.
+func editProxyConfig(client kubernetes.Interface, namespace string, edit func([]byte) ([]byte, error)) (bool, error) {
+	config, cm, err := fetchProxyConfig(client, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	original, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	edited, err := edit(original)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(bytes.TrimSpace(original), bytes.TrimSpace(edited)) {
+		return false, nil
+	}
+
+	if err := checkProxyConfigKeys(edited); err != nil {
+		return false, fmt.Errorf("invalid configuration: %s", err)
+	}
+	var updated proxyConfig
+	decoder := json.NewDecoder(bytes.NewReader(edited))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&updated); err != nil {
+		return false, fmt.Errorf("invalid configuration: %s", err)
+	}
+	if err := updated.validate(); err != nil {
+		return false, fmt.Errorf("invalid configuration: %s", err)
+	}
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		return false, err
+	}
+	cm.Data[k8s.ConfigProxyKey] = string(data)
+	if _, err := client.CoreV1().ConfigMaps(namespace).Update(cm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkProxyConfigKeys returns an error if a key of the configuration isn't
+// the exact name of a field of proxyConfig. encoding/json matches the keys
+// case-insensitively, which would silently accept misspelled keys.
+func checkProxyConfigKeys(data []byte) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	fields := map[string]struct{}{}
+	configType := reflect.TypeOf(proxyConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		name := strings.Split(configType.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = struct{}{}
+	}
+	for key := range keys {
+		if _, ok := fields[key]; !ok {
+			return fmt.Errorf("json: unknown field %q", key)
+		}
+	}
+	return nil
+}
+
+// editInEditor opens the content in the editor of the KUBE_EDITOR or EDITOR
+// environment variables, or vi, and returns the edited content.
+func editInEditor(content []byte) ([]byte, error) {
+	file, err := ioutil.TempFile("", "linkerd-config-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	editor := os.Getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), file.Name())
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %q failed: %s", editor, err)
+	}
+
+	return ioutil.ReadFile(file.Name())
+}
+
+// configClient returns a client of the Kubernetes API whose requests time out
+// after timeout, or never if it's 0.
+func configClient(timeout time.Duration) (kubernetes.Interface, error) {
+	kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, clientAuth())
+	if err != nil {
+		return nil, err
+	}
+	kubeAPI.Config.Timeout = timeout
+	return kubernetes.NewForConfig(kubeAPI.Config)
+}
+
+// loadProxyConfig sets the options whose flags weren't set on the command line
+// to the persisted configuration of the control plane. The defaults are kept
+// if the configuration can't be read, e.g. when the control plane isn't
+// installed.
+func loadProxyConfig(flags *pflag.FlagSet, options *proxyConfigOptions) {
+	client, err := configClient(proxyConfigTimeout)
+	if err != nil {
+		log.Debugf("Using the default proxy configuration: %s", err)
+		return
+	}
+
+	config, _, err := fetchProxyConfig(client, controlPlaneNamespace)
+	if err != nil {
+		log.Debugf("Using the default proxy configuration: %s", err)
+		return
+	}
+	config.applyTo(options, flags)
+}
+
+func newCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View or edit the configuration of the control plane",
+		Long: `View or edit the configuration of the control plane.
+
+The configuration of the proxies chosen when the control plane was installed
+is stored in the linkerd-config ConfigMap of the control plane namespace.
+"linkerd inject" uses it as the defaults of its flags, and the proxy injector
+applies its proxy log level, skipped ports and proxy-await and
+proxy-job-shutdown defaults to the pods it injects.`,
+	}
+
+	cmd.AddCommand(newCmdConfigView())
+	cmd.AddCommand(newCmdConfigEdit())
+
+	return cmd
+}
+
+func newCmdConfigView() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Print the configuration of the control plane",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := configClient(0)
+			if err != nil {
+				return err
+			}
+			return viewProxyConfig(client, controlPlaneNamespace, os.Stdout)
+		},
+	}
+}
+
+func viewProxyConfig(client kubernetes.Interface, namespace string, w io.Writer) error {
+	config, _, err := fetchProxyConfig(client, namespace)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func newCmdConfigEdit() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the configuration of the control plane",
+		Long: `Edit the configuration of the control plane.
+
+The configuration is opened in the editor of the KUBE_EDITOR or EDITOR
+environment variables, or vi, and saved back to the cluster once the editor
+exits. The proxies that are already injected aren't changed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := configClient(0)
+			if err != nil {
+				return err
+			}
+
+			changed, err := editProxyConfig(client, controlPlaneNamespace, editInEditor)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				fmt.Println("Edit cancelled, no changes made.")
+				return nil
+			}
+			fmt.Printf("ConfigMap %s/%s edited\n", controlPlaneNamespace, k8s.ConfigConfigMapName)
+			return nil
+		},
+	}
+}
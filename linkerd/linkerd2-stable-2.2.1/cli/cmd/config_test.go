@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newFakeConfigClient(t *testing.T, config *proxyConfig) *fake.Clientset {
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: k8s.ConfigConfigMapName, Namespace: "linkerd"},
+		Data:       map[string]string{k8s.ConfigProxyKey: string(data)},
+	})
+}
+
+func TestViewProxyConfig(t *testing.T) {
+	options := newProxyConfigOptions()
+	options.proxyLogLevel = "debug"
+	options.tls = optionalTLS
+	expected := newProxyConfig(options)
+	client := newFakeConfigClient(t, expected)
+
+	var buf bytes.Buffer
+	if err := viewProxyConfig(client, "linkerd", &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var config proxyConfig
+	if err := json.Unmarshal(buf.Bytes(), &config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(&config, expected) {
+		t.Fatalf("Expected configuration %+v, got %+v", expected, config)
+	}
+
+	if err := viewProxyConfig(client, "other", &buf); err == nil {
+		t.Fatalf("Expected an error when the ConfigMap doesn't exist")
+	}
+}
+
+func TestProxyConfigApplyTo(t *testing.T) {
+	installed := newProxyConfigOptions()
+	installed.proxyUID = 1234
+	installed.proxyLogLevel = "debug"
+	installed.ignoreInboundPorts = []string{"22", "mysql"}
+	installed.ignoreOutboundSubnets = []string{"10.0.0.0/8"}
+	config := newProxyConfig(installed)
+
+	options := newProxyConfigOptions()
+	cmd := &cobra.Command{}
+	addProxyConfigFlags(cmd, options)
+	if err := cmd.PersistentFlags().Set("proxy-log-level", "info"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config.applyTo(options, cmd.PersistentFlags())
+
+	if options.proxyUID != 1234 {
+		t.Fatalf("Expected the proxy UID of the configuration, got %d", options.proxyUID)
+	}
+	if !reflect.DeepEqual(options.ignoreInboundPorts, []string{"22", "3306"}) {
+		t.Fatalf("Expected the inbound ports of the configuration, got %v", options.ignoreInboundPorts)
+	}
+	if !reflect.DeepEqual(options.ignoreOutboundSubnets, []string{"10.0.0.0/8"}) {
+		t.Fatalf("Expected the outbound subnets of the configuration, got %v", options.ignoreOutboundSubnets)
+	}
+	if options.proxyLogLevel != "info" {
+		t.Fatalf("Expected the log level of the flag, got %s", options.proxyLogLevel)
+	}
+}
+
+func TestEditProxyConfig(t *testing.T) {
+	testCases := []struct {
+		edit    func([]byte) ([]byte, error)
+		changed bool
+		err     string
+		uid     int64
+	}{
+		{
+			edit:    func(b []byte) ([]byte, error) { return b, nil },
+			changed: false,
+			uid:     2102,
+		},
+		{
+			edit: func(b []byte) ([]byte, error) {
+				return bytes.Replace(b, []byte(`"proxyUID": 2102`), []byte(`"proxyUID": 1234`), 1), nil
+			},
+			changed: true,
+			uid:     1234,
+		},
+		{
+			edit: func(b []byte) ([]byte, error) {
+				return bytes.Replace(b, []byte(`"proxyUID"`), []byte(`"proxyUid"`), 1), nil
+			},
+			err: "invalid configuration: json: unknown field \"proxyUid\"",
+			uid: 2102,
+		},
+		{
+			edit: func(b []byte) ([]byte, error) {
+				return bytes.Replace(b, []byte(`"imagePullPolicy": "IfNotPresent"`), []byte(`"imagePullPolicy": "Sometimes"`), 1), nil
+			},
+			err: "invalid configuration: --image-pull-policy must be one of: Always, IfNotPresent, Never",
+			uid: 2102,
+		},
+		{
+			edit: func(b []byte) ([]byte, error) {
+				return bytes.Replace(b, []byte(`"ignoreOutboundSubnets": []`), []byte(`"ignoreOutboundSubnets": ["10.0.0.1"]`), 1), nil
+			},
+			err: "invalid configuration: Invalid CIDR '10.0.0.1' for --skip-outbound-subnets flag",
+			uid: 2102,
+		},
+		{
+			edit: func(b []byte) ([]byte, error) { return nil, errors.New("editor failed") },
+			err:  "editor failed",
+			uid:  2102,
+		},
+	}
+
+	for i, tc := range testCases {
+		client := newFakeConfigClient(t, newProxyConfig(newProxyConfigOptions()))
+
+		changed, err := editProxyConfig(client, "linkerd", tc.edit)
+		if tc.err != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("test case %d: expected error %q, got %v", i, tc.err, err)
+			}
+		} else if err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if changed != tc.changed {
+			t.Fatalf("test case %d: expected changed to be %t, got %t", i, tc.changed, changed)
+		}
+
+		config, _, err := fetchProxyConfig(client, "linkerd")
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if config.ProxyUID != tc.uid {
+			t.Fatalf("test case %d: expected proxy UID %d, got %d", i, tc.uid, config.ProxyUID)
+		}
+	}
+}
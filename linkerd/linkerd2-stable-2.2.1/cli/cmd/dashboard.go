@@ -1,14 +1,21 @@
 package cmd
 
 import (
+	"crypto/rand"
 	"fmt"
 	"os"
 	"os/signal"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/webtoken"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // These constants are used by the `show` flag.
@@ -22,24 +29,42 @@ const (
 	// showURL displays dashboard URLs without opening a browser.
 	showURL = "url"
 
-	// webDeployment is the name of the web deployment in cli/install/template.go
-	webDeployment = "linkerd-web"
+	// webService is the name of the web service in chart/templates/base.yaml
+	webService = "linkerd-web"
 
-	// webPort is the http port from the web pod spec in cli/install/template.go
+	// webPort is the http port of the web service in chart/templates/base.yaml
 	webPort = 8084
+
+	// webExternalPort is the port of the web service requiring a token, in
+	// chart/templates/base.yaml
+	webExternalPort = 8089
+
+	// webExternalPortName is the name of webExternalPort
+	webExternalPortName = "external-http"
+
+	// webTokenKeySize is the size in bytes of the key signing the tokens
+	webTokenKeySize = 32
+
+	// externalDashboardTimeout is the timeout of the Kubernetes API requests
+	// of `linkerd dashboard --external`
+	externalDashboardTimeout = 30 * time.Second
 )
 
 type dashboardOptions struct {
-	port int
-	show string
-	wait time.Duration
+	port     int
+	show     string
+	wait     time.Duration
+	external bool
+	tokenTTL time.Duration
 }
 
 func newDashboardOptions() *dashboardOptions {
 	return &dashboardOptions{
-		port: 0,
-		show: showLinkerd,
-		wait: 300 * time.Second,
+		port:     0,
+		show:     showLinkerd,
+		wait:     300 * time.Second,
+		external: false,
+		tokenTTL: time.Hour,
 	}
 }
 
@@ -49,6 +74,18 @@ func newCmdDashboard() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dashboard [flags]",
 		Short: "Open the Linkerd dashboard in a web browser",
+		Long: `Open the Linkerd dashboard in a web browser.
+
+  By default, the dashboard is port-forwarded to a local port. With --external,
+  the dashboard is opened through the external-http port of the linkerd-web
+  service instead, which must be exposed by an Ingress terminating TLS. Its
+  URLs carry a token, valid for --token-ttl, without which the external-http
+  port refuses the requests.`,
+		Example: `  # Open the dashboard through a local port-forward.
+  linkerd dashboard
+
+  # Print the URLs of the dashboard exposed by an HTTPS Ingress, valid for 8 hours.
+  linkerd dashboard --external --token-ttl 8h --show url`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if options.port < 0 {
 				return fmt.Errorf("port must be greater than or equal to zero, was %d", options.port)
@@ -59,72 +96,26 @@ func newCmdDashboard() *cobra.Command {
 					options.show, showLinkerd, showGrafana, showURL)
 			}
 
-			// ensure we can connect to the public API before starting the proxy
-			validatedPublicAPIClient(time.Now().Add(options.wait), true)
-
-			wait := make(chan struct{}, 1)
-			signals := make(chan os.Signal, 1)
-			signal.Notify(signals, os.Interrupt)
-			defer signal.Stop(signals)
-
-			portforward, err := k8s.NewPortForward(
-				kubeconfigPath,
-				kubeContext,
-				controlPlaneNamespace,
-				webDeployment,
-				options.port,
-				webPort,
-				verbose,
-			)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to initialize port-forward: %s\n", err)
-				os.Exit(1)
+			if options.tokenTTL <= 0 {
+				return fmt.Errorf("token-ttl must be greater than zero, was %s", options.tokenTTL)
 			}
 
-			go func() {
-				err := portforward.Run()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error running port-forward: %s", err)
-					os.Exit(1)
-				}
-				close(wait)
-			}()
-
-			go func() {
-				<-signals
-				portforward.Stop()
-			}()
-
-			<-portforward.Ready()
-
-			webURL := portforward.URLFor("")
-			grafanaURL := portforward.URLFor("/grafana")
-
-			fmt.Printf("Linkerd dashboard available at:\n%s\n", webURL)
-			fmt.Printf("Grafana dashboard available at:\n%s\n", grafanaURL)
+			if options.external {
+				return runExternalDashboard(options)
+			}
 
-			switch options.show {
-			case showLinkerd:
-				fmt.Println("Opening Linkerd dashboard in the default browser")
+			// ensure we can connect to the public API before starting the proxy
+			validatedPublicAPIClient(time.Now().Add(options.wait), true)
 
-				err = browser.OpenURL(webURL)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, "Failed to open Linkerd dashboard automatically")
-					fmt.Fprintf(os.Stderr, "Visit %s in your browser to view the dashboard\n", webURL)
-				}
-			case showGrafana:
-				fmt.Println("Opening Grafana dashboard in the default browser")
+			runWebPortForward(options.port, func(portforward *k8s.PortForward) {
+				webURL := portforward.URLFor("")
+				grafanaURL := portforward.URLFor("/grafana")
 
-				err = browser.OpenURL(grafanaURL)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, "Failed to open Grafana dashboard automatically")
-					fmt.Fprintf(os.Stderr, "Visit %s in your browser to view the dashboard\n", grafanaURL)
-				}
-			case showURL:
-				// no-op, we already printed the URLs
-			}
+				fmt.Printf("Linkerd dashboard available at:\n%s\n", webURL)
+				fmt.Printf("Grafana dashboard available at:\n%s\n", grafanaURL)
 
-			<-wait
+				openDashboard(options.show, webURL, grafanaURL)
+			})
 			return nil
 		},
 	}
@@ -134,6 +125,264 @@ func newCmdDashboard() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(&options.port, "port", "p", options.port, "The local port on which to serve requests (when set to 0, a random port will be used)")
 	cmd.PersistentFlags().StringVar(&options.show, "show", options.show, "Open a dashboard in a browser or show URLs in the CLI (one of: linkerd, grafana, url)")
 	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Wait for dashboard to become available if it's not available when the command is run")
+	cmd.PersistentFlags().BoolVar(&options.external, "external", options.external, "Open the dashboard through its external-http port, exposed outside the cluster by an Ingress terminating TLS, instead of a port-forward")
+	cmd.PersistentFlags().DurationVar(&options.tokenTTL, "token-ttl", options.tokenTTL, "How long the token of the external dashboard URLs is valid for (only with --external)")
 
 	return cmd
 }
+
+// openDashboard opens the dashboard selected by the `show` flag in the
+// default browser.
+func openDashboard(show, webURL, grafanaURL string) {
+	switch show {
+	case showLinkerd:
+		fmt.Println("Opening Linkerd dashboard in the default browser")
+
+		err := browser.OpenURL(webURL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open Linkerd dashboard automatically")
+			fmt.Fprintf(os.Stderr, "Visit %s in your browser to view the dashboard\n", webURL)
+		}
+	case showGrafana:
+		fmt.Println("Opening Grafana dashboard in the default browser")
+
+		err := browser.OpenURL(grafanaURL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open Grafana dashboard automatically")
+			fmt.Fprintf(os.Stderr, "Visit %s in your browser to view the dashboard\n", grafanaURL)
+		}
+	case showURL:
+		// no-op, we already printed the URLs
+	}
+}
+
+// runExternalDashboard prints the external URLs of the dashboard, with a token
+// valid for options.tokenTTL, and opens the one selected by options.show.
+func runExternalDashboard(options *dashboardOptions) error {
+	client, err := configClient(externalDashboardTimeout)
+	if err != nil {
+		return err
+	}
+
+	baseURL, err := externalWebURL(client, controlPlaneNamespace)
+	if err != nil {
+		return err
+	}
+
+	key, created, err := webTokenKey(client, controlPlaneNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the token key: %s", err)
+	}
+	if created {
+		fmt.Fprintln(os.Stderr, "External access to the dashboard is now enabled; it may take a minute for the dashboard to accept the token")
+	}
+
+	expiry := time.Now().Add(options.tokenTTL)
+	query := "?token=" + webtoken.Generate(key, expiry)
+	webURL := baseURL + "/" + query
+	grafanaURL := baseURL + "/grafana" + query
+
+	fmt.Printf("Linkerd dashboard available at:\n%s\n", webURL)
+	fmt.Printf("Grafana dashboard available at:\n%s\n", grafanaURL)
+	fmt.Printf("These URLs expire at %s\n", expiry.Format(time.RFC1123))
+
+	openDashboard(options.show, webURL, grafanaURL)
+	return nil
+}
+
+// webTokenKey returns the key signing the tokens of the external dashboard
+// URLs, creating its secret in the namespace if it doesn't exist yet. It also
+// returns whether the secret was created.
+func webTokenKey(client kubernetes.Interface, namespace string) ([]byte, bool, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(k8s.WebTokenSecretName, meta_v1.GetOptions{})
+	if err == nil {
+		key := secret.Data[k8s.WebTokenSecretKey]
+		if len(key) == 0 {
+			return nil, false, fmt.Errorf("Secret %s/%s has no %q key", namespace, k8s.WebTokenSecretName, k8s.WebTokenSecretKey)
+		}
+		return key, false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, false, err
+	}
+
+	key := make([]byte, webTokenKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, err
+	}
+	_, err = client.CoreV1().Secrets(namespace).Create(&v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      k8s.WebTokenSecretName,
+			Namespace: namespace,
+			Labels:    map[string]string{k8s.ControllerNSLabel: namespace},
+		},
+		Data: map[string][]byte{k8s.WebTokenSecretKey: key},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// externalWebURL returns the base URL of the external-http port of the web
+// service, from an Ingress routing the root path to it and terminating TLS.
+// The port serves plain HTTP, so that exposing it otherwise, e.g. with a
+// LoadBalancer service, would leak the tokens of its URLs and cookies.
+func externalWebURL(client kubernetes.Interface, namespace string) (string, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(webService, meta_v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	plainHTTP := false
+	if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		for _, port := range svc.Spec.Ports {
+			if port.Name == webExternalPortName {
+				plainHTTP = true
+			}
+		}
+	}
+
+	ingresses, err := client.ExtensionsV1beta1().Ingresses(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, ingress := range ingresses.Items {
+		host, ok := ingressWebHost(ingress)
+		if !ok {
+			continue
+		}
+		if host == "" {
+			host = loadBalancerHost(ingress.Status.LoadBalancer)
+		}
+		if host == "" {
+			continue
+		}
+
+		if !ingressTLS(ingress, host) {
+			plainHTTP = true
+			continue
+		}
+		return "https://" + host, nil
+	}
+
+	if plainHTTP {
+		return "", fmt.Errorf("the %s port of the %s/%s service is only exposed over plain HTTP, which would leak the tokens of the dashboard; expose it with an Ingress terminating TLS instead",
+			webExternalPortName, namespace, webService)
+	}
+	return "", fmt.Errorf("the %s port of the %s/%s service isn't exposed outside the cluster; expose it with an Ingress terminating TLS",
+		webExternalPortName, namespace, webService)
+}
+
+// ingressWebHost returns the host of the ingress routing the root path to the
+// external-http port of the web service, which is empty if it routes every
+// host, and whether it routes it at all.
+func ingressWebHost(ingress v1beta1.Ingress) (string, bool) {
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if isWebExternalBackend(path.Backend) && (path.Path == "" || path.Path == "/" || path.Path == "/*") {
+				return rule.Host, true
+			}
+		}
+	}
+	if ingress.Spec.Backend != nil && isWebExternalBackend(*ingress.Spec.Backend) {
+		return "", true
+	}
+	return "", false
+}
+
+func isWebExternalBackend(backend v1beta1.IngressBackend) bool {
+	return backend.ServiceName == webService &&
+		(backend.ServicePort.String() == webExternalPortName || backend.ServicePort.IntValue() == webExternalPort)
+}
+
+// ingressTLS returns whether the ingress terminates TLS for the host.
+func ingressTLS(ingress v1beta1.Ingress, host string) bool {
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.Hosts) == 0 {
+			return true
+		}
+		for _, h := range tls.Hosts {
+			if h == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func loadBalancerHost(status v1.LoadBalancerStatus) string {
+	for _, ingress := range status.Ingress {
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
+
+// runWebPortForward port-forwards a local port to the web service of the
+// control plane, which also serves Grafana, and calls ready once the
+// port-forward is ready. It returns when the port-forward is stopped by an
+// interrupt.
+func runWebPortForward(port int, ready func(portforward *k8s.PortForward)) {
+	wait := make(chan struct{}, 1)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, clientAuth())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize port-forward: %s\n", err)
+		os.Exit(1)
+	}
+
+	portforward, err := k8s.NewServicePortForward(
+		kubeAPI,
+		controlPlaneNamespace,
+		webService,
+		port,
+		webPort,
+		verbose,
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize port-forward: %s\n", err)
+		os.Exit(1)
+	}
+
+	go func() {
+		err := portforward.Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error running port-forward: %s", err)
+			os.Exit(1)
+		}
+		close(wait)
+	}()
+
+	go func() {
+		<-signals
+		portforward.Stop()
+	}()
+
+	go func() {
+		for event := range portforward.Events() {
+			switch event.Type {
+			case k8s.PortForwardDisconnected:
+				fmt.Fprintf(os.Stderr, "%s, reconnecting\n", event.Err)
+			case k8s.PortForwardConnected:
+				if verbose {
+					fmt.Fprintf(os.Stderr, "Forwarding to pod %s\n", event.Pod)
+				}
+			}
+		}
+	}()
+
+	<-portforward.Ready()
+	ready(portforward)
+
+	<-wait
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func webServiceObject(serviceType v1.ServiceType, lbIngress ...v1.LoadBalancerIngress) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: webService, Namespace: "linkerd"},
+		Spec: v1.ServiceSpec{
+			Type: serviceType,
+			Ports: []v1.ServicePort{
+				{Name: "http", Port: webPort},
+				{Name: webExternalPortName, Port: webExternalPort},
+			},
+		},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: lbIngress},
+		},
+	}
+}
+
+func webIngressObject(host string, port intstr.IntOrString, tls []v1beta1.IngressTLS, lbIngress ...v1.LoadBalancerIngress) *v1beta1.Ingress {
+	return &v1beta1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "web", Namespace: "linkerd"},
+		Spec: v1beta1.IngressSpec{
+			TLS: tls,
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{Path: "/", Backend: v1beta1.IngressBackend{ServiceName: webService, ServicePort: port}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: v1beta1.IngressStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: lbIngress},
+		},
+	}
+}
+
+func TestExternalWebURL(t *testing.T) {
+	testCases := []struct {
+		objects     []runtime.Object
+		expectedURL string
+		expectedErr bool
+	}{
+		{
+			// the load balancer would expose the tokens over plain HTTP
+			objects:     []runtime.Object{webServiceObject(v1.ServiceTypeLoadBalancer, v1.LoadBalancerIngress{IP: "1.2.3.4"})},
+			expectedErr: true,
+		},
+		{
+			// so would an ingress without TLS
+			objects: []runtime.Object{
+				webServiceObject(v1.ServiceTypeClusterIP),
+				webIngressObject("linkerd.example.com", intstr.FromString(webExternalPortName), nil),
+			},
+			expectedErr: true,
+		},
+		{
+			objects: []runtime.Object{
+				webServiceObject(v1.ServiceTypeClusterIP),
+				webIngressObject("linkerd.example.com", intstr.FromInt(webExternalPort), []v1beta1.IngressTLS{{Hosts: []string{"linkerd.example.com"}}}),
+			},
+			expectedURL: "https://linkerd.example.com",
+		},
+		{
+			objects: []runtime.Object{
+				webServiceObject(v1.ServiceTypeLoadBalancer, v1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+				webIngressObject("", intstr.FromString(webExternalPortName), []v1beta1.IngressTLS{{}}, v1.LoadBalancerIngress{IP: "5.6.7.8"}),
+			},
+			expectedURL: "https://5.6.7.8",
+		},
+		{
+			// the TLS of the ingress is for another host
+			objects: []runtime.Object{
+				webServiceObject(v1.ServiceTypeClusterIP),
+				webIngressObject("linkerd.example.com", intstr.FromString(webExternalPortName), []v1beta1.IngressTLS{{Hosts: []string{"other.example.com"}}}),
+			},
+			expectedErr: true,
+		},
+		{
+			// the port-forwarded port doesn't require a token
+			objects: []runtime.Object{
+				webServiceObject(v1.ServiceTypeClusterIP),
+				webIngressObject("linkerd.example.com", intstr.FromInt(webPort), nil),
+			},
+			expectedErr: true,
+		},
+		{
+			// the load balancer isn't provisioned yet
+			objects:     []runtime.Object{webServiceObject(v1.ServiceTypeLoadBalancer)},
+			expectedErr: true,
+		},
+		{
+			objects:     []runtime.Object{},
+			expectedErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		client := fake.NewSimpleClientset(tc.objects...)
+		url, err := externalWebURL(client, "linkerd")
+		if tc.expectedErr != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got [%v]", i, tc.expectedErr, err)
+		}
+		if url != tc.expectedURL {
+			t.Fatalf("test case %d: expected URL [%s], got [%s]", i, tc.expectedURL, url)
+		}
+	}
+}
+
+func TestWebTokenKey(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	key, created, err := webTokenKey(client, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !created {
+		t.Fatalf("Expected the secret to be created")
+	}
+	if len(key) != webTokenKeySize {
+		t.Fatalf("Expected a key of %d bytes, got %d", webTokenKeySize, len(key))
+	}
+
+	secret, err := client.CoreV1().Secrets("linkerd").Get(k8s.WebTokenSecretName, meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(secret.Data[k8s.WebTokenSecretKey], key) {
+		t.Fatalf("Expected the secret to hold the key")
+	}
+
+	again, created, err := webTokenKey(client, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if created {
+		t.Fatalf("Expected the existing secret to be reused")
+	}
+	if !bytes.Equal(again, key) {
+		t.Fatalf("Expected the existing key to be returned")
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type edgesOptions struct {
+	statOptionsBase
+	allNamespaces bool
+}
+
+func newEdgesOptions() *edgesOptions {
+	return &edgesOptions{
+		statOptionsBase: *newStatOptionsBase(),
+		allNamespaces:   false,
+	}
+}
+
+func newCmdEdges() *cobra.Command {
+	options := newEdgesOptions()
+
+	cmd := &cobra.Command{
+		Use:   "edges [flags] (RESOURCE)",
+		Short: "Display the edges between resources",
+		Long: `Display the edges between resources.
+
+An edge is the traffic sent by the pods of a resource to the pods of another
+resource of the same type, as reported by the proxies of its source. The edges
+of the specified resources are listed both ways, with their success rate,
+request rate and the percentage of their requests that are TLSed.
+
+Valid resource types include:
+  * daemonsets
+  * deployments
+  * jobs
+  * namespaces
+  * pods
+  * replicationcontrollers
+  * statefulsets`,
+		Example: `  # Edges between the deployments of the test namespace.
+  linkerd edges deploy -n test
+
+  # Edges of the web deployment in the test namespace.
+  linkerd edges deploy/web -n test
+
+  # Edges between the namespaces of the cluster.
+  linkerd edges ns`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: util.ValidTargets,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := buildEdgesRequest(args[0], options)
+			if err != nil {
+				return fmt.Errorf("error creating edges request: %v", err)
+			}
+
+			output, err := requestEdgesFromAPI(cliPublicAPIClient(), req, options)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Print(output)
+
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns edges across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default) and \"json\" are supported")
+
+	return cmd
+}
+
+func requestEdgesFromAPI(client pb.ApiClient, req *pb.EdgesRequest, options *edgesOptions) (string, error) {
+	resp, err := client.Edges(context.Background(), req)
+	if err != nil {
+		return "", fmt.Errorf("Edges API error: %v", err)
+	}
+	if e := resp.GetError(); e != nil {
+		return "", errors.New(e.Error)
+	}
+
+	return renderEdges(resp.GetOk().GetEdges(), req.GetTimeWindow(), options), nil
+}
+
+type edgeRow struct {
+	src         *pb.Resource
+	dst         *pb.Resource
+	successRate float64
+	requestRate float64
+	tlsPercent  float64
+}
+
+func renderEdges(edges []*pb.Edge, timeWindow string, options *edgesOptions) string {
+	rows := make([]*edgeRow, 0, len(edges))
+	for _, edge := range edges {
+		stats := edge.GetStats()
+		rows = append(rows, &edgeRow{
+			src:         edge.GetSrc(),
+			dst:         edge.GetDst(),
+			successRate: getSuccessRate(stats.GetSuccessCount(), stats.GetFailureCount()),
+			requestRate: getRequestRate(stats.GetSuccessCount(), stats.GetFailureCount(), timeWindow),
+			tlsPercent:  getPercentTLS(stats),
+		})
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
+	switch options.outputFormat {
+	case "json":
+		printEdgesJSON(rows, w)
+	default:
+		if len(rows) == 0 {
+			return "No edges found.\n"
+		}
+		printEdgesTable(rows, w)
+	}
+	w.Flush()
+
+	return renderStats(buffer, &options.statOptionsBase)
+}
+
+func printEdgesTable(rows []*edgeRow, w *tabwriter.Writer) {
+	srcWidth, dstWidth := len("SRC"), len("DST")
+	for _, row := range rows {
+		if len(row.src.GetName()) > srcWidth {
+			srcWidth = len(row.src.GetName())
+		}
+		if len(row.dst.GetName()) > dstWidth {
+			dstWidth = len(row.dst.GetName())
+		}
+	}
+	// templates for left-aligning the resource columns
+	srcTemplate := fmt.Sprintf("%%-%ds", srcWidth)
+	dstTemplate := fmt.Sprintf("%%-%ds", dstWidth)
+
+	headers := []string{
+		fmt.Sprintf(srcTemplate, "SRC"),
+		"SRC_NS",
+		fmt.Sprintf(dstTemplate, "DST"),
+		"DST_NS",
+		"SUCCESS",
+		"RPS",
+		"TLS\t", // trailing \t is required to format last column
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	templateString := srcTemplate + "\t%s\t" + dstTemplate + "\t%s\t%.2f%%\t%.1frps\t%.f%%\t\n"
+	for _, row := range rows {
+		fmt.Fprintf(w, templateString,
+			row.src.GetName(),
+			row.src.GetNamespace(),
+			row.dst.GetName(),
+			row.dst.GetNamespace(),
+			row.successRate*100,
+			row.requestRate,
+			row.tlsPercent*100,
+		)
+	}
+}
+
+type jsonEdge struct {
+	Src          string  `json:"src"`
+	SrcNamespace string  `json:"src_namespace"`
+	Dst          string  `json:"dst"`
+	DstNamespace string  `json:"dst_namespace"`
+	Type         string  `json:"type"`
+	Success      float64 `json:"success"`
+	Rps          float64 `json:"rps"`
+	TLS          float64 `json:"tls"`
+}
+
+func printEdgesJSON(rows []*edgeRow, w *tabwriter.Writer) {
+	// avoid nil initialization so that if there are no edges it gets marshalled as an empty array vs null
+	entries := []*jsonEdge{}
+	for _, row := range rows {
+		entries = append(entries, &jsonEdge{
+			Src:          row.src.GetName(),
+			SrcNamespace: row.src.GetNamespace(),
+			Dst:          row.dst.GetName(),
+			DstNamespace: row.dst.GetNamespace(),
+			Type:         row.src.GetType(),
+			Success:      row.successRate,
+			Rps:          row.requestRate,
+			TLS:          row.tlsPercent,
+		})
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	fmt.Fprintf(w, "%s\n", b)
+}
+
+func buildEdgesRequest(resource string, options *edgesOptions) (*pb.EdgesRequest, error) {
+	err := options.validateOutputFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := util.BuildResource(options.namespace, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.BuildEdgesRequest(util.StatsBaseRequestParams{
+		TimeWindow:    options.timeWindow,
+		ResourceName:  target.Name,
+		ResourceType:  target.Type,
+		Namespace:     options.namespace,
+		AllNamespaces: options.allNamespaces,
+	})
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestEdges(t *testing.T) {
+	edge := func(src, dst string, success, failure, tls uint64) *pb.Edge {
+		return &pb.Edge{
+			Src: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: src},
+			Dst: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: dst},
+			Stats: &pb.BasicStats{
+				SuccessCount:    success,
+				FailureCount:    failure,
+				TlsRequestCount: tls,
+			},
+		}
+	}
+	response := &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Ok_{
+			Ok: &pb.EdgesResponse_Ok{
+				Edges: []*pb.Edge{
+					edge("vote-bot", "web", 120, 0, 0),
+					edge("web", "emoji", 234, 6, 240),
+					edge("web", "voting", 54, 6, 30),
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		outputFormat string
+		file         string
+	}{
+		{"", "edges_output.golden"},
+		{"json", "edges_output_json.golden"},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			options := newEdgesOptions()
+			options.namespace = "emojivoto"
+			options.outputFormat = tc.outputFormat
+
+			req, err := buildEdgesRequest("deploy", options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			mockClient := &public.MockAPIClient{EdgesResponseToReturn: response}
+			output, err := requestEdgesFromAPI(mockClient, req, options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			diffCompareFile(t, output, tc.file)
+		})
+	}
+
+	t.Run("Rejects unsupported output formats", func(t *testing.T) {
+		options := newEdgesOptions()
+		options.outputFormat = "wide"
+		if _, err := buildEdgesRequest("deploy", options); err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+	})
+}
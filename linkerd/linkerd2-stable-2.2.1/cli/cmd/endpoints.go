@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -21,6 +22,8 @@ import (
 type endpointsOptions struct {
 	namespace    string
 	outputFormat string
+	clientZone   string
+	watch        bool
 }
 
 var (
@@ -42,6 +45,8 @@ func newEndpointsOptions() *endpointsOptions {
 	return &endpointsOptions{
 		namespace:    "",
 		outputFormat: "",
+		clientZone:   "",
+		watch:        false,
 	}
 }
 
@@ -55,7 +60,13 @@ func newCmdEndpoints() *cobra.Command {
   linkerd endpoints -n emojivoto
 
   # get all endpoints in json
-  linkerd endpoints -o json`
+  linkerd endpoints -o json
+
+  # get the weights of all endpoints for proxies in the us-east-1a zone
+  linkerd endpoints --client-zone us-east-1a
+
+  # watch the endpoints being added and removed in the emojivoto namespace
+  linkerd endpoints -n emojivoto --watch`
 
 	cmd := &cobra.Command{
 		Use:     "endpoints [flags]",
@@ -76,7 +87,11 @@ requests.`,
 				return err
 			}
 
-			endpoints, err := requestEndpointsFromAPI(cliPublicAPIClient())
+			if options.watch {
+				return watchEndpoints(cliPublicAPIClient(), options, os.Stdout)
+			}
+
+			endpoints, err := requestEndpointsFromAPI(cliPublicAPIClient(), options)
 			if err != nil {
 				return fmt.Errorf("Endpoints API error: %s", err)
 			}
@@ -90,12 +105,16 @@ requests.`,
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified endpoints (default: all namespaces)")
 	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" and \"json\" are supported (default \"table\")")
+	cmd.PersistentFlags().StringVar(&options.clientZone, "client-zone", options.clientZone, "Show the weights sent to proxies in this zone (default: weights of proxies in an unknown zone)")
+	cmd.PersistentFlags().BoolVarP(&options.watch, "watch", "w", options.watch, "Watch the endpoints being added and removed, instead of listing the current ones")
 
 	return cmd
 }
 
-func requestEndpointsFromAPI(client public.APIClient) (*discovery.EndpointsResponse, error) {
-	return client.Endpoints(context.Background(), &discovery.EndpointsParams{})
+func requestEndpointsFromAPI(client public.APIClient, options *endpointsOptions) (*discovery.EndpointsResponse, error) {
+	return client.Endpoints(context.Background(), &discovery.EndpointsParams{
+		ClientZone: options.clientZone,
+	})
 }
 
 func renderEndpoints(endpoints *discovery.EndpointsResponse, options *endpointsOptions) string {
@@ -108,12 +127,15 @@ func renderEndpoints(endpoints *discovery.EndpointsResponse, options *endpointsO
 }
 
 type rowEndpoint struct {
+	Event     string `json:"event,omitempty"`
 	Namespace string `json:"namespace"`
 	IP        string `json:"ip"`
 	Port      uint32 `json:"port"`
 	Pod       string `json:"pod"`
 	Version   string `json:"version"`
 	Service   string `json:"service"`
+	Zone      string `json:"zone"`
+	Weight    uint32 `json:"weight"`
 }
 
 func writeEndpointsToBuffer(endpoints *discovery.EndpointsResponse, w *tabwriter.Writer, options *endpointsOptions) {
@@ -122,37 +144,19 @@ func writeEndpointsToBuffer(endpoints *discovery.EndpointsResponse, w *tabwriter
 	endpointsTables := map[string][]rowEndpoint{}
 
 	for serviceID, servicePort := range endpoints.GetServicePorts() {
-		namespace := ""
-		parts := strings.SplitN(serviceID, ".", 2)
-		if len(parts) == 2 {
-			namespace = parts[1]
-		}
-
+		namespace := serviceNamespace(serviceID)
 		if options.namespace != "" && options.namespace != namespace {
 			continue
 		}
 
 		for port, podAddrs := range servicePort.GetPortEndpoints() {
 			for _, podAddr := range podAddrs.GetPodAddresses() {
-				pod := podAddr.GetPod()
-				name := pod.GetName()
-				parts := strings.SplitN(name, "/", 2)
-				if len(parts) == 2 {
-					name = parts[1]
-				}
-				row := rowEndpoint{
-					Namespace: namespace,
-					IP:        addr.PublicIPToString(podAddr.GetAddr().GetIp()),
-					Port:      port,
-					Pod:       name,
-					Version:   pod.GetResourceVersion(),
-					Service:   serviceID,
-				}
+				row := newRowEndpoint(serviceID, port, podAddr)
 
 				endpointsTables[namespace] = append(endpointsTables[namespace], row)
 
-				if len(name) > maxPodLength {
-					maxPodLength = len(name)
+				if len(row.Pod) > maxPodLength {
+					maxPodLength = len(row.Pod)
 				}
 				if len(namespace) > maxNamespaceLength {
 					maxNamespaceLength = len(namespace)
@@ -177,6 +181,36 @@ func writeEndpointsToBuffer(endpoints *discovery.EndpointsResponse, w *tabwriter
 	}
 }
 
+// serviceNamespace returns the namespace of a service ID of the form
+// "name.namespace".
+func serviceNamespace(serviceID string) string {
+	parts := strings.SplitN(serviceID, ".", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return ""
+}
+
+func newRowEndpoint(serviceID string, port uint32, podAddr *discovery.PodAddress) rowEndpoint {
+	pod := podAddr.GetPod()
+	name := pod.GetName()
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 {
+		name = parts[1]
+	}
+
+	return rowEndpoint{
+		Namespace: serviceNamespace(serviceID),
+		IP:        addr.PublicIPToString(podAddr.GetAddr().GetIp()),
+		Port:      port,
+		Pod:       name,
+		Version:   pod.GetResourceVersion(),
+		Service:   serviceID,
+		Zone:      podAddr.GetZone(),
+		Weight:    podAddr.GetWeight(),
+	}
+}
+
 func printEndpointsTables(endpointsTables map[string][]rowEndpoint, w *tabwriter.Writer, options *endpointsOptions, maxPodLength int, maxNamespaceLength int) {
 	firstTable := true // don't print a newline before the first table
 
@@ -191,7 +225,7 @@ func printEndpointsTables(endpointsTables map[string][]rowEndpoint, w *tabwriter
 
 func printEndpointsTable(namespace string, rows []rowEndpoint, w *tabwriter.Writer, options *endpointsOptions, maxPodLength int, maxNamespaceLength int) {
 	headers := make([]string, 0)
-	templateString := "%s\t%d\t%s\t%s\t%s\n"
+	templateString := "%s\t%d\t%s\t%s\t%s\t%s\t%d\n"
 
 	if options.namespace == "" {
 		headers = append(headers, namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
@@ -204,10 +238,17 @@ func printEndpointsTable(namespace string, rows []rowEndpoint, w *tabwriter.Writ
 		podHeader + strings.Repeat(" ", maxPodLength-len(podHeader)),
 		"VERSION",
 		"SERVICE",
+		"ZONE",
+		"WEIGHT",
 	}...)
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
 
 	for _, row := range rows {
+		zone := row.Zone
+		if zone == "" {
+			zone = "-"
+		}
+
 		values := make([]interface{}, 0)
 		if options.namespace == "" {
 			values = append(values,
@@ -220,6 +261,8 @@ func printEndpointsTable(namespace string, rows []rowEndpoint, w *tabwriter.Writ
 			row.Pod,
 			row.Version,
 			row.Service,
+			zone,
+			row.Weight,
 		}...)
 
 		fmt.Fprintf(w, templateString, values...)
@@ -249,3 +292,95 @@ func sortNamespaceKeys(endpointsTables map[string][]rowEndpoint) []string {
 	sort.Strings(sortedKeys)
 	return sortedKeys
 }
+
+// watchEndpoints streams the endpoints being added and removed to w, one row
+// per endpoint, until the stream is closed.
+func watchEndpoints(client public.APIClient, options *endpointsOptions, w io.Writer) error {
+	rsp, err := client.WatchEndpoints(context.Background(), &discovery.EndpointsParams{
+		ClientZone: options.clientZone,
+	})
+	if err != nil {
+		return fmt.Errorf("Endpoints API error: %s", err)
+	}
+
+	if options.outputFormat == "table" || options.outputFormat == "" {
+		fmt.Fprintln(w, endpointsWatchHeader(options))
+	}
+
+	for {
+		update, err := rsp.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Endpoints API error: %s", err)
+		}
+
+		fmt.Fprint(w, renderEndpointsUpdate(update, options))
+	}
+}
+
+func endpointsWatchHeader(options *endpointsOptions) string {
+	headers := []string{"EVENT"}
+	if options.namespace == "" {
+		headers = append(headers, namespaceHeader)
+	}
+	headers = append(headers, "IP", "PORT", podHeader, "VERSION", "SERVICE", "ZONE", "WEIGHT")
+	return strings.Join(headers, "\t")
+}
+
+// renderEndpointsUpdate renders a row for each endpoint added and removed by
+// an update. Since the rows of a watch are printed as they come, they are
+// tab-separated rather than aligned in a table, and the JSON output has an
+// object per line.
+func renderEndpointsUpdate(update *discovery.EndpointsUpdate, options *endpointsOptions) string {
+	if options.namespace != "" && options.namespace != serviceNamespace(update.GetService()) {
+		return ""
+	}
+
+	rows := []rowEndpoint{}
+	for _, podAddr := range update.GetRemove() {
+		row := newRowEndpoint(update.GetService(), update.GetPort(), podAddr)
+		row.Event = "REMOVE"
+		rows = append(rows, row)
+	}
+	for _, podAddr := range update.GetAdd() {
+		row := newRowEndpoint(update.GetService(), update.GetPort(), podAddr)
+		row.Event = "ADD"
+		rows = append(rows, row)
+	}
+
+	var buffer bytes.Buffer
+	for _, row := range rows {
+		switch options.outputFormat {
+		case "table", "":
+			zone := row.Zone
+			if zone == "" {
+				zone = "-"
+			}
+			values := []string{row.Event}
+			if options.namespace == "" {
+				values = append(values, row.Namespace)
+			}
+			values = append(values,
+				row.IP,
+				fmt.Sprintf("%d", row.Port),
+				row.Pod,
+				row.Version,
+				row.Service,
+				zone,
+				fmt.Sprintf("%d", row.Weight),
+			)
+			fmt.Fprintln(&buffer, strings.Join(values, "\t"))
+		case "json":
+			b, err := json.Marshal(row)
+			if err != nil {
+				log.Error(err.Error())
+				continue
+			}
+			fmt.Fprintf(&buffer, "%s\n", b)
+		}
+	}
+
+	return buffer.String()
+}
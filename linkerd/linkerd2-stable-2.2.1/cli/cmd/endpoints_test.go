@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 )
 
 type endpointsExp struct {
@@ -58,7 +60,7 @@ func testEndpointsCall(exp endpointsExp, t *testing.T) {
 
 	mockClient.EndpointsResponseToReturn = &response
 
-	endpoints, err := requestEndpointsFromAPI(mockClient)
+	endpoints, err := requestEndpointsFromAPI(mockClient, exp.options)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -67,3 +69,52 @@ func testEndpointsCall(exp endpointsExp, t *testing.T) {
 
 	diffCompareFile(t, output, exp.file)
 }
+
+func TestWatchEndpoints(t *testing.T) {
+	options := newEndpointsOptions()
+	options.watch = true
+	t.Run("Returns endpoint updates", func(t *testing.T) {
+		testWatchEndpointsCall(endpointsExp{
+			options:    options,
+			identities: []string{"emoji-svc.emojivoto", "authors.books"},
+			file:       "endpoints_watch_output.golden",
+		}, t)
+	})
+
+	options = newEndpointsOptions()
+	options.watch = true
+	options.namespace = "emojivoto"
+	options.outputFormat = "json"
+	t.Run("Returns endpoint updates (json)", func(t *testing.T) {
+		testWatchEndpointsCall(endpointsExp{
+			options:    options,
+			identities: []string{"emoji-svc.emojivoto", "authors.books"},
+			file:       "endpoints_watch_output_json.golden",
+		}, t)
+	})
+}
+
+func testWatchEndpointsCall(exp endpointsExp, t *testing.T) {
+	updates := []*discovery.EndpointsUpdate{}
+	response := public.GenEndpointsResponse(exp.identities)
+	for _, identity := range exp.identities {
+		podAddrs := response.ServicePorts[identity].PortEndpoints[8080].PodAddresses
+		updates = append(updates,
+			&discovery.EndpointsUpdate{Service: identity, Port: 8080, Add: podAddrs},
+			&discovery.EndpointsUpdate{Service: identity, Port: 8080, Remove: podAddrs},
+		)
+	}
+
+	mockClient := &public.MockAPIClient{}
+	mockClient.WatchEndpointsClientToReturn = &public.MockWatchEndpointsClient{
+		UpdatesToReturn: updates,
+	}
+
+	var output bytes.Buffer
+	err := watchEndpoints(mockClient, exp.options, &output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	diffCompareFile(t, output.String(), exp.file)
+}
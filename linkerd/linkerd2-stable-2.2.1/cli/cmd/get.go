@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/spf13/cobra"
@@ -14,12 +20,16 @@ import (
 type getOptions struct {
 	namespace     string
 	allNamespaces bool
+	outputFormat  string
 }
 
+const wideOutput = "wide"
+
 func newGetOptions() *getOptions {
 	return &getOptions{
 		namespace:     "default",
 		allNamespaces: false,
+		outputFormat:  "",
 	}
 }
 
@@ -36,7 +46,10 @@ Only pod resources (aka pods, po) are supported.`,
   linkerd get pods
 
   # get pods from namespace linkerd
-  linkerd get pods --namespace linkerd`,
+  linkerd get pods --namespace linkerd
+
+  # get pods from namespace linkerd, with the version, health and resources of their proxies
+  linkerd get pods --namespace linkerd -o wide`,
 		Args:      cobra.ExactArgs(1),
 		ValidArgs: []string{k8s.Pod},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,30 +68,31 @@ Only pod resources (aka pods, po) are supported.`,
 				return fmt.Errorf("invalid resource type %s, valid types: %s", friendlyName, k8s.Pod)
 			}
 
-			podNames, err := getPods(cliPublicAPIClient(), options)
+			if options.outputFormat != "" && options.outputFormat != wideOutput {
+				return fmt.Errorf("--output currently only supports %s", wideOutput)
+			}
+
+			pods, err := getPods(cliPublicAPIClient(), options)
 			if err != nil {
 				return err
 			}
 
-			if len(podNames) == 0 {
+			if len(pods) == 0 {
 				fmt.Fprintln(os.Stderr, "No resources found.")
 				os.Exit(0)
 			}
 
-			for _, podName := range podNames {
-				fmt.Println(podName)
-			}
-
-			return nil
+			return renderPods(pods, options, os.Stdout)
 		},
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of pods")
 	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns pods across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"wide\" is supported, which adds the version, readiness, restarts, uptime and resources of the proxies")
 	return cmd
 }
 
-func getPods(apiClient pb.ApiClient, options *getOptions) ([]string, error) {
+func getPods(apiClient pb.ApiClient, options *getOptions) ([]*pb.Pod, error) {
 	req := &pb.ListPodsRequest{}
 	if !options.allNamespaces {
 		req.Selector = &pb.ResourceSelection{
@@ -93,10 +107,47 @@ func getPods(apiClient pb.ApiClient, options *getOptions) ([]string, error) {
 		return nil, err
 	}
 
-	names := make([]string, 0)
-	for _, pod := range resp.GetPods() {
-		names = append(names, pod.Name)
+	return resp.GetPods(), nil
+}
+
+// renderPods writes the names of the pods, or with the wide output format, a
+// table of the pods along with the details of their proxies.
+func renderPods(pods []*pb.Pod, options *getOptions, w io.Writer) error {
+	var buffer bytes.Buffer
+	if options.outputFormat != wideOutput {
+		for _, pod := range pods {
+			fmt.Fprintln(&buffer, pod.Name)
+		}
+		_, err := w.Write(buffer.Bytes())
+		return err
+	}
+
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"NAME", "STATUS", "PROXY_VERSION", "PROXY_READY", "PROXY_RESTARTS", "PROXY_UPTIME", "PROXY_CPU", "PROXY_MEMORY"}, "\t"))
+	for _, pod := range pods {
+		if pod.ProxyVersion == "" {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\t-\t-\n", pod.Name, pod.Status)
+			continue
+		}
+
+		uptime := "-"
+		if d, err := ptypes.Duration(pod.GetProxyUptime()); err == nil {
+			uptime = d.Round(time.Second).String()
+		}
+		resources := pod.GetProxyResources()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%d\t%s\t%s\t%s\n",
+			pod.Name, pod.Status, pod.ProxyVersion, pod.ProxyReady, pod.ProxyRestartCount, uptime,
+			requestAndLimit(resources.GetCpuRequest(), resources.GetCpuLimit()),
+			requestAndLimit(resources.GetMemoryRequest(), resources.GetMemoryLimit()))
 	}
+	tw.Flush()
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
 
-	return names, nil
+// requestAndLimit formats the request and limit of a resource as
+// request/limit, with dashes for the unset ones.
+func requestAndLimit(request, limit string) string {
+	return valueOrDash(request) + "/" + valueOrDash(limit)
 }
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 )
@@ -28,15 +30,15 @@ func TestGetPods(t *testing.T) {
 		}
 
 		mockClient.ListPodsResponseToReturn = response
-		actualPodNames, err := getPods(mockClient, newGetOptions())
+		actualPods, err := getPods(mockClient, newGetOptions())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		for i, actualName := range actualPodNames {
+		for i, actualPod := range actualPods {
 			expectedName := expectedPodNames[i]
-			if expectedName != actualName {
-				t.Fatalf("Expected %dth element on %v to be [%s], but was [%s]", i, actualPodNames, expectedName, actualName)
+			if expectedName != actualPod.Name {
+				t.Fatalf("Expected %dth element on %v to be [%s], but was [%s]", i, actualPods, expectedName, actualPod.Name)
 			}
 		}
 	})
@@ -48,13 +50,13 @@ func TestGetPods(t *testing.T) {
 			Pods: []*pb.Pod{},
 		}
 
-		actualPodNames, err := getPods(mockClient, newGetOptions())
+		actualPods, err := getPods(mockClient, newGetOptions())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		if len(actualPodNames) != 0 {
-			t.Fatalf("Expecting no pod names, got %v", actualPodNames)
+		if len(actualPods) != 0 {
+			t.Fatalf("Expecting no pods, got %v", actualPods)
 		}
 	})
 
@@ -67,4 +69,36 @@ func TestGetPods(t *testing.T) {
 			t.Fatalf("Expecting error, got noting")
 		}
 	})
+
+	t.Run("Renders the proxy details of the pods with the wide output format", func(t *testing.T) {
+		pods := []*pb.Pod{
+			{
+				Name:              "emojivoto/emoji-1",
+				Status:            "Running",
+				ProxyVersion:      "stable-2.2.1",
+				ProxyReady:        true,
+				ProxyRestartCount: 1,
+				ProxyUptime:       &duration.Duration{Seconds: 3723, Nanos: 600000000},
+				ProxyResources: &pb.Pod_ProxyResources{
+					CpuRequest:    "100m",
+					MemoryRequest: "20Mi",
+					MemoryLimit:   "250Mi",
+				},
+			},
+			{Name: "emojivoto/vote-bot-1", Status: "Pending"},
+		}
+
+		options := newGetOptions()
+		options.outputFormat = wideOutput
+
+		var buf bytes.Buffer
+		if err := renderPods(pods, options, &buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := `NAME                   STATUS    PROXY_VERSION   PROXY_READY   PROXY_RESTARTS   PROXY_UPTIME   PROXY_CPU   PROXY_MEMORY
+emojivoto/emoji-1      Running   stable-2.2.1    true          1                1h2m4s         100m/-      20Mi/250Mi
+emojivoto/vote-bot-1   Pending   -               -             -                -              -           -
+`
+		diffCompare(t, buf.String(), expected)
+	})
 }
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+// grafanaDashboards maps the resource types with a Grafana dashboard, in
+// grafana/dashboards, to the slug of their dashboard.
+var grafanaDashboards = map[string]string{
+	k8s.Authority:             "linkerd-authority",
+	k8s.DaemonSet:             "linkerd-daemonset",
+	k8s.Deployment:            "linkerd-deployment",
+	k8s.Namespace:             "linkerd-top-line",
+	k8s.Pod:                   "linkerd-pod",
+	k8s.ReplicationController: "linkerd-replicationcontroller",
+	k8s.Service:               "linkerd-service",
+	k8s.StatefulSet:           "linkerd-statefulset",
+}
+
+type grafanaOptions struct {
+	namespace string
+	port      int
+	urlOnly   bool
+	wait      time.Duration
+}
+
+func newGrafanaOptions() *grafanaOptions {
+	return &grafanaOptions{
+		namespace: "default",
+		port:      0,
+		urlOnly:   false,
+		wait:      300 * time.Second,
+	}
+}
+
+func newCmdGrafana() *cobra.Command {
+	options := newGrafanaOptions()
+
+	cmd := &cobra.Command{
+		Use:   "grafana [flags] [RESOURCE]",
+		Short: "Open the Grafana dashboard of a resource in a web browser",
+		Long: `Open the Grafana dashboard of a resource in a web browser.
+
+  The RESOURCE argument specifies the resource to display the dashboard of:
+  (TYPE/NAME)
+
+  Without RESOURCE, opens the top line dashboard of all the namespaces. The
+  dashboard of a namespace is the top line dashboard of its deployments.
+
+  Valid resource types include:
+  * authorities
+  * daemonsets
+  * deployments
+  * namespaces
+  * pods
+  * replicationcontrollers
+  * services
+  * statefulsets`,
+		Example: `  # Open the dashboard of the web deployment in the emojivoto namespace.
+  linkerd grafana deploy/web -n emojivoto
+
+  # Open the top line dashboard of the emojivoto namespace.
+  linkerd grafana ns/emojivoto
+
+  # Print the URL of the dashboard of the web-svc service without opening a browser.
+  linkerd grafana svc/web-svc -n emojivoto --url`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.port < 0 {
+				return fmt.Errorf("port must be greater than or equal to zero, was %d", options.port)
+			}
+
+			var resource *pb.Resource
+			if len(args) == 1 {
+				res, err := util.BuildResource(options.namespace, args[0])
+				if err != nil {
+					return err
+				}
+				resource = &res
+			}
+			path, err := grafanaDashboardPath(resource)
+			if err != nil {
+				return err
+			}
+
+			// ensure we can connect to the public API before starting the proxy
+			validatedPublicAPIClient(time.Now().Add(options.wait), true)
+
+			runWebPortForward(options.port, func(portforward *k8s.PortForward) {
+				dashboardURL := portforward.URLFor(path)
+
+				fmt.Printf("Grafana dashboard available at:\n%s\n", dashboardURL)
+				if options.urlOnly {
+					return
+				}
+
+				fmt.Println("Opening Grafana dashboard in the default browser")
+				err := browser.OpenURL(dashboardURL)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Failed to open Grafana dashboard automatically")
+					fmt.Fprintf(os.Stderr, "Visit %s in your browser to view the dashboard\n", dashboardURL)
+				}
+			})
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
+	cmd.PersistentFlags().IntVarP(&options.port, "port", "p", options.port, "The local port on which to serve requests (when set to 0, a random port will be used)")
+	cmd.PersistentFlags().BoolVar(&options.urlOnly, "url", options.urlOnly, "If present, only prints the URL of the dashboard without opening a browser")
+	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Wait for Grafana to become available if it's not available when the command is run")
+
+	return cmd
+}
+
+// grafanaDashboardPath returns the path of the Grafana dashboard of the
+// resource, relative to the web service, with its variables set to select the
+// resource. A nil resource selects the top line dashboard of all namespaces.
+func grafanaDashboardPath(resource *pb.Resource) (string, error) {
+	if resource == nil {
+		return "/grafana/dashboard/db/" + grafanaDashboards[k8s.Namespace], nil
+	}
+
+	dashboard, ok := grafanaDashboards[resource.Type]
+	if !ok {
+		return "", fmt.Errorf("no Grafana dashboard is available for %s resources", resource.Type)
+	}
+
+	vars := url.Values{}
+	if resource.Type == k8s.Namespace {
+		if resource.Name != "" {
+			vars.Set("var-namespace", resource.Name)
+		}
+	} else {
+		vars.Set("var-namespace", resource.Namespace)
+		if resource.Name != "" {
+			vars.Set("var-"+resource.Type, resource.Name)
+		}
+	}
+
+	path := "/grafana/dashboard/db/" + dashboard
+	if len(vars) > 0 {
+		path += "?" + vars.Encode()
+	}
+	return path, nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestGrafanaDashboardPath(t *testing.T) {
+	testCases := []struct {
+		resource     *pb.Resource
+		expectedPath string
+		expectedErr  bool
+	}{
+		{
+			resource:     nil,
+			expectedPath: "/grafana/dashboard/db/linkerd-top-line",
+		},
+		{
+			resource:     &pb.Resource{Type: k8s.Deployment, Namespace: "emojivoto", Name: "web"},
+			expectedPath: "/grafana/dashboard/db/linkerd-deployment?var-deployment=web&var-namespace=emojivoto",
+		},
+		{
+			resource:     &pb.Resource{Type: k8s.Pod, Namespace: "emojivoto"},
+			expectedPath: "/grafana/dashboard/db/linkerd-pod?var-namespace=emojivoto",
+		},
+		{
+			resource:     &pb.Resource{Type: k8s.Namespace, Name: "emojivoto"},
+			expectedPath: "/grafana/dashboard/db/linkerd-top-line?var-namespace=emojivoto",
+		},
+		{
+			resource:     &pb.Resource{Type: k8s.Authority, Namespace: "emojivoto", Name: "web-svc.emojivoto:80"},
+			expectedPath: "/grafana/dashboard/db/linkerd-authority?var-authority=web-svc.emojivoto%3A80&var-namespace=emojivoto",
+		},
+		{
+			resource:    &pb.Resource{Type: k8s.Job, Namespace: "emojivoto", Name: "migrate"},
+			expectedErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		path, err := grafanaDashboardPath(tc.resource)
+		if tc.expectedErr != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got [%v]", i, tc.expectedErr, err)
+		}
+		if path != tc.expectedPath {
+			t.Fatalf("test case %d: expected path [%s], got [%s]", i, tc.expectedPath, path)
+		}
+	}
+}
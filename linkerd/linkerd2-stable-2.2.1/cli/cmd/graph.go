@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/spf13/cobra"
+)
+
+const defaultGraphResource = "deployments"
+
+type graphOptions struct {
+	namespace     string
+	timeWindow    string
+	allNamespaces bool
+	outputFormat  string
+}
+
+func newGraphOptions() *graphOptions {
+	return &graphOptions{
+		namespace:     "default",
+		timeWindow:    "1m",
+		allNamespaces: false,
+		outputFormat:  "dot",
+	}
+}
+
+func newCmdGraph() *cobra.Command {
+	options := newGraphOptions()
+
+	cmd := &cobra.Command{
+		Use:   "graph [flags] [RESOURCE]",
+		Short: "Export the dependency graph of the resources of a namespace",
+		Long: `Export the dependency graph of the resources of a namespace.
+
+The graph is built from the edges between the resources, as displayed by
+"linkerd edges", and is exported either in the DOT language, to be rendered
+with Graphviz, or as JSON. Resources are deployments unless specified.`,
+		Example: `  # Render the dependency graph of the deployments of the test namespace.
+  linkerd graph -n test | dot -Tsvg > test.svg
+
+  # Export the dependency graph of the namespaces of the cluster as JSON.
+  linkerd graph ns -o json`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: util.ValidTargets,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource := defaultGraphResource
+			if len(args) > 0 {
+				resource = args[0]
+			}
+
+			req, err := buildGraphRequest(resource, options)
+			if err != nil {
+				return fmt.Errorf("error creating graph request: %v", err)
+			}
+
+			output, err := requestGraphFromAPI(cliPublicAPIClient(), req, options)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Print(output)
+
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, exports the graph across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"dot\" (default) and \"json\" are supported")
+
+	return cmd
+}
+
+func (o *graphOptions) validateOutputFormat() error {
+	switch o.outputFormat {
+	case "dot", "json":
+		return nil
+	default:
+		return errors.New("--output currently only supports dot and json")
+	}
+}
+
+func buildGraphRequest(resource string, options *graphOptions) (*pb.EdgesRequest, error) {
+	err := options.validateOutputFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := util.BuildResource(options.namespace, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.BuildEdgesRequest(util.StatsBaseRequestParams{
+		TimeWindow:    options.timeWindow,
+		ResourceName:  target.Name,
+		ResourceType:  target.Type,
+		Namespace:     options.namespace,
+		AllNamespaces: options.allNamespaces,
+	})
+}
+
+func requestGraphFromAPI(client pb.ApiClient, req *pb.EdgesRequest, options *graphOptions) (string, error) {
+	resp, err := client.Edges(context.Background(), req)
+	if err != nil {
+		return "", fmt.Errorf("Edges API error: %v", err)
+	}
+	if e := resp.GetError(); e != nil {
+		return "", errors.New(e.Error)
+	}
+
+	graph := buildGraph(resp.GetOk().GetEdges(), req.GetTimeWindow())
+
+	switch options.outputFormat {
+	case "json":
+		b, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	default:
+		return renderGraphDOT(graph, req.GetSelector().GetResource()), nil
+	}
+}
+
+type graphNode struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type"`
+}
+
+type graphEdge struct {
+	Src     string  `json:"src"`
+	Dst     string  `json:"dst"`
+	Success float64 `json:"success"`
+	Rps     float64 `json:"rps"`
+	TLS     float64 `json:"tls"`
+}
+
+type graph struct {
+	Nodes []*graphNode `json:"nodes"`
+	Edges []*graphEdge `json:"edges"`
+}
+
+// graphNodeID returns the ID of the node of a resource, which is qualified by
+// its namespace, if it has one, as graphs can span namespaces.
+func graphNodeID(resource *pb.Resource) string {
+	if resource.GetNamespace() == "" {
+		return resource.GetName()
+	}
+	return resource.GetNamespace() + "/" + resource.GetName()
+}
+
+func buildGraph(edges []*pb.Edge, timeWindow string) *graph {
+	// avoid nil initialization so that an empty graph gets marshalled as empty arrays vs null
+	g := &graph{Nodes: []*graphNode{}, Edges: []*graphEdge{}}
+
+	nodes := make(map[string]*graphNode)
+	for _, edge := range edges {
+		for _, resource := range []*pb.Resource{edge.GetSrc(), edge.GetDst()} {
+			id := graphNodeID(resource)
+			if _, ok := nodes[id]; !ok {
+				nodes[id] = &graphNode{
+					ID:        id,
+					Name:      resource.GetName(),
+					Namespace: resource.GetNamespace(),
+					Type:      resource.GetType(),
+				}
+			}
+		}
+
+		stats := edge.GetStats()
+		g.Edges = append(g.Edges, &graphEdge{
+			Src:     graphNodeID(edge.GetSrc()),
+			Dst:     graphNodeID(edge.GetDst()),
+			Success: getSuccessRate(stats.GetSuccessCount(), stats.GetFailureCount()),
+			Rps:     getRequestRate(stats.GetSuccessCount(), stats.GetFailureCount(), timeWindow),
+			TLS:     getPercentTLS(stats),
+		})
+	}
+
+	for _, node := range nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+
+	return g
+}
+
+// renderGraphDOT renders a graph in the DOT language. Edges are labeled with
+// their success and request rates, and those that aren't fully TLSed are
+// dashed.
+func renderGraphDOT(g *graph, resource *pb.Resource) string {
+	var buffer bytes.Buffer
+
+	name := resource.GetNamespace()
+	if name == "" {
+		name = "linkerd"
+	}
+	fmt.Fprintf(&buffer, "digraph %q {\n", name)
+	fmt.Fprintln(&buffer, "  node [shape=box];")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&buffer, "  %q [label=%q];\n", node.ID, node.Name)
+	}
+	for _, edge := range g.Edges {
+		style := ""
+		if edge.TLS < 1 {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&buffer, "  %q -> %q [label=\"%.2f%% %.1frps\"%s];\n", edge.Src, edge.Dst, edge.Success*100, edge.Rps, style)
+	}
+	fmt.Fprintln(&buffer, "}")
+
+	return buffer.String()
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestGraph(t *testing.T) {
+	edge := func(src, dst string, success, failure, tls uint64) *pb.Edge {
+		return &pb.Edge{
+			Src: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: src},
+			Dst: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: dst},
+			Stats: &pb.BasicStats{
+				SuccessCount:    success,
+				FailureCount:    failure,
+				TlsRequestCount: tls,
+			},
+		}
+	}
+	response := &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Ok_{
+			Ok: &pb.EdgesResponse_Ok{
+				Edges: []*pb.Edge{
+					edge("vote-bot", "web", 120, 0, 0),
+					edge("web", "emoji", 234, 6, 240),
+					edge("web", "voting", 54, 6, 30),
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		outputFormat string
+		file         string
+	}{
+		{"dot", "graph_output.golden"},
+		{"json", "graph_output_json.golden"},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			options := newGraphOptions()
+			options.namespace = "emojivoto"
+			options.outputFormat = tc.outputFormat
+
+			req, err := buildGraphRequest(defaultGraphResource, options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			mockClient := &public.MockAPIClient{EdgesResponseToReturn: response}
+			output, err := requestGraphFromAPI(mockClient, req, options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			diffCompareFile(t, output, tc.file)
+		})
+	}
+
+	t.Run("Rejects unsupported output formats", func(t *testing.T) {
+		options := newGraphOptions()
+		options.outputFormat = "table"
+		if _, err := buildGraphRequest(defaultGraphResource, options); err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+	})
+}
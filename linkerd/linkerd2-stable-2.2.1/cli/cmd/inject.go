@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
@@ -35,10 +34,15 @@ const (
 	injectDisabledDesc = "pods are not annotated to disable injection"
 	unsupportedDesc    = "at least one resource injected"
 	udpDesc            = "pod specs do not include UDP ports"
+	autoSkipPortsDesc  = "pod specs do not include ports of protocols the proxy can't detect"
 )
 
 type injectOptions struct {
 	*proxyConfigOptions
+	helmPostRenderer bool
+	ignoreCluster    bool
+	autoSkipPorts    bool
+	summary          summaryOptions
 }
 
 type resourceTransformerInject struct{}
@@ -60,6 +64,10 @@ type objMeta struct {
 func newInjectOptions() *injectOptions {
 	return &injectOptions{
 		proxyConfigOptions: newProxyConfigOptions(),
+		helmPostRenderer:   false,
+		ignoreCluster:      false,
+		autoSkipPorts:      true,
+		summary:            summaryOptions{},
 	}
 }
 
@@ -72,7 +80,17 @@ func newCmdInject() *cobra.Command {
 		Long: `Add the Linkerd proxy to a Kubernetes config.
 
 You can inject resources contained in a single file, inside a folder and its
-sub-folders, or coming from stdin.`,
+sub-folders, or coming from stdin.
+
+The flags that aren't set default to the configuration the control plane was
+installed with, if it can be read from the cluster; see "linkerd config view".
+
+With the --helm-post-renderer flag, the resources are read from stdin and the
+injected resources are the only output, for Helm post-renderers. The command
+exits with a non-zero code and prints the error if any resource can't be
+injected, in which case nothing is written to stdout. Documents without any
+resource, like the ones of the templates rendered empty, are kept as is, and
+the comments of the injected resources are dropped.`,
 		Example: `  # Inject all the deployments in the default namespace.
   kubectl get deploy -o yaml | linkerd inject - | kubectl apply -f -
 
@@ -80,8 +98,31 @@ sub-folders, or coming from stdin.`,
   curl http://url.to/yml | linkerd inject - | kubectl apply -f -
 
   # Inject all the resources inside a folder and its sub-folders.
-  linkerd inject <folder> | kubectl apply -f -`,
+  linkerd inject <folder> | kubectl apply -f -
+
+  # Inject the resources of a Helm chart as they are installed, through an
+  # executable script that runs "linkerd inject --helm-post-renderer".
+  helm install --post-renderer ./linkerd-inject.sh <chart>`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !options.ignoreCluster {
+				loadProxyConfig(cmd.Flags(), options.proxyConfigOptions)
+			}
+
+			if options.helmPostRenderer {
+				if len(args) > 0 {
+					return fmt.Errorf("--helm-post-renderer reads the resources from stdin, no kubernetes resource file is expected")
+				}
+
+				if err := options.validate(); err != nil {
+					return err
+				}
+				if err := options.summary.validate(); err != nil {
+					return err
+				}
+
+				os.Exit(runInjectHelmPostRenderer(os.Stdin, stderr, stdout, options))
+				return nil
+			}
 
 			if len(args) < 1 {
 				return fmt.Errorf("please specify a kubernetes resource file")
@@ -90,6 +131,9 @@ sub-folders, or coming from stdin.`,
 			if err := options.validate(); err != nil {
 				return err
 			}
+			if err := options.summary.validate(); err != nil {
+				return err
+			}
 
 			in, err := read(args[0])
 			if err != nil {
@@ -103,13 +147,38 @@ sub-folders, or coming from stdin.`,
 	}
 
 	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	cmd.PersistentFlags().BoolVar(&options.helmPostRenderer, "helm-post-renderer", options.helmPostRenderer, "Read the resources from stdin and only output the injected resources, for use as a Helm post-renderer")
+	cmd.PersistentFlags().BoolVar(&options.autoSkipPorts, "auto-skip-ports", options.autoSkipPorts, "Skip the proxy for the container ports of the protocols it can't detect, such as MySQL and SMTP, whose servers speak first")
+	cmd.PersistentFlags().BoolVar(&options.ignoreCluster, "ignore-cluster", options.ignoreCluster, "Ignore the configuration of the control plane installed in the cluster, which is otherwise used as the defaults of the flags")
+	addSummaryFlags(cmd, &options.summary, "injected")
 
 	return cmd
 }
 
+// runInjectHelmPostRenderer injects the resources read from in, as a Helm
+// post-renderer: the injected resources are written to outWriter only if all
+// of them were injected, and the inject report is dropped, so that errWriter
+// only gets the errors.
+func runInjectHelmPostRenderer(in io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
+	var out, report bytes.Buffer
+	if exitCode := uninjectAndInject([]io.Reader{in}, &report, &out, options); exitCode != 0 {
+		io.Copy(errWriter, &report)
+		return exitCode
+	}
+
+	if _, err := io.Copy(outWriter, &out); err != nil {
+		fmt.Fprintf(errWriter, "Error printing YAML: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 func uninjectAndInject(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
 	var out bytes.Buffer
-	if exitCode := runUninjectSilentCmd(inputs, errWriter, &out, nil); exitCode != 0 {
+	uninjectOptions := &injectOptions{
+		summary: summaryOptions{format: options.summary.format, errorsOnly: true},
+	}
+	if exitCode := runUninjectSilentCmd(inputs, errWriter, &out, uninjectOptions); exitCode != 0 {
 		return exitCode
 	}
 	return runInjectCmd([]io.Reader{&out}, errWriter, outWriter, options)
@@ -143,9 +212,12 @@ func injectObjectMeta(t *metaV1.ObjectMeta, k8sLabels map[string]string, options
 
 /* Given a PodSpec, update the PodSpec in place with the sidecar
  * and init-container injected. If the pod is unsuitable for having them
- * injected, return false.
+ * injected, return false. If awaitProxy is true, the sidecar is injected as
+ * the first container, and the other containers wait for it to be ready. If
+ * shutdownProxy is true, the sidecar exits once the other containers have
+ * exited.
  */
-func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameOverride string, options *injectOptions, report *injectReport) bool {
+func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameOverride string, awaitProxy, shutdownProxy bool, options *injectOptions, report *injectReport) bool {
 	report.hostNetwork = t.HostNetwork
 	report.sidecar = healthcheck.HasExistingSidecars(t)
 	report.udp = checkUDPPorts(t)
@@ -160,16 +232,20 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 	}
 
 	f := false
-	inboundSkipPorts := append(options.ignoreInboundPorts, options.proxyControlPort, options.proxyMetricsPort)
-	inboundSkipPortsStr := make([]string, len(inboundSkipPorts))
-	for i, p := range inboundSkipPorts {
-		inboundSkipPortsStr[i] = strconv.Itoa(int(p))
+	inboundSkipPorts := skipPorts(options.ignoreInboundPorts)
+	if options.autoSkipPorts {
+		detected := k8s.DetectServerFirstPorts(t, func(port uint) bool { return isSkipped(port, inboundSkipPorts) })
+		for _, port := range detected {
+			protocol, _ := k8s.ServerFirstProtocol(port)
+			report.autoSkippedPorts = append(report.autoSkippedPorts, fmt.Sprintf("%d (%s)", port, protocol))
+			inboundSkipPorts = append(inboundSkipPorts, portRange{first: port, last: port})
+		}
 	}
-
-	outboundSkipPortsStr := make([]string, len(options.ignoreOutboundPorts))
-	for i, p := range options.ignoreOutboundPorts {
-		outboundSkipPortsStr[i] = strconv.Itoa(int(p))
+	for _, port := range []uint{options.proxyControlPort, options.proxyMetricsPort} {
+		inboundSkipPorts = append(inboundSkipPorts, portRange{first: port, last: port})
 	}
+	inboundSkipPortsStr := formatPortRanges(inboundSkipPorts)
+	outboundSkipPortsStr := formatPortRanges(skipPorts(options.ignoreOutboundPorts))
 
 	initArgs := []string{
 		"--incoming-proxy-port", fmt.Sprintf("%d", options.inboundPort),
@@ -186,6 +262,7 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 		initArgs = append(initArgs, "--outbound-ports-to-ignore")
 		initArgs = append(initArgs, strings.Join(outboundSkipPortsStr, ","))
 	}
+	initArgs = append(initArgs, options.iptablesInitArgs()...)
 
 	controlPlaneDNS := fmt.Sprintf("linkerd-proxy-api.%s.svc.cluster.local", controlPlaneNamespace)
 	if controlPlaneDNSNameOverride != "" {
@@ -319,6 +396,10 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 			{Name: "LINKERD2_PROXY_TLS_CONTROLLER_IDENTITY", Value: identity.ToControllerIdentity().ToDNSName()},
 		}
 
+		if options.requireTLS() {
+			tlsEnvVars = append(tlsEnvVars, v1.EnvVar{Name: k8s.ProxyTLSRequiredEnvVar, Value: "true"})
+		}
+
 		sidecar.Env = append(sidecar.Env, tlsEnvVars...)
 		sidecar.VolumeMounts = []v1.VolumeMount{
 			{Name: configMapVolume.Name, MountPath: configMapBase, ReadOnly: true},
@@ -328,7 +409,18 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 		t.Volumes = append(t.Volumes, configMapVolume, secretVolume)
 	}
 
-	t.Containers = append(t.Containers, sidecar)
+	if shutdownProxy {
+		yes := true
+		t.ShareProcessNamespace = &yes
+		k8s.ShutdownProxy(&sidecar)
+	}
+
+	if awaitProxy {
+		k8s.AwaitProxy(&sidecar)
+		t.Containers = append([]v1.Container{sidecar}, t.Containers...)
+	} else {
+		t.Containers = append(t.Containers, sidecar)
+	}
 	if !options.noInitContainer {
 		nonRoot := false
 		runAsUser := int64(0)
@@ -385,7 +477,9 @@ func (rt resourceTransformerInject) transform(bytes []byte, options *injectOptio
 			ControllerNamespace: controlPlaneNamespace,
 		}
 
-		if injectPodSpec(conf.podSpec, identity, conf.dnsNameOverride, options, &report) &&
+		awaitProxy := k8s.ShouldAwaitProxy(conf.objectMeta.GetAnnotations(), options.proxyAwait)
+		shutdownProxy := conf.meta.Kind == "Job" && k8s.ShouldShutdownProxy(conf.objectMeta.GetAnnotations(), options.proxyJobShutdown)
+		if injectPodSpec(conf.podSpec, identity, conf.dnsNameOverride, awaitProxy, shutdownProxy, options, &report) &&
 			injectObjectMeta(conf.objectMeta, conf.k8sLabels, options, &report) {
 			var err error
 			output, err = yaml.Marshal(conf.obj)
@@ -405,6 +499,7 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 	hostNetwork := []string{}
 	sidecar := []string{}
 	udp := []string{}
+	autoSkipped := []string{}
 	injectDisabled := []string{}
 	warningsPrinted := verbose
 
@@ -432,6 +527,11 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 			injectDisabled = append(injectDisabled, r.resName())
 			warningsPrinted = true
 		}
+
+		if len(r.autoSkippedPorts) > 0 {
+			autoSkipped = append(autoSkipped, fmt.Sprintf("%s: %s", r.resName(), strings.Join(r.autoSkippedPorts, ", ")))
+			warningsPrinted = true
+		}
 	}
 
 	//
@@ -477,6 +577,14 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 		output.Write([]byte(fmt.Sprintf("%s %s\n", okStatus, udpDesc)))
 	}
 
+	if len(autoSkipped) > 0 {
+		for _, ports := range autoSkipped {
+			output.Write([]byte(fmt.Sprintf("%s the proxy is skipped for the ports of protocols it can't detect in %s\n", warnStatus, ports)))
+		}
+	} else if verbose {
+		output.Write([]byte(fmt.Sprintf("%s %s\n", okStatus, autoSkipPortsDesc)))
+	}
+
 	//
 	// Summary
 	//
@@ -496,6 +604,21 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 	output.Write([]byte("\n"))
 }
 
+func (resourceTransformerInject) outcome(r injectReport) resourceOutcome {
+	switch {
+	case r.unsupportedResource:
+		return outcomeSkippedUnsupportedKind
+	case r.sidecar:
+		return outcomeSkippedAlreadyInjected
+	case r.hostNetwork:
+		return outcomeSkippedHostNetwork
+	case r.injectDisabled:
+		return outcomeSkippedInjectDisabled
+	default:
+		return outcomeInjected
+	}
+}
+
 func checkUDPPorts(t *v1.PodSpec) bool {
 	// check for ports with `protocol: UDP`, which will not be routed by Linkerd
 	for _, container := range t.Containers {
@@ -508,6 +631,16 @@ func checkUDPPorts(t *v1.PodSpec) bool {
 	return false
 }
 
+// isSkipped is true if the port is in one of the skipped ranges.
+func isSkipped(port uint, skipped []portRange) bool {
+	for _, r := range skipped {
+		if r.contains(port) {
+			return true
+		}
+	}
+	return false
+}
+
 func injectDisabled(t *metaV1.ObjectMeta) bool {
 	return t.GetAnnotations()[k8s.ProxyInjectAnnotation] == k8s.ProxyInjectDisabled
 }
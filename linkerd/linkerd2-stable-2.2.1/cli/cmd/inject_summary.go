@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// resourceOutcome is what inject or uninject did with a resource, as
+// reported in the summary.
+type resourceOutcome string
+
+const (
+	outcomeInjected               resourceOutcome = "injected"
+	outcomeUninjected             resourceOutcome = "uninjected"
+	outcomeSkippedAlreadyInjected resourceOutcome = "skipped-already-injected"
+	outcomeSkippedNotInjected     resourceOutcome = "skipped-not-injected"
+	outcomeSkippedUnsupportedKind resourceOutcome = "skipped-unsupported-kind"
+	outcomeSkippedHostNetwork     resourceOutcome = "skipped-host-network"
+	outcomeSkippedInjectDisabled  resourceOutcome = "skipped-inject-disabled"
+	outcomeError                  resourceOutcome = "error"
+)
+
+func (o resourceOutcome) changed() bool {
+	return o == outcomeInjected || o == outcomeUninjected
+}
+
+// summaryOptions configures the summary of the outcomes of the resources,
+// written to stderr after the inject or uninject report.
+type summaryOptions struct {
+	format         string
+	failIfNoChange bool
+
+	// errorsOnly restricts the summary to the error that stopped the
+	// transformation, for the uninject pass that precedes inject
+	errorsOnly bool
+}
+
+// resourceSummary is a row of the summary.
+type resourceSummary struct {
+	Kind    string          `json:"kind"`
+	Name    string          `json:"name"`
+	Outcome resourceOutcome `json:"outcome"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func (options *summaryOptions) validate() error {
+	switch options.format {
+	case "table", "json", "":
+		return nil
+	default:
+		return fmt.Errorf("--summary currently only supports table and json")
+	}
+}
+
+// addSummaryFlags adds the summary flags to the inject or uninject command,
+// whose changed resources are described by verb, e.g. "injected".
+func addSummaryFlags(cmd *cobra.Command, options *summaryOptions, verb string) {
+	cmd.PersistentFlags().StringVar(&options.format, "summary", options.format,
+		"Print a summary of the outcome of each resource to stderr, as a table or json")
+	cmd.PersistentFlags().BoolVar(&options.failIfNoChange, "fail-if-none-"+verb, options.failIfNoChange,
+		fmt.Sprintf("Exit with a non-zero code if no resource was %s", verb))
+}
+
+// summarize returns the summary of the reports of the resources transformed
+// by rt, followed by the error that stopped the transformation, if any.
+func summarize(rt resourceTransformer, reports []injectReport, err error) []resourceSummary {
+	rows := []resourceSummary{}
+	for _, r := range reports {
+		rows = append(rows, resourceSummary{
+			Kind:    r.kind,
+			Name:    r.name,
+			Outcome: rt.outcome(r),
+		})
+	}
+	if err != nil {
+		rows = append(rows, resourceSummary{Outcome: outcomeError, Error: err.Error()})
+	}
+	return rows
+}
+
+// reportSummary writes the summary of the resources to w, if it was
+// requested, and returns the exit code of the command: 1 if it must fail
+// because no resource was changed, 0 otherwise.
+func reportSummary(w io.Writer, options *summaryOptions, rows []resourceSummary) int {
+	if options.format != "" {
+		if err := renderSummary(rows, options.format, w); err != nil {
+			fmt.Fprintf(w, "Error printing summary: %v\n", err)
+			return 1
+		}
+	}
+
+	if options.failIfNoChange {
+		for _, row := range rows {
+			if row.Outcome.changed() {
+				return 0
+			}
+		}
+		fmt.Fprintln(w, "Error: all the resources were skipped")
+		return 1
+	}
+	return 0
+}
+
+func renderSummary(rows []resourceSummary, format string, w io.Writer) error {
+	if format == "json" {
+		b, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	var buffer bytes.Buffer
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"KIND", "NAME", "OUTCOME"}, "\t"))
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", valueOrDash(row.Kind), valueOrDash(row.Name), row.Outcome)
+	}
+	tw.Flush()
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
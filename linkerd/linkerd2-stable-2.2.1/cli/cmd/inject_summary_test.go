@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+const summaryServiceYAML = `apiVersion: v1
+kind: Service
+metadata:
+  name: web-svc
+  namespace: emojivoto
+spec:
+  ports:
+  - name: http
+    port: 80
+  selector:
+    app: web-svc
+`
+
+func openTestInputs(t *testing.T, fileNames ...string) []io.Reader {
+	inputs := []io.Reader{}
+	for _, fileName := range fileNames {
+		file, err := os.Open("testdata/" + fileName)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		inputs = append(inputs, file)
+	}
+	return inputs
+}
+
+func TestInjectSummary(t *testing.T) {
+	t.Run("Reports the outcome of each resource", func(t *testing.T) {
+		options := newInjectOptions()
+		options.linkerdVersion = "testinjectversion"
+		options.summary.format = "table"
+
+		inputs := openTestInputs(t,
+			"inject_emojivoto_deployment.input.yml",
+			"inject_emojivoto_deployment_hostNetwork_true.input.yml",
+			"inject_emojivoto_istio.input.yml",
+			"inject_emojivoto_deployment_injectDisabled.input.yml",
+		)
+		inputs = append(inputs, strings.NewReader(summaryServiceYAML))
+
+		errBuffer := &bytes.Buffer{}
+		if exitCode := runInjectCmd(inputs, errBuffer, &bytes.Buffer{}, options); exitCode != 0 {
+			t.Fatalf("Expected exit code to be 0 but got: %d", exitCode)
+		}
+		diffCompareFile(t, errBuffer.String(), "inject_summary.golden")
+	})
+
+	t.Run("Fails if no resource was injected", func(t *testing.T) {
+		options := newInjectOptions()
+		options.linkerdVersion = "testinjectversion"
+		options.summary.failIfNoChange = true
+
+		inputs := openTestInputs(t, "inject_emojivoto_deployment_hostNetwork_true.input.yml")
+		inputs = append(inputs, strings.NewReader(summaryServiceYAML))
+
+		errBuffer := &bytes.Buffer{}
+		if exitCode := uninjectAndInject(inputs, errBuffer, &bytes.Buffer{}, options); exitCode != 1 {
+			t.Fatalf("Expected exit code to be 1 but got: %d", exitCode)
+		}
+		if !strings.HasSuffix(errBuffer.String(), "Error: all the resources were skipped\n") {
+			t.Fatalf("Expected the skipped resources error, got: %s", errBuffer.String())
+		}
+	})
+
+	t.Run("Reports the errors as json", func(t *testing.T) {
+		options := newInjectOptions()
+		options.linkerdVersion = "testinjectversion"
+		options.summary.format = "json"
+
+		inputs := openTestInputs(t, "inject_gettest_deployment.bad.input.yml")
+
+		errBuffer := &bytes.Buffer{}
+		if exitCode := uninjectAndInject(inputs, errBuffer, &bytes.Buffer{}, options); exitCode != 1 {
+			t.Fatalf("Expected exit code to be 1 but got: %d", exitCode)
+		}
+
+		lines := strings.SplitN(errBuffer.String(), "\n", 2)
+		var summary []resourceSummary
+		if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(summary) != 1 || summary[0].Outcome != outcomeError || summary[0].Error == "" {
+			t.Fatalf("Expected a single error in the summary, got: %+v", summary)
+		}
+	})
+}
+
+func TestUninjectSummary(t *testing.T) {
+	options := &injectOptions{summary: summaryOptions{format: "json", failIfNoChange: true}}
+
+	inputs := openTestInputs(t, "inject_emojivoto_deployment.golden.yml", "inject_emojivoto_deployment.input.yml")
+	inputs = append(inputs, strings.NewReader(summaryServiceYAML))
+
+	errBuffer := &bytes.Buffer{}
+	if exitCode := runUninjectCmd(inputs, errBuffer, &bytes.Buffer{}, options); exitCode != 0 {
+		t.Fatalf("Expected exit code to be 0 but got: %d", exitCode)
+	}
+
+	report := errBuffer.String()
+	summary := []resourceSummary{}
+	if err := json.Unmarshal([]byte(report[strings.Index(report, "["):]), &summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []resourceOutcome{outcomeUninjected, outcomeSkippedNotInjected, outcomeSkippedUnsupportedKind}
+	if len(summary) != len(expected) {
+		t.Fatalf("Expected %d resources in the summary, got: %+v", len(expected), summary)
+	}
+	for i, outcome := range expected {
+		if summary[i].Outcome != outcome {
+			t.Fatalf("test case %d: expected outcome %s, got %s", i, outcome, summary[i].Outcome)
+		}
+	}
+}
@@ -9,6 +9,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	batchV1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/yaml"
 )
 
 type injectYAML struct {
@@ -67,6 +71,17 @@ func TestUninjectAndInject(t *testing.T) {
 	noInitContainerOptions.linkerdVersion = "testinjectversion"
 	noInitContainerOptions.noInitContainer = true
 
+	proxyAwaitOptions := newInjectOptions()
+	proxyAwaitOptions.linkerdVersion = "testinjectversion"
+	proxyAwaitOptions.proxyAwait = true
+
+	iptablesOptions := newInjectOptions()
+	iptablesOptions.linkerdVersion = "testinjectversion"
+	iptablesOptions.ignoreInboundSubnets = []string{"10.0.0.0/8"}
+	iptablesOptions.ignoreOutboundSubnets = []string{"192.168.0.0/16", "172.16.0.0/12"}
+	iptablesOptions.ignoreMarks = []string{"0x4000/0x4000"}
+	iptablesOptions.iptablesMode = iptablesNftMode
+
 	testCases := []injectYAML{
 		{
 			inputFileName:     "inject_emojivoto_deployment.input.yml",
@@ -164,6 +179,18 @@ func TestUninjectAndInject(t *testing.T) {
 			reportFileName:    "inject_emojivoto_deployment.report",
 			testInjectOptions: noInitContainerOptions,
 		},
+		{
+			inputFileName:     "inject_emojivoto_deployment.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_proxy_await.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment.report",
+			testInjectOptions: proxyAwaitOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_deployment.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_iptables.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment.report",
+			testInjectOptions: iptablesOptions,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -247,6 +274,51 @@ func TestRunInjectCmd(t *testing.T) {
 	}
 }
 
+func TestRunInjectHelmPostRenderer(t *testing.T) {
+	testCases := []injectCmd{
+		{
+			inputFileName:        "inject_gettest_deployment.bad.input.yml",
+			stdErrGoldenFileName: "inject_gettest_deployment.bad.golden",
+			exitCode:             1,
+		},
+		{
+			inputFileName:        "inject_gettest_deployment.good.input.yml",
+			stdOutGoldenFileName: "inject_gettest_deployment.good.golden.yml",
+			exitCode:             0,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.inputFileName), func(t *testing.T) {
+			options := newInjectOptions()
+			options.linkerdVersion = "testinjectversion"
+			options.helmPostRenderer = true
+
+			in, err := os.Open(fmt.Sprintf("testdata/%s", tc.inputFileName))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			errBuffer := &bytes.Buffer{}
+			outBuffer := &bytes.Buffer{}
+			exitCode := runInjectHelmPostRenderer(in, errBuffer, outBuffer, options)
+			if exitCode != tc.exitCode {
+				t.Fatalf("Expected exit code to be %d but got: %d", tc.exitCode, exitCode)
+			}
+
+			expectedStdOutResult := readOptionalTestFile(t, tc.stdOutGoldenFileName)
+			if actual := outBuffer.String(); expectedStdOutResult != actual {
+				t.Errorf("Result mismatch.\nExpected: %s\nActual: %s", expectedStdOutResult, actual)
+			}
+
+			expectedStdErrResult := readOptionalTestFile(t, tc.stdErrGoldenFileName)
+			if actual := errBuffer.String(); expectedStdErrResult != actual {
+				t.Errorf("Result mismatch.\nExpected: %s\nActual: %s", expectedStdErrResult, actual)
+			}
+		})
+	}
+}
+
 type injectFilePath struct {
 	resource     string
 	resourceFile string
@@ -393,3 +465,59 @@ func TestWalk(t *testing.T) {
 		}
 	}
 }
+
+func TestInjectJobShutdown(t *testing.T) {
+	job := func(annotations string) []byte {
+		return []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: emojivoto
+spec:
+  template:
+    metadata:` + annotations + `
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: buoyantio/emojivoto-web:v3
+`)
+	}
+
+	testCases := []struct {
+		input            []byte
+		proxyJobShutdown bool
+		expected         bool
+	}{
+		{job(""), false, false},
+		{job(""), true, true},
+		{job("\n      annotations:\n        " + k8s.ProxyJobShutdownAnnotation + ": enabled"), false, true},
+		{job("\n      annotations:\n        " + k8s.ProxyJobShutdownAnnotation + ": disabled"), true, false},
+	}
+
+	for i, tc := range testCases {
+		options := newInjectOptions()
+		options.proxyJobShutdown = tc.proxyJobShutdown
+
+		output, _, err := resourceTransformerInject{}.transform(tc.input, options)
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+
+		var injected batchV1.Job
+		if err := yaml.Unmarshal(output, &injected); err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+
+		spec := injected.Spec.Template.Spec
+		shareProcessNamespace := spec.ShareProcessNamespace != nil && *spec.ShareProcessNamespace
+		if shareProcessNamespace != tc.expected {
+			t.Fatalf("test case %d: expected shareProcessNamespace to be %t", i, tc.expected)
+		}
+		for _, container := range spec.Containers {
+			if container.Name == k8s.ProxyContainerName && (len(container.Command) > 0) != tc.expected {
+				t.Fatalf("test case %d: expected the proxy command to be overridden: %t, got %v", i, tc.expected, container.Command)
+			}
+		}
+	}
+}
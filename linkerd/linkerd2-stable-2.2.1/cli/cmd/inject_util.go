@@ -22,6 +22,7 @@ import (
 type resourceTransformer interface {
 	transform([]byte, *injectOptions) ([]byte, []injectReport, error)
 	generateReport([]injectReport, io.Writer)
+	outcome(injectReport) resourceOutcome
 }
 
 type injectReport struct {
@@ -29,7 +30,8 @@ type injectReport struct {
 	name                string
 	hostNetwork         bool
 	sidecar             bool
-	udp                 bool // true if any port in any container has `protocol: UDP`
+	udp                 bool     // true if any port in any container has `protocol: UDP`
+	autoSkippedPorts    []string // the detected ports of the autoSkipPorts, with their protocols
 	unsupportedResource bool
 	injectDisabled      bool
 }
@@ -52,13 +54,26 @@ func (i injectReport) resName() string {
 func transformInput(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions, rt resourceTransformer) int {
 	postInjectBuf := &bytes.Buffer{}
 	reportBuf := &bytes.Buffer{}
+	summary := &summaryOptions{}
+	if options != nil {
+		summary = &options.summary
+	}
+	reports := []injectReport{}
 
 	for _, input := range inputs {
-		err := ProcessYAML(input, postInjectBuf, reportBuf, options, rt)
+		irs, err := processYAML(input, postInjectBuf, reportBuf, options, rt)
 		if err != nil {
 			fmt.Fprintf(errWriter, "Error transforming resources: %v\n", err)
+			if summary.format != "" {
+				if summary.errorsOnly {
+					reports = []injectReport{}
+				}
+				renderSummary(summarize(rt, reports, err), summary.format, errWriter)
+			}
 			return 1
 		}
+		reports = append(reports, irs...)
+
 		_, err = io.Copy(outWriter, postInjectBuf)
 
 		// print error report after yaml output, for better visibility
@@ -69,11 +84,20 @@ func transformInput(inputs []io.Reader, errWriter, outWriter io.Writer, options
 			return 1
 		}
 	}
-	return 0
+	if summary.errorsOnly {
+		return 0
+	}
+	return reportSummary(errWriter, summary, summarize(rt, reports, nil))
 }
 
 // ProcessYAML takes an input stream of YAML, outputting injected/uninjected YAML to out.
 func ProcessYAML(in io.Reader, out io.Writer, report io.Writer, options *injectOptions, rt resourceTransformer) error {
+	_, err := processYAML(in, out, report, options, rt)
+	return err
+}
+
+// processYAML is ProcessYAML, also returning the reports of the resources.
+func processYAML(in io.Reader, out io.Writer, report io.Writer, options *injectOptions, rt resourceTransformer) ([]injectReport, error) {
 	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
 
 	injectReports := []injectReport{}
@@ -86,12 +110,12 @@ func ProcessYAML(in io.Reader, out io.Writer, report io.Writer, options *injectO
 			break
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		result, irs, err := rt.transform(bytes, options)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		out.Write(result)
@@ -102,7 +126,7 @@ func ProcessYAML(in io.Reader, out io.Writer, report io.Writer, options *injectO
 
 	rt.generateReport(injectReports, report)
 
-	return nil
+	return injectReports, nil
 }
 
 func processList(b []byte, options *injectOptions, rt resourceTransformer) ([]byte, []injectReport, error) {
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -23,47 +27,190 @@ type installCNIPluginConfig struct {
 	OutboundPort        uint
 	IgnoreInboundPorts  string
 	IgnoreOutboundPorts string
-	ProxyUID            int64
-	DestCNINetDir       string
-	DestCNIBinDir       string
-	CreatedByAnnotation string
-	CliVersion          string
+	// the subnets and marks are JSON arrays, empty if there are none
+	IgnoreInboundSubnets  string
+	IgnoreOutboundSubnets string
+	IgnoreMarks           string
+	IptablesMode          string
+	ProxyUID              int64
+	DestCNINetDir         string
+	DestCNIBinDir         string
+	HostNetwork           bool
+	PriorityClassName     string
+	Tolerations           []cniToleration
+	Privileged            bool
+	CreatedByAnnotation   string
+	CliVersion            string
+}
+
+// cniToleration is a toleration of the pods of the CNI plugin DaemonSet, so
+// that it also runs on the tainted nodes.
+type cniToleration struct {
+	Key      string
+	Operator string
+	Value    string
+	Effect   string
 }
 
 type cniPluginOptions struct {
-	linkerdVersion      string
-	dockerRegistry      string
-	proxyControlPort    uint
-	proxyMetricsPort    uint
-	inboundPort         uint
-	outboundPort        uint
-	ignoreInboundPorts  []uint
-	ignoreOutboundPorts []uint
-	proxyUID            int64
-	cniPluginImage      string
-	logLevel            string
-	destCNINetDir       string
-	destCNIBinDir       string
+	linkerdVersion        string
+	dockerRegistry        string
+	proxyControlPort      uint
+	proxyMetricsPort      uint
+	inboundPort           uint
+	outboundPort          uint
+	ignoreInboundPorts    []string
+	ignoreOutboundPorts   []string
+	ignoreInboundSubnets  []string
+	ignoreOutboundSubnets []string
+	ignoreMarks           []string
+	iptablesMode          string
+	proxyUID              int64
+	cniPluginImage        string
+	logLevel              string
+	destCNINetDir         string
+	destCNIBinDir         string
+	platform              string
+	hostNetwork           bool
+	priorityClassName     string
+	tolerations           []string
+	privileged            bool
+}
+
+// cniPlatform holds the settings the CNI plugin needs on a distribution of
+// Kubernetes, which the flags of install-cni override.
+type cniPlatform struct {
+	destCNINetDir string
+	destCNIBinDir string
+	hostNetwork   bool
+	tolerations   []string
+	privileged    bool
+}
+
+// cniPlatforms are the distributions of Kubernetes whose settings --platform
+// applies. Without --platform, the defaults fit vanilla kubeadm clusters.
+var cniPlatforms = map[string]cniPlatform{
+	"gke": {
+		destCNINetDir: "/etc/cni/net.d",
+		destCNIBinDir: "/home/kubernetes/bin",
+		hostNetwork:   true,
+		tolerations:   []string{"*"},
+	},
+	"eks": {
+		destCNINetDir: "/etc/cni/net.d",
+		destCNIBinDir: "/opt/cni/bin",
+		hostNetwork:   true,
+		tolerations:   []string{"*"},
+	},
+	"openshift": {
+		destCNINetDir: "/etc/cni/multus/net.d",
+		destCNIBinDir: "/var/lib/cni/bin",
+		hostNetwork:   true,
+		tolerations:   []string{"*"},
+		privileged:    true,
+	},
+	"k3s": {
+		destCNINetDir: "/var/lib/rancher/k3s/agent/etc/cni/net.d",
+		destCNIBinDir: "/var/lib/rancher/k3s/data/current/bin",
+		hostNetwork:   true,
+		tolerations:   []string{"*"},
+	},
+}
+
+// criticalPriorityClasses can only be used in the kube-system namespace.
+var criticalPriorityClasses = map[string]bool{
+	"system-cluster-critical": true,
+	"system-node-critical":    true,
 }
 
 func newCNIPluginOptions() *cniPluginOptions {
 	return &cniPluginOptions{
-		linkerdVersion:      version.Version,
-		dockerRegistry:      defaultDockerRegistry,
-		proxyControlPort:    4190,
-		proxyMetricsPort:    4191,
-		inboundPort:         4143,
-		outboundPort:        4140,
-		ignoreInboundPorts:  nil,
-		ignoreOutboundPorts: nil,
-		proxyUID:            2102,
-		cniPluginImage:      defaultDockerRegistry + "/cni-plugin",
-		logLevel:            "info",
-		destCNINetDir:       "/etc/cni/net.d",
-		destCNIBinDir:       "/opt/cni/bin",
+		linkerdVersion:        version.Version,
+		dockerRegistry:        defaultDockerRegistry,
+		proxyControlPort:      4190,
+		proxyMetricsPort:      4191,
+		inboundPort:           4143,
+		outboundPort:          4140,
+		ignoreInboundPorts:    nil,
+		ignoreOutboundPorts:   nil,
+		ignoreInboundSubnets:  nil,
+		ignoreOutboundSubnets: nil,
+		ignoreMarks:           nil,
+		iptablesMode:          iptablesLegacyMode,
+		proxyUID:              2102,
+		cniPluginImage:        defaultDockerRegistry + "/cni-plugin",
+		logLevel:              "info",
+		destCNINetDir:         "/etc/cni/net.d",
+		destCNIBinDir:         "/opt/cni/bin",
+		platform:              "",
+		hostNetwork:           true,
+		priorityClassName:     "",
+		tolerations:           nil,
+		privileged:            false,
 	}
 }
 
+// applyPlatform sets the options of the --platform preset whose flags weren't
+// changed, so that the flags override the preset.
+func (options *cniPluginOptions) applyPlatform(changed func(flag string) bool) error {
+	if options.platform == "" {
+		return nil
+	}
+	platform, ok := cniPlatforms[options.platform]
+	if !ok {
+		return fmt.Errorf("--platform must be one of: %s", strings.Join(cniPlatformNames(), ", "))
+	}
+
+	if !changed("dest-cni-net-dir") {
+		options.destCNINetDir = platform.destCNINetDir
+	}
+	if !changed("dest-cni-bin-dir") {
+		options.destCNIBinDir = platform.destCNIBinDir
+	}
+	if !changed("host-network") {
+		options.hostNetwork = platform.hostNetwork
+	}
+	if !changed("toleration") {
+		options.tolerations = append([]string{}, platform.tolerations...)
+	}
+	if !changed("privileged") {
+		options.privileged = platform.privileged
+	}
+	return nil
+}
+
+func cniPlatformNames() []string {
+	names := []string{}
+	for name := range cniPlatforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseCNIToleration parses a toleration in the format of the taints of
+// kubectl, key[=value][:effect], or "*" to tolerate all the taints.
+func parseCNIToleration(toleration string) (cniToleration, error) {
+	if toleration == "*" {
+		return cniToleration{Operator: "Exists"}, nil
+	}
+
+	t := cniToleration{Key: toleration, Operator: "Exists"}
+	if i := strings.LastIndex(t.Key, ":"); i >= 0 {
+		t.Key, t.Effect = t.Key[:i], t.Key[i+1:]
+		if t.Effect != "NoSchedule" && t.Effect != "PreferNoSchedule" && t.Effect != "NoExecute" {
+			return cniToleration{}, fmt.Errorf("Invalid toleration %s: the effect must be one of: NoSchedule, PreferNoSchedule, NoExecute", toleration)
+		}
+	}
+	if i := strings.Index(t.Key, "="); i >= 0 {
+		t.Key, t.Value, t.Operator = t.Key[:i], t.Key[i+1:], "Equal"
+	}
+	if t.Key == "" {
+		return cniToleration{}, fmt.Errorf("Invalid toleration %s: the key must not be empty", toleration)
+	}
+	return t, nil
+}
+
 func (options *cniPluginOptions) validate() error {
 	if !alphaNumDashDot.MatchString(options.linkerdVersion) {
 		return fmt.Errorf("%s is not a valid version", options.linkerdVersion)
@@ -77,7 +224,24 @@ func (options *cniPluginOptions) validate() error {
 		return fmt.Errorf("--cni-log-level must be one of: panic, fatal, error, warn, info, debug")
 	}
 
-	return nil
+	for flag, dir := range map[string]string{
+		"--dest-cni-net-dir": options.destCNINetDir,
+		"--dest-cni-bin-dir": options.destCNIBinDir,
+	} {
+		if !path.IsAbs(dir) {
+			return fmt.Errorf("%s must be an absolute path, was %s", flag, dir)
+		}
+	}
+
+	if criticalPriorityClasses[options.priorityClassName] && controlPlaneNamespace != "kube-system" {
+		return fmt.Errorf("--priority-class-name %s can only be used in the kube-system namespace", options.priorityClassName)
+	}
+
+	if err := validateSkipPorts(options.ignoreInboundPorts, options.ignoreOutboundPorts); err != nil {
+		return err
+	}
+
+	return validateIptablesOptions(options.iptablesMode, options.ignoreInboundSubnets, options.ignoreOutboundSubnets, options.ignoreMarks)
 }
 
 func (options *cniPluginOptions) taggedCNIPluginImage() string {
@@ -97,9 +261,21 @@ This command installs a DaemonSet into the Linkerd control plane. The DaemonSet
 copies the necessary linkerd-cni plugin binaries and configs onto the host. It
 assumes that the 'linkerd install' command will be executed with the '--linkerd-cni-enabled'
 flag. This command needs to be executed before the 'linkerd install --linkerd-cni-enabled'
-command.`,
+command.
+
+The defaults fit vanilla kubeadm clusters. The --platform flag presets the CNI
+directories, host network, tolerations and privileges of the DaemonSet for
+other distributions of Kubernetes, which the other flags override.`,
+		Example: `  # Install the CNI plugin on GKE, also on the tainted nodes.
+  linkerd install-cni --platform gke | kubectl apply -f -
+
+  # Install the CNI plugin on k3s, only on the untainted nodes.
+  linkerd install-cni --platform k3s --toleration="" | kubectl apply -f -`,
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.applyPlatform(cmd.Flags().Changed); err != nil {
+				return err
+			}
 			config, err := validateAndBuildCNIConfig(options)
 			if err != nil {
 				return err
@@ -115,12 +291,21 @@ command.`,
 	cmd.PersistentFlags().UintVar(&options.outboundPort, "outbound-port", options.outboundPort, "Proxy port to use for outbound traffic")
 	cmd.PersistentFlags().UintVar(&options.proxyControlPort, "control-port", options.proxyControlPort, "Proxy port to use for control")
 	cmd.PersistentFlags().UintVar(&options.proxyMetricsPort, "metrics-port", options.proxyMetricsPort, "Proxy port to serve metrics on")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, "Ports that should skip the proxy and send directly to the application")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, "Outbound ports that should skip the proxy")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, skipInboundPortsUsage)
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, skipOutboundPortsUsage)
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreInboundSubnets, "skip-inbound-subnets", options.ignoreInboundSubnets, "CIDRs of the sources whose traffic should skip the proxy and be sent directly to the application")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreOutboundSubnets, "skip-outbound-subnets", options.ignoreOutboundSubnets, "CIDRs of the destinations the outbound traffic should skip the proxy to")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreMarks, "skip-marks", options.ignoreMarks, "Firewall marks, as value[/mask], of the traffic that should skip the proxy")
+	cmd.PersistentFlags().StringVar(&options.iptablesMode, "iptables-mode", options.iptablesMode, "Variant of iptables the CNI plugin configures the rules with: \"legacy\", or \"nft\" for the nodes whose rules are managed with nftables")
 	cmd.PersistentFlags().StringVar(&options.cniPluginImage, "cni-image", options.cniPluginImage, "Image for the cni-plugin.")
 	cmd.PersistentFlags().StringVar(&options.logLevel, "cni-log-level", options.logLevel, "Log level for the cni-plugin.")
 	cmd.PersistentFlags().StringVar(&options.destCNINetDir, "dest-cni-net-dir", options.destCNINetDir, "Directory on the host where the CNI configuration will be placed.")
 	cmd.PersistentFlags().StringVar(&options.destCNIBinDir, "dest-cni-bin-dir", options.destCNIBinDir, "Directory on the host where the CNI plugin binaries reside.")
+	cmd.PersistentFlags().StringVar(&options.platform, "platform", options.platform, fmt.Sprintf("Distribution of Kubernetes to preset the flags of the CNI plugin for, one of: %s (default \"\", which fits vanilla kubeadm clusters)", strings.Join(cniPlatformNames(), ", ")))
+	cmd.PersistentFlags().BoolVar(&options.hostNetwork, "host-network", options.hostNetwork, "Run the CNI plugin pods in the network namespace of their hosts.")
+	cmd.PersistentFlags().StringVar(&options.priorityClassName, "priority-class-name", options.priorityClassName, "Priority class of the CNI plugin pods; the system-node-critical and system-cluster-critical classes require --linkerd-namespace=kube-system.")
+	cmd.PersistentFlags().StringSliceVar(&options.tolerations, "toleration", options.tolerations, "Taints of the nodes, as key[=value][:effect], that the CNI plugin pods tolerate, or \"*\" for all of them.")
+	cmd.PersistentFlags().BoolVar(&options.privileged, "privileged", options.privileged, "Run the CNI plugin containers as privileged, as required by e.g. OpenShift.")
 
 	return cmd
 }
@@ -134,30 +319,74 @@ func validateAndBuildCNIConfig(options *cniPluginOptions) (*installCNIPluginConf
 		fmt.Sprintf("%d", options.proxyControlPort),
 		fmt.Sprintf("%d", options.proxyMetricsPort),
 	}
-	for _, p := range options.ignoreInboundPorts {
-		ignoreInboundPorts = append(ignoreInboundPorts, fmt.Sprintf("%d", p))
+	ignoreInboundPorts = append(ignoreInboundPorts, formatPortRanges(skipPorts(options.ignoreInboundPorts))...)
+	ignoreOutboundPorts := formatPortRanges(skipPorts(options.ignoreOutboundPorts))
+
+	// the default mode is left out of the network configuration
+	iptablesMode := ""
+	if options.iptablesMode != iptablesLegacyMode {
+		iptablesMode = options.iptablesMode
 	}
-	ignoreOutboundPorts := []string{}
-	for _, p := range options.ignoreOutboundPorts {
-		ignoreOutboundPorts = append(ignoreOutboundPorts, fmt.Sprintf("%d", p))
+
+	tolerations := []cniToleration{}
+	for _, t := range options.tolerations {
+		if t == "" {
+			continue
+		}
+		toleration, err := parseCNIToleration(t)
+		if err != nil {
+			return nil, err
+		}
+		tolerations = append(tolerations, toleration)
 	}
 
 	return &installCNIPluginConfig{
-		Namespace:           controlPlaneNamespace,
-		CNIPluginImage:      options.taggedCNIPluginImage(),
-		LogLevel:            options.logLevel,
-		InboundPort:         options.inboundPort,
-		OutboundPort:        options.outboundPort,
-		IgnoreInboundPorts:  strings.Join(ignoreInboundPorts, ","),
-		IgnoreOutboundPorts: strings.Join(ignoreOutboundPorts, ","),
-		ProxyUID:            options.proxyUID,
-		DestCNINetDir:       options.destCNINetDir,
-		DestCNIBinDir:       options.destCNIBinDir,
-		CreatedByAnnotation: k8s.CreatedByAnnotation,
-		CliVersion:          k8s.CreatedByAnnotationValue(),
+		Namespace:             controlPlaneNamespace,
+		CNIPluginImage:        options.taggedCNIPluginImage(),
+		LogLevel:              options.logLevel,
+		InboundPort:           options.inboundPort,
+		OutboundPort:          options.outboundPort,
+		IgnoreInboundPorts:    cniPorts(ignoreInboundPorts),
+		IgnoreOutboundPorts:   cniPorts(ignoreOutboundPorts),
+		IgnoreInboundSubnets:  jsonArray(options.ignoreInboundSubnets),
+		IgnoreOutboundSubnets: jsonArray(options.ignoreOutboundSubnets),
+		IgnoreMarks:           jsonArray(options.ignoreMarks),
+		IptablesMode:          iptablesMode,
+		ProxyUID:              options.proxyUID,
+		DestCNINetDir:         options.destCNINetDir,
+		DestCNIBinDir:         options.destCNIBinDir,
+		HostNetwork:           options.hostNetwork,
+		PriorityClassName:     options.priorityClassName,
+		Tolerations:           tolerations,
+		Privileged:            options.privileged,
+		CreatedByAnnotation:   k8s.CreatedByAnnotation,
+		CliVersion:            k8s.CreatedByAnnotationValue(),
 	}, nil
 }
 
+// jsonArray returns the values as a JSON array for the CNI network
+// configuration, or an empty string if there are none.
+// cniPorts returns the elements of the JSON arrays of ports of the network
+// configuration, where the ports are numbers and the ranges of ports strings.
+func cniPorts(ports []string) string {
+	values := make([]string, len(ports))
+	for i, port := range ports {
+		if strings.Contains(port, "-") {
+			port = strconv.Quote(port)
+		}
+		values[i] = port
+	}
+	return strings.Join(values, ",")
+}
+
+func jsonArray(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
 func renderCNIPlugin(w io.Writer, config *installCNIPluginConfig) error {
 	template, err := template.New("linkerd-cni").Parse(install.CNITemplate)
 	if err != nil {
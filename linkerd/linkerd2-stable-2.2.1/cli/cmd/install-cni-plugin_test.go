@@ -16,19 +16,23 @@ func TestRenderCNIPlugin(t *testing.T) {
 	}
 
 	fullyConfiguredOptions := cniPluginOptions{
-		linkerdVersion:      "awesome-linkerd-version.1",
-		dockerRegistry:      "gcr.io/linkerd-io",
-		proxyControlPort:    5190,
-		proxyMetricsPort:    5191,
-		inboundPort:         5143,
-		outboundPort:        5140,
-		ignoreInboundPorts:  make([]uint, 0),
-		ignoreOutboundPorts: make([]uint, 0),
-		proxyUID:            12102,
-		cniPluginImage:      "my-docker-registry.io/awesome/cni-plugin-test-image",
-		logLevel:            "debug",
-		destCNINetDir:       "/etc/kubernetes/cni/net.d",
-		destCNIBinDir:       "/opt/my-cni/bin",
+		linkerdVersion:        "awesome-linkerd-version.1",
+		dockerRegistry:        "gcr.io/linkerd-io",
+		proxyControlPort:      5190,
+		proxyMetricsPort:      5191,
+		inboundPort:           5143,
+		outboundPort:          5140,
+		ignoreInboundPorts:    make([]string, 0),
+		ignoreOutboundPorts:   []string{"mysql", "4000-4100"},
+		proxyUID:              12102,
+		cniPluginImage:        "my-docker-registry.io/awesome/cni-plugin-test-image",
+		logLevel:              "debug",
+		destCNINetDir:         "/etc/kubernetes/cni/net.d",
+		destCNIBinDir:         "/opt/my-cni/bin",
+		hostNetwork:           true,
+		ignoreOutboundSubnets: []string{"10.0.0.0/8", "192.168.0.0/16"},
+		ignoreMarks:           []string{"0x4000/0x4000"},
+		iptablesMode:          iptablesNftMode,
 	}
 	fullyConfiguredConfig, err := validateAndBuildCNIConfig(&fullyConfiguredOptions)
 	if err != nil {
@@ -43,13 +47,15 @@ func TestRenderCNIPlugin(t *testing.T) {
 		proxyMetricsPort:    5191,
 		inboundPort:         5143,
 		outboundPort:        5140,
-		ignoreInboundPorts:  make([]uint, 0),
-		ignoreOutboundPorts: make([]uint, 0),
+		ignoreInboundPorts:  make([]string, 0),
+		ignoreOutboundPorts: make([]string, 0),
 		proxyUID:            12102,
 		cniPluginImage:      "my-docker-registry.io/awesome/cni-plugin-test-image",
 		logLevel:            "debug",
 		destCNINetDir:       "/etc/kubernetes/cni/net.d",
 		destCNIBinDir:       "/etc/kubernetes/cni/net.d",
+		hostNetwork:         true,
+		iptablesMode:        iptablesLegacyMode,
 	}
 	fullyConfiguredConfigEqualDsts, err := validateAndBuildCNIConfig(&fullyConfiguredOptionsEqualDsts)
 	if err != nil {
@@ -57,6 +63,17 @@ func TestRenderCNIPlugin(t *testing.T) {
 	}
 	fullyConfiguredConfigEqualDsts.Namespace = "other"
 
+	openShiftOptions := newCNIPluginOptions()
+	openShiftOptions.platform = "openshift"
+	openShiftOptions.destCNIBinDir = "/opt/cni/bin"
+	openShiftOptions.priorityClassName = "linkerd-cni"
+	openShiftOptions.applyPlatform(func(flag string) bool { return flag == "dest-cni-bin-dir" })
+	openShiftOptions.tolerations = append(openShiftOptions.tolerations, "dedicated=mesh:NoSchedule")
+	openShiftConfig, err := validateAndBuildCNIConfig(openShiftOptions)
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildCNIConfig(): %v", err)
+	}
+
 	testCases := []struct {
 		*installCNIPluginConfig
 		namespace      string
@@ -65,6 +82,7 @@ func TestRenderCNIPlugin(t *testing.T) {
 		{defaultConfig, defaultControlPlaneNamespace, "testdata/install-cni-plugin_default.golden"},
 		{fullyConfiguredConfig, fullyConfiguredConfig.Namespace, "testdata/install-cni-plugin_fully_configured.golden"},
 		{fullyConfiguredConfigEqualDsts, fullyConfiguredConfigEqualDsts.Namespace, "testdata/install-cni-plugin_fully_configured_equal_dsts.golden"},
+		{openShiftConfig, defaultControlPlaneNamespace, "testdata/install-cni-plugin_openshift.golden"},
 	}
 
 	for i, tc := range testCases {
@@ -91,6 +109,89 @@ func TestRenderCNIPlugin(t *testing.T) {
 	controlPlaneNamespace = defaultControlPlaneNamespace
 }
 
+func TestApplyCNIPlatform(t *testing.T) {
+	options := newCNIPluginOptions()
+	options.platform = "gke"
+	options.hostNetwork = false
+	if err := options.applyPlatform(func(flag string) bool { return flag == "host-network" }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if options.destCNIBinDir != "/home/kubernetes/bin" || options.destCNINetDir != "/etc/cni/net.d" {
+		t.Fatalf("Expected the CNI directories of GKE, got %s and %s", options.destCNIBinDir, options.destCNINetDir)
+	}
+	if options.hostNetwork {
+		t.Fatalf("Expected --host-network to override the preset")
+	}
+
+	options.platform = "minikube"
+	expected := "--platform must be one of: eks, gke, k3s, openshift"
+	if err := options.applyPlatform(func(string) bool { return false }); err == nil || err.Error() != expected {
+		t.Fatalf("Expected error [%s], got [%v]", expected, err)
+	}
+}
+
+func TestParseCNIToleration(t *testing.T) {
+	testCases := []struct {
+		toleration    string
+		expected      cniToleration
+		expectedError string
+	}{
+		{"*", cniToleration{Operator: "Exists"}, ""},
+		{"dedicated", cniToleration{Key: "dedicated", Operator: "Exists"}, ""},
+		{"dedicated:NoSchedule", cniToleration{Key: "dedicated", Operator: "Exists", Effect: "NoSchedule"}, ""},
+		{"dedicated=mesh:NoExecute", cniToleration{Key: "dedicated", Operator: "Equal", Value: "mesh", Effect: "NoExecute"}, ""},
+		{"dedicated=mesh:Never", cniToleration{}, "Invalid toleration dedicated=mesh:Never: the effect must be one of: NoSchedule, PreferNoSchedule, NoExecute"},
+		{"=mesh", cniToleration{}, "Invalid toleration =mesh: the key must not be empty"},
+	}
+
+	for i, tc := range testCases {
+		toleration, err := parseCNIToleration(tc.toleration)
+		if tc.expectedError != "" {
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if toleration != tc.expected {
+			t.Fatalf("test case %d: expected toleration %+v, got %+v", i, tc.expected, toleration)
+		}
+	}
+}
+
+func TestValidateCNIPluginOptions(t *testing.T) {
+	testCases := []struct {
+		configure func(*cniPluginOptions)
+		expected  string
+	}{
+		{
+			func(options *cniPluginOptions) { options.destCNIBinDir = "bin" },
+			"--dest-cni-bin-dir must be an absolute path, was bin",
+		},
+		{
+			func(options *cniPluginOptions) { options.priorityClassName = "system-node-critical" },
+			"--priority-class-name system-node-critical can only be used in the kube-system namespace",
+		},
+		{
+			func(options *cniPluginOptions) { options.ignoreInboundSubnets = []string{"10.0.0.0"} },
+			"Invalid CIDR '10.0.0.0' for --skip-inbound-subnets flag",
+		},
+	}
+
+	for i, tc := range testCases {
+		options := newCNIPluginOptions()
+		tc.configure(options)
+
+		err := options.validate()
+		if err == nil || err.Error() != tc.expected {
+			t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+		}
+	}
+}
+
 func teardown(originalNamespace string, t *testing.T) {
 	controlPlaneNamespace = originalNamespace
 }
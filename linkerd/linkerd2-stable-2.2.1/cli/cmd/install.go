@@ -2,18 +2,27 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/linkerd/linkerd2/cli/static"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	k8sResource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/renderutil"
@@ -27,8 +36,19 @@ type installConfig struct {
 	WebImage                         string
 	PrometheusImage                  string
 	PrometheusVolumeName             string
+	PrometheusRetention              string
+	PrometheusStorageSize            string
+	PrometheusStorageClass           string
+	PrometheusScrapeInterval         string
+	PrometheusScrapeTimeout          string
 	GrafanaImage                     string
 	GrafanaVolumeName                string
+	GrafanaDashboards                map[string]string
+	GrafanaDashboardsConfigMap       string
+	GrafanaDatasources               map[string]string
+	GrafanaDatasourcesConfigMap      string
+	DisableGrafana                   bool
+	GrafanaURL                       string
 	ControllerReplicas               uint
 	ImagePullPolicy                  string
 	UUID                             string
@@ -38,6 +58,11 @@ type installConfig struct {
 	CreatedByAnnotation              string
 	ProxyAPIPort                     uint
 	EnableTLS                        bool
+	RequireTLS                       bool
+	ProxyTLSRequiredEnvVar           string
+	IdentityIssuerSecret             string
+	IdentityIssuerVolumeName         string
+	IdentityIssuerMountPath          string
 	TLSTrustAnchorVolumeName         string
 	TLSSecretsVolumeName             string
 	TLSTrustAnchorConfigMapName      string
@@ -51,10 +76,15 @@ type installConfig struct {
 	OutboundPort                     uint
 	IgnoreInboundPorts               string
 	IgnoreOutboundPorts              string
+	IgnoreInboundSubnets             string
+	IgnoreOutboundSubnets            string
+	IgnoreMarks                      string
+	IptablesMode                     string
 	InboundAcceptKeepaliveMs         uint
 	OutboundConnectKeepaliveMs       uint
 	ProxyAutoInjectEnabled           bool
 	ProxyInjectAnnotation            string
+	ProxyInjectLabel                 string
 	ProxyInjectDisabled              string
 	ProxyLogLevel                    string
 	ProxyUID                         int64
@@ -72,6 +102,28 @@ type installConfig struct {
 	ProfileSuffixes                  string
 	EnableH2Upgrade                  bool
 	NoInitContainer                  bool
+	ProxyInjectorFailurePolicy       string
+	ProxyInjectorTimeoutSeconds      uint
+	ProxyInjectorReinvocationPolicy  string
+	ProxyConfig                      string
+	EnableHeartbeat                  bool
+	HeartbeatSchedule                string
+	HeartbeatEndpoint                string
+	DashboardReadOnly                bool
+	PrometheusRecordingRules         bool
+	ControllerResources              installResources
+	WebResources                     installResources
+	PrometheusResources              installResources
+	GrafanaResources                 installResources
+}
+
+// installResources holds the resource requests and limits of the containers
+// of a control plane component. The empty values are left unset.
+type installResources struct {
+	RequestCPU    string
+	RequestMemory string
+	LimitCPU      string
+	LimitMemory   string
 }
 
 // installOptions holds values for command line flags that apply to the install
@@ -87,6 +139,36 @@ type installOptions struct {
 	highAvailability   bool
 	controllerUID      int64
 	disableH2Upgrade   bool
+
+	identityIssuerSecret string
+
+	proxyInjectorFailurePolicy      string
+	proxyInjectorTimeoutSeconds     uint
+	proxyInjectorReinvocationPolicy string
+
+	enableHeartbeat          bool
+	heartbeatEndpoint        string
+	dashboardReadOnly        bool
+	prometheusRecordingRules bool
+	prometheusRetention      time.Duration
+	prometheusStorageSize    string
+	prometheusStorageClass   string
+	prometheusScrapeInterval time.Duration
+
+	grafanaDashboardsDir        string
+	grafanaDashboardsConfigMap  string
+	grafanaDatasourcesDir       string
+	grafanaDatasourcesConfigMap string
+	disableGrafana              bool
+	grafanaURL                  string
+
+	outputDir    string
+	outputFormat string
+
+	controllerResources installResources
+	webResources        installResources
+	prometheusResources installResources
+	grafanaResources    installResources
 	*proxyConfigOptions
 }
 
@@ -95,11 +177,52 @@ const (
 	defaultControllerReplicas       = 1
 	defaultHAControllerReplicas     = 3
 
+	defaultPrometheusRetention      = 6 * time.Hour
+	defaultPrometheusScrapeInterval = 10 * time.Second
+	// maxPrometheusScrapeTimeout is the longest Prometheus waits for a scrape,
+	// unless the scrape interval, which bounds it, is shorter.
+	maxPrometheusScrapeTimeout = 10 * time.Second
+
+	// maxWebhookTimeoutSeconds is the longest timeout the Kubernetes API
+	// server accepts for admission webhooks.
+	maxWebhookTimeoutSeconds = 30
+
 	baseTemplateName          = "templates/base.yaml"
 	tlsTemplateName           = "templates/tls.yaml"
 	proxyInjectorTemplateName = "templates/proxy_injector.yaml"
+	spValidatorTemplateName   = "templates/sp_validator.yaml"
+	heartbeatTemplateName     = "templates/heartbeat.yaml"
+
+	yamlOutput      = "yaml"
+	helmChartOutput = "helm-chart"
 )
 
+// chartTemplateConditions are the conditions under which render includes the
+// templates, which the Helm chart written with --output helm-chart evaluates
+// itself.
+var chartTemplateConditions = map[string]string{
+	tlsTemplateName:           ".Values.EnableTLS",
+	proxyInjectorTemplateName: ".Values.ProxyAutoInjectEnabled",
+	spValidatorTemplateName:   "not .Values.SingleNamespace",
+	heartbeatTemplateName:     "and (not .Values.SingleNamespace) .Values.EnableHeartbeat .Values.HeartbeatEndpoint",
+}
+
+// installComponents are the components the install manifests are split into
+// with --output-dir, in the order they are applied.
+var installComponents = []string{
+	"namespace",
+	"crds",
+	"rbac",
+	"controller",
+	"web",
+	"prometheus",
+	"grafana",
+	"ca",
+	"proxy-injector",
+	"sp-validator",
+	"heartbeat",
+}
+
 func newInstallOptions() *installOptions {
 	return &installOptions{
 		controllerReplicas: defaultControllerReplicas,
@@ -109,7 +232,27 @@ func newInstallOptions() *installOptions {
 		highAvailability:   false,
 		controllerUID:      2103,
 		disableH2Upgrade:   false,
-		proxyConfigOptions: newProxyConfigOptions(),
+
+		proxyInjectorFailurePolicy:      "Ignore",
+		proxyInjectorTimeoutSeconds:     0,
+		proxyInjectorReinvocationPolicy: "Never",
+		enableHeartbeat:                 false,
+		heartbeatEndpoint:               "",
+		dashboardReadOnly:               false,
+		prometheusRecordingRules:        false,
+		prometheusRetention:             defaultPrometheusRetention,
+		prometheusStorageSize:           "",
+		prometheusStorageClass:          "",
+		prometheusScrapeInterval:        defaultPrometheusScrapeInterval,
+		grafanaDashboardsDir:            "",
+		grafanaDashboardsConfigMap:      "",
+		grafanaDatasourcesDir:           "",
+		grafanaDatasourcesConfigMap:     "",
+		disableGrafana:                  false,
+		grafanaURL:                      "",
+		outputDir:                       "",
+		outputFormat:                    yamlOutput,
+		proxyConfigOptions:              newProxyConfigOptions(),
 	}
 }
 
@@ -117,15 +260,39 @@ func newCmdInstall() *cobra.Command {
 	options := newInstallOptions()
 
 	cmd := &cobra.Command{
-		Use:   "install [flags]",
+		Use:   "install [flags] [CHART DIR]",
 		Short: "Output Kubernetes configs to install Linkerd",
-		Long:  "Output Kubernetes configs to install Linkerd.",
+		Long: `Output Kubernetes configs to install Linkerd.
+
+With --output helm-chart, the install templates are written as a Helm chart to
+the CHART DIR argument instead, with a values.yaml holding the settings of the
+flags. The proxies of the control plane pods are not part of the chart
+templates, add them by piping the output of "helm template" to "linkerd inject".`,
+		Example: `  # Output the configs to install Linkerd.
+  linkerd install | kubectl apply -f -
+
+  # Write a Helm chart installing Linkerd with TLS to the linkerd-chart directory.
+  linkerd install --tls optional --output helm-chart linkerd-chart`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			config, err := validateAndBuildConfig(options)
 			if err != nil {
 				return err
 			}
 
+			if options.outputFormat == helmChartOutput {
+				if len(args) == 0 {
+					return errors.New("--output helm-chart requires the directory to write the chart to")
+				}
+				return renderHelmChart(*config, args[0])
+			}
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected argument %s, only --output helm-chart takes a directory", args[0])
+			}
+
+			if options.outputDir != "" {
+				return renderToDir(*config, options.outputDir, options)
+			}
 			return render(*config, os.Stdout, options)
 		},
 	}
@@ -138,10 +305,41 @@ func newCmdInstall() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(&options.highAvailability, "ha", options.highAvailability, "Experimental: Enable HA deployment config for the control plane (default false)")
 	cmd.PersistentFlags().Int64Var(&options.controllerUID, "controller-uid", options.controllerUID, "Run the control plane components under this user ID")
 	cmd.PersistentFlags().BoolVar(&options.disableH2Upgrade, "disable-h2-upgrade", options.disableH2Upgrade, "Prevents the controller from instructing proxies to perform transparent HTTP/2 upgrading (default false)")
+	cmd.PersistentFlags().StringVar(&options.identityIssuerSecret, "identity-issuer-secret", options.identityIssuerSecret, "Name of a kubernetes.io/tls secret in the control plane namespace, e.g. one managed by cert-manager, holding the CA certificate and key the TLS identities are issued with, instead of a self-signed CA; requires --tls")
+	cmd.PersistentFlags().StringVar(&options.proxyInjectorFailurePolicy, "proxy-injector-failure-policy", options.proxyInjectorFailurePolicy, "How pods are admitted when the proxy injector can't be reached or fails to inject them: \"Ignore\" admits them without a proxy, \"Fail\" rejects them")
+	cmd.PersistentFlags().UintVar(&options.proxyInjectorTimeoutSeconds, "proxy-injector-timeout-seconds", options.proxyInjectorTimeoutSeconds, "Seconds the Kubernetes API server waits for the proxy injector before applying its failure policy, at most 30 (default 0, which uses the API server's default)")
+	cmd.PersistentFlags().StringVar(&options.proxyInjectorReinvocationPolicy, "proxy-injector-reinvocation-policy", options.proxyInjectorReinvocationPolicy, "Whether the proxy injector is called again when other mutating webhooks change a pod after it was injected: \"Never\" or \"IfNeeded\"")
+	cmd.PersistentFlags().BoolVar(&options.enableHeartbeat, "enable-heartbeat", options.enableHeartbeat, "Enables the heartbeat CronJob, which posts anonymized statistics of the mesh, i.e. its version and counts of its pods and namespaces, to --heartbeat-endpoint once a day (default false)")
+	cmd.PersistentFlags().StringVar(&options.heartbeatEndpoint, "heartbeat-endpoint", options.heartbeatEndpoint, "URL the heartbeat CronJob posts the statistics of the mesh to; required with --enable-heartbeat")
+	cmd.PersistentFlags().BoolVar(&options.dashboardReadOnly, "dashboard-read-only", options.dashboardReadOnly, "Disables tap, top and the editing of the Grafana dashboards in the dashboard, which only serves the metrics, so that it can be exposed to a wider audience (default false)")
+	cmd.PersistentFlags().BoolVar(&options.prometheusRecordingRules, "prometheus-recording-rules", options.prometheusRecordingRules, "Installs Prometheus recording rules pre-aggregating the metrics of the proxies, which the public API queries for the stats over 1m instead of the raw metrics (default false)")
+	cmd.PersistentFlags().DurationVar(&options.prometheusRetention, "prometheus-retention", options.prometheusRetention, "How long Prometheus keeps the metrics it scrapes")
+	cmd.PersistentFlags().StringVar(&options.prometheusStorageSize, "prometheus-storage-size", options.prometheusStorageSize, "Size of a PersistentVolumeClaim Prometheus stores its metrics in, so that they survive its restarts, instead of an emptyDir volume, e.g. 10Gi")
+	cmd.PersistentFlags().StringVar(&options.prometheusStorageClass, "prometheus-storage-class", options.prometheusStorageClass, "Storage class of the PersistentVolumeClaim of Prometheus; requires --prometheus-storage-size (default \"\", which uses the default storage class of the cluster)")
+	cmd.PersistentFlags().DurationVar(&options.prometheusScrapeInterval, "prometheus-scrape-interval", options.prometheusScrapeInterval, "How often Prometheus scrapes the metrics of the proxies and the control plane")
+	cmd.PersistentFlags().StringVar(&options.grafanaDashboardsDir, "grafana-dashboards-dir", options.grafanaDashboardsDir, "Directory of Grafana dashboards, as .json files, that Grafana provisions along with the Linkerd dashboards")
+	cmd.PersistentFlags().StringVar(&options.grafanaDashboardsConfigMap, "grafana-dashboards-configmap", options.grafanaDashboardsConfigMap, "Name of a ConfigMap in the control plane namespace holding Grafana dashboards, as .json keys, that Grafana provisions along with the Linkerd dashboards")
+	cmd.PersistentFlags().StringVar(&options.grafanaDatasourcesDir, "grafana-datasources-dir", options.grafanaDatasourcesDir, "Directory of Grafana datasource provisioning files, as .yaml files, that Grafana provisions along with the Linkerd Prometheus")
+	cmd.PersistentFlags().StringVar(&options.grafanaDatasourcesConfigMap, "grafana-datasources-configmap", options.grafanaDatasourcesConfigMap, "Name of a ConfigMap in the control plane namespace holding a Grafana datasource provisioning file, as its datasources.yaml key, that Grafana provisions along with the Linkerd Prometheus")
+	cmd.PersistentFlags().BoolVar(&options.disableGrafana, "disable-grafana", options.disableGrafana, "Disables the bundled Grafana, e.g. in favor of an external one set with --grafana-url (default false)")
+	cmd.PersistentFlags().StringVar(&options.grafanaURL, "grafana-url", options.grafanaURL, "URL of an external Grafana, with the Linkerd dashboards, that the dashboard links to; requires --disable-grafana")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"yaml\" (default) and \"helm-chart\" are supported")
+	cmd.PersistentFlags().StringVar(&options.outputDir, "output-dir", options.outputDir, "Writes the configs of each component to its own file in this directory, e.g. 02-rbac.yaml, instead of to stdout")
+	addResourceFlags(cmd.PersistentFlags(), "controller", &options.controllerResources)
+	addResourceFlags(cmd.PersistentFlags(), "web", &options.webResources)
+	addResourceFlags(cmd.PersistentFlags(), "prometheus", &options.prometheusResources)
+	addResourceFlags(cmd.PersistentFlags(), "grafana", &options.grafanaResources)
 	return cmd
 }
 
 func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
+	if options.highAvailability {
+		options.controllerResources.setDefaultRequests("20m", "50Mi")
+		options.webResources.setDefaultRequests("20m", "50Mi")
+		options.prometheusResources.setDefaultRequests("300m", "300Mi")
+		options.grafanaResources.setDefaultRequests("20m", "50Mi")
+	}
+
 	if err := options.validate(); err != nil {
 		return nil, err
 	}
@@ -150,12 +348,13 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		fmt.Sprintf("%d", options.proxyControlPort),
 		fmt.Sprintf("%d", options.proxyMetricsPort),
 	}
-	for _, p := range options.ignoreInboundPorts {
-		ignoreInboundPorts = append(ignoreInboundPorts, fmt.Sprintf("%d", p))
-	}
-	ignoreOutboundPorts := []string{}
-	for _, p := range options.ignoreOutboundPorts {
-		ignoreOutboundPorts = append(ignoreOutboundPorts, fmt.Sprintf("%d", p))
+	ignoreInboundPorts = append(ignoreInboundPorts, formatPortRanges(skipPorts(options.ignoreInboundPorts))...)
+	ignoreOutboundPorts := formatPortRanges(skipPorts(options.ignoreOutboundPorts))
+
+	// the default mode isn't passed to proxy-init, as inject does
+	iptablesMode := ""
+	if options.iptablesMode != iptablesLegacyMode {
+		iptablesMode = options.iptablesMode
 	}
 
 	if options.highAvailability && options.controllerReplicas == defaultControllerReplicas {
@@ -175,14 +374,47 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		profileSuffixes = "svc.cluster.local."
 	}
 
+	proxyConfig, err := json.Marshal(newProxyConfig(options.proxyConfigOptions))
+	if err != nil {
+		return nil, err
+	}
+
+	var grafanaDashboards, grafanaDatasources map[string]string
+	if options.grafanaDashboardsDir != "" {
+		if grafanaDashboards, err = readGrafanaFiles(options.grafanaDashboardsDir, ".json"); err != nil {
+			return nil, err
+		}
+	}
+	if options.grafanaDatasourcesDir != "" {
+		if grafanaDatasources, err = readGrafanaFiles(options.grafanaDatasourcesDir, ".yaml", ".yml"); err != nil {
+			return nil, err
+		}
+	}
+
+	prometheusScrapeTimeout := options.prometheusScrapeInterval
+	if prometheusScrapeTimeout > maxPrometheusScrapeTimeout {
+		prometheusScrapeTimeout = maxPrometheusScrapeTimeout
+	}
+
 	return &installConfig{
 		Namespace:                        controlPlaneNamespace,
 		ControllerImage:                  fmt.Sprintf("%s/controller:%s", options.dockerRegistry, options.linkerdVersion),
 		WebImage:                         fmt.Sprintf("%s/web:%s", options.dockerRegistry, options.linkerdVersion),
 		PrometheusImage:                  "prom/prometheus:v2.4.0",
 		PrometheusVolumeName:             "data",
+		PrometheusRetention:              model.Duration(options.prometheusRetention).String(),
+		PrometheusStorageSize:            options.prometheusStorageSize,
+		PrometheusStorageClass:           options.prometheusStorageClass,
+		PrometheusScrapeInterval:         model.Duration(options.prometheusScrapeInterval).String(),
+		PrometheusScrapeTimeout:          model.Duration(prometheusScrapeTimeout).String(),
 		GrafanaImage:                     fmt.Sprintf("%s/grafana:%s", options.dockerRegistry, options.linkerdVersion),
 		GrafanaVolumeName:                "data",
+		GrafanaDashboards:                grafanaDashboards,
+		GrafanaDashboardsConfigMap:       options.grafanaDashboardsConfigMap,
+		GrafanaDatasources:               grafanaDatasources,
+		GrafanaDatasourcesConfigMap:      options.grafanaDatasourcesConfigMap,
+		DisableGrafana:                   options.disableGrafana,
+		GrafanaURL:                       options.grafanaURL,
 		ControllerReplicas:               options.controllerReplicas,
 		ImagePullPolicy:                  options.imagePullPolicy,
 		UUID:                             uuid.NewV4().String(),
@@ -193,6 +425,11 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		CreatedByAnnotation:              k8s.CreatedByAnnotation,
 		ProxyAPIPort:                     options.proxyAPIPort,
 		EnableTLS:                        options.enableTLS(),
+		RequireTLS:                       options.requireTLS(),
+		ProxyTLSRequiredEnvVar:           k8s.ProxyTLSRequiredEnvVar,
+		IdentityIssuerSecret:             options.identityIssuerSecret,
+		IdentityIssuerVolumeName:         k8s.IdentityIssuerVolumeName,
+		IdentityIssuerMountPath:          k8s.MountPathIdentityIssuer,
 		TLSTrustAnchorVolumeName:         k8s.TLSTrustAnchorVolumeName,
 		TLSSecretsVolumeName:             k8s.TLSSecretsVolumeName,
 		TLSTrustAnchorConfigMapName:      k8s.TLSTrustAnchorConfigMapName,
@@ -206,10 +443,15 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		OutboundPort:                     options.outboundPort,
 		IgnoreInboundPorts:               strings.Join(ignoreInboundPorts, ","),
 		IgnoreOutboundPorts:              strings.Join(ignoreOutboundPorts, ","),
+		IgnoreInboundSubnets:             strings.Join(options.ignoreInboundSubnets, ","),
+		IgnoreOutboundSubnets:            strings.Join(options.ignoreOutboundSubnets, ","),
+		IgnoreMarks:                      strings.Join(options.ignoreMarks, ","),
+		IptablesMode:                     iptablesMode,
 		InboundAcceptKeepaliveMs:         defaultKeepaliveMs,
 		OutboundConnectKeepaliveMs:       defaultKeepaliveMs,
 		ProxyAutoInjectEnabled:           options.proxyAutoInject,
 		ProxyInjectAnnotation:            k8s.ProxyInjectAnnotation,
+		ProxyInjectLabel:                 k8s.ProxyInjectLabel,
 		ProxyInjectDisabled:              k8s.ProxyInjectDisabled,
 		ProxyLogLevel:                    options.proxyLogLevel,
 		ProxyUID:                         options.proxyUID,
@@ -226,6 +468,19 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		ProfileSuffixes:                  profileSuffixes,
 		EnableH2Upgrade:                  !options.disableH2Upgrade,
 		NoInitContainer:                  options.noInitContainer,
+		ProxyInjectorFailurePolicy:       options.proxyInjectorFailurePolicy,
+		ProxyInjectorTimeoutSeconds:      options.proxyInjectorTimeoutSeconds,
+		ProxyInjectorReinvocationPolicy:  options.proxyInjectorReinvocationPolicy,
+		ProxyConfig:                      string(proxyConfig),
+		EnableHeartbeat:                  options.enableHeartbeat,
+		HeartbeatSchedule:                heartbeatSchedule(time.Now()),
+		HeartbeatEndpoint:                options.heartbeatEndpoint,
+		DashboardReadOnly:                options.dashboardReadOnly,
+		PrometheusRecordingRules:         options.prometheusRecordingRules,
+		ControllerResources:              options.controllerResources,
+		WebResources:                     options.webResources,
+		PrometheusResources:              options.prometheusResources,
+		GrafanaResources:                 options.grafanaResources,
 	}, nil
 }
 
@@ -254,12 +509,22 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 	if err != nil {
 		return err
 	}
+	spValidatorTmpl, err := readIntoBytes(spValidatorTemplateName)
+	if err != nil {
+		return err
+	}
+	heartbeatTmpl, err := readIntoBytes(heartbeatTemplateName)
+	if err != nil {
+		return err
+	}
 
 	files := []*chartutil.BufferedFile{
 		{Name: chartutil.ChartfileName, Data: chartTmpl},
 		{Name: baseTemplateName, Data: baseTmpl},
 		{Name: tlsTemplateName, Data: tlsTmpl},
 		{Name: proxyInjectorTemplateName, Data: proxyInjectorTmpl},
+		{Name: spValidatorTemplateName, Data: spValidatorTmpl},
+		{Name: heartbeatTemplateName, Data: heartbeatTmpl},
 	}
 
 	// Create chart and render templates
@@ -305,6 +570,25 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 		}
 	}
 
+	// ServiceProfiles are cluster-wide resources, and so is their validating
+	// webhook; neither are installed in single-namespace mode.
+	if !config.SingleNamespace {
+		st := path.Join(renderOpts.ReleaseOptions.Name, spValidatorTemplateName)
+		if _, err := buf.WriteString(renderedTemplates[st]); err != nil {
+			return err
+		}
+	}
+
+	// The heartbeat counts the pods of all the namespaces, which it can't
+	// list in single-namespace mode. It's only installed when it's enabled
+	// with an endpoint to post to.
+	if !config.SingleNamespace && config.EnableHeartbeat && config.HeartbeatEndpoint != "" {
+		ht := path.Join(renderOpts.ReleaseOptions.Name, heartbeatTemplateName)
+		if _, err := buf.WriteString(renderedTemplates[ht]); err != nil {
+			return err
+		}
+	}
+
 	injectOptions := newInjectOptions()
 	injectOptions.proxyConfigOptions = options.proxyConfigOptions
 
@@ -314,7 +598,171 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 	return InjectYAML(&buf, w, ioutil.Discard, injectOptions)
 }
 
+// renderToDir renders the install manifests and writes the documents of each
+// component to its own file in dir, prefixed with its position in
+// installComponents so that `kubectl apply -f` applies them in order.
+func renderToDir(config installConfig, dir string, options *installOptions) error {
+	var buf bytes.Buffer
+	if err := render(config, &buf, options); err != nil {
+		return err
+	}
+
+	components, err := splitInstallComponents(buf.String())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, component := range installComponents {
+		docs, ok := components[component]
+		if !ok {
+			continue
+		}
+		file := path.Join(dir, fmt.Sprintf("%02d-%s.yaml", i, component))
+		if err := ioutil.WriteFile(file, []byte(strings.Join(docs, "")), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderHelmChart writes the install templates to dir as a Helm chart, with
+// the given config as its values.
+func renderHelmChart(config installConfig, dir string) error {
+	values, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	chartfile, err := readIntoBytes(chartutil.ChartfileName)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		chartutil.ChartfileName:  chartfile,
+		chartutil.ValuesfileName: values,
+	}
+	for _, name := range []string{
+		baseTemplateName,
+		tlsTemplateName,
+		proxyInjectorTemplateName,
+		spValidatorTemplateName,
+		heartbeatTemplateName,
+	} {
+		tmpl, err := readIntoBytes(name)
+		if err != nil {
+			return err
+		}
+		if condition, ok := chartTemplateConditions[name]; ok {
+			tmpl = []byte(fmt.Sprintf("{{- if %s }}\n%s{{- end }}\n", condition, tmpl))
+		}
+		files[name] = tmpl
+	}
+
+	for name, data := range files {
+		file := path.Join(dir, name)
+		if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitInstallComponents groups the documents of the install manifests by the
+// component of installComponents they belong to. The section comments of the
+// templates are kept with the documents they precede.
+func splitInstallComponents(manifests string) (map[string][]string, error) {
+	docs := [][]string{}
+	doc := []string{}
+	for _, line := range strings.Split(manifests, "\n") {
+		if line == "---" {
+			docs = append(docs, doc)
+			doc = []string{}
+			continue
+		}
+		doc = append(doc, line)
+	}
+	docs = append(docs, doc)
+
+	components := map[string][]string{}
+	comments := []string{}
+	for _, lines := range docs {
+		lines = append(comments, lines...)
+
+		// move the section comments ending the document to the next one
+		end := len(lines)
+		for end > 0 && (strings.HasPrefix(lines[end-1], "### ") || strings.TrimSpace(lines[end-1]) == "") {
+			end--
+		}
+		comments = []string{}
+		for _, line := range lines[end:] {
+			if line != "" {
+				comments = append(comments, line)
+			}
+		}
+		lines = lines[:end]
+		if len(lines) == 0 {
+			continue
+		}
+
+		var meta struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		text := strings.Join(lines, "\n") + "\n"
+		if err := yaml.Unmarshal([]byte(text), &meta); err != nil {
+			return nil, err
+		}
+		if meta.Kind == "" {
+			continue
+		}
+
+		component := installComponent(meta.Kind, meta.Metadata.Name)
+		components[component] = append(components[component], "---\n"+text)
+	}
+
+	return components, nil
+}
+
+// installComponent returns the component of installComponents a resource of
+// the install manifests belongs to.
+func installComponent(kind, name string) string {
+	switch kind {
+	case "Namespace":
+		return "namespace"
+	case "CustomResourceDefinition":
+		return "crds"
+	case "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding":
+		return "rbac"
+	}
+
+	name = strings.TrimPrefix(name, "linkerd-")
+	for _, component := range installComponents {
+		if name == component || strings.HasPrefix(name, component+"-") {
+			return component
+		}
+	}
+	// the public API, destination and tap services, and the config of the
+	// control plane
+	return "controller"
+}
+
 func (options *installOptions) validate() error {
+	if options.outputFormat != yamlOutput && options.outputFormat != helmChartOutput {
+		return fmt.Errorf("--output must be one of: %s, %s", yamlOutput, helmChartOutput)
+	}
+
+	if options.outputFormat == helmChartOutput && options.outputDir != "" {
+		return fmt.Errorf("The --output helm-chart and --output-dir flags cannot both be specified together")
+	}
+
 	if _, err := log.ParseLevel(options.controllerLogLevel); err != nil {
 		return fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
 	}
@@ -323,9 +771,200 @@ func (options *installOptions) validate() error {
 		return fmt.Errorf("The --proxy-auto-inject and --single-namespace flags cannot both be specified together")
 	}
 
+	if options.identityIssuerSecret != "" && !options.enableTLS() {
+		return fmt.Errorf("--identity-issuer-secret requires --tls")
+	}
+
+	if options.proxyInjectorFailurePolicy != "Ignore" && options.proxyInjectorFailurePolicy != "Fail" {
+		return fmt.Errorf("--proxy-injector-failure-policy must be one of: Ignore, Fail")
+	}
+
+	if options.proxyInjectorTimeoutSeconds > maxWebhookTimeoutSeconds {
+		return fmt.Errorf("--proxy-injector-timeout-seconds must be at most %d", maxWebhookTimeoutSeconds)
+	}
+
+	if options.proxyInjectorReinvocationPolicy != "Never" && options.proxyInjectorReinvocationPolicy != "IfNeeded" {
+		return fmt.Errorf("--proxy-injector-reinvocation-policy must be one of: Never, IfNeeded")
+	}
+
+	if options.prometheusRetention <= 0 {
+		return fmt.Errorf("--prometheus-retention must be positive, was %s", options.prometheusRetention)
+	}
+
+	if options.prometheusScrapeInterval <= 0 {
+		return fmt.Errorf("--prometheus-scrape-interval must be positive, was %s", options.prometheusScrapeInterval)
+	}
+
+	if options.prometheusStorageSize != "" {
+		if _, err := k8sResource.ParseQuantity(options.prometheusStorageSize); err != nil {
+			return fmt.Errorf("Invalid storage size '%s' for --prometheus-storage-size flag", options.prometheusStorageSize)
+		}
+	} else if options.prometheusStorageClass != "" {
+		return fmt.Errorf("--prometheus-storage-class requires --prometheus-storage-size")
+	}
+
+	if err := options.validateGrafana(); err != nil {
+		return err
+	}
+
+	for component, resources := range map[string]installResources{
+		"controller": options.controllerResources,
+		"web":        options.webResources,
+		"prometheus": options.prometheusResources,
+		"grafana":    options.grafanaResources,
+	} {
+		if err := resources.validate(component); err != nil {
+			return err
+		}
+	}
+
+	if options.enableHeartbeat {
+		if options.heartbeatEndpoint == "" {
+			return errors.New("--enable-heartbeat requires --heartbeat-endpoint")
+		}
+		if _, err := url.ParseRequestURI(options.heartbeatEndpoint); err != nil {
+			return fmt.Errorf("--heartbeat-endpoint must be a URL: %s", err)
+		}
+	}
+
 	return options.proxyConfigOptions.validate()
 }
 
+// addResourceFlags adds the flags setting the resource requests and limits of
+// the containers of a control plane component.
+func addResourceFlags(flags *pflag.FlagSet, component string, resources *installResources) {
+	flags.StringVar(&resources.RequestCPU, component+"-cpu-request", resources.RequestCPU, fmt.Sprintf("Amount of CPU units that the %s containers request", component))
+	flags.StringVar(&resources.RequestMemory, component+"-memory-request", resources.RequestMemory, fmt.Sprintf("Amount of memory that the %s containers request", component))
+	flags.StringVar(&resources.LimitCPU, component+"-cpu-limit", resources.LimitCPU, fmt.Sprintf("Maximum amount of CPU units that the %s containers can use", component))
+	flags.StringVar(&resources.LimitMemory, component+"-memory-limit", resources.LimitMemory, fmt.Sprintf("Maximum amount of memory that the %s containers can use", component))
+}
+
+// setDefaultRequests sets the requests that aren't set, e.g. to the defaults
+// of the HA install.
+func (r *installResources) setDefaultRequests(cpu, memory string) {
+	if r.RequestCPU == "" {
+		r.RequestCPU = cpu
+	}
+	if r.RequestMemory == "" {
+		r.RequestMemory = memory
+	}
+}
+
+// validate checks that the requests and limits of the component are valid
+// quantities, and that no request exceeds its limit.
+func (r installResources) validate(component string) error {
+	for _, resource := range []struct {
+		name           string
+		request, limit string
+	}{
+		{"cpu", r.RequestCPU, r.LimitCPU},
+		{"memory", r.RequestMemory, r.LimitMemory},
+	} {
+		var request, limit k8sResource.Quantity
+		var err error
+		if resource.request != "" {
+			if request, err = k8sResource.ParseQuantity(resource.request); err != nil {
+				return fmt.Errorf("Invalid %s request '%s' for --%s-%s-request flag", resource.name, resource.request, component, resource.name)
+			}
+		}
+		if resource.limit != "" {
+			if limit, err = k8sResource.ParseQuantity(resource.limit); err != nil {
+				return fmt.Errorf("Invalid %s limit '%s' for --%s-%s-limit flag", resource.name, resource.limit, component, resource.name)
+			}
+		}
+		if resource.request != "" && resource.limit != "" && request.Cmp(limit) > 0 {
+			return fmt.Errorf("The --%s-%s-request of %s exceeds the --%s-%s-limit of %s", component, resource.name, resource.request, component, resource.name, resource.limit)
+		}
+	}
+	return nil
+}
+
+func (options *installOptions) validateGrafana() error {
+	if options.grafanaDashboardsDir != "" && options.grafanaDashboardsConfigMap != "" {
+		return fmt.Errorf("The --grafana-dashboards-dir and --grafana-dashboards-configmap flags cannot both be specified together")
+	}
+
+	if options.grafanaDatasourcesDir != "" && options.grafanaDatasourcesConfigMap != "" {
+		return fmt.Errorf("The --grafana-datasources-dir and --grafana-datasources-configmap flags cannot both be specified together")
+	}
+
+	for flag, name := range map[string]string{
+		"--grafana-dashboards-configmap":  options.grafanaDashboardsConfigMap,
+		"--grafana-datasources-configmap": options.grafanaDatasourcesConfigMap,
+	} {
+		if name == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("%s must be the name of a ConfigMap: %s", flag, strings.Join(errs, "; "))
+		}
+	}
+
+	if options.disableGrafana {
+		if options.grafanaDashboardsDir != "" || options.grafanaDashboardsConfigMap != "" ||
+			options.grafanaDatasourcesDir != "" || options.grafanaDatasourcesConfigMap != "" {
+			return fmt.Errorf("The Grafana dashboards and datasources flags cannot be specified with --disable-grafana")
+		}
+	}
+
+	if options.grafanaURL != "" {
+		if !options.disableGrafana {
+			return fmt.Errorf("--grafana-url requires --disable-grafana")
+		}
+		if _, err := url.ParseRequestURI(options.grafanaURL); err != nil {
+			return fmt.Errorf("--grafana-url must be a URL: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// readGrafanaFiles reads the files of a directory with one of the extensions,
+// to be provisioned in Grafana, keyed by their names.
+func readGrafanaFiles(dir string, extensions ...string) (map[string]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !hasExtension(entry.Name(), extensions) {
+			continue
+		}
+		if errs := validation.IsConfigMapKey(entry.Name()); len(errs) > 0 {
+			return nil, fmt.Errorf("Invalid file name %s: %s", entry.Name(), strings.Join(errs, "; "))
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = string(b)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("No %s files found in %s", strings.Join(extensions, " or "), dir)
+	}
+	return files, nil
+}
+
+func hasExtension(name string, extensions []string) bool {
+	for _, ext := range extensions {
+		if filepath.Ext(name) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// heartbeatSchedule returns the schedule of the heartbeat CronJob, once a day
+// at the time of day of the install, so that the heartbeats of the clusters
+// are spread over the day.
+func heartbeatSchedule(now time.Time) string {
+	return fmt.Sprintf("%d %d * * *", now.Minute(), now.Hour())
+}
+
 func readIntoBytes(filename string) ([]byte, error) {
 	file, err := static.Templates.Open(filename)
 	if err != nil {
@@ -4,12 +4,23 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/renderutil"
 )
 
 func TestRender(t *testing.T) {
-	// The default configuration, with the random UUID overridden with a fixed
-	// value to facilitate testing.
+	// The default configuration, with the random UUID and the heartbeat
+	// schedule, which depends on the time, overridden with fixed values to
+	// facilitate testing.
 	defaultControlPlaneNamespace := controlPlaneNamespace
 	defaultOptions := newInstallOptions()
 	defaultConfig, err := validateAndBuildConfig(defaultOptions)
@@ -18,6 +29,7 @@ func TestRender(t *testing.T) {
 	}
 
 	defaultConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	defaultConfig.HeartbeatSchedule = "1 2 * * *"
 
 	// A configuration that shows that all config setting strings are honored
 	// by `render()`. Note that `SingleNamespace` is tested in a separate
@@ -28,8 +40,16 @@ func TestRender(t *testing.T) {
 		WebImage:                         "WebImage",
 		PrometheusImage:                  "PrometheusImage",
 		PrometheusVolumeName:             "data",
+		PrometheusRetention:              "PrometheusRetention",
+		PrometheusStorageSize:            "PrometheusStorageSize",
+		PrometheusStorageClass:           "PrometheusStorageClass",
+		PrometheusScrapeInterval:         "PrometheusScrapeInterval",
+		PrometheusScrapeTimeout:          "PrometheusScrapeTimeout",
 		GrafanaImage:                     "GrafanaImage",
 		GrafanaVolumeName:                "data",
+		GrafanaDashboards:                map[string]string{"custom.json": "{\n  \"title\": \"Custom\"\n}\n"},
+		GrafanaDatasources:               map[string]string{"influxdb.yaml": "apiVersion: 1\ndatasources:\n- name: influxdb\n  type: influxdb\n  url: http://influxdb:8086\n"},
+		GrafanaDatasourcesConfigMap:      "GrafanaDatasourcesConfigMap",
 		ControllerReplicas:               1,
 		ImagePullPolicy:                  "ImagePullPolicy",
 		UUID:                             "UUID",
@@ -50,6 +70,7 @@ func TestRender(t *testing.T) {
 		TLSIdentityVolumeSpecFileName:    "TLSIdentityVolumeSpecFileName",
 		ProxyAutoInjectEnabled:           true,
 		ProxyInjectAnnotation:            "ProxyInjectAnnotation",
+		ProxyInjectLabel:                 "ProxyInjectLabel",
 		ProxyInjectDisabled:              "ProxyInjectDisabled",
 		ProxyLogLevel:                    "ProxyLogLevel",
 		ProxyUID:                         2102,
@@ -66,11 +87,24 @@ func TestRender(t *testing.T) {
 		ProxyInitSpecFileName:            "ProxyInitSpecFileName",
 		IgnoreInboundPorts:               "4190,4191,1,2,3",
 		IgnoreOutboundPorts:              "2,3,4",
+		IgnoreInboundSubnets:             "10.0.0.0/8",
+		IgnoreOutboundSubnets:            "192.168.0.0/16,172.16.0.0/12",
+		IgnoreMarks:                      "0x4000/0x4000",
+		IptablesMode:                     "nft",
 		ProxyResourceRequestCPU:          "RequestCPU",
 		ProxyResourceRequestMemory:       "RequestMemory",
 		ProfileSuffixes:                  "suffix.",
 		EnableH2Upgrade:                  true,
 		NoInitContainer:                  false,
+		ProxyInjectorFailurePolicy:       "ProxyInjectorFailurePolicy",
+		ProxyInjectorTimeoutSeconds:      10,
+		ProxyInjectorReinvocationPolicy:  "ProxyInjectorReinvocationPolicy",
+		ProxyConfig:                      "ProxyConfig",
+		EnableHeartbeat:                  true,
+		HeartbeatSchedule:                "HeartbeatSchedule",
+		HeartbeatEndpoint:                "HeartbeatEndpoint",
+		DashboardReadOnly:                true,
+		PrometheusRecordingRules:         true,
 	}
 
 	singleNamespaceConfig := installConfig{
@@ -79,6 +113,9 @@ func TestRender(t *testing.T) {
 		WebImage:                         "WebImage",
 		PrometheusImage:                  "PrometheusImage",
 		PrometheusVolumeName:             "data",
+		PrometheusRetention:              "6h",
+		PrometheusScrapeInterval:         "10s",
+		PrometheusScrapeTimeout:          "10s",
 		GrafanaImage:                     "GrafanaImage",
 		GrafanaVolumeName:                "data",
 		ControllerReplicas:               1,
@@ -104,25 +141,39 @@ func TestRender(t *testing.T) {
 		SingleNamespace:                  true,
 		EnableH2Upgrade:                  true,
 		NoInitContainer:                  false,
+		ProxyConfig:                      "ProxyConfig",
 	}
 
 	haOptions := newInstallOptions()
 	haOptions.highAvailability = true
 	haConfig, _ := validateAndBuildConfig(haOptions)
 	haConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	haConfig.HeartbeatSchedule = "1 2 * * *"
 
 	haWithOverridesOptions := newInstallOptions()
 	haWithOverridesOptions.highAvailability = true
 	haWithOverridesOptions.controllerReplicas = 2
 	haWithOverridesOptions.proxyCPURequest = "400m"
 	haWithOverridesOptions.proxyMemoryRequest = "300Mi"
+	haWithOverridesOptions.controllerResources.LimitCPU = "500m"
+	haWithOverridesOptions.controllerResources.LimitMemory = "250Mi"
+	haWithOverridesOptions.prometheusResources.RequestMemory = "1Gi"
 	haWithOverridesConfig, _ := validateAndBuildConfig(haWithOverridesOptions)
 	haWithOverridesConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	haWithOverridesConfig.HeartbeatSchedule = "1 2 * * *"
+
+	noGrafanaOptions := newInstallOptions()
+	noGrafanaOptions.disableGrafana = true
+	noGrafanaOptions.grafanaURL = "https://grafana.example.com"
+	noGrafanaConfig, _ := validateAndBuildConfig(noGrafanaOptions)
+	noGrafanaConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	noGrafanaConfig.HeartbeatSchedule = "1 2 * * *"
 
 	noInitContainerOptions := newInstallOptions()
 	noInitContainerOptions.noInitContainer = true
 	noInitContainerConfig, _ := validateAndBuildConfig(noInitContainerOptions)
 	noInitContainerConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	noInitContainerConfig.HeartbeatSchedule = "1 2 * * *"
 
 	noInitContainerWithProxyAutoInjectOptions := newInstallOptions()
 	noInitContainerWithProxyAutoInjectOptions.noInitContainer = true
@@ -130,6 +181,7 @@ func TestRender(t *testing.T) {
 	noInitContainerWithProxyAutoInjectOptions.tls = "optional"
 	noInitContainerWithProxyAutoInjectConfig, _ := validateAndBuildConfig(noInitContainerWithProxyAutoInjectOptions)
 	noInitContainerWithProxyAutoInjectConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	noInitContainerWithProxyAutoInjectConfig.HeartbeatSchedule = "1 2 * * *"
 
 	testCases := []struct {
 		config                installConfig
@@ -142,6 +194,7 @@ func TestRender(t *testing.T) {
 		{singleNamespaceConfig, defaultOptions, singleNamespaceConfig.Namespace, "testdata/install_single_namespace_output.golden"},
 		{*haConfig, haOptions, haConfig.Namespace, "testdata/install_ha_output.golden"},
 		{*haWithOverridesConfig, haWithOverridesOptions, haWithOverridesConfig.Namespace, "testdata/install_ha_with_overrides_output.golden"},
+		{*noGrafanaConfig, noGrafanaOptions, noGrafanaConfig.Namespace, "testdata/install_no_grafana.golden"},
 		{*noInitContainerConfig, noInitContainerOptions, noInitContainerConfig.Namespace, "testdata/install_no_init_container.golden"},
 		{*noInitContainerWithProxyAutoInjectConfig, noInitContainerWithProxyAutoInjectOptions, noInitContainerWithProxyAutoInjectConfig.Namespace, "testdata/install_no_init_container_auto_inject.golden"},
 	}
@@ -167,6 +220,233 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderToDir(t *testing.T) {
+	options := newInstallOptions()
+	options.enableHeartbeat = true
+	options.heartbeatEndpoint = "https://heartbeat.example.com"
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "install")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := renderToDir(*config, dir, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := []string{}
+	split := ""
+	for _, file := range files {
+		names = append(names, file.Name())
+		b, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		split += string(b)
+	}
+
+	expectedNames := []string{
+		"00-namespace.yaml",
+		"01-crds.yaml",
+		"02-rbac.yaml",
+		"03-controller.yaml",
+		"04-web.yaml",
+		"05-prometheus.yaml",
+		"06-grafana.yaml",
+		"09-sp-validator.yaml",
+		"10-heartbeat.yaml",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected files %v, got %v", expectedNames, names)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	kinds := regexp.MustCompile("(?m)^kind: ")
+	if expected, actual := len(kinds.FindAllString(buf.String(), -1)), len(kinds.FindAllString(split, -1)); expected != actual {
+		t.Fatalf("Expected the files to hold %d resources, got %d", expected, actual)
+	}
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	options := newInstallOptions()
+	options.singleNamespace = true
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "chart")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := renderHelmChart(*config, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	chrt, err := chartutil.Load(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error loading the chart: %v", err)
+	}
+	rendered, err := renderutil.Render(chrt, &chart.Config{Values: map[string]*chart.Value{}}, renderutil.Options{
+		ReleaseOptions: chartutil.ReleaseOptions{Name: "linkerd", IsInstall: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error rendering the chart: %v", err)
+	}
+
+	// the chart renders the templates render includes with the values of the
+	// options, i.e. neither the TLS nor the cluster-wide resources
+	testCases := []struct {
+		template string
+		empty    bool
+	}{
+		{baseTemplateName, false},
+		{tlsTemplateName, true},
+		{proxyInjectorTemplateName, true},
+		{spValidatorTemplateName, true},
+		{heartbeatTemplateName, true},
+	}
+	for i, tc := range testCases {
+		content := rendered[filepath.Join("linkerd", tc.template)]
+		if empty := strings.TrimSpace(content) == ""; empty != tc.empty {
+			t.Fatalf("test case %d: expected %s to be empty: %t, got:\n%s", i, tc.template, tc.empty, content)
+		}
+	}
+}
+
+func TestInstallComponent(t *testing.T) {
+	testCases := []struct {
+		kind     string
+		name     string
+		expected string
+	}{
+		{"Namespace", "linkerd", "namespace"},
+		{"CustomResourceDefinition", "serviceprofiles.linkerd.io", "crds"},
+		{"ClusterRole", "linkerd-linkerd-prometheus", "rbac"},
+		{"ServiceAccount", "linkerd-web", "rbac"},
+		{"ConfigMap", "linkerd-config", "controller"},
+		{"Service", "linkerd-proxy-api", "controller"},
+		{"Deployment", "linkerd-controller", "controller"},
+		{"Deployment", "linkerd-ca", "ca"},
+		{"ConfigMap", "linkerd-grafana-config", "grafana"},
+		{"ConfigMap", "linkerd-proxy-injector-sidecar-config", "proxy-injector"},
+		{"CronJob", "linkerd-heartbeat", "heartbeat"},
+		{"PersistentVolumeClaim", "linkerd-prometheus", "prometheus"},
+		{"ConfigMap", "linkerd-grafana-dashboards", "grafana"},
+	}
+
+	for i, tc := range testCases {
+		if component := installComponent(tc.kind, tc.name); component != tc.expected {
+			t.Fatalf("test case %d: expected component %s, got %s", i, tc.expected, component)
+		}
+	}
+}
+
+func TestSplitInstallComponents(t *testing.T) {
+	manifests := `### Namespace ###
+kind: Namespace
+apiVersion: v1
+metadata:
+  name: linkerd
+### Web ###
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: linkerd-web
+---
+`
+	components, err := splitInstallComponents(manifests)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"namespace": []string{"---\n### Namespace ###\nkind: Namespace\napiVersion: v1\nmetadata:\n  name: linkerd\n"},
+		"web":       []string{"---\n### Web ###\nkind: Service\napiVersion: v1\nmetadata:\n  name: linkerd-web\n"},
+	}
+	if !reflect.DeepEqual(components, expected) {
+		t.Fatalf("Expected components:\n%s\nbut got:\n%s", expected, components)
+	}
+}
+
+func TestHeartbeatSchedule(t *testing.T) {
+	schedule := heartbeatSchedule(time.Date(2019, 2, 14, 17, 32, 5, 0, time.UTC))
+	if schedule != "32 17 * * *" {
+		t.Fatalf("Expected schedule \"32 17 * * *\", got \"%s\"", schedule)
+	}
+}
+
+func TestReadGrafanaFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grafana")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"custom.json":   "{}",
+		"influxdb.yaml": "apiVersion: 1",
+		"README.md":     "dashboards",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	files, err := readGrafanaFiles(dir, ".json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{"custom.json": "{}"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Fatalf("Expected files %v, got %v", expected, files)
+	}
+
+	if _, err := readGrafanaFiles(dir, ".jsonnet"); err == nil {
+		t.Fatalf("Expected error for a directory without dashboards, got nothing")
+	}
+}
+
+func TestPrometheusScrapeConfig(t *testing.T) {
+	testCases := []struct {
+		interval         time.Duration
+		expectedInterval string
+		expectedTimeout  string
+	}{
+		{5 * time.Second, "5s", "5s"},
+		{10 * time.Second, "10s", "10s"},
+		{90 * time.Second, "90s", "10s"},
+	}
+
+	for i, tc := range testCases {
+		options := newInstallOptions()
+		options.prometheusScrapeInterval = tc.interval
+
+		config, err := validateAndBuildConfig(options)
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if config.PrometheusScrapeInterval != tc.expectedInterval || config.PrometheusScrapeTimeout != tc.expectedTimeout {
+			t.Fatalf("test case %d: expected scrape interval %s and timeout %s, got %s and %s", i, tc.expectedInterval, tc.expectedTimeout, config.PrometheusScrapeInterval, config.PrometheusScrapeTimeout)
+		}
+	}
+}
+
 func TestValidate(t *testing.T) {
 	t.Run("Accepts the default options as valid", func(t *testing.T) {
 		if err := newInstallOptions().validate(); err != nil {
@@ -188,6 +468,59 @@ func TestValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("Rejects invalid proxy injector webhook settings", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) { options.proxyInjectorFailurePolicy = "Retry" },
+				"--proxy-injector-failure-policy must be one of: Ignore, Fail",
+			},
+			{
+				func(options *installOptions) { options.proxyInjectorTimeoutSeconds = 31 },
+				"--proxy-injector-timeout-seconds must be at most 30",
+			},
+			{
+				func(options *installOptions) { options.proxyInjectorReinvocationPolicy = "Always" },
+				"--proxy-injector-reinvocation-policy must be one of: Never, IfNeeded",
+			},
+		}
+
+		for _, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil {
+				t.Fatalf("Expected error, got nothing")
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("Expected error string\"%s\", got \"%s\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid heartbeat endpoint", func(t *testing.T) {
+		options := newInstallOptions()
+		options.enableHeartbeat = true
+
+		err := options.validate()
+		if err == nil || err.Error() != "--enable-heartbeat requires --heartbeat-endpoint" {
+			t.Fatalf("Expected the endpoint to be required, got %v", err)
+		}
+
+		options.heartbeatEndpoint = "versioncheck"
+		if err := options.validate(); err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+
+		options.enableHeartbeat = false
+		if err := options.validate(); err != nil {
+			t.Fatalf("Expected the endpoint not to be validated when the heartbeat is disabled, got %s", err)
+		}
+	})
+
 	t.Run("Rejects single namespace install with auto inject", func(t *testing.T) {
 		options := newInstallOptions()
 		options.proxyAutoInject = true
@@ -202,4 +535,198 @@ func TestValidate(t *testing.T) {
 			t.Fatalf("Expected error string\"%s\", got \"%s\"", expected, err)
 		}
 	})
+
+	t.Run("Rejects invalid Prometheus settings", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) { options.prometheusRetention = 0 },
+				"--prometheus-retention must be positive, was 0s",
+			},
+			{
+				func(options *installOptions) { options.prometheusScrapeInterval = -time.Second },
+				"--prometheus-scrape-interval must be positive, was -1s",
+			},
+			{
+				func(options *installOptions) { options.prometheusStorageSize = "large" },
+				"Invalid storage size 'large' for --prometheus-storage-size flag",
+			},
+			{
+				func(options *installOptions) { options.prometheusStorageClass = "ssd" },
+				"--prometheus-storage-class requires --prometheus-storage-size",
+			},
+		}
+
+		for i, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid skipped ports", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) { options.ignoreInboundPorts = []string{"22", "65536"} },
+				"Invalid port '65536' for --skip-inbound-ports flag: expected a port between 1 and 65535, a range of ports such as 4000-4100, or one of: ftp, memcached, mysql, postgresql, redis, smtp",
+			},
+			{
+				func(options *installOptions) { options.ignoreOutboundPorts = []string{"http"} },
+				"Invalid port 'http' for --skip-outbound-ports flag: expected a port between 1 and 65535, a range of ports such as 4000-4100, or one of: ftp, memcached, mysql, postgresql, redis, smtp",
+			},
+			{
+				func(options *installOptions) { options.ignoreOutboundPorts = []string{"4100-4000"} },
+				"Invalid port '4100-4000' for --skip-outbound-ports flag: the range must not end before it starts",
+			},
+		}
+
+		for i, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid iptables settings", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) { options.iptablesMode = "nftables" },
+				"--iptables-mode must be one of: legacy, nft",
+			},
+			{
+				func(options *installOptions) { options.ignoreInboundSubnets = []string{"10.0.0.1"} },
+				"Invalid CIDR '10.0.0.1' for --skip-inbound-subnets flag",
+			},
+			{
+				func(options *installOptions) { options.ignoreOutboundSubnets = []string{"10.0.0.0/8", "10.0.0.0/33"} },
+				"Invalid CIDR '10.0.0.0/33' for --skip-outbound-subnets flag",
+			},
+			{
+				func(options *installOptions) { options.ignoreMarks = []string{"0x100000000"} },
+				"Invalid mark '0x100000000' for --skip-marks flag, expected value[/mask]",
+			},
+		}
+
+		for i, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid Grafana settings", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) {
+					options.grafanaDashboardsDir = "dashboards"
+					options.grafanaDashboardsConfigMap = "dashboards"
+				},
+				"The --grafana-dashboards-dir and --grafana-dashboards-configmap flags cannot both be specified together",
+			},
+			{
+				func(options *installOptions) {
+					options.disableGrafana = true
+					options.grafanaDashboardsConfigMap = "dashboards"
+				},
+				"The Grafana dashboards and datasources flags cannot be specified with --disable-grafana",
+			},
+			{
+				func(options *installOptions) { options.grafanaURL = "https://grafana.example.com" },
+				"--grafana-url requires --disable-grafana",
+			},
+		}
+
+		for i, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid resource requests and limits", func(t *testing.T) {
+		testCases := []struct {
+			configure func(*installOptions)
+			expected  string
+		}{
+			{
+				func(options *installOptions) { options.webResources.RequestCPU = "lots" },
+				"Invalid cpu request 'lots' for --web-cpu-request flag",
+			},
+			{
+				func(options *installOptions) { options.grafanaResources.LimitMemory = "1Gib" },
+				"Invalid memory limit '1Gib' for --grafana-memory-limit flag",
+			},
+			{
+				func(options *installOptions) {
+					options.prometheusResources.RequestMemory = "2Gi"
+					options.prometheusResources.LimitMemory = "1Gi"
+				},
+				"The --prometheus-memory-request of 2Gi exceeds the --prometheus-memory-limit of 1Gi",
+			},
+		}
+
+		for i, tc := range testCases {
+			options := newInstallOptions()
+			tc.configure(options)
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects HA default requests exceeding the limits", func(t *testing.T) {
+		options := newInstallOptions()
+		options.highAvailability = true
+		options.prometheusResources.LimitCPU = "200m"
+		expected := "The --prometheus-cpu-request of 300m exceeds the --prometheus-cpu-limit of 200m"
+
+		_, err := validateAndBuildConfig(options)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	})
+
+	t.Run("Rejects helm chart output with an output directory", func(t *testing.T) {
+		options := newInstallOptions()
+		options.outputFormat = helmChartOutput
+		options.outputDir = "linkerd"
+		expected := "The --output helm-chart and --output-dir flags cannot both be specified together"
+
+		err := options.validate()
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+		if err.Error() != expected {
+			t.Fatalf("Expected error string\"%s\", got \"%s\"", expected, err)
+		}
+	})
 }
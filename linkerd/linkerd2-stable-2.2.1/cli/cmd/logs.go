@@ -3,13 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wercker/stern/stern"
 	"k8s.io/api/core/v1"
@@ -20,10 +24,15 @@ import (
 
 //This code replicates most of the functionality in https://github.com/wercker/stern/blob/master/cmd/cli.go
 type logCmdConfig struct {
+	kubeAPI   *k8s.KubernetesAPI
 	clientset *kubernetes.Clientset
 	*stern.Config
 }
 
+// adminPortName is the name of the port of the admin servers of the control
+// plane containers.
+const adminPortName = "admin-http"
+
 type logsOptions struct {
 	container             string
 	controlPlaneComponent string
@@ -31,6 +40,8 @@ type logsOptions struct {
 	sinceSeconds          time.Duration
 	tail                  int64
 	timestamps            bool
+	setLevel              string
+	adminTokenFile        string
 }
 
 func newLogsOptions() *logsOptions {
@@ -41,6 +52,8 @@ func newLogsOptions() *logsOptions {
 		sinceSeconds:          48 * time.Hour,
 		tail:                  -1,
 		timestamps:            false,
+		setLevel:              "",
+		adminTokenFile:        "",
 	}
 }
 
@@ -116,8 +129,8 @@ func getControlPlaneComponentsAndContainers(pods *v1.PodList) ([]string, []strin
 	return controlPlaneComponents, containers
 }
 
-func newLogCmdConfig(options *logsOptions, kubeconfigPath, kubeContext string) (*logCmdConfig, error) {
-	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext)
+func newLogCmdConfig(options *logsOptions, kubeconfigPath, kubeContext string, auth k8s.ClientAuth) (*logCmdConfig, error) {
+	kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +153,7 @@ func newLogCmdConfig(options *logsOptions, kubeconfigPath, kubeContext string) (
 	}
 
 	return &logCmdConfig{
+		kubeAPI,
 		clientset,
 		c,
 	}, nil
@@ -163,16 +177,27 @@ func newCmdLogs() *cobra.Command {
 
   # Tail logs from the linkerd-proxy container in the controller component showing timestamps for each line
   linkerd logs --control-plane-component controller --container linkerd-proxy --timestamps
+
+  # Turn on debug logging in the tap container of the controller component, without restarting it
+  linkerd logs --control-plane-component controller --container tap --set-level debug --admin-token-file admin-token
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			color.NoColor = options.noColor
 
-			opts, err := newLogCmdConfig(options, kubeconfigPath, kubeContext)
+			opts, err := newLogCmdConfig(options, kubeconfigPath, kubeContext, clientAuth())
 
 			if err != nil {
 				return err
 			}
 
+			if options.setLevel != "" {
+				token, err := readAdminToken(options.adminTokenFile)
+				if err != nil {
+					return err
+				}
+				return setLogLevels(opts, options.setLevel, token)
+			}
+
 			return runLogOutput(opts)
 		},
 	}
@@ -183,6 +208,8 @@ func newCmdLogs() *cobra.Command {
 	cmd.PersistentFlags().DurationVarP(&options.sinceSeconds, "since", "s", options.sinceSeconds, "Duration of how far back logs should be retrieved")
 	cmd.PersistentFlags().Int64Var(&options.tail, "tail", options.tail, "Last number of log lines to show for a given container. -1 does not show previous log lines")
 	cmd.PersistentFlags().BoolVarP(&options.timestamps, "timestamps", "t", options.timestamps, "Print timestamps for each given log line")
+	cmd.PersistentFlags().StringVar(&options.setLevel, "set-level", options.setLevel, "Instead of tailing logs, set the log level of the specified containers at runtime (one of: panic, fatal, error, warn, info, debug)")
+	cmd.PersistentFlags().StringVar(&options.adminTokenFile, "admin-token-file", options.adminTokenFile, "Path to a file with the bearer token of the admin servers, which --set-level requires unless they authenticate with client certificates")
 
 	return cmd
 }
@@ -229,3 +256,105 @@ func runLogOutput(opts *logCmdConfig) error {
 	<-sigCh
 	return nil
 }
+
+// setLogLevels sets the log level of the control plane containers selected by
+// opts, through the /loglevel endpoint of their admin servers, authenticated
+// with the bearer token if it's not empty. Containers without an admin server,
+// e.g. the proxies, are skipped.
+func setLogLevels(opts *logCmdConfig, level, token string) error {
+	if _, err := log.ParseLevel(level); err != nil {
+		return err
+	}
+
+	pods, err := opts.clientset.CoreV1().Pods(opts.Namespace).List(meta_v1.ListOptions{
+		LabelSelector: opts.LabelSelector.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed bool
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if !opts.ContainerQuery.MatchString(container.Name) {
+				continue
+			}
+			port := adminPort(container)
+			if port == 0 {
+				continue
+			}
+
+			if err := setLogLevel(opts.kubeAPI, pod.Namespace, pod.Name, port, level, token); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to set the log level of %s/%s: %s\n", pod.Name, container.Name, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("Set the log level of %s/%s to %s\n", pod.Name, container.Name, level)
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("failed to set the log level of some containers")
+	}
+	return nil
+}
+
+func adminPort(container v1.Container) int {
+	for _, port := range container.Ports {
+		if port.Name == adminPortName {
+			return int(port.ContainerPort)
+		}
+	}
+	return 0
+}
+
+func setLogLevel(kubeAPI *k8s.KubernetesAPI, namespace, podName string, port int, level, token string) error {
+	portforward, err := k8s.NewPodPortForward(kubeAPI, namespace, podName, 0, port, verbose)
+	if err != nil {
+		return err
+	}
+	defer portforward.Stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- portforward.Run()
+	}()
+
+	select {
+	case <-portforward.Ready():
+	case err := <-errCh:
+		return fmt.Errorf("failed to port-forward: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, portforward.URLFor("/loglevel"), strings.NewReader(level))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(rsp.Body)
+		return fmt.Errorf("unexpected response: %s: %s", rsp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// readAdminToken returns the bearer token of the admin servers in path, or an
+// empty token if path is empty.
+func readAdminToken(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	token, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the admin token: %s", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
@@ -3,9 +3,13 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/profiles"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -18,15 +22,30 @@ type templateConfig struct {
 	ClusterZone           string
 }
 
+// grpcReflectTimeout bounds the time spent connecting to and querying a gRPC
+// server with --grpc-reflect.
+const grpcReflectTimeout = 30 * time.Second
+
 type profileOptions struct {
 	name          string
 	namespace     string
 	template      bool
 	openAPI       string
-	proto         string
+	proto         []string
+	protoPaths    []string
 	tap           string
 	tapDuration   time.Duration
 	tapRouteLimit uint
+	grpcReflect   string
+	validateFile  string
+	external      bool
+
+	retryable           bool
+	routeTimeout        time.Duration
+	retryBudget         bool
+	retryRatio          float32
+	minRetriesPerSecond uint32
+	retryBudgetTTL      time.Duration
 }
 
 func newProfileOptions() *profileOptions {
@@ -35,10 +54,21 @@ func newProfileOptions() *profileOptions {
 		namespace:     "default",
 		template:      false,
 		openAPI:       "",
-		proto:         "",
+		proto:         []string{},
+		protoPaths:    []string{},
 		tap:           "",
 		tapDuration:   5 * time.Second,
 		tapRouteLimit: 20,
+		grpcReflect:   "",
+		validateFile:  "",
+		external:      false,
+
+		retryable:           false,
+		routeTimeout:        0,
+		retryBudget:         false,
+		retryRatio:          profiles.DefaultRetryBudget.RetryRatio,
+		minRetriesPerSecond: profiles.DefaultRetryBudget.MinRetriesPerSecond,
+		retryBudgetTTL:      10 * time.Second,
 	}
 }
 
@@ -50,19 +80,49 @@ func (options *profileOptions) validate() error {
 	if options.openAPI != "" {
 		outputs++
 	}
-	if options.proto != "" {
+	if len(options.proto) != 0 {
 		outputs++
 	}
 	if options.tap != "" {
 		outputs++
 	}
+	if options.grpcReflect != "" {
+		outputs++
+	}
+	if options.validateFile != "" {
+		outputs++
+	}
 	if outputs != 1 {
-		return errors.New("You must specify exactly one of --template or --open-api or --proto or --tap")
+		return errors.New("You must specify exactly one of --template or --open-api or --proto or --tap or --grpc-reflect or --validate")
 	}
 
-	// a DNS-1035 label must consist of lower case alphanumeric characters or '-',
-	// start with an alphabetic character, and end with an alphanumeric character
-	if errs := validation.IsDNS1035Label(options.name); len(errs) != 0 {
+	// validating a manifest doesn't involve a service
+	if options.validateFile != "" {
+		return nil
+	}
+
+	if options.routeTimeout < 0 {
+		return fmt.Errorf("--route-timeout must be positive: %s", options.routeTimeout)
+	}
+	if options.retryRatio < 0 {
+		return fmt.Errorf("--retry-ratio must be non-negative: %f", options.retryRatio)
+	}
+	if options.retryBudgetTTL <= 0 {
+		return fmt.Errorf("--retry-budget-ttl must be positive: %s", options.retryBudgetTTL)
+	}
+
+	if options.external {
+		// the profile of an external authority is named after its DNS name,
+		// which must have several labels so it's not taken for a service
+		if errs := validation.IsDNS1123Subdomain(options.name); len(errs) != 0 {
+			return fmt.Errorf("invalid external authority %q: %v", options.name, errs)
+		}
+		if !strings.Contains(options.name, ".") {
+			return fmt.Errorf("invalid external authority %q: must be a fully qualified DNS name, e.g. api.github.com", options.name)
+		}
+	} else if errs := validation.IsDNS1035Label(options.name); len(errs) != 0 {
+		// a DNS-1035 label must consist of lower case alphanumeric characters or '-',
+		// start with an alphabetic character, and end with an alphanumeric character
 		return fmt.Errorf("invalid service %q: %v", options.name, errs)
 	}
 
@@ -75,13 +135,32 @@ func (options *profileOptions) validate() error {
 	return nil
 }
 
+// retryConfig returns the retries and timeouts to set on the rendered
+// profile.
+func (options *profileOptions) retryConfig() profiles.RetryConfig {
+	config := profiles.RetryConfig{
+		IsRetryable: options.retryable,
+	}
+	if options.routeTimeout != 0 {
+		config.Timeout = options.routeTimeout.String()
+	}
+	if options.retryBudget {
+		config.RetryBudget = &sp.RetryBudget{
+			RetryRatio:          options.retryRatio,
+			MinRetriesPerSecond: options.minRetriesPerSecond,
+			TTL:                 options.retryBudgetTTL.String(),
+		}
+	}
+	return config
+}
+
 // NewCmdProfile creates a new cobra command for the Profile subcommand which
 // generates Linkerd service profiles.
 func newCmdProfile() *cobra.Command {
 	options := newProfileOptions()
 
 	cmd := &cobra.Command{
-		Use:   "profile [flags] (--template | --open-api file | --proto file | --tap resource) (SERVICE)",
+		Use:   "profile [flags] (--template | --open-api file | --proto file | --tap resource | --grpc-reflect address) (SERVICE | --external AUTHORITY) | --validate file",
 		Short: "Output service profile config for Kubernetes",
 		Long:  "Output service profile config for Kubernetes.",
 		Example: `  # Output a basic template to apply after modification.
@@ -90,29 +169,63 @@ func newCmdProfile() *cobra.Command {
   # Generate a profile from an OpenAPI specification.
   linkerd profile -n emojivoto --open-api web-svc.swagger web-svc
 
+  # Generate a profile from the OpenAPI specification served by a live service.
+  linkerd profile -n emojivoto --open-api http://localhost:8080/openapi/v2 web-svc
+
   # Generate a profile from a protobuf definition.
   linkerd profile -n emojivoto --proto Voting.proto vote-svc
 
+  # Generate a profile from protobuf definitions and the definitions they import.
+  linkerd profile -n emojivoto --proto api/Voting.proto --proto api/Results.proto -I include vote-svc
+
+  # Generate a profile from the services of a running gRPC server, through its reflection API.
+  linkerd profile -n emojivoto --grpc-reflect localhost:8080 vote-svc
+
   # Generate a profile by watching live traffic based off tap data.
   linkerd profile -n emojivoto web-svc --tap deploy/web --tap-duration 10s --tap-route-limit 5
-`,
-		Args: cobra.ExactArgs(1),
+
+  # Output a template with retryable routes that time out after 300ms, and a retry budget.
+  linkerd profile -n emojivoto --template web-svc --retryable --route-timeout 300ms --retry-budget --retry-ratio 0.1
+
+  # Output a template for an authority outside of the cluster, stored in the control plane namespace.
+  linkerd profile --template --external api.github.com
+
+  # Check a service profile manifest for errors before applying it.
+  linkerd profile --validate web-svc-profile.yaml`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if options.validateFile != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			options.name = args[0]
+			if len(args) != 0 {
+				options.name = args[0]
+			}
+			if options.external && !cmd.Flags().Changed("namespace") {
+				options.namespace = controlPlaneNamespace
+			}
 
 			err := options.validate()
 			if err != nil {
 				return err
 			}
 
+			if options.validateFile != "" {
+				return validateProfileManifest(options.validateFile, os.Stdout, os.Stderr)
+			}
+
+			retries := options.retryConfig()
 			if options.template {
-				return profiles.RenderProfileTemplate(options.namespace, options.name, os.Stdout)
+				return profiles.RenderProfileTemplate(options.namespace, options.name, retries, os.Stdout)
 			} else if options.openAPI != "" {
-				return profiles.RenderOpenAPI(options.openAPI, options.namespace, options.name, os.Stdout)
+				return profiles.RenderOpenAPI(options.openAPI, options.namespace, options.name, retries, os.Stdout)
 			} else if options.tap != "" {
-				return profiles.RenderTapOutputProfile(cliPublicAPIClient(), options.tap, options.namespace, options.name, options.tapDuration, int(options.tapRouteLimit), os.Stdout)
-			} else if options.proto != "" {
-				return profiles.RenderProto(options.proto, options.namespace, options.name, os.Stdout)
+				return profiles.RenderTapOutputProfile(cliPublicAPIClient(), options.tap, options.namespace, options.name, options.tapDuration, int(options.tapRouteLimit), retries, os.Stdout)
+			} else if len(options.proto) != 0 {
+				return profiles.RenderProto(options.proto, options.protoPaths, options.namespace, options.name, retries, os.Stdout)
+			} else if options.grpcReflect != "" {
+				return profiles.RenderGRPCReflection(options.grpcReflect, options.namespace, options.name, grpcReflectTimeout, retries, os.Stdout)
 			}
 
 			// we should never get here
@@ -121,12 +234,48 @@ func newCmdProfile() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().BoolVar(&options.template, "template", options.template, "Output a service profile template")
-	cmd.PersistentFlags().StringVar(&options.openAPI, "open-api", options.openAPI, "Output a service profile based on the given OpenAPI spec file")
-	cmd.PersistentFlags().StringVar(&options.tap, "tap", options.tap, "Output a service profile based on tap data for the given target resource")
+	cmd.PersistentFlags().StringVar(&options.openAPI, "open-api", options.openAPI, "Output a service profile based on the given OpenAPI 2 or 3 spec file or HTTP(S) URL")
+	cmd.PersistentFlags().StringVar(&options.tap, "tap", options.tap, "Output a service profile based on tap data for the given target resource, with a route per method and path, where the path segments that look like IDs are replaced by a parameter")
 	cmd.PersistentFlags().DurationVar(&options.tapDuration, "tap-duration", options.tapDuration, "Duration over which tap data is collected (for example: \"10s\", \"1m\", \"10m\")")
 	cmd.PersistentFlags().UintVar(&options.tapRouteLimit, "tap-route-limit", options.tapRouteLimit, "Max number of routes to add to the profile")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the service")
-	cmd.PersistentFlags().StringVar(&options.proto, "proto", options.proto, "Output a service profile based on the given Protobuf spec file")
+	cmd.PersistentFlags().StringArrayVar(&options.proto, "proto", options.proto, "Output a service profile based on the given Protobuf spec file; can be repeated")
+	cmd.PersistentFlags().StringArrayVarP(&options.protoPaths, "proto-path", "I", options.protoPaths, "Directory in which to look for the files imported by the Protobuf spec files, before their own directory; can be repeated")
+	cmd.PersistentFlags().StringVar(&options.grpcReflect, "grpc-reflect", options.grpcReflect, "Output a service profile based on the services of the gRPC server at the given host:port, listed through its reflection API")
+	cmd.PersistentFlags().BoolVar(&options.external, "external", options.external, "Output a service profile for an authority outside of the cluster, e.g. api.github.com, given instead of SERVICE; it goes in the control plane namespace unless --namespace is set, in which case it only applies to the clients in that namespace")
+	cmd.PersistentFlags().StringVar(&options.validateFile, "validate", options.validateFile, "Check the service profiles of the given manifest file, or of stdin if \"-\", for errors, without access to the cluster")
+	cmd.PersistentFlags().BoolVar(&options.retryable, "retryable", options.retryable, "Mark all the routes as retryable, so that the proxy retries their failed requests")
+	cmd.PersistentFlags().DurationVar(&options.routeTimeout, "route-timeout", options.routeTimeout, "Timeout of all the routes (default: the proxy's default of 10s)")
+	cmd.PersistentFlags().BoolVar(&options.retryBudget, "retry-budget", options.retryBudget, "Set a retry budget for the service, using the --retry-ratio, --min-retries-per-second and --retry-budget-ttl values")
+	cmd.PersistentFlags().Float32Var(&options.retryRatio, "retry-ratio", options.retryRatio, "Maximum ratio of retries to original requests in the retry budget")
+	cmd.PersistentFlags().Uint32Var(&options.minRetriesPerSecond, "min-retries-per-second", options.minRetriesPerSecond, "Retries per second allowed by the retry budget in addition to the --retry-ratio")
+	cmd.PersistentFlags().DurationVar(&options.retryBudgetTTL, "retry-budget-ttl", options.retryBudgetTTL, "Duration over which requests are counted to calculate the --retry-ratio")
 
 	return cmd
 }
+
+// validateProfileManifest checks the service profiles of a manifest file, or
+// of stdin if path is "-", and prints the errors it finds with their lines.
+func validateProfileManifest(path string, wout, werr io.Writer) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	errs := profiles.ValidateManifest(data)
+	if len(errs) == 0 {
+		fmt.Fprintf(wout, "%s is a valid service profile manifest\n", path)
+		return nil
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(werr, "%s:%d: %s\n", path, err.Line, err.Message)
+	}
+	return fmt.Errorf("found %d error(s) in %s", len(errs), path)
+}
@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/profiles"
@@ -14,7 +18,7 @@ import (
 func TestParseProfile(t *testing.T) {
 	var buf bytes.Buffer
 
-	err := profiles.RenderProfileTemplate("myns", "mysvc", &buf)
+	err := profiles.RenderProfileTemplate("myns", "mysvc", profiles.RetryConfig{}, &buf)
 	if err != nil {
 		t.Fatalf("Error rendering service profile template: %v", err)
 	}
@@ -33,9 +37,44 @@ func TestParseProfile(t *testing.T) {
 	}
 }
 
+func TestParseProfileWithRetries(t *testing.T) {
+	var buf bytes.Buffer
+
+	options := newProfileOptions()
+	options.retryable = true
+	options.routeTimeout = 300 * time.Millisecond
+	options.retryBudget = true
+	options.retryRatio = 0.1
+
+	err := profiles.RenderProfileTemplate("myns", "mysvc", options.retryConfig(), &buf)
+	if err != nil {
+		t.Fatalf("Error rendering service profile template: %v", err)
+	}
+
+	var serviceProfile v1alpha1.ServiceProfile
+	err = yaml.Unmarshal(buf.Bytes(), &serviceProfile)
+	if err != nil {
+		t.Fatalf("Error parsing service profile: %v", err)
+	}
+
+	expectedServiceProfile := profiles.GenServiceProfile("mysvc", "myns")
+	expectedServiceProfile.Spec.Routes[0].IsRetryable = true
+	expectedServiceProfile.Spec.Routes[0].Timeout = "300ms"
+	expectedServiceProfile.Spec.RetryBudget = &v1alpha1.RetryBudget{
+		RetryRatio:          0.1,
+		MinRetriesPerSecond: 10,
+		TTL:                 "10s",
+	}
+
+	err = profiles.ServiceProfileYamlEquals(serviceProfile, expectedServiceProfile)
+	if err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+}
+
 func TestValidateOptions(t *testing.T) {
 	options := newProfileOptions()
-	exp := errors.New("You must specify exactly one of --template or --open-api or --proto or --tap")
+	exp := errors.New("You must specify exactly one of --template or --open-api or --proto or --tap or --grpc-reflect or --validate")
 	err := options.validate()
 	if err == nil || err.Error() != exp.Error() {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
@@ -44,7 +83,7 @@ func TestValidateOptions(t *testing.T) {
 	options = newProfileOptions()
 	options.template = true
 	options.openAPI = "openAPI"
-	exp = errors.New("You must specify exactly one of --template or --open-api or --proto or --tap")
+	exp = errors.New("You must specify exactly one of --template or --open-api or --proto or --tap or --grpc-reflect or --validate")
 	err = options.validate()
 	if err == nil || err.Error() != exp.Error() {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
@@ -138,4 +177,72 @@ func TestValidateOptions(t *testing.T) {
 	if err == nil || err.Error() != exp.Error() {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
 	}
+
+	options = newProfileOptions()
+	options.template = true
+	options.external = true
+	options.name = "api.github.com"
+	err = options.validate()
+	if err != nil {
+		t.Fatalf("validateOptions returned unexpected error (%s) for options: %+v", err, options)
+	}
+
+	options = newProfileOptions()
+	options.template = true
+	options.external = true
+	options.name = "github"
+	exp = errors.New("invalid external authority \"github\": must be a fully qualified DNS name, e.g. api.github.com")
+	err = options.validate()
+	if err == nil || err.Error() != exp.Error() {
+		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
+	}
+
+	options = newProfileOptions()
+	options.validateFile = "profile.yaml"
+	err = options.validate()
+	if err != nil {
+		t.Fatalf("validateOptions returned unexpected error (%s) for options: %+v", err, options)
+	}
+
+	options = newProfileOptions()
+	options.template = true
+	options.name = "service-name"
+	options.routeTimeout = -time.Second
+	exp = errors.New("--route-timeout must be positive: -1s")
+	err = options.validate()
+	if err == nil || err.Error() != exp.Error() {
+		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
+	}
+}
+
+func TestValidateProfileManifest(t *testing.T) {
+	manifest := `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+  namespace: emojivoto
+spec:
+  routes:
+  - name: GET /api/vote
+    condition:
+      pathRegex: /api/vote(`
+
+	file, err := ioutil.TempFile("", "profile")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(manifest); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	file.Close()
+
+	var stdout, stderr bytes.Buffer
+	err = validateProfileManifest(file.Name(), &stdout, &stderr)
+	if err == nil {
+		t.Fatal("Expected an error, got none")
+	}
+	if !strings.HasPrefix(stderr.String(), file.Name()+":8: ") {
+		t.Fatalf("Expected an error on line 8, got: %s", stderr.String())
+	}
 }
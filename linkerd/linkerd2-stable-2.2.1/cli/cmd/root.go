@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -38,6 +42,10 @@ var (
 	apiAddr               string // An empty value means "use the Kubernetes configuration"
 	kubeconfigPath        string
 	kubeContext           string
+	impersonate           string
+	impersonateGroups     []string
+	bearerToken           string
+	certificateAuthority  string
 	verbose               bool
 
 	// These regexs are not as strict as they could be, but are a quick and dirty
@@ -77,14 +85,24 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "linkerd-namespace", "l", defaultNamespace, "Namespace in which Linkerd is installed [$LINKERD_NAMESPACE]")
 	RootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
 	RootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Name of the kubeconfig context to use")
-	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
+	RootCmd.PersistentFlags().StringVar(&impersonate, "as", "", "Username to impersonate for Kubernetes operations")
+	RootCmd.PersistentFlags().StringArrayVar(&impersonateGroups, "as-group", []string{}, "Group to impersonate for Kubernetes operations, this flag can be repeated to specify multiple groups")
+	RootCmd.PersistentFlags().StringVar(&bearerToken, "token", "", "Bearer token for authentication to the Kubernetes API server")
+	RootCmd.PersistentFlags().StringVar(&certificateAuthority, "certificate-authority", "", "Path to a cert file for the certificate authority of the Kubernetes API server, or of the control plane when --api-addr is an https:// URL")
+	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port, or at an http(s):// URL (mostly for testing)")
 	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Turn on debug logging")
 
+	RootCmd.AddCommand(newCmdAlerts())
+	RootCmd.AddCommand(newCmdCerts())
 	RootCmd.AddCommand(newCmdCheck())
 	RootCmd.AddCommand(newCmdCompletion())
+	RootCmd.AddCommand(newCmdConfig())
 	RootCmd.AddCommand(newCmdDashboard())
+	RootCmd.AddCommand(newCmdEdges())
 	RootCmd.AddCommand(newCmdEndpoints())
 	RootCmd.AddCommand(newCmdGet())
+	RootCmd.AddCommand(newCmdGrafana())
+	RootCmd.AddCommand(newCmdGraph())
 	RootCmd.AddCommand(newCmdInject())
 	RootCmd.AddCommand(newCmdInstall())
 	RootCmd.AddCommand(newCmdInstallCNIPlugin())
@@ -99,6 +117,17 @@ func init() {
 	RootCmd.AddCommand(newCmdVersion())
 }
 
+// clientAuth returns the credentials that override the ones of the kubeconfig,
+// set by the --as, --as-group, --token and --certificate-authority flags.
+func clientAuth() k8s.ClientAuth {
+	return k8s.ClientAuth{
+		Impersonate:          impersonate,
+		ImpersonateGroups:    impersonateGroups,
+		Token:                bearerToken,
+		CertificateAuthority: certificateAuthority,
+	}
+}
+
 // cliPublicAPIClient builds a new public API client and executes default status
 // checks to determine if the client can successfully perform cli commands. If the
 // checks fail, then CLI will print an error and exit.
@@ -124,6 +153,7 @@ func validatedPublicAPIClient(retryDeadline time.Time, apiChecks bool) public.AP
 		ControlPlaneNamespace: controlPlaneNamespace,
 		KubeConfig:            kubeconfigPath,
 		KubeContext:           kubeContext,
+		ClientAuth:            clientAuth(),
 		APIAddr:               apiAddr,
 		RetryDeadline:         retryDeadline,
 	})
@@ -236,8 +266,12 @@ type proxyConfigOptions struct {
 	imagePullPolicy         string
 	inboundPort             uint
 	outboundPort            uint
-	ignoreInboundPorts      []uint
-	ignoreOutboundPorts     []uint
+	ignoreInboundPorts      []string
+	ignoreOutboundPorts     []string
+	ignoreInboundSubnets    []string
+	ignoreOutboundSubnets   []string
+	ignoreMarks             []string
+	iptablesMode            string
 	proxyUID                int64
 	proxyLogLevel           string
 	proxyAPIPort            uint
@@ -247,6 +281,8 @@ type proxyConfigOptions struct {
 	proxyMemoryRequest      string
 	tls                     string
 	disableExternalProfiles bool
+	proxyAwait              bool
+	proxyJobShutdown        bool
 	noInitContainer         bool
 
 	// proxyOutboundCapacity is a special case that's only used for injecting the
@@ -257,8 +293,18 @@ type proxyConfigOptions struct {
 
 const (
 	optionalTLS           = "optional"
+	requiredTLS           = "required"
 	defaultDockerRegistry = "gcr.io/linkerd-io"
 	defaultKeepaliveMs    = 10000
+
+	// the variants of iptables proxy-init configures the rules with
+	iptablesLegacyMode = "legacy"
+	iptablesNftMode    = "nft"
+)
+
+const (
+	skipInboundPortsUsage  = "Ports that should skip the proxy and send directly to the application; accepts ranges such as 4000-4100, and the ftp, memcached, mysql, postgresql, redis and smtp protocols"
+	skipOutboundPortsUsage = "Outbound ports that should skip the proxy; accepts ranges such as 4000-4100, and the ftp, memcached, mysql, postgresql, redis and smtp protocols"
 )
 
 func newProxyConfigOptions() *proxyConfigOptions {
@@ -272,6 +318,10 @@ func newProxyConfigOptions() *proxyConfigOptions {
 		outboundPort:            4140,
 		ignoreInboundPorts:      nil,
 		ignoreOutboundPorts:     nil,
+		ignoreInboundSubnets:    nil,
+		ignoreOutboundSubnets:   nil,
+		ignoreMarks:             nil,
+		iptablesMode:            iptablesLegacyMode,
 		proxyUID:                2102,
 		proxyLogLevel:           "warn,linkerd2_proxy=info",
 		proxyAPIPort:            8086,
@@ -281,6 +331,8 @@ func newProxyConfigOptions() *proxyConfigOptions {
 		proxyMemoryRequest:      "",
 		tls:                     "",
 		disableExternalProfiles: false,
+		proxyAwait:              false,
+		proxyJobShutdown:        false,
 		noInitContainer:         false,
 		proxyOutboundCapacity:   map[string]uint{},
 	}
@@ -311,15 +363,188 @@ func (options *proxyConfigOptions) validate() error {
 		}
 	}
 
-	if options.tls != "" && options.tls != optionalTLS {
-		return fmt.Errorf("--tls must be blank or set to \"%s\"", optionalTLS)
+	if options.tls != "" && options.tls != optionalTLS && options.tls != requiredTLS {
+		return fmt.Errorf("--tls must be blank or set to \"%s\" or \"%s\"", optionalTLS, requiredTLS)
+	}
+
+	if err := validateSkipPorts(options.ignoreInboundPorts, options.ignoreOutboundPorts); err != nil {
+		return err
 	}
 
+	return validateIptablesOptions(options.iptablesMode, options.ignoreInboundSubnets, options.ignoreOutboundSubnets, options.ignoreMarks)
+}
+
+// validateSkipPorts checks the ports of the --skip-inbound-ports and
+// --skip-outbound-ports flags of install, inject and install-cni.
+func validateSkipPorts(inboundPorts, outboundPorts []string) error {
+	for flag, ports := range map[string][]string{
+		"--skip-inbound-ports":  inboundPorts,
+		"--skip-outbound-ports": outboundPorts,
+	} {
+		for _, port := range ports {
+			if _, err := parseSkipPort(port); err != nil {
+				return fmt.Errorf("Invalid port '%s' for %s flag: %s", port, flag, err)
+			}
+		}
+	}
 	return nil
 }
 
+// portRange is an inclusive range of ports, with the same first and last
+// port for a single port.
+type portRange struct {
+	first uint
+	last  uint
+}
+
+// String formats the range as proxy-init takes it, i.e. first-last.
+func (r portRange) String() string {
+	if r.first == r.last {
+		return strconv.FormatUint(uint64(r.first), 10)
+	}
+	return fmt.Sprintf("%d-%d", r.first, r.last)
+}
+
+func (r portRange) contains(port uint) bool {
+	return r.first <= port && port <= r.last
+}
+
+// parseSkipPort returns the ranges of a port, a range of ports such as
+// 4000-4100, or one of the k8s.ServerFirstProtocols.
+func parseSkipPort(port string) ([]portRange, error) {
+	if ports, ok := k8s.ServerFirstProtocols[strings.ToLower(port)]; ok {
+		ranges := make([]portRange, len(ports))
+		for i, p := range ports {
+			ranges[i] = portRange{first: p, last: p}
+		}
+		return ranges, nil
+	}
+
+	bounds := strings.SplitN(port, "-", 2)
+	first, err := parsePortNumber(bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	last := first
+	if len(bounds) == 2 {
+		if last, err = parsePortNumber(bounds[1]); err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, fmt.Errorf("the range must not end before it starts")
+		}
+	}
+	return []portRange{{first: first, last: last}}, nil
+}
+
+func parsePortNumber(port string) (uint, error) {
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || p == 0 {
+		protocols := []string{}
+		for protocol := range k8s.ServerFirstProtocols {
+			protocols = append(protocols, protocol)
+		}
+		sort.Strings(protocols)
+		return 0, fmt.Errorf("expected a port between 1 and 65535, a range of ports such as 4000-4100, or one of: %s", strings.Join(protocols, ", "))
+	}
+	return uint(p), nil
+}
+
+// skipPorts returns the ports and ranges of ports of the valid values of
+// --skip-inbound-ports or --skip-outbound-ports, with their protocols
+// expanded, in order and without duplicates. The ranges are kept as is, so
+// that proxy-init ignores each of them with a single rule.
+func skipPorts(values []string) []portRange {
+	ranges := []portRange{}
+	seen := map[portRange]bool{}
+	for _, value := range values {
+		parsed, _ := parseSkipPort(value)
+		for _, r := range parsed {
+			if !seen[r] {
+				seen[r] = true
+				ranges = append(ranges, r)
+			}
+		}
+	}
+	return ranges
+}
+
+// formatPortRanges returns the ranges of ports as proxy-init takes them.
+func formatPortRanges(ranges []portRange) []string {
+	values := make([]string, len(ranges))
+	for i, r := range ranges {
+		values[i] = r.String()
+	}
+	return values
+}
+
+// validateIptablesOptions checks the flags of install, inject and install-cni
+// that customize the iptables rules of proxy-init.
+func validateIptablesOptions(mode string, inboundSubnets, outboundSubnets, marks []string) error {
+	if mode != iptablesLegacyMode && mode != iptablesNftMode {
+		return fmt.Errorf("--iptables-mode must be one of: %s, %s", iptablesLegacyMode, iptablesNftMode)
+	}
+
+	for flag, subnets := range map[string][]string{
+		"--skip-inbound-subnets":  inboundSubnets,
+		"--skip-outbound-subnets": outboundSubnets,
+	} {
+		for _, subnet := range subnets {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("Invalid CIDR '%s' for %s flag", subnet, flag)
+			}
+		}
+	}
+
+	for _, mark := range marks {
+		if !isValidMark(mark) {
+			return fmt.Errorf("Invalid mark '%s' for --skip-marks flag, expected value[/mask]", mark)
+		}
+	}
+
+	return nil
+}
+
+// isValidMark is true if the firewall mark is a 32 bits value, optionally
+// followed by a mask, as proxy-init takes them.
+func isValidMark(mark string) bool {
+	for _, part := range strings.SplitN(mark, "/", 2) {
+		if _, err := strconv.ParseUint(part, 0, 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// iptablesInitArgs returns the arguments of proxy-init for the subnets and
+// marks that skip the proxy, and for the variant of iptables. The variant is
+// only passed when it's not the default, so that older proxy-init images
+// still run with the default settings.
+func (options *proxyConfigOptions) iptablesInitArgs() []string {
+	args := []string{}
+	if len(options.ignoreInboundSubnets) > 0 {
+		args = append(args, "--inbound-subnets-to-ignore", strings.Join(options.ignoreInboundSubnets, ","))
+	}
+	if len(options.ignoreOutboundSubnets) > 0 {
+		args = append(args, "--outbound-subnets-to-ignore", strings.Join(options.ignoreOutboundSubnets, ","))
+	}
+	if len(options.ignoreMarks) > 0 {
+		args = append(args, "--marks-to-ignore", strings.Join(options.ignoreMarks, ","))
+	}
+	if options.iptablesMode != iptablesLegacyMode {
+		args = append(args, "--iptables-mode", options.iptablesMode)
+	}
+	return args
+}
+
 func (options *proxyConfigOptions) enableTLS() bool {
-	return options.tls == optionalTLS
+	return options.tls == optionalTLS || options.tls == requiredTLS
+}
+
+// requireTLS is true if the proxies must refuse plaintext connections between
+// meshed pods, rather than falling back to them.
+func (options *proxyConfigOptions) requireTLS() bool {
+	return options.tls == requiredTLS
 }
 
 func (options *proxyConfigOptions) taggedProxyImage() string {
@@ -343,8 +568,12 @@ func addProxyConfigFlags(cmd *cobra.Command, options *proxyConfigOptions) {
 	cmd.PersistentFlags().StringVar(&options.imagePullPolicy, "image-pull-policy", options.imagePullPolicy, "Docker image pull policy")
 	cmd.PersistentFlags().UintVar(&options.inboundPort, "inbound-port", options.inboundPort, "Proxy port to use for inbound traffic")
 	cmd.PersistentFlags().UintVar(&options.outboundPort, "outbound-port", options.outboundPort, "Proxy port to use for outbound traffic")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, "Ports that should skip the proxy and send directly to the application")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, "Outbound ports that should skip the proxy")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, skipInboundPortsUsage)
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, skipOutboundPortsUsage)
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreInboundSubnets, "skip-inbound-subnets", options.ignoreInboundSubnets, "CIDRs of the sources whose traffic should skip the proxy and be sent directly to the application")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreOutboundSubnets, "skip-outbound-subnets", options.ignoreOutboundSubnets, "CIDRs of the destinations the outbound traffic should skip the proxy to")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreMarks, "skip-marks", options.ignoreMarks, "Firewall marks, as value[/mask], of the traffic that should skip the proxy")
+	cmd.PersistentFlags().StringVar(&options.iptablesMode, "iptables-mode", options.iptablesMode, "Variant of iptables proxy-init configures the rules with: \"legacy\", or \"nft\" for the nodes whose rules are managed with nftables")
 	cmd.PersistentFlags().Int64Var(&options.proxyUID, "proxy-uid", options.proxyUID, "Run the proxy under this user ID")
 	cmd.PersistentFlags().StringVar(&options.proxyLogLevel, "proxy-log-level", options.proxyLogLevel, "Log level for the proxy")
 	cmd.PersistentFlags().UintVar(&options.proxyAPIPort, "api-port", options.proxyAPIPort, "Port where the Linkerd controller is running")
@@ -352,8 +581,10 @@ func addProxyConfigFlags(cmd *cobra.Command, options *proxyConfigOptions) {
 	cmd.PersistentFlags().UintVar(&options.proxyMetricsPort, "metrics-port", options.proxyMetricsPort, "Proxy port to serve metrics on")
 	cmd.PersistentFlags().StringVar(&options.proxyCPURequest, "proxy-cpu", options.proxyCPURequest, "Amount of CPU units that the proxy sidecar requests")
 	cmd.PersistentFlags().StringVar(&options.proxyMemoryRequest, "proxy-memory", options.proxyMemoryRequest, "Amount of Memory that the proxy sidecar requests")
-	cmd.PersistentFlags().StringVar(&options.tls, "tls", options.tls, "Enable TLS; valid settings: \"optional\"")
+	cmd.PersistentFlags().StringVar(&options.tls, "tls", options.tls, "Enable TLS; valid settings: \"optional\", to fall back to plaintext when a peer doesn't support TLS, or \"required\", to refuse plaintext connections between meshed pods")
 	cmd.PersistentFlags().BoolVar(&options.disableExternalProfiles, "disable-external-profiles", options.disableExternalProfiles, "Disables service profiles for non-Kubernetes services")
+	cmd.PersistentFlags().BoolVar(&options.proxyAwait, "proxy-await", options.proxyAwait, fmt.Sprintf("Start the application containers only once the proxy is ready; can be overridden per pod with the \"%s\" annotation", k8s.ProxyAwaitAnnotation))
+	cmd.PersistentFlags().BoolVar(&options.proxyJobShutdown, "proxy-job-shutdown", options.proxyJobShutdown, fmt.Sprintf("Stop the proxy of the Jobs' pods once their other containers have exited, so that the pods complete; requires Kubernetes 1.12 or later and can be overridden per pod with the \"%s\" annotation", k8s.ProxyJobShutdownAnnotation))
 	cmd.PersistentFlags().BoolVar(&options.noInitContainer, "linkerd-cni-enabled", options.noInitContainer, "Experimental: Omit the proxy-init container when injecting the proxy; requires the linkerd-cni plugin to already be installed")
 	cmd.PersistentFlags().MarkHidden("linkerd-cni-enabled")
 }
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkipPorts(t *testing.T) {
+	testCases := []struct {
+		values   []string
+		expected []string
+	}{
+		{nil, []string{}},
+		{[]string{"22", "8080"}, []string{"22", "8080"}},
+		{[]string{"4000-4003"}, []string{"4000-4003"}},
+		{[]string{"4000-4000"}, []string{"4000"}},
+		{[]string{"MySQL", "smtp"}, []string{"3306", "25", "587"}},
+		{[]string{"redis", "postgresql", "memcached"}, []string{"6379", "5432", "11211"}},
+		{[]string{"3306", "mysql", "3305-3307"}, []string{"3306", "3305-3307"}},
+	}
+
+	for i, tc := range testCases {
+		if err := validateSkipPorts(tc.values, nil); err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if actual := formatPortRanges(skipPorts(tc.values)); !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("test case %d: expected ports %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
@@ -28,6 +28,7 @@ type routeRowStats struct {
 	rowStats
 	actualRequestRate float64
 	actualSuccessRate float64
+	slo               *pb.RouteTable_SLO
 }
 
 const defaultRoute = "[UNKNOWN]"
@@ -48,12 +49,18 @@ func newCmdRoutes() *cobra.Command {
 		Short: "Display route stats",
 		Long: `Display route stats.
 
-This command will only display traffic which is sent to a service that has a Service Profile defined.`,
+This command will only display traffic which is sent to a service that has a Service Profile defined.
+
+  If the routes of the Service Profile define SLOs, the SLO column reports whether the stats met them over the time
+  window, and the BUDGET_BURN column the share of the error budget of the success rate objective consumed.`,
 		Example: `  # Routes for the webapp service in the test namespace.
   linkerd routes service/webapp -n test
 
   # Routes for calls from the traffic deployment to the webapp service in the test namespace.
-  linkerd routes deploy/traffic -n test --to svc/webapp`,
+  linkerd routes deploy/traffic -n test --to svc/webapp
+
+  # Routes for calls from the traffic deployment to the authorities starting with "webapp." in the test namespace.
+  linkerd routes deploy/traffic -n test --to 'au/webapp.*'`,
 		Args:      cobra.ExactArgs(1),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -74,7 +81,7 @@ This command will only display traffic which is sent to a service that has a Ser
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
-	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\"); the control plane only accepts windows between 10s and 6h by default")
 	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, shows outbound stats to the specified resource")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default), \"wide\", and \"json\" are supported")
@@ -126,6 +133,7 @@ func writeRouteStatsToBuffer(resp *pb.TopRoutesResponse, w *tabwriter.Writer, op
 					},
 					actualRequestRate: getRequestRate(r.Stats.GetActualSuccessCount(), r.Stats.GetActualFailureCount(), r.TimeWindow),
 					actualSuccessRate: getSuccessRate(r.Stats.GetActualSuccessCount(), r.Stats.GetActualFailureCount()),
+					slo:               r.GetSlo(),
 				})
 			}
 		}
@@ -185,11 +193,27 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 		}...)
 	}
 
+	// the SLO columns are only displayed if any route defines an SLO
+	outputSLO := false
+	for _, row := range stats {
+		if row.slo != nil {
+			outputSLO = true
+		}
+	}
+
 	headers = append(headers, []string{
 		"LATENCY_P50",
 		"LATENCY_P95",
-		"LATENCY_P99\t", // trailing \t is required to format last column
 	}...)
+	if outputSLO {
+		headers = append(headers, []string{
+			"LATENCY_P99",
+			"SLO",
+			"BUDGET_BURN\t", // trailing \t is required to format last column
+		}...)
+	} else {
+		headers = append(headers, "LATENCY_P99\t") // trailing \t is required to format last column
+	}
 
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
 
@@ -200,7 +224,12 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 		templateString = templateString + "%.2f%%\t%.1frps\t"
 	}
 	// p50, p95, p99
-	templateString = templateString + "%dms\t%dms\t%dms\t\n"
+	templateString = templateString + "%dms\t%dms\t%dms\t"
+	if outputSLO {
+		// SLO compliance, error budget burn
+		templateString = templateString + "%s\t%s\t"
+	}
+	templateString = templateString + "\n"
 
 	for _, row := range stats {
 
@@ -221,24 +250,60 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 			row.latencyP95,
 			row.latencyP99,
 		}...)
+		if outputSLO {
+			values = append(values, sloCompliance(row.slo), sloBudgetBurn(row.slo))
+		}
 
 		fmt.Fprintf(w, templateString, values...)
 	}
 }
 
+// sloCompliance returns whether the stats of a route met its SLO, or "-" if it
+// has none.
+func sloCompliance(slo *pb.RouteTable_SLO) string {
+	switch {
+	case slo == nil:
+		return "-"
+	case slo.GetSuccessRateMet() && slo.GetLatencyMet():
+		return "met"
+	default:
+		return "missed"
+	}
+}
+
+// sloBudgetBurn returns the share of the error budget of the success rate
+// objective of a route consumed, or "-" if it has no such objective.
+func sloBudgetBurn(slo *pb.RouteTable_SLO) string {
+	if slo.GetSuccessRateObjective() == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", slo.GetErrorBudgetBurn()*100)
+}
+
 // Using pointers there where the value is NA and the corresponding json is null
 type jsonRouteStats struct {
-	Route            string   `json:"route"`
-	Authority        string   `json:"authority"`
-	Success          *float64 `json:"success,omitempty"`
-	Rps              *float64 `json:"rps,omitempty"`
-	EffectiveSuccess *float64 `json:"effective_success,omitempty"`
-	EffectiveRps     *float64 `json:"effective_rps,omitempty"`
-	ActualSuccess    *float64 `json:"actual_success,omitempty"`
-	ActualRps        *float64 `json:"actual_rps,omitempty"`
-	LatencyMSp50     *uint64  `json:"latency_ms_p50"`
-	LatencyMSp95     *uint64  `json:"latency_ms_p95"`
-	LatencyMSp99     *uint64  `json:"latency_ms_p99"`
+	Route            string        `json:"route"`
+	Authority        string        `json:"authority"`
+	Success          *float64      `json:"success,omitempty"`
+	Rps              *float64      `json:"rps,omitempty"`
+	EffectiveSuccess *float64      `json:"effective_success,omitempty"`
+	EffectiveRps     *float64      `json:"effective_rps,omitempty"`
+	ActualSuccess    *float64      `json:"actual_success,omitempty"`
+	ActualRps        *float64      `json:"actual_rps,omitempty"`
+	LatencyMSp50     *uint64       `json:"latency_ms_p50"`
+	LatencyMSp95     *uint64       `json:"latency_ms_p95"`
+	LatencyMSp99     *uint64       `json:"latency_ms_p99"`
+	SLO              *jsonRouteSLO `json:"slo,omitempty"`
+}
+
+// jsonRouteSLO holds the compliance of the stats of a route with its SLO, and
+// only the objectives it sets.
+type jsonRouteSLO struct {
+	Met                  bool     `json:"met"`
+	SuccessRateObjective *float64 `json:"success_rate_objective,omitempty"`
+	ErrorBudgetBurn      *float64 `json:"error_budget_burn,omitempty"`
+	LatencyObjectiveMS   *uint64  `json:"latency_objective_ms,omitempty"`
+	LatencyPercentile    *uint32  `json:"latency_percentile,omitempty"`
 }
 
 func printRouteJSON(tables map[string][]*routeRowStats, w *tabwriter.Writer, options *routesOptions) {
@@ -264,6 +329,19 @@ func printRouteJSON(tables map[string][]*routeRowStats, w *tabwriter.Writer, opt
 			entry.LatencyMSp50 = &row.latencyP50
 			entry.LatencyMSp95 = &row.latencyP95
 			entry.LatencyMSp99 = &row.latencyP99
+			if slo := row.slo; slo != nil {
+				entry.SLO = &jsonRouteSLO{
+					Met: slo.SuccessRateMet && slo.LatencyMet,
+				}
+				if slo.SuccessRateObjective != 0 {
+					entry.SLO.SuccessRateObjective = &slo.SuccessRateObjective
+					entry.SLO.ErrorBudgetBurn = &slo.ErrorBudgetBurn
+				}
+				if slo.LatencyObjectiveMs != 0 {
+					entry.SLO.LatencyObjectiveMS = &slo.LatencyObjectiveMs
+					entry.SLO.LatencyPercentile = &slo.LatencyPercentile
+				}
+			}
 
 			entries[resource] = append(entries[resource], entry)
 		}
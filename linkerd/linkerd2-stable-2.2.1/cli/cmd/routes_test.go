@@ -4,12 +4,14 @@ import (
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 )
 
 type routesParamsExp struct {
 	options *routesOptions
 	routes  []string
 	counts  []uint64
+	slos    map[string]*pb.RouteTable_SLO
 	file    string
 }
 
@@ -33,12 +35,42 @@ func TestRoutes(t *testing.T) {
 			file:    "routes_one_output_json.golden",
 		}, t)
 	})
+
+	slos := map[string]*pb.RouteTable_SLO{
+		"/a": {SuccessRateObjective: 0.99, SuccessRateMet: true, LatencyMet: true},
+		"/b": {LatencyObjectiveMs: 100, LatencyPercentile: 99, SuccessRateMet: true, LatencyMet: false},
+	}
+
+	options = newRoutesOptions()
+	t.Run("Returns route stats with SLOs", func(t *testing.T) {
+		testRoutesCall(routesParamsExp{
+			routes:  []string{"/a", "/b", "/c"},
+			counts:  []uint64{90, 60, 0, 30},
+			slos:    slos,
+			options: options,
+			file:    "routes_slo_output.golden",
+		}, t)
+	})
+
+	options.outputFormat = "json"
+	t.Run("Returns route stats with SLOs (json)", func(t *testing.T) {
+		testRoutesCall(routesParamsExp{
+			routes:  []string{"/a", "/b", "/c"},
+			counts:  []uint64{90, 60, 0, 30},
+			slos:    slos,
+			options: options,
+			file:    "routes_slo_output_json.golden",
+		}, t)
+	})
 }
 
 func testRoutesCall(exp routesParamsExp, t *testing.T) {
 	mockClient := &public.MockAPIClient{}
 
 	response := public.GenTopRoutesResponse(exp.routes, exp.counts, exp.options.toResource != "", "foobar")
+	for _, row := range response.GetOk().GetRoutes()[0].GetRows() {
+		row.Slo = exp.slos[row.GetRoute()]
+	}
 
 	mockClient.TopRoutesResponseToReturn = &response
 
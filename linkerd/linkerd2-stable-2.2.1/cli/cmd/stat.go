@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -24,14 +25,37 @@ type statOptions struct {
 	fromNamespace string
 	fromResource  string
 	allNamespaces bool
+	noMetrics     bool
+	onlyMeshed    bool
+	coverage      bool
+	direction     string
+	showQueries   bool
 }
 
 type indexedResults struct {
-	ix   int
-	rows []*pb.StatTable_PodGroup_Row
-	err  error
+	ix        int
+	direction string
+	rows      []*pb.StatTable_PodGroup_Row
+	queries   statQueries
+	err       error
 }
 
+// queriesKey identifies the stat tables of a resource type in a direction.
+type queriesKey struct {
+	resourceType string
+	direction    string
+}
+
+// statQueries holds the PromQL queries the stats of each stat table were
+// computed with, by stat.
+type statQueries map[queriesKey]map[string]string
+
+const (
+	inboundDirection  = "inbound"
+	outboundDirection = "outbound"
+	bothDirections    = "both"
+)
+
 func newStatOptions() *statOptions {
 	return &statOptions{
 		statOptionsBase: *newStatOptionsBase(),
@@ -40,6 +64,11 @@ func newStatOptions() *statOptions {
 		fromNamespace:   "",
 		fromResource:    "",
 		allNamespaces:   false,
+		noMetrics:       false,
+		onlyMeshed:      false,
+		coverage:        false,
+		direction:       inboundDirection,
+		showQueries:     false,
 	}
 }
 
@@ -68,6 +97,8 @@ func newCmdStat() *cobra.Command {
   * sts/my-statefulset
   * authority
   * au/my-authority
+  * ts
+  * ts/my-service-profile
   * all
 
   Valid resource types include:
@@ -77,7 +108,8 @@ func newCmdStat() *cobra.Command {
   * pods
   * replicationcontrollers
   * statefulsets
-  * authorities (not supported in --from)
+  * authorities (not supported in --from; the name may be a pattern, where "*" matches any characters, or a regex prefixed with "~")
+  * trafficsplits (the ServiceProfiles with dstOverrides; not supported in --from or --to)
   * jobs (only supported as a --from or --to)
   * services (only supported if a --from is also specified, or as a --to)
   * all (all resource types, not supported in --from or --to)
@@ -111,14 +143,54 @@ If no resource name is specified, displays stats about all resources of the spec
   # Get all services in all namespaces that receive calls from hello1 deployment in the test namespace.
   linkerd stat services --from deploy/hello1 --from-namespace test --all-namespaces
 
+  # Get the authorities starting with "web." in the test namespace.
+  linkerd stat authorities 'web.*' -n test
+
   # Get all namespaces that receive traffic from the default namespace.
   linkerd stat namespaces --from ns/default
 
+  # Get all traffic from the test namespace to the prod namespace.
+  linkerd stat ns/prod --from-namespace test
+
+  # Get the deployments of the test namespace that call the prod namespace.
+  linkerd stat deployments -n test --to-namespace prod
+
+  # Get the deployments of the test namespace that receive calls from the web deployment through the hello1 service.
+  linkerd stat deployments --from deploy/web --to svc/hello1 -n test
+
+  # Get the stats of the requests the deployments of the test namespace send.
+  linkerd stat deployments -n test --direction outbound
+
+  # Get the stats of the requests the deployments of the test namespace receive next to the ones they send.
+  linkerd stat deployments -n test --direction both
+
   # Get all inbound stats to the test namespace.
-  linkerd stat ns/test`,
+  linkerd stat ns/test
+
+  # Get the live traffic split across the backends of all the traffic splits in the test namespace.
+  linkerd stat trafficsplits -n test
+
+  # Get the meshed pods of all deployments in the test namespace, even if Prometheus is down.
+  linkerd stat deployments -n test --no-metrics
+
+  # Get the meshed namespaces only.
+  linkerd stat namespaces --only-meshed
+
+  # Get the mesh coverage of the workloads of all namespaces, with their proxy versions and auto-injection status.
+  linkerd stat namespaces --coverage
+
+  # Get the stats of all deployments in the test namespace, along with the PromQL queries they were computed with.
+  linkerd stat deployments -n test --show-queries`,
 		Args:      cobra.MinimumNArgs(1),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.coverage {
+				if err := options.validateOutputFormat(); err != nil {
+					return err
+				}
+				return statMeshCoverage(args, options, os.Stdout)
+			}
+
 			reqs, err := buildStatSummaryRequests(args, options)
 			if err != nil {
 				return fmt.Errorf("error creating metrics request while making stats request: %v", err)
@@ -132,37 +204,54 @@ If no resource name is specified, displays stats about all resources of the spec
 				go func(num int, req *pb.StatSummaryRequest) {
 					resp, err := requestStatsFromAPI(client, req, options)
 					rows := respToRows(resp)
-					c <- indexedResults{num, rows, err}
+					queries := respToQueries(resp, req.GetDirection())
+					c <- indexedResults{num, req.GetDirection(), rows, queries, err}
 				}(num, req)
 			}
 
 			totalRows := make([]*pb.StatTable_PodGroup_Row, 0)
+			outboundRows := make([]*pb.StatTable_PodGroup_Row, 0)
+			queries := make(statQueries)
 			i := 0
 			for res := range c {
 				if res.err != nil {
 					return res.err
 				}
-				totalRows = append(totalRows, res.rows...)
+				for key, q := range res.queries {
+					queries[key] = q
+				}
+				if options.direction == bothDirections && res.direction == outboundDirection {
+					outboundRows = append(outboundRows, res.rows...)
+				} else {
+					totalRows = append(totalRows, res.rows...)
+				}
 				if i++; i == len(reqs) {
 					close(c)
 				}
 			}
 
-			output := renderStatStats(totalRows, options)
-			_, err = fmt.Print(output)
+			if !options.showQueries {
+				queries = nil
+			}
+			_, err = fmt.Print(renderStatStatsWithQueries(totalRows, outboundRows, queries, options))
 
 			return err
 		},
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
-	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
-	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name")
-	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\"); the control plane only accepts windows between 10s and 6h by default")
+	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name; along with \"--from\", only counts the requests the \"--from\" resource sends to it")
+	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used. Without \"--to\", restricts outbound stats to the requests sent to this namespace")
 	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource, "If present, restricts outbound stats from the specified resource name")
-	cmd.PersistentFlags().StringVar(&options.fromNamespace, "from-namespace", options.fromNamespace, "Sets the namespace used from lookup the \"--from\" resource; by default the current \"--namespace\" is used")
+	cmd.PersistentFlags().StringVar(&options.fromNamespace, "from-namespace", options.fromNamespace, "Sets the namespace used from lookup the \"--from\" resource; by default the current \"--namespace\" is used. Without \"--from\", restricts outbound stats to the requests sent from this namespace")
 	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns stats across all namespaces, ignoring the \"--namespace\" flag")
-	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default) and \"json\" are supported")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default), \"wide\", and \"json\" are supported")
+	cmd.PersistentFlags().BoolVar(&options.onlyMeshed, "only-meshed", options.onlyMeshed, "If present, only displays the resources with meshed pods")
+	cmd.PersistentFlags().BoolVar(&options.coverage, "coverage", options.coverage, "If present, reports the mesh coverage of the workloads of the namespaces, with their proxy versions and auto-injection status, from the Kubernetes API")
+	cmd.PersistentFlags().BoolVar(&options.noMetrics, "no-metrics", options.noMetrics, "If present, only displays the meshed pods of the resources, from the Kubernetes API, without querying Prometheus for their traffic stats")
+	cmd.PersistentFlags().StringVar(&options.direction, "direction", options.direction, "Direction of the requests to display the stats of: \"inbound\" (default) for the requests the resources receive, \"outbound\" for the ones they send, or \"both\" to display them side by side")
+	cmd.PersistentFlags().BoolVar(&options.showQueries, "show-queries", options.showQueries, "If present, also displays the PromQL queries the stats were computed with, to run them in Prometheus or Grafana")
 
 	return cmd
 }
@@ -177,6 +266,23 @@ func respToRows(resp *pb.StatSummaryResponse) []*pb.StatTable_PodGroup_Row {
 	return rows
 }
 
+// respToQueries returns the queries of the stat tables of the response, by the
+// resource type of their rows, in the direction of the request.
+func respToQueries(resp *pb.StatSummaryResponse, direction string) statQueries {
+	queries := make(statQueries)
+	if resp != nil {
+		for _, statTable := range resp.GetOk().StatTables {
+			podGroup := statTable.GetPodGroup()
+			if len(podGroup.GetRows()) == 0 || len(podGroup.GetQueries()) == 0 {
+				continue
+			}
+			key := queriesKey{podGroup.Rows[0].GetResource().GetType(), direction}
+			queries[key] = podGroup.GetQueries()
+		}
+	}
+	return queries
+}
+
 func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) (*pb.StatSummaryResponse, error) {
 	resp, err := client.StatSummary(context.Background(), req)
 	if err != nil {
@@ -190,12 +296,54 @@ func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest, option
 }
 
 func renderStatStats(rows []*pb.StatTable_PodGroup_Row, options *statOptions) string {
+	return renderBothDirectionsStatStats(rows, nil, options)
+}
+
+// renderBothDirectionsStatStats renders the stats of the outbound rows next
+// to the ones of the inbound rows of the same resources, for the "both"
+// direction.
+func renderBothDirectionsStatStats(rows, outboundRows []*pb.StatTable_PodGroup_Row, options *statOptions) string {
+	return renderStatStatsWithQueries(rows, outboundRows, nil, options)
+}
+
+// renderStatStatsWithQueries renders the stats of the rows along with the
+// queries they were computed with, after the tables, or in the "queries" field
+// of the entries of the JSON output.
+func renderStatStatsWithQueries(rows, outboundRows []*pb.StatTable_PodGroup_Row, queries statQueries, options *statOptions) string {
 	var buffer bytes.Buffer
 	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
-	writeStatsToBuffer(rows, w, options)
+	writeStatsToBuffer(rows, outboundRows, queries, w, options)
 	w.Flush()
 
-	return renderStats(buffer, &options.statOptionsBase)
+	out := renderStats(buffer, &options.statOptionsBase)
+	if options.outputFormat != "json" {
+		out += renderStatQueries(queries)
+	}
+	return out
+}
+
+// renderStatQueries renders the queries of each stat table, in the order of
+// the tables.
+func renderStatQueries(queries statQueries) string {
+	var buffer bytes.Buffer
+	for _, resourceType := range k8s.AllResources {
+		for _, direction := range []string{inboundDirection, outboundDirection} {
+			q, ok := queries[queriesKey{resourceType, direction}]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&buffer, "\n%s %s queries:\n", resourceType, direction)
+			stats := make([]string, 0, len(q))
+			for stat := range q {
+				stats = append(stats, stat)
+			}
+			sort.Strings(stats)
+			for _, stat := range stats {
+				fmt.Fprintf(&buffer, "  %s: %s\n", stat, q[stat])
+			}
+		}
+	}
+	return buffer.String()
 }
 
 const padding = 3
@@ -212,8 +360,20 @@ type rowStats struct {
 }
 
 type row struct {
-	meshed string
+	meshed   string
+	identity string
 	*rowStats
+	*tsStats
+
+	// outbound holds the stats of the requests sent by the resource, in the
+	// "both" direction
+	outbound *rowStats
+}
+
+type tsStats struct {
+	apex   string
+	leaf   string
+	weight uint32
 }
 
 var (
@@ -221,13 +381,17 @@ var (
 	namespaceHeader = "NAMESPACE"
 )
 
-func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer, options *statOptions) {
+func writeStatsToBuffer(rows, outboundRows []*pb.StatTable_PodGroup_Row, queries statQueries, w *tabwriter.Writer, options *statOptions) {
 	maxNameLength := len(nameHeader)
 	maxNamespaceLength := len(namespaceHeader)
 	statTables := make(map[string]map[string]*row)
 
+	// the outbound rows follow the inbound ones, and only add their stats to
+	// the rows of the same resources
+	allRows := append(append([]*pb.StatTable_PodGroup_Row{}, rows...), outboundRows...)
+
 	prefixTypes := make(map[string]bool)
-	for _, r := range rows {
+	for _, r := range allRows {
 		prefixTypes[r.Resource.Type] = true
 	}
 	usePrefix := false
@@ -235,7 +399,12 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		usePrefix = true
 	}
 
-	for _, r := range rows {
+	for i, r := range allRows {
+		outbound := i >= len(rows)
+		if options.onlyMeshed && r.MeshedPodCount == 0 && r.Resource.Type != k8s.Authority && r.Resource.Type != k8s.TrafficSplit {
+			continue
+		}
+
 		name := r.Resource.Name
 		nameWithPrefix := name
 		if usePrefix {
@@ -245,6 +414,10 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		namespace := r.Resource.Namespace
 		key := fmt.Sprintf("%s/%s", namespace, name)
 		resourceKey := r.Resource.Type
+		if r.TsStats != nil {
+			// traffic splits have a row for each of their backends
+			key = fmt.Sprintf("%s/%s", key, r.TsStats.Leaf)
+		}
 
 		if _, ok := statTables[resourceKey]; !ok {
 			statTables[resourceKey] = make(map[string]*row)
@@ -259,15 +432,26 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 
 		meshedCount := fmt.Sprintf("%d/%d", r.MeshedPodCount, r.RunningPodCount)
-		if resourceKey == k8s.Authority {
+		if resourceKey == k8s.Authority || resourceKey == k8s.TrafficSplit {
 			meshedCount = "-"
 		}
-		statTables[resourceKey][key] = &row{
-			meshed: meshedCount,
+		if _, ok := statTables[resourceKey][key]; !ok || !outbound {
+			statTables[resourceKey][key] = &row{
+				meshed:   meshedCount,
+				identity: rowIdentity(r),
+			}
+		}
+
+		if r.TsStats != nil {
+			statTables[resourceKey][key].tsStats = &tsStats{
+				apex:   r.TsStats.Apex,
+				leaf:   r.TsStats.Leaf,
+				weight: r.TsStats.Weight,
+			}
 		}
 
 		if r.Stats != nil {
-			statTables[resourceKey][key].rowStats = &rowStats{
+			stats := &rowStats{
 				requestRate: getRequestRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount(), r.TimeWindow),
 				successRate: getSuccessRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount()),
 				tlsPercent:  getPercentTLS(r.Stats),
@@ -275,6 +459,11 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 				latencyP95:  r.Stats.LatencyMsP95,
 				latencyP99:  r.Stats.LatencyMsP99,
 			}
+			if outbound {
+				statTables[resourceKey][key].outbound = stats
+			} else {
+				statTables[resourceKey][key].rowStats = stats
+			}
 		}
 	}
 
@@ -286,7 +475,26 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 		printStatTables(statTables, w, maxNameLength, maxNamespaceLength, options)
 	case "json":
-		printStatJSON(statTables, w)
+		printStatJSON(statTables, queries, w, options)
+	}
+}
+
+// rowIdentity returns the TLS identity the meshed pods of a row's resource are
+// issued certificates for, if the resource owns pods.
+func rowIdentity(r *pb.StatTable_PodGroup_Row) string {
+	if r.MeshedPodCount == 0 {
+		return ""
+	}
+	switch r.Resource.Type {
+	case k8s.DaemonSet, k8s.Deployment, k8s.Job, k8s.ReplicationController, k8s.StatefulSet:
+		return k8s.TLSIdentity{
+			Name:                r.Resource.Name,
+			Kind:                r.Resource.Type,
+			Namespace:           r.Resource.Namespace,
+			ControllerNamespace: controlPlaneNamespace,
+		}.ToDNSName()
+	default:
+		return ""
 	}
 }
 
@@ -307,12 +515,124 @@ func printStatTables(statTables map[string]map[string]*row, w *tabwriter.Writer,
 			if !usePrefix {
 				resourceTypeLabel = ""
 			}
-			printSingleStatTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			if resourceType == k8s.TrafficSplit {
+				printTrafficSplitTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			} else if options.noMetrics {
+				printMeshedTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			} else {
+				printSingleStatTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			}
 		}
 	}
 }
 
 func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
+	headers := make([]string, 0)
+	if options.allNamespaces {
+		headers = append(headers,
+			namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
+	}
+	headers = append(headers, nameHeader+strings.Repeat(" ", maxNameLength-len(nameHeader)), "MESHED")
+	if options.direction == bothDirections {
+		headers = append(headers, statHeaders("IN_")...)
+		headers = append(headers, statHeaders("OUT_")...)
+	} else {
+		headers = append(headers, statHeaders("")...)
+	}
+	if options.outputFormat == "wide" {
+		headers = append(headers, "IDENTITY")
+	}
+
+	fmt.Fprintln(w, strings.Join(headers, "\t")+"\t") // trailing \t is required to format last column
+
+	sortedKeys := sortStatsKeys(stats)
+	for _, key := range sortedKeys {
+		namespace, name := namespaceName(resourceType, key)
+		values := make([]string, 0)
+		if options.allNamespaces {
+			values = append(values,
+				namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
+		}
+		padding := 0
+		if maxNameLength > len(name) {
+			padding = maxNameLength - len(name)
+		}
+		values = append(values, name+strings.Repeat(" ", padding), stats[key].meshed)
+
+		values = append(values, statValues(stats[key].rowStats)...)
+		if options.direction == bothDirections {
+			values = append(values, statValues(stats[key].outbound)...)
+		}
+		if options.outputFormat == "wide" {
+			values = append(values, valueOrDash(stats[key].identity))
+		}
+
+		fmt.Fprintf(w, "%s\t\n", strings.Join(values, "\t"))
+	}
+}
+
+// statHeaders returns the headers of the stats columns, with the prefix of
+// their direction.
+func statHeaders(prefix string) []string {
+	headers := []string{"SUCCESS", "RPS", "LATENCY_P50", "LATENCY_P95", "LATENCY_P99", "TLS"}
+	for i := range headers {
+		headers[i] = prefix + headers[i]
+	}
+	return headers
+}
+
+// statValues returns the values of the stats columns, or dashes for the rows
+// without stats.
+func statValues(stats *rowStats) []string {
+	if stats == nil {
+		return []string{"-", "-", "-", "-", "-", "-"}
+	}
+	return []string{
+		fmt.Sprintf("%.2f%%", stats.successRate*100),
+		fmt.Sprintf("%.1frps", stats.requestRate),
+		fmt.Sprintf("%dms", stats.latencyP50),
+		fmt.Sprintf("%dms", stats.latencyP95),
+		fmt.Sprintf("%dms", stats.latencyP99),
+		fmt.Sprintf("%.f%%", stats.tlsPercent*100),
+	}
+}
+
+// printMeshedTable prints the meshed pods of the resources, without their
+// traffic stats, for the --no-metrics flag.
+func printMeshedTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
+	headers := make([]string, 0)
+	if options.allNamespaces {
+		headers = append(headers,
+			namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
+	}
+	headers = append(headers, nameHeader+strings.Repeat(" ", maxNameLength-len(nameHeader)))
+	if options.outputFormat == "wide" {
+		headers = append(headers, "MESHED", "IDENTITY\t")
+	} else {
+		headers = append(headers, "MESHED\t") // trailing \t is required to format last column
+	}
+
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	sortedKeys := sortStatsKeys(stats)
+	for _, key := range sortedKeys {
+		namespace, name := namespaceName(resourceType, key)
+		values := make([]string, 0)
+		if options.allNamespaces {
+			values = append(values, namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
+		}
+		values = append(values, name+strings.Repeat(" ", maxNameLength-len(name)), stats[key].meshed)
+		if options.outputFormat == "wide" {
+			values = append(values, valueOrDash(stats[key].identity))
+		}
+
+		fmt.Fprintf(w, "%s\t\n", strings.Join(values, "\t"))
+	}
+}
+
+// printTrafficSplitTable prints a row for each backend of the traffic splits,
+// with the weight it was given and the stats of the traffic it actually got.
+func printTrafficSplitTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
 	headers := make([]string, 0)
 	if options.allNamespaces {
 		headers = append(headers,
@@ -320,13 +640,14 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 	}
 	headers = append(headers, []string{
 		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
-		"MESHED",
+		"APEX",
+		"LEAF",
+		"WEIGHT",
 		"SUCCESS",
 		"RPS",
 		"LATENCY_P50",
 		"LATENCY_P95",
-		"LATENCY_P99",
-		"TLS\t", // trailing \t is required to format last column
+		"LATENCY_P99\t", // trailing \t is required to format last column
 	}...)
 
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
@@ -335,8 +656,8 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 	for _, key := range sortedKeys {
 		namespace, name := namespaceName(resourceType, key)
 		values := make([]interface{}, 0)
-		templateString := "%s\t%s\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t%.f%%\t\n"
-		templateStringEmpty := "%s\t%s\t-\t-\t-\t-\t-\t-\t\n"
+		templateString := "%s\t%s\t%s\t%d\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t\n"
+		templateStringEmpty := "%s\t%s\t%s\t%d\t-\t-\t-\t-\t-\t\n"
 
 		if options.allNamespaces {
 			values = append(values,
@@ -350,7 +671,9 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 		}
 		values = append(values, []interface{}{
 			name + strings.Repeat(" ", padding),
-			stats[key].meshed,
+			stats[key].apex,
+			stats[key].leaf,
+			stats[key].weight,
 		}...)
 
 		if stats[key].rowStats != nil {
@@ -360,7 +683,6 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 				stats[key].latencyP50,
 				stats[key].latencyP95,
 				stats[key].latencyP99,
-				stats[key].tlsPercent * 100,
 			}...)
 
 			fmt.Fprintf(w, templateString, values...)
@@ -380,19 +702,44 @@ func namespaceName(resourceType string, key string) (string, string) {
 
 // Using pointers where the value is NA and the corresponding json is null
 type jsonStats struct {
-	Namespace    string   `json:"namespace"`
-	Kind         string   `json:"kind"`
-	Name         string   `json:"name"`
-	Meshed       string   `json:"meshed"`
-	Success      *float64 `json:"success"`
-	Rps          *float64 `json:"rps"`
-	LatencyMSp50 *uint64  `json:"latency_ms_p50"`
-	LatencyMSp95 *uint64  `json:"latency_ms_p95"`
-	LatencyMSp99 *uint64  `json:"latency_ms_p99"`
-	TLS          *float64 `json:"tls"`
-}
-
-func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer) {
+	Namespace    string             `json:"namespace"`
+	Kind         string             `json:"kind"`
+	Name         string             `json:"name"`
+	Meshed       string             `json:"meshed"`
+	Success      *float64           `json:"success"`
+	Rps          *float64           `json:"rps"`
+	LatencyMSp50 *uint64            `json:"latency_ms_p50"`
+	LatencyMSp95 *uint64            `json:"latency_ms_p95"`
+	LatencyMSp99 *uint64            `json:"latency_ms_p99"`
+	TLS          *float64           `json:"tls"`
+	Identity     string             `json:"identity,omitempty"`
+	Apex         string             `json:"apex,omitempty"`
+	Leaf         string             `json:"leaf,omitempty"`
+	Weight       *uint32            `json:"weight,omitempty"`
+	Outbound     *jsonOutboundStats `json:"outbound,omitempty"`
+	Queries      map[string]string  `json:"queries,omitempty"`
+}
+
+// jsonOutboundStats holds the stats of the requests sent by a resource, in
+// the "both" direction.
+type jsonOutboundStats struct {
+	Success      *float64          `json:"success"`
+	Rps          *float64          `json:"rps"`
+	LatencyMSp50 *uint64           `json:"latency_ms_p50"`
+	LatencyMSp95 *uint64           `json:"latency_ms_p95"`
+	LatencyMSp99 *uint64           `json:"latency_ms_p99"`
+	TLS          *float64          `json:"tls"`
+	Queries      map[string]string `json:"queries,omitempty"`
+}
+
+func printStatJSON(statTables map[string]map[string]*row, queries statQueries, w *tabwriter.Writer, options *statOptions) {
+	// the stats of the rows are in the requested direction, or inbound when
+	// displaying both
+	direction := options.direction
+	if direction == bothDirections {
+		direction = inboundDirection
+	}
+
 	// avoid nil initialization so that if there are not stats it gets marshalled as an empty array vs null
 	entries := []*jsonStats{}
 	for _, resourceType := range k8s.AllResources {
@@ -405,6 +752,7 @@ func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer) {
 					Kind:      resourceType,
 					Name:      name,
 					Meshed:    stats[key].meshed,
+					Identity:  stats[key].identity,
 				}
 				if stats[key].rowStats != nil {
 					entry.Success = &stats[key].successRate
@@ -413,6 +761,23 @@ func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer) {
 					entry.LatencyMSp95 = &stats[key].latencyP95
 					entry.LatencyMSp99 = &stats[key].latencyP99
 					entry.TLS = &stats[key].tlsPercent
+					entry.Queries = queries[queriesKey{resourceType, direction}]
+				}
+				if outbound := stats[key].outbound; outbound != nil {
+					entry.Outbound = &jsonOutboundStats{
+						Success:      &outbound.successRate,
+						Rps:          &outbound.requestRate,
+						LatencyMSp50: &outbound.latencyP50,
+						LatencyMSp95: &outbound.latencyP95,
+						LatencyMSp99: &outbound.latencyP99,
+						TLS:          &outbound.tlsPercent,
+						Queries:      queries[queriesKey{resourceType, outboundDirection}],
+					}
+				}
+				if stats[key].tsStats != nil {
+					entry.Apex = stats[key].apex
+					entry.Leaf = stats[key].leaf
+					entry.Weight = &stats[key].weight
 				}
 
 				entries = append(entries, entry)
@@ -442,18 +807,30 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 		return nil, err
 	}
 
+	// Without --to or --from, the --to-namespace and --from-namespace flags
+	// are shorthands for the namespace resources, that filter the requests by
+	// their dst_namespace and namespace labels.
 	var toRes, fromRes pb.Resource
 	if options.toResource != "" {
 		toRes, err = util.BuildResource(options.toNamespace, options.toResource)
 		if err != nil {
 			return nil, err
 		}
+	} else if options.toNamespace != "" {
+		toRes = pb.Resource{Type: k8s.Namespace, Name: options.toNamespace}
 	}
 	if options.fromResource != "" {
 		fromRes, err = util.BuildResource(options.fromNamespace, options.fromResource)
 		if err != nil {
 			return nil, err
 		}
+	} else if options.fromNamespace != "" {
+		fromRes = pb.Resource{Type: k8s.Namespace, Name: options.fromNamespace}
+	}
+
+	directions := []string{options.direction}
+	if options.direction == bothDirections {
+		directions = []string{inboundDirection, outboundDirection}
 	}
 
 	requests := make([]*pb.StatSummaryRequest, 0)
@@ -463,27 +840,32 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 			return nil, err
 		}
 
-		requestParams := util.StatsSummaryRequestParams{
-			StatsBaseRequestParams: util.StatsBaseRequestParams{
-				TimeWindow:    options.timeWindow,
-				ResourceName:  target.Name,
-				ResourceType:  target.Type,
-				Namespace:     options.namespace,
-				AllNamespaces: options.allNamespaces,
-			},
-			ToName:        toRes.Name,
-			ToType:        toRes.Type,
-			ToNamespace:   options.toNamespace,
-			FromName:      fromRes.Name,
-			FromType:      fromRes.Type,
-			FromNamespace: options.fromNamespace,
-		}
+		for _, direction := range directions {
+			requestParams := util.StatsSummaryRequestParams{
+				StatsBaseRequestParams: util.StatsBaseRequestParams{
+					TimeWindow:    options.timeWindow,
+					ResourceName:  target.Name,
+					ResourceType:  target.Type,
+					Namespace:     options.namespace,
+					AllNamespaces: options.allNamespaces,
+				},
+				ToName:        toRes.Name,
+				ToType:        toRes.Type,
+				ToNamespace:   options.toNamespace,
+				FromName:      fromRes.Name,
+				FromType:      fromRes.Type,
+				FromNamespace: options.fromNamespace,
+				SkipStats:     options.noMetrics,
+				Direction:     direction,
+				ShowQueries:   options.showQueries,
+			}
 
-		req, err := util.BuildStatSummaryRequest(requestParams)
-		if err != nil {
-			return nil, err
+			req, err := util.BuildStatSummaryRequest(requestParams)
+			if err != nil {
+				return nil, err
+			}
+			requests = append(requests, req)
 		}
-		requests = append(requests, req)
 	}
 	return requests, nil
 }
@@ -500,13 +882,23 @@ func sortStatsKeys(stats map[string]*row) []string {
 // validate performs all validation on the command-line options.
 // It returns the first error encountered, or `nil` if the options are valid.
 func (o *statOptions) validate(resourceType string) error {
-	err := o.validateConflictingFlags()
-	if err != nil {
+	if resourceType == k8s.Namespace {
+		err := o.validateNamespaceFlags()
+		if err != nil {
+			return err
+		}
+	}
+
+	if resourceType == k8s.TrafficSplit && o.hasToOrFrom() {
+		return fmt.Errorf("trafficsplits are not supported with the --to or --from flags")
+	}
+
+	if err := o.validateDirection(resourceType); err != nil {
 		return err
 	}
 
-	if resourceType == k8s.Namespace {
-		err := o.validateNamespaceFlags()
+	if o.noMetrics {
+		err := o.validateNoMetricsFlag(resourceType)
 		if err != nil {
 			return err
 		}
@@ -515,15 +907,46 @@ func (o *statOptions) validate(resourceType string) error {
 	return o.validateOutputFormat()
 }
 
-// validateConflictingFlags validates that the options do not contain mutually
-// exclusive flags.
-func (o *statOptions) validateConflictingFlags() error {
-	if o.toResource != "" && o.fromResource != "" {
-		return fmt.Errorf("--to and --from flags are mutually exclusive")
+// hasToOrFrom returns whether the stats are restricted to the requests sent
+// to or from a resource, including the --to-namespace and --from-namespace
+// shorthands.
+func (o *statOptions) hasToOrFrom() bool {
+	return o.toResource != "" || o.fromResource != "" || o.toNamespace != "" || o.fromNamespace != ""
+}
+
+// validateOutputFormat extends the output formats shared with the other stat
+// commands with "wide", which adds the TLS identity of the resources.
+func (o *statOptions) validateOutputFormat() error {
+	switch o.outputFormat {
+	case "table", "wide", "json", "":
+		return nil
+	default:
+		return errors.New("--output currently only supports table, wide, and json")
+	}
+}
+
+// validateDirection validates the --direction flag, which only applies to the
+// stats of the resources themselves, as the --to and --from flags already
+// display outbound stats.
+func (o *statOptions) validateDirection(resourceType string) error {
+	switch o.direction {
+	case inboundDirection:
+		return nil
+	case outboundDirection, bothDirections:
+	default:
+		return errors.New("--direction currently only supports inbound, outbound, and both")
+	}
+
+	if o.hasToOrFrom() {
+		return fmt.Errorf("--direction flag is incompatible with the --to and --from flags")
 	}
 
-	if o.toNamespace != "" && o.fromNamespace != "" {
-		return fmt.Errorf("--to-namespace and --from-namespace flags are mutually exclusive")
+	if resourceType == k8s.TrafficSplit {
+		return fmt.Errorf("trafficsplits are not supported with the --direction flag")
+	}
+
+	if o.noMetrics {
+		return fmt.Errorf("--direction flag is incompatible with the --no-metrics flag")
 	}
 
 	return nil
@@ -532,14 +955,6 @@ func (o *statOptions) validateConflictingFlags() error {
 // validateNamespaceFlags performs additional validation for options when the target
 // resource type is a namespace.
 func (o *statOptions) validateNamespaceFlags() error {
-	if o.toNamespace != "" {
-		return fmt.Errorf("--to-namespace flag is incompatible with namespace resource type")
-	}
-
-	if o.fromNamespace != "" {
-		return fmt.Errorf("--from-namespace flag is incompatible with namespace resource type")
-	}
-
 	// Note: technically, this allows you to say `stat ns --namespace default`, but that
 	// seems like an edge case.
 	if o.namespace != "default" {
@@ -548,3 +963,21 @@ func (o *statOptions) validateNamespaceFlags() error {
 
 	return nil
 }
+
+// validateNoMetricsFlag performs additional validation for options with the
+// --no-metrics flag, which can only display the resources that own pods.
+func (o *statOptions) validateNoMetricsFlag(resourceType string) error {
+	if o.hasToOrFrom() {
+		return fmt.Errorf("--no-metrics flag is incompatible with the --to and --from flags")
+	}
+
+	if o.showQueries {
+		return fmt.Errorf("--no-metrics flag is incompatible with the --show-queries flag")
+	}
+
+	if resourceType == k8s.Authority {
+		return fmt.Errorf("--no-metrics flag is incompatible with authority resource type")
+	}
+
+	return nil
+}
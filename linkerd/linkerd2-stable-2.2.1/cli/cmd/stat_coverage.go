@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// namespaceCoverage is the mesh adoption of the workloads of a namespace.
+type namespaceCoverage struct {
+	Namespace     string   `json:"namespace"`
+	Workloads     int      `json:"workloads"`
+	Meshed        int      `json:"meshed"`
+	Unmeshed      int      `json:"unmeshed"`
+	ProxyVersions []string `json:"proxy_versions"`
+	AutoInject    string   `json:"auto_inject"`
+}
+
+// workloadPods selects the pods of a workload, whose template may already be
+// annotated by linkerd inject.
+type workloadPods struct {
+	selector    *meta_v1.LabelSelector
+	annotations map[string]string
+}
+
+// statMeshCoverage reports the mesh coverage of the namespaces targeted by
+// args, for the --coverage flag.
+func statMeshCoverage(args []string, options *statOptions, w io.Writer) error {
+	targets, err := util.BuildResources(options.namespace, args)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	allNamespaces := false
+	for _, target := range targets {
+		if target.Type != k8s.Namespace {
+			return fmt.Errorf("--coverage flag is only supported with namespace resource type")
+		}
+		if target.Name == "" {
+			allNamespaces = true
+		}
+		names = append(names, target.Name)
+	}
+	if allNamespaces {
+		names = []string{}
+	}
+
+	kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, clientAuth())
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+	if err != nil {
+		return err
+	}
+
+	coverage, err := collectMeshCoverage(clientset, controlPlaneNamespace, names, options.onlyMeshed)
+	if err != nil {
+		return err
+	}
+
+	return renderMeshCoverage(coverage, options, w)
+}
+
+// collectMeshCoverage returns the mesh coverage of the named namespaces, or of
+// all the namespaces if there are no names. A workload is meshed if any of its
+// pods is, or if its pod template was injected.
+func collectMeshCoverage(client kubernetes.Interface, controlPlaneNamespace string, names []string, onlyMeshed bool) ([]namespaceCoverage, error) {
+	namespaces := []v1.Namespace{}
+	if len(names) == 0 {
+		list, err := client.CoreV1().Namespaces().List(meta_v1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		namespaces = list.Items
+	} else {
+		for _, name := range names {
+			ns, err := client.CoreV1().Namespaces().Get(name, meta_v1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			namespaces = append(namespaces, *ns)
+		}
+	}
+
+	coverage := []namespaceCoverage{}
+	for _, ns := range namespaces {
+		workloads, err := listWorkloadPods(client, ns.Name)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := client.CoreV1().Pods(ns.Name).List(meta_v1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		row := namespaceCoverage{
+			Namespace:     ns.Name,
+			Workloads:     len(workloads),
+			ProxyVersions: []string{},
+			AutoInject:    ns.Annotations[k8s.ProxyInjectAnnotation],
+		}
+		versions := make(map[string]bool)
+		for _, workload := range workloads {
+			selector, err := meta_v1.LabelSelectorAsSelector(workload.selector)
+			if err != nil {
+				return nil, err
+			}
+
+			meshed := workload.annotations[k8s.ProxyVersionAnnotation] != ""
+			for _, pod := range pods.Items {
+				if !selector.Matches(labels.Set(pod.Labels)) || !k8s.IsMeshed(&pod, controlPlaneNamespace) {
+					continue
+				}
+				meshed = true
+				if version := pod.Annotations[k8s.ProxyVersionAnnotation]; version != "" {
+					versions[version] = true
+				}
+			}
+			if meshed {
+				row.Meshed++
+			}
+		}
+		row.Unmeshed = row.Workloads - row.Meshed
+
+		for version := range versions {
+			row.ProxyVersions = append(row.ProxyVersions, version)
+		}
+		sort.Strings(row.ProxyVersions)
+
+		if onlyMeshed && row.Meshed == 0 {
+			continue
+		}
+		coverage = append(coverage, row)
+	}
+
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Namespace < coverage[j].Namespace
+	})
+	return coverage, nil
+}
+
+// listWorkloadPods returns the pod selectors of the workloads of a namespace.
+func listWorkloadPods(client kubernetes.Interface, namespace string) ([]workloadPods, error) {
+	workloads := []workloadPods{}
+
+	deploys, err := client.AppsV1().Deployments(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deploys.Items {
+		workloads = append(workloads, workloadPods{d.Spec.Selector, d.Spec.Template.Annotations})
+	}
+
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonSets.Items {
+		workloads = append(workloads, workloadPods{ds.Spec.Selector, ds.Spec.Template.Annotations})
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range statefulSets.Items {
+		workloads = append(workloads, workloadPods{ss.Spec.Selector, ss.Spec.Template.Annotations})
+	}
+
+	rcs, err := client.CoreV1().ReplicationControllers(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, rc := range rcs.Items {
+		var annotations map[string]string
+		if rc.Spec.Template != nil {
+			annotations = rc.Spec.Template.Annotations
+		}
+		workloads = append(workloads, workloadPods{&meta_v1.LabelSelector{MatchLabels: rc.Spec.Selector}, annotations})
+	}
+
+	return workloads, nil
+}
+
+func renderMeshCoverage(coverage []namespaceCoverage, options *statOptions, w io.Writer) error {
+	if options.outputFormat == "json" {
+		b, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	if len(coverage) == 0 {
+		fmt.Fprintln(os.Stderr, "No namespaces found.")
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{namespaceHeader, "WORKLOADS", "MESHED", "UNMESHED", "COVERAGE", "PROXY_VERSIONS", "AUTO_INJECT"}, "\t"))
+	for _, row := range coverage {
+		percent := "-"
+		if row.Workloads > 0 {
+			percent = fmt.Sprintf("%.f%%", float64(row.Meshed)/float64(row.Workloads)*100)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\n",
+			row.Namespace, row.Workloads, row.Meshed, row.Unmeshed, percent,
+			valueOrDash(strings.Join(row.ProxyVersions, ",")), valueOrDash(row.AutoInject))
+	}
+	tw.Flush()
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollectMeshCoverage(t *testing.T) {
+	namespace := func(name string, annotations map[string]string) *v1.Namespace {
+		return &v1.Namespace{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Annotations: annotations},
+		}
+	}
+	deployment := func(namespace, name string, annotations map[string]string) *appsV1.Deployment {
+		return &appsV1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: appsV1.DeploymentSpec{
+				Selector: &meta_v1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: meta_v1.ObjectMeta{Annotations: annotations},
+				},
+			},
+		}
+	}
+	pod := func(namespace, app, version string) *v1.Pod {
+		p := &v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      app + "-pod",
+				Namespace: namespace,
+				Labels:    map[string]string{"app": app},
+			},
+		}
+		if version != "" {
+			p.Labels[k8s.ControllerNSLabel] = "linkerd"
+			p.Annotations = map[string]string{k8s.ProxyVersionAnnotation: version}
+		}
+		return p
+	}
+
+	objects := []runtime.Object{
+		namespace("emojivoto", map[string]string{k8s.ProxyInjectAnnotation: k8s.ProxyInjectEnabled}),
+		namespace("books", nil),
+		namespace("default", nil),
+		deployment("emojivoto", "web", nil),
+		deployment("emojivoto", "emoji", nil),
+		deployment("emojivoto", "voting", nil),
+		deployment("books", "authors", map[string]string{k8s.ProxyVersionAnnotation: "stable-2.2.1"}),
+		deployment("default", "nginx", nil),
+		pod("emojivoto", "web", "stable-2.2.1"),
+		pod("emojivoto", "emoji", "stable-2.2.0"),
+		pod("emojivoto", "voting", ""),
+		pod("default", "nginx", ""),
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	t.Run("Returns the coverage of all namespaces", func(t *testing.T) {
+		coverage, err := collectMeshCoverage(client, "linkerd", []string{}, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []namespaceCoverage{
+			{Namespace: "books", Workloads: 1, Meshed: 1, Unmeshed: 0, ProxyVersions: []string{}},
+			{Namespace: "default", Workloads: 1, Meshed: 0, Unmeshed: 1, ProxyVersions: []string{}},
+			{Namespace: "emojivoto", Workloads: 3, Meshed: 2, Unmeshed: 1, ProxyVersions: []string{"stable-2.2.0", "stable-2.2.1"}, AutoInject: k8s.ProxyInjectEnabled},
+		}
+		if !reflect.DeepEqual(coverage, expected) {
+			t.Fatalf("Expected coverage %+v, got %+v", expected, coverage)
+		}
+
+		var buf bytes.Buffer
+		if err := renderMeshCoverage(coverage, newStatOptions(), &buf); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		diffCompareFile(t, buf.String(), "stat_coverage_output.golden")
+	})
+
+	t.Run("Returns the coverage of the meshed named namespaces", func(t *testing.T) {
+		coverage, err := collectMeshCoverage(client, "linkerd", []string{"default", "emojivoto"}, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if len(coverage) != 1 || coverage[0].Namespace != "emojivoto" {
+			t.Fatalf("Expected the coverage of the emojivoto namespace only, got %+v", coverage)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 )
 
@@ -72,11 +73,52 @@ func TestStat(t *testing.T) {
 		}, t)
 	})
 
-	t.Run("Returns an error for named resource queries with the --all-namespaces flag", func(t *testing.T) {
+	t.Run("Returns the identity of deployments (wide)", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 1, RunningPods: 1}, true)
+		mockClient := &public.MockAPIClient{}
+		mockClient.StatSummaryResponseToReturn = &response
+
 		options := newStatOptions()
-		options.allNamespaces = true
-		args := []string{"po", "web"}
-		expectedError := "stats for a resource cannot be retrieved by name across all namespaces"
+		options.outputFormat = "wide"
+		reqs, err := buildStatSummaryRequests([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		resp, err := requestStatsFromAPI(mockClient, reqs[0], options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := renderStatStats(respToRows(resp), options)
+		diffCompareFile(t, output, "stat_wide_output.golden")
+	})
+
+	t.Run("Returns the inbound and outbound stats of deployments side by side", func(t *testing.T) {
+		options := newStatOptions()
+		options.direction = bothDirections
+		reqs, err := buildStatSummaryRequests([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(reqs) != 2 || reqs[0].GetDirection() != inboundDirection || reqs[1].GetDirection() != outboundDirection {
+			t.Fatalf("Expected an inbound and an outbound request, got %v", reqs)
+		}
+
+		inbound := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 1, RunningPods: 1}, true)
+		outbound := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 1, RunningPods: 1}, true)
+		outbound.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats.FailureCount = 123
+
+		output := renderBothDirectionsStatStats(respToRows(&inbound), respToRows(&outbound), options)
+		diffCompareFile(t, output, "stat_both_directions_output.golden")
+	})
+
+	t.Run("Rejects the --direction flag with the --from flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.direction = outboundDirection
+		options.fromResource = "deploy/foo"
+		args := []string{"po"}
+		expectedError := "--direction flag is incompatible with the --to and --from flags"
 
 		_, err := buildStatSummaryRequests(args, options)
 		if err == nil || err.Error() != expectedError {
@@ -84,12 +126,11 @@ func TestStat(t *testing.T) {
 		}
 	})
 
-	t.Run("Rejects commands with both --to and --from flags", func(t *testing.T) {
+	t.Run("Rejects unknown directions", func(t *testing.T) {
 		options := newStatOptions()
-		options.toResource = "deploy/foo"
-		options.fromResource = "deploy/bar"
-		args := []string{"ns", "test"}
-		expectedError := "--to and --from flags are mutually exclusive"
+		options.direction = "sideways"
+		args := []string{"po"}
+		expectedError := "--direction currently only supports inbound, outbound, and both"
 
 		_, err := buildStatSummaryRequests(args, options)
 		if err == nil || err.Error() != expectedError {
@@ -97,12 +138,90 @@ func TestStat(t *testing.T) {
 		}
 	})
 
-	t.Run("Rejects commands with both --to-namespace and --from-namespace flags", func(t *testing.T) {
+	t.Run("Returns the backends of traffic splits", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("web.emojivoto.svc.cluster.local", k8s.TrafficSplit, []string{"emojivoto", "emojivoto"}, nil, true)
+		rows := response.GetOk().StatTables[0].GetPodGroup().Rows
+		rows[0].TsStats = &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v1", Weight: 90}
+		rows[1].TsStats = &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v2", Weight: 10}
+		rows[1].Stats = nil
+
+		mockClient := &public.MockAPIClient{}
+		mockClient.StatSummaryResponseToReturn = &response
+
 		options := newStatOptions()
-		options.toNamespace = "foo"
-		options.fromNamespace = "bar"
+		reqs, err := buildStatSummaryRequests([]string{"ts"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		resp, err := requestStatsFromAPI(mockClient, reqs[0], options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := renderStatStats(respToRows(resp), options)
+		diffCompareFile(t, output, "stat_ts_output.golden")
+	})
+
+	t.Run("Returns the meshed pods of deployments without metrics", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 1, RunningPods: 2}, false)
+		mockClient := &public.MockAPIClient{}
+		mockClient.StatSummaryResponseToReturn = &response
+
+		options := newStatOptions()
+		options.noMetrics = true
+		reqs, err := buildStatSummaryRequests([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reqs[0].GetSkipStats() {
+			t.Fatalf("Expected the request to skip stats")
+		}
+
+		resp, err := requestStatsFromAPI(mockClient, reqs[0], options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := renderStatStats(respToRows(resp), options)
+		diffCompareFile(t, output, "stat_no_metrics_output.golden")
+	})
+
+	t.Run("Returns the queries of the stats of deployments", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 1, RunningPods: 1}, true)
+		response.GetOk().StatTables[0].GetPodGroup().Queries = map[string]string{
+			"requests":       `sum(increase(response_total{direction="inbound", namespace="emojivoto"}[1m])) by (namespace, deployment, classification, tls)`,
+			"latency_ms_p50": `histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, deployment))`,
+		}
+		mockClient := &public.MockAPIClient{}
+		mockClient.StatSummaryResponseToReturn = &response
+
+		options := newStatOptions()
+		options.showQueries = true
+		reqs, err := buildStatSummaryRequests([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reqs[0].GetShowQueries() {
+			t.Fatalf("Expected the request to show queries")
+		}
+
+		resp, err := requestStatsFromAPI(mockClient, reqs[0], options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		queries := respToQueries(resp, reqs[0].GetDirection())
+		output := renderStatStatsWithQueries(respToRows(resp), nil, queries, options)
+		diffCompareFile(t, output, "stat_queries_output.golden")
+	})
+
+	t.Run("Rejects the --no-metrics flag with the --to flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.noMetrics = true
+		options.toResource = "deploy/foo"
 		args := []string{"po"}
-		expectedError := "--to-namespace and --from-namespace flags are mutually exclusive"
+		expectedError := "--no-metrics flag is incompatible with the --to and --from flags"
 
 		_, err := buildStatSummaryRequests(args, options)
 		if err == nil || err.Error() != expectedError {
@@ -110,11 +229,35 @@ func TestStat(t *testing.T) {
 		}
 	})
 
-	t.Run("Rejects --to-namespace flag when the target is a namespace", func(t *testing.T) {
+	t.Run("Filters out the resources without meshed pods with the --only-meshed flag", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("emoji", k8s.Deployment, []string{"emojivoto"}, &public.PodCounts{MeshedPods: 0, RunningPods: 2}, true)
+		mockClient := &public.MockAPIClient{}
+		mockClient.StatSummaryResponseToReturn = &response
+
 		options := newStatOptions()
-		options.toNamespace = "bar"
-		args := []string{"ns", "foo"}
-		expectedError := "--to-namespace flag is incompatible with namespace resource type"
+		options.onlyMeshed = true
+		options.outputFormat = "json"
+		reqs, err := buildStatSummaryRequests([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		resp, err := requestStatsFromAPI(mockClient, reqs[0], options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := renderStatStats(respToRows(resp), options)
+		if output != "[]\n" {
+			t.Fatalf("Expected no resources, got [%s]", output)
+		}
+	})
+
+	t.Run("Rejects traffic splits with the --to flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.toResource = "deploy/foo"
+		args := []string{"ts"}
+		expectedError := "trafficsplits are not supported with the --to or --from flags"
 
 		_, err := buildStatSummaryRequests(args, options)
 		if err == nil || err.Error() != expectedError {
@@ -122,11 +265,133 @@ func TestStat(t *testing.T) {
 		}
 	})
 
-	t.Run("Rejects --from-namespace flag when the target is a namespace", func(t *testing.T) {
+	t.Run("Returns an error for named resource queries with the --all-namespaces flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.allNamespaces = true
+		args := []string{"po", "web"}
+		expectedError := "stats for a resource cannot be retrieved by name across all namespaces"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Restricts the --from stats to the --to resource", func(t *testing.T) {
+		options := newStatOptions()
+		options.toResource = "svc/foo"
+		options.toNamespace = "foo"
+		options.fromResource = "deploy/bar"
+		args := []string{"deploy"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		from := reqs[0].GetFromResource()
+		if from == nil || from.Type != k8s.Deployment || from.Name != "bar" || from.Namespace != "default" {
+			t.Fatalf("Expected the request to come from deploy/bar in the default namespace, got %+v", from)
+		}
+		to := reqs[0].GetToFilter()
+		if to == nil || to.Type != k8s.Service || to.Name != "foo" || to.Namespace != "foo" {
+			t.Fatalf("Expected the request to be filtered to svc/foo in the foo namespace, got %+v", to)
+		}
+	})
+
+	t.Run("Expands the --to-namespace and --from-namespace flags to namespace resources", func(t *testing.T) {
+		options := newStatOptions()
+		options.toNamespace = "bar"
+		options.fromNamespace = "foo"
+		args := []string{"deploy"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		from := reqs[0].GetFromResource()
+		if from == nil || from.Type != k8s.Namespace || from.Name != "foo" {
+			t.Fatalf("Expected the request to come from the foo namespace, got %+v", from)
+		}
+		to := reqs[0].GetToFilter()
+		if to == nil || to.Type != k8s.Namespace || to.Name != "bar" {
+			t.Fatalf("Expected the request to be filtered to the bar namespace, got %+v", to)
+		}
+	})
+
+	t.Run("Accepts the --from-namespace flag when the target is a namespace", func(t *testing.T) {
 		options := newStatOptions()
 		options.fromNamespace = "foo"
 		args := []string{"ns/bar"}
-		expectedError := "--from-namespace flag is incompatible with namespace resource type"
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		from := reqs[0].GetFromResource()
+		if from == nil || from.Type != k8s.Namespace || from.Name != "foo" {
+			t.Fatalf("Expected the request to come from the foo namespace, got %+v", from)
+		}
+	})
+
+	t.Run("Rejects the --to-namespace flag with the --direction flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.toNamespace = "bar"
+		options.direction = outboundDirection
+		args := []string{"deploy"}
+		expectedError := "--direction flag is incompatible with the --to and --from flags"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Looks up the --to resource of a namespace target in the --to-namespace namespace", func(t *testing.T) {
+		options := newStatOptions()
+		options.toResource = "deploy/foo"
+		options.toNamespace = "bar"
+		args := []string{"ns"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		to := reqs[0].GetToResource()
+		if to == nil || to.Type != k8s.Deployment || to.Name != "foo" || to.Namespace != "bar" {
+			t.Fatalf("Expected the request to be sent to deploy/foo in the bar namespace, got %+v", to)
+		}
+	})
+
+	t.Run("Looks up the --to resource in the --namespace namespace across all namespaces", func(t *testing.T) {
+		options := newStatOptions()
+		options.namespace = "bar"
+		options.allNamespaces = true
+		options.toResource = "svc/foo"
+		args := []string{"deploy"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if namespace := reqs[0].GetSelector().GetResource().GetNamespace(); namespace != "" {
+			t.Fatalf("Expected the deployments of all namespaces, got the %s namespace", namespace)
+		}
+		to := reqs[0].GetToResource()
+		if to == nil || to.Type != k8s.Service || to.Name != "foo" || to.Namespace != "bar" {
+			t.Fatalf("Expected the request to be sent to svc/foo in the bar namespace, got %+v", to)
+		}
+	})
+
+	t.Run("Rejects --namespace flag when the target is a namespace", func(t *testing.T) {
+		options := newStatOptions()
+		options.namespace = "foo"
+		args := []string{"ns/bar"}
+		expectedError := "--namespace flag is incompatible with namespace resource type"
 
 		_, err := buildStatSummaryRequests(args, options)
 		if err == nil || err.Error() != expectedError {
@@ -12,34 +12,41 @@ import (
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/addr"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgUtil "github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
 )
 
 type tapOptions struct {
-	namespace   string
-	toResource  string
-	toNamespace string
-	maxRps      float32
-	scheme      string
-	method      string
-	authority   string
-	path        string
-	output      string
+	namespace    string
+	toResource   string
+	toNamespace  string
+	maxRps       float32
+	maxPods      uint32
+	pods         []string
+	scheme       string
+	method       string
+	authority    string
+	path         string
+	output       string
+	showStreamID bool
 }
 
 func newTapOptions() *tapOptions {
 	return &tapOptions{
-		namespace:   "default",
-		toResource:  "",
-		toNamespace: "",
-		maxRps:      100.0,
-		scheme:      "",
-		method:      "",
-		authority:   "",
-		path:        "",
-		output:      "",
+		namespace:    "default",
+		toResource:   "",
+		toNamespace:  "",
+		maxRps:       100.0,
+		maxPods:      0,
+		pods:         []string{},
+		scheme:       "",
+		method:       "",
+		authority:    "",
+		path:         "",
+		output:       "",
+		showStreamID: false,
 	}
 }
 
@@ -79,7 +86,10 @@ func newCmdTap() *cobra.Command {
   linkerd tap pod/web-dlbvj
 
   # tap the test namespace, filter by request to prod namespace
-  linkerd tap ns/test --to ns/prod`,
+  linkerd tap ns/test --to ns/prod
+
+  # tap at most 5 of the pods of the web deployment
+  linkerd tap deploy/web --max-pods 5`,
 		Args:      cobra.RangeArgs(1, 2),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -89,6 +99,8 @@ func newCmdTap() *cobra.Command {
 				ToResource:  options.toResource,
 				ToNamespace: options.toNamespace,
 				MaxRps:      options.maxRps,
+				MaxPods:     options.maxPods,
+				Pods:        options.pods,
 				Scheme:      options.scheme,
 				Method:      options.method,
 				Authority:   options.authority,
@@ -111,7 +123,7 @@ func newCmdTap() *cobra.Command {
 				return fmt.Errorf("output format \"%s\" not recognized", options.output)
 			}
 
-			return requestTapByResourceFromAPI(os.Stdout, cliPublicAPIClient(), req, wide)
+			return requestTapByResourceFromAPI(os.Stdout, cliPublicAPIClient(), req, wide, options.showStreamID)
 		},
 	}
 
@@ -123,21 +135,27 @@ func newCmdTap() *cobra.Command {
 		"Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().Float32Var(&options.maxRps, "max-rps", options.maxRps,
 		"Maximum requests per second to tap.")
+	cmd.PersistentFlags().Uint32Var(&options.maxPods, "max-pods", options.maxPods,
+		"Maximum number of pods of the specified resource to tap; 0 taps all of them")
+	cmd.PersistentFlags().StringSliceVar(&options.pods, "pods", options.pods,
+		"Only tap these pods of the specified resource (comma-separated)")
 	cmd.PersistentFlags().StringVar(&options.scheme, "scheme", options.scheme,
 		"Display requests with this scheme")
 	cmd.PersistentFlags().StringVar(&options.method, "method", options.method,
 		"Display requests with this HTTP method")
 	cmd.PersistentFlags().StringVar(&options.authority, "authority", options.authority,
-		"Display requests with this :authority")
+		"Display requests with this :authority, or starting with it if it ends with a \"*\" wildcard")
 	cmd.PersistentFlags().StringVar(&options.path, "path", options.path,
 		"Display requests with paths that start with this prefix")
 	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output,
 		"Output format. One of: wide")
+	cmd.PersistentFlags().BoolVar(&options.showStreamID, "show-stream-id", options.showStreamID,
+		"Print the ID of the tap session, which the logs of the control plane components serving it carry; it is not an ID of the tapped requests")
 
 	return cmd
 }
 
-func requestTapByResourceFromAPI(w io.Writer, client pb.ApiClient, req *pb.TapByResourceRequest, wide bool) error {
+func requestTapByResourceFromAPI(w io.Writer, client pb.ApiClient, req *pb.TapByResourceRequest, wide, showStreamID bool) error {
 	var resource string
 	if wide {
 		resource = req.Target.Resource.GetType()
@@ -147,9 +165,31 @@ func requestTapByResourceFromAPI(w io.Writer, client pb.ApiClient, req *pb.TapBy
 	if err != nil {
 		return err
 	}
+	if showStreamID {
+		if err := renderTapStreamID(w, rsp); err != nil {
+			return err
+		}
+	}
 	return renderTap(w, rsp, resource)
 }
 
+// renderTapStreamID prints the ID of the tap session, i.e. the request ID of
+// the tap stream, which the logs of the control plane components serving it
+// carry. It identifies the session, not the tapped requests: the tap events
+// don't carry their headers.
+func renderTapStreamID(w io.Writer, tapClient pb.Api_TapByResourceClient) error {
+	header, err := tapClient.Header()
+	if err != nil {
+		return err
+	}
+	id := "unknown"
+	if ids := header[pkgUtil.RequestIDMetadataKey]; len(ids) > 0 {
+		id = ids[0]
+	}
+	_, err = fmt.Fprintf(w, "tap stream-id=%s\n", id)
+	return err
+}
+
 func renderTap(w io.Writer, tapClient pb.Api_TapByResourceClient, resource string) error {
 	tableWriter := tabwriter.NewWriter(w, 0, 0, 0, ' ', tabwriter.AlignRight)
 	err := writeTapEventsToBuffer(tapClient, tableWriter, resource)
@@ -188,13 +228,16 @@ func renderTapEvent(event *pb.TapEvent, resource string) string {
 
 	proxy := "???"
 	tls := ""
+	identity := ""
 	switch event.GetProxyDirection() {
 	case pb.TapEvent_INBOUND:
 		proxy = "in " // A space is added so it aligns with `out`.
 		tls = src.tlsStatus()
+		identity = src.tlsIdentity()
 	case pb.TapEvent_OUTBOUND:
 		proxy = "out"
 		tls = dst.tlsStatus()
+		identity = dst.tlsIdentity()
 	default:
 		// Too old for TLS.
 	}
@@ -205,6 +248,9 @@ func renderTapEvent(event *pb.TapEvent, resource string) string {
 		dst.formatAddr(),
 		tls,
 	)
+	if identity != "" {
+		flow = fmt.Sprintf("%s tls_identity=%s", flow, identity)
+	}
 
 	// If `resource` is non-empty, then
 	resources := ""
@@ -348,6 +394,12 @@ func (p *peer) tlsStatus() string {
 	return p.labels["tls"]
 }
 
+// tlsIdentity returns the identity the peer was verified as, if its connection
+// was secured with TLS.
+func (p *peer) tlsIdentity() string {
+	return p.labels[k8s.TLSIdentityLabel]
+}
+
 func routeLabels(event *pb.TapEvent) string {
 	out := ""
 	for key, val := range event.GetRouteMeta().GetLabels() {
@@ -13,7 +13,9 @@ import (
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/addr"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgUtil "github.com/linkerd/linkerd2/pkg/util"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 func busyTest(t *testing.T, wide bool) {
@@ -79,7 +81,7 @@ func busyTest(t *testing.T, wide bool) {
 	}
 
 	writer := bytes.NewBufferString("")
-	err = requestTapByResourceFromAPI(writer, mockAPIClient, req, wide)
+	err = requestTapByResourceFromAPI(writer, mockAPIClient, req, wide, false)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -133,7 +135,7 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err = requestTapByResourceFromAPI(writer, mockAPIClient, req, false)
+		err = requestTapByResourceFromAPI(writer, mockAPIClient, req, false, false)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -149,6 +151,30 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 	})
 
+	t.Run("Should render the ID of the tap session with --show-stream-id", func(t *testing.T) {
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{Resource: k8s.Pod + "/pod-666"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		mockAPIClient := &public.MockAPIClient{}
+		mockAPIClient.APITapByResourceClientToReturn = &public.MockAPITapByResourceClient{
+			TapEventsToReturn: []pb.TapEvent{},
+			HeaderToReturn:    metadata.Pairs(pkgUtil.RequestIDMetadataKey, "abc"),
+		}
+
+		writer := bytes.NewBufferString("")
+		err = requestTapByResourceFromAPI(writer, mockAPIClient, req, false, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedContent := "tap stream-id=abc\n"
+		if output := writer.String(); output != expectedContent {
+			t.Fatalf("Expected function to render:\n%s\bbut got:\n%s", expectedContent, output)
+		}
+	})
+
 	t.Run("Should return error if stream returned error", func(t *testing.T) {
 		t.SkipNow()
 		resourceType := k8s.Pod
@@ -172,7 +198,7 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err = requestTapByResourceFromAPI(writer, mockAPIClient, req, false)
+		err = requestTapByResourceFromAPI(writer, mockAPIClient, req, false, false)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing but output [%s]", writer.String())
 		}
@@ -332,6 +358,30 @@ func TestEventToString(t *testing.T) {
 		}
 	})
 
+	t.Run("Renders the TLS identity of the client of an inbound proxy", func(t *testing.T) {
+		event := toTapEvent(&pb.TapEvent_Http{
+			Event: &pb.TapEvent_Http_ResponseInit_{
+				ResponseInit: &pb.TapEvent_Http_ResponseInit{
+					SinceRequestInit: &duration.Duration{Nanos: 999000},
+					HttpStatus:       http.StatusOK,
+				},
+			},
+		})
+		event.ProxyDirection = pb.TapEvent_INBOUND
+		event.SourceMeta = &pb.TapEvent_EndpointMeta{
+			Labels: map[string]string{
+				"tls":                "true",
+				k8s.TLSIdentityLabel: "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local",
+			},
+		}
+
+		expectedOutput := "rsp id=7:8 proxy=in  src=1.2.3.4:5555 dst=2.3.4.5:6666 tls=true tls_identity=web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local :status=200 latency=999µs"
+		output := renderTapEvent(event, "")
+		if output != expectedOutput {
+			t.Fatalf("Expecting command output to be [%s], got [%s]", expectedOutput, output)
+		}
+	})
+
 	t.Run("Handles unknown event types", func(t *testing.T) {
 		event := toTapEvent(&pb.TapEvent_Http{})
 
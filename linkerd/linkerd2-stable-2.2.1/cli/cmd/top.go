@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"sort"
 	"strconv"
@@ -26,6 +27,8 @@ type topOptions struct {
 	toResource  string
 	toNamespace string
 	maxRps      float32
+	maxPods     uint32
+	pods        []string
 	scheme      string
 	method      string
 	authority   string
@@ -258,6 +261,8 @@ func newTopOptions() *topOptions {
 		toResource:  "",
 		toNamespace: "",
 		maxRps:      100.0,
+		maxPods:     0,
+		pods:        []string{},
 		scheme:      "",
 		method:      "",
 		authority:   "",
@@ -312,6 +317,8 @@ func newCmdTop() *cobra.Command {
 				ToResource:  options.toResource,
 				ToNamespace: options.toNamespace,
 				MaxRps:      options.maxRps,
+				MaxPods:     options.maxPods,
+				Pods:        options.pods,
 				Scheme:      options.scheme,
 				Method:      options.method,
 				Authority:   options.authority,
@@ -349,6 +356,10 @@ func newCmdTop() *cobra.Command {
 		"Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().Float32Var(&options.maxRps, "max-rps", options.maxRps,
 		"Maximum requests per second to tap.")
+	cmd.PersistentFlags().Uint32Var(&options.maxPods, "max-pods", options.maxPods,
+		"Maximum number of pods of the specified resource to tap; 0 taps all of them")
+	cmd.PersistentFlags().StringSliceVar(&options.pods, "pods", options.pods,
+		"Only tap these pods of the specified resource (comma-separated)")
 	cmd.PersistentFlags().StringVar(&options.scheme, "scheme", options.scheme,
 		"Display requests with this scheme")
 	cmd.PersistentFlags().StringVar(&options.method, "method", options.method,
@@ -552,7 +563,11 @@ func (t *topTable) insert(req topRequest) {
 }
 
 func stripPort(address string) string {
-	return strings.Split(address, ":")[0]
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
 }
 
 func (t *topTable) renderHeaders() {
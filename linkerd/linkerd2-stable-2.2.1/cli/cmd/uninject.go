@@ -31,6 +31,8 @@ func runUninjectSilentCmd(inputs []io.Reader, errWriter, outWriter io.Writer, op
 }
 
 func newCmdUninject() *cobra.Command {
+	options := &injectOptions{}
+
 	cmd := &cobra.Command{
 		Use:   "uninject [flags] CONFIG-FILE",
 		Short: "Remove the Linkerd proxy from a Kubernetes config",
@@ -52,17 +54,23 @@ sub-folders, or coming from stdin.`,
 				return fmt.Errorf("please specify a kubernetes resource file")
 			}
 
+			if err := options.summary.validate(); err != nil {
+				return err
+			}
+
 			in, err := read(args[0])
 			if err != nil {
 				return err
 			}
 
-			exitCode := runUninjectCmd(in, os.Stderr, os.Stdout, nil)
+			exitCode := runUninjectCmd(in, os.Stderr, os.Stdout, options)
 			os.Exit(exitCode)
 			return nil
 		},
 	}
 
+	addSummaryFlags(cmd, &options.summary, "uninjected")
+
 	return cmd
 }
 
@@ -120,6 +128,21 @@ func (resourceTransformerUninject) generateReport(uninjectReports []injectReport
 func (resourceTransformerUninjectSilent) generateReport(uninjectReports []injectReport, output io.Writer) {
 }
 
+func (resourceTransformerUninject) outcome(r injectReport) resourceOutcome {
+	switch {
+	case r.unsupportedResource:
+		return outcomeSkippedUnsupportedKind
+	case r.sidecar:
+		return outcomeUninjected
+	default:
+		return outcomeSkippedNotInjected
+	}
+}
+
+func (resourceTransformerUninjectSilent) outcome(r injectReport) resourceOutcome {
+	return resourceTransformerUninject{}.outcome(r)
+}
+
 // Given a PodSpec, update the PodSpec in place with the sidecar
 // and init-container uninjected
 func uninjectPodSpec(t *v1.PodSpec, report *injectReport) {
@@ -84,9 +84,9 @@ func configureAndRunVersion(
 // This client does not do any validation
 func newVersionClient() (pb.ApiClient, error) {
 	if apiAddr != "" {
-		return public.NewInternalClient(controlPlaneNamespace, apiAddr)
+		return public.NewInternalClientWithCA(controlPlaneNamespace, apiAddr, certificateAuthority)
 	}
-	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext)
+	kubeAPI, err := k8s.NewAPIWithAuth(kubeconfigPath, kubeContext, clientAuth())
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +65,8 @@ data:
   incoming_proxy_port: "{{.InboundPort}}"
   outgoing_proxy_port: "{{.OutboundPort}}"
   proxy_uid: "{{.ProxyUID}}"
-  inbound_ports_to_ignore: "{{.IgnoreInboundPorts}}"
-  outbound_ports_to_ignore: "{{.IgnoreOutboundPorts}}"
+  inbound_ports_to_ignore: {{printf "%q" .IgnoreInboundPorts}}
+  outbound_ports_to_ignore: {{printf "%q" .IgnoreOutboundPorts}}
   simulate: "false"
   log_level: "{{.LogLevel}}"
   dest_cni_net_dir: "{{.DestCNINetDir}}"
@@ -93,6 +93,18 @@ data:
         "ports-to-redirect": [__PORTS_TO_REDIRECT__],
         "inbound-ports-to-ignore": [__INBOUND_PORTS_TO_IGNORE__],
         "outbound-ports-to-ignore": [__OUTBOUND_PORTS_TO_IGNORE__],
+        {{- if .IgnoreInboundSubnets}}
+        "inbound-subnets-to-ignore": {{.IgnoreInboundSubnets}},
+        {{- end}}
+        {{- if .IgnoreOutboundSubnets}}
+        "outbound-subnets-to-ignore": {{.IgnoreOutboundSubnets}},
+        {{- end}}
+        {{- if .IgnoreMarks}}
+        "marks-to-ignore": {{.IgnoreMarks}},
+        {{- end}}
+        {{- if .IptablesMode}}
+        "iptables-mode": "{{.IptablesMode}}",
+        {{- end}}
         "simulate": __SIMULATE__
       }
     }
@@ -125,7 +137,25 @@ spec:
     spec:
       nodeSelector:
         beta.kubernetes.io/os: linux
-      hostNetwork: true
+      hostNetwork: {{.HostNetwork}}
+      {{- with .PriorityClassName }}
+      priorityClassName: {{.}}
+      {{- end }}
+      {{- if .Tolerations }}
+      tolerations:
+      {{- range .Tolerations }}
+      - operator: {{.Operator}}
+        {{- with .Key }}
+        key: {{.}}
+        {{- end }}
+        {{- with .Value }}
+        value: {{.}}
+        {{- end }}
+        {{- with .Effect }}
+        effect: {{.}}
+        {{- end }}
+      {{- end }}
+      {{- end }}
       serviceAccountName: linkerd-cni
       terminationGracePeriodSeconds: 5
       containers:
@@ -179,6 +209,10 @@ spec:
                 key: log_level
           - name: SLEEP
             value: "true"
+          {{- if .Privileged }}
+          securityContext:
+            privileged: true
+          {{- end }}
           volumeMounts:
           {{- if ne .DestCNIBinDir .DestCNINetDir }}
           - mountPath: /host{{.DestCNIBinDir}}
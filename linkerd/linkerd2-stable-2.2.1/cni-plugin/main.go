@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/containernetworking/cni/pkg/skel"
@@ -39,13 +40,43 @@ import (
 
 // ProxyInit is the configuration for the proxy-init binary
 type ProxyInit struct {
-	IncomingProxyPort     int   `json:"incoming-proxy-port"`
-	OutgoingProxyPort     int   `json:"outgoing-proxy-port"`
-	ProxyUID              int   `json:"proxy-uid"`
-	PortsToRedirect       []int `json:"ports-to-redirect"`
-	InboundPortsToIgnore  []int `json:"inbound-ports-to-ignore"`
-	OutboundPortsToIgnore []int `json:"outbound-ports-to-ignore"`
-	Simulate              bool  `json:"simulate"`
+	IncomingProxyPort       int      `json:"incoming-proxy-port"`
+	OutgoingProxyPort       int      `json:"outgoing-proxy-port"`
+	ProxyUID                int      `json:"proxy-uid"`
+	PortsToRedirect         []int    `json:"ports-to-redirect"`
+	InboundPortsToIgnore    Ports    `json:"inbound-ports-to-ignore"`
+	OutboundPortsToIgnore   Ports    `json:"outbound-ports-to-ignore"`
+	InboundSubnetsToIgnore  []string `json:"inbound-subnets-to-ignore"`
+	OutboundSubnetsToIgnore []string `json:"outbound-subnets-to-ignore"`
+	MarksToIgnore           []string `json:"marks-to-ignore"`
+	IptablesMode            string   `json:"iptables-mode"`
+	Simulate                bool     `json:"simulate"`
+}
+
+// Ports are ports, or ranges of ports such as "4000-4100". The ranges are
+// strings, while the ports may be numbers, as the configurations written by
+// older versions list them.
+type Ports []string
+
+// UnmarshalJSON accepts both numbers and strings.
+func (p *Ports) UnmarshalJSON(data []byte) error {
+	var values []interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	ports := make(Ports, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case float64:
+			ports[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			ports[i] = v
+		default:
+			return fmt.Errorf("invalid port %v", value)
+		}
+	}
+	*p = ports
+	return nil
 }
 
 // Kubernetes a K8s specific struct to hold config
@@ -199,14 +230,18 @@ func cmdAdd(args *skel.CmdArgs) error {
 		if containsLinkerdProxy && !containsInitContainer {
 			logEntry.Infof("linkerd-cni: setting up iptables firewall")
 			options := cmd.RootOptions{
-				IncomingProxyPort:     conf.ProxyInit.IncomingProxyPort,
-				OutgoingProxyPort:     conf.ProxyInit.OutgoingProxyPort,
-				ProxyUserID:           conf.ProxyInit.ProxyUID,
-				PortsToRedirect:       conf.ProxyInit.PortsToRedirect,
-				InboundPortsToIgnore:  conf.ProxyInit.InboundPortsToIgnore,
-				OutboundPortsToIgnore: conf.ProxyInit.OutboundPortsToIgnore,
-				SimulateOnly:          conf.ProxyInit.Simulate,
-				NetNs:                 args.Netns,
+				IncomingProxyPort:       conf.ProxyInit.IncomingProxyPort,
+				OutgoingProxyPort:       conf.ProxyInit.OutgoingProxyPort,
+				ProxyUserID:             conf.ProxyInit.ProxyUID,
+				PortsToRedirect:         conf.ProxyInit.PortsToRedirect,
+				InboundPortsToIgnore:    conf.ProxyInit.InboundPortsToIgnore,
+				OutboundPortsToIgnore:   conf.ProxyInit.OutboundPortsToIgnore,
+				InboundSubnetsToIgnore:  conf.ProxyInit.InboundSubnetsToIgnore,
+				OutboundSubnetsToIgnore: conf.ProxyInit.OutboundSubnetsToIgnore,
+				MarksToIgnore:           conf.ProxyInit.MarksToIgnore,
+				IptablesMode:            conf.ProxyInit.IptablesMode,
+				SimulateOnly:            conf.ProxyInit.Simulate,
+				NetNs:                   args.Netns,
 			}
 			firewallConfiguration, err := cmd.BuildFirewallConfiguration(&options)
 			if err != nil {
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
@@ -27,6 +28,46 @@ type ownerKindAndNameFn func(*coreV1.Pod) (string, string)
 type updateAddress struct {
 	address *net.TcpAddress
 	pod     *coreV1.Pod
+	// weight overrides the default weight of the address when it's non-zero,
+	// as it is for the backends of traffic splits
+	weight uint32
+	// leafService is the name of the backend service the address belongs to
+	// when it's part of a traffic split
+	leafService string
+	// metadata memoizes the metadata of the pod, so that it's computed once
+	// for all the listeners the address is published to; nil if it isn't
+	// memoized
+	metadata *podMetadata
+}
+
+// podMetadata is the metadata of the pod of an address that doesn't depend on
+// the listener, which all the listeners of a service port share.
+type podMetadata struct {
+	once      sync.Once
+	ownerKind string
+	ownerName string
+	labels    map[string]string
+}
+
+// podMetadata returns the owner and the metric labels of the pod of the
+// address, computed with the given function on the first call if they're
+// memoized. The labels can be modified by the caller.
+func (ua *updateAddress) podMetadata(ownerKindAndName ownerKindAndNameFn) (string, string, map[string]string) {
+	if ua.metadata == nil {
+		ownerKind, ownerName := ownerKindAndName(ua.pod)
+		return ownerKind, ownerName, pkgK8s.GetPodLabels(ownerKind, ownerName, ua.pod)
+	}
+
+	m := ua.metadata
+	m.once.Do(func() {
+		m.ownerKind, m.ownerName = ownerKindAndName(ua.pod)
+		m.labels = pkgK8s.GetPodLabels(m.ownerKind, m.ownerName, ua.pod)
+	})
+	labels := make(map[string]string, len(m.labels))
+	for k, v := range m.labels {
+		labels[k] = v
+	}
+	return m.ownerKind, m.ownerName, labels
 }
 
 func (ua updateAddress) String() string {
@@ -39,8 +80,10 @@ func (ua updateAddress) String() string {
 
 func (ua updateAddress) clone() *updateAddress {
 	return &updateAddress{
-		pod:     ua.pod.DeepCopy(),
-		address: proto.Clone(ua.address).(*net.TcpAddress),
+		pod:         ua.pod.DeepCopy(),
+		address:     proto.Clone(ua.address).(*net.TcpAddress),
+		weight:      ua.weight,
+		leafService: ua.leafService,
 	}
 }
 
@@ -84,8 +127,11 @@ type endpointListener struct {
 	labels           map[string]string
 	enableH2Upgrade  bool
 	enableTLS        bool
-	stopCh           chan struct{}
-	log              *log.Entry
+	// topology is nil if the topology of endpoints isn't known
+	topology   *topologyWeigher
+	clientZone string
+	stopCh     chan struct{}
+	log        *log.Entry
 }
 
 func newEndpointListener(
@@ -184,11 +230,45 @@ func (l *endpointListener) toWeightedAddrSet(addresses []*updateAddress) *pb.Wei
 }
 
 func (l *endpointListener) toWeightedAddr(address *updateAddress) *pb.WeightedAddr {
-	labels, hint, tlsIdentity := l.getAddrMetadata(address.pod)
+	if address.pod == nil {
+		// addresses resolved from the name of an ExternalName service aren't
+		// backed by a pod, so there's no metadata to add to them
+		weighted := &pb.WeightedAddr{
+			Addr:   address.address,
+			Weight: addr.DefaultWeight,
+		}
+		if address.weight != 0 {
+			weighted.Weight = address.weight
+		}
+		if address.leafService != "" {
+			weighted.MetricLabels = map[string]string{"leaf_service": address.leafService}
+		}
+		return weighted
+	}
+
+	labels, hint, tlsIdentity := l.getAddrMetadata(address)
+
+	var weight uint32 = addr.DefaultWeight
+	if l.topology != nil {
+		zone, region := l.topology.podTopology(address.pod)
+		if zone != "" {
+			labels["zone"] = zone
+		}
+		if region != "" {
+			labels["region"] = region
+		}
+		weight = l.topology.weight(l.clientZone, zone)
+	}
+	if address.weight != 0 {
+		weight *= address.weight
+	}
+	if address.leafService != "" {
+		labels["leaf_service"] = address.leafService
+	}
 
 	return &pb.WeightedAddr{
 		Addr:         address.address,
-		Weight:       addr.DefaultWeight,
+		Weight:       weight,
 		MetricLabels: labels,
 		TlsIdentity:  tlsIdentity,
 		ProtocolHint: hint,
@@ -203,10 +283,10 @@ func (l *endpointListener) toAddrSet(addresses []*updateAddress) *pb.AddrSet {
 	return &pb.AddrSet{Addrs: addrs}
 }
 
-func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string, *pb.ProtocolHint, *pb.TlsIdentity) {
+func (l *endpointListener) getAddrMetadata(address *updateAddress) (map[string]string, *pb.ProtocolHint, *pb.TlsIdentity) {
+	pod := address.pod
 	controllerNs := pod.Labels[pkgK8s.ControllerNSLabel]
-	ownerKind, ownerName := l.ownerKindAndName(pod)
-	labels := pkgK8s.GetPodLabels(ownerKind, ownerName, pod)
+	ownerKind, ownerName, labels := address.podMetadata(l.ownerKindAndName)
 
 	var hint *pb.ProtocolHint
 
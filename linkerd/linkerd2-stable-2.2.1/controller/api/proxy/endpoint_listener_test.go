@@ -299,6 +299,37 @@ func TestEndpointListener(t *testing.T) {
 			t.Fatalf("Expected no TlsIdentity to be sent, but got [%v]", addrs[0].TlsIdentity)
 		}
 	})
+
+	t.Run("Computes the pod metadata once for all the listeners", func(t *testing.T) {
+		lookups := 0
+		ownerKindAndName := func(pod *v1.Pod) (string, string) {
+			lookups++
+			return "deployment", "pod-deployment"
+		}
+
+		add := []*updateAddress{
+			&updateAddress{address: addedAddress1, pod: pod1, metadata: &podMetadata{}},
+		}
+		expectedLabels := map[string]string{
+			"pod":        "pod1",
+			"deployment": "pod-deployment",
+		}
+
+		for i := 0; i < 3; i++ {
+			mockGetServer := &mockDestinationGetServer{updatesReceived: []*pb.Update{}}
+			listener := newEndpointListener(mockGetServer, ownerKindAndName, false, false)
+			listener.Update(add, nil)
+
+			labels := mockGetServer.updatesReceived[0].GetAdd().Addrs[0].MetricLabels
+			if !reflect.DeepEqual(labels, expectedLabels) {
+				t.Fatalf("Expected metric labels [%v] for listener %d, got [%v]", expectedLabels, i, labels)
+			}
+		}
+
+		if lookups != 1 {
+			t.Fatalf("Expected the owner of the pod to be looked up once, got %d lookups", lookups)
+		}
+	})
 }
 
 func TestUpdateAddress(t *testing.T) {
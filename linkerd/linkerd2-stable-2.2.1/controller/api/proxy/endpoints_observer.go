@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"sync"
+)
+
+// endpointsObserverBufferSize is the number of updates an observer can fall
+// behind by before it's closed.
+const endpointsObserverBufferSize = 1000
+
+// endpointsUpdate is a change to the addresses of a watched service port.
+type endpointsUpdate struct {
+	service serviceID
+	port    uint32
+	add     []*updateAddress
+	remove  []*updateAddress
+}
+
+// endpointsObserver receives the changes to the addresses of all the service
+// ports watched by an endpointsWatcher, e.g. to stream them to the CLI. Unlike
+// endpointUpdateListeners, observers don't cause service ports to be watched.
+type endpointsObserver struct {
+	updates chan *endpointsUpdate
+	// overflow is closed if the observer falls too far behind, in which case
+	// updates have been dropped and the observer must be stopped.
+	overflow     chan struct{}
+	overflowOnce sync.Once
+}
+
+func newEndpointsObserver() *endpointsObserver {
+	return &endpointsObserver{
+		updates:  make(chan *endpointsUpdate, endpointsObserverBufferSize),
+		overflow: make(chan struct{}),
+	}
+}
+
+func (o *endpointsObserver) send(update *endpointsUpdate) {
+	select {
+	case o.updates <- update:
+	default:
+		o.overflowOnce.Do(func() { close(o.overflow) })
+	}
+}
+
+// endpointsObservers is the set of observers of an endpointsWatcher. It has
+// its own mutex, which is always acquired last, so that updates can be sent
+// while holding the mutexes of the endpointsWatcher or of a servicePort.
+type endpointsObservers struct {
+	mutex     sync.RWMutex
+	observers map[*endpointsObserver]struct{}
+}
+
+func newEndpointsObservers() *endpointsObservers {
+	return &endpointsObservers{
+		observers: make(map[*endpointsObserver]struct{}),
+	}
+}
+
+func (o *endpointsObservers) add(observer *endpointsObserver) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.observers[observer] = struct{}{}
+}
+
+func (o *endpointsObservers) remove(observer *endpointsObserver) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	delete(o.observers, observer)
+}
+
+// notify sends an update to all the observers. It's a no-op if o is nil or if
+// the update is empty.
+func (o *endpointsObservers) notify(service serviceID, port uint32, add, remove []*updateAddress) {
+	if o == nil || (len(add) == 0 && len(remove) == 0) {
+		return
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	update := &endpointsUpdate{
+		service: service,
+		port:    port,
+		add:     add,
+		remove:  remove,
+	}
+	for observer := range o.observers {
+		observer.send(update)
+	}
+}
@@ -1,14 +1,20 @@
 package proxy
 
 import (
+	"container/list"
 	"fmt"
+	gonet "net"
 	"strings"
 	"sync"
+	"time"
 
 	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,6 +42,24 @@ type endpointsWatcher struct {
 	endpointLister corelisters.EndpointsLister
 	podLister      corelisters.PodLister
 	servicePorts   servicePorts
+	// lookupIP and refreshInterval are used to resolve the names of
+	// ExternalName services
+	lookupIP        lookupIPFn
+	refreshInterval time.Duration
+	// maxServicePorts limits the number of watched service ports, including
+	// idle ones; 0 means no limit. maxIdleServicePorts is the number of service
+	// ports that stay watched once they have no listeners left, so that they
+	// can be reused by later subscriptions. The least recently used idle
+	// service ports are evicted first.
+	maxServicePorts     int
+	maxIdleServicePorts int
+	numServicePorts     int
+	// debounce is the window during which the updates of the endpoints of a
+	// service port are batched, once one is received; 0 publishes each of
+	// them right away
+	debounce  time.Duration
+	idle      *list.List // of *servicePort, most recently used first
+	observers *endpointsObservers
 	// This mutex protects the servicePorts data structure (nested map) itself
 	// and does not protect the servicePort objects themselves.  They are locked
 	// separately.
@@ -45,11 +69,15 @@ type endpointsWatcher struct {
 
 func newEndpointsWatcher(k8sAPI *k8s.API) *endpointsWatcher {
 	watcher := &endpointsWatcher{
-		serviceLister:  k8sAPI.Svc().Lister(),
-		endpointLister: k8sAPI.Endpoint().Lister(),
-		podLister:      k8sAPI.Pod().Lister(),
-		servicePorts:   make(servicePorts),
-		mutex:          sync.RWMutex{},
+		serviceLister:   k8sAPI.Svc().Lister(),
+		endpointLister:  k8sAPI.Endpoint().Lister(),
+		podLister:       k8sAPI.Pod().Lister(),
+		servicePorts:    make(servicePorts),
+		idle:            list.New(),
+		observers:       newEndpointsObservers(),
+		lookupIP:        gonet.LookupIP,
+		refreshInterval: externalNameRefreshInterval,
+		mutex:           sync.RWMutex{},
 		log: log.WithFields(log.Fields{
 			"component": "endpoints-watcher",
 		}),
@@ -97,16 +125,30 @@ func (e *endpointsWatcher) subscribe(service *serviceID, port uint32, listener e
 		return err
 	}
 
+	// Resolve the name of ExternalName services before locking, so that slow
+	// DNS lookups don't hold up the other subscriptions.
+	var resolved []*updateAddress
+	if isExternalName(svc) {
+		resolved, err = lookupExternalName(e.lookupIP, svc.Spec.ExternalName, port)
+		if err != nil {
+			e.log.Errorf("Error resolving %s: %s", svc.Spec.ExternalName, err)
+		}
+	}
+
 	e.mutex.Lock() // Acquire write-lock on servicePorts data structure.
 	defer e.mutex.Unlock()
 
-	svcPorts, ok := e.servicePorts[*service]
-	if !ok {
-		svcPorts = make(map[uint32]*servicePort)
-		e.servicePorts[*service] = svcPorts
+	svcPort, ok := e.servicePorts[*service][port]
+	if ok && svcPort.idleElement != nil {
+		e.reuseIdle(svcPort)
 	}
-	svcPort, ok := svcPorts[port]
 	if !ok {
+		if e.maxServicePorts > 0 && e.numServicePorts >= e.maxServicePorts && !e.evictIdle() {
+			err := status.Errorf(codes.ResourceExhausted, "Cannot watch %s:%d: the limit of %d watched service ports was reached", service, port, e.maxServicePorts)
+			e.log.Error(err)
+			return err
+		}
+
 		endpoints, err := e.getEndpoints(service)
 		if apierrors.IsNotFound(err) {
 			endpoints = &v1.Endpoints{}
@@ -115,19 +157,35 @@ func (e *endpointsWatcher) subscribe(service *serviceID, port uint32, listener e
 			return err
 		}
 		svcPort = newServicePort(svc, endpoints, port, e.podLister)
+		// The service may not exist yet, in which case it's only known by the
+		// ID it's subscribed to by.
+		svcPort.service = *service
+		svcPort.observers = e.observers
+		svcPort.lookupIP = e.lookupIP
+		svcPort.refreshInterval = e.refreshInterval
+		svcPort.debounce = e.debounce
+		if isExternalName(svc) {
+			// ExternalName services have no endpoints; their addresses are
+			// the ones their external name resolves to.
+			svcPort.addresses = resolved
+			svcPort.resolveExternalName(svc.Spec.ExternalName, false)
+		}
+
+		svcPorts, ok := e.servicePorts[*service]
+		if !ok {
+			svcPorts = make(map[uint32]*servicePort)
+			e.servicePorts[*service] = svcPorts
+		}
 		svcPorts[port] = svcPort
+		e.numServicePorts++
+		servicePortsGauge.Inc()
+		endpointsGauge.Add(float64(len(svcPort.addresses)))
+		e.observers.notify(*service, port, svcPort.addresses, nil)
 	}
 
-	exists := true
-	if svc == nil || svc.Spec.Type == v1.ServiceTypeExternalName {
-		// XXX: The proxy will use DNS to discover the service if it is told
-		// the service doesn't exist. An external service is represented in DNS
-		// as a CNAME, which the proxy will correctly resolve. Thus, there's no
-		// benefit (yet) to distinguishing between "the service exists but it
-		// is an ExternalName service so use DNS anyway" and "the service does
-		// not exist."
-		exists = false
-	}
+	// The proxy will use DNS to discover the service if it is told the service
+	// doesn't exist.
+	exists := svc != nil
 
 	svcPort.subscribe(exists, listener)
 	return nil
@@ -152,14 +210,94 @@ func (e *endpointsWatcher) unsubscribe(service *serviceID, port uint32, listener
 		return fmt.Errorf("Cannot unsubscribe from %s: not subscribed", service)
 	}
 	if numListeners == 0 {
-		delete(svc, port)
-		if len(svc) == 0 {
-			delete(e.servicePorts, *service)
+		if e.maxIdleServicePorts > 0 {
+			e.markIdle(svcPort)
+		} else {
+			e.remove(svcPort)
 		}
 	}
 	return nil
 }
 
+// markIdle keeps watching a service port that has no listeners left, evicting
+// the least recently used idle service port if there are too many. Must be
+// called with the write-lock held.
+func (e *endpointsWatcher) markIdle(sp *servicePort) {
+	sp.idleElement = e.idle.PushFront(sp)
+	idleServicePortsGauge.Inc()
+	if e.idle.Len() > e.maxIdleServicePorts {
+		e.evictIdle()
+	}
+}
+
+// reuseIdle marks an idle service port as active again. Must be called with
+// the write-lock held.
+func (e *endpointsWatcher) reuseIdle(sp *servicePort) {
+	e.idle.Remove(sp.idleElement)
+	sp.idleElement = nil
+	idleServicePortsGauge.Dec()
+}
+
+// evictIdle stops watching the least recently used idle service port. It
+// returns false if there are no idle service ports. Must be called with the
+// write-lock held.
+func (e *endpointsWatcher) evictIdle() bool {
+	oldest := e.idle.Back()
+	if oldest == nil {
+		return false
+	}
+	sp := oldest.Value.(*servicePort)
+	e.reuseIdle(sp)
+	e.remove(sp)
+	servicePortEvictions.Inc()
+	e.log.Debugf("Evicted idle service port %s:%d", sp.service, sp.port)
+	return true
+}
+
+// remove stops watching a service port. Must be called with the write-lock
+// held.
+func (e *endpointsWatcher) remove(sp *servicePort) {
+	svc := e.servicePorts[sp.service]
+	delete(svc, sp.port)
+	if len(svc) == 0 {
+		delete(e.servicePorts, sp.service)
+	}
+	e.numServicePorts--
+	servicePortsGauge.Dec()
+	addresses := sp.close()
+	endpointsGauge.Sub(float64(len(addresses)))
+	e.observers.notify(sp.service, sp.port, nil, addresses)
+}
+
+// observe returns an observer of the changes to the addresses of all the
+// watched service ports. The observer first receives the current addresses of
+// the service ports, as additions.
+func (e *endpointsWatcher) observe() *endpointsObserver {
+	observer := newEndpointsObserver()
+	// The observer is added before reading the current addresses, so that no
+	// change is missed. A change that's made in between is received twice,
+	// which is harmless.
+	e.observers.add(observer)
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for id, portMap := range e.servicePorts {
+		for port, sp := range portMap {
+			_, _, addresses := sp.getState()
+			if len(addresses) > 0 {
+				observer.send(&endpointsUpdate{service: id, port: port, add: addresses})
+			}
+		}
+	}
+
+	return observer
+}
+
+func (e *endpointsWatcher) stopObserving(observer *endpointsObserver) {
+	e.observers.remove(observer)
+}
+
 func (e *endpointsWatcher) getService(service *serviceID) (*v1.Service, error) {
 	return e.serviceLister.Services(service.namespace).Get(service.name)
 }
@@ -295,6 +433,21 @@ type servicePort struct {
 	targetPort intstr.IntOrString
 	addresses  []*updateAddress
 	podLister  corelisters.PodLister
+	// observers are notified of all the changes to the addresses
+	observers *endpointsObservers
+	// idleElement is the service port's element in the endpointsWatcher's list
+	// of idle service ports, if it has no listeners
+	idleElement *list.Element
+	// externalName is the name being resolved for an ExternalName service, and
+	// stopResolvingCh stops the goroutine resolving it
+	externalName    string
+	lookupIP        lookupIPFn
+	refreshInterval time.Duration
+	stopResolvingCh chan struct{}
+	// debounce is the window during which the updates of the endpoints are
+	// batched, and pendingUpdate fires at the end of the current one
+	debounce      time.Duration
+	pendingUpdate *time.Timer
 	// This mutex protects against concurrent modification of the listeners slice
 	// as well as prevents updates for occurring while the listeners slice is being
 	// modified.
@@ -335,20 +488,65 @@ func (sp *servicePort) updateEndpoints(newEndpoints *v1.Endpoints) {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
-	sp.updateAddresses(newEndpoints, sp.targetPort)
 	sp.endpoints = newEndpoints
+	if sp.externalName != "" {
+		return
+	}
+	if sp.debounce <= 0 {
+		sp.updateAddresses(newEndpoints, sp.targetPort)
+		return
+	}
+
+	// During rollouts, the endpoints change with every pod that becomes
+	// ready or terminates. The changes made within the window are published
+	// at once, from the latest endpoints.
+	if sp.pendingUpdate != nil {
+		batchedEndpointUpdates.Inc()
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(sp.debounce, func() {
+		sp.mutex.Lock()
+		defer sp.mutex.Unlock()
+
+		if sp.pendingUpdate != timer {
+			// canceled after it fired
+			return
+		}
+		sp.pendingUpdate = nil
+		if sp.externalName == "" {
+			sp.updateAddresses(sp.endpoints, sp.targetPort)
+		}
+	})
+	sp.pendingUpdate = timer
+}
+
+// cancelPendingUpdate cancels the batched update of the endpoints, if any,
+// e.g. when the latest endpoints are published right away. Must be called
+// with the servicePort's mutex held.
+func (sp *servicePort) cancelPendingUpdate() {
+	if sp.pendingUpdate != nil {
+		sp.pendingUpdate.Stop()
+		sp.pendingUpdate = nil
+	}
 }
 
 func (sp *servicePort) deleteEndpoints() {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
+	sp.cancelPendingUpdate()
+	sp.endpoints = &v1.Endpoints{}
+	if sp.externalName != "" {
+		return
+	}
+
 	sp.log.Debugf("Deleting %s:%d", sp.service, sp.port)
 
+	endpointUpdateFanout.Observe(float64(len(sp.listeners)))
 	for _, listener := range sp.listeners {
 		listener.NoEndpoints(false)
 	}
-	sp.endpoints = &v1.Endpoints{}
 	sp.addresses = []*updateAddress{}
 }
 
@@ -356,15 +554,34 @@ func (sp *servicePort) updateService(newService *v1.Service) {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
+	if isExternalName(newService) {
+		if newService.Spec.ExternalName != sp.externalName {
+			sp.cancelPendingUpdate()
+			sp.resolveExternalName(newService.Spec.ExternalName, true)
+		}
+		return
+	}
+
 	newTargetPort := getTargetPort(newService, sp.port)
-	if newTargetPort != sp.targetPort {
+	if sp.externalName != "" {
+		// the service is no longer an ExternalName service
+		sp.stopResolving()
+		sp.updateAddresses(sp.endpoints, newTargetPort)
+		sp.targetPort = newTargetPort
+	} else if newTargetPort != sp.targetPort {
+		sp.cancelPendingUpdate()
 		sp.updateAddresses(sp.endpoints, newTargetPort)
 		sp.targetPort = newTargetPort
 	}
 }
 
 func (sp *servicePort) updateAddresses(endpoints *v1.Endpoints, port intstr.IntOrString) {
-	newAddresses := sp.endpointsToAddresses(endpoints, port)
+	sp.publishAddresses(sp.endpointsToAddresses(endpoints, port))
+}
+
+// publishAddresses replaces the address set and publishes the changes to all
+// listeners.
+func (sp *servicePort) publishAddresses(newAddresses []*updateAddress) {
 	if log.GetLevel() >= log.DebugLevel {
 		var s []string
 		for _, v := range newAddresses {
@@ -373,16 +590,29 @@ func (sp *servicePort) updateAddresses(endpoints *v1.Endpoints, port intstr.IntO
 		sp.log.Debugf("Updating %s:%d to [%v]", sp.service, sp.port, strings.Join(s, ", "))
 	}
 
+	// The changes are computed once and the same addresses are published to
+	// all the listeners, which share the metadata of their pods.
 	if len(newAddresses) == 0 {
+		endpointUpdateFanout.Observe(float64(len(sp.listeners)))
 		for _, listener := range sp.listeners {
 			listener.NoEndpoints(true)
 		}
+		sp.observers.notify(sp.service, sp.port, nil, sp.addresses)
 	} else {
 		add, remove := diffUpdateAddresses(sp.addresses, newAddresses)
-		for _, listener := range sp.listeners {
-			listener.Update(add, remove)
+		if len(add) == 0 && len(remove) == 0 {
+			// e.g. on a resync of the informer, or a change to the
+			// endpoints of another port: there's nothing to publish
+			unchangedEndpointUpdates.Inc()
+		} else {
+			endpointUpdateFanout.Observe(float64(len(sp.listeners)))
+			for _, listener := range sp.listeners {
+				listener.Update(add, remove)
+			}
+			sp.observers.notify(sp.service, sp.port, add, remove)
 		}
 	}
+	endpointsGauge.Add(float64(len(newAddresses) - len(sp.addresses)))
 	sp.addresses = newAddresses
 }
 
@@ -393,6 +623,7 @@ func (sp *servicePort) subscribe(exists bool, listener endpointUpdateListener) {
 	defer sp.mutex.Unlock()
 
 	sp.listeners = append(sp.listeners, listener)
+	endpointListenersGauge.Inc()
 	if !exists {
 		listener.NoEndpoints(false)
 	} else if len(sp.addresses) == 0 {
@@ -416,18 +647,33 @@ func (sp *servicePort) unsubscribe(listener endpointUpdateListener) (bool, int)
 			sp.listeners[i] = sp.listeners[len(sp.listeners)-1]
 			sp.listeners[len(sp.listeners)-1] = nil
 			sp.listeners = sp.listeners[:len(sp.listeners)-1]
+			endpointListenersGauge.Dec()
 			return true, len(sp.listeners)
 		}
 	}
 	return false, len(sp.listeners)
 }
 
+// close stops resolving the service's name if it's an ExternalName service,
+// once the service port is no longer watched. It returns the addresses the
+// service port had.
+func (sp *servicePort) close() []*updateAddress {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sp.cancelPendingUpdate()
+	sp.stopResolving()
+	return sp.addresses
+}
+
 func (sp *servicePort) unsubscribeAll() {
 	sp.log.Debugf("Unsubscribing %s:%d", sp.service, sp.port)
 
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
+	sp.cancelPendingUpdate()
+	sp.stopResolving()
 	for _, listener := range sp.listeners {
 		listener.Stop()
 	}
@@ -475,6 +721,19 @@ func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort
 
 		for _, address := range subset.Addresses {
 			target := address.TargetRef
+			if target == nil && endpoints.Labels[pkgK8s.MirroredServiceLabel] == "true" {
+				// The endpoints of mirrored services are the addresses of the
+				// gateway of the remote cluster they were mirrored from.
+				ip, err := addr.ParseProxyIP(address.IP)
+				if err != nil {
+					sp.log.Errorf("[%s] not a valid IP address", address.IP)
+					continue
+				}
+				addrs = append(addrs, &updateAddress{
+					address: &net.TcpAddress{Ip: ip, Port: portNum},
+				})
+				continue
+			}
 			if target == nil {
 				sp.log.Errorf("Target not found for endpoint %v", address)
 				continue
@@ -482,9 +741,9 @@ func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort
 
 			idStr := fmt.Sprintf("%s %s.%s", address.IP, target.Name, target.Namespace)
 
-			ip, err := addr.ParseProxyIPV4(address.IP)
+			ip, err := addr.ParseProxyIP(address.IP)
 			if err != nil {
-				sp.log.Errorf("[%s] not a valid IPV4 address", idStr)
+				sp.log.Errorf("[%s] not a valid IP address", idStr)
 				continue
 			}
 
@@ -495,8 +754,9 @@ func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort
 			}
 
 			addrs = append(addrs, &updateAddress{
-				address: &net.TcpAddress{Ip: ip, Port: portNum},
-				pod:     pod,
+				address:  &net.TcpAddress{Ip: ip, Port: portNum},
+				pod:      pod,
+				metadata: &podMetadata{},
 			})
 		}
 	}
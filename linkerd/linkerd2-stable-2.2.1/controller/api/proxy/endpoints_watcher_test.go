@@ -1,12 +1,20 @@
 package proxy
 
 import (
+	"fmt"
+	"net"
 	"reflect"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -397,6 +405,70 @@ status:
 				},
 			},
 		},
+		{
+			serviceType: "mirrored services",
+			k8sConfigs: []string{`
+apiVersion: v1
+kind: Service
+metadata:
+  name: books-east
+  namespace: ns
+  labels:
+    mirror.linkerd.io/mirrored-service: "true"
+spec:
+  type: ClusterIP
+  ports:
+  - name: http
+    port: 7000
+    targetPort: 4143`,
+				`
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: books-east
+  namespace: ns
+  labels:
+    mirror.linkerd.io/mirrored-service: "true"
+subsets:
+- addresses:
+  - ip: 192.0.2.1
+  ports:
+  - name: http
+    port: 4143`,
+			},
+			service: &serviceID{namespace: "ns", name: "books-east"},
+			port:    uint32(7000),
+			expectedAddresses: []string{
+				"192.0.2.1:4143",
+			},
+			expectedNoEndpoints:              false,
+			expectedNoEndpointsServiceExists: false,
+			expectedState: servicePorts{
+				serviceID{namespace: "ns", name: "books-east"}: map[uint32]*servicePort{
+					7000: &servicePort{
+						addresses: []*updateAddress{
+							makeExternalUpdateAddress("192.0.2.1", 4143),
+						},
+						targetPort: intstr.IntOrString{Type: intstr.String, StrVal: "http"},
+						endpoints: &v1.Endpoints{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "books-east",
+								Namespace: "ns",
+								Labels:    map[string]string{"mirror.linkerd.io/mirrored-service": "true"},
+							},
+							Subsets: []v1.EndpointSubset{
+								v1.EndpointSubset{
+									Addresses: []v1.EndpointAddress{
+										v1.EndpointAddress{IP: "192.0.2.1"},
+									},
+									Ports: []v1.EndpointPort{v1.EndpointPort{Name: "http", Port: 4143}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			serviceType: "local services with no endpoints",
 			k8sConfigs: []string{`
@@ -436,14 +508,21 @@ spec:
   type: ExternalName
   externalName: foo`,
 			},
-			service:                          &serviceID{namespace: "ns", name: "name3"},
-			port:                             uint32(6969),
-			expectedAddresses:                []string{},
-			expectedNoEndpoints:              true,
+			service: &serviceID{namespace: "ns", name: "name3"},
+			port:    uint32(6969),
+			expectedAddresses: []string{
+				"10.1.2.3:6969",
+				"[2001:db8::1]:6969",
+			},
+			expectedNoEndpoints:              false,
 			expectedNoEndpointsServiceExists: false,
 			expectedState: servicePorts{
 				serviceID{namespace: "ns", name: "name3"}: map[uint32]*servicePort{
 					6969: &servicePort{
+						addresses: []*updateAddress{
+							makeExternalUpdateAddress("10.1.2.3", 6969),
+							makeExternalUpdateAddress("2001:db8::1", 6969),
+						},
 						targetPort: intstr.IntOrString{Type: intstr.Int, IntVal: 6969},
 						endpoints:  &v1.Endpoints{},
 					},
@@ -475,6 +554,12 @@ spec:
 			}
 
 			watcher := newEndpointsWatcher(k8sAPI)
+			watcher.lookupIP = func(host string) ([]net.IP, error) {
+				if host != "foo" {
+					return nil, fmt.Errorf("no such host: %s", host)
+				}
+				return []net.IP{net.ParseIP("10.1.2.3"), net.ParseIP("2001:db8::1")}, nil
+			}
 
 			k8sAPI.Sync()
 
@@ -514,3 +599,390 @@ spec:
 		})
 	}
 }
+
+func TestEndpointsWatcherExternalName(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: name1
+  namespace: ns
+spec:
+  type: ExternalName
+  externalName: foo.example.com`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	var mutex sync.Mutex
+	ips := []string{"10.1.2.3"}
+
+	watcher := newEndpointsWatcher(k8sAPI)
+	watcher.refreshInterval = 10 * time.Millisecond
+	watcher.lookupIP = func(host string) ([]net.IP, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		resolved := []net.IP{}
+		for _, ip := range ips {
+			resolved = append(resolved, net.ParseIP(ip))
+		}
+		return resolved, nil
+	}
+
+	k8sAPI.Sync()
+
+	listener := newChannelUpdateListener()
+	service := &serviceID{namespace: "ns", name: "name1"}
+	err = watcher.subscribe(service, 8080, listener)
+	if err != nil {
+		t.Fatalf("subscribe returned an error: %s", err)
+	}
+	listener.expect(t, "add 10.1.2.3:8080")
+
+	t.Run("publishes changes to the resolved addresses", func(t *testing.T) {
+		mutex.Lock()
+		ips = []string{"10.1.2.4"}
+		mutex.Unlock()
+
+		listener.expect(t, "add 10.1.2.4:8080, remove 10.1.2.3:8080")
+	})
+
+	t.Run("publishes no endpoints when the name doesn't resolve to any address", func(t *testing.T) {
+		mutex.Lock()
+		ips = []string{}
+		mutex.Unlock()
+
+		listener.expect(t, "no endpoints, exists=true")
+	})
+
+	t.Run("stops resolving when the last listener unsubscribes", func(t *testing.T) {
+		err := watcher.unsubscribe(service, 8080, listener)
+		if err != nil {
+			t.Fatalf("unsubscribe returned an error: %s", err)
+		}
+
+		mutex.Lock()
+		ips = []string{"10.1.2.5"}
+		mutex.Unlock()
+
+		select {
+		case update := <-listener.updates:
+			t.Fatalf("Unexpected update after unsubscribing: %s", update)
+		case <-time.After(5 * watcher.refreshInterval):
+		}
+	})
+}
+
+func TestEndpointsWatcherLimits(t *testing.T) {
+	var configs []string
+	for _, name := range []string{"name1", "name2", "name3"} {
+		configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: ns
+spec:
+  type: ClusterIP
+  ports:
+  - port: 8080`, name))
+	}
+
+	k8sAPI, err := k8s.NewFakeAPI("", configs...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	k8sAPI.Sync()
+
+	services := []*serviceID{
+		&serviceID{namespace: "ns", name: "name1"},
+		&serviceID{namespace: "ns", name: "name2"},
+		&serviceID{namespace: "ns", name: "name3"},
+	}
+
+	expectWatched := func(t *testing.T, watcher *endpointsWatcher, expected ...string) {
+		watched := []string{}
+		for id := range watcher.getState() {
+			watched = append(watched, id.name)
+		}
+		sort.Strings(watched)
+		if !reflect.DeepEqual(watched, expected) {
+			t.Fatalf("Expected watched services %v, got %v", expected, watched)
+		}
+	}
+
+	t.Run("keeps idle service ports watched for reuse", func(t *testing.T) {
+		watcher := newEndpointsWatcher(k8sAPI)
+		watcher.maxIdleServicePorts = 1
+
+		listener := newChannelUpdateListener()
+		if err := watcher.subscribe(services[0], 8080, listener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+		if err := watcher.unsubscribe(services[0], 8080, listener); err != nil {
+			t.Fatalf("unsubscribe returned an error: %s", err)
+		}
+		expectWatched(t, watcher, "name1")
+
+		listener = newChannelUpdateListener()
+		if err := watcher.subscribe(services[0], 8080, listener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+		listener.expect(t, "no endpoints, exists=true")
+		if watcher.idle.Len() != 0 {
+			t.Fatalf("Expected no idle service ports, got %d", watcher.idle.Len())
+		}
+	})
+
+	t.Run("evicts the least recently used idle service port", func(t *testing.T) {
+		watcher := newEndpointsWatcher(k8sAPI)
+		watcher.maxIdleServicePorts = 1
+
+		for _, service := range services[:2] {
+			listener := newChannelUpdateListener()
+			if err := watcher.subscribe(service, 8080, listener); err != nil {
+				t.Fatalf("subscribe returned an error: %s", err)
+			}
+			if err := watcher.unsubscribe(service, 8080, listener); err != nil {
+				t.Fatalf("unsubscribe returned an error: %s", err)
+			}
+		}
+		expectWatched(t, watcher, "name2")
+	})
+
+	t.Run("evicts idle service ports to stay within the limit", func(t *testing.T) {
+		watcher := newEndpointsWatcher(k8sAPI)
+		watcher.maxServicePorts = 2
+		watcher.maxIdleServicePorts = 2
+
+		for _, service := range services {
+			listener := newChannelUpdateListener()
+			if err := watcher.subscribe(service, 8080, listener); err != nil {
+				t.Fatalf("subscribe returned an error: %s", err)
+			}
+			if err := watcher.unsubscribe(service, 8080, listener); err != nil {
+				t.Fatalf("unsubscribe returned an error: %s", err)
+			}
+		}
+		expectWatched(t, watcher, "name2", "name3")
+	})
+
+	t.Run("rejects subscriptions beyond the limit", func(t *testing.T) {
+		watcher := newEndpointsWatcher(k8sAPI)
+		watcher.maxServicePorts = 2
+
+		for _, service := range services[:2] {
+			if err := watcher.subscribe(service, 8080, newChannelUpdateListener()); err != nil {
+				t.Fatalf("subscribe returned an error: %s", err)
+			}
+		}
+
+		err := watcher.subscribe(services[2], 8080, newChannelUpdateListener())
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("Expected a ResourceExhausted error, got: %v", err)
+		}
+		expectWatched(t, watcher, "name1", "name2")
+	})
+}
+
+func TestEndpointsWatcherObserve(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: name1
+  namespace: ns
+spec:
+  type: ExternalName
+  externalName: foo.example.com`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	var mutex sync.Mutex
+	ips := []string{"10.1.2.3"}
+
+	watcher := newEndpointsWatcher(k8sAPI)
+	watcher.refreshInterval = 10 * time.Millisecond
+	watcher.lookupIP = func(host string) ([]net.IP, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		resolved := []net.IP{}
+		for _, ip := range ips {
+			resolved = append(resolved, net.ParseIP(ip))
+		}
+		return resolved, nil
+	}
+
+	k8sAPI.Sync()
+
+	listener := newChannelUpdateListener()
+	service := &serviceID{namespace: "ns", name: "name1"}
+	err = watcher.subscribe(service, 8080, listener)
+	if err != nil {
+		t.Fatalf("subscribe returned an error: %s", err)
+	}
+
+	observer := watcher.observe()
+	defer watcher.stopObserving(observer)
+
+	expect := func(t *testing.T, expected string) {
+		select {
+		case update := <-observer.updates:
+			var changes []string
+			for _, a := range update.add {
+				changes = append(changes, "add "+addr.ProxyAddressToString(a.address))
+			}
+			for _, r := range update.remove {
+				changes = append(changes, "remove "+addr.ProxyAddressToString(r.address))
+			}
+			actual := fmt.Sprintf("%s:%d %s", update.service, update.port, strings.Join(changes, ", "))
+			if actual != expected {
+				t.Fatalf("Expected update [%s], got [%s]", expected, actual)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for update [%s]", expected)
+		}
+	}
+
+	t.Run("receives the current addresses", func(t *testing.T) {
+		expect(t, "name1.ns:8080 add 10.1.2.3:8080")
+	})
+
+	t.Run("receives changes to the addresses", func(t *testing.T) {
+		mutex.Lock()
+		ips = []string{"10.1.2.4"}
+		mutex.Unlock()
+
+		expect(t, "name1.ns:8080 add 10.1.2.4:8080, remove 10.1.2.3:8080")
+	})
+
+	t.Run("receives the removal of service ports that are no longer watched", func(t *testing.T) {
+		err := watcher.unsubscribe(service, 8080, listener)
+		if err != nil {
+			t.Fatalf("unsubscribe returned an error: %s", err)
+		}
+
+		expect(t, "name1.ns:8080 remove 10.1.2.4:8080")
+	})
+}
+
+func TestServicePortDebounce(t *testing.T) {
+	mirroredEndpoints := func(ips ...string) *v1.Endpoints {
+		addresses := []v1.EndpointAddress{}
+		for _, ip := range ips {
+			addresses = append(addresses, v1.EndpointAddress{IP: ip})
+		}
+		return &v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name1",
+				Namespace: "ns",
+				Labels:    map[string]string{pkgK8s.MirroredServiceLabel: "true"},
+			},
+			Subsets: []v1.EndpointSubset{
+				{Addresses: addresses},
+			},
+		}
+	}
+
+	newDebouncedServicePort := func(debounce time.Duration) (*servicePort, *channelUpdateListener) {
+		sp := newServicePort(nil, mirroredEndpoints("10.0.0.1"), 8080, nil)
+		sp.debounce = debounce
+		listener := newChannelUpdateListener()
+		sp.subscribe(true, listener)
+		return sp, listener
+	}
+
+	expectNoUpdate := func(t *testing.T, listener *channelUpdateListener, wait time.Duration) {
+		select {
+		case update := <-listener.updates:
+			t.Fatalf("Unexpected update [%s]", update)
+		case <-time.After(wait):
+		}
+	}
+
+	t.Run("batches the updates within the window", func(t *testing.T) {
+		sp, listener := newDebouncedServicePort(50 * time.Millisecond)
+		listener.expect(t, "add 10.0.0.1:8080")
+
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.1", "10.0.0.2"))
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.2"))
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.2", "10.0.0.3"))
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.3"))
+
+		listener.expect(t, "add 10.0.0.3:8080, remove 10.0.0.1:8080")
+		expectNoUpdate(t, listener, 100*time.Millisecond)
+	})
+
+	t.Run("publishes the deletion of the endpoints right away", func(t *testing.T) {
+		sp, listener := newDebouncedServicePort(time.Hour)
+		listener.expect(t, "add 10.0.0.1:8080")
+
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.2"))
+		sp.deleteEndpoints()
+
+		listener.expect(t, "no endpoints, exists=false")
+	})
+
+	t.Run("stops the pending update when closed", func(t *testing.T) {
+		sp, listener := newDebouncedServicePort(50 * time.Millisecond)
+		listener.expect(t, "add 10.0.0.1:8080")
+
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.2"))
+		sp.close()
+
+		expectNoUpdate(t, listener, 100*time.Millisecond)
+	})
+
+	t.Run("publishes each update without a window", func(t *testing.T) {
+		sp, listener := newDebouncedServicePort(0)
+		listener.expect(t, "add 10.0.0.1:8080")
+
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.1", "10.0.0.2"))
+		listener.expect(t, "add 10.0.0.2:8080")
+		sp.updateEndpoints(mirroredEndpoints("10.0.0.2"))
+		listener.expect(t, "remove 10.0.0.1:8080")
+	})
+}
+
+// implements the endpointUpdateListener interface, sending updates to a
+// channel so that they can be awaited
+type channelUpdateListener struct {
+	collectListener
+	updates chan string
+}
+
+func newChannelUpdateListener() *channelUpdateListener {
+	return &channelUpdateListener{
+		collectListener: collectListener{stopCh: make(chan struct{})},
+		updates:         make(chan string, 10),
+	}
+}
+
+func (c *channelUpdateListener) Update(add, remove []*updateAddress) {
+	var updates []string
+	for _, a := range add {
+		updates = append(updates, "add "+addr.ProxyAddressToString(a.address))
+	}
+	for _, r := range remove {
+		updates = append(updates, "remove "+addr.ProxyAddressToString(r.address))
+	}
+	c.updates <- strings.Join(updates, ", ")
+}
+
+func (c *channelUpdateListener) NoEndpoints(exists bool) {
+	c.updates <- fmt.Sprintf("no endpoints, exists=%t", exists)
+}
+
+func (c *channelUpdateListener) SetServiceID(id *serviceID) {}
+
+func (c *channelUpdateListener) expect(t *testing.T, expected string) {
+	select {
+	case update := <-c.updates:
+		if update != expected {
+			t.Fatalf("Expected update [%s], got [%s]", expected, update)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for update [%s]", expected)
+	}
+}
@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	gonet "net"
+	"time"
+
+	net "github.com/linkerd/linkerd2-proxy-api/go/net"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	"k8s.io/api/core/v1"
+)
+
+// externalNameRefreshInterval is how often the names of ExternalName services
+// are resolved again. Go's resolver doesn't expose the TTLs of the records it
+// returns, so the names are refreshed at a fixed interval in the range of
+// common TTLs instead.
+const externalNameRefreshInterval = 30 * time.Second
+
+// lookupIPFn resolves a host name to its IP addresses.
+type lookupIPFn func(host string) ([]gonet.IP, error)
+
+func isExternalName(service *v1.Service) bool {
+	return service != nil && service.Spec.Type == v1.ServiceTypeExternalName
+}
+
+// lookupExternalName resolves the name of an ExternalName service into
+// addresses on the given port. Addresses resolved from DNS are not backed by a
+// pod. Both IPv4 and IPv6 addresses are returned, for dual-stack clusters.
+func lookupExternalName(lookupIP lookupIPFn, host string, port uint32) ([]*updateAddress, error) {
+	ips, err := lookupIP(host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]*updateAddress, 0)
+	for _, ip := range ips {
+		proxyIP, err := addr.ParseProxyIP(ip.String())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, &updateAddress{
+			address: &net.TcpAddress{Ip: proxyIP, Port: port},
+		})
+	}
+	return addrs, nil
+}
+
+// resolveExternalName makes the servicePort track the addresses host resolves
+// to, until stopResolving is called or the service changes. Must be called
+// with the servicePort's mutex held.
+func (sp *servicePort) resolveExternalName(host string, resolveNow bool) {
+	sp.stopResolving()
+
+	stop := make(chan struct{})
+	sp.externalName = host
+	sp.stopResolvingCh = stop
+	go sp.refreshExternalName(host, resolveNow, stop)
+}
+
+// stopResolving stops tracking the name of an ExternalName service, if it was
+// being tracked. Must be called with the servicePort's mutex held.
+func (sp *servicePort) stopResolving() {
+	if sp.stopResolvingCh != nil {
+		close(sp.stopResolvingCh)
+		sp.stopResolvingCh = nil
+	}
+	sp.externalName = ""
+}
+
+func (sp *servicePort) refreshExternalName(host string, resolveNow bool, stop <-chan struct{}) {
+	ticker := time.NewTicker(sp.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if !resolveNow {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+		resolveNow = false
+
+		addresses, err := lookupExternalName(sp.lookupIP, host, sp.port)
+		if err != nil {
+			// keep the last known addresses until the name resolves again
+			sp.log.Errorf("Failed to resolve %s: %s", host, err)
+			continue
+		}
+
+		sp.mutex.Lock()
+		select {
+		case <-stop:
+			// the service changed while the name was being resolved
+			sp.mutex.Unlock()
+			return
+		default:
+		}
+		add, remove := diffUpdateAddresses(sp.addresses, addresses)
+		if len(add) > 0 || len(remove) > 0 {
+			sp.publishAddresses(addresses)
+		}
+		sp.mutex.Unlock()
+	}
+}
@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+// hostnameListener wraps an endpointUpdateListener subscribed to a headless
+// service, and only passes on the address of the pod with the given hostname,
+// as resolved by "hostname.service-name.namespace-name.svc.$zone" DNS names.
+type hostnameListener struct {
+	endpointUpdateListener
+	service  *serviceID
+	hostname string
+	// addresses is the set of addresses that were passed on, so that the
+	// underlying listener can be told when none remain
+	addresses map[string]struct{}
+}
+
+func newHostnameListener(service *serviceID, hostname string, listener endpointUpdateListener) *hostnameListener {
+	return &hostnameListener{
+		endpointUpdateListener: listener,
+		service:                service,
+		hostname:               hostname,
+		addresses:              make(map[string]struct{}),
+	}
+}
+
+func (h *hostnameListener) Update(add, remove []*updateAddress) {
+	add = h.filter(add)
+	remove = h.filter(remove)
+
+	for _, a := range add {
+		h.addresses[addr.ProxyAddressToString(a.address)] = struct{}{}
+	}
+	for _, r := range remove {
+		delete(h.addresses, addr.ProxyAddressToString(r.address))
+	}
+
+	if len(h.addresses) == 0 {
+		if len(remove) > 0 {
+			h.endpointUpdateListener.NoEndpoints(true)
+		}
+		return
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		h.endpointUpdateListener.Update(add, remove)
+	}
+}
+
+func (h *hostnameListener) NoEndpoints(exists bool) {
+	h.addresses = make(map[string]struct{})
+	h.endpointUpdateListener.NoEndpoints(exists)
+}
+
+// filter returns the addresses of the pods that have the listener's hostname
+// and whose subdomain is the service, as required by Kubernetes for the
+// hostname to resolve.
+func (h *hostnameListener) filter(addresses []*updateAddress) []*updateAddress {
+	filtered := make([]*updateAddress, 0)
+	for _, a := range addresses {
+		if a.pod == nil {
+			continue
+		}
+		if a.pod.Spec.Hostname == h.hostname && a.pod.Spec.Subdomain == h.service.name {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
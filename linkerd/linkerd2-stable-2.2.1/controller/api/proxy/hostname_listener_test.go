@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+func TestHostnameListener(t *testing.T) {
+	service := &serviceID{namespace: "ns", name: "db"}
+
+	makeAddress := func(ip, hostname, subdomain string) *updateAddress {
+		address := makeUpdateAddress(ip, 5432, "ns", hostname)
+		address.pod.Spec.Hostname = hostname
+		address.pod.Spec.Subdomain = subdomain
+		return address
+	}
+	db0 := makeAddress("10.1.1.1", "db-0", "db")
+	db1 := makeAddress("10.1.1.2", "db-1", "db")
+	otherSubdomain := makeAddress("10.1.1.3", "db-0", "other")
+
+	addresses := func(updates []*updateAddress) []string {
+		result := []string{}
+		for _, update := range updates {
+			result = append(result, addr.ProxyAddressToString(update.address))
+		}
+		return result
+	}
+
+	t.Run("Only passes on the address of the pod with the hostname", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newHostnameListener(service, "db-0", underlying)
+
+		listener.Update([]*updateAddress{db0, db1, otherSubdomain}, nil)
+
+		if !reflect.DeepEqual(addresses(underlying.added), []string{"10.1.1.1:5432"}) {
+			t.Fatalf("Expected only db-0 to be added, got %v", addresses(underlying.added))
+		}
+		if underlying.noEndpointsCalled {
+			t.Fatal("Expected NoEndpoints not to be called")
+		}
+	})
+
+	t.Run("Ignores updates to other pods", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newHostnameListener(service, "db-0", underlying)
+
+		listener.Update([]*updateAddress{db0}, nil)
+		listener.Update(nil, []*updateAddress{db1})
+
+		if len(underlying.removed) != 0 {
+			t.Fatalf("Expected nothing to be removed, got %v", addresses(underlying.removed))
+		}
+		if underlying.noEndpointsCalled {
+			t.Fatal("Expected NoEndpoints not to be called")
+		}
+	})
+
+	t.Run("Sends NoEndpoints when the pod goes away", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newHostnameListener(service, "db-0", underlying)
+
+		listener.Update([]*updateAddress{db0, db1}, nil)
+		listener.Update(nil, []*updateAddress{db0})
+
+		if !underlying.noEndpointsCalled || !underlying.noEndpointsExists {
+			t.Fatal("Expected NoEndpoints(true) to be called")
+		}
+	})
+}
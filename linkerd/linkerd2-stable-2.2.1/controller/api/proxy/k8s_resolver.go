@@ -45,7 +45,7 @@ func (s serviceID) String() string {
 }
 
 func (k *k8sResolver) canResolve(host string, port int) (bool, error) {
-	id, err := k.localKubernetesServiceIDFromDNSName(host)
+	id, _, err := k.localKubernetesHostFromDNSName(host)
 	if err != nil {
 		return false, err
 	}
@@ -54,7 +54,7 @@ func (k *k8sResolver) canResolve(host string, port int) (bool, error) {
 }
 
 func (k *k8sResolver) streamResolution(host string, port int, listener endpointUpdateListener) error {
-	id, err := k.localKubernetesServiceIDFromDNSName(host)
+	id, hostname, err := k.localKubernetesHostFromDNSName(host)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -68,6 +68,16 @@ func (k *k8sResolver) streamResolution(host string, port int, listener endpointU
 
 	listener.SetServiceID(id)
 
+	if hostname != "" {
+		// Only stream the address of the pod the hostname belongs to
+		listener = newHostnameListener(id, hostname, listener)
+		return k.resolveKubernetesService(id, port, listener)
+	}
+
+	if k.profileWatcher != nil {
+		return k.resolveTrafficSplit(host, id, port, listener)
+	}
+
 	return k.resolveKubernetesService(id, port, listener)
 }
 
@@ -112,6 +122,23 @@ func (k *k8sResolver) streamProfiles(host string, clientNs string, listener prof
 			return err
 		}
 		subscriptions[serverProfileID] = secondaryListener
+	} else if err == nil && serviceID == nil {
+		// host is an external authority, e.g. api.github.com, whose profile
+		// lives in the controller namespace. Proxies only ask for them if
+		// external profiles aren't disabled.
+		externalProfileID := profileID{
+			namespace: k.controllerNamespace,
+			name:      strings.TrimSuffix(host, "."),
+		}
+
+		if _, ok := subscriptions[externalProfileID]; !ok {
+			err := k.profileWatcher.subscribeToProfile(externalProfileID, secondaryListener)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+			subscriptions[externalProfileID] = secondaryListener
+		}
 	}
 
 	select {
@@ -132,6 +159,14 @@ func (k *k8sResolver) getState() servicePorts {
 	return k.endpointsWatcher.getState()
 }
 
+func (k *k8sResolver) observeEndpoints() *endpointsObserver {
+	return k.endpointsWatcher.observe()
+}
+
+func (k *k8sResolver) stopObservingEndpoints(observer *endpointsObserver) {
+	k.endpointsWatcher.stopObserving(observer)
+}
+
 func (k *k8sResolver) stop() {
 	k.endpointsWatcher.stop()
 	if k.profileWatcher != nil {
@@ -140,7 +175,10 @@ func (k *k8sResolver) stop() {
 }
 
 func (k *k8sResolver) resolveKubernetesService(id *serviceID, port int, listener endpointUpdateListener) error {
-	k.endpointsWatcher.subscribe(id, uint32(port), listener)
+	err := k.endpointsWatcher.subscribe(id, uint32(port), listener)
+	if err != nil {
+		return err
+	}
 
 	select {
 	case <-listener.ClientClose():
@@ -150,15 +188,55 @@ func (k *k8sResolver) resolveKubernetesService(id *serviceID, port int, listener
 	}
 }
 
+// resolveTrafficSplit streams the endpoints of the service, or of the backends
+// its ServiceProfile splits its traffic across.
+func (k *k8sResolver) resolveTrafficSplit(host string, id *serviceID, port int, listener endpointUpdateListener) error {
+	split := newTrafficSplitListener(k.endpointsWatcher, k.localKubernetesServiceIDFromDNSName, id, uint32(port), listener)
+	profile := profileID{
+		namespace: id.namespace,
+		name:      host,
+	}
+
+	err := k.profileWatcher.subscribeToProfile(profile, split)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	select {
+	case <-listener.ClientClose():
+		err = k.profileWatcher.unsubscribeToProfile(profile, split)
+		split.unsubscribeAll()
+		return err
+	case <-listener.ServerClose():
+		return nil
+	}
+}
+
 // localKubernetesServiceIDFromDNSName returns the name of the service in
 // "namespace-name/service-name" form if `host` is a DNS name in a form used
 // for local Kubernetes services. It returns nil if `host` isn't in such a
 // form.
 func (k *k8sResolver) localKubernetesServiceIDFromDNSName(host string) (*serviceID, error) {
-	hostLabels, err := splitDNSName(host)
+	id, hostname, err := k.localKubernetesHostFromDNSName(host)
 	if err != nil {
 		return nil, err
 	}
+	if hostname != "" {
+		return nil, fmt.Errorf("not a service: %s", host)
+	}
+	return id, nil
+}
+
+// localKubernetesHostFromDNSName is like localKubernetesServiceIDFromDNSName,
+// but also accepts the "hostname.service-name.namespace-name.svc.$zone" names
+// of the pods backing headless services, such as the pods of StatefulSets. In
+// that case, the pod's hostname is returned along with the service's name.
+func (k *k8sResolver) localKubernetesHostFromDNSName(host string) (*serviceID, string, error) {
+	hostLabels, err := splitDNSName(host)
+	if err != nil {
+		return nil, "", err
+	}
 
 	// Verify that `host` ends with ".svc.$zone", ".svc.cluster.local," or ".svc".
 	matched := false
@@ -186,20 +264,26 @@ func (k *k8sResolver) localKubernetesServiceIDFromDNSName(host string) (*service
 	// workaround until the proxies are configured to know "$zone."
 	hostLabels, matched = maybeStripSuffixLabels(hostLabels, []string{"svc"})
 	if !matched {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	// Extract the service name and namespace. TODO: Federated services have
-	// *three* components before "svc"; see
-	// https://github.com/linkerd/linkerd2/issues/156.
-	if len(hostLabels) != 2 {
-		return nil, fmt.Errorf("not a service: %s", host)
+	// Extract the service name and namespace, and the pod's hostname if there
+	// is one. TODO: Federated services also have *three* components before
+	// "svc"; see https://github.com/linkerd/linkerd2/issues/156.
+	var hostname string
+	switch len(hostLabels) {
+	case 2:
+	case 3:
+		hostname = hostLabels[0]
+		hostLabels = hostLabels[1:]
+	default:
+		return nil, "", fmt.Errorf("not a service: %s", host)
 	}
 
 	return &serviceID{
 		namespace: hostLabels[1],
 		name:      hostLabels[0],
-	}, nil
+	}, hostname, nil
 }
 
 func splitDNSName(dnsName string) ([]string, error) {
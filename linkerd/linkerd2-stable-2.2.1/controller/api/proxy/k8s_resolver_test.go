@@ -52,6 +52,37 @@ func TestK8sResolver(t *testing.T) {
 		}
 	})
 
+	t.Run("streams the profiles of external authorities from the controller namespace", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: api.github.com
+  namespace: linkerd
+spec:
+  routes:
+  - name: GET /repos
+    condition:
+      method: GET`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		resolver := newK8sResolver(someKubernetesDNSZone, "linkerd", nil, newProfileWatcher(k8sAPI))
+		k8sAPI.Sync()
+
+		listener, cancelFn := newCollectProfileListener()
+		cancelFn()
+
+		err = resolver.streamProfiles("api.github.com", "", listener)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(listener.profiles) == 0 || listener.profiles[len(listener.profiles)-1].GetName() != "api.github.com" {
+			t.Fatalf("Expected the profile of api.github.com, got %v", listener.profiles)
+		}
+	})
 }
 
 func TestGetState(t *testing.T) {
@@ -199,6 +230,24 @@ func TestLocalKubernetesServiceIdFromDNSName(t *testing.T) {
 		assertReturnError(t, resolver, invalidServiceNames)
 	})
 
+	t.Run("Resolves the hostnames of pods backing headless services", func(t *testing.T) {
+		resolver := &k8sResolver{k8sDNSZoneLabels: someKubernetesDNSZone}
+		for _, name := range []string{"db-0.db.ns.svc", "db-0.db.ns.svc.cluster.local", "db-0.db.ns.svc.some.namespace."} {
+			id, hostname, err := resolver.localKubernetesHostFromDNSName(name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id == nil || id.String() != "db.ns" || hostname != "db-0" {
+				t.Fatalf("Expected [%s] to resolve to hostname [db-0] of [db.ns], got hostname [%s] of [%v]", name, hostname, id)
+			}
+		}
+
+		_, _, err := resolver.localKubernetesHostFromDNSName("a.b.c.d.svc")
+		if err == nil {
+			t.Fatal("Expected an error for a name with four labels before 'svc'")
+		}
+	})
+
 }
 
 func TestSplitDNSName(t *testing.T) {
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	servicePortsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "destination_service_ports",
+			Help: "A gauge for the number of service ports watched by the destination service, including idle ones.",
+		},
+	)
+
+	idleServicePortsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "destination_idle_service_ports",
+			Help: "A gauge for the number of watched service ports that have no listeners left.",
+		},
+	)
+
+	servicePortEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "destination_service_port_evictions_total",
+			Help: "A counter for the idle service ports that stopped being watched to make room for others.",
+		},
+	)
+
+	endpointListenersGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "destination_endpoint_listeners",
+			Help: "A gauge for the number of listeners subscribed to the endpoints of service ports.",
+		},
+	)
+
+	endpointUpdateFanout = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "destination_endpoint_update_fanout",
+			Help:    "A histogram of the number of listeners each update of the endpoints of a service port is published to.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 7),
+		},
+	)
+
+	unchangedEndpointUpdates = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "destination_unchanged_endpoint_updates_total",
+			Help: "A counter for the updates of the endpoints of service ports that didn't change their addresses, which aren't published to the listeners.",
+		},
+	)
+
+	batchedEndpointUpdates = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "destination_batched_endpoint_updates_total",
+			Help: "A counter for the updates of the endpoints of service ports that were batched with an earlier one within the debounce window.",
+		},
+	)
+
+	endpointsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "destination_endpoints",
+			Help: "A gauge for the number of endpoints of the watched service ports.",
+		},
+	)
+
+	profilesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "destination_profiles",
+			Help: "A gauge for the number of service profiles watched by the destination service.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		servicePortsGauge,
+		idleServicePortsGauge,
+		servicePortEvictions,
+		endpointListenersGauge,
+		endpointUpdateFanout,
+		unchangedEndpointUpdates,
+		batchedEndpointUpdates,
+		endpointsGauge,
+		profilesGauge,
+	)
+}
@@ -18,12 +18,22 @@ type profileUpdateListener interface {
 type profileListener struct {
 	stream pb.Destination_GetProfileServer
 	stopCh chan struct{}
+	// clientNamespace and clientLabels identify the proxy that requested the
+	// profile, to select the profile's perClient overrides
+	clientNamespace string
+	clientLabels    map[string]string
 }
 
-func newProfileListener(stream pb.Destination_GetProfileServer) *profileListener {
+func newProfileListener(
+	stream pb.Destination_GetProfileServer,
+	clientNamespace string,
+	clientLabels map[string]string,
+) *profileListener {
 	return &profileListener{
-		stream: stream,
-		stopCh: make(chan struct{}),
+		stream:          stream,
+		stopCh:          make(chan struct{}),
+		clientNamespace: clientNamespace,
+		clientLabels:    clientLabels,
 	}
 }
 
@@ -40,6 +50,7 @@ func (l *profileListener) Stop() {
 }
 
 func (l *profileListener) Update(profile *sp.ServiceProfile) {
+	profile = profiles.ForClient(profile, l.clientNamespace, l.clientLabels)
 	if profile == nil {
 		l.stream.Send(&profiles.DefaultServiceProfile)
 		return
@@ -10,6 +10,7 @@ import (
 	httpPb "github.com/linkerd/linkerd2-proxy-api/go/http_types"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/profiles"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -184,6 +185,25 @@ var (
 		RetryBudget: &profiles.DefaultRetryBudget,
 	}
 
+	perClientProfile = &sp.ServiceProfile{
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				route1,
+			},
+			PerClient: []*sp.ClientProfile{
+				&sp.ClientProfile{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "client"},
+					},
+					Routes: []*sp.RouteSpec{
+						route1,
+						route2,
+					},
+				},
+			},
+		},
+	}
+
 	defaultPbProfile = &pb.DestinationProfile{
 		Routes:      []*pb.Route{},
 		RetryBudget: &profiles.DefaultRetryBudget,
@@ -610,4 +630,25 @@ func TestProfileListener(t *testing.T) {
 			t.Fatalf("Expected profile sent to be [%v] but was [%v]", pbProfileWithTimeout, actualPbProfile)
 		}
 	})
+
+	t.Run("Sends the overrides for the client", func(t *testing.T) {
+		mockGetProfileServer := &mockDestinationGetProfileServer{profilesReceived: []*pb.DestinationProfile{}}
+
+		listener := &profileListener{
+			stream:          mockGetProfileServer,
+			clientNamespace: "client-ns",
+			clientLabels:    map[string]string{"app": "client"},
+		}
+
+		listener.Update(perClientProfile)
+
+		numProfiles := len(mockGetProfileServer.profilesReceived)
+		if numProfiles != 1 {
+			t.Fatalf("Expecting [1] profile, got [%d]. Updates: %v", numProfiles, mockGetProfileServer.profilesReceived)
+		}
+		actualPbProfile := mockGetProfileServer.profilesReceived[0]
+		if !reflect.DeepEqual(actualPbProfile, pbProfile) {
+			t.Fatalf("Expected profile sent to be [%v] but was [%v]", pbProfile, actualPbProfile)
+		}
+	})
 }
@@ -72,6 +72,7 @@ func (p *profileWatcher) subscribeToProfile(name profileID, listener profileUpda
 
 		profileEntry = newProfileEntry(profile)
 		p.profiles[name] = profileEntry
+		profilesGauge.Inc()
 	}
 	profileEntry.subscribe(listener)
 	return nil
@@ -92,6 +93,7 @@ func (p *profileWatcher) unsubscribeToProfile(profile profileID, listener profil
 	}
 	if numListeners == 0 {
 		delete(p.profiles, profile)
+		profilesGauge.Dec()
 	}
 	return nil
 }
@@ -5,5 +5,7 @@ type streamingDestinationResolver interface {
 	streamResolution(host string, port int, listener endpointUpdateListener) error
 	streamProfiles(host string, clientNs string, listener profileUpdateListener) error
 	getState() servicePorts
+	observeEndpoints() *endpointsObserver
+	stopObservingEndpoints(observer *endpointsObserver)
 	stop()
 }
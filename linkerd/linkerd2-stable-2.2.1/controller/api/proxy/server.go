@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
 	"github.com/linkerd/linkerd2/controller/api/util"
@@ -14,6 +15,8 @@ import (
 	"github.com/linkerd/linkerd2/pkg/prometheus"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type server struct {
@@ -21,6 +24,7 @@ type server struct {
 	resolver        streamingDestinationResolver
 	enableH2Upgrade bool
 	enableTLS       bool
+	topology        *topologyWeigher
 	log             *log.Entry
 }
 
@@ -35,15 +39,26 @@ type server struct {
 // omitted, "default" is used as a default.append
 //
 // Addresses for the given destination are fetched from the Kubernetes Endpoints
-// API.
+// API. Unless running in single namespace mode, they are labeled with the zone
+// and region of their node, and weighed according to topologyWeighting. The
+// changes to the Endpoints of a service within endpointsDebounce are batched
+// into a single update.
 func NewServer(
 	addr, k8sDNSZone string,
 	controllerNamespace string,
 	enableTLS, enableH2Upgrade, singleNamespace bool,
+	topologyWeighting string,
+	maxServicePorts, maxIdleServicePorts int,
+	endpointsDebounce time.Duration,
 	k8sAPI *k8s.API,
 	done chan struct{},
 ) (*grpc.Server, error) {
-	resolver, err := buildResolver(k8sDNSZone, controllerNamespace, k8sAPI, singleNamespace)
+	resolver, err := buildResolver(k8sDNSZone, controllerNamespace, k8sAPI, singleNamespace, maxServicePorts, maxIdleServicePorts, endpointsDebounce)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := newTopologyWeigher(k8sAPI, topologyWeighting, !singleNamespace)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +68,7 @@ func NewServer(
 		resolver:        resolver,
 		enableH2Upgrade: enableH2Upgrade,
 		enableTLS:       enableTLS,
+		topology:        topology,
 		log: log.WithFields(log.Fields{
 			"addr":      addr,
 			"component": "server",
@@ -92,15 +108,17 @@ func (s *server) GetProfile(dest *pb.GetDestination, stream pb.Destination_GetPr
 		return err
 	}
 
-	listener := newProfileListener(stream)
-
 	proxyID := strings.Split(dest.ProxyId, ".")
 	proxyNS := ""
+	var proxyLabels map[string]string
 	// <deployment>.deployment.<namespace>.linkerd-managed.linkerd.svc.cluster.local
 	if len(proxyID) >= 3 {
 		proxyNS = proxyID[2]
+		proxyLabels = s.getProxyPodLabels(proxyID[1], proxyID[0], proxyNS)
 	}
 
+	listener := newProfileListener(stream, proxyNS, proxyLabels)
+
 	err = s.resolver.streamProfiles(host, proxyNS, listener)
 	if err != nil {
 		s.log.Errorf("Error streaming profile for %s: %v", dest.Path, err)
@@ -127,16 +145,7 @@ func (s *server) Endpoints(ctx context.Context, params *discovery.EndpointsParam
 			}
 
 			for _, ua := range sp.addresses {
-				ownerKind, ownerName := s.k8sAPI.GetOwnerKindAndName(ua.pod)
-				pod := util.K8sPodToPublicPod(*ua.pod, ownerKind, ownerName)
-
-				podAddrs.PodAddresses = append(
-					podAddrs.PodAddresses,
-					&discovery.PodAddress{
-						Addr: addr.NetToPublic(ua.address),
-						Pod:  &pod,
-					},
-				)
+				podAddrs.PodAddresses = append(podAddrs.PodAddresses, s.toPodAddress(ua, params.GetClientZone()))
 			}
 
 			discoverySP.PortEndpoints[port] = &podAddrs
@@ -149,8 +158,62 @@ func (s *server) Endpoints(ctx context.Context, params *discovery.EndpointsParam
 	return &rsp, nil
 }
 
+func (s *server) WatchEndpoints(params *discovery.EndpointsParams, stream discovery.Discovery_WatchEndpointsServer) error {
+	s.log.Debugf("WatchEndpoints(%+v)", params)
+
+	observer := s.resolver.observeEndpoints()
+	defer s.resolver.stopObservingEndpoints(observer)
+
+	for {
+		select {
+		case update := <-observer.updates:
+			rsp := &discovery.EndpointsUpdate{
+				Service: update.service.String(),
+				Port:    update.port,
+			}
+			for _, ua := range update.add {
+				rsp.Add = append(rsp.Add, s.toPodAddress(ua, params.GetClientZone()))
+			}
+			for _, ua := range update.remove {
+				rsp.Remove = append(rsp.Remove, s.toPodAddress(ua, params.GetClientZone()))
+			}
+			err := stream.Send(rsp)
+			if err != nil {
+				return err
+			}
+		case <-observer.overflow:
+			return status.Error(codes.ResourceExhausted, "Endpoints changed faster than they could be sent")
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// toPodAddress returns the address and pod of an endpoint, with the weight it
+// has for proxies in the given zone.
+func (s *server) toPodAddress(ua *updateAddress, clientZone string) *discovery.PodAddress {
+	podAddr := &discovery.PodAddress{
+		Addr:   addr.NetToPublic(ua.address),
+		Weight: addr.DefaultWeight,
+	}
+	if s.topology != nil {
+		podAddr.Zone, _ = s.topology.podTopology(ua.pod)
+		podAddr.Weight = s.topology.weight(clientZone, podAddr.Zone)
+	}
+	if ua.pod != nil {
+		ownerKind, ownerName := s.k8sAPI.GetOwnerKindAndName(ua.pod)
+		pod := util.K8sPodToPublicPod(*ua.pod, ownerKind, ownerName)
+		podAddr.Pod = &pod
+	}
+	return podAddr
+}
+
 func (s *server) streamResolution(host string, port int, stream pb.Destination_GetServer) error {
 	listener := newEndpointListener(stream, s.k8sAPI.GetOwnerKindAndName, s.enableTLS, s.enableH2Upgrade)
+	if s.topology != nil {
+		listener.topology = s.topology
+		listener.clientZone = s.topology.clientZone(stream.Context())
+	}
 
 	resolverCanResolve, err := s.resolver.canResolve(host, port)
 	if err != nil {
@@ -162,6 +225,22 @@ func (s *server) streamResolution(host string, port int, stream pb.Destination_G
 	return s.resolver.streamResolution(host, port, listener)
 }
 
+// getProxyPodLabels returns the labels of one of the pods of the workload
+// identified by a proxy ID, which are used to select the perClient overrides
+// of the profiles sent to the proxy. The pods of a workload are assumed to
+// share the labels the overrides select them by.
+func (s *server) getProxyPodLabels(kind, name, namespace string) map[string]string {
+	pods, err := s.k8sAPI.GetPodsForOwner(namespace, kind, name)
+	if err != nil {
+		s.log.Errorf("Error getting the pods of %s/%s in %s: %s", kind, name, namespace, err)
+		return nil
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+	return pods[0].Labels
+}
+
 func getHostAndPort(dest *pb.GetDestination) (string, int, error) {
 	if dest.Scheme != "k8s" {
 		err := fmt.Errorf("Unsupported scheme %s", dest.Scheme)
@@ -192,6 +271,8 @@ func buildResolver(
 	k8sDNSZone, controllerNamespace string,
 	k8sAPI *k8s.API,
 	singleNamespace bool,
+	maxServicePorts, maxIdleServicePorts int,
+	endpointsDebounce time.Duration,
 ) (streamingDestinationResolver, error) {
 	var k8sDNSZoneLabels []string
 	if k8sDNSZone == "" {
@@ -209,7 +290,12 @@ func buildResolver(
 		pw = newProfileWatcher(k8sAPI)
 	}
 
-	k8sResolver := newK8sResolver(k8sDNSZoneLabels, controllerNamespace, newEndpointsWatcher(k8sAPI), pw)
+	ew := newEndpointsWatcher(k8sAPI)
+	ew.maxServicePorts = maxServicePorts
+	ew.maxIdleServicePorts = maxIdleServicePorts
+	ew.debounce = endpointsDebounce
+
+	k8sResolver := newK8sResolver(k8sDNSZoneLabels, controllerNamespace, ew, pw)
 
 	log.Infof("Built k8s name resolver")
 
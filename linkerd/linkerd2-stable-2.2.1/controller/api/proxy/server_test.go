@@ -53,7 +53,7 @@ func TestBuildResolver(t *testing.T) {
 	t.Run("Doesn't build a resolver if Kubernetes DNS zone isnt valid", func(t *testing.T) {
 		invalidK8sDNSZones := []string{"1", "-a", "a-", "-"}
 		for _, dsnZone := range invalidK8sDNSZones {
-			resolver, err := buildResolver(dsnZone, "linkerd", k8sAPI, false)
+			resolver, err := buildResolver(dsnZone, "linkerd", k8sAPI, false, 0, 0, 0)
 			if err == nil {
 				t.Fatalf("Expecting error when k8s zone is [%s], got nothing. Resolver: %v", dsnZone, resolver)
 			}
@@ -90,6 +90,12 @@ func (m *mockStreamingDestinationResolver) getState() servicePorts {
 	return servicePorts{}
 }
 
+func (m *mockStreamingDestinationResolver) observeEndpoints() *endpointsObserver {
+	return newEndpointsObserver()
+}
+
+func (m *mockStreamingDestinationResolver) stopObservingEndpoints(observer *endpointsObserver) {}
+
 func (m *mockStreamingDestinationResolver) stop() {}
 
 func TestStreamResolutionUsingCorrectResolverFor(t *testing.T) {
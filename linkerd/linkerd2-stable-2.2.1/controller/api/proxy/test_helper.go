@@ -120,7 +120,7 @@ func equalServicePorts(servicePorts1, servicePorts2 servicePorts) error {
 }
 
 func makeUpdateAddress(ipStr string, portNum uint32, ns string, name string) *updateAddress {
-	ip, _ := addr.ParseProxyIPV4(ipStr)
+	ip, _ := addr.ParseProxyIP(ipStr)
 	return &updateAddress{
 		address: &proxyNet.TcpAddress{Ip: ip, Port: portNum},
 		pod: &v1.Pod{
@@ -132,6 +132,13 @@ func makeUpdateAddress(ipStr string, portNum uint32, ns string, name string) *up
 	}
 }
 
+func makeExternalUpdateAddress(ipStr string, portNum uint32) *updateAddress {
+	ip, _ := addr.ParseProxyIP(ipStr)
+	return &updateAddress{
+		address: &proxyNet.TcpAddress{Ip: ip, Port: portNum},
+	}
+}
+
 // InitFakeDiscoveryServer takes a Kubernetes API client and returns a fake
 // discovery API client, gRPC Server, and gRPC client connection.
 // The caller is responsible for calling Server.GracefulStop() and
@@ -146,7 +153,7 @@ func InitFakeDiscoveryServer(t *testing.T, k8sAPI *k8s.API) (discovery.Discovery
 	lis := bufconn.Listen(1024 * 1024)
 	gRPCServer, err := NewServer(
 		"fake-addr", "", "controller-ns",
-		false, false, false, k8sAPI, nil,
+		false, false, false, TopologyWeightingNone, 0, 0, 0, k8sAPI, nil,
 	)
 	if err != nil {
 		t.Fatalf("Unexpected error: %s", err)
@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	gonet "net"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	"google.golang.org/grpc/peer"
+	coreV1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// TopologyWeightingNone gives all endpoints the same weight.
+	TopologyWeightingNone = "none"
+	// TopologyWeightingPreferSameZone gives endpoints in the client's zone a
+	// higher weight than endpoints in other zones.
+	TopologyWeightingPreferSameZone = "prefer-same-zone"
+
+	// sameZoneWeightFactor is how much more traffic endpoints in the client's
+	// zone receive with the prefer-same-zone weighting.
+	sameZoneWeightFactor = 10
+
+	zoneLabel   = "failure-domain.beta.kubernetes.io/zone"
+	regionLabel = "failure-domain.beta.kubernetes.io/region"
+
+	podIPIndex = "ip"
+)
+
+// topologyWeigher looks up the zone and region of endpoints from the labels of
+// the nodes their pods run on, and weighs endpoints according to the zone of
+// the client they're sent to.
+type topologyWeigher struct {
+	nodeLister     corelisters.NodeLister
+	podIndexer     cache.Indexer
+	preferSameZone bool
+}
+
+// newTopologyWeigher returns a topologyWeigher for the given weighting. It
+// returns nil if nodes aren't watched, in which case the topology of endpoints
+// isn't known.
+func newTopologyWeigher(k8sAPI *k8s.API, weighting string, watchNodes bool) (*topologyWeigher, error) {
+	switch weighting {
+	case "", TopologyWeightingNone, TopologyWeightingPreferSameZone:
+	default:
+		return nil, fmt.Errorf("unsupported topology weighting: %s", weighting)
+	}
+
+	if !watchNodes {
+		if weighting == TopologyWeightingPreferSameZone {
+			return nil, fmt.Errorf("%s topology weighting requires watching nodes", weighting)
+		}
+		return nil, nil
+	}
+
+	k8sAPI.Pod().Informer().AddIndexers(cache.Indexers{podIPIndex: indexPodByIP})
+
+	return &topologyWeigher{
+		nodeLister:     k8sAPI.Node().Lister(),
+		podIndexer:     k8sAPI.Pod().Informer().GetIndexer(),
+		preferSameZone: weighting == TopologyWeightingPreferSameZone,
+	}, nil
+}
+
+// podTopology returns the zone and region of the node the pod runs on, if
+// they're known.
+func (t *topologyWeigher) podTopology(pod *coreV1.Pod) (string, string) {
+	if pod == nil || pod.Spec.NodeName == "" {
+		return "", ""
+	}
+	node, err := t.nodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		return "", ""
+	}
+	return node.Labels[zoneLabel], node.Labels[regionLabel]
+}
+
+// clientZone returns the zone of the pod a request was sent from, if it's
+// known.
+func (t *topologyWeigher) clientZone(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tcpAddr, ok := p.Addr.(*gonet.TCPAddr)
+	if !ok {
+		return ""
+	}
+
+	objs, err := t.podIndexer.ByIndex(podIPIndex, tcpAddr.IP.String())
+	if err != nil {
+		return ""
+	}
+	for _, obj := range objs {
+		pod := obj.(*coreV1.Pod)
+		if pod.Status.Phase == coreV1.PodRunning && !pod.Spec.HostNetwork {
+			zone, _ := t.podTopology(pod)
+			return zone
+		}
+	}
+	return ""
+}
+
+// weight returns the weight of an endpoint in the given zone, for clients in
+// clientZone.
+func (t *topologyWeigher) weight(clientZone, zone string) uint32 {
+	if t.preferSameZone && clientZone != "" && zone == clientZone {
+		return addr.DefaultWeight * sameZoneWeightFactor
+	}
+	return addr.DefaultWeight
+}
+
+func indexPodByIP(obj interface{}) ([]string, error) {
+	if pod, ok := obj.(*coreV1.Pod); ok {
+		return []string{pod.Status.PodIP}, nil
+	}
+	return []string{""}, fmt.Errorf("object is not a pod")
+}
@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"context"
+	gonet "net"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"google.golang.org/grpc/peer"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTopologyWeigher(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-a
+  labels:
+    failure-domain.beta.kubernetes.io/zone: us-east-1a
+    failure-domain.beta.kubernetes.io/region: us-east-1`, `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-b
+  labels:
+    failure-domain.beta.kubernetes.io/zone: us-east-1b
+    failure-domain.beta.kubernetes.io/region: us-east-1`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: client
+  namespace: ns
+spec:
+  nodeName: node-b
+status:
+  phase: Running
+  podIP: 10.1.1.1`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	topology, err := newTopologyWeigher(k8sAPI, TopologyWeightingPreferSameZone, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	k8sAPI.Sync()
+
+	t.Run("Returns the zone and region of the pod's node", func(t *testing.T) {
+		pod := &coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: "ns"},
+			Spec:       coreV1.PodSpec{NodeName: "node-a"},
+		}
+		zone, region := topology.podTopology(pod)
+		if zone != "us-east-1a" || region != "us-east-1" {
+			t.Fatalf("Expected zone [us-east-1a] and region [us-east-1], got [%s] and [%s]", zone, region)
+		}
+
+		zone, region = topology.podTopology(&coreV1.Pod{Spec: coreV1.PodSpec{NodeName: "unknown"}})
+		if zone != "" || region != "" {
+			t.Fatalf("Expected an unknown topology, got [%s] and [%s]", zone, region)
+		}
+	})
+
+	t.Run("Returns the zone of the client", func(t *testing.T) {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &gonet.TCPAddr{IP: gonet.ParseIP("10.1.1.1"), Port: 45678},
+		})
+		if zone := topology.clientZone(ctx); zone != "us-east-1b" {
+			t.Fatalf("Expected client zone [us-east-1b], got [%s]", zone)
+		}
+
+		ctx = peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &gonet.TCPAddr{IP: gonet.ParseIP("10.9.9.9"), Port: 45678},
+		})
+		if zone := topology.clientZone(ctx); zone != "" {
+			t.Fatalf("Expected an unknown client zone, got [%s]", zone)
+		}
+	})
+
+	t.Run("Prefers endpoints in the same zone", func(t *testing.T) {
+		testCases := []struct {
+			clientZone string
+			zone       string
+			weight     uint32
+		}{
+			{clientZone: "us-east-1a", zone: "us-east-1a", weight: sameZoneWeightFactor},
+			{clientZone: "us-east-1a", zone: "us-east-1b", weight: 1},
+			{clientZone: "", zone: "", weight: 1},
+			{clientZone: "us-east-1a", zone: "", weight: 1},
+		}
+		for _, tc := range testCases {
+			if weight := topology.weight(tc.clientZone, tc.zone); weight != tc.weight {
+				t.Errorf("Expected weight %d for zone [%s] and client zone [%s], got %d", tc.weight, tc.zone, tc.clientZone, weight)
+			}
+		}
+	})
+
+	t.Run("Labels and weighs the addresses sent to proxies", func(t *testing.T) {
+		mockGetServer := &mockDestinationGetServer{updatesReceived: []*pb.Update{}}
+		listener := newEndpointListener(mockGetServer, defaultOwnerKindAndName, false, false)
+		listener.topology = topology
+		listener.clientZone = "us-east-1a"
+
+		pod := &coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: "ns"},
+			Spec:       coreV1.PodSpec{NodeName: "node-a"},
+		}
+		listener.Update([]*updateAddress{&updateAddress{address: addedAddress1, pod: pod}}, nil)
+
+		addr := mockGetServer.updatesReceived[0].GetAdd().Addrs[0]
+		if addr.MetricLabels["zone"] != "us-east-1a" || addr.MetricLabels["region"] != "us-east-1" {
+			t.Fatalf("Expected zone and region metric labels, got %v", addr.MetricLabels)
+		}
+		if addr.Weight != sameZoneWeightFactor {
+			t.Fatalf("Expected weight %d, got %d", sameZoneWeightFactor, addr.Weight)
+		}
+	})
+
+	t.Run("Rejects unknown weightings", func(t *testing.T) {
+		_, err := newTopologyWeigher(k8sAPI, "prefer-other-zones", true)
+		if err == nil {
+			t.Fatal("Expected an error for an unknown weighting")
+		}
+	})
+}
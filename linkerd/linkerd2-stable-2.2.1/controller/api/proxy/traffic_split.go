@@ -0,0 +1,325 @@
+package proxy
+
+import (
+	"fmt"
+	gonet "net"
+	"strconv"
+	"sync"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	log "github.com/sirupsen/logrus"
+)
+
+// splitWeightScale scales the weights of the backends of a traffic split
+// before they're divided among the backends' addresses, so that the addresses
+// of backends with many endpoints still get a non-zero weight.
+const splitWeightScale = 1000
+
+// backendID identifies a service port that receives traffic sent to a service.
+type backendID struct {
+	service serviceID
+	port    uint32
+}
+
+func (b backendID) String() string {
+	return fmt.Sprintf("%s:%d", b.service, b.port)
+}
+
+// trafficSplitListener implements the profileUpdateListener interface. It's
+// subscribed to the ServiceProfile of a service, and when the profile has
+// dstOverrides, it streams the endpoints of the backend services they name to
+// the underlying endpointUpdateListener, weighted according to the overrides,
+// instead of the endpoints of the service itself.
+type trafficSplitListener struct {
+	listener  endpointUpdateListener
+	endpoints *endpointsWatcher
+	// resolveAuthority returns the service a dstOverride's authority refers to
+	resolveAuthority func(host string) (*serviceID, error)
+	apex             backendID
+
+	mutex sync.Mutex
+	// backends are the backends that are subscribed to
+	backends map[backendID]*splitBackend
+	// weights are the backends that currently receive traffic, with the
+	// weights from the profile. The apex service has a weight of 0 when the
+	// service isn't split, in which case its addresses are passed on as is.
+	weights map[backendID]uint32
+	// sent are the addresses that were sent to the underlying listener
+	sent     map[string]*updateAddress
+	stopOnce sync.Once
+	log      *log.Entry
+}
+
+func newTrafficSplitListener(
+	endpoints *endpointsWatcher,
+	resolveAuthority func(host string) (*serviceID, error),
+	service *serviceID,
+	port uint32,
+	listener endpointUpdateListener,
+) *trafficSplitListener {
+	return &trafficSplitListener{
+		listener:         listener,
+		endpoints:        endpoints,
+		resolveAuthority: resolveAuthority,
+		apex:             backendID{service: *service, port: port},
+		backends:         make(map[backendID]*splitBackend),
+		weights:          make(map[backendID]uint32),
+		sent:             make(map[string]*updateAddress),
+		log: log.WithFields(log.Fields{
+			"component": "traffic-split-listener",
+			"service":   service.String(),
+		}),
+	}
+}
+
+func (s *trafficSplitListener) ClientClose() <-chan struct{} {
+	return s.listener.ClientClose()
+}
+
+func (s *trafficSplitListener) ServerClose() <-chan struct{} {
+	return s.listener.ServerClose()
+}
+
+// Stop may be called by both the profile watcher and the endpoints watchers
+// of the backends, but only stops the underlying listener once.
+func (s *trafficSplitListener) Stop() {
+	s.stopOnce.Do(s.listener.Stop)
+}
+
+// Update subscribes to the backends of the given profile and unsubscribes
+// from the backends that were removed from it. Backends are subscribed to
+// before they start receiving traffic, so that the service doesn't appear to
+// have no endpoints while the split changes.
+func (s *trafficSplitListener) Update(profile *sp.ServiceProfile) {
+	weights := s.profileWeights(profile)
+
+	s.mutex.Lock()
+	added := make([]*splitBackend, 0)
+	for id := range weights {
+		if _, ok := s.backends[id]; !ok {
+			backend := newSplitBackend(s, id)
+			s.backends[id] = backend
+			added = append(added, backend)
+		}
+	}
+	s.mutex.Unlock()
+
+	// Subscribing publishes the backend's current addresses to it, which
+	// requires the mutex, so it's released while subscribing.
+	for _, backend := range added {
+		err := s.endpoints.subscribe(&backend.id.service, backend.id.port, backend)
+		if err != nil {
+			s.log.Errorf("Failed to subscribe to %s: %s", backend.id, err)
+		}
+	}
+
+	s.mutex.Lock()
+	s.weights = weights
+	removed := make([]*splitBackend, 0)
+	for id, backend := range s.backends {
+		if _, ok := weights[id]; !ok {
+			delete(s.backends, id)
+			removed = append(removed, backend)
+		}
+	}
+	s.publish()
+	s.mutex.Unlock()
+
+	for _, backend := range removed {
+		s.unsubscribe(backend)
+	}
+}
+
+// unsubscribeAll unsubscribes from all the backends, once the listener is
+// closed.
+func (s *trafficSplitListener) unsubscribeAll() {
+	s.mutex.Lock()
+	backends := s.backends
+	s.backends = make(map[backendID]*splitBackend)
+	s.mutex.Unlock()
+
+	for _, backend := range backends {
+		s.unsubscribe(backend)
+	}
+}
+
+func (s *trafficSplitListener) unsubscribe(backend *splitBackend) {
+	err := s.endpoints.unsubscribe(&backend.id.service, backend.id.port, backend)
+	if err != nil {
+		s.log.Errorf("Failed to unsubscribe from %s: %s", backend.id, err)
+	}
+}
+
+// profileWeights returns the weights of the backends of the given profile, or
+// just the apex service if the profile has no valid dstOverrides.
+func (s *trafficSplitListener) profileWeights(profile *sp.ServiceProfile) map[backendID]uint32 {
+	weights := make(map[backendID]uint32)
+	if profile == nil {
+		weights[s.apex] = 0
+		return weights
+	}
+
+	for _, dst := range profile.Spec.DstOverrides {
+		if dst.Weight == 0 {
+			continue
+		}
+		id, err := s.backendFromAuthority(dst.Authority)
+		if err != nil {
+			s.log.Errorf("Ignoring dstOverride %s: %s", dst.Authority, err)
+			continue
+		}
+		weights[*id] += dst.Weight
+	}
+
+	if len(weights) == 0 {
+		weights[s.apex] = 0
+	}
+	return weights
+}
+
+// backendFromAuthority returns the backend an authority of the form
+// "service-name.namespace-name.svc.$zone[:port]" refers to. The port defaults
+// to the port of the apex service.
+func (s *trafficSplitListener) backendFromAuthority(authority string) (*backendID, error) {
+	host := authority
+	port := s.apex.port
+	if h, p, err := gonet.SplitHostPort(authority); err == nil {
+		parsed, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", p)
+		}
+		host = h
+		port = uint32(parsed)
+	}
+
+	id, err := s.resolveAuthority(host)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, fmt.Errorf("not a local Kubernetes service")
+	}
+	return &backendID{service: *id, port: port}, nil
+}
+
+// publish sends the changes between the addresses that were last sent and the
+// current addresses of the backends that receive traffic to the underlying
+// listener. Must be called with the mutex held.
+func (s *trafficSplitListener) publish() {
+	addresses := make(map[string]*updateAddress)
+	exists := false
+	for id, weight := range s.weights {
+		backend, ok := s.backends[id]
+		if !ok {
+			continue
+		}
+		exists = exists || backend.exists
+
+		for key, address := range backend.addresses {
+			if weight == 0 {
+				addresses[key] = address
+				continue
+			}
+			weighted := *address
+			weighted.weight = weight * splitWeightScale / uint32(len(backend.addresses))
+			if weighted.weight == 0 {
+				weighted.weight = 1
+			}
+			weighted.leafService = id.service.name
+			addresses[key] = &weighted
+		}
+	}
+
+	if len(addresses) == 0 {
+		s.sent = make(map[string]*updateAddress)
+		s.listener.NoEndpoints(exists)
+		return
+	}
+
+	add := make([]*updateAddress, 0)
+	for key, address := range addresses {
+		sent, ok := s.sent[key]
+		if !ok || sent.weight != address.weight || sent.leafService != address.leafService {
+			add = append(add, address)
+		}
+	}
+	remove := make([]*updateAddress, 0)
+	for key, address := range s.sent {
+		if _, ok := addresses[key]; !ok {
+			remove = append(remove, address)
+		}
+	}
+
+	s.sent = addresses
+	if len(add) > 0 || len(remove) > 0 {
+		s.listener.Update(add, remove)
+	}
+}
+
+// splitBackend implements the endpointUpdateListener interface, and tracks
+// the addresses of one of the backends of a trafficSplitListener.
+type splitBackend struct {
+	split     *trafficSplitListener
+	id        backendID
+	exists    bool
+	addresses map[string]*updateAddress
+}
+
+func newSplitBackend(split *trafficSplitListener, id backendID) *splitBackend {
+	return &splitBackend{
+		split:     split,
+		id:        id,
+		addresses: make(map[string]*updateAddress),
+	}
+}
+
+func (b *splitBackend) ClientClose() <-chan struct{} {
+	return b.split.ClientClose()
+}
+
+func (b *splitBackend) ServerClose() <-chan struct{} {
+	return b.split.ServerClose()
+}
+
+func (b *splitBackend) Stop() {
+	b.split.Stop()
+}
+
+func (b *splitBackend) SetServiceID(id *serviceID) {}
+
+func (b *splitBackend) Update(add, remove []*updateAddress) {
+	b.split.mutex.Lock()
+	defer b.split.mutex.Unlock()
+
+	b.exists = true
+	for _, a := range add {
+		b.addresses[addr.ProxyAddressToString(a.address)] = a
+	}
+	for _, r := range remove {
+		delete(b.addresses, addr.ProxyAddressToString(r.address))
+	}
+	b.maybePublish()
+}
+
+func (b *splitBackend) NoEndpoints(exists bool) {
+	b.split.mutex.Lock()
+	defer b.split.mutex.Unlock()
+
+	b.exists = exists
+	b.addresses = make(map[string]*updateAddress)
+	b.maybePublish()
+}
+
+// maybePublish publishes the addresses of the split if the backend is part of
+// it. Backends that are being subscribed to or unsubscribed from aren't. Must
+// be called with the split's mutex held.
+func (b *splitBackend) maybePublish() {
+	if _, ok := b.split.weights[b.id]; !ok {
+		return
+	}
+	if b.split.backends[b.id] != b {
+		return
+	}
+	b.split.publish()
+}
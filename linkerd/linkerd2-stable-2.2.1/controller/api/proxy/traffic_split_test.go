@@ -0,0 +1,176 @@
+package proxy
+
+import (
+	"fmt"
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+func makeService(name string, ips ...string) []string {
+	configs := []string{fmt.Sprintf(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: ns
+spec:
+  type: ClusterIP
+  ports:
+  - port: 8080`, name)}
+
+	addresses := ""
+	for i, ip := range ips {
+		pod := fmt.Sprintf("%s-%d", name, i)
+		addresses += fmt.Sprintf(`
+  - ip: %s
+    targetRef:
+      kind: Pod
+      name: %s
+      namespace: ns`, ip, pod)
+		configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  namespace: ns
+status:
+  phase: Running
+  podIP: %s`, pod, ip))
+	}
+	if len(ips) > 0 {
+		configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: %s
+  namespace: ns
+subsets:
+- addresses:%s
+  ports:
+  - port: 8080`, name, addresses))
+	}
+	return configs
+}
+
+func TestTrafficSplitListener(t *testing.T) {
+	configs := makeService("web", "10.1.1.1")
+	configs = append(configs, makeService("web-v1", "10.1.2.1")...)
+	configs = append(configs, makeService("web-v2", "10.1.3.1", "10.1.3.2")...)
+	k8sAPI, err := k8s.NewFakeAPI("", configs...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	watcher := newEndpointsWatcher(k8sAPI)
+	k8sAPI.Sync()
+
+	resolver := &k8sResolver{}
+	apex := &serviceID{namespace: "ns", name: "web"}
+
+	split := func(weights ...uint32) *sp.ServiceProfile {
+		profile := &sp.ServiceProfile{}
+		for i, weight := range weights {
+			profile.Spec.DstOverrides = append(profile.Spec.DstOverrides, &sp.WeightedDst{
+				Authority: fmt.Sprintf("web-v%d.ns.svc.cluster.local", i+1),
+				Weight:    weight,
+			})
+		}
+		return profile
+	}
+
+	weights := func(addresses []*updateAddress) map[string]uint32 {
+		result := make(map[string]uint32)
+		for _, a := range addresses {
+			result[addr.ProxyAddressToString(a.address)] = a.weight
+		}
+		return result
+	}
+
+	t.Run("Passes on the endpoints of the service when it isn't split", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newTrafficSplitListener(watcher, resolver.localKubernetesServiceIDFromDNSName, apex, 8080, underlying)
+		defer listener.unsubscribeAll()
+
+		listener.Update(nil)
+
+		expected := map[string]uint32{"10.1.1.1:8080": 0}
+		if actual := weights(underlying.added); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("Expected %v to be added, got %v", expected, actual)
+		}
+	})
+
+	t.Run("Weighs the endpoints of the backends of a split", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newTrafficSplitListener(watcher, resolver.localKubernetesServiceIDFromDNSName, apex, 8080, underlying)
+		defer listener.unsubscribeAll()
+
+		listener.Update(split(90, 10))
+
+		expected := map[string]uint32{
+			"10.1.2.1:8080": 90 * splitWeightScale,
+			"10.1.3.1:8080": 10 * splitWeightScale / 2,
+			"10.1.3.2:8080": 10 * splitWeightScale / 2,
+		}
+		if actual := weights(underlying.added); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("Expected %v to be added, got %v", expected, actual)
+		}
+		for _, a := range underlying.added {
+			if a.leafService == "" {
+				t.Fatalf("Expected %s to have a leaf service", a)
+			}
+		}
+		if underlying.noEndpointsCalled {
+			t.Fatal("Expected NoEndpoints not to be called")
+		}
+	})
+
+	t.Run("Moves traffic between backends when the split changes", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newTrafficSplitListener(watcher, resolver.localKubernetesServiceIDFromDNSName, apex, 8080, underlying)
+		defer listener.unsubscribeAll()
+
+		listener.Update(nil)
+		underlying.added = nil
+		listener.Update(split(0, 100))
+
+		expected := map[string]uint32{
+			"10.1.3.1:8080": 100 * splitWeightScale / 2,
+			"10.1.3.2:8080": 100 * splitWeightScale / 2,
+		}
+		if actual := weights(underlying.added); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("Expected %v to be added, got %v", expected, actual)
+		}
+		expectedRemoved := map[string]uint32{"10.1.1.1:8080": 0}
+		if actual := weights(underlying.removed); fmt.Sprint(actual) != fmt.Sprint(expectedRemoved) {
+			t.Fatalf("Expected %v to be removed, got %v", expectedRemoved, actual)
+		}
+
+		state := watcher.getState()
+		if _, ok := state[*apex]; ok {
+			t.Fatal("Expected the apex service to be unsubscribed from")
+		}
+	})
+
+	t.Run("Ignores dstOverrides that aren't local services", func(t *testing.T) {
+		underlying, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newTrafficSplitListener(watcher, resolver.localKubernetesServiceIDFromDNSName, apex, 8080, underlying)
+		defer listener.unsubscribeAll()
+
+		listener.Update(&sp.ServiceProfile{
+			Spec: sp.ServiceProfileSpec{
+				DstOverrides: []*sp.WeightedDst{{Authority: "example.com", Weight: 100}},
+			},
+		})
+
+		expected := map[string]uint32{"10.1.1.1:8080": 0}
+		if actual := weights(underlying.added); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("Expected %v to be added, got %v", expected, actual)
+		}
+	})
+}
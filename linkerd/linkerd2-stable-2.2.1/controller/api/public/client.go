@@ -4,20 +4,29 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
+	tapPb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/tap"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
+	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 )
 
 const (
@@ -40,6 +49,10 @@ type grpcOverHTTPClient struct {
 	serverURL             *url.URL
 	httpClient            *http.Client
 	controlPlaneNamespace string
+
+	// tapClient, if set, serves TapByResource requests instead of the public
+	// API.
+	tapClient tapPb.TapClient
 }
 
 func (c *grpcOverHTTPClient) StatSummary(ctx context.Context, req *pb.StatSummaryRequest, _ ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
@@ -54,6 +67,12 @@ func (c *grpcOverHTTPClient) TopRoutes(ctx context.Context, req *pb.TopRoutesReq
 	return &msg, err
 }
 
+func (c *grpcOverHTTPClient) Edges(ctx context.Context, req *pb.EdgesRequest, _ ...grpc.CallOption) (*pb.EdgesResponse, error) {
+	var msg pb.EdgesResponse
+	err := c.apiRequest(ctx, "Edges", req, &msg)
+	return &msg, err
+}
+
 func (c *grpcOverHTTPClient) Version(ctx context.Context, req *pb.Empty, _ ...grpc.CallOption) (*pb.VersionInfo, error) {
 	var msg pb.VersionInfo
 	err := c.apiRequest(ctx, "Version", req, &msg)
@@ -83,13 +102,17 @@ func (c *grpcOverHTTPClient) Tap(ctx context.Context, req *pb.TapRequest, _ ...g
 }
 
 func (c *grpcOverHTTPClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, _ ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	if c.tapClient != nil {
+		return c.tapClient.TapByResource(ctx, req)
+	}
+
 	url := c.endpointNameToPublicAPIURL("TapByResource")
 	httpRsp, err := c.post(ctx, url, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := checkIfResponseHasError(httpRsp); err != nil {
+	if err := protohttp.CheckIfResponseHasError(httpRsp); err != nil {
 		httpRsp.Body.Close()
 		return nil, err
 	}
@@ -100,7 +123,7 @@ func (c *grpcOverHTTPClient) TapByResource(ctx context.Context, req *pb.TapByRes
 		httpRsp.Body.Close()
 	}()
 
-	return &tapClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+	return &tapClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body), header: util.ResponseMetadata(httpRsp.Header)}, nil
 }
 
 func (c *grpcOverHTTPClient) Endpoints(ctx context.Context, req *discovery.EndpointsParams, _ ...grpc.CallOption) (*discovery.EndpointsResponse, error) {
@@ -109,6 +132,27 @@ func (c *grpcOverHTTPClient) Endpoints(ctx context.Context, req *discovery.Endpo
 	return &msg, err
 }
 
+func (c *grpcOverHTTPClient) WatchEndpoints(ctx context.Context, req *discovery.EndpointsParams, _ ...grpc.CallOption) (discovery.Discovery_WatchEndpointsClient, error) {
+	url := c.endpointNameToPublicAPIURL("WatchEndpoints")
+	httpRsp, err := c.post(ctx, url, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := protohttp.CheckIfResponseHasError(httpRsp); err != nil {
+		httpRsp.Body.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Debug("Closing response body after context marked as done")
+		httpRsp.Body.Close()
+	}()
+
+	return &watchEndpointsClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+}
+
 func (c *grpcOverHTTPClient) apiRequest(ctx context.Context, endpoint string, req proto.Message, protoResponse proto.Message) error {
 	url := c.endpointNameToPublicAPIURL(endpoint)
 
@@ -120,12 +164,12 @@ func (c *grpcOverHTTPClient) apiRequest(ctx context.Context, endpoint string, re
 	defer httpRsp.Body.Close()
 	log.Debugf("gRPC-over-HTTP call returned status [%s] and content length [%d]", httpRsp.Status, httpRsp.ContentLength)
 
-	if err := checkIfResponseHasError(httpRsp); err != nil {
+	if err := protohttp.CheckIfResponseHasError(httpRsp); err != nil {
 		return err
 	}
 
 	reader := bufio.NewReader(httpRsp.Body)
-	return fromByteStreamToProtocolBuffers(reader, protoResponse)
+	return protohttp.FromByteStreamToProtocolBuffers(reader, protoResponse)
 }
 
 func (c *grpcOverHTTPClient) post(ctx context.Context, url *url.URL, req proto.Message) (*http.Response, error) {
@@ -160,37 +204,43 @@ func (c *grpcOverHTTPClient) endpointNameToPublicAPIURL(endpoint string) *url.UR
 type tapClient struct {
 	ctx    context.Context
 	reader *bufio.Reader
+	header metadata.MD
 }
 
 func (c tapClient) Recv() (*pb.TapEvent, error) {
 	var msg pb.TapEvent
-	err := fromByteStreamToProtocolBuffers(c.reader, &msg)
+	err := protohttp.FromByteStreamToProtocolBuffers(c.reader, &msg)
 	return &msg, err
 }
 
 // satisfy the pb.Api_TapClient interface
-func (c tapClient) Header() (metadata.MD, error) { return nil, nil }
+func (c tapClient) Header() (metadata.MD, error) { return c.header, nil }
 func (c tapClient) Trailer() metadata.MD         { return nil }
 func (c tapClient) CloseSend() error             { return nil }
 func (c tapClient) Context() context.Context     { return c.ctx }
 func (c tapClient) SendMsg(interface{}) error    { return nil }
 func (c tapClient) RecvMsg(interface{}) error    { return nil }
 
-func fromByteStreamToProtocolBuffers(byteStreamContainingMessage *bufio.Reader, out proto.Message) error {
-	messageAsBytes, err := deserializePayloadFromReader(byteStreamContainingMessage)
-	if err != nil {
-		return fmt.Errorf("error reading byte stream header: %v", err)
-	}
-
-	err = proto.Unmarshal(messageAsBytes, out)
-	if err != nil {
-		return fmt.Errorf("error unmarshalling array of [%d] bytes error: %v", len(messageAsBytes), err)
-	}
+type watchEndpointsClient struct {
+	ctx    context.Context
+	reader *bufio.Reader
+}
 
-	return nil
+func (c watchEndpointsClient) Recv() (*discovery.EndpointsUpdate, error) {
+	var msg discovery.EndpointsUpdate
+	err := protohttp.FromByteStreamToProtocolBuffers(c.reader, &msg)
+	return &msg, err
 }
 
-func newClient(apiURL *url.URL, httpClientToUse *http.Client, controlPlaneNamespace string) (APIClient, error) {
+// satisfy the discovery.Discovery_WatchEndpointsClient interface
+func (c watchEndpointsClient) Header() (metadata.MD, error) { return nil, nil }
+func (c watchEndpointsClient) Trailer() metadata.MD         { return nil }
+func (c watchEndpointsClient) CloseSend() error             { return nil }
+func (c watchEndpointsClient) Context() context.Context     { return c.ctx }
+func (c watchEndpointsClient) SendMsg(interface{}) error    { return nil }
+func (c watchEndpointsClient) RecvMsg(interface{}) error    { return nil }
+
+func newClient(apiURL *url.URL, httpClientToUse *http.Client, controlPlaneNamespace string) (*grpcOverHTTPClient, error) {
 	if !apiURL.IsAbs() {
 		return nil, fmt.Errorf("server URL must be absolute, was [%s]", apiURL.String())
 	}
@@ -209,12 +259,55 @@ func newClient(apiURL *url.URL, httpClientToUse *http.Client, controlPlaneNamesp
 // NewInternalClient creates a new Public API client intended to run inside a
 // Kubernetes cluster.
 func NewInternalClient(controlPlaneNamespace string, kubeAPIHost string) (APIClient, error) {
-	apiURL, err := url.Parse(fmt.Sprintf("http://%s/", kubeAPIHost))
+	return NewInternalClientWithCA(controlPlaneNamespace, kubeAPIHost, "")
+}
+
+// NewInternalClientWithCA is like NewInternalClient, but the API is served over
+// HTTPS if kubeAPIHost starts with "https://", in which case it's verified with
+// the CA certificates of the caFile PEM file, or the system's if it's empty.
+// Requests go through the proxy set by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables, if any.
+func NewInternalClientWithCA(controlPlaneNamespace, kubeAPIHost, caFile string) (APIClient, error) {
+	if !strings.Contains(kubeAPIHost, "://") {
+		kubeAPIHost = "http://" + kubeAPIHost
+	}
+	apiURL, err := url.Parse(strings.TrimSuffix(kubeAPIHost, "/") + "/")
 	if err != nil {
 		return nil, err
 	}
 
-	return newClient(apiURL, http.DefaultClient, controlPlaneNamespace)
+	transport, err := newInternalTransport(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(apiURL, &http.Client{Transport: transport}, controlPlaneNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newInternalTransport(caFile string) (*http.Transport, error) {
+	// unlike http.ProxyFromEnvironment, NO_PROXY may contain CIDRs
+	transport := utilnet.SetTransportDefaults(&http.Transport{
+		Proxy: utilnet.NewProxierWithNoProxyCIDR(http.ProxyFromEnvironment),
+	})
+
+	if caFile != "" {
+		caPEM, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the certificate authority: %s", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+
+	return transport, nil
 }
 
 // NewExternalClient creates a new Public API client intended to run from
@@ -230,5 +323,28 @@ func NewExternalClient(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI)
 		return nil, err
 	}
 
-	return newClient(apiURL, httpClientToUse, controlPlaneNamespace)
+	client, err := newClient(apiURL, httpClientToUse, controlPlaneNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taps are requested through the tap APIService, so that they are
+	// authenticated and authorized as the user running the CLI. Installs
+	// restricted to some namespaces don't register it, and fall back to the
+	// public API, which taps through the tap gRPC service.
+	exists, err := kubeAPI.APIServiceExists(context.Background(), httpClientToUse, k8s.TapAPIGroup, k8s.TapAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		log.Debugf("tap APIService [%s] is not available, tapping through the public API", k8s.TapAPIService)
+		return client, nil
+	}
+
+	client.tapClient, err = tap.NewAPIClient(kubeAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
 }
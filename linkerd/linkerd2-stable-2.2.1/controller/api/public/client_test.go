@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
 )
 
 type mockTransport struct {
@@ -60,6 +61,35 @@ func TestNewInternalClient(t *testing.T) {
 	})
 }
 
+func TestNewInternalClientWithCA(t *testing.T) {
+	t.Run("Serves over the scheme of the address", func(t *testing.T) {
+		expectations := map[string]string{
+			"some-hostname:8085":          "http://some-hostname:8085/api/v1/",
+			"http://some-hostname:8085":   "http://some-hostname:8085/api/v1/",
+			"https://some-hostname:8085/": "https://some-hostname:8085/api/v1/",
+		}
+
+		for addr, expectedURL := range expectations {
+			client, err := NewInternalClientWithCA("linkerd", addr, "")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actualURL := client.(*grpcOverHTTPClient).serverURL.String()
+			if actualURL != expectedURL {
+				t.Fatalf("Expected server URL [%s] for address [%s], but got [%s]", expectedURL, addr, actualURL)
+			}
+		}
+	})
+
+	t.Run("Returns error if the certificate authority cannot be read", func(t *testing.T) {
+		_, err := NewInternalClientWithCA("linkerd", "https://some-hostname:8085", "/this/doest./not/exist.crt")
+		if err == nil {
+			t.Fatalf("Expecting error when the certificate authority doesnt exist, got nothing")
+		}
+	})
+}
+
 func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 	t.Run("Correctly marshalls an valid object", func(t *testing.T) {
 		versionInfo := pb.VersionInfo{
@@ -71,7 +101,7 @@ func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 		var protobufMessageToBeFilledWithData pb.VersionInfo
 		reader := bufferedReader(t, &versionInfo)
 
-		err := fromByteStreamToProtocolBuffers(reader, &protobufMessageToBeFilledWithData)
+		err := protohttp.FromByteStreamToProtocolBuffers(reader, &protobufMessageToBeFilledWithData)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -114,7 +144,7 @@ func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 		reader := bufferedReader(t, &msg)
 
 		protobufMessageToBeFilledWithData := &pb.StatSummaryResponse{}
-		err := fromByteStreamToProtocolBuffers(reader, protobufMessageToBeFilledWithData)
+		err := protohttp.FromByteStreamToProtocolBuffers(reader, protobufMessageToBeFilledWithData)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -125,7 +155,7 @@ func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 
 		var protobufMessageToBeFilledWithData pb.ApiError
 		reader := bufferedReader(t, &apiError)
-		err := fromByteStreamToProtocolBuffers(reader, &protobufMessageToBeFilledWithData)
+		err := protohttp.FromByteStreamToProtocolBuffers(reader, &protobufMessageToBeFilledWithData)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -147,7 +177,7 @@ func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 		reader := bufferedReader(t, versionInfo)
 
 		protobufMessageToBeFilledWithData := &pb.StatSummaryResponse{}
-		err := fromByteStreamToProtocolBuffers(reader, protobufMessageToBeFilledWithData)
+		err := protohttp.FromByteStreamToProtocolBuffers(reader, protobufMessageToBeFilledWithData)
 		if err == nil {
 			t.Fatal("Expecting error, got nothing")
 		}
@@ -190,7 +220,7 @@ func bufferedReader(t *testing.T, msg proto.Message) *bufio.Reader {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	payload, err := serializeAsPayload(msgBytes)
+	payload, err := protohttp.SerializeAsPayload(msgBytes)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
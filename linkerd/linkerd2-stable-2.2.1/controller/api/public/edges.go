@@ -0,0 +1,181 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgUtil "github.com/linkerd/linkerd2/pkg/util"
+	"github.com/prometheus/common/model"
+)
+
+// edgeKey identifies the requests sent by the proxies of a resource to
+// another resource
+type edgeKey struct {
+	src rKey
+	dst rKey
+}
+
+// Edges returns the edges between the selected resources and the resources
+// they send requests to, or receive requests from. The edges are derived from
+// the outbound requests reported by the proxies of their sources, which are
+// labeled with the resources of their destinations.
+func (s *grpcServer) Edges(ctx context.Context, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	pkgUtil.ContextLogger(ctx).Debugf("Edges request: %+v", req)
+
+	resource := req.GetSelector().GetResource()
+	if resource == nil {
+		return edgesError(req, "Edges request missing Selector Resource"), nil
+	}
+
+	switch resource.GetType() {
+	case k8s.All, k8s.Authority, k8s.Service, k8s.TrafficSplit:
+		return edgesError(req, fmt.Sprintf("the %s resource type is not supported in edges queries", resource.GetType())), nil
+	}
+
+	if err := s.timeWindowBounds.Validate(req.GetTimeWindow()); err != nil {
+		return edgesError(req, err.Error()), nil
+	}
+
+	// the edges from the selected resources, and the edges to them
+	edges := make(map[edgeKey]*pb.BasicStats)
+	for _, dst := range []bool{false, true} {
+		stats, err := s.getEdgeMetrics(ctx, req, edgeQueryLabels(resource, dst))
+		if err != nil {
+			return nil, util.GRPCError(err)
+		}
+		for key, stat := range stats {
+			if _, ok := edges[key]; !ok {
+				edges[key] = stat
+			}
+		}
+	}
+
+	keys := make([]edgeKey, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.src != b.src {
+			return lessKey(a.src, b.src)
+		}
+		return lessKey(a.dst, b.dst)
+	})
+
+	rows := make([]*pb.Edge, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, &pb.Edge{
+			Src:   &pb.Resource{Namespace: key.src.Namespace, Type: key.src.Type, Name: key.src.Name},
+			Dst:   &pb.Resource{Namespace: key.dst.Namespace, Type: key.dst.Type, Name: key.dst.Name},
+			Stats: edges[key],
+		})
+	}
+
+	return &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Ok_{
+			Ok: &pb.EdgesResponse_Ok{
+				Edges: rows,
+			},
+		},
+	}, nil
+}
+
+// getEdgeMetrics returns the stats of the outbound requests matching
+// reqLabels, by the resources of their sources and destinations. Requests to
+// destinations that aren't known to Kubernetes, or not of the type of the
+// selected resources, aren't part of any edge.
+func (s *grpcServer) getEdgeMetrics(ctx context.Context, req *pb.EdgesRequest, reqLabels model.LabelSet) (map[edgeKey]*pb.BasicStats, error) {
+	resource := req.GetSelector().GetResource()
+	resourceLabel := promResourceType(resource)
+
+	groupBy := model.LabelNames{namespaceLabel, dstNamespaceLabel}
+	if resource.GetType() != k8s.Namespace {
+		groupBy = append(groupBy, resourceLabel, "dst_"+resourceLabel)
+	}
+
+	query, err := newPromQuery(reqLabels, req.GetTimeWindow(), groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.getPrometheusMetrics(ctx, responseMetrics, query)
+	if err != nil {
+		return nil, err
+	}
+
+	basicStats := make(map[edgeKey]*pb.BasicStats)
+	for _, result := range results {
+		for _, sample := range result.vec {
+			key := edgeKey{
+				src: metricToResourceKey(resource.GetType(), sample.Metric, namespaceLabel, resourceLabel),
+				dst: metricToResourceKey(resource.GetType(), sample.Metric, dstNamespaceLabel, "dst_"+resourceLabel),
+			}
+			if key.src.Name == "" || key.dst.Name == "" {
+				continue
+			}
+
+			if basicStats[key] == nil {
+				basicStats[key] = &pb.BasicStats{}
+			}
+			addSampleToStats(basicStats[key], result.prom, sample)
+		}
+	}
+	return basicStats, nil
+}
+
+// edgeQueryLabels returns the labels of the outbound requests sent by the
+// selected resources or, if dst is set, of the requests sent to them.
+func edgeQueryLabels(resource *pb.Resource, dst bool) model.LabelSet {
+	prefix := model.LabelName("")
+	if dst {
+		prefix = "dst_"
+	}
+
+	set := promDirectionLabels("outbound")
+	if resource.GetName() != "" {
+		set[prefix+promResourceType(resource)] = model.LabelValue(resource.GetName())
+	}
+	if shouldAddNamespaceLabel(resource) {
+		set[prefix+namespaceLabel] = model.LabelValue(resource.GetNamespace())
+	}
+	return set
+}
+
+// metricToResourceKey returns the key of the source or destination resource of
+// a metric, according to the given labels. Namespaces are keyed by name only,
+// like the namespace rows of StatSummary.
+func metricToResourceKey(resourceType string, metric model.Metric, namespaceLabel, resourceLabel model.LabelName) rKey {
+	if resourceType == k8s.Namespace {
+		return rKey{
+			Type: resourceType,
+			Name: string(metric[namespaceLabel]),
+		}
+	}
+	return rKey{
+		Namespace: string(metric[namespaceLabel]),
+		Type:      resourceType,
+		Name:      string(metric[resourceLabel]),
+	}
+}
+
+func lessKey(a, b rKey) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+func edgesError(req *pb.EdgesRequest, message string) *pb.EdgesResponse {
+	return &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Error{
+			Error: &pb.ResourceError{
+				Resource: req.GetSelector().GetResource(),
+				Error:    message,
+			},
+		},
+	}
+}
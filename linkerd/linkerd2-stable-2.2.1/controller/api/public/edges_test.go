@@ -0,0 +1,136 @@
+package public
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+)
+
+type edgesExpected struct {
+	expectedStatRPC
+	req              pb.EdgesRequest  // the request we would like to test
+	expectedResponse pb.EdgesResponse // the edges response we expect
+}
+
+func genEdgeSample(src, dst string) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"namespace":      "emojivoto",
+			"deployment":     model.LabelValue(src),
+			"dst_namespace":  "emojivoto",
+			"dst_deployment": model.LabelValue(dst),
+			"classification": "success",
+			"tls":            "true",
+		},
+		Value:     123,
+		Timestamp: 456,
+	}
+}
+
+func genEdge(src, dst string) *pb.Edge {
+	return &pb.Edge{
+		Src: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: src},
+		Dst: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: dst},
+		Stats: &pb.BasicStats{
+			SuccessCount:    123,
+			TlsRequestCount: 123,
+			LatencyMsP50:    123,
+			LatencyMsP95:    123,
+			LatencyMsP99:    123,
+		},
+	}
+}
+
+func TestEdges(t *testing.T) {
+	t.Run("Successfully performs an edges query", func(t *testing.T) {
+		expectations := []edgesExpected{
+			{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					mockPromResponse: model.Vector{
+						genEdgeSample("web", "voting"),
+						genEdgeSample("web", "emoji"),
+						// requests to destinations that aren't deployments
+						genEdgeSample("web", ""),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto"}[1m])) by (le, namespace, dst_namespace, deployment, dst_deployment))`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto"}[1m])) by (namespace, dst_namespace, deployment, dst_deployment, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", namespace="emojivoto"}[1m])) by (namespace, dst_namespace, deployment, dst_deployment, classification, tls)`,
+					},
+				},
+				req: pb.EdgesRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Deployment,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: pb.EdgesResponse{
+					Response: &pb.EdgesResponse_Ok_{
+						Ok: &pb.EdgesResponse_Ok{
+							Edges: []*pb.Edge{
+								genEdge("web", "emoji"),
+								genEdge("web", "voting"),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		for _, exp := range expectations {
+			mockProm, fakeGrpcServer, err := newMockGrpcServer(exp.expectedStatRPC)
+			if err != nil {
+				t.Fatalf("Error creating mock grpc server: %s", err)
+			}
+
+			rsp, err := fakeGrpcServer.Edges(context.TODO(), &exp.req)
+			if err != exp.err {
+				t.Fatalf("Expected error: %s, Got: %s", exp.err, err)
+			}
+
+			err = exp.verifyPromQueries(mockProm)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !proto.Equal(rsp, &exp.expectedResponse) {
+				t.Fatalf("Expected: %+v, Got: %+v", &exp.expectedResponse, rsp)
+			}
+		}
+	})
+
+	t.Run("Rejects unsupported resource types", func(t *testing.T) {
+		_, fakeGrpcServer, err := newMockGrpcServer(expectedStatRPC{})
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		for _, resourceType := range []string{pkgK8s.All, pkgK8s.Authority, pkgK8s.Service} {
+			rsp, err := fakeGrpcServer.Edges(context.TODO(), &pb.EdgesRequest{
+				Selector: &pb.ResourceSelection{
+					Resource: &pb.Resource{Namespace: "emojivoto", Type: resourceType},
+				},
+				TimeWindow: "1m",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if rsp.GetError() == nil {
+				t.Fatalf("Expected an error for the %s resource type, got: %+v", resourceType, rsp)
+			}
+		}
+	})
+}
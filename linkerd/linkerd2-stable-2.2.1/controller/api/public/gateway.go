@@ -0,0 +1,421 @@
+package public
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+)
+
+// The REST gateway serves the unary methods of the public API as JSON over
+// plain HTTP, e.g. POST /api/v1/rest/StatSummary, for the clients that don't
+// link the protobuf client. The messages are encoded as documented in the
+// OpenAPI document served at /api/v1/openapi.json, which is generated from the
+// descriptors of the public API, so that it never gets out of sync.
+const (
+	publicProtoFile = "public.proto"
+	apiServiceName  = "Api"
+)
+
+var (
+	restPathPrefix = fullURLPathFor("rest/")
+	openAPIPath    = fullURLPathFor("openapi.json")
+)
+
+// restMethod is a unary method of the public API served by the REST gateway.
+type restMethod struct {
+	newRequest func() proto.Message
+	call       func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error)
+}
+
+var restMethods = map[string]restMethod{
+	"StatSummary": {
+		newRequest: func() proto.Message { return &pb.StatSummaryRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.StatSummary(ctx, req.(*pb.StatSummaryRequest))
+		},
+	},
+	"TopRoutes": {
+		newRequest: func() proto.Message { return &pb.TopRoutesRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.TopRoutes(ctx, req.(*pb.TopRoutesRequest))
+		},
+	},
+	"Edges": {
+		newRequest: func() proto.Message { return &pb.EdgesRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.Edges(ctx, req.(*pb.EdgesRequest))
+		},
+	},
+	"ListPods": {
+		newRequest: func() proto.Message { return &pb.ListPodsRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListPods(ctx, req.(*pb.ListPodsRequest))
+		},
+	},
+	"ListServices": {
+		newRequest: func() proto.Message { return &pb.ListServicesRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListServices(ctx, req.(*pb.ListServicesRequest))
+		},
+	},
+	"Version": {
+		newRequest: func() proto.Message { return &pb.Empty{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.Version(ctx, req.(*pb.Empty))
+		},
+	},
+	"SelfCheck": {
+		newRequest: func() proto.Message { return &healthcheckPb.SelfCheckRequest{} },
+		call: func(s APIServer, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.SelfCheck(ctx, req.(*healthcheckPb.SelfCheckRequest))
+		},
+	},
+}
+
+type restError struct {
+	Error string `json:"error"`
+}
+
+func writeRESTError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(restError{Error: err.Error()})
+}
+
+func (h *handler) handleREST(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeRESTError(w, http.StatusMethodNotAllowed, fmt.Errorf("POST required"))
+		return
+	}
+
+	method, ok := restMethods[strings.TrimPrefix(req.URL.Path, restPathPrefix)]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	protoRequest := method.newRequest()
+	// an empty body is an empty request, e.g. for Version
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		err = jsonpb.Unmarshal(bytes.NewReader(body), protoRequest)
+		if err != nil {
+			writeRESTError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	rsp, err := method.call(h.grpcServer, req.Context(), protoRequest)
+	if err != nil {
+		writeRESTError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = pbMarshaler.Marshal(w, rsp)
+	if err != nil {
+		log.Errorf("Failed to write the %s response: %s", req.URL.Path, err)
+	}
+}
+
+func (h *handler) handleOpenAPI(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeRESTError(w, http.StatusMethodNotAllowed, fmt.Errorf("GET required"))
+		return
+	}
+
+	doc, err := buildOpenAPIDocument()
+	if err != nil {
+		writeRESTError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
+
+// openAPISchema is the subset of the OpenAPI 2.0 schema object that's needed
+// to describe the messages of the public API.
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string         `json:"description"`
+	Schema      *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []openAPIParameter         `json:"parameters"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+}
+
+type openAPIDocument struct {
+	Swagger     string                                  `json:"swagger"`
+	Info        map[string]string                       `json:"info"`
+	BasePath    string                                  `json:"basePath"`
+	Consumes    []string                                `json:"consumes"`
+	Produces    []string                                `json:"produces"`
+	Paths       map[string]map[string]*openAPIOperation `json:"paths"`
+	Definitions map[string]*openAPISchema               `json:"definitions"`
+}
+
+// buildOpenAPIDocument generates the OpenAPI document of the REST gateway
+// from the descriptor of the public API and of the files it imports.
+func buildOpenAPIDocument() (*openAPIDocument, error) {
+	files, err := loadFileDescriptors(publicProtoFile)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &openAPIDocument{
+		Swagger:     "2.0",
+		Info:        map[string]string{"title": "Linkerd public API", "version": apiVersion},
+		BasePath:    "/",
+		Consumes:    []string{"application/json"},
+		Produces:    []string{"application/json"},
+		Paths:       make(map[string]map[string]*openAPIOperation),
+		Definitions: make(map[string]*openAPISchema),
+	}
+
+	mapEntries := make(map[string]*descriptor.DescriptorProto)
+	for _, file := range files {
+		prefix := "." + file.GetPackage()
+		for _, msg := range file.GetMessageType() {
+			collectMapEntries(prefix, msg, mapEntries)
+		}
+	}
+	for _, file := range files {
+		prefix := "." + file.GetPackage()
+		for _, msg := range file.GetMessageType() {
+			addMessageDefinitions(doc.Definitions, prefix, msg, mapEntries)
+		}
+		for _, enum := range file.GetEnumType() {
+			doc.Definitions[definitionName(prefix+"."+enum.GetName())] = enumSchema(enum)
+		}
+	}
+
+	for _, svc := range files[0].GetService() {
+		if svc.GetName() != apiServiceName {
+			continue
+		}
+		for _, m := range svc.GetMethod() {
+			if _, ok := restMethods[m.GetName()]; !ok || m.GetServerStreaming() || m.GetClientStreaming() {
+				continue
+			}
+			doc.Paths[restPathPrefix+m.GetName()] = map[string]*openAPIOperation{
+				"post": {
+					OperationID: m.GetName(),
+					Tags:        []string{apiServiceName},
+					Parameters: []openAPIParameter{
+						{Name: "body", In: "body", Required: true, Schema: &openAPISchema{Ref: definitionRef(m.GetInputType())}},
+					},
+					Responses: map[string]openAPIResponse{
+						"200": {Description: "A successful response.", Schema: &openAPISchema{Ref: definitionRef(m.GetOutputType())}},
+						"400": {Description: "The request couldn't be decoded."},
+						"500": {Description: "The request failed."},
+					},
+					Deprecated: m.GetOptions().GetDeprecated(),
+				},
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// loadFileDescriptors returns the descriptor of a registered proto file,
+// followed by the descriptors of the files it imports, except for the
+// well-known types.
+func loadFileDescriptors(name string) ([]*descriptor.FileDescriptorProto, error) {
+	gz := proto.FileDescriptor(name)
+	if gz == nil {
+		return nil, fmt.Errorf("no descriptor registered for %s", name)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	file := &descriptor.FileDescriptorProto{}
+	err = proto.Unmarshal(b, file)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*descriptor.FileDescriptorProto{file}
+	for _, dep := range file.GetDependency() {
+		if strings.HasPrefix(dep, "google/protobuf/") {
+			continue
+		}
+		deps, err := loadFileDescriptors(dep)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, deps...)
+	}
+	return files, nil
+}
+
+func collectMapEntries(prefix string, msg *descriptor.DescriptorProto, entries map[string]*descriptor.DescriptorProto) {
+	name := prefix + "." + msg.GetName()
+	if msg.GetOptions().GetMapEntry() {
+		entries[name] = msg
+	}
+	for _, nested := range msg.GetNestedType() {
+		collectMapEntries(name, nested, entries)
+	}
+}
+
+func addMessageDefinitions(definitions map[string]*openAPISchema, prefix string, msg *descriptor.DescriptorProto, mapEntries map[string]*descriptor.DescriptorProto) {
+	name := prefix + "." + msg.GetName()
+	if msg.GetOptions().GetMapEntry() {
+		return
+	}
+
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for _, field := range msg.GetField() {
+		schema.Properties[jsonFieldName(field)] = fieldSchema(field, mapEntries)
+	}
+	definitions[definitionName(name)] = schema
+
+	for _, nested := range msg.GetNestedType() {
+		addMessageDefinitions(definitions, name, nested, mapEntries)
+	}
+	for _, enum := range msg.GetEnumType() {
+		definitions[definitionName(name+"."+enum.GetName())] = enumSchema(enum)
+	}
+}
+
+// fieldSchema returns the schema of a field, as encoded by jsonpb.
+func fieldSchema(field *descriptor.FieldDescriptorProto, mapEntries map[string]*descriptor.DescriptorProto) *openAPISchema {
+	if entry, ok := mapEntries[field.GetTypeName()]; ok {
+		return &openAPISchema{
+			Type:                 "object",
+			AdditionalProperties: fieldSchema(entry.GetField()[1], mapEntries),
+		}
+	}
+
+	var schema *openAPISchema
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		schema = &openAPISchema{Type: "number", Format: "double"}
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		schema = &openAPISchema{Type: "number", Format: "float"}
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		schema = &openAPISchema{Type: "integer", Format: "int32"}
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		schema = &openAPISchema{Type: "integer", Format: "int64"}
+	// jsonpb encodes 64-bit integers as strings
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		schema = &openAPISchema{Type: "string", Format: "int64"}
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		schema = &openAPISchema{Type: "string", Format: "uint64"}
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		schema = &openAPISchema{Type: "boolean"}
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		schema = &openAPISchema{Type: "string"}
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		schema = &openAPISchema{Type: "string", Format: "byte"}
+	case descriptor.FieldDescriptorProto_TYPE_ENUM,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		switch field.GetTypeName() {
+		case ".google.protobuf.Duration":
+			// e.g. "1.5s"
+			schema = &openAPISchema{Type: "string"}
+		default:
+			schema = &openAPISchema{Ref: definitionRef(field.GetTypeName())}
+		}
+	default:
+		schema = &openAPISchema{Type: "string"}
+	}
+
+	if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return &openAPISchema{Type: "array", Items: schema}
+	}
+	return schema
+}
+
+func enumSchema(enum *descriptor.EnumDescriptorProto) *openAPISchema {
+	values := make([]string, 0, len(enum.GetValue()))
+	for _, value := range enum.GetValue() {
+		values = append(values, value.GetName())
+	}
+	return &openAPISchema{Type: "string", Enum: values}
+}
+
+// jsonFieldName returns the name of a field in the JSON encoding of its
+// message, which is its lowerCamelCase name.
+func jsonFieldName(field *descriptor.FieldDescriptorProto) string {
+	if field.GetJsonName() != "" {
+		return field.GetJsonName()
+	}
+
+	var name []byte
+	upper := false
+	for i := 0; i < len(field.GetName()); i++ {
+		c := field.GetName()[i]
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			name = append(name, c-'a'+'A')
+			upper = false
+		default:
+			name = append(name, c)
+			upper = false
+		}
+	}
+	return string(name)
+}
+
+func definitionName(typeName string) string {
+	return strings.TrimPrefix(typeName, ".")
+}
+
+func definitionRef(typeName string) string {
+	return "#/definitions/" + definitionName(typeName)
+}
@@ -0,0 +1,110 @@
+package public
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestREST(t *testing.T) {
+	mockGrpcServer := &mockGrpcServer{}
+	handler := &handler{grpcServer: mockGrpcServer}
+
+	t.Run("Delegates JSON requests to the underlying grpc server", func(t *testing.T) {
+		mockGrpcServer.ErrorToReturn = nil
+		mockGrpcServer.ResponseToReturn = &pb.ListPodsResponse{
+			Pods: []*pb.Pod{{Name: "emojivoto/web-1", Status: "Running"}},
+		}
+
+		body := `{"namespace": "emojivoto"}`
+		req := httptest.NewRequest(http.MethodPost, restPathPrefix+"ListPods", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		expectedRequest := &pb.ListPodsRequest{Namespace: "emojivoto"}
+		if !proto.Equal(mockGrpcServer.LastRequestReceived, expectedRequest) {
+			t.Fatalf("Expecting server call to receive [%v], but got [%v]", expectedRequest, mockGrpcServer.LastRequestReceived)
+		}
+		var rsp pb.ListPodsResponse
+		if err := jsonpb.Unmarshal(rec.Body, &rsp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !proto.Equal(&rsp, mockGrpcServer.ResponseToReturn) {
+			t.Fatalf("Expecting response [%v], but got [%v]", mockGrpcServer.ResponseToReturn, &rsp)
+		}
+	})
+
+	t.Run("Rejects invalid requests", func(t *testing.T) {
+		testCases := []struct {
+			method string
+			path   string
+			body   string
+			status int
+		}{
+			{http.MethodGet, restPathPrefix + "ListPods", "", http.StatusMethodNotAllowed},
+			{http.MethodPost, restPathPrefix + "ListPods", `{"namespace": 1}`, http.StatusBadRequest},
+			{http.MethodPost, restPathPrefix + "TapByResource", "", http.StatusNotFound},
+		}
+
+		for i, tc := range testCases {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("test case %d: expected status %d, got %d", i, tc.status, rec.Code)
+			}
+		}
+	})
+
+	t.Run("Serves the OpenAPI document", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, openAPIPath, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var doc openAPIDocument
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for method := range restMethods {
+			if _, ok := doc.Paths[restPathPrefix+method]["post"]; !ok {
+				t.Fatalf("Expected the %s method to be documented", method)
+			}
+		}
+		if len(doc.Paths) != len(restMethods) {
+			t.Fatalf("Expected %d paths, got %d", len(restMethods), len(doc.Paths))
+		}
+
+		for _, name := range []string{
+			"linkerd2.public.StatSummaryRequest",
+			"linkerd2.public.BasicStats",
+			"linkerd2.common.healthcheck.SelfCheckResponse",
+		} {
+			if _, ok := doc.Definitions[name]; !ok {
+				t.Fatalf("Expected the %s message to be defined", name)
+			}
+		}
+
+		successCount := doc.Definitions["linkerd2.public.BasicStats"].Properties["successCount"]
+		if successCount == nil || successCount.Type != "string" {
+			t.Fatalf("Expected successCount to be encoded as a string, got %+v", successCount)
+		}
+		uptime := doc.Definitions["linkerd2.public.Pod"].Properties["uptime"]
+		if uptime == nil || uptime.Type != "string" {
+			t.Fatalf("Expected uptime to be encoded as a string, got %+v", uptime)
+		}
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/prometheus"
 	"github.com/linkerd/linkerd2/pkg/version"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -39,6 +40,17 @@ type grpcServer struct {
 	controllerNamespace string
 	ignoredNamespaces   []string
 	singleNamespace     bool
+	timeWindowBounds    util.TimeWindowBounds
+
+	// longWindowPrometheusAPI, if set, serves the queries over longer time
+	// windows than longWindowThreshold, the retention of prometheusAPI
+	longWindowPrometheusAPI promv1.API
+	longWindowThreshold     time.Duration
+
+	// recordingRules is set if Prometheus records the series of the
+	// recordedMetrics, which are queried instead of the raw metrics when
+	// possible
+	recordingRules bool
 }
 
 type podReport struct {
@@ -47,7 +59,7 @@ type podReport struct {
 }
 
 const (
-	podQuery                   = "max(process_start_time_seconds{%s}) by (pod, namespace)"
+	podQuery                   = "max(process_start_time_seconds%s) by (pod, namespace)"
 	k8sClientSubsystemName     = "kubernetes"
 	k8sClientCheckDescription  = "control plane can talk to Kubernetes"
 	promClientSubsystemName    = "prometheus"
@@ -62,6 +74,7 @@ func newGrpcServer(
 	controllerNamespace string,
 	ignoredNamespaces []string,
 	singleNamespace bool,
+	timeWindowBounds util.TimeWindowBounds,
 ) *grpcServer {
 
 	grpcServer := &grpcServer{
@@ -72,6 +85,7 @@ func newGrpcServer(
 		controllerNamespace: controllerNamespace,
 		ignoredNamespaces:   ignoredNamespaces,
 		singleNamespace:     singleNamespace,
+		timeWindowBounds:    timeWindowBounds,
 	}
 
 	pb.RegisterApiServer(prometheus.NewGrpcServer(), grpcServer)
@@ -105,7 +119,6 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 		}
 	}
 
-	nsQuery := ""
 	namespace := ""
 	if req.GetNamespace() != "" {
 		namespace = req.GetNamespace()
@@ -114,13 +127,14 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 	} else if targetOwner.GetType() == pkgK8s.Namespace {
 		namespace = targetOwner.GetName()
 	}
+	nsLabels := model.LabelSet{}
 	if namespace != "" {
-		nsQuery = fmt.Sprintf("namespace=\"%s\"", namespace)
+		nsLabels[namespaceLabel] = model.LabelValue(namespace)
 	}
-	processStartTimeQuery := fmt.Sprintf(podQuery, nsQuery)
+	processStartTimeQuery := fmt.Sprintf(podQuery, nsLabels)
 
 	// Query Prometheus for all pods present
-	vec, err := s.queryProm(ctx, processStartTimeQuery)
+	vec, err := s.queryProm(ctx, s.prometheusAPI, processStartTimeQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +222,7 @@ func (s *grpcServer) SelfCheck(ctx context.Context, in *healthcheckPb.SelfCheckR
 		CheckDescription: promClientCheckDescription,
 		Status:           healthcheckPb.CheckStatus_OK,
 	}
-	_, err = s.queryProm(ctx, fmt.Sprintf(podQuery, ""))
+	_, err = s.queryProm(ctx, s.prometheusAPI, fmt.Sprintf(podQuery, model.LabelSet{}))
 	if err != nil {
 		promClientCheck.Status = healthcheckPb.CheckStatus_ERROR
 		promClientCheck.FriendlyMessageToUser = fmt.Sprintf("Error calling Prometheus from the control plane: %s", err)
@@ -229,22 +243,23 @@ func (s *grpcServer) Tap(req *pb.TapRequest, stream pb.Api_TapServer) error {
 
 // Pass through to tap service
 func (s *grpcServer) TapByResource(req *pb.TapByResourceRequest, stream pb.Api_TapByResourceServer) error {
-	tapStream := stream.(tapServer)
-	tapClient, err := s.tapClient.TapByResource(tapStream.Context(), req)
+	tapClient, err := s.tapClient.TapByResource(stream.Context(), req)
 	if err != nil {
 		log.Errorf("Unexpected error tapping [%v]: %v", req, err)
 		return err
 	}
 	for {
 		select {
-		case <-tapStream.Context().Done():
+		case <-stream.Context().Done():
 			return nil
 		default:
 			event, err := tapClient.Recv()
 			if err != nil {
 				return err
 			}
-			tapStream.Send(event)
+			if err := stream.Send(event); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -288,3 +303,26 @@ func (s *grpcServer) Endpoints(ctx context.Context, params *discovery.EndpointsP
 
 	return rsp, nil
 }
+
+func (s *grpcServer) WatchEndpoints(params *discovery.EndpointsParams, stream discovery.Discovery_WatchEndpointsServer) error {
+	log.Debugf("WatchEndpoints request %+v", params)
+
+	updates, err := s.discoveryClient.WatchEndpoints(stream.Context(), params)
+	if err != nil {
+		log.Errorf("endpoints watch request to proxy API failed: %s", err)
+		return err
+	}
+	for {
+		update, err := updates.Recv()
+		if err != nil {
+			if stream.Context().Err() != nil {
+				return nil
+			}
+			return err
+		}
+		err = stream.Send(update)
+		if err != nil {
+			return err
+		}
+	}
+}
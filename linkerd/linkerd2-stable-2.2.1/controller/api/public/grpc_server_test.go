@@ -11,6 +11,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/linkerd/linkerd2/controller/api/proxy"
+	"github.com/linkerd/linkerd2/controller/api/util"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -408,6 +409,7 @@ status:
 				"linkerd",
 				[]string{},
 				false,
+				util.DefaultTimeWindowBounds,
 			)
 
 			k8sAPI.Sync()
@@ -510,6 +512,7 @@ metadata:
 				"linkerd",
 				[]string{},
 				false,
+				util.DefaultTimeWindowBounds,
 			)
 
 			k8sAPI.Sync()
@@ -561,6 +564,7 @@ func TestEndpoints(t *testing.T) {
 				"linkerd",
 				[]string{},
 				false,
+				util.DefaultTimeWindowBounds,
 			)
 
 			rsp, err := fakeGrpcServer.Endpoints(context.TODO(), exp.req)
@@ -1,31 +1,46 @@
 package public
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/gorilla/websocket"
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	discoveryPb "github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	tapPb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
+	"github.com/linkerd/linkerd2/pkg/util"
 	promApi "github.com/prometheus/client_golang/api"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/plugin/ochttp"
 	"google.golang.org/grpc/metadata"
 )
 
 var (
-	statSummaryPath   = fullURLPathFor("StatSummary")
-	topRoutesPath     = fullURLPathFor("TopRoutes")
-	versionPath       = fullURLPathFor("Version")
-	listPodsPath      = fullURLPathFor("ListPods")
-	listServicesPath  = fullURLPathFor("ListServices")
-	tapByResourcePath = fullURLPathFor("TapByResource")
-	selfCheckPath     = fullURLPathFor("SelfCheck")
-	endpointsPath     = fullURLPathFor("Endpoints")
+	statSummaryPath     = fullURLPathFor("StatSummary")
+	topRoutesPath       = fullURLPathFor("TopRoutes")
+	edgesPath           = fullURLPathFor("Edges")
+	versionPath         = fullURLPathFor("Version")
+	listPodsPath        = fullURLPathFor("ListPods")
+	listServicesPath    = fullURLPathFor("ListServices")
+	tapByResourcePath   = fullURLPathFor("TapByResource")
+	tapByResourceWSPath = fullURLPathFor("TapByResourceWebsocket")
+	selfCheckPath       = fullURLPathFor("SelfCheck")
+	endpointsPath       = fullURLPathFor("Endpoints")
+	watchEndpointsPath  = fullURLPathFor("WatchEndpoints")
+
+	pbMarshaler       = jsonpb.Marshaler{EmitDefaults: true}
+	websocketUpgrader = websocket.Upgrader{}
 )
 
 type handler struct {
@@ -33,12 +48,27 @@ type handler struct {
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	log.WithFields(log.Fields{
+	util.RequestLogger(req).WithFields(log.Fields{
 		"req.Method": req.Method, "req.URL": req.URL, "req.Form": req.Form,
 	}).Debugf("Serving %s %s", req.Method, req.URL.Path)
+	// Websocket handshakes are GET requests
+	if req.URL.Path == tapByResourceWSPath {
+		h.handleTapByResourceWebsocket(w, req)
+		return
+	}
+	// The REST gateway replies with JSON errors
+	if strings.HasPrefix(req.URL.Path, restPathPrefix) {
+		h.handleREST(w, req)
+		return
+	}
+	if req.URL.Path == openAPIPath {
+		h.handleOpenAPI(w, req)
+		return
+	}
+
 	// Validate request method
 	if req.Method != http.MethodPost {
-		writeErrorToHTTPResponse(w, fmt.Errorf("POST required"))
+		protohttp.WriteErrorToHTTPResponse(w, fmt.Errorf("POST required"))
 		return
 	}
 
@@ -48,6 +78,8 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.handleStatSummary(w, req)
 	case topRoutesPath:
 		h.handleTopRoutes(w, req)
+	case edgesPath:
+		h.handleEdges(w, req)
 	case versionPath:
 		h.handleVersion(w, req)
 	case listPodsPath:
@@ -60,6 +92,8 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.handleSelfCheck(w, req)
 	case endpointsPath:
 		h.handleEndpoints(w, req)
+	case watchEndpointsPath:
+		h.handleWatchEndpoints(w, req)
 	default:
 		http.NotFound(w, req)
 	}
@@ -69,20 +103,20 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 func (h *handler) handleStatSummary(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.StatSummaryRequest
 
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.StatSummary(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
@@ -90,83 +124,104 @@ func (h *handler) handleStatSummary(w http.ResponseWriter, req *http.Request) {
 func (h *handler) handleTopRoutes(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.TopRoutesRequest
 
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.TopRoutes(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
+func (h *handler) handleEdges(w http.ResponseWriter, req *http.Request) {
+	var protoRequest pb.EdgesRequest
+
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+
+	rsp, err := h.grpcServer.Edges(req.Context(), &protoRequest)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
 func (h *handler) handleVersion(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.Empty
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.Version(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
 func (h *handler) handleSelfCheck(w http.ResponseWriter, req *http.Request) {
 	var protoRequest healthcheckPb.SelfCheckRequest
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.SelfCheck(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
 func (h *handler) handleListPods(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.ListPodsRequest
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.ListPods(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
@@ -174,56 +229,56 @@ func (h *handler) handleListPods(w http.ResponseWriter, req *http.Request) {
 func (h *handler) handleListServices(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.ListServicesRequest
 
-	err := httpRequestToProto(req, &protoRequest)
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	rsp, err := h.grpcServer.ListServices(req.Context(), &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
-	err = writeProtoToHTTPResponse(w, rsp)
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
 func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request) {
-	flushableWriter, err := newStreamingWriter(w)
+	flushableWriter, err := protohttp.NewStreamingWriter(w)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	var protoRequest pb.TapByResourceRequest
-	err = httpRequestToProto(req, &protoRequest)
+	err = protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 
 	server := tapServer{w: flushableWriter, req: req}
 	err = h.grpcServer.TapByResource(&protoRequest, server)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
 type tapServer struct {
-	w   flushableResponseWriter
+	w   protohttp.FlushableResponseWriter
 	req *http.Request
 }
 
 func (s tapServer) Send(msg *pb.TapEvent) error {
-	err := writeProtoToHTTPResponse(s.w, msg)
+	err := protohttp.WriteProtoToHTTPResponse(s.w, msg)
 	if err != nil {
-		writeErrorToHTTPResponse(s.w, err)
+		protohttp.WriteErrorToHTTPResponse(s.w, err)
 		return err
 	}
 
@@ -239,47 +294,197 @@ func (s tapServer) Context() context.Context     { return s.req.Context() }
 func (s tapServer) SendMsg(interface{}) error    { return nil }
 func (s tapServer) RecvMsg(interface{}) error    { return nil }
 
+// handleTapByResourceWebsocket bridges the TapByResource stream to a
+// websocket, for clients that can't decode the streaming protobuf encoding,
+// e.g. browsers. The first message of the client is the TapByResourceRequest,
+// JSON-encoded, and each tap event is then sent as a JSON-encoded text
+// message. The tap stops when the client closes the websocket.
+func (h *handler) handleTapByResourceWebsocket(w http.ResponseWriter, req *http.Request) {
+	ws, err := websocketUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// the upgrader has already replied with an HTTP error
+		log.Debugf("Websocket upgrade failed: %s", err)
+		return
+	}
+	defer ws.Close()
+
+	messageType, message, err := ws.ReadMessage()
+	if err != nil {
+		websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+		return
+	}
+	if messageType != websocket.TextMessage {
+		websocketError(ws, websocket.CloseUnsupportedData, "MessageType not supported")
+		return
+	}
+
+	var protoRequest pb.TapByResourceRequest
+	err = jsonpb.Unmarshal(bytes.NewReader(message), &protoRequest)
+	if err != nil {
+		websocketError(ws, websocket.CloseUnsupportedData, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	// the websocket has to be read for close frames to be processed
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Errorf("Unexpected close error: %s", err)
+				}
+				return
+			}
+		}
+	}()
+
+	err = h.grpcServer.TapByResource(&protoRequest, websocketTapServer{ws: ws, ctx: ctx})
+	if err != nil {
+		websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+		return
+	}
+	websocketError(ws, websocket.CloseNormalClosure, "")
+}
+
+func websocketError(ws *websocket.Conn, wsError int, msg string) {
+	ws.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(wsError, msg),
+		time.Time{})
+}
+
+type websocketTapServer struct {
+	ws  *websocket.Conn
+	ctx context.Context
+}
+
+func (s websocketTapServer) Send(msg *pb.TapEvent) error {
+	buf := new(bytes.Buffer)
+	err := pbMarshaler.Marshal(buf, msg)
+	if err != nil {
+		return err
+	}
+	return s.ws.WriteMessage(websocket.TextMessage, buf.Bytes())
+}
+
+// satisfy the pb.Api_TapByResourceServer interface
+func (s websocketTapServer) SetHeader(metadata.MD) error  { return nil }
+func (s websocketTapServer) SendHeader(metadata.MD) error { return nil }
+func (s websocketTapServer) SetTrailer(metadata.MD)       {}
+func (s websocketTapServer) Context() context.Context     { return s.ctx }
+func (s websocketTapServer) SendMsg(interface{}) error    { return nil }
+func (s websocketTapServer) RecvMsg(interface{}) error    { return nil }
+
 func fullURLPathFor(method string) string {
 	return apiRoot + apiPrefix + method
 }
 
 func (h *handler) handleEndpoints(w http.ResponseWriter, req *http.Request) {
-	rsp, err := h.grpcServer.Endpoints(req.Context(), &discoveryPb.EndpointsParams{})
+	var protoRequest discoveryPb.EndpointsParams
+
+	err := protohttp.HTTPRequestToProto(req, &protoRequest)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+
+	rsp, err := h.grpcServer.Endpoints(req.Context(), &protoRequest)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+	err = protohttp.WriteProtoToHTTPResponse(w, rsp)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
+func (h *handler) handleWatchEndpoints(w http.ResponseWriter, req *http.Request) {
+	flushableWriter, err := protohttp.NewStreamingWriter(w)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+
+	var protoRequest discoveryPb.EndpointsParams
+	err = protohttp.HTTPRequestToProto(req, &protoRequest)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
-	err = writeProtoToHTTPResponse(w, rsp)
+
+	server := watchEndpointsServer{w: flushableWriter, req: req}
+	err = h.grpcServer.WatchEndpoints(&protoRequest, server)
 	if err != nil {
-		writeErrorToHTTPResponse(w, err)
+		protohttp.WriteErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
-// NewServer creates a Public API HTTP server.
+type watchEndpointsServer struct {
+	w   protohttp.FlushableResponseWriter
+	req *http.Request
+}
+
+func (s watchEndpointsServer) Send(msg *discoveryPb.EndpointsUpdate) error {
+	err := protohttp.WriteProtoToHTTPResponse(s.w, msg)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(s.w, err)
+		return err
+	}
+
+	s.w.Flush()
+	return nil
+}
+
+// satisfy the discovery.Discovery_WatchEndpointsServer interface
+func (s watchEndpointsServer) SetHeader(metadata.MD) error  { return nil }
+func (s watchEndpointsServer) SendHeader(metadata.MD) error { return nil }
+func (s watchEndpointsServer) SetTrailer(metadata.MD)       {}
+func (s watchEndpointsServer) Context() context.Context     { return s.req.Context() }
+func (s watchEndpointsServer) SendMsg(interface{}) error    { return nil }
+func (s watchEndpointsServer) RecvMsg(interface{}) error    { return nil }
+
+// NewServer creates a Public API HTTP server. If longWindowPrometheusClient
+// isn't nil, the metrics queries over longer time windows than
+// prometheusRetention are sent to it instead of prometheusClient. If
+// recordingRules is set, the series pre-aggregated by the recording rules of
+// the install are queried instead of the raw metrics when possible.
 func NewServer(
 	addr string,
 	prometheusClient promApi.Client,
+	longWindowPrometheusClient promApi.Client,
+	prometheusRetention time.Duration,
+	recordingRules bool,
 	tapClient tapPb.TapClient,
 	discoveryClient discoveryPb.DiscoveryClient,
 	k8sAPI *k8s.API,
 	controllerNamespace string,
 	ignoredNamespaces []string,
 	singleNamespace bool,
+	timeWindowBounds apiUtil.TimeWindowBounds,
 ) *http.Server {
-	baseHandler := &handler{
-		grpcServer: newGrpcServer(
-			promv1.NewAPI(prometheusClient),
-			tapClient,
-			discoveryClient,
-			k8sAPI,
-			controllerNamespace,
-			ignoredNamespaces,
-			singleNamespace,
-		),
-	}
-
-	instrumentedHandler := prometheus.WithTelemetry(baseHandler)
+	grpcServer := newGrpcServer(
+		promv1.NewAPI(prometheusClient),
+		tapClient,
+		discoveryClient,
+		k8sAPI,
+		controllerNamespace,
+		ignoredNamespaces,
+		singleNamespace,
+		timeWindowBounds,
+	)
+	if longWindowPrometheusClient != nil {
+		grpcServer.longWindowPrometheusAPI = promv1.NewAPI(longWindowPrometheusClient)
+		grpcServer.longWindowThreshold = prometheusRetention
+	}
+	grpcServer.recordingRules = recordingRules
+	baseHandler := &handler{grpcServer: grpcServer}
+
+	instrumentedHandler := prometheus.WithTelemetry(util.WithRequestID(&ochttp.Handler{Handler: baseHandler}))
 
 	return &http.Server{
 		Addr:    addr,
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
 	healcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -22,7 +24,8 @@ type mockServer struct {
 
 type mockGrpcServer struct {
 	mockServer
-	TapStreamsToReturn []*pb.TapEvent
+	TapStreamsToReturn       []*pb.TapEvent
+	EndpointsUpdatesToReturn []*discovery.EndpointsUpdate
 }
 
 func (m *mockGrpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
@@ -35,6 +38,11 @@ func (m *mockGrpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest
 	return m.ResponseToReturn.(*pb.TopRoutesResponse), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) Edges(ctx context.Context, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	m.LastRequestReceived = req
+	return m.ResponseToReturn.(*pb.EdgesResponse), m.ErrorToReturn
+}
+
 func (m *mockGrpcServer) Version(ctx context.Context, req *pb.Empty) (*pb.VersionInfo, error) {
 	m.LastRequestReceived = req
 	return m.ResponseToReturn.(*pb.VersionInfo), m.ErrorToReturn
@@ -82,6 +90,17 @@ func (m *mockGrpcServer) Endpoints(ctx context.Context, req *discovery.Endpoints
 	return m.ResponseToReturn.(*discovery.EndpointsResponse), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) WatchEndpoints(req *discovery.EndpointsParams, stream discovery.Discovery_WatchEndpointsServer) error {
+	m.LastRequestReceived = req
+	if m.ErrorToReturn == nil {
+		for _, msg := range m.EndpointsUpdatesToReturn {
+			stream.Send(msg)
+		}
+	}
+
+	return m.ErrorToReturn
+}
+
 type grpcCallTestCase struct {
 	expectedRequest  proto.Message
 	expectedResponse proto.Message
@@ -214,6 +233,135 @@ func TestServer(t *testing.T) {
 		}
 	})
 
+	t.Run("Delegates all streaming endpoints RPC messages to the underlying grpc server", func(t *testing.T) {
+		mockGrpcServer := &mockGrpcServer{}
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("Could not start listener: %v", err)
+		}
+
+		go func() {
+			handler := &handler{
+				grpcServer: mockGrpcServer,
+			}
+			err := http.Serve(listener, handler)
+			if err != nil {
+				t.Fatalf("Could not start server: %v", err)
+			}
+		}()
+
+		client, err := NewInternalClient("linkerd", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedUpdates := []*discovery.EndpointsUpdate{
+			{
+				Service: "emoji.emojivoto",
+				Port:    8080,
+				Add:     []*discovery.PodAddress{{Pod: &pb.Pod{Name: "emoji-1"}}},
+			}, {
+				Service: "emoji.emojivoto",
+				Port:    8080,
+				Remove:  []*discovery.PodAddress{{Pod: &pb.Pod{Name: "emoji-1"}}},
+			},
+		}
+		mockGrpcServer.EndpointsUpdatesToReturn = expectedUpdates
+		mockGrpcServer.ErrorToReturn = nil
+
+		updates, err := client.WatchEndpoints(context.TODO(), &discovery.EndpointsParams{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, expectedUpdate := range expectedUpdates {
+			actualUpdate, err := updates.Recv()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !proto.Equal(actualUpdate, expectedUpdate) {
+				t.Fatalf("Expecting endpoints update to be [%v], but was [%v]", expectedUpdate, actualUpdate)
+			}
+		}
+	})
+
+	t.Run("Bridges streaming tap RPC messages to a websocket", func(t *testing.T) {
+		mockGrpcServer := &mockGrpcServer{}
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("Could not start listener: %v", err)
+		}
+
+		go func() {
+			handler := &handler{
+				grpcServer: mockGrpcServer,
+			}
+			err := http.Serve(listener, handler)
+			if err != nil {
+				t.Fatalf("Could not start server: %v", err)
+			}
+		}()
+
+		expectedTapResponses := []*pb.TapEvent{
+			{
+				Destination: &pb.TcpAddress{
+					Port: 9999,
+				},
+			}, {
+				Destination: &pb.TcpAddress{
+					Port: 2102,
+				},
+			},
+		}
+		mockGrpcServer.TapStreamsToReturn = expectedTapResponses
+		mockGrpcServer.ErrorToReturn = nil
+
+		ws, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+tapByResourceWSPath, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer ws.Close()
+
+		err = ws.WriteMessage(websocket.TextMessage, []byte(`{"target": {"resource": {"namespace": "emojivoto", "type": "deployment"}}, "maxRps": 1}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, expectedTapEvent := range expectedTapResponses {
+			_, message, err := ws.ReadMessage()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var actualTapEvent pb.TapEvent
+			err = jsonpb.UnmarshalString(string(message), &actualTapEvent)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !proto.Equal(&actualTapEvent, expectedTapEvent) {
+				t.Fatalf("Expecting tap event to be [%v], but was [%v]", expectedTapEvent, &actualTapEvent)
+			}
+		}
+
+		_, _, err = ws.ReadMessage()
+		if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			t.Fatalf("Expecting the websocket to be closed, got: %v", err)
+		}
+
+		expectedRequest := &pb.TapByResourceRequest{
+			Target: &pb.ResourceSelection{
+				Resource: &pb.Resource{Namespace: "emojivoto", Type: "deployment"},
+			},
+			MaxRps: 1,
+		}
+		if !proto.Equal(mockGrpcServer.LastRequestReceived, expectedRequest) {
+			t.Fatalf("Expecting server call to receive [%v], but got [%v]", expectedRequest, mockGrpcServer.LastRequestReceived)
+		}
+	})
+
 	t.Run("Handles errors before opening keep-alive response", func(t *testing.T) {
 		mockGrpcServer := &mockGrpcServer{}
 
@@ -8,15 +8,19 @@ import (
 
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgUtil "github.com/linkerd/linkerd2/pkg/util"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
 )
 
 type promType string
 type promResult struct {
-	prom promType
-	vec  model.Vector
-	err  error
+	prom  promType
+	query string
+	vec   model.Vector
+	err   error
 }
 
 const (
@@ -26,8 +30,10 @@ const (
 	promLatencyP95     = promType("0.95")
 	promLatencyP99     = promType("0.99")
 
-	namespaceLabel    = model.LabelName("namespace")
-	dstNamespaceLabel = model.LabelName("dst_namespace")
+	namespaceLabel      = model.LabelName("namespace")
+	dstNamespaceLabel   = model.LabelName("dst_namespace")
+	dstServiceLabel     = model.LabelName("dst_service")
+	dstLeafServiceLabel = model.LabelName("dst_leaf_service")
 )
 
 func extractSampleValue(sample *model.Sample) uint64 {
@@ -38,26 +44,65 @@ func extractSampleValue(sample *model.Sample) uint64 {
 	return value
 }
 
-func (s *grpcServer) queryProm(ctx context.Context, query string) (model.Vector, error) {
-	log.Debugf("Query request:\n\t%+v", query)
+func (s *grpcServer) queryProm(ctx context.Context, api promv1.API, query string) (model.Vector, error) {
+	logger := pkgUtil.ContextLogger(ctx)
+	logger.Debugf("Query request:\n\t%+v", query)
+
+	ctx, span := trace.StartSpan(ctx, "prometheus.Query")
+	span.AddAttributes(trace.StringAttribute("query", query))
+	defer span.End()
 
 	// single data point (aka summary) query
-	res, err := s.prometheusAPI.Query(ctx, query, time.Time{})
+	res, err := api.Query(ctx, query, time.Time{})
 	if err != nil {
-		log.Errorf("Query(%+v) failed with: %+v", query, err)
+		logger.Errorf("Query(%+v) failed with: %+v", query, err)
 		return nil, err
 	}
-	log.Debugf("Query response:\n\t%+v", res)
+	logger.Debugf("Query response:\n\t%+v", res)
 
 	if res.Type() != model.ValVector {
 		err = fmt.Errorf("Unexpected query result type (expected Vector): %s", res.Type())
-		log.Error(err)
+		logger.Error(err)
 		return nil, err
 	}
 
 	return res.(model.Vector), nil
 }
 
+// queryPromRecorded runs the query of the recorded series, if there is one,
+// and falls back to the raw query if it fails or has no result, e.g. when the
+// recording rules aren't installed or haven't been evaluated yet. It also
+// returns the query whose result it returns.
+func (s *grpcServer) queryPromRecorded(ctx context.Context, api promv1.API, recordedQuery, rawQuery string) (model.Vector, string, error) {
+	if recordedQuery != "" {
+		vec, err := s.queryProm(ctx, api, recordedQuery)
+		if err == nil && len(vec) > 0 {
+			return vec, recordedQuery, nil
+		}
+	}
+	vec, err := s.queryProm(ctx, api, rawQuery)
+	return vec, rawQuery, err
+}
+
+// promQueryNames names the stats computed by the queries of each promType, as
+// the fields of BasicStats.
+var promQueryNames = map[promType]string{
+	promRequests:       "requests",
+	promActualRequests: "actual_requests",
+	promLatencyP50:     "latency_ms_p50",
+	promLatencyP95:     "latency_ms_p95",
+	promLatencyP99:     "latency_ms_p99",
+}
+
+// promQueries returns the queries of the results, by the name of their stat.
+func promQueries(results []promResult) map[string]string {
+	queries := make(map[string]string)
+	for _, result := range results {
+		queries[promQueryNames[result.prom]] = result.query
+	}
+	return queries
+}
+
 // add filtering by resource type
 // note that metricToKey assumes the label ordering (namespace, name)
 func promGroupByLabelNames(resource *pb.Resource) model.LabelNames {
@@ -96,20 +141,23 @@ func promQueryLabels(resource *pb.Resource) model.LabelSet {
 	return set
 }
 
-// query a named resource
+// query a named resource, or the resources of a namespace by their
+// destination labels
 func promDstQueryLabels(resource *pb.Resource) model.LabelSet {
 	set := model.LabelSet{}
-	if resource.Name != "" {
-		if isNonK8sResourceQuery(resource.GetType()) {
+	if isNonK8sResourceQuery(resource.GetType()) {
+		if resource.Name != "" {
 			set[promResourceType(resource)] = model.LabelValue(resource.Name)
-		} else {
-			set["dst_"+promResourceType(resource)] = model.LabelValue(resource.Name)
-			if shouldAddNamespaceLabel(resource) {
-				set[dstNamespaceLabel] = model.LabelValue(resource.Namespace)
-			}
 		}
+		return set
 	}
 
+	if resource.Name != "" {
+		set["dst_"+promResourceType(resource)] = model.LabelValue(resource.Name)
+	}
+	if shouldAddNamespaceLabel(resource) {
+		set[dstNamespaceLabel] = model.LabelValue(resource.Namespace)
+	}
 	return set
 }
 
@@ -130,35 +178,57 @@ func promResourceType(resource *pb.Resource) model.LabelName {
 	return model.LabelName(l5dLabel)
 }
 
-func (s *grpcServer) getPrometheusMetrics(ctx context.Context, requestQueryTemplates map[promType]string, latencyQueryTemplate, labels, timeWindow, groupBy string) ([]promResult, error) {
+// prometheusAPIFor returns the API of the Prometheus the queries over the
+// time window are sent to: the long-window Prometheus if there is one and the
+// window is longer than the retention of the primary Prometheus, or else the
+// primary Prometheus.
+func (s *grpcServer) prometheusAPIFor(window time.Duration) promv1.API {
+	if s.longWindowPrometheusAPI != nil && window > s.longWindowThreshold {
+		return s.longWindowPrometheusAPI
+	}
+	return s.prometheusAPI
+}
+
+func (s *grpcServer) getPrometheusMetrics(ctx context.Context, metrics promMetrics, query *promQuery) ([]promResult, error) {
 	resultChan := make(chan promResult)
+	api := s.prometheusAPIFor(time.Duration(query.window))
 
 	// kick off asynchronous queries: request count queries + 3 latency queries
-	for pt, requestQueryTemplate := range requestQueryTemplates {
-		go func(typ promType, template string) {
+	for pt, metric := range metrics.requests {
+		go func(typ promType, metric string) {
 			// success/failure counts
-			requestsQuery := fmt.Sprintf(template, labels, timeWindow, groupBy)
-			resultVector, err := s.queryProm(ctx, requestsQuery)
+			requestsQuery := query.requests(metric, metrics.requestsGroupBy)
+			recordedQuery := ""
+			if s.recordingRules {
+				recordedQuery, _ = query.recordedRequests(metric, metrics.requestsGroupBy)
+			}
+			resultVector, executed, err := s.queryPromRecorded(ctx, api, recordedQuery, requestsQuery)
 
 			resultChan <- promResult{
-				prom: typ,
-				vec:  resultVector,
-				err:  err,
+				prom:  typ,
+				query: executed,
+				vec:   resultVector,
+				err:   err,
 			}
-		}(pt, requestQueryTemplate)
+		}(pt, metric)
 	}
 
 	quantiles := []promType{promLatencyP50, promLatencyP95, promLatencyP99}
 
 	for _, quantile := range quantiles {
 		go func(quantile promType) {
-			latencyQuery := fmt.Sprintf(latencyQueryTemplate, quantile, labels, timeWindow, groupBy)
-			latencyResult, err := s.queryProm(ctx, latencyQuery)
+			latencyQuery := query.latency(quantile, metrics.latency, metrics.latencyGroupBy)
+			recordedQuery := ""
+			if s.recordingRules {
+				recordedQuery, _ = query.recordedLatency(quantile, metrics.latency, metrics.latencyGroupBy)
+			}
+			latencyResult, executed, err := s.queryPromRecorded(ctx, api, recordedQuery, latencyQuery)
 
 			resultChan <- promResult{
-				prom: quantile,
-				vec:  latencyResult,
-				err:  err,
+				prom:  quantile,
+				query: executed,
+				vec:   latencyResult,
+				err:   err,
 			}
 		}(quantile)
 	}
@@ -166,7 +236,7 @@ func (s *grpcServer) getPrometheusMetrics(ctx context.Context, requestQueryTempl
 	// process results, receive one message per prometheus query type
 	var err error
 	results := []promResult{}
-	for i := 0; i < len(quantiles)+len(requestQueryTemplates); i++ {
+	for i := 0; i < len(quantiles)+len(metrics.requests); i++ {
 		result := <-resultChan
 		if result.err != nil {
 			log.Errorf("queryProm failed with: %s", result.err)
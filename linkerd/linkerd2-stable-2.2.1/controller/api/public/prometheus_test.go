@@ -0,0 +1,127 @@
+package public
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+)
+
+func TestGetPrometheusMetricsLongWindow(t *testing.T) {
+	testCases := []struct {
+		timeWindow string
+		longWindow bool
+	}{
+		{"1m", false},
+		{"6h", false},
+		{"24h", true},
+	}
+
+	for i, tc := range testCases {
+		prom := &mockProm{Res: model.Vector{}}
+		longWindowProm := &mockProm{Res: model.Vector{}}
+		server := &grpcServer{
+			prometheusAPI:           prom,
+			longWindowPrometheusAPI: longWindowProm,
+			longWindowThreshold:     6 * time.Hour,
+		}
+
+		query, err := newPromQuery(model.LabelSet{"namespace": "emojivoto"}, tc.timeWindow, model.LabelNames{"namespace"})
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if _, err := server.getPrometheusMetrics(context.Background(), responseMetrics, query); err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+
+		queried, notQueried := prom, longWindowProm
+		if tc.longWindow {
+			queried, notQueried = longWindowProm, prom
+		}
+		if len(queried.QueriesExecuted) == 0 || len(notQueried.QueriesExecuted) != 0 {
+			t.Fatalf("test case %d: expected the queries to be sent to the long-window Prometheus to be %t, got %d primary and %d long-window queries",
+				i, tc.longWindow, len(prom.QueriesExecuted), len(longWindowProm.QueriesExecuted))
+		}
+	}
+
+	// without a long-window Prometheus, every query goes to the primary one
+	prom := &mockProm{Res: model.Vector{}}
+	server := &grpcServer{prometheusAPI: prom}
+	if api := server.prometheusAPIFor(24 * time.Hour); api != prom {
+		t.Fatalf("Expected the queries to be sent to the primary Prometheus")
+	}
+}
+
+func TestGetPrometheusMetricsRecordingRules(t *testing.T) {
+	query, err := newPromQuery(model.LabelSet{"namespace": "emojivoto"}, "1m", model.LabelNames{"namespace"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// the recorded series have results, so the raw metrics aren't queried
+	prom := &mockProm{Res: model.Vector{&model.Sample{Value: 1}}}
+	server := &grpcServer{prometheusAPI: prom, recordingRules: true}
+	if _, err := server.getPrometheusMetrics(context.Background(), responseMetrics, query); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, q := range prom.QueriesExecuted {
+		if !strings.Contains(q, "linkerd:") {
+			t.Fatalf("Expected only the recorded series to be queried, got %s", q)
+		}
+	}
+
+	// the recorded series have no result, so the raw metrics are queried
+	prom = &mockProm{Res: model.Vector{}}
+	server = &grpcServer{prometheusAPI: prom, recordingRules: true}
+	if _, err := server.getPrometheusMetrics(context.Background(), responseMetrics, query); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	raw := 0
+	for _, q := range prom.QueriesExecuted {
+		if !strings.Contains(q, "linkerd:") {
+			raw++
+		}
+	}
+	if raw != len(prom.QueriesExecuted)/2 {
+		t.Fatalf("Expected every recorded query to fall back to a raw query, got %v", prom.QueriesExecuted)
+	}
+}
+
+func TestPromDstQueryLabels(t *testing.T) {
+	testCases := []struct {
+		resource *pb.Resource
+		expected model.LabelSet
+	}{
+		{
+			&pb.Resource{Type: pkgK8s.Service, Name: "foo", Namespace: "bar"},
+			model.LabelSet{"dst_service": "foo", "dst_namespace": "bar"},
+		},
+		{
+			&pb.Resource{Type: pkgK8s.Deployment, Namespace: "bar"},
+			model.LabelSet{"dst_namespace": "bar"},
+		},
+		{
+			&pb.Resource{Type: pkgK8s.Deployment},
+			model.LabelSet{},
+		},
+		{
+			&pb.Resource{Type: pkgK8s.Namespace, Name: "bar"},
+			model.LabelSet{"dst_namespace": "bar"},
+		},
+		{
+			&pb.Resource{Type: pkgK8s.Authority, Name: "web.emojivoto.svc.cluster.local", Namespace: "emojivoto"},
+			model.LabelSet{"authority": "web.emojivoto.svc.cluster.local"},
+		},
+	}
+
+	for i, tc := range testCases {
+		if actual := promDstQueryLabels(tc.resource); !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("test case %d: expected %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package public
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// promMetrics names the metrics queried by getPrometheusMetrics: the counters
+// of the request count queries, by type, and the histogram of the latency
+// queries, with the labels they're grouped by on top of the query's.
+type promMetrics struct {
+	requests        map[promType]string
+	requestsGroupBy model.LabelNames
+	latency         string
+	latencyGroupBy  model.LabelNames
+}
+
+var (
+	responseMetrics = promMetrics{
+		requests: map[promType]string{
+			promRequests: "response_total",
+		},
+		requestsGroupBy: model.LabelNames{"classification", "tls"},
+		latency:         "response_latency_ms_bucket",
+	}
+
+	routeResponseMetrics = promMetrics{
+		requests: map[promType]string{
+			promRequests: "route_response_total",
+		},
+		requestsGroupBy: model.LabelNames{"dst", "classification", "status_code", "grpc_status"},
+		latency:         "route_response_latency_ms_bucket",
+		latencyGroupBy:  model.LabelNames{"dst"},
+	}
+
+	// actualRouteResponseMetrics also counts the actual requests to the
+	// routes, retries included
+	actualRouteResponseMetrics = promMetrics{
+		requests: map[promType]string{
+			promRequests:       "route_response_total",
+			promActualRequests: "route_actual_response_total",
+		},
+		requestsGroupBy: routeResponseMetrics.requestsGroupBy,
+		latency:         routeResponseMetrics.latency,
+		latencyGroupBy:  routeResponseMetrics.latencyGroupBy,
+	}
+)
+
+// recordedWindow is the time window the recording rules of
+// chart/templates/base.yaml pre-aggregate the proxy metrics over, without the
+// recordedDroppedLabels, into the recordedMetrics series.
+const recordedWindow = model.Duration(time.Minute)
+
+var (
+	recordedDroppedLabels = model.LabelNames{"instance", "pod"}
+
+	recordedMetrics = map[string]string{
+		"response_total":                   "linkerd:response_total:increase1m",
+		"response_latency_ms_bucket":       "linkerd:response_latency_ms_bucket:irate1m",
+		"route_response_total":             "linkerd:route_response_total:increase1m",
+		"route_actual_response_total":      "linkerd:route_actual_response_total:increase1m",
+		"route_response_latency_ms_bucket": "linkerd:route_response_latency_ms_bucket:irate1m",
+	}
+)
+
+// promQuery builds the PromQL queries of the requests matching a set of
+// labels, over a time window, grouped by other labels. The label names and
+// the time window are validated when the query is created, and the label
+// values are escaped when it's rendered, so that resource names can't alter
+// the queries.
+type promQuery struct {
+	labels  model.LabelSet
+	regexes map[model.LabelName]string
+	window  model.Duration
+	groupBy model.LabelNames
+}
+
+func newPromQuery(labels model.LabelSet, timeWindow string, groupBy model.LabelNames) (*promQuery, error) {
+	window, err := model.ParseDuration(timeWindow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time window %q: %s", timeWindow, err)
+	}
+	if err := labels.Validate(); err != nil {
+		return nil, err
+	}
+	for _, name := range groupBy {
+		if !name.IsValid() {
+			return nil, fmt.Errorf("invalid group by label name %q", name)
+		}
+	}
+
+	return &promQuery{
+		labels:  labels,
+		regexes: make(map[model.LabelName]string),
+		window:  window,
+		groupBy: groupBy,
+	}, nil
+}
+
+// matchValues adds a matcher of the label to any of the values, which are
+// matched literally, optionally followed by a port.
+func (q *promQuery) matchValues(name model.LabelName, values []string) {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	q.regexes[name] = fmt.Sprintf(`(%s)(:\d+)?`, strings.Join(quoted, "|"))
+}
+
+// matchRegex replaces the matcher of the label to a value with a matcher of
+// the label to the regex.
+func (q *promQuery) matchRegex(name model.LabelName, regex string) {
+	delete(q.labels, name)
+	q.regexes[name] = regex
+}
+
+// selector renders the label matchers of the query, sorted by label name.
+func (q *promQuery) selector() string {
+	matchers := make([]string, 0, len(q.labels)+len(q.regexes))
+	for name, value := range q.labels {
+		matchers = append(matchers, string(name)+"="+strconv.Quote(string(value)))
+	}
+	for name, regex := range q.regexes {
+		matchers = append(matchers, string(name)+"=~"+strconv.Quote(regex))
+	}
+	sort.Strings(matchers)
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+// requests renders the query of the number of requests counted by the
+// counter metric.
+func (q *promQuery) requests(metric string, groupBy model.LabelNames) string {
+	by := append(append(model.LabelNames{}, q.groupBy...), groupBy...)
+	return fmt.Sprintf("sum(increase(%s%s[%s])) by (%s)", metric, q.selector(), q.window, by)
+}
+
+// latency renders the query of the quantile of the latencies of the requests
+// recorded by the histogram metric.
+func (q *promQuery) latency(quantile promType, metric string, groupBy model.LabelNames) string {
+	by := append(append(model.LabelNames{"le"}, groupBy...), q.groupBy...)
+	return fmt.Sprintf("histogram_quantile(%s, sum(irate(%s%s[%s])) by (%s))", quantile, metric, q.selector(), q.window, by)
+}
+
+// recordedRequests renders the query of requests from the series recorded
+// from the counter metric, or returns false if the recorded series can't serve
+// the query, because of its time window or labels.
+func (q *promQuery) recordedRequests(metric string, groupBy model.LabelNames) (string, bool) {
+	recorded, ok := q.recordedMetric(metric, groupBy)
+	if !ok {
+		return "", false
+	}
+	by := append(append(model.LabelNames{}, q.groupBy...), groupBy...)
+	return fmt.Sprintf("sum(%s%s) by (%s)", recorded, q.selector(), by), true
+}
+
+// recordedLatency renders the query of the quantile of the latencies from the
+// series recorded from the histogram metric, or returns false if the recorded
+// series can't serve the query, because of its time window or labels.
+func (q *promQuery) recordedLatency(quantile promType, metric string, groupBy model.LabelNames) (string, bool) {
+	recorded, ok := q.recordedMetric(metric, groupBy)
+	if !ok {
+		return "", false
+	}
+	by := append(append(model.LabelNames{"le"}, groupBy...), q.groupBy...)
+	return fmt.Sprintf("histogram_quantile(%s, sum(%s%s) by (%s))", quantile, recorded, q.selector(), by), true
+}
+
+func (q *promQuery) recordedMetric(metric string, groupBy model.LabelNames) (string, bool) {
+	recorded, ok := recordedMetrics[metric]
+	if !ok || q.window != recordedWindow {
+		return "", false
+	}
+	for _, name := range recordedDroppedLabels {
+		if _, ok := q.labels[name]; ok {
+			return "", false
+		}
+		if _, ok := q.regexes[name]; ok {
+			return "", false
+		}
+		for _, by := range append(append(model.LabelNames{}, q.groupBy...), groupBy...) {
+			if by == name {
+				return "", false
+			}
+		}
+	}
+	return recorded, true
+}
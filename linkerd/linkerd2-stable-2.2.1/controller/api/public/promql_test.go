@@ -0,0 +1,116 @@
+package public
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestPromQuery(t *testing.T) {
+	t.Run("Renders request and latency queries", func(t *testing.T) {
+		query, err := newPromQuery(
+			model.LabelSet{"direction": "inbound", "namespace": "emojivoto"},
+			"1m",
+			model.LabelNames{"namespace", "deployment"},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		requests := query.requests(responseMetrics.requests[promRequests], responseMetrics.requestsGroupBy)
+		expected := `sum(increase(response_total{direction="inbound", namespace="emojivoto"}[1m])) by (namespace, deployment, classification, tls)`
+		if requests != expected {
+			t.Fatalf("Expected query:\n%s\nGot:\n%s", expected, requests)
+		}
+
+		latency := query.latency(promLatencyP95, responseMetrics.latency, responseMetrics.latencyGroupBy)
+		expected = `histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, deployment))`
+		if latency != expected {
+			t.Fatalf("Expected query:\n%s\nGot:\n%s", expected, latency)
+		}
+	})
+
+	t.Run("Escapes label values", func(t *testing.T) {
+		query, err := newPromQuery(
+			model.LabelSet{"deployment": `web"} or vector(1) #`},
+			"1m",
+			model.LabelNames{"rt_route"},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		query.matchValues("dst", []string{"web.default.svc.cluster.local", `a|b")`})
+
+		expected := `{deployment="web\"} or vector(1) #", dst=~"(web\\.default\\.svc\\.cluster\\.local|a\\|b\"\\))(:\\d+)?"}`
+		if selector := query.selector(); selector != expected {
+			t.Fatalf("Expected selector:\n%s\nGot:\n%s", expected, selector)
+		}
+	})
+
+	t.Run("Rejects invalid queries", func(t *testing.T) {
+		testCases := []struct {
+			labels     model.LabelSet
+			timeWindow string
+			groupBy    model.LabelNames
+		}{
+			{model.LabelSet{"namespace": "emojivoto"}, "", model.LabelNames{"namespace"}},
+			{model.LabelSet{"namespace": "emojivoto"}, "1m]) or vector(1", model.LabelNames{"namespace"}},
+			{model.LabelSet{"namespace": "emojivoto"}, "1m30s", model.LabelNames{"namespace"}},
+			{model.LabelSet{"name-space": "emojivoto"}, "1m", model.LabelNames{"namespace"}},
+			{model.LabelSet{"namespace": "\xff"}, "1m", model.LabelNames{"namespace"}},
+			{model.LabelSet{"namespace": "emojivoto"}, "1m", model.LabelNames{"namespace) or (pod"}},
+		}
+
+		for i, tc := range testCases {
+			_, err := newPromQuery(tc.labels, tc.timeWindow, tc.groupBy)
+			if err == nil {
+				t.Fatalf("test case %d: expected an error", i)
+			}
+		}
+	})
+
+	t.Run("Renders queries of the recorded series", func(t *testing.T) {
+		query, err := newPromQuery(
+			model.LabelSet{"direction": "inbound", "namespace": "emojivoto"},
+			"1m",
+			model.LabelNames{"namespace", "deployment"},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		requests, ok := query.recordedRequests(responseMetrics.requests[promRequests], responseMetrics.requestsGroupBy)
+		expected := `sum(linkerd:response_total:increase1m{direction="inbound", namespace="emojivoto"}) by (namespace, deployment, classification, tls)`
+		if !ok || requests != expected {
+			t.Fatalf("Expected query:\n%s\nGot:\n%s", expected, requests)
+		}
+
+		latency, ok := query.recordedLatency(promLatencyP95, responseMetrics.latency, responseMetrics.latencyGroupBy)
+		expected = `histogram_quantile(0.95, sum(linkerd:response_latency_ms_bucket:irate1m{direction="inbound", namespace="emojivoto"}) by (le, namespace, deployment))`
+		if !ok || latency != expected {
+			t.Fatalf("Expected query:\n%s\nGot:\n%s", expected, latency)
+		}
+	})
+
+	t.Run("Doesn't query the recorded series when they can't serve the query", func(t *testing.T) {
+		testCases := []struct {
+			labels     model.LabelSet
+			timeWindow string
+			groupBy    model.LabelNames
+		}{
+			{model.LabelSet{"namespace": "emojivoto"}, "10m", model.LabelNames{"namespace"}},
+			{model.LabelSet{"namespace": "emojivoto"}, "1m", model.LabelNames{"namespace", "pod"}},
+			{model.LabelSet{"pod": "web-1"}, "1m", model.LabelNames{"namespace"}},
+		}
+
+		for i, tc := range testCases {
+			query, err := newPromQuery(tc.labels, tc.timeWindow, tc.groupBy)
+			if err != nil {
+				t.Fatalf("test case %d: unexpected error: %s", i, err)
+			}
+			if q, ok := query.recordedRequests(responseMetrics.requests[promRequests], responseMetrics.requestsGroupBy); ok {
+				t.Fatalf("test case %d: expected no recorded query, got %s", i, q)
+			}
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package public
+
+import (
+	"strconv"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/prometheus/common/model"
+)
+
+// classifyRouteSample returns the classification of the responses counted by a
+// sample of a route. The responses are classified by the first response class
+// of the route that matches their status and grpc-status, so that the classes
+// the proxy can't evaluate are honored, and otherwise by the proxy.
+func classifyRouteSample(sample *model.Sample, route *sp.RouteSpec) string {
+	if route != nil {
+		status := parseStatus(string(sample.Metric[model.LabelName("status_code")]))
+		grpcStatus := parseStatus(string(sample.Metric[model.LabelName("grpc_status")]))
+		for _, rc := range route.ResponseClasses {
+			if rc.Condition == nil || !responseMatches(rc.Condition, status, grpcStatus) {
+				continue
+			}
+			if rc.IsFailure {
+				return "failure"
+			}
+			return "success"
+		}
+	}
+	return string(sample.Metric[model.LabelName("classification")])
+}
+
+// parseStatus returns the status code in s, or -1 if s isn't a status code,
+// e.g. because the response wasn't a gRPC response.
+func parseStatus(s string) int64 {
+	code, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return -1
+	}
+	return int64(code)
+}
+
+func responseMatches(rspMatch *sp.ResponseMatch, status, grpcStatus int64) bool {
+	for _, m := range rspMatch.All {
+		if !responseMatches(m, status, grpcStatus) {
+			return false
+		}
+	}
+
+	if rspMatch.Any != nil {
+		matchesAny := false
+		for _, m := range rspMatch.Any {
+			if responseMatches(m, status, grpcStatus) {
+				matchesAny = true
+				break
+			}
+		}
+		if !matchesAny {
+			return false
+		}
+	}
+
+	if rspMatch.Not != nil && responseMatches(rspMatch.Not, status, grpcStatus) {
+		return false
+	}
+
+	if rspMatch.Status != nil && !rangeMatches(rspMatch.Status, status) {
+		return false
+	}
+
+	if rspMatch.GRPCStatus != nil && !rangeMatches(rspMatch.GRPCStatus, grpcStatus) {
+		return false
+	}
+
+	return true
+}
+
+// rangeMatches returns true if code is in r. As in the proxy, a range with
+// only one bound set matches just that one code.
+func rangeMatches(r *sp.Range, code int64) bool {
+	if code < 0 {
+		return false
+	}
+	min, max := int64(r.Min), int64(r.Max)
+	if r.Min == 0 {
+		min = max
+	}
+	if r.Max == 0 {
+		max = min
+	}
+	return min <= code && code <= max
+}
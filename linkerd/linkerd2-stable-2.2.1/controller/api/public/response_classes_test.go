@@ -0,0 +1,66 @@
+package public
+
+import (
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/prometheus/common/model"
+)
+
+func TestClassifyRouteSample(t *testing.T) {
+	route := &sp.RouteSpec{
+		Name: "GET /books/{id}",
+		ResponseClasses: []*sp.ResponseClass{
+			{
+				Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 404}},
+				IsFailure: false,
+			},
+			{
+				Condition: &sp.ResponseMatch{GRPCStatus: &sp.Range{Min: 5}},
+				IsFailure: true,
+			},
+			{
+				Condition: &sp.ResponseMatch{
+					All: []*sp.ResponseMatch{
+						{Status: &sp.Range{Min: 500, Max: 599}},
+						{Not: &sp.ResponseMatch{Status: &sp.Range{Min: 503}}},
+					},
+				},
+				IsFailure: true,
+			},
+		},
+	}
+
+	testCases := []struct {
+		status         string
+		grpcStatus     string
+		classification string
+		expected       string
+	}{
+		{"404", "", "failure", "success"},
+		{"200", "5", "success", "failure"},
+		{"500", "", "success", "failure"},
+		{"503", "", "success", "success"},
+		{"200", "", "success", "success"},
+		{"", "", "failure", "failure"},
+	}
+
+	for _, tc := range testCases {
+		sample := &model.Sample{
+			Metric: model.Metric{
+				"classification": model.LabelValue(tc.classification),
+				"status_code":    model.LabelValue(tc.status),
+				"grpc_status":    model.LabelValue(tc.grpcStatus),
+			},
+		}
+		classification := classifyRouteSample(sample, route)
+		if classification != tc.expected {
+			t.Fatalf("Expected status [%s] grpc-status [%s] to be classified as [%s], got [%s]", tc.status, tc.grpcStatus, tc.expected, classification)
+		}
+	}
+
+	sample := &model.Sample{Metric: model.Metric{"classification": "success", "status_code": "404"}}
+	if classification := classifyRouteSample(sample, nil); classification != "success" {
+		t.Fatalf("Expected the proxy's classification for an unknown route, got [%s]", classification)
+	}
+}
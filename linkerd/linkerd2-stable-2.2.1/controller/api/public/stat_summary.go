@@ -2,12 +2,14 @@ package public
 
 import (
 	"context"
+	"fmt"
 
 	proto "github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/prometheus/common/model"
+	"go.opencensus.io/trace"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,11 +32,6 @@ type rKey struct {
 	Name      string
 }
 
-const (
-	reqQuery             = "sum(increase(response_total%s[%s])) by (%s, classification, tls)"
-	latencyQuantileQuery = "histogram_quantile(%s, sum(irate(response_latency_ms_bucket%s[%s])) by (le, %s))"
-)
-
 type podStats struct {
 	inMesh uint64
 	total  uint64
@@ -54,6 +51,15 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		return statSummaryError(req, "service only supported as a target on 'from' queries, or as a destination on 'to' queries"), nil
 	}
 
+	if req.Selector.Resource.Type == k8s.TrafficSplit {
+		if s.singleNamespace {
+			return statSummaryError(req, "Traffic splits are not available in single-namespace mode"), nil
+		}
+		if req.GetToResource() != nil || req.GetFromResource() != nil {
+			return statSummaryError(req, "traffic splits are not supported in 'to' or 'from' queries"), nil
+		}
+	}
+
 	switch req.Outbound.(type) {
 	case *pb.StatSummaryRequest_ToResource:
 		if req.Outbound.(*pb.StatSummaryRequest_ToResource).ToResource.Type == k8s.All {
@@ -65,6 +71,33 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		}
 	}
 
+	if message := validateToFilter(req); message != "" {
+		return statSummaryError(req, message), nil
+	}
+
+	if message := validateNamePatterns(req); message != "" {
+		return statSummaryError(req, message), nil
+	}
+
+	switch req.Direction {
+	case "", "inbound":
+	case "outbound":
+		if req.GetToResource() != nil || req.GetFromResource() != nil {
+			return statSummaryError(req, "'to' and 'from' queries only count outbound requests, and don't support a direction"), nil
+		}
+		if req.Selector.Resource.Type == k8s.TrafficSplit {
+			return statSummaryError(req, "traffic splits only support the inbound direction"), nil
+		}
+	default:
+		return statSummaryError(req, fmt.Sprintf("invalid direction %q, must be inbound or outbound", req.Direction)), nil
+	}
+
+	if !req.SkipStats {
+		if err := s.timeWindowBounds.Validate(req.TimeWindow); err != nil {
+			return statSummaryError(req, err.Error()), nil
+		}
+	}
+
 	statTables := make([]*pb.StatTable, 0)
 
 	var resourcesToQuery []string
@@ -84,6 +117,8 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		go func() {
 			if isNonK8sResourceQuery(statReq.GetSelector().GetResource().GetType()) {
 				resultChan <- s.nonK8sResourceQuery(ctx, statReq)
+			} else if statReq.GetSelector().GetResource().GetType() == k8s.TrafficSplit {
+				resultChan <- s.trafficSplitResourceQuery(ctx, statReq)
 			} else {
 				resultChan <- s.k8sResourceQuery(ctx, statReq)
 			}
@@ -117,6 +152,56 @@ func isInvalidServiceRequest(selector *pb.ResourceSelection, fromResource *pb.Re
 	return selector.Resource.Type == k8s.Service
 }
 
+// validateToFilter returns why the to filter of a request, which narrows a
+// 'from' query to the requests sent to a resource, is invalid, or an empty
+// string if it's valid.
+func validateToFilter(req *pb.StatSummaryRequest) string {
+	toFilter := req.GetToFilter()
+	if toFilter == nil {
+		return ""
+	}
+
+	if req.GetFromResource() == nil {
+		return "'to' filter is only supported along with a 'from' resource"
+	}
+	if toFilter.Type == k8s.All {
+		return "resource type 'all' is not supported as a filter"
+	}
+
+	// the requests are selected by the labels of their destination, which
+	// both the to filter and the selected resource set
+	if req.Selector.Resource.Type != k8s.All {
+		selected := promDstQueryLabels(req.Selector.Resource)
+		for name, value := range promDstQueryLabels(toFilter) {
+			if v, ok := selected[name]; ok && v != value {
+				return fmt.Sprintf("the 'to' resource %s/%s conflicts with the selected resource %s/%s", toFilter.Type, toFilter.Name, req.Selector.Resource.Type, req.Selector.Resource.Name)
+			}
+		}
+	}
+
+	return ""
+}
+
+// validateNamePatterns returns why the name patterns of the resources of a
+// request, which are only supported for authorities, are invalid, or an empty
+// string if they're valid.
+func validateNamePatterns(req *pb.StatSummaryRequest) string {
+	resources := []*pb.Resource{req.Selector.Resource, req.GetToResource(), req.GetFromResource(), req.GetToFilter()}
+	for _, resource := range resources {
+		if resource == nil {
+			continue
+		}
+		regex, err := util.AuthorityRegex(resource.Name)
+		if err != nil {
+			return err.Error()
+		}
+		if regex != "" && resource.Type != k8s.Authority {
+			return fmt.Sprintf("name patterns are only supported for authorities, not for %s/%s", resource.Type, resource.Name)
+		}
+	}
+	return ""
+}
+
 func statSummaryError(req *pb.StatSummaryRequest, message string) *pb.StatSummaryResponse {
 	return &pb.StatSummaryResponse{
 		Response: &pb.StatSummaryResponse_Error{
@@ -128,9 +213,9 @@ func statSummaryError(req *pb.StatSummaryRequest, message string) *pb.StatSummar
 	}
 }
 
-func (s *grpcServer) getKubernetesObjectStats(req *pb.StatSummaryRequest) (map[rKey]k8sStat, error) {
+func (s *grpcServer) getKubernetesObjectStats(ctx context.Context, req *pb.StatSummaryRequest) (map[rKey]k8sStat, error) {
 	requestedResource := req.GetSelector().GetResource()
-	objects, err := s.k8sAPI.GetObjects(requestedResource.Namespace, requestedResource.Type, requestedResource.Name)
+	objects, err := s.getObjects(ctx, requestedResource)
 	if err != nil {
 		return nil, err
 	}
@@ -163,14 +248,15 @@ func (s *grpcServer) getKubernetesObjectStats(req *pb.StatSummaryRequest) (map[r
 }
 
 func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
-	k8sObjects, err := s.getKubernetesObjectStats(req)
+	k8sObjects, err := s.getKubernetesObjectStats(ctx, req)
 	if err != nil {
 		return resourceResult{res: nil, err: err}
 	}
 
 	var requestMetrics map[rKey]*pb.BasicStats
+	var queries map[string]string
 	if !req.SkipStats {
-		requestMetrics, err = s.getStatMetrics(ctx, req, req.TimeWindow)
+		requestMetrics, queries, err = s.getStatMetrics(ctx, req, req.TimeWindow)
 		if err != nil {
 			return resourceResult{res: nil, err: err}
 		}
@@ -207,7 +293,8 @@ func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRe
 	rsp := pb.StatTable{
 		Table: &pb.StatTable_PodGroup_{
 			PodGroup: &pb.StatTable_PodGroup{
-				Rows: rows,
+				Rows:    rows,
+				Queries: shownQueries(req, queries),
 			},
 		},
 	}
@@ -217,9 +304,10 @@ func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRe
 
 func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
 	var requestMetrics map[rKey]*pb.BasicStats
+	var queries map[string]string
 	if !req.SkipStats {
 		var err error
-		requestMetrics, err = s.getStatMetrics(ctx, req, req.TimeWindow)
+		requestMetrics, queries, err = s.getStatMetrics(ctx, req, req.TimeWindow)
 		if err != nil {
 			return resourceResult{res: nil, err: err}
 		}
@@ -244,13 +332,23 @@ func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummar
 	rsp := pb.StatTable{
 		Table: &pb.StatTable_PodGroup_{
 			PodGroup: &pb.StatTable_PodGroup{
-				Rows: rows,
+				Rows:    rows,
+				Queries: shownQueries(req, queries),
 			},
 		},
 	}
 	return resourceResult{res: &rsp, err: nil}
 }
 
+// shownQueries returns the queries of the stats if the request shows them, or
+// else nil.
+func shownQueries(req *pb.StatSummaryRequest, queries map[string]string) map[string]string {
+	if !req.ShowQueries {
+		return nil
+	}
+	return queries
+}
+
 func isNonK8sResourceQuery(resourceType string) bool {
 	return resourceType == k8s.Authority
 }
@@ -294,28 +392,53 @@ func buildRequestLabels(req *pb.StatSummaryRequest) (labels model.LabelSet, labe
 		labelNames = promDstGroupByLabelNames(req.Selector.Resource)
 
 		labels = labels.Merge(promQueryLabels(out.FromResource))
+		if req.ToFilter != nil {
+			labels = labels.Merge(promDstQueryLabels(req.ToFilter))
+		}
 		labels = labels.Merge(promDstQueryLabels(req.Selector.Resource))
 		labels = labels.Merge(promDirectionLabels("outbound"))
 
 	default:
 		labelNames = promGroupByLabelNames(req.Selector.Resource)
 
+		direction := "inbound"
+		if req.Direction == "outbound" {
+			direction = "outbound"
+		}
 		labels = labels.Merge(promQueryLabels(req.Selector.Resource))
-		labels = labels.Merge(promDirectionLabels("inbound"))
+		labels = labels.Merge(promDirectionLabels(direction))
 	}
 
 	return
 }
 
-func (s *grpcServer) getStatMetrics(ctx context.Context, req *pb.StatSummaryRequest, timeWindow string) (map[rKey]*pb.BasicStats, error) {
+// getStatMetrics returns the stats of the resources selected by the request,
+// along with the queries they were computed with, by stat.
+func (s *grpcServer) getStatMetrics(ctx context.Context, req *pb.StatSummaryRequest, timeWindow string) (map[rKey]*pb.BasicStats, map[string]string, error) {
 	reqLabels, groupBy := buildRequestLabels(req)
-	results, err := s.getPrometheusMetrics(ctx, map[promType]string{promRequests: reqQuery}, latencyQuantileQuery, reqLabels.String(), timeWindow, groupBy.String())
+	query, err := newPromQuery(reqLabels, timeWindow, groupBy)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	// authorities may be selected by a pattern rather than by name
+	authorityLabel := model.LabelName(k8s.KindToL5DLabel(k8s.Authority))
+	if authority, ok := reqLabels[authorityLabel]; ok {
+		regex, err := util.AuthorityRegex(string(authority))
+		if err != nil {
+			return nil, nil, err
+		}
+		if regex != "" {
+			query.matchRegex(authorityLabel, regex)
+		}
+	}
+
+	results, err := s.getPrometheusMetrics(ctx, responseMetrics, query)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return processPrometheusMetrics(req, results, groupBy), nil
+	return processPrometheusMetrics(req, results, groupBy), promQueries(results), nil
 }
 
 func processPrometheusMetrics(req *pb.StatSummaryRequest, results []promResult, groupBy model.LabelNames) map[rKey]*pb.BasicStats {
@@ -329,33 +452,37 @@ func processPrometheusMetrics(req *pb.StatSummaryRequest, results []promResult,
 				basicStats[resource] = &pb.BasicStats{}
 			}
 
-			value := extractSampleValue(sample)
-
-			switch result.prom {
-			case promRequests:
-				switch string(sample.Metric[model.LabelName("classification")]) {
-				case "success":
-					basicStats[resource].SuccessCount += value
-				case "failure":
-					basicStats[resource].FailureCount += value
-				}
-				switch string(sample.Metric[model.LabelName("tls")]) {
-				case "true":
-					basicStats[resource].TlsRequestCount += value
-				}
-			case promLatencyP50:
-				basicStats[resource].LatencyMsP50 = value
-			case promLatencyP95:
-				basicStats[resource].LatencyMsP95 = value
-			case promLatencyP99:
-				basicStats[resource].LatencyMsP99 = value
-			}
+			addSampleToStats(basicStats[resource], result.prom, sample)
 		}
 	}
 
 	return basicStats
 }
 
+func addSampleToStats(stats *pb.BasicStats, prom promType, sample *model.Sample) {
+	value := extractSampleValue(sample)
+
+	switch prom {
+	case promRequests:
+		switch string(sample.Metric[model.LabelName("classification")]) {
+		case "success":
+			stats.SuccessCount += value
+		case "failure":
+			stats.FailureCount += value
+		}
+		switch string(sample.Metric[model.LabelName("tls")]) {
+		case "true":
+			stats.TlsRequestCount += value
+		}
+	case promLatencyP50:
+		stats.LatencyMsP50 = value
+	case promLatencyP95:
+		stats.LatencyMsP95 = value
+	case promLatencyP99:
+		stats.LatencyMsP99 = value
+	}
+}
+
 func metricToKey(req *pb.StatSummaryRequest, metric model.Metric, groupBy model.LabelNames) rKey {
 	// this key is used to match the metric stats we queried from prometheus
 	// with the k8s object stats we queried from k8s
@@ -372,6 +499,19 @@ func metricToKey(req *pb.StatSummaryRequest, metric model.Metric, groupBy model.
 	return key
 }
 
+// getObjects returns the objects of a resource from the informer caches.
+func (s *grpcServer) getObjects(ctx context.Context, resource *pb.Resource) ([]runtime.Object, error) {
+	_, span := trace.StartSpan(ctx, "k8s.GetObjects")
+	span.AddAttributes(
+		trace.StringAttribute("namespace", resource.GetNamespace()),
+		trace.StringAttribute("type", resource.GetType()),
+		trace.StringAttribute("name", resource.GetName()),
+	)
+	defer span.End()
+
+	return s.k8sAPI.GetObjects(resource.GetNamespace(), resource.GetType(), resource.GetName())
+}
+
 func (s *grpcServer) getPodStats(obj runtime.Object) (*podStats, error) {
 	pods, err := s.k8sAPI.GetPodsFor(obj, true)
 	if err != nil {
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/linkerd/linkerd2/controller/api/util"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -622,10 +624,10 @@ status:
 						genPromSample("emojivoto-1", "pod", "emojivoto", "success", true),
 					},
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`sum(increase(response_total{direction="outbound", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -656,6 +658,115 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the outbound metrics of the resources if the direction is outbound", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="outbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					Direction:  "outbound",
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Queries prometheus for outbound metrics if --from and --to resources are specified", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: model.Vector{
+						genPromSample("emojivoto-1", "pod", "emojivoto", "success", true),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_deployment="emoji", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_deployment="emoji", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_deployment="emoji", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`sum(increase(response_total{direction="outbound", dst_deployment="emoji", dst_namespace="emojivoto", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{
+							Name:      "emojivoto-2",
+							Namespace: "",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					ToFilter: &pb.Resource{
+						Name:      "emoji",
+						Namespace: "emojivoto",
+						Type:      pkgK8s.Deployment,
+					},
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Queries prometheus for outbound metrics if --from resource is specified and --from-namespace is different from the resource namespace", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -926,6 +1037,7 @@ status:
 							Type: "badtype",
 						},
 					},
+					TimeWindow: "1m",
 				},
 			},
 			statSumExpected{
@@ -938,6 +1050,7 @@ status:
 							Type: "deployments",
 						},
 					},
+					TimeWindow: "1m",
 				},
 			},
 			statSumExpected{
@@ -950,6 +1063,7 @@ status:
 							Type: "po",
 						},
 					},
+					TimeWindow: "1m",
 				},
 			},
 		}
@@ -963,6 +1077,7 @@ status:
 				"linkerd",
 				[]string{},
 				false,
+				util.DefaultTimeWindowBounds,
 			)
 
 			_, err := fakeGrpcServer.StatSummary(context.TODO(), &exp.req)
@@ -976,6 +1091,45 @@ status:
 		}
 	})
 
+	t.Run("Rejects time windows out of bounds", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(
+			&mockProm{Res: model.Vector{}},
+			tap.NewTapClient(nil),
+			discovery.NewDiscoveryClient(nil),
+			k8sAPI,
+			"linkerd",
+			[]string{},
+			false,
+			util.TimeWindowBounds{Min: time.Minute, Max: time.Hour},
+		)
+
+		for _, timeWindow := range []string{"", "10s", "2h"} {
+			rsp, err := fakeGrpcServer.StatSummary(context.TODO(), &pb.StatSummaryRequest{
+				Selector: &pb.ResourceSelection{
+					Resource: &pb.Resource{
+						Type: pkgK8s.Pod,
+					},
+				},
+				TimeWindow: timeWindow,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			expected := util.TimeWindowError{
+				TimeWindow: timeWindow,
+				Bounds:     util.TimeWindowBounds{Min: time.Minute, Max: time.Hour},
+			}
+			if rsp.GetError().GetError() != expected.Error() {
+				t.Fatalf("Expected validation error [%s] on StatSummaryResponse, got %v", expected, rsp)
+			}
+		}
+	})
+
 	t.Run("Validates service stat requests", func(t *testing.T) {
 		k8sAPI, err := k8s.NewFakeAPI("")
 		if err != nil {
@@ -989,6 +1143,7 @@ status:
 			"linkerd",
 			[]string{},
 			false,
+			util.DefaultTimeWindowBounds,
 		)
 
 		invalidRequests := []statSumExpected{
@@ -1055,6 +1210,7 @@ status:
 							Type: pkgK8s.Service,
 						},
 					},
+					TimeWindow: "1m",
 				},
 			},
 			statSumExpected{
@@ -1069,6 +1225,7 @@ status:
 							Type: pkgK8s.Pod,
 						},
 					},
+					TimeWindow: "1m",
 				},
 			},
 		}
@@ -1082,6 +1239,131 @@ status:
 		}
 	})
 
+	t.Run("Validates to filters, directions and name patterns", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(
+			&mockProm{Res: model.Vector{}},
+			tap.NewTapClient(nil),
+			discovery.NewDiscoveryClient(nil),
+			k8sAPI,
+			"linkerd",
+			[]string{},
+			false,
+			util.DefaultTimeWindowBounds,
+		)
+
+		testCases := []struct {
+			req           pb.StatSummaryRequest
+			expectedError string
+		}{
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Pod},
+					},
+					ToFilter:   &pb.Resource{Type: pkgK8s.Deployment, Name: "emoji"},
+					TimeWindow: "1m",
+				},
+				expectedError: "'to' filter is only supported along with a 'from' resource",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Pod},
+					},
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web"},
+					},
+					ToFilter:   &pb.Resource{Type: pkgK8s.All},
+					TimeWindow: "1m",
+				},
+				expectedError: "resource type 'all' is not supported as a filter",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Deployment, Name: "voting", Namespace: "emojivoto"},
+					},
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web"},
+					},
+					ToFilter:   &pb.Resource{Type: pkgK8s.Deployment, Name: "emoji", Namespace: "emojivoto"},
+					TimeWindow: "1m",
+				},
+				expectedError: "the 'to' resource deployment/emoji conflicts with the selected resource deployment/voting",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Pod, Namespace: "emojivoto"},
+					},
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web"},
+					},
+					ToFilter:   &pb.Resource{Type: pkgK8s.Deployment, Name: "emoji", Namespace: "emojivoto"},
+					TimeWindow: "1m",
+				},
+				expectedError: "",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Pod},
+					},
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web"},
+					},
+					TimeWindow: "1m",
+					Direction:  "outbound",
+				},
+				expectedError: "'to' and 'from' queries only count outbound requests, and don't support a direction",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Pod},
+					},
+					TimeWindow: "1m",
+					Direction:  "sideways",
+				},
+				expectedError: `invalid direction "sideways", must be inbound or outbound`,
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web-*"},
+					},
+					TimeWindow: "1m",
+				},
+				expectedError: "name patterns are only supported for authorities, not for deployment/web-*",
+			},
+			{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{Type: pkgK8s.Authority, Name: "~web-("},
+					},
+					TimeWindow: "1m",
+				},
+				expectedError: "invalid authority regex \"web-(\": error parsing regexp: missing closing ): `web-(`",
+			},
+		}
+
+		for i, tc := range testCases {
+			rsp, err := fakeGrpcServer.StatSummary(context.TODO(), &tc.req)
+			if err != nil {
+				t.Fatalf("test case %d: unexpected error: %s", i, err)
+			}
+
+			actualError := rsp.GetError().GetError()
+			if actualError != tc.expectedError {
+				t.Fatalf("test case %d: expected error [%s], got [%s]", i, tc.expectedError, actualError)
+			}
+		}
+	})
+
 	t.Run("Return empty stats summary response", func(t *testing.T) {
 		t.Run("when pod phase is succeeded or failed", func(t *testing.T) {
 			expectations := []statSumExpected{
@@ -1113,10 +1395,10 @@ status:
 `},
 						mockPromResponse: model.Vector{},
 						expectedPrometheusQueries: []string{
-							`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
-							`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
-							`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
-							`sum(increase(response_total{direction="inbound", namespace="emojivoto"}[])) by (namespace, pod, classification, tls)`,
+							`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, pod))`,
+							`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, pod))`,
+							`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, pod))`,
+							`sum(increase(response_total{direction="inbound", namespace="emojivoto"}[1m])) by (namespace, pod, classification, tls)`,
 						},
 					},
 					req: pb.StatSummaryRequest{
@@ -1126,6 +1408,7 @@ status:
 								Type:      pkgK8s.Pod,
 							},
 						},
+						TimeWindow: "1m",
 					},
 					expectedResponse: genEmptyResponse(),
 				},
@@ -1316,6 +1599,51 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the authorities matching a pattern", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: model.Vector{
+						genPromSample("web.emojivoto.svc.cluster.local:80", "authority", "emojivoto", "success", false),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{authority=~"web\\..*", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{authority=~"web\\..*", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{authority=~"web\\..*", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+						`sum(increase(response_total{authority=~"web\\..*", direction="inbound", namespace="emojivoto"}[1m])) by (namespace, authority, classification, tls)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Authority,
+							Name:      "web.*",
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: GenStatSummaryResponse("web.emojivoto.svc.cluster.local:80", pkgK8s.Authority, []string{"emojivoto"}, nil, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Queries prometheus for a named authority", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -1361,6 +1689,85 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the backends of traffic splits", func(t *testing.T) {
+		expectedResponse := GenStatSummaryResponse("web.emojivoto.svc.cluster.local", pkgK8s.TrafficSplit, []string{"emojivoto", "emojivoto"}, nil, false)
+		rows := expectedResponse.GetOk().StatTables[0].GetPodGroup().Rows
+		rows[0].TsStats = &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v1", Weight: 90}
+		rows[0].Stats = &pb.BasicStats{
+			SuccessCount:    123,
+			LatencyMsP50:    123,
+			LatencyMsP95:    123,
+			LatencyMsP99:    123,
+			TlsRequestCount: 123,
+		}
+		rows[1].TsStats = &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v2", Weight: 10}
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+  namespace: emojivoto
+spec:
+  dstOverrides:
+  - authority: web-v1.emojivoto.svc.cluster.local
+    weight: 90
+  - authority: web-v2.emojivoto.svc.cluster.local:8080
+    weight: 10
+`, `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: emoji.emojivoto.svc.cluster.local
+  namespace: emojivoto
+spec:
+  routes:
+  - name: GET /
+    condition:
+      method: GET
+      pathRegex: /
+`,
+					},
+					mockPromResponse: model.Vector{
+						&model.Sample{
+							Metric: model.Metric{
+								"dst_namespace":    "emojivoto",
+								"dst_service":      "web",
+								"dst_leaf_service": "web-v1",
+								"classification":   "success",
+								"tls":              "true",
+							},
+							Value:     123,
+							Timestamp: 456,
+						},
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, dst_namespace, dst_service, dst_leaf_service))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, dst_namespace, dst_service, dst_leaf_service))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto"}[1m])) by (le, dst_namespace, dst_service, dst_leaf_service))`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto"}[1m])) by (dst_namespace, dst_service, dst_leaf_service, classification, tls)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.TrafficSplit,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Stats returned are nil when SkipStats is true", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -1402,4 +1809,61 @@ status:
 
 		testStatSummary(t, expectations)
 	})
+
+	t.Run("Returns the queries of the stats when ShowQueries is true", func(t *testing.T) {
+		queries := map[string]string{
+			"latency_ms_p50": `histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+			"latency_ms_p95": `histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+			"latency_ms_p99": `histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+			"requests":       `sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+		}
+		expectedResponse := GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+			MeshedPods:  1,
+			RunningPods: 1,
+			FailedPods:  0,
+		}, true)
+		expectedResponse.GetOk().StatTables[0].GetPodGroup().Queries = queries
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						queries["latency_ms_p50"],
+						queries["latency_ms_p95"],
+						queries["latency_ms_p99"],
+						queries["requests"],
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow:  "1m",
+					ShowQueries: true,
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
 }
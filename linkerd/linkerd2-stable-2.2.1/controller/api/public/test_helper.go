@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/linkerd/linkerd2/controller/api/util"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
@@ -19,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // MockAPIClient satisfies the Public API's gRPC interfaces (public.APIClient).
@@ -29,10 +31,12 @@ type MockAPIClient struct {
 	ListServicesResponseToReturn   *pb.ListServicesResponse
 	StatSummaryResponseToReturn    *pb.StatSummaryResponse
 	TopRoutesResponseToReturn      *pb.TopRoutesResponse
+	EdgesResponseToReturn          *pb.EdgesResponse
 	SelfCheckResponseToReturn      *healthcheckPb.SelfCheckResponse
 	APITapClientToReturn           pb.Api_TapClient
 	APITapByResourceClientToReturn pb.Api_TapByResourceClient
 	EndpointsResponseToReturn      *discovery.EndpointsResponse
+	WatchEndpointsClientToReturn   discovery.Discovery_WatchEndpointsClient
 }
 
 // StatSummary provides a mock of a Public API method.
@@ -45,6 +49,11 @@ func (c *MockAPIClient) TopRoutes(ctx context.Context, in *pb.TopRoutesRequest,
 	return c.TopRoutesResponseToReturn, c.ErrorToReturn
 }
 
+// Edges provides a mock of a Public API method.
+func (c *MockAPIClient) Edges(ctx context.Context, in *pb.EdgesRequest, opts ...grpc.CallOption) (*pb.EdgesResponse, error) {
+	return c.EdgesResponseToReturn, c.ErrorToReturn
+}
+
 // Version provides a mock of a Public API method.
 func (c *MockAPIClient) Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
 	return c.VersionInfoToReturn, c.ErrorToReturn
@@ -80,6 +89,28 @@ func (c *MockAPIClient) Endpoints(ctx context.Context, in *discovery.EndpointsPa
 	return c.EndpointsResponseToReturn, c.ErrorToReturn
 }
 
+// WatchEndpoints provides a mock of a Discovery API method.
+func (c *MockAPIClient) WatchEndpoints(ctx context.Context, in *discovery.EndpointsParams, _ ...grpc.CallOption) (discovery.Discovery_WatchEndpointsClient, error) {
+	return c.WatchEndpointsClientToReturn, c.ErrorToReturn
+}
+
+// MockWatchEndpointsClient satisfies the Discovery_WatchEndpointsClient gRPC
+// interface.
+type MockWatchEndpointsClient struct {
+	UpdatesToReturn []*discovery.EndpointsUpdate
+	grpc.ClientStream
+}
+
+// Recv satisfies the Discovery_WatchEndpointsClient.Recv() gRPC method.
+func (c *MockWatchEndpointsClient) Recv() (*discovery.EndpointsUpdate, error) {
+	if len(c.UpdatesToReturn) == 0 {
+		return nil, io.EOF
+	}
+	var update *discovery.EndpointsUpdate
+	update, c.UpdatesToReturn = c.UpdatesToReturn[0], c.UpdatesToReturn[1:]
+	return update, nil
+}
+
 type mockAPITapClient struct {
 	TapEventsToReturn []pb.TapEvent
 	ErrorsToReturn    []error
@@ -106,9 +137,15 @@ func (a *mockAPITapClient) Recv() (*pb.TapEvent, error) {
 type MockAPITapByResourceClient struct {
 	TapEventsToReturn []pb.TapEvent
 	ErrorsToReturn    []error
+	HeaderToReturn    metadata.MD
 	grpc.ClientStream
 }
 
+// Header satisfies the TapByResourceClient.Header() gRPC method.
+func (a *MockAPITapByResourceClient) Header() (metadata.MD, error) {
+	return a.HeaderToReturn, nil
+}
+
 // Recv satisfies the TapByResourceClient.Recv() gRPC method.
 func (a *MockAPITapByResourceClient) Recv() (*pb.TapEvent, error) {
 	var eventPopped pb.TapEvent
@@ -297,6 +334,8 @@ func GenEndpointsResponse(identities []string) discovery.EndpointsResponse {
 								PodIP:           "1.2.3.4",
 								ResourceVersion: "1234",
 							},
+							Zone:   "us-east-1a",
+							Weight: 1,
 						},
 					},
 				},
@@ -329,6 +368,7 @@ func newMockGrpcServer(exp expectedStatRPC) (*mockProm, *grpcServer, error) {
 		"linkerd",
 		[]string{},
 		false,
+		util.DefaultTimeWindowBounds,
 	)
 
 	k8sAPI.Sync()
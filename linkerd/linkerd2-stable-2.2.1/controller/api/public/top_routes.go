@@ -4,24 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/linkerd/linkerd2/controller/api/util"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	api "github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgUtil "github.com/linkerd/linkerd2/pkg/util"
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
-	routeReqQuery             = "sum(increase(route_response_total%s[%s])) by (%s, dst, classification)"
-	actualRouteReqQuery       = "sum(increase(route_actual_response_total%s[%s])) by (%s, dst, classification)"
-	routeLatencyQuantileQuery = "histogram_quantile(%s, sum(irate(route_response_latency_ms_bucket%s[%s])) by (le, dst, %s))"
-	dstLabel                  = `dst=~"(%s)(:\\d+)?"`
 	// DefaultRouteName is the name to display for requests that don't match any routes.
 	DefaultRouteName = "[DEFAULT]"
 )
@@ -33,13 +33,17 @@ type dstAndRoute struct {
 
 type indexedTable = map[dstAndRoute]*pb.RouteTable_Row
 
+// indexedRoutes holds the routes of the rows of an indexedTable, whose
+// response classes are used to classify responses.
+type indexedRoutes = map[dstAndRoute]*sp.RouteSpec
+
 type resourceTable struct {
 	resource string
 	table    indexedTable
 }
 
 func (s *grpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest) (*pb.TopRoutesResponse, error) {
-	log.Debugf("TopRoutes request: %+v", req)
+	pkgUtil.ContextLogger(ctx).Debugf("TopRoutes request: %+v", req)
 
 	if s.singleNamespace {
 		return topRoutesError(req, "Routes are not available in single-namespace mode"), nil
@@ -50,6 +54,10 @@ func (s *grpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest) (*
 		return errRsp, nil
 	}
 
+	if err := s.timeWindowBounds.Validate(req.TimeWindow); err != nil {
+		return topRoutesError(req, err.Error()), nil
+	}
+
 	// TopRoutes will return one table for each resource object requested.
 	tables := make([]resourceTable, 0)
 	targetResource := req.GetSelector().GetResource()
@@ -61,7 +69,7 @@ func (s *grpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest) (*
 	}
 
 	// Non-authority resource
-	objects, err := s.k8sAPI.GetObjects(targetResource.Namespace, targetResource.Type, targetResource.Name)
+	objects, err := s.getObjects(ctx, targetResource)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +143,7 @@ func (s *grpcServer) topRoutesFor(ctx context.Context, req *pb.TopRoutesRequest,
 	} else {
 		// Non-authority resource.
 		// Lookup individual resource objects.
-		objects, err := s.k8sAPI.GetObjects(requestedResource.Namespace, requestedResource.Type, requestedResource.Name)
+		objects, err := s.getObjects(ctx, requestedResource)
 		if err != nil {
 			return nil, err
 		}
@@ -215,8 +223,22 @@ func (s *grpcServer) getProfilesForAuthority(authority string, clientNs string)
 
 		return profiles, nil
 	}
+
+	regex, err := util.AuthorityRegex(authority)
+	if err != nil {
+		return nil, err
+	}
+	if regex != "" {
+		return s.getProfilesMatchingAuthority(authority, regex, clientNs)
+	}
+
 	// Specific authority
 	p, err := s.k8sAPI.SP().Lister().ServiceProfiles(clientNs).Get(authority)
+	if apierrors.IsNotFound(err) {
+		// as in the proxy, the profile of an external authority may also live
+		// in the controller namespace
+		p, err = s.k8sAPI.SP().Lister().ServiceProfiles(s.controllerNamespace).Get(authority)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +247,33 @@ func (s *grpcServer) getProfilesForAuthority(authority string, clientNs string)
 	}, nil
 }
 
+// getProfilesMatchingAuthority returns the service profiles of the
+// authorities matching the regex of an authority pattern. As for a specific
+// authority, the profiles of the client namespace take precedence over the
+// ones of the controller namespace.
+func (s *grpcServer) getProfilesMatchingAuthority(authority, regex, clientNs string) (map[string]*sp.ServiceProfile, error) {
+	// PromQL regexes are fully anchored, so are the ones matched here
+	re := regexp.MustCompile("^(?:" + regex + ")$")
+
+	profiles := make(map[string]*sp.ServiceProfile)
+	for _, ns := range []string{s.controllerNamespace, clientNs} {
+		ps, err := s.k8sAPI.SP().Lister().ServiceProfiles(ns).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range ps {
+			if re.MatchString(p.Name) {
+				profiles[p.Name] = p
+			}
+		}
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("No ServiceProfiles found for authorities matching %s", authority)
+	}
+	return profiles, nil
+}
+
 func (s *grpcServer) getRouteMetrics(ctx context.Context, req *pb.TopRoutesRequest, profiles map[string]*sp.ServiceProfile, resource *pb.Resource) (indexedTable, error) {
 	timeWindow := req.TimeWindow
 
@@ -233,33 +282,34 @@ func (s *grpcServer) getRouteMetrics(ctx context.Context, req *pb.TopRoutesReque
 		dsts = append(dsts, p.GetName())
 	}
 
-	reqLabels, err := s.buildRouteLabels(req, dsts, resource)
+	query, err := newPromQuery(buildRouteLabels(req, resource), timeWindow, model.LabelNames{"rt_route"})
 	if err != nil {
 		return nil, err
 	}
-	groupBy := "rt_route"
-
-	queries := map[promType]string{
-		promRequests: routeReqQuery,
+	if len(dsts) > 0 {
+		query.matchValues("dst", dsts)
 	}
 
+	metrics := routeResponseMetrics
 	if req.GetOutbound() != nil && req.GetNone() == nil {
 		// If this req has an Outbound, then query the actual request counts as well.
-		queries[promActualRequests] = actualRouteReqQuery
+		metrics = actualRouteResponseMetrics
 	}
 
-	results, err := s.getPrometheusMetrics(ctx, queries, routeLatencyQuantileQuery, reqLabels, timeWindow, groupBy)
+	results, err := s.getPrometheusMetrics(ctx, metrics, query)
 	if err != nil {
 		return nil, err
 	}
 
 	table := make(indexedTable)
+	routes := make(indexedRoutes)
 	for service, profile := range profiles {
 		for _, route := range profile.Spec.Routes {
 			key := dstAndRoute{
 				dst:   profile.GetName(),
 				route: route.Name,
 			}
+			routes[key] = route
 			table[key] = &pb.RouteTable_Row{
 				Authority: service,
 				Route:     route.Name,
@@ -277,14 +327,65 @@ func (s *grpcServer) getRouteMetrics(ctx context.Context, req *pb.TopRoutesReque
 		}
 	}
 
-	err = processRouteMetrics(results, timeWindow, table)
+	err = processRouteMetrics(results, timeWindow, table, routes)
 	if err != nil {
 		return nil, err
 	}
+	for key, route := range routes {
+		if route.SLO != nil {
+			table[key].Slo = evaluateRouteSLO(route.SLO, table[key].Stats)
+		}
+	}
 	return table, nil
 }
 
-func (s *grpcServer) buildRouteLabels(req *pb.TopRoutesRequest, dsts []string, resource *pb.Resource) (string, error) {
+// evaluateRouteSLO returns whether the stats of a route meet its SLO, along
+// with the share of the error budget of its success rate objective they burn.
+// The objectives that aren't set are always met.
+func evaluateRouteSLO(spec *sp.RouteSLO, stats *pb.BasicStats) *pb.RouteTable_SLO {
+	slo := &pb.RouteTable_SLO{
+		SuccessRateObjective: spec.SuccessRate,
+		SuccessRateMet:       true,
+		LatencyMet:           true,
+	}
+
+	if spec.SuccessRate > 0 {
+		total := stats.GetSuccessCount() + stats.GetFailureCount()
+		if total > 0 {
+			failureRate := float64(stats.GetFailureCount()) / float64(total)
+			slo.ErrorBudgetBurn = failureRate / (1 - spec.SuccessRate)
+			slo.SuccessRateMet = slo.ErrorBudgetBurn <= 1
+		}
+	}
+
+	if spec.Latency != "" {
+		latency, err := time.ParseDuration(spec.Latency)
+		if err != nil {
+			log.Warnf("Invalid SLO latency %s: %s", spec.Latency, err)
+			return slo
+		}
+		slo.LatencyObjectiveMs = uint64(latency / time.Millisecond)
+		slo.LatencyPercentile = spec.LatencyPercentile
+		if slo.LatencyPercentile == 0 {
+			slo.LatencyPercentile = 99
+		}
+
+		var observed uint64
+		switch slo.LatencyPercentile {
+		case 50:
+			observed = stats.GetLatencyMsP50()
+		case 95:
+			observed = stats.GetLatencyMsP95()
+		default:
+			observed = stats.GetLatencyMsP99()
+		}
+		slo.LatencyMet = observed <= slo.LatencyObjectiveMs
+	}
+
+	return slo
+}
+
+func buildRouteLabels(req *pb.TopRoutesRequest, resource *pb.Resource) model.LabelSet {
 	// labels: the labels for the resource we want to query for
 	var labels model.LabelSet
 
@@ -293,28 +394,16 @@ func (s *grpcServer) buildRouteLabels(req *pb.TopRoutesRequest, dsts []string, r
 	case *pb.TopRoutesRequest_ToResource:
 		labels = labels.Merge(promQueryLabels(resource))
 		labels = labels.Merge(promDirectionLabels("outbound"))
-		return renderLabels(labels, dsts), nil
 
 	default:
 		labels = labels.Merge(promDirectionLabels("inbound"))
 		labels = labels.Merge(promQueryLabels(resource))
-		return renderLabels(labels, dsts), nil
 	}
-}
 
-func renderLabels(labels model.LabelSet, services []string) string {
-	pairs := make([]string, 0)
-	for k, v := range labels {
-		pairs = append(pairs, fmt.Sprintf("%s=%q", k, v))
-	}
-	if len(services) > 0 {
-		pairs = append(pairs, fmt.Sprintf(dstLabel, strings.Join(services, "|")))
-	}
-	sort.Strings(pairs)
-	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+	return labels
 }
 
-func processRouteMetrics(results []promResult, timeWindow string, table indexedTable) error {
+func processRouteMetrics(results []promResult, timeWindow string, table indexedTable, routes indexedRoutes) error {
 	samples := 0
 	for _, result := range results {
 		for _, sample := range result.vec {
@@ -336,14 +425,14 @@ func processRouteMetrics(results []promResult, timeWindow string, table indexedT
 
 			switch result.prom {
 			case promRequests:
-				switch string(sample.Metric[model.LabelName("classification")]) {
+				switch classifyRouteSample(sample, routes[key]) {
 				case "success":
 					table[key].Stats.SuccessCount += value
 				case "failure":
 					table[key].Stats.FailureCount += value
 				}
 			case promActualRequests:
-				switch string(sample.Metric[model.LabelName("classification")]) {
+				switch classifyRouteSample(sample, routes[key]) {
 				case "success":
 					table[key].Stats.ActualSuccessCount += value
 				case "failure":
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/prometheus/common/model"
@@ -227,10 +228,10 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{deployment="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksConfig,
 				},
@@ -263,10 +264,10 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksConfig,
 				},
@@ -299,10 +300,10 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{daemonset="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{daemonset="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{daemonset="books", direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksDSConfig,
 				},
@@ -332,10 +333,10 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{direction="inbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default", statefulset="books"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksSSConfig,
 				},
@@ -365,11 +366,11 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
-						`sum(increase(route_actual_response_total{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
+						`sum(increase(route_actual_response_total{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksConfig,
 				},
@@ -404,11 +405,11 @@ func TestTopRoutes(t *testing.T) {
 					err:              nil,
 					mockPromResponse: routesMetric([]string{"/a"}),
 					expectedPrometheusQueries: []string{
-						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
-						`sum(increase(route_response_total{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
-						`sum(increase(route_actual_response_total{deployment="books", direction="outbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
+						`sum(increase(route_actual_response_total{deployment="books", direction="outbound", dst=~"(books\\.default\\.svc\\.cluster\\.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification, status_code, grpc_status)`,
 					},
 					k8sConfigs: booksConfig,
 				},
@@ -435,3 +436,55 @@ func TestTopRoutes(t *testing.T) {
 		testTopRoutes(t, expectations)
 	})
 }
+
+func TestEvaluateRouteSLO(t *testing.T) {
+	stats := &pb.BasicStats{
+		SuccessCount: 990,
+		FailureCount: 10,
+		LatencyMsP50: 20,
+		LatencyMsP95: 150,
+		LatencyMsP99: 400,
+	}
+
+	testCases := []struct {
+		spec     *sp.RouteSLO
+		stats    *pb.BasicStats
+		expected *pb.RouteTable_SLO
+	}{
+		{
+			&sp.RouteSLO{SuccessRate: 0.98},
+			stats,
+			&pb.RouteTable_SLO{SuccessRateObjective: 0.98, SuccessRateMet: true, LatencyMet: true, ErrorBudgetBurn: 0.5},
+		},
+		{
+			&sp.RouteSLO{SuccessRate: 0.998},
+			stats,
+			&pb.RouteTable_SLO{SuccessRateObjective: 0.998, SuccessRateMet: false, LatencyMet: true, ErrorBudgetBurn: 5},
+		},
+		{
+			&sp.RouteSLO{Latency: "250ms"},
+			stats,
+			&pb.RouteTable_SLO{LatencyObjectiveMs: 250, LatencyPercentile: 99, SuccessRateMet: true, LatencyMet: false},
+		},
+		{
+			&sp.RouteSLO{Latency: "250ms", LatencyPercentile: 95},
+			stats,
+			&pb.RouteTable_SLO{LatencyObjectiveMs: 250, LatencyPercentile: 95, SuccessRateMet: true, LatencyMet: true},
+		},
+		{
+			// without requests, no error budget is burnt
+			&sp.RouteSLO{SuccessRate: 0.99},
+			&pb.BasicStats{},
+			&pb.RouteTable_SLO{SuccessRateObjective: 0.99, SuccessRateMet: true, LatencyMet: true},
+		},
+	}
+
+	for i, tc := range testCases {
+		slo := evaluateRouteSLO(tc.spec, tc.stats)
+		// the error budget burn is rounded to ignore floating point errors
+		slo.ErrorBudgetBurn = float64(int64(slo.ErrorBudgetBurn*1000+0.5)) / 1000
+		if !proto.Equal(slo, tc.expected) {
+			t.Fatalf("test case %d: expected %v, got %v", i, tc.expected, slo)
+		}
+	}
+}
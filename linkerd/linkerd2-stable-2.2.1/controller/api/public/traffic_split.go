@@ -0,0 +1,158 @@
+package public
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// tsKey identifies the traffic sent to one of the backends of a traffic split
+type tsKey struct {
+	Namespace string
+	Apex      string
+	Leaf      string
+}
+
+// trafficSplitResourceQuery returns a row for each backend of the
+// ServiceProfiles that split their service's traffic with dstOverrides, with
+// the stats of the traffic the backend actually received.
+func (s *grpcServer) trafficSplitResourceQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
+	profiles, err := s.getTrafficSplits(req.GetSelector().GetResource())
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+
+	var requestMetrics map[tsKey]*pb.BasicStats
+	var queries map[string]string
+	if !req.SkipStats {
+		requestMetrics, queries, err = s.getTrafficSplitMetrics(ctx, req)
+		if err != nil {
+			return resourceResult{res: nil, err: err}
+		}
+	}
+
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	for _, profile := range profiles {
+		apex := serviceNameFromAuthority(profile.Name)
+		for _, dst := range profile.Spec.DstOverrides {
+			leaf := serviceNameFromAuthority(dst.Authority)
+			key := tsKey{
+				Namespace: profile.Namespace,
+				Apex:      apex,
+				Leaf:      leaf,
+			}
+			rows = append(rows, &pb.StatTable_PodGroup_Row{
+				Resource: &pb.Resource{
+					Name:      profile.Name,
+					Namespace: profile.Namespace,
+					Type:      req.GetSelector().GetResource().GetType(),
+				},
+				TimeWindow: req.TimeWindow,
+				Stats:      requestMetrics[key],
+				TsStats: &pb.TrafficSplitStats{
+					Apex:   apex,
+					Leaf:   leaf,
+					Weight: dst.Weight,
+				},
+			})
+		}
+	}
+
+	rsp := pb.StatTable{
+		Table: &pb.StatTable_PodGroup_{
+			PodGroup: &pb.StatTable_PodGroup{
+				Rows:    rows,
+				Queries: shownQueries(req, queries),
+			},
+		},
+	}
+	return resourceResult{res: &rsp, err: nil}
+}
+
+// getTrafficSplits returns the ServiceProfiles selected by the resource that
+// have dstOverrides.
+func (s *grpcServer) getTrafficSplits(resource *pb.Resource) ([]*sp.ServiceProfile, error) {
+	var profiles []*sp.ServiceProfile
+	if resource.GetName() != "" {
+		profile, err := s.k8sAPI.SP().Lister().ServiceProfiles(resource.GetNamespace()).Get(resource.GetName())
+		if err != nil {
+			return nil, err
+		}
+		profiles = []*sp.ServiceProfile{profile}
+	} else {
+		var err error
+		profiles, err = s.k8sAPI.SP().Lister().ServiceProfiles(resource.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	splits := make([]*sp.ServiceProfile, 0)
+	for _, profile := range profiles {
+		if len(profile.Spec.DstOverrides) > 0 {
+			splits = append(splits, profile)
+		}
+	}
+	return splits, nil
+}
+
+// getTrafficSplitMetrics returns the stats of the outbound traffic sent to the
+// backends of traffic splits, which is labeled with the backend the
+// destination service sent it to, along with the queries they were computed
+// with, by stat.
+func (s *grpcServer) getTrafficSplitMetrics(ctx context.Context, req *pb.StatSummaryRequest) (map[tsKey]*pb.BasicStats, map[string]string, error) {
+	resource := req.GetSelector().GetResource()
+
+	reqLabels := promDirectionLabels("outbound")
+	if resource.GetNamespace() != "" {
+		reqLabels[dstNamespaceLabel] = model.LabelValue(resource.GetNamespace())
+	}
+	if resource.GetName() != "" {
+		reqLabels[dstServiceLabel] = model.LabelValue(serviceNameFromAuthority(resource.GetName()))
+	}
+	groupBy := model.LabelNames{dstNamespaceLabel, dstServiceLabel, dstLeafServiceLabel}
+
+	query, err := newPromQuery(reqLabels, req.TimeWindow, groupBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, err := s.getPrometheusMetrics(ctx, responseMetrics, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	basicStats := make(map[tsKey]*pb.BasicStats)
+	for _, result := range results {
+		for _, sample := range result.vec {
+			key := tsKey{
+				Namespace: string(sample.Metric[dstNamespaceLabel]),
+				Apex:      string(sample.Metric[dstServiceLabel]),
+				Leaf:      string(sample.Metric[dstLeafServiceLabel]),
+			}
+			if key.Leaf == "" {
+				continue
+			}
+
+			if basicStats[key] == nil {
+				basicStats[key] = &pb.BasicStats{}
+			}
+			addSampleToStats(basicStats[key], result.prom, sample)
+		}
+	}
+	return basicStats, promQueries(results), nil
+}
+
+// serviceNameFromAuthority returns the name of the service of an authority of
+// the form "service-name.namespace-name.svc.$zone[:port]".
+func serviceNameFromAuthority(authority string) string {
+	if host, _, err := net.SplitHostPort(authority); err == nil {
+		authority = host
+	}
+	return strings.Split(authority, ".")[0]
+}
@@ -3,11 +3,15 @@ package util
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"k8s.io/api/core/v1"
@@ -48,6 +52,16 @@ var (
 		k8s.Service,
 		k8s.StatefulSet,
 	}
+
+	// DefaultTimeWindowBounds are the bounds of the time windows of the
+	// metrics requests accepted by the public API by default. Shorter windows
+	// than the interval at which Prometheus scrapes the proxies have no
+	// samples, and longer windows than its retention only make for expensive
+	// queries.
+	DefaultTimeWindowBounds = TimeWindowBounds{
+		Min: 10 * time.Second,
+		Max: 6 * time.Hour,
+	}
 )
 
 // StatsBaseRequestParams contains parameters that are used to build requests
@@ -71,6 +85,8 @@ type StatsSummaryRequestParams struct {
 	FromType      string
 	FromName      string
 	SkipStats     bool
+	Direction     string
+	ShowQueries   bool
 }
 
 // TopRoutesRequestParams contains parameters that are used to build TopRoutes
@@ -90,12 +106,43 @@ type TapRequestParams struct {
 	ToResource  string
 	ToNamespace string
 	MaxRps      float32
+	MaxPods     uint32
+	Pods        []string
 	Scheme      string
 	Method      string
 	Authority   string
 	Path        string
 }
 
+// TimeWindowBounds are the bounds of the time windows of the metrics requests
+// accepted by the public API.
+type TimeWindowBounds struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Validate returns a TimeWindowError if the time window isn't a valid
+// Prometheus duration, or is out of bounds.
+func (b TimeWindowBounds) Validate(timeWindow string) error {
+	window, err := model.ParseDuration(timeWindow)
+	if err != nil || time.Duration(window) < b.Min || time.Duration(window) > b.Max {
+		return TimeWindowError{TimeWindow: timeWindow, Bounds: b}
+	}
+	return nil
+}
+
+// TimeWindowError is returned for metrics requests whose time window isn't
+// accepted by the public API. Its message lists the accepted time windows.
+type TimeWindowError struct {
+	TimeWindow string
+	Bounds     TimeWindowBounds
+}
+
+func (e TimeWindowError) Error() string {
+	return fmt.Sprintf("invalid time window \"%s\": must be a duration between %s and %s, for example \"1m\"",
+		e.TimeWindow, model.Duration(e.Bounds.Min), model.Duration(e.Bounds.Max))
+}
+
 // GRPCError generates a gRPC error code, as defined in
 // google.golang.org/grpc/status.
 // If the error is nil or already a gRPC error, return the error.
@@ -164,13 +211,24 @@ func BuildStatSummaryRequest(p StatsSummaryRequestParams) (*pb.StatSummaryReques
 				Type:      resourceType,
 			},
 		},
-		TimeWindow: window,
-		SkipStats:  p.SkipStats,
+		TimeWindow:  window,
+		SkipStats:   p.SkipStats,
+		Direction:   p.Direction,
+		ShowQueries: p.ShowQueries,
+	}
+
+	// the to and from resources are looked up in the namespace of the request
+	// by default, including when the stats are requested across all
+	// namespaces
+	lookupNamespace := p.Namespace
+	if lookupNamespace == "" {
+		lookupNamespace = v1.NamespaceDefault
 	}
 
+	var toFilter *pb.Resource
 	if p.ToName != "" || p.ToType != "" || p.ToNamespace != "" {
 		if p.ToNamespace == "" {
-			p.ToNamespace = targetNamespace
+			p.ToNamespace = lookupNamespace
 		}
 		if p.ToType == "" {
 			p.ToType = resourceType
@@ -181,19 +239,20 @@ func BuildStatSummaryRequest(p StatsSummaryRequestParams) (*pb.StatSummaryReques
 			return nil, err
 		}
 
+		toFilter = &pb.Resource{
+			Namespace: p.ToNamespace,
+			Type:      toType,
+			Name:      p.ToName,
+		}
 		toResource := pb.StatSummaryRequest_ToResource{
-			ToResource: &pb.Resource{
-				Namespace: p.ToNamespace,
-				Type:      toType,
-				Name:      p.ToName,
-			},
+			ToResource: toFilter,
 		}
 		statRequest.Outbound = &toResource
 	}
 
 	if p.FromName != "" || p.FromType != "" || p.FromNamespace != "" {
 		if p.FromNamespace == "" {
-			p.FromNamespace = targetNamespace
+			p.FromNamespace = lookupNamespace
 		}
 		if p.FromType == "" {
 			p.FromType = resourceType
@@ -212,6 +271,10 @@ func BuildStatSummaryRequest(p StatsSummaryRequestParams) (*pb.StatSummaryReques
 			},
 		}
 		statRequest.Outbound = &fromResource
+
+		// With both a from and a to resource, the stats are the ones of the
+		// requests the from resource sends to the to resource.
+		statRequest.ToFilter = toFilter
 	}
 
 	return statRequest, nil
@@ -286,6 +349,46 @@ func BuildTopRoutesRequest(p TopRoutesRequestParams) (*pb.TopRoutesRequest, erro
 	return topRoutesRequest, nil
 }
 
+// BuildEdgesRequest builds a Public API EdgesRequest from a
+// StatsBaseRequestParams.
+func BuildEdgesRequest(p StatsBaseRequestParams) (*pb.EdgesRequest, error) {
+	window := defaultMetricTimeWindow
+	if p.TimeWindow != "" {
+		_, err := time.ParseDuration(p.TimeWindow)
+		if err != nil {
+			return nil, err
+		}
+		window = p.TimeWindow
+	}
+
+	if p.AllNamespaces && p.ResourceName != "" {
+		return nil, errors.New("edges for a resource cannot be retrieved by name across all namespaces")
+	}
+
+	targetNamespace := p.Namespace
+	if p.AllNamespaces {
+		targetNamespace = ""
+	} else if p.Namespace == "" {
+		targetNamespace = v1.NamespaceDefault
+	}
+
+	resourceType, err := k8s.CanonicalResourceNameFromFriendlyName(p.ResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EdgesRequest{
+		Selector: &pb.ResourceSelection{
+			Resource: &pb.Resource{
+				Namespace: targetNamespace,
+				Name:      p.ResourceName,
+				Type:      resourceType,
+			},
+		},
+		TimeWindow: window,
+	}, nil
+}
+
 // An authority can only receive traffic, not send it, so it can't be a --from
 func validateFromResourceType(resourceType string) (string, error) {
 	name, err := k8s.CanonicalResourceNameFromFriendlyName(resourceType)
@@ -298,6 +401,32 @@ func validateFromResourceType(resourceType string) (string, error) {
 	return name, nil
 }
 
+// AuthorityRegex returns the regex of the authorities an authority name
+// matches when it's a pattern: either "~" followed by a regex, or a wildcard
+// where each "*" matches any characters, such as "web.*". It returns an empty
+// string for the other names, which match a single authority exactly.
+func AuthorityRegex(name string) (string, error) {
+	if strings.HasPrefix(name, "~") {
+		regex := strings.TrimPrefix(name, "~")
+		if regex == "" {
+			return "", errors.New("authority regex can't be empty")
+		}
+		if _, err := regexp.Compile(regex); err != nil {
+			return "", fmt.Errorf("invalid authority regex %q: %s", regex, err)
+		}
+		return regex, nil
+	}
+
+	if !strings.Contains(name, "*") {
+		return "", nil
+	}
+	parts := strings.Split(name, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, ".*"), nil
+}
+
 // BuildResource parses input strings, typically from CLI flags, to build a
 // Resource object for use in the protobuf API.
 // It's the same as BuildResources but only admits one arg and only returns one resource
@@ -457,7 +586,9 @@ func BuildTapByResourceRequest(params TapRequestParams) (*pb.TapByResourceReques
 		Target: &pb.ResourceSelection{
 			Resource: &target,
 		},
-		MaxRps: params.MaxRps,
+		MaxRps:  params.MaxRps,
+		MaxPods: params.MaxPods,
+		Pods:    params.Pods,
 		Match: &pb.TapByResourceRequest_Match{
 			Match: &pb.TapByResourceRequest_Match_All{
 				All: &pb.TapByResourceRequest_Match_Seq{
@@ -523,17 +654,25 @@ func K8sPodToPublicPod(pod v1.Pod, ownerKind string, ownerName string) pb.Pod {
 	controllerNS := pod.Labels[k8s.ControllerNSLabel]
 
 	proxyReady := false
+	var proxyRestartCount uint32
+	var proxyUptime *duration.Duration
 	for _, container := range pod.Status.ContainerStatuses {
 		if container.Name == k8s.ProxyContainerName {
 			proxyReady = container.Ready
+			proxyRestartCount = uint32(container.RestartCount)
+			if running := container.State.Running; running != nil {
+				proxyUptime = ptypes.DurationProto(time.Since(running.StartedAt.Time))
+			}
 		}
 	}
 
 	proxyVersion := ""
+	var proxyResources *pb.Pod_ProxyResources
 	for _, container := range pod.Spec.Containers {
 		if container.Name == k8s.ProxyContainerName {
 			parts := strings.Split(container.Image, ":")
 			proxyVersion = parts[1]
+			proxyResources = toProxyResources(container.Resources)
 		}
 	}
 
@@ -546,6 +685,9 @@ func K8sPodToPublicPod(pod v1.Pod, ownerKind string, ownerName string) pb.Pod {
 		ProxyReady:          proxyReady,
 		ProxyVersion:        proxyVersion,
 		ResourceVersion:     pod.ResourceVersion,
+		ProxyRestartCount:   proxyRestartCount,
+		ProxyUptime:         proxyUptime,
+		ProxyResources:      proxyResources,
 	}
 
 	namespacedOwnerName := pod.Namespace + "/" + ownerName
@@ -567,3 +709,24 @@ func K8sPodToPublicPod(pod v1.Pod, ownerKind string, ownerName string) pb.Pod {
 
 	return item
 }
+
+// toProxyResources returns the resource requests and limits of the proxy
+// container, or nil if it has none.
+func toProxyResources(resources v1.ResourceRequirements) *pb.Pod_ProxyResources {
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return nil
+	}
+
+	quantity := func(list v1.ResourceList, name v1.ResourceName) string {
+		if q, ok := list[name]; ok {
+			return q.String()
+		}
+		return ""
+	}
+	return &pb.Pod_ProxyResources{
+		CpuRequest:    quantity(resources.Requests, v1.ResourceCPU),
+		CpuLimit:      quantity(resources.Limits, v1.ResourceCPU),
+		MemoryRequest: quantity(resources.Requests, v1.ResourceMemory),
+		MemoryLimit:   quantity(resources.Limits, v1.ResourceMemory),
+	}
+}
@@ -2,7 +2,9 @@ package util
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -11,6 +13,7 @@ import (
 	"google.golang.org/grpc/status"
 	"k8s.io/api/core/v1"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -190,6 +193,112 @@ func TestBuildTopRoutesRequest(t *testing.T) {
 	})
 }
 
+func TestBuildEdgesRequest(t *testing.T) {
+	testCases := []struct {
+		params            StatsBaseRequestParams
+		expectedNamespace string
+		expectedType      string
+		expectedErr       string
+	}{
+		{
+			params:            StatsBaseRequestParams{ResourceType: "deploy"},
+			expectedNamespace: "default",
+			expectedType:      k8s.Deployment,
+		},
+		{
+			params:            StatsBaseRequestParams{ResourceType: "po", Namespace: "emojivoto", AllNamespaces: true},
+			expectedNamespace: "",
+			expectedType:      k8s.Pod,
+		},
+		{
+			params:      StatsBaseRequestParams{ResourceType: "deploy", ResourceName: "web", AllNamespaces: true},
+			expectedErr: "edges for a resource cannot be retrieved by name across all namespaces",
+		},
+		{
+			params:      StatsBaseRequestParams{ResourceType: "deploy", TimeWindow: "1"},
+			expectedErr: "time: missing unit in duration",
+		},
+	}
+
+	for i, tc := range testCases {
+		req, err := BuildEdgesRequest(tc.params)
+		if tc.expectedErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedErr) {
+				t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		resource := req.GetSelector().GetResource()
+		if resource.GetNamespace() != tc.expectedNamespace || resource.GetType() != tc.expectedType {
+			t.Fatalf("test case %d: expected %s in namespace [%s], got %s in namespace [%s]", i, tc.expectedType, tc.expectedNamespace, resource.GetType(), resource.GetNamespace())
+		}
+		if req.GetTimeWindow() != "1m" {
+			t.Fatalf("test case %d: expected the default time window, got %s", i, req.GetTimeWindow())
+		}
+	}
+}
+
+func TestTimeWindowBounds(t *testing.T) {
+	testCases := []struct {
+		timeWindow string
+		valid      bool
+	}{
+		{"10s", true},
+		{"1m", true},
+		{"6h", true},
+		{"1s", false},
+		{"30d", false},
+		{"1m30s", false},
+		{"", false},
+	}
+
+	for i, tc := range testCases {
+		err := DefaultTimeWindowBounds.Validate(tc.timeWindow)
+		if tc.valid {
+			if err != nil {
+				t.Fatalf("test case %d: unexpected error: %s", i, err)
+			}
+			continue
+		}
+
+		if _, ok := err.(TimeWindowError); !ok {
+			t.Fatalf("test case %d: expected a TimeWindowError, got [%v]", i, err)
+		}
+		expected := fmt.Sprintf("invalid time window \"%s\": must be a duration between 10s and 6h, for example \"1m\"", tc.timeWindow)
+		if err.Error() != expected {
+			t.Fatalf("test case %d: expected error [%s], got [%s]", i, expected, err)
+		}
+	}
+}
+
+func TestAuthorityRegex(t *testing.T) {
+	testCases := []struct {
+		name  string
+		regex string
+		err   bool
+	}{
+		{"web.emojivoto.svc.cluster.local:80", "", false},
+		{"web.*", `web\..*`, false},
+		{"*.emojivoto.svc.cluster.local", `.*\.emojivoto\.svc\.cluster\.local`, false},
+		{"~web-v[12]\\..*", `web-v[12]\..*`, false},
+		{"~web-(", "", true},
+		{"~", "", true},
+	}
+
+	for i, tc := range testCases {
+		regex, err := AuthorityRegex(tc.name)
+		if tc.err != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got [%v]", i, tc.err, err)
+		}
+		if regex != tc.regex {
+			t.Fatalf("test case %d: expected regex [%s], got [%s]", i, tc.regex, regex)
+		}
+	}
+}
+
 func TestBuildResource(t *testing.T) {
 	type resourceExp struct {
 		namespace string
@@ -432,6 +541,15 @@ func TestK8sPodToPublicPod(t *testing.T) {
 							v1.Container{
 								Name:  k8s.ProxyContainerName,
 								Image: "linkerd-proxy:test-version",
+								Resources: v1.ResourceRequirements{
+									Requests: v1.ResourceList{
+										v1.ResourceCPU:    resource.MustParse("100m"),
+										v1.ResourceMemory: resource.MustParse("20Mi"),
+									},
+									Limits: v1.ResourceList{
+										v1.ResourceMemory: resource.MustParse("250Mi"),
+									},
+								},
 							},
 						},
 					},
@@ -440,8 +558,9 @@ func TestK8sPodToPublicPod(t *testing.T) {
 						Phase: "status",
 						ContainerStatuses: []v1.ContainerStatus{
 							v1.ContainerStatus{
-								Name:  k8s.ProxyContainerName,
-								Ready: true,
+								Name:         k8s.ProxyContainerName,
+								Ready:        true,
+								RestartCount: 2,
 							},
 						},
 					},
@@ -457,7 +576,13 @@ func TestK8sPodToPublicPod(t *testing.T) {
 					Status:              "status",
 					ProxyReady:          true,
 					ProxyVersion:        "test-version",
-					PodIP:               "pod-ip",
+					ProxyRestartCount:   2,
+					ProxyResources: &pb.Pod_ProxyResources{
+						CpuRequest:    "100m",
+						MemoryRequest: "20Mi",
+						MemoryLimit:   "250Mi",
+					},
+					PodIP: "pod-ip",
 				},
 			},
 		}
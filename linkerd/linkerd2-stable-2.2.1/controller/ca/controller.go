@@ -1,8 +1,10 @@
 package ca
 
 import (
+	"encoding/pem"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -12,6 +14,7 @@ import (
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -23,9 +26,15 @@ import (
 type CertificateController struct {
 	namespace   string
 	k8sAPI      *k8s.API
-	ca          *tls.CA
 	syncHandler func(key string) error
 
+	// ca issues the certificates, and trustAnchors is the PEM bundle
+	// published to the meshed namespaces to verify them. They're replaced
+	// when the issuer is rotated.
+	sync.RWMutex
+	ca           *tls.CA
+	trustAnchors string
+
 	// The queue is keyed on a string. If the string doesn't contain any dots
 	// then it is a namespace name and the task is to create the CA bundle
 	// configmap in that namespace. Otherwise the string must be of the form
@@ -41,11 +50,18 @@ func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API) (*Cer
 	if err != nil {
 		return nil, err
 	}
+	return NewCertificateControllerWithCA(controllerNamespace, k8sAPI, ca)
+}
 
+// NewCertificateControllerWithCA initializes a CertificateController that
+// issues certificates with the given CA, e.g. one with an operator-provided
+// issuer.
+func NewCertificateControllerWithCA(controllerNamespace string, k8sAPI *k8s.API, ca *tls.CA) (*CertificateController, error) {
 	c := &CertificateController{
-		namespace: controllerNamespace,
-		k8sAPI:    k8sAPI,
-		ca:        ca,
+		namespace:    controllerNamespace,
+		k8sAPI:       k8sAPI,
+		ca:           ca,
+		trustAnchors: ca.TrustAnchorPEM(),
 		queue: workqueue.NewNamedRateLimitingQueue(
 			workqueue.DefaultControllerRateLimiter(), "certificates"),
 	}
@@ -75,6 +91,32 @@ func (c *CertificateController) Run(stopCh <-chan struct{}) {
 	<-stopCh
 }
 
+// SetCA replaces the CA of the controller, e.g. when its issuer is rotated,
+// and re-issues the certificates of all the meshed pods. The previous issuer
+// stays trusted until it expires, so that the pods with certificates it
+// issued keep talking to the others until they pick up their new ones.
+func (c *CertificateController) SetCA(ca *tls.CA) {
+	c.Lock()
+	trustAnchors := ca.TrustAnchorPEM()
+	if previous := c.ca.Issuer(); previous.NotAfter.After(time.Now()) && !previous.Equal(ca.Issuer()) {
+		trustAnchors = strings.TrimSpace(trustAnchors) + "\n" +
+			string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: previous.Raw}))
+	}
+	c.ca = ca
+	c.trustAnchors = trustAnchors
+	c.Unlock()
+
+	pods, err := c.k8sAPI.Pod().Lister().List(labels.Everything())
+	if err != nil {
+		log.Errorf("failed to list the pods to re-issue their certificates: %s", err)
+		return
+	}
+	log.Infof("re-issuing the certificates of the meshed pods with issuer %s", ca.Issuer().Subject)
+	for _, pod := range pods {
+		c.handlePodAdd(pod)
+	}
+}
+
 func (c *CertificateController) worker() {
 	for c.processNextWorkItem() {
 	}
@@ -111,7 +153,7 @@ func (c *CertificateController) syncNamespace(ns string) error {
 	configMap := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Name: pkgK8s.TLSTrustAnchorConfigMapName},
 		Data: map[string]string{
-			pkgK8s.TLSTrustAnchorFileName: c.ca.TrustAnchorPEM(),
+			pkgK8s.TLSTrustAnchorFileName: c.getTrustAnchors(),
 		},
 	}
 
@@ -141,7 +183,7 @@ func (c *CertificateController) syncSecret(key string) error {
 
 	dnsName := identity.ToDNSName()
 	secretName := identity.ToSecretName()
-	certAndPrivateKey, err := c.ca.IssueEndEntityCertificate(dnsName)
+	certAndPrivateKey, err := c.issue(dnsName)
 	if err != nil {
 		log.Errorf("Failed to issue certificate for %s", dnsName)
 		return err
@@ -161,6 +203,20 @@ func (c *CertificateController) syncSecret(key string) error {
 	return err
 }
 
+func (c *CertificateController) getTrustAnchors() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.trustAnchors
+}
+
+// issue issues a certificate for dnsName. The lock is exclusive because the
+// CA doesn't support issuing certificates concurrently.
+func (c *CertificateController) issue(dnsName string) (*tls.CertificateAndPrivateKey, error) {
+	c.Lock()
+	defer c.Unlock()
+	return c.ca.IssueEndEntityCertificate(dnsName)
+}
+
 func (c *CertificateController) handlePodAdd(obj interface{}) {
 	pod := obj.(*v1.Pod)
 	if pkgK8s.IsMeshed(pod, c.namespace) {
@@ -0,0 +1,79 @@
+package ca
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// IssuerPollInterval is how often the mounted issuer secret is checked for a
+// rotated issuer.
+const IssuerPollInterval = time.Minute
+
+// LoadIssuer returns a CA that issues certificates with the operator-provided
+// issuer in dir, where a kubernetes.io/tls secret is mounted, e.g. one written
+// by cert-manager.
+func LoadIssuer(dir string) (*tls.CA, error) {
+	certPEM, keyPEM, trustAnchorsPEM, err := readIssuer(dir)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewCAFromPEM(certPEM, keyPEM, trustAnchorsPEM)
+}
+
+// WatchIssuer checks the issuer in dir every interval, and replaces the CA of
+// the controller whenever it's rotated, until stop is closed. An invalid
+// issuer is logged, and the current CA is kept.
+func (c *CertificateController) WatchIssuer(dir string, interval time.Duration, stop <-chan struct{}) {
+	var last []byte
+	if certPEM, keyPEM, trustAnchorsPEM, err := readIssuer(dir); err == nil {
+		last = bytes.Join([][]byte{certPEM, keyPEM, trustAnchorsPEM}, nil)
+	}
+
+	wait.Until(func() {
+		certPEM, keyPEM, trustAnchorsPEM, err := readIssuer(dir)
+		if err != nil {
+			log.Errorf("failed to read the issuer: %s", err)
+			return
+		}
+		current := bytes.Join([][]byte{certPEM, keyPEM, trustAnchorsPEM}, nil)
+		if bytes.Equal(current, last) {
+			return
+		}
+
+		ca, err := tls.NewCAFromPEM(certPEM, keyPEM, trustAnchorsPEM)
+		if err != nil {
+			log.Errorf("ignoring the rotated issuer: %s", err)
+			return
+		}
+		last = current
+
+		log.Infof("the issuer was rotated, it's now valid until %s", ca.Issuer().NotAfter)
+		c.SetCA(ca)
+	}, interval, stop)
+}
+
+func readIssuer(dir string) (certPEM, keyPEM, trustAnchorsPEM []byte, err error) {
+	certPEM, err = ioutil.ReadFile(filepath.Join(dir, pkgK8s.IssuerCertFileName))
+	if err != nil {
+		return
+	}
+	keyPEM, err = ioutil.ReadFile(filepath.Join(dir, pkgK8s.IssuerPrivateKeyFileName))
+	if err != nil {
+		return
+	}
+	// the trust anchors are optional, e.g. cert-manager's CA issuers set
+	// them, but not its self-signed ones
+	trustAnchorsPEM, err = ioutil.ReadFile(filepath.Join(dir, pkgK8s.IssuerTrustAnchorsFileName))
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
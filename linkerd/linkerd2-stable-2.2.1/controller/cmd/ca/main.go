@@ -2,15 +2,13 @@ package main
 
 import (
 	"flag"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/linkerd/linkerd2/controller/ca"
 	"github.com/linkerd/linkerd2/controller/k8s"
-	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/linkerd/linkerd2/pkg/runner"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func main() {
@@ -18,41 +16,56 @@ func main() {
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	podSelector := flag.String("pod-selector", "", "label selector of the pods to cache, e.g. \"linkerd.io/control-plane-ns\" for meshed pods only; all pods if empty")
+	issuerDir := flag.String("issuer-dir", "", "directory where an operator-provided issuer secret is mounted, with its tls.crt, tls.key and optional ca.crt; a self-signed CA is generated if empty")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
 	flags.ConfigureAndParse()
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	if _, err := labels.Parse(*podSelector); err != nil {
+		log.Fatalf("invalid pod selector: %s", err)
+	}
 
 	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	restrictToNamespace := ""
+	options := k8s.APIOptions{PodSelector: *podSelector}
 	if *singleNamespace {
-		restrictToNamespace = *controllerNamespace
+		options.Namespaces = []string{*controllerNamespace}
 	}
 
-	k8sAPI := k8s.NewAPI(k8sClient, nil, restrictToNamespace, k8s.Pod, k8s.RS)
+	k8sAPI := k8s.NewAPIWithOptions(k8sClient, nil, options, k8s.Job, k8s.Pod, k8s.RS)
 
-	controller, err := ca.NewCertificateController(*controllerNamespace, k8sAPI)
+	var controller *ca.CertificateController
+	if *issuerDir != "" {
+		issuer, err := ca.LoadIssuer(*issuerDir)
+		if err != nil {
+			log.Fatalf("Failed to load the issuer: %s", err)
+		}
+		log.Infof("issuing certificates with %s, valid until %s", issuer.Issuer().Subject, issuer.Issuer().NotAfter)
+		controller, err = ca.NewCertificateControllerWithCA(*controllerNamespace, k8sAPI, issuer)
+	} else {
+		controller, err = ca.NewCertificateController(*controllerNamespace, k8sAPI)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create CertificateController: %v", err)
 	}
 
-	stopCh := make(chan struct{})
-
-	k8sAPI.Sync() // blocks until caches are synced
-
-	go func() {
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.OnSync(func() { k8sAPI.Sync() })
+	r.ReadyCheck(func() error {
+		return k8sAPI.CheckHealth(k8s.DefaultMaxStaleness)
+	})
+	r.Go(func(stop <-chan struct{}) {
 		log.Info("starting CA")
-		controller.Run(stopCh)
-	}()
-
-	go admin.StartServer(*metricsAddr)
-
-	<-stop
-
-	log.Info("shutting down")
-	close(stopCh)
+		controller.Run(stop)
+	})
+	if *issuerDir != "" {
+		r.Go(func(stop <-chan struct{}) {
+			controller.WatchIssuer(*issuerDir, ca.IssuerPollInterval, stop)
+		})
+	}
+	r.Run()
 }
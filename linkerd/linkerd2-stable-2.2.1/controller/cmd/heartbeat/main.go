@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/heartbeat"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/flags"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	uuid := flag.String("uuid", "", "identifier of the installation of the control plane, sent with the statistics")
+	endpoint := flag.String("endpoint", "", "URL the statistics are posted to")
+	timeout := flag.Duration("timeout", 30*time.Second, "time given to the endpoint to accept the statistics")
+	flags.ConfigureAndParse()
+
+	if *endpoint == "" {
+		log.Fatal("the -endpoint flag is required")
+	}
+
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	report, err := heartbeat.Collect(k8sClient, *controllerNamespace, *uuid)
+	if err != nil {
+		log.Fatalf("Failed to collect the mesh statistics: %s", err)
+	}
+	log.Infof("collected mesh statistics: %+v", *report)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := heartbeat.Send(ctx, http.DefaultClient, *endpoint, report); err != nil {
+		log.Fatalf("Failed to send the heartbeat to %s: %s", *endpoint, err)
+	}
+	log.Infof("sent the heartbeat to %s", *endpoint)
+}
@@ -2,16 +2,15 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"net"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/linkerd/linkerd2/controller/api/proxy"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	"github.com/linkerd/linkerd2/controller/k8s"
-	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/linkerd/linkerd2/pkg/runner"
+	"github.com/linkerd/linkerd2/pkg/trace"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -24,35 +23,51 @@ func main() {
 	enableTLS := flag.Bool("enable-tls", false, "Enable TLS connections among pods in the service mesh")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	topologyWeighting := flag.String("topology-weighting", proxy.TopologyWeightingNone,
+		fmt.Sprintf("how to weigh endpoints according to the zone of their node; one of: %s, %s (ignored in single namespace mode)",
+			proxy.TopologyWeightingNone, proxy.TopologyWeightingPreferSameZone))
+	maxServicePorts := flag.Int("max-watched-service-ports", 0, "maximum number of service ports to watch, including idle ones; requests for other service ports fail once it's reached (0 for no limit)")
+	maxIdleServicePorts := flag.Int("max-idle-service-ports", 0, "number of service ports without subscribers that stay watched for reuse, evicting the least recently used ones first")
+	endpointsDebounce := flag.Duration("endpoints-debounce", 0, "window during which the changes to the endpoints of a service are batched into a single update to the proxies, e.g. during rollouts (0 to send each change right away)")
+	clientFlags := k8s.NewClientFlags()
+	informerResync := flag.Duration("informer-resync", k8s.DefaultResync, "period at which the informers resync their caches")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
+	traceFlags := trace.NewFlags()
 	flags.ConfigureAndParse()
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	stopTracing, err := traceFlags.Init("linkerd-destination")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer stopTracing()
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
 	var spClient *spclient.Clientset
-	restrictToNamespace := ""
-	resources := []k8s.APIResource{k8s.Endpoint, k8s.Pod, k8s.RS, k8s.Svc}
+	restrictToNamespaces := []string{}
+	resources := []k8s.APIResource{k8s.Endpoint, k8s.Job, k8s.Pod, k8s.RS, k8s.Svc}
 
 	if *singleNamespace {
-		restrictToNamespace = *controllerNamespace
+		restrictToNamespaces = []string{*controllerNamespace}
 	} else {
-		spClient, err = k8s.NewSpClientSet(*kubeConfigPath)
+		spClient, err = k8s.NewSpClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
 		if err != nil {
 			log.Fatal(err.Error())
 		}
 
-		resources = append(resources, k8s.SP)
+		resources = append(resources, k8s.SP, k8s.Node)
 	}
 
-	k8sAPI := k8s.NewAPI(
+	k8sAPI := k8s.NewAPIWithOptions(
 		k8sClient,
 		spClient,
-		restrictToNamespace,
+		k8s.APIOptions{
+			Namespaces: restrictToNamespaces,
+			Resync:     *informerResync,
+		},
 		resources...,
 	)
 
@@ -63,23 +78,26 @@ func main() {
 		log.Fatalf("Failed to listen on %s: %s", *addr, err)
 	}
 
-	server, err := proxy.NewServer(*addr, *k8sDNSZone, *controllerNamespace, *enableTLS, *enableH2Upgrade, *singleNamespace, k8sAPI, done)
+	if *singleNamespace {
+		*topologyWeighting = proxy.TopologyWeightingNone
+	}
+
+	server, err := proxy.NewServer(*addr, *k8sDNSZone, *controllerNamespace, *enableTLS, *enableH2Upgrade, *singleNamespace, *topologyWeighting, *maxServicePorts, *maxIdleServicePorts, *endpointsDebounce, k8sAPI, done)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	k8sAPI.Sync() // blocks until caches are synced
-
-	go func() {
-		log.Infof("starting gRPC server on %s", *addr)
-		server.Serve(lis)
-	}()
-
-	go admin.StartServer(*metricsAddr)
-
-	<-stop
-
-	log.Infof("shutting down gRPC server on %s", *addr)
-	close(done)
-	server.GracefulStop()
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.OnSync(func() { k8sAPI.Sync() })
+	r.ReadyCheck(func() error {
+		return k8sAPI.CheckHealth(k8s.DefaultMaxStaleness)
+	})
+	// end the open streams before the gRPC server drains
+	r.Go(func(stop <-chan struct{}) {
+		<-stop
+		close(done)
+	})
+	r.AddGRPCServer("gRPC server on "+*addr, server, lis)
+	r.Run()
 }
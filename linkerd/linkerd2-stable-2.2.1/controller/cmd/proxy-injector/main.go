@@ -3,16 +3,15 @@ package main
 import (
 	"flag"
 	"net/http"
-	"os"
-	"os/signal"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
 	injector "github.com/linkerd/linkerd2/controller/proxy-injector"
-	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/controller/webhook"
 	"github.com/linkerd/linkerd2/pkg/flags"
 	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
-	"github.com/linkerd/linkerd2/pkg/tls"
+	"github.com/linkerd/linkerd2/pkg/runner"
 	log "github.com/sirupsen/logrus"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 )
 
 func main() {
@@ -23,59 +22,51 @@ func main() {
 	webhookServiceName := flag.String("webhook-service", "linkerd-proxy-injector.linkerd.io", "name of the admission webhook")
 	noInitContainer := flag.Bool("no-init-container", false, "whether to use an init container or the linkerd-cni plugin")
 	tlsEnabled := flag.Bool("tls-enabled", false, "whether the control plane was installed with TLS enabled")
+	failurePolicy := flag.String("failure-policy", "Ignore", "whether pods that can't be injected are admitted (\"Ignore\") or rejected (\"Fail\")")
+	timeoutSeconds := flag.Uint("timeout-seconds", 0, "seconds the Kubernetes API server waits for the webhook; 0 uses the API server's default")
+	reinvocationPolicy := flag.String("reinvocation-policy", "Never", "whether the webhook is called again after other webhooks change a pod (\"Never\" or \"IfNeeded\")")
+	clientFlags := k8s.NewClientFlags()
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
 	flags.ConfigureAndParse()
 
-	stop := make(chan os.Signal, 1)
-	defer close(stop)
-	signal.Notify(stop, os.Interrupt, os.Kill)
-
-	k8sClient, err := k8s.NewClientSet(*kubeconfig)
+	k8sClient, err := k8s.NewClientSetWithOptions(*kubeconfig, clientFlags.Options())
 	if err != nil {
 		log.Fatalf("failed to initialize Kubernetes client: %s", err)
 	}
 
-	rootCA, err := tls.NewCA()
-	if err != nil {
-		log.Fatalf("failed to create root CA: %s", err)
+	policy := injector.WebhookPolicy{
+		FailurePolicy:      arv1beta1.FailurePolicyType(*failurePolicy),
+		TimeoutSeconds:     *timeoutSeconds,
+		ReinvocationPolicy: *reinvocationPolicy,
 	}
-
-	webhookConfig, err := injector.NewWebhookConfig(k8sClient, *controllerNamespace, *webhookServiceName, rootCA)
+	webhookConfig, err := injector.NewWebhookConfig(k8sClient, *controllerNamespace, *webhookServiceName, policy)
 	if err != nil {
-		log.Fatalf("failed to read the trust anchor file: %s", err)
+		log.Fatalf("failed to initialize the webhook configuration: %s", err)
 	}
 
-	mwc, err := webhookConfig.CreateOrUpdate()
-	if err != nil {
-		log.Fatalf("failed to create the mutating webhook configurations resource: %s", err)
+	certs := webhook.NewCertRotator(k8sClient, *controllerNamespace, "linkerd-proxy-injector", k8sPkg.ProxyInjectorTLSSecret, webhookConfig.Publish)
+	if err := certs.Sync(); err != nil {
+		log.Fatalf("failed to set up the webhook certificate: %s", err)
 	}
-	log.Infof("created or updated mutating webhook configuration: %s", mwc.ObjectMeta.SelfLink)
+	log.Info("created or updated mutating webhook configuration")
 
 	resources := &injector.WebhookResources{
 		FileProxySpec:                k8sPkg.MountPathConfigProxySpec,
 		FileProxyInitSpec:            k8sPkg.MountPathConfigProxyInitSpec,
 		FileTLSTrustAnchorVolumeSpec: k8sPkg.MountPathTLSTrustAnchorVolumeSpec,
 		FileTLSIdentityVolumeSpec:    k8sPkg.MountPathTLSIdentityVolumeSpec,
+		FileExtraVolumesSpec:         k8sPkg.MountPathExtraVolumesSpec,
 	}
 
-	s, err := injector.NewWebhookServer(k8sClient, resources, *addr, *controllerNamespace, *noInitContainer, *tlsEnabled, rootCA)
+	s, err := injector.NewWebhookServer(k8sClient, resources, *addr, *controllerNamespace, *noInitContainer, *tlsEnabled, policy.FailurePolicy, certs)
 	if err != nil {
 		log.Fatalf("failed to initialize the webhook server: %s", err)
 	}
 
-	go func() {
-		log.Infof("listening at %s", *addr)
-		if err := s.ListenAndServeTLS("", ""); err != nil {
-			if err == http.ErrServerClosed {
-				return
-			}
-			log.Fatal(err)
-		}
-	}()
-	go admin.StartServer(*metricsAddr)
-
-	<-stop
-	log.Info("shutting down webhook server")
-	if err := s.Shutdown(); err != nil {
-		log.Error(err)
-	}
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.Go(certs.Run)
+	r.AdminHandler("/explain", http.HandlerFunc(s.ServeExplain))
+	r.AddHTTPSServer("webhook server on "+*addr, s.Server, nil)
+	r.Run()
 }
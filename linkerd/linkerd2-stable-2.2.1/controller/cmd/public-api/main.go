@@ -3,64 +3,118 @@ package main
 import (
 	"context"
 	"flag"
-	"os"
-	"os/signal"
+	"fmt"
 	"strings"
-	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
+	tapPb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/controller/tap"
-	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/downstream"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/runner"
+	"github.com/linkerd/linkerd2/pkg/trace"
 	promApi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
+	"go.opencensus.io/plugin/ochttp"
 )
 
 func main() {
 	addr := flag.String("addr", ":8085", "address to serve on")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
 	prometheusURL := flag.String("prometheus-url", "http://127.0.0.1:9090", "prometheus url")
+	longWindowPrometheusURL := flag.String("long-window-prometheus-url", "", "url of a Prometheus-compatible endpoint with a longer retention, e.g. reading from remote storage, serving the queries over longer time windows than -prometheus-retention (disabled if empty)")
+	prometheusRetention := flag.Duration("prometheus-retention", 6*time.Hour, "retention of the Prometheus at -prometheus-url, beyond which the queries are sent to -long-window-prometheus-url")
+	recordingRules := flag.Bool("recording-rules", false, "query the series pre-aggregated by the Prometheus recording rules of the install when possible, falling back to the raw metrics")
 	metricsAddr := flag.String("metrics-addr", ":9995", "address to serve scrapable metrics on")
 	proxyAPIAddr := flag.String("proxy-api-addr", "127.0.0.1:8086", "address of proxy-api service")
 	tapAddr := flag.String("tap-addr", "127.0.0.1:8088", "address of tap service")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	namespaces := flag.String("namespaces", "", "comma separated list of namespaces to operate in, on top of the controller namespace; all namespaces if empty")
 	ignoredNamespaces := flag.String("ignore-namespaces", "kube-system", "comma separated list of namespaces to not list pods from")
+	clientFlags := k8s.NewClientFlags()
+	downstreamFlags := downstream.NewFlags()
+	informerResync := flag.Duration("informer-resync", k8s.DefaultResync, "period at which the informers resync their caches")
+	minTimeWindow := flag.Duration("min-time-window", util.DefaultTimeWindowBounds.Min, "shortest time window of the metrics requests")
+	maxTimeWindow := flag.Duration("max-time-window", util.DefaultTimeWindowBounds.Max, "longest time window of the metrics requests")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
+	traceFlags := trace.NewFlags()
 	flags.ConfigureAndParse()
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	if *singleNamespace && *namespaces != "" {
+		log.Fatal("-single-namespace and -namespaces are mutually exclusive")
+	}
+	downstreamOptions := downstreamFlags.Options()
+	if err := downstreamOptions.Validate(); err != nil {
+		log.Fatalf("invalid -grpc-client-* flags: %s", err)
+	}
+	if *minTimeWindow > *maxTimeWindow {
+		log.Fatal("-min-time-window must not be greater than -max-time-window")
+	}
+	if *longWindowPrometheusURL != "" && *maxTimeWindow <= *prometheusRetention {
+		log.Warn("-long-window-prometheus-url is only queried for time windows longer than -prometheus-retention, which -max-time-window rejects")
+	}
 
-	tapClient, tapConn, err := tap.NewClient(*tapAddr)
+	stopTracing, err := traceFlags.Init("linkerd-controller-api")
 	if err != nil {
 		log.Fatal(err.Error())
 	}
-	defer tapConn.Close()
+	defer stopTracing()
+
+	var tapClient tapPb.TapClient
+	var tapConn *downstream.Conn
+	if *singleNamespace || *namespaces != "" {
+		// The tap APIService is cluster-scoped and thus unavailable to
+		// installs restricted to some namespaces, which fall back to the tap
+		// gRPC service.
+		tapConn, err = downstream.Dial("tap", *tapAddr, downstreamOptions)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		defer tapConn.Close()
+		tapClient = tapPb.NewTapClient(tapConn.ClientConn())
+	} else {
+		kubeAPI, err := pkgK8s.NewAPI(*kubeConfigPath, "")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		tapClient, err = tap.NewAPIClient(kubeAPI)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
 
-	proxyAPIConn, err := grpc.Dial(*proxyAPIAddr, grpc.WithInsecure())
+	proxyAPIConn, err := downstream.Dial("proxy-api", *proxyAPIAddr, downstreamOptions)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 	defer proxyAPIConn.Close()
-	discoveryClient := discovery.NewDiscoveryClient(proxyAPIConn)
+	discoveryClient := discovery.NewDiscoveryClient(proxyAPIConn.ClientConn())
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
 	var spClient *spclient.Clientset
-	restrictToNamespace := ""
+	restrictToNamespaces := []string{}
 	resources := []k8s.APIResource{k8s.DS, k8s.Deploy, k8s.Pod, k8s.RC, k8s.RS, k8s.Svc, k8s.SS}
 
 	if *singleNamespace {
-		restrictToNamespace = *controllerNamespace
+		restrictToNamespaces = []string{*controllerNamespace}
 	} else {
-		spClient, err = k8s.NewSpClientSet(*kubeConfigPath)
+		if *namespaces != "" {
+			restrictToNamespaces = k8s.ParseNamespaces(*namespaces, *controllerNamespace)
+		}
+
+		spClient, err = k8s.NewSpClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
 		if err != nil {
 			log.Fatal(err.Error())
 		}
@@ -68,40 +122,81 @@ func main() {
 		resources = append(resources, k8s.SP)
 	}
 
-	k8sAPI := k8s.NewAPI(
+	k8sAPI := k8s.NewAPIWithOptions(
 		k8sClient,
 		spClient,
-		restrictToNamespace,
+		k8s.APIOptions{
+			Namespaces: restrictToNamespaces,
+			Resync:     *informerResync,
+		},
 		resources...,
 	)
 
-	prometheusClient, err := promApi.NewClient(promApi.Config{Address: *prometheusURL})
+	prometheusClient, err := promApi.NewClient(promApi.Config{
+		Address:      *prometheusURL,
+		RoundTripper: &ochttp.Transport{},
+	})
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
+	var longWindowPrometheusClient promApi.Client
+	if *longWindowPrometheusURL != "" {
+		longWindowPrometheusClient, err = promApi.NewClient(promApi.Config{
+			Address:      *longWindowPrometheusURL,
+			RoundTripper: &ochttp.Transport{},
+		})
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
 	server := public.NewServer(
 		*addr,
 		prometheusClient,
+		longWindowPrometheusClient,
+		*prometheusRetention,
+		*recordingRules,
 		tapClient,
 		discoveryClient,
 		k8sAPI,
 		*controllerNamespace,
 		strings.Split(*ignoredNamespaces, ","),
 		*singleNamespace,
+		util.TimeWindowBounds{Min: *minTimeWindow, Max: *maxTimeWindow},
 	)
 
-	k8sAPI.Sync() // blocks until caches are synced
-
-	go func() {
-		log.Infof("starting HTTP server on %+v", *addr)
-		server.ListenAndServe()
-	}()
-
-	go admin.StartServer(*metricsAddr)
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.OnSync(func() { k8sAPI.Sync() })
+	r.ReadyCheck(func() error {
+		return k8sAPI.CheckHealth(k8s.DefaultMaxStaleness)
+	})
+	downstreams := []*downstream.Conn{proxyAPIConn}
+	if tapConn != nil {
+		downstreams = append(downstreams, tapConn)
+	}
+	for _, conn := range downstreams {
+		r.ReadyCheck(conn.Check)
+		r.Go(conn.Watch)
+	}
+	r.AdminHandler("/downstreams", downstream.Handler(downstreams...))
+	r.PeriodicReadyCheck(prometheusCheckInterval, func() error {
+		return checkPrometheus(promv1.NewAPI(prometheusClient))
+	})
+	r.AddHTTPServer("HTTP server on "+*addr, server, nil)
+	r.Run()
+}
 
-	<-stop
+const prometheusCheckInterval = 10 * time.Second
 
-	log.Infof("shutting down HTTP server on %+v", *addr)
-	server.Shutdown(context.Background())
+// checkPrometheus makes a trivial query to Prometheus, without which the
+// metrics endpoints of the public API fail.
+func checkPrometheus(api promv1.API) error {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusCheckInterval/2)
+	defer cancel()
+	if _, err := api.Query(ctx, "1", time.Now()); err != nil {
+		return fmt.Errorf("Prometheus is unreachable: %s", err)
+	}
+	return nil
 }
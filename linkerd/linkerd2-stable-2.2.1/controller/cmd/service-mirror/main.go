@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	servicemirror "github.com/linkerd/linkerd2/controller/service-mirror"
+	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/linkerd/linkerd2/pkg/runner"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// remoteKubeConfigKey is the key of the remote cluster's kubeconfig in the
+// remote cluster's secret.
+const remoteKubeConfigKey = "kubeconfig"
+
+func main() {
+	metricsAddr := flag.String("metrics-addr", ":9994", "address to serve scrapable metrics on")
+	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	clusterName := flag.String("remote-cluster-name", "", "name of the remote cluster, appended to the names of its mirrored services")
+	clusterDomain := flag.String("remote-cluster-domain", "cluster.local", "DNS suffix of the remote cluster")
+	kubeConfigSecret := flag.String("remote-kubeconfig-secret", "", "name of the secret in the controller namespace holding the remote cluster's kubeconfig, under the \""+remoteKubeConfigKey+"\" key")
+	gatewayAddresses := flag.String("gateway-addresses", "", "comma-separated IP addresses of the remote cluster's gateway")
+	gatewayPort := flag.Int("gateway-port", 4143, "port of the remote cluster's gateway")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
+	flags.ConfigureAndParse()
+
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	secret, err := k8sClient.CoreV1().Secrets(*controllerNamespace).Get(*kubeConfigSecret, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to read the remote cluster's kubeconfig: %s", err)
+	}
+	remoteClient, err := k8s.NewClientSetFromKubeConfig(secret.Data[remoteKubeConfigKey])
+	if err != nil {
+		log.Fatalf("Failed to load the remote cluster's kubeconfig: %s", err)
+	}
+
+	localAPI := k8s.NewAPI(k8sClient, nil, "", k8s.Svc, k8s.Endpoint)
+	remoteAPI := k8s.NewAPI(remoteClient, nil, "", k8s.Svc)
+
+	gateway := servicemirror.GatewayConfig{Port: int32(*gatewayPort)}
+	if *gatewayAddresses != "" {
+		gateway.Addresses = strings.Split(*gatewayAddresses, ",")
+	}
+
+	watcher, err := servicemirror.NewRemoteClusterServiceWatcher(*clusterName, *clusterDomain, gateway, remoteAPI, localAPI)
+	if err != nil {
+		log.Fatalf("Failed to create the service mirror: %s", err)
+	}
+
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.OnSync(func() { localAPI.Sync() })
+	r.OnSync(func() { remoteAPI.Sync() })
+	r.Go(func(stop <-chan struct{}) {
+		log.Info("starting service mirror")
+		watcher.Run(stop)
+	})
+	r.Run()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	validator "github.com/linkerd/linkerd2/controller/sp-validator"
+	"github.com/linkerd/linkerd2/controller/webhook"
+	"github.com/linkerd/linkerd2/pkg/flags"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/runner"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	metricsAddr := flag.String("metrics-addr", ":9999", "address to serve scrapable metrics on")
+	addr := flag.String("addr", ":8443", "address to serve on")
+	kubeconfig := flag.String("kubeconfig", "", "path to kubeconfig")
+	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	webhookServiceName := flag.String("webhook-service", "linkerd-sp-validator.linkerd.io", "name of the admission webhook")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
+	flags.ConfigureAndParse()
+
+	k8sClient, err := k8s.NewClientSet(*kubeconfig)
+	if err != nil {
+		log.Fatalf("failed to initialize Kubernetes client: %s", err)
+	}
+
+	webhookConfig := validator.NewWebhookConfig(k8sClient, *controllerNamespace, *webhookServiceName)
+
+	certs := webhook.NewCertRotator(k8sClient, *controllerNamespace, "linkerd-sp-validator", k8sPkg.SPValidatorTLSSecret, webhookConfig.Publish)
+	if err := certs.Sync(); err != nil {
+		log.Fatalf("failed to set up the webhook certificate: %s", err)
+	}
+	log.Info("created or updated validating webhook configuration")
+
+	s := validator.NewWebhookServer(*addr, certs)
+
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.Go(certs.Run)
+	r.AddHTTPSServer("webhook server on "+*addr, s.Server, nil)
+	r.Run()
+}
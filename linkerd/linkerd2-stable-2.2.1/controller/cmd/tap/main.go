@@ -2,66 +2,121 @@ package main
 
 import (
 	"flag"
-	"os"
-	"os/signal"
-	"syscall"
 
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/controller/tap"
-	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/controller/webhook"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/runner"
+	"github.com/linkerd/linkerd2/pkg/trace"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func main() {
 	addr := flag.String("addr", "127.0.0.1:8088", "address to serve on")
+	apiServerAddr := flag.String("apiserver-addr", ":8089", "address to serve the tap APIService on")
 	metricsAddr := flag.String("metrics-addr", ":9998", "address to serve scrapable metrics on")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	namespaces := flag.String("namespaces", "", "comma separated list of namespaces to operate in, on top of the controller namespace; all namespaces if empty")
 	tapPort := flag.Uint("tap-port", 4190, "proxy tap port to connect to")
+	podSelector := flag.String("pod-selector", "", "label selector of the pods to cache, e.g. \"linkerd.io/control-plane-ns\" for meshed pods only; all pods if empty")
+	maxConcurrentDials := flag.Uint("max-concurrent-dials", 10, "maximum number of proxy taps to establish at once; 0 for no limit")
+	clientFlags := k8s.NewClientFlags()
+	informerResync := flag.Duration("informer-resync", k8s.DefaultResync, "period at which the informers resync their caches")
+	shutdownTimeout := flag.Duration("shutdown-timeout", runner.DefaultShutdownTimeout, "time given to the servers to drain their connections on shutdown")
+	traceFlags := trace.NewFlags()
 	flags.ConfigureAndParse()
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	if *singleNamespace && *namespaces != "" {
+		log.Fatal("-single-namespace and -namespaces are mutually exclusive")
+	}
+
+	if _, err := labels.Parse(*podSelector); err != nil {
+		log.Fatalf("invalid pod selector: %s", err)
+	}
+
+	stopTracing, err := traceFlags.Init("linkerd-tap")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer stopTracing()
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
 	if err != nil {
 		log.Fatalf("failed to create Kubernetes client: %s", err)
 	}
-	restrictToNamespace := ""
+
+	var spClient *spclient.Clientset
+	restrictToNamespaces := []string{}
+	resources := []k8s.APIResource{k8s.DS, k8s.SS, k8s.Deploy, k8s.Pod, k8s.RC, k8s.Svc, k8s.RS}
+
 	if *singleNamespace {
-		restrictToNamespace = *controllerNamespace
+		restrictToNamespaces = []string{*controllerNamespace}
+	} else {
+		if *namespaces != "" {
+			restrictToNamespaces = k8s.ParseNamespaces(*namespaces, *controllerNamespace)
+		}
+
+		spClient, err = k8s.NewSpClientSetWithOptions(*kubeConfigPath, clientFlags.Options())
+		if err != nil {
+			log.Fatalf("failed to create ServiceProfile client: %s", err)
+		}
+
+		resources = append(resources, k8s.SP)
 	}
-	k8sAPI := k8s.NewAPI(
+
+	k8sAPI := k8s.NewAPIWithOptions(
 		k8sClient,
-		nil,
-		restrictToNamespace,
-		k8s.DS,
-		k8s.SS,
-		k8s.Deploy,
-		k8s.Pod,
-		k8s.RC,
-		k8s.Svc,
-		k8s.RS,
+		spClient,
+		k8s.APIOptions{
+			Namespaces:  restrictToNamespaces,
+			PodSelector: *podSelector,
+			Resync:      *informerResync,
+		},
+		resources...,
 	)
 
-	server, lis, err := tap.NewServer(*addr, *tapPort, *controllerNamespace, k8sAPI)
+	r := runner.New(*metricsAddr)
+	r.ShutdownTimeout = *shutdownTimeout
+	r.OnSync(func() { k8sAPI.Sync() })
+	r.ReadyCheck(func() error {
+		return k8sAPI.CheckHealth(k8s.DefaultMaxStaleness)
+	})
+
+	grpcTapServer := tap.NewGrpcTapServer(*tapPort, *maxConcurrentDials, *controllerNamespace, *singleNamespace, k8sAPI)
+	server, lis, err := tap.NewServer(*addr, grpcTapServer)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
+	r.AddGRPCServer("gRPC server on "+*addr, server, lis)
 
-	k8sAPI.Sync() // blocks until caches are synced
-
-	go func() {
-		log.Println("starting gRPC server on", *addr)
-		server.Serve(lis)
-	}()
+	// The tap APIService is cluster-scoped, and requires access to the
+	// kube-system namespace to authenticate requests, so it is only served
+	// when the control plane has cluster-wide permissions.
+	if !*singleNamespace && *namespaces == "" {
+		// The CA and serving certificate are kept in a secret shared by all
+		// the replicas, so that the APIService's CA bundle matches each of
+		// their certificates.
+		apiServiceConfig := tap.NewAPIServiceConfig(k8sClient, *controllerNamespace)
+		certs := webhook.NewCertRotator(k8sClient, *controllerNamespace, pkgK8s.TapServiceName, pkgK8s.TapTLSSecret, apiServiceConfig.Publish)
+		if err := certs.Sync(); err != nil {
+			log.Fatalf("failed to set up the tap APIService certificate: %s", err)
+		}
+		log.Infof("created or updated APIService: %s", pkgK8s.TapAPIService)
 
-	go admin.StartServer(*metricsAddr)
+		apiServer, apiLis, err := tap.NewAPIServer(*apiServerAddr, certs.GetCertificate, k8sAPI, grpcTapServer)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
 
-	<-stop
+		r.Go(certs.Run)
+		r.AddHTTPSServer("tap APIService on "+*apiServerAddr, apiServer, apiLis)
+	}
 
-	log.Println("shutting down gRPC server on", *addr)
-	server.GracefulStop()
+	r.Run()
 }
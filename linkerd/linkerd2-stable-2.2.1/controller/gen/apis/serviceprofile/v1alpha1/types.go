@@ -29,6 +29,12 @@ type ServiceProfile struct {
 type ServiceProfileSpec struct {
 	Routes      []*RouteSpec `json:"routes"`
 	RetryBudget *RetryBudget `json:"retryBudget,omitempty"`
+	// DstOverrides splits the traffic sent to the service across weighted
+	// backend services, e.g. to shift traffic to a canary.
+	DstOverrides []*WeightedDst `json:"dstOverrides,omitempty"`
+	// PerClient overrides the routes and retry budget for the clients it
+	// selects. The first matching entry applies.
+	PerClient []*ClientProfile `json:"perClient,omitempty"`
 }
 
 // RouteSpec specifies a Route resource.
@@ -38,6 +44,21 @@ type RouteSpec struct {
 	ResponseClasses []*ResponseClass `json:"responseClasses,omitempty"`
 	IsRetryable     bool             `json:"isRetryable,omitempty"`
 	Timeout         string           `json:"timeout,omitempty"`
+	// SLO holds the service level objectives of the route, whose compliance
+	// the route stats report.
+	SLO *RouteSLO `json:"slo,omitempty"`
+}
+
+// RouteSLO describes the service level objectives of a route. Either
+// objective may be left unset.
+type RouteSLO struct {
+	// SuccessRate is the objective success rate, between 0 and 1, e.g. 0.999.
+	SuccessRate float64 `json:"successRate,omitempty"`
+	// Latency is the objective latency of the LatencyPercentile percentile of
+	// the requests, e.g. "250ms".
+	Latency string `json:"latency,omitempty"`
+	// LatencyPercentile is 50, 95 or 99, the default.
+	LatencyPercentile uint32 `json:"latencyPercentile,omitempty"`
 }
 
 // RequestMatch describes the conditions under which to match a Route.
@@ -62,6 +83,10 @@ type ResponseMatch struct {
 	Not    *ResponseMatch   `json:"not,omitempty"`
 	Any    []*ResponseMatch `json:"any,omitempty"`
 	Status *Range           `json:"status,omitempty"`
+	// GRPCStatus matches the grpc-status of gRPC responses. The proxy can't
+	// classify responses by it, so it's only honored by the route stats of
+	// the public API.
+	GRPCStatus *Range `json:"grpcStatus,omitempty"`
 }
 
 // Range describes a range of integers (e.g. status codes).
@@ -78,6 +103,23 @@ type RetryBudget struct {
 	TTL                 string  `json:"ttl"`
 }
 
+// WeightedDst is a backend service that receives a share of the traffic sent
+// to a service, in proportion to its weight.
+type WeightedDst struct {
+	Authority string `json:"authority"`
+	Weight    uint32 `json:"weight"`
+}
+
+// ClientProfile overrides the routes and retry budget of a ServiceProfile for
+// the clients in a namespace and/or whose pods match a label selector. Fields
+// that are not set are taken from the ServiceProfile.
+type ClientProfile struct {
+	Namespace   string                `json:"namespace,omitempty"`
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	Routes      []*RouteSpec          `json:"routes,omitempty"`
+	RetryBudget *RetryBudget          `json:"retryBudget,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ServiceProfileList is a list of ServiceProfile resources.
@@ -21,9 +21,47 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientProfile) DeepCopyInto(out *ClientProfile) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]*RouteSpec, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(RouteSpec)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.RetryBudget != nil {
+		in, out := &in.RetryBudget, &out.RetryBudget
+		*out = new(RetryBudget)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProfile.
+func (in *ClientProfile) DeepCopy() *ClientProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Range) DeepCopyInto(out *Range) {
 	*out = *in
@@ -139,6 +177,11 @@ func (in *ResponseMatch) DeepCopyInto(out *ResponseMatch) {
 		*out = new(Range)
 		**out = **in
 	}
+	if in.GRPCStatus != nil {
+		in, out := &in.GRPCStatus, &out.GRPCStatus
+		*out = new(Range)
+		**out = **in
+	}
 	return
 }
 
@@ -168,6 +211,22 @@ func (in *RetryBudget) DeepCopy() *RetryBudget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteSLO) DeepCopyInto(out *RouteSLO) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSLO.
+func (in *RouteSLO) DeepCopy() *RouteSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
 	*out = *in
@@ -187,6 +246,11 @@ func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
 			}
 		}
 	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(RouteSLO)
+		**out = **in
+	}
 	return
 }
 
@@ -279,6 +343,28 @@ func (in *ServiceProfileSpec) DeepCopyInto(out *ServiceProfileSpec) {
 		*out = new(RetryBudget)
 		**out = **in
 	}
+	if in.DstOverrides != nil {
+		in, out := &in.DstOverrides, &out.DstOverrides
+		*out = make([]*WeightedDst, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(WeightedDst)
+				**out = **in
+			}
+		}
+	}
+	if in.PerClient != nil {
+		in, out := &in.PerClient, &out.PerClient
+		*out = make([]*ClientProfile, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ClientProfile)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	return
 }
 
@@ -291,3 +377,19 @@ func (in *ServiceProfileSpec) DeepCopy() *ServiceProfileSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedDst) DeepCopyInto(out *WeightedDst) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedDst.
+func (in *WeightedDst) DeepCopy() *WeightedDst {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedDst)
+	in.DeepCopyInto(out)
+	return out
+}
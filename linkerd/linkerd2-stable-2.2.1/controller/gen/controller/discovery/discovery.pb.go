@@ -25,6 +25,9 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type EndpointsParams struct {
+	// If set, the weights of the addresses are the ones sent to proxies in this
+	// zone.
+	ClientZone           string   `protobuf:"bytes,1,opt,name=client_zone,json=clientZone,proto3" json:"client_zone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -34,7 +37,7 @@ func (m *EndpointsParams) Reset()         { *m = EndpointsParams{} }
 func (m *EndpointsParams) String() string { return proto.CompactTextString(m) }
 func (*EndpointsParams) ProtoMessage()    {}
 func (*EndpointsParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_discovery_1291ed21e9de6976, []int{0}
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{0}
 }
 func (m *EndpointsParams) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EndpointsParams.Unmarshal(m, b)
@@ -54,6 +57,13 @@ func (m *EndpointsParams) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_EndpointsParams proto.InternalMessageInfo
 
+func (m *EndpointsParams) GetClientZone() string {
+	if m != nil {
+		return m.ClientZone
+	}
+	return ""
+}
+
 type EndpointsResponse struct {
 	ServicePorts         map[string]*ServicePort `protobuf:"bytes,1,rep,name=service_ports,json=servicePorts,proto3" json:"service_ports,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
@@ -65,7 +75,7 @@ func (m *EndpointsResponse) Reset()         { *m = EndpointsResponse{} }
 func (m *EndpointsResponse) String() string { return proto.CompactTextString(m) }
 func (*EndpointsResponse) ProtoMessage()    {}
 func (*EndpointsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_discovery_1291ed21e9de6976, []int{1}
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{1}
 }
 func (m *EndpointsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EndpointsResponse.Unmarshal(m, b)
@@ -103,7 +113,7 @@ func (m *ServicePort) Reset()         { *m = ServicePort{} }
 func (m *ServicePort) String() string { return proto.CompactTextString(m) }
 func (*ServicePort) ProtoMessage()    {}
 func (*ServicePort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_discovery_1291ed21e9de6976, []int{2}
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{2}
 }
 func (m *ServicePort) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServicePort.Unmarshal(m, b)
@@ -141,7 +151,7 @@ func (m *PodAddresses) Reset()         { *m = PodAddresses{} }
 func (m *PodAddresses) String() string { return proto.CompactTextString(m) }
 func (*PodAddresses) ProtoMessage()    {}
 func (*PodAddresses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_discovery_1291ed21e9de6976, []int{3}
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{3}
 }
 func (m *PodAddresses) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodAddresses.Unmarshal(m, b)
@@ -169,18 +179,21 @@ func (m *PodAddresses) GetPodAddresses() []*PodAddress {
 }
 
 type PodAddress struct {
-	Addr                 *public.TcpAddress `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
-	Pod                  *public.Pod        `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Addr *public.TcpAddress `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Pod  *public.Pod        `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
+	// The zone of the node the pod runs on, if known.
+	Zone                 string   `protobuf:"bytes,3,opt,name=zone,proto3" json:"zone,omitempty"`
+	Weight               uint32   `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *PodAddress) Reset()         { *m = PodAddress{} }
 func (m *PodAddress) String() string { return proto.CompactTextString(m) }
 func (*PodAddress) ProtoMessage()    {}
 func (*PodAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_discovery_1291ed21e9de6976, []int{4}
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{4}
 }
 func (m *PodAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodAddress.Unmarshal(m, b)
@@ -214,6 +227,83 @@ func (m *PodAddress) GetPod() *public.Pod {
 	return nil
 }
 
+func (m *PodAddress) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *PodAddress) GetWeight() uint32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+type EndpointsUpdate struct {
+	// The service whose endpoints changed, of the form "name.namespace".
+	Service              string        `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Port                 uint32        `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Add                  []*PodAddress `protobuf:"bytes,3,rep,name=add,proto3" json:"add,omitempty"`
+	Remove               []*PodAddress `protobuf:"bytes,4,rep,name=remove,proto3" json:"remove,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *EndpointsUpdate) Reset()         { *m = EndpointsUpdate{} }
+func (m *EndpointsUpdate) String() string { return proto.CompactTextString(m) }
+func (*EndpointsUpdate) ProtoMessage()    {}
+func (*EndpointsUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_discovery_0d704fb2e34a0cc6, []int{5}
+}
+func (m *EndpointsUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndpointsUpdate.Unmarshal(m, b)
+}
+func (m *EndpointsUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndpointsUpdate.Marshal(b, m, deterministic)
+}
+func (dst *EndpointsUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndpointsUpdate.Merge(dst, src)
+}
+func (m *EndpointsUpdate) XXX_Size() int {
+	return xxx_messageInfo_EndpointsUpdate.Size(m)
+}
+func (m *EndpointsUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndpointsUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndpointsUpdate proto.InternalMessageInfo
+
+func (m *EndpointsUpdate) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *EndpointsUpdate) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *EndpointsUpdate) GetAdd() []*PodAddress {
+	if m != nil {
+		return m.Add
+	}
+	return nil
+}
+
+func (m *EndpointsUpdate) GetRemove() []*PodAddress {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*EndpointsParams)(nil), "linkerd2.controller.discovery.EndpointsParams")
 	proto.RegisterType((*EndpointsResponse)(nil), "linkerd2.controller.discovery.EndpointsResponse")
@@ -222,6 +312,7 @@ func init() {
 	proto.RegisterMapType((map[uint32]*PodAddresses)(nil), "linkerd2.controller.discovery.ServicePort.PortEndpointsEntry")
 	proto.RegisterType((*PodAddresses)(nil), "linkerd2.controller.discovery.PodAddresses")
 	proto.RegisterType((*PodAddress)(nil), "linkerd2.controller.discovery.PodAddress")
+	proto.RegisterType((*EndpointsUpdate)(nil), "linkerd2.controller.discovery.EndpointsUpdate")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -237,6 +328,9 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type DiscoveryClient interface {
 	Endpoints(ctx context.Context, in *EndpointsParams, opts ...grpc.CallOption) (*EndpointsResponse, error)
+	// Streams the changes to the endpoints of the watched service ports,
+	// starting with their current endpoints as additions.
+	WatchEndpoints(ctx context.Context, in *EndpointsParams, opts ...grpc.CallOption) (Discovery_WatchEndpointsClient, error)
 }
 
 type discoveryClient struct {
@@ -256,9 +350,44 @@ func (c *discoveryClient) Endpoints(ctx context.Context, in *EndpointsParams, op
 	return out, nil
 }
 
+func (c *discoveryClient) WatchEndpoints(ctx context.Context, in *EndpointsParams, opts ...grpc.CallOption) (Discovery_WatchEndpointsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Discovery_serviceDesc.Streams[0], "/linkerd2.controller.discovery.Discovery/WatchEndpoints", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &discoveryWatchEndpointsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Discovery_WatchEndpointsClient interface {
+	Recv() (*EndpointsUpdate, error)
+	grpc.ClientStream
+}
+
+type discoveryWatchEndpointsClient struct {
+	grpc.ClientStream
+}
+
+func (x *discoveryWatchEndpointsClient) Recv() (*EndpointsUpdate, error) {
+	m := new(EndpointsUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DiscoveryServer is the server API for Discovery service.
 type DiscoveryServer interface {
 	Endpoints(context.Context, *EndpointsParams) (*EndpointsResponse, error)
+	// Streams the changes to the endpoints of the watched service ports,
+	// starting with their current endpoints as additions.
+	WatchEndpoints(*EndpointsParams, Discovery_WatchEndpointsServer) error
 }
 
 func RegisterDiscoveryServer(s *grpc.Server, srv DiscoveryServer) {
@@ -283,6 +412,27 @@ func _Discovery_Endpoints_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Discovery_WatchEndpoints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EndpointsParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiscoveryServer).WatchEndpoints(m, &discoveryWatchEndpointsServer{stream})
+}
+
+type Discovery_WatchEndpointsServer interface {
+	Send(*EndpointsUpdate) error
+	grpc.ServerStream
+}
+
+type discoveryWatchEndpointsServer struct {
+	grpc.ServerStream
+}
+
+func (x *discoveryWatchEndpointsServer) Send(m *EndpointsUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Discovery_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "linkerd2.controller.discovery.Discovery",
 	HandlerType: (*DiscoveryServer)(nil),
@@ -292,39 +442,53 @@ var _Discovery_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Discovery_Endpoints_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEndpoints",
+			Handler:       _Discovery_WatchEndpoints_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "controller/discovery.proto",
 }
 
 func init() {
-	proto.RegisterFile("controller/discovery.proto", fileDescriptor_discovery_1291ed21e9de6976)
-}
-
-var fileDescriptor_discovery_1291ed21e9de6976 = []byte{
-	// 394 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xcf, 0x4b, 0xc3, 0x30,
-	0x14, 0xc7, 0xed, 0xa6, 0xc2, 0x5e, 0x5b, 0x75, 0xc1, 0xc3, 0xa8, 0x08, 0xa3, 0x07, 0x99, 0x0a,
-	0xa9, 0xd4, 0x8b, 0x08, 0xa2, 0x1b, 0xee, 0x2a, 0xa5, 0x7a, 0xf2, 0xe0, 0xe8, 0x9a, 0x30, 0xcb,
-	0xba, 0x26, 0x24, 0xdd, 0x60, 0xe0, 0x5f, 0xeb, 0xcd, 0xff, 0x42, 0xba, 0xfe, 0x58, 0xa4, 0xe2,
-	0xe6, 0x65, 0x2b, 0x2f, 0xdf, 0x7c, 0xfa, 0x3e, 0xe5, 0x3d, 0xb0, 0x42, 0x96, 0xa4, 0x82, 0xc5,
-	0x31, 0x15, 0x0e, 0x89, 0x64, 0xc8, 0x16, 0x54, 0x2c, 0x31, 0x17, 0x2c, 0x65, 0xe8, 0x34, 0x8e,
-	0x92, 0x29, 0x15, 0xc4, 0xc5, 0xeb, 0x10, 0xae, 0x42, 0x96, 0xc1, 0xe7, 0xe3, 0x38, 0x0a, 0xf3,
-	0xb0, 0xdd, 0x86, 0xc3, 0x61, 0x42, 0x38, 0x8b, 0x92, 0x54, 0x7a, 0x81, 0x08, 0x66, 0xd2, 0xfe,
-	0xd2, 0xa0, 0x5d, 0xd5, 0x7c, 0x2a, 0x39, 0x4b, 0x24, 0x45, 0x13, 0x30, 0x25, 0x15, 0x8b, 0x28,
-	0xa4, 0x23, 0xce, 0x44, 0x2a, 0x3b, 0x5a, 0xb7, 0xd9, 0xd3, 0xdd, 0x01, 0xfe, 0xf3, 0x6d, 0xb8,
-	0x06, 0xc2, 0xcf, 0x39, 0xc5, 0xcb, 0x20, 0xc3, 0x24, 0x15, 0x4b, 0xdf, 0x90, 0x4a, 0xc9, 0x9a,
-	0x42, 0xbb, 0x16, 0x41, 0x47, 0xd0, 0x9c, 0xd2, 0x65, 0x47, 0xeb, 0x6a, 0xbd, 0x96, 0x9f, 0x3d,
-	0xa2, 0x07, 0xd8, 0x5b, 0x04, 0xf1, 0x9c, 0x76, 0x1a, 0x5d, 0xad, 0xa7, 0xbb, 0x17, 0x1b, 0xfa,
-	0x50, 0x90, 0x7e, 0x7e, 0xf1, 0xb6, 0x71, 0xa3, 0xd9, 0x9f, 0x1a, 0xe8, 0xca, 0x11, 0x22, 0x70,
-	0x90, 0xd9, 0x8d, 0x68, 0xd9, 0x76, 0xa1, 0x79, 0xb7, 0x3d, 0x1e, 0x67, 0x3f, 0x95, 0x76, 0x6e,
-	0x68, 0x72, 0xb5, 0x66, 0xcd, 0x00, 0xd5, 0x43, 0xaa, 0xa3, 0x99, 0x3b, 0xf6, 0x7f, 0x3a, 0x5e,
-	0x6e, 0x68, 0xc2, 0x63, 0xa4, 0x4f, 0x88, 0xa0, 0x52, 0x52, 0xa9, 0x4a, 0xbe, 0x81, 0xa1, 0x1e,
-	0xa1, 0x27, 0x30, 0x39, 0x23, 0xa3, 0xa0, 0x2c, 0x14, 0x8e, 0xe7, 0x5b, 0xe3, 0x7d, 0x83, 0x2b,
-	0x3c, 0x9b, 0x02, 0xac, 0xcf, 0x90, 0x03, 0xbb, 0x19, 0x79, 0xe5, 0xa1, 0xbb, 0x27, 0x6b, 0x68,
-	0x31, 0x77, 0x2f, 0x21, 0x2f, 0x31, 0xab, 0x20, 0x3a, 0x83, 0x26, 0x67, 0xa4, 0x70, 0x3c, 0xae,
-	0xe5, 0x3d, 0x46, 0xfc, 0x2c, 0xe0, 0x7e, 0x40, 0xeb, 0xb1, 0x6c, 0x06, 0x31, 0x68, 0x55, 0x9f,
-	0x0f, 0xe1, 0x6d, 0x87, 0x30, 0x9f, 0x70, 0xeb, 0xea, 0xbf, 0x43, 0x6b, 0xef, 0x0c, 0xfa, 0xaf,
-	0xf7, 0x93, 0x28, 0x7d, 0x9f, 0x8f, 0x71, 0xc8, 0x66, 0x4e, 0x71, 0xbf, 0xfc, 0x77, 0x1d, 0x65,
-	0x1f, 0x27, 0x34, 0x71, 0x7e, 0x5b, 0xcf, 0xf1, 0xfe, 0x6a, 0xe5, 0xae, 0xbf, 0x03, 0x00, 0x00,
-	0xff, 0xff, 0x67, 0x87, 0x75, 0x8c, 0xbd, 0x03, 0x00, 0x00,
+	proto.RegisterFile("controller/discovery.proto", fileDescriptor_discovery_0d704fb2e34a0cc6)
+}
+
+var fileDescriptor_discovery_0d704fb2e34a0cc6 = []byte{
+	// 517 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xd1, 0x8a, 0x13, 0x3d,
+	0x14, 0xc7, 0x37, 0x3b, 0xfd, 0xfa, 0xd1, 0xd3, 0x76, 0x75, 0x83, 0xc8, 0x50, 0x11, 0xcb, 0x5c,
+	0x48, 0x55, 0x98, 0x2e, 0xe3, 0x8d, 0x28, 0xa2, 0x5d, 0xdc, 0x5b, 0x29, 0xa3, 0x22, 0xec, 0x85,
+	0x65, 0x3a, 0x39, 0xb4, 0x43, 0xa7, 0x49, 0x48, 0xd2, 0x4a, 0x7d, 0x0b, 0x9f, 0xc8, 0x77, 0xf1,
+	0xce, 0x5b, 0x9f, 0x40, 0x66, 0x26, 0xd3, 0x46, 0x2a, 0x6e, 0xeb, 0x4d, 0x9b, 0x9c, 0xfc, 0xcf,
+	0x7f, 0xce, 0x2f, 0x39, 0x09, 0xf4, 0x52, 0xc1, 0x8d, 0x12, 0x79, 0x8e, 0x6a, 0xc8, 0x32, 0x9d,
+	0x8a, 0x35, 0xaa, 0x4d, 0x28, 0x95, 0x30, 0x82, 0xde, 0xcf, 0x33, 0xbe, 0x40, 0xc5, 0xa2, 0x70,
+	0x27, 0x0a, 0xb7, 0xa2, 0x5e, 0x47, 0xae, 0xa6, 0x79, 0x96, 0x56, 0xe2, 0x20, 0x82, 0x5b, 0x57,
+	0x9c, 0x49, 0x91, 0x71, 0xa3, 0xc7, 0x89, 0x4a, 0x96, 0x9a, 0x3e, 0x80, 0x76, 0x9a, 0x67, 0xc8,
+	0xcd, 0xe4, 0x8b, 0xe0, 0xe8, 0x93, 0x3e, 0x19, 0xb4, 0x62, 0xa8, 0x42, 0xd7, 0x82, 0x63, 0xf0,
+	0x83, 0xc0, 0xf9, 0x36, 0x29, 0x46, 0x2d, 0x05, 0xd7, 0x48, 0x67, 0xd0, 0xd5, 0xa8, 0xd6, 0x59,
+	0x8a, 0x13, 0x29, 0x94, 0xd1, 0x3e, 0xe9, 0x7b, 0x83, 0x76, 0x74, 0x19, 0xfe, 0xb5, 0x9c, 0x70,
+	0xcf, 0x28, 0x7c, 0x57, 0xb9, 0x8c, 0x0b, 0x93, 0x2b, 0x6e, 0xd4, 0x26, 0xee, 0x68, 0x27, 0xd4,
+	0x5b, 0xc0, 0xf9, 0x9e, 0x84, 0xde, 0x06, 0x6f, 0x81, 0x1b, 0x5b, 0x6c, 0x31, 0xa4, 0xaf, 0xe1,
+	0xbf, 0x75, 0x92, 0xaf, 0xd0, 0x3f, 0xed, 0x93, 0x41, 0x3b, 0x7a, 0x7c, 0x43, 0x1d, 0x8e, 0x65,
+	0x5c, 0x25, 0x3e, 0x3f, 0x7d, 0x46, 0x82, 0xef, 0x04, 0xda, 0xce, 0x12, 0x65, 0x70, 0x56, 0xd0,
+	0x4d, 0xb0, 0x2e, 0xdb, 0x62, 0xbe, 0x3c, 0xdc, 0x3e, 0x2c, 0x7e, 0xb6, 0xd8, 0x15, 0x61, 0x57,
+	0xba, 0xb1, 0xde, 0x12, 0xe8, 0xbe, 0xc8, 0x65, 0xec, 0x56, 0x8c, 0xa3, 0xdf, 0x19, 0x9f, 0xdc,
+	0x50, 0xc4, 0x58, 0xb0, 0x11, 0x63, 0x0a, 0xb5, 0x46, 0xed, 0x42, 0x7e, 0x82, 0x8e, 0xbb, 0x44,
+	0xdf, 0x42, 0x57, 0x0a, 0x36, 0x49, 0xea, 0x80, 0x65, 0x7c, 0x74, 0xb0, 0x7d, 0xdc, 0x91, 0x8e,
+	0x5f, 0xf0, 0x95, 0x00, 0xec, 0x16, 0xe9, 0x10, 0x1a, 0x85, 0x75, 0x09, 0xd2, 0x8e, 0xee, 0xed,
+	0x5c, 0x6d, 0x67, 0xbe, 0x4f, 0x65, 0xed, 0x53, 0x0a, 0xe9, 0x43, 0xf0, 0xa4, 0x60, 0x16, 0xf2,
+	0xce, 0x9e, 0x7e, 0x2c, 0x58, 0x5c, 0x08, 0x28, 0x85, 0x46, 0xd9, 0xb2, 0x5e, 0xd9, 0x05, 0xe5,
+	0x98, 0xde, 0x85, 0xe6, 0x67, 0xcc, 0x66, 0x73, 0xe3, 0x37, 0xca, 0x7d, 0xb3, 0xb3, 0xe0, 0x1b,
+	0x71, 0x3a, 0xff, 0x83, 0x64, 0x89, 0x41, 0xea, 0xc3, 0xff, 0xb6, 0xd3, 0x6c, 0x23, 0xd5, 0xd3,
+	0xc2, 0xb9, 0x38, 0xa1, 0xb2, 0x84, 0x6e, 0x5c, 0x8e, 0xe9, 0x0b, 0xf0, 0x12, 0xc6, 0x7c, 0xef,
+	0xd8, 0xbd, 0x29, 0xb2, 0xe8, 0x08, 0x9a, 0x0a, 0x97, 0x62, 0x8d, 0x7e, 0xe3, 0xd8, 0x7c, 0x9b,
+	0x18, 0xfd, 0x24, 0xd0, 0x7a, 0x53, 0x0b, 0xa8, 0x80, 0xd6, 0x16, 0x87, 0x86, 0x87, 0x5e, 0xba,
+	0xea, 0xca, 0xf7, 0x2e, 0x8e, 0xbd, 0xa4, 0xc1, 0x09, 0x35, 0x70, 0xf6, 0x31, 0x31, 0xe9, 0xfc,
+	0xdf, 0xbf, 0x7a, 0xb0, 0xbe, 0x3a, 0x9e, 0xe0, 0xe4, 0x82, 0x5c, 0x8e, 0xae, 0x5f, 0xcd, 0x32,
+	0x33, 0x5f, 0x4d, 0xc3, 0x54, 0x2c, 0x87, 0x36, 0xbf, 0xfe, 0x8f, 0x86, 0xce, 0xb3, 0x38, 0x43,
+	0x3e, 0xfc, 0xd3, 0x2b, 0x39, 0x6d, 0x96, 0x2f, 0xdf, 0xd3, 0x5f, 0x03, 0x00, 0x2a, 0x53, 0x0d,
+	0xe6, 0x44, 0x05, 0x00, 0x00,
 }
@@ -66,7 +66,7 @@ func (x HttpMethod_Registered) String() string {
 	return proto.EnumName(HttpMethod_Registered_name, int32(x))
 }
 func (HttpMethod_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{10, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{10, 0}
 }
 
 type Scheme_Registered int32
@@ -89,7 +89,7 @@ func (x Scheme_Registered) String() string {
 	return proto.EnumName(Scheme_Registered_name, int32(x))
 }
 func (Scheme_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{11, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{11, 0}
 }
 
 type TapEvent_ProxyDirection int32
@@ -115,7 +115,7 @@ func (x TapEvent_ProxyDirection) String() string {
 	return proto.EnumName(TapEvent_ProxyDirection_name, int32(x))
 }
 func (TapEvent_ProxyDirection) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 0}
 }
 
 type Empty struct {
@@ -128,7 +128,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{0}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -161,7 +161,7 @@ func (m *VersionInfo) Reset()         { *m = VersionInfo{} }
 func (m *VersionInfo) String() string { return proto.CompactTextString(m) }
 func (*VersionInfo) ProtoMessage()    {}
 func (*VersionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{1}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{1}
 }
 func (m *VersionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionInfo.Unmarshal(m, b)
@@ -213,7 +213,7 @@ func (m *ListServicesRequest) Reset()         { *m = ListServicesRequest{} }
 func (m *ListServicesRequest) String() string { return proto.CompactTextString(m) }
 func (*ListServicesRequest) ProtoMessage()    {}
 func (*ListServicesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{2}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{2}
 }
 func (m *ListServicesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListServicesRequest.Unmarshal(m, b)
@@ -251,7 +251,7 @@ func (m *ListServicesResponse) Reset()         { *m = ListServicesResponse{} }
 func (m *ListServicesResponse) String() string { return proto.CompactTextString(m) }
 func (*ListServicesResponse) ProtoMessage()    {}
 func (*ListServicesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{3}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{3}
 }
 func (m *ListServicesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListServicesResponse.Unmarshal(m, b)
@@ -290,7 +290,7 @@ func (m *Service) Reset()         { *m = Service{} }
 func (m *Service) String() string { return proto.CompactTextString(m) }
 func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{4}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{4}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Service.Unmarshal(m, b)
@@ -336,7 +336,7 @@ func (m *ListPodsRequest) Reset()         { *m = ListPodsRequest{} }
 func (m *ListPodsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListPodsRequest) ProtoMessage()    {}
 func (*ListPodsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{5}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{5}
 }
 func (m *ListPodsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsRequest.Unmarshal(m, b)
@@ -382,7 +382,7 @@ func (m *ListPodsResponse) Reset()         { *m = ListPodsResponse{} }
 func (m *ListPodsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListPodsResponse) ProtoMessage()    {}
 func (*ListPodsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{6}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{6}
 }
 func (m *ListPodsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsResponse.Unmarshal(m, b)
@@ -419,26 +419,29 @@ type Pod struct {
 	//	*Pod_StatefulSet
 	//	*Pod_DaemonSet
 	//	*Pod_Job
-	Owner                isPod_Owner        `protobuf_oneof:"owner"`
-	Status               string             `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	Added                bool               `protobuf:"varint,5,opt,name=added,proto3" json:"added,omitempty"`
-	SinceLastReport      *duration.Duration `protobuf:"bytes,6,opt,name=sinceLastReport,proto3" json:"sinceLastReport,omitempty"`
-	ControllerNamespace  string             `protobuf:"bytes,7,opt,name=controllerNamespace,proto3" json:"controllerNamespace,omitempty"`
-	ControlPlane         bool               `protobuf:"varint,8,opt,name=controlPlane,proto3" json:"controlPlane,omitempty"`
-	Uptime               *duration.Duration `protobuf:"bytes,9,opt,name=uptime,proto3" json:"uptime,omitempty"`
-	ProxyReady           bool               `protobuf:"varint,15,opt,name=proxyReady,proto3" json:"proxyReady,omitempty"`
-	ProxyVersion         string             `protobuf:"bytes,16,opt,name=proxyVersion,proto3" json:"proxyVersion,omitempty"`
-	ResourceVersion      string             `protobuf:"bytes,17,opt,name=resourceVersion,proto3" json:"resourceVersion,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Owner                isPod_Owner         `protobuf_oneof:"owner"`
+	Status               string              `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Added                bool                `protobuf:"varint,5,opt,name=added,proto3" json:"added,omitempty"`
+	SinceLastReport      *duration.Duration  `protobuf:"bytes,6,opt,name=sinceLastReport,proto3" json:"sinceLastReport,omitempty"`
+	ControllerNamespace  string              `protobuf:"bytes,7,opt,name=controllerNamespace,proto3" json:"controllerNamespace,omitempty"`
+	ControlPlane         bool                `protobuf:"varint,8,opt,name=controlPlane,proto3" json:"controlPlane,omitempty"`
+	Uptime               *duration.Duration  `protobuf:"bytes,9,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	ProxyReady           bool                `protobuf:"varint,15,opt,name=proxyReady,proto3" json:"proxyReady,omitempty"`
+	ProxyVersion         string              `protobuf:"bytes,16,opt,name=proxyVersion,proto3" json:"proxyVersion,omitempty"`
+	ResourceVersion      string              `protobuf:"bytes,17,opt,name=resourceVersion,proto3" json:"resourceVersion,omitempty"`
+	ProxyRestartCount    uint32              `protobuf:"varint,18,opt,name=proxyRestartCount,proto3" json:"proxyRestartCount,omitempty"`
+	ProxyUptime          *duration.Duration  `protobuf:"bytes,19,opt,name=proxyUptime,proto3" json:"proxyUptime,omitempty"`
+	ProxyResources       *Pod_ProxyResources `protobuf:"bytes,20,opt,name=proxyResources,proto3" json:"proxyResources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *Pod) Reset()         { *m = Pod{} }
 func (m *Pod) String() string { return proto.CompactTextString(m) }
 func (*Pod) ProtoMessage()    {}
 func (*Pod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{7}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{7}
 }
 func (m *Pod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pod.Unmarshal(m, b)
@@ -624,6 +627,27 @@ func (m *Pod) GetResourceVersion() string {
 	return ""
 }
 
+func (m *Pod) GetProxyRestartCount() uint32 {
+	if m != nil {
+		return m.ProxyRestartCount
+	}
+	return 0
+}
+
+func (m *Pod) GetProxyUptime() *duration.Duration {
+	if m != nil {
+		return m.ProxyUptime
+	}
+	return nil
+}
+
+func (m *Pod) GetProxyResources() *Pod_ProxyResources {
+	if m != nil {
+		return m.ProxyResources
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Pod) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Pod_OneofMarshaler, _Pod_OneofUnmarshaler, _Pod_OneofSizer, []interface{}{
@@ -750,6 +774,70 @@ func _Pod_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// ProxyResources are the resource requests and limits of the proxy
+// container, as Kubernetes quantities, empty when unset.
+type Pod_ProxyResources struct {
+	CpuRequest           string   `protobuf:"bytes,1,opt,name=cpuRequest,proto3" json:"cpuRequest,omitempty"`
+	CpuLimit             string   `protobuf:"bytes,2,opt,name=cpuLimit,proto3" json:"cpuLimit,omitempty"`
+	MemoryRequest        string   `protobuf:"bytes,3,opt,name=memoryRequest,proto3" json:"memoryRequest,omitempty"`
+	MemoryLimit          string   `protobuf:"bytes,4,opt,name=memoryLimit,proto3" json:"memoryLimit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Pod_ProxyResources) Reset()         { *m = Pod_ProxyResources{} }
+func (m *Pod_ProxyResources) String() string { return proto.CompactTextString(m) }
+func (*Pod_ProxyResources) ProtoMessage()    {}
+func (*Pod_ProxyResources) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{7, 0}
+}
+func (m *Pod_ProxyResources) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pod_ProxyResources.Unmarshal(m, b)
+}
+func (m *Pod_ProxyResources) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pod_ProxyResources.Marshal(b, m, deterministic)
+}
+func (dst *Pod_ProxyResources) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pod_ProxyResources.Merge(dst, src)
+}
+func (m *Pod_ProxyResources) XXX_Size() int {
+	return xxx_messageInfo_Pod_ProxyResources.Size(m)
+}
+func (m *Pod_ProxyResources) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pod_ProxyResources.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pod_ProxyResources proto.InternalMessageInfo
+
+func (m *Pod_ProxyResources) GetCpuRequest() string {
+	if m != nil {
+		return m.CpuRequest
+	}
+	return ""
+}
+
+func (m *Pod_ProxyResources) GetCpuLimit() string {
+	if m != nil {
+		return m.CpuLimit
+	}
+	return ""
+}
+
+func (m *Pod_ProxyResources) GetMemoryRequest() string {
+	if m != nil {
+		return m.MemoryRequest
+	}
+	return ""
+}
+
+func (m *Pod_ProxyResources) GetMemoryLimit() string {
+	if m != nil {
+		return m.MemoryLimit
+	}
+	return ""
+}
+
 // Deprecated: Do not use.
 type TapRequest struct {
 	// Types that are valid to be assigned to Target:
@@ -775,7 +863,7 @@ func (m *TapRequest) Reset()         { *m = TapRequest{} }
 func (m *TapRequest) String() string { return proto.CompactTextString(m) }
 func (*TapRequest) ProtoMessage()    {}
 func (*TapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{8}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{8}
 }
 func (m *TapRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapRequest.Unmarshal(m, b)
@@ -968,7 +1056,13 @@ type TapByResourceRequest struct {
 	// Selects over events to be reported.
 	Match *TapByResourceRequest_Match `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
 	// Limits the number of events to be inspected.
-	MaxRps               float32  `protobuf:"fixed32,3,opt,name=maxRps,proto3" json:"maxRps,omitempty"`
+	MaxRps float32 `protobuf:"fixed32,3,opt,name=maxRps,proto3" json:"maxRps,omitempty"`
+	// Limits the number of pods to be tapped. If zero, all pods selected by
+	// `target` are tapped.
+	MaxPods uint32 `protobuf:"varint,4,opt,name=maxPods,proto3" json:"maxPods,omitempty"`
+	// Restricts the tap to the pods with these names. If empty, all pods
+	// selected by `target` are eligible.
+	Pods                 []string `protobuf:"bytes,5,rep,name=pods,proto3" json:"pods,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -978,7 +1072,7 @@ func (m *TapByResourceRequest) Reset()         { *m = TapByResourceRequest{} }
 func (m *TapByResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest) ProtoMessage()    {}
 func (*TapByResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{9}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{9}
 }
 func (m *TapByResourceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest.Unmarshal(m, b)
@@ -1019,6 +1113,20 @@ func (m *TapByResourceRequest) GetMaxRps() float32 {
 	return 0
 }
 
+func (m *TapByResourceRequest) GetMaxPods() uint32 {
+	if m != nil {
+		return m.MaxPods
+	}
+	return 0
+}
+
+func (m *TapByResourceRequest) GetPods() []string {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
 type TapByResourceRequest_Match struct {
 	// Types that are valid to be assigned to Match:
 	//	*TapByResourceRequest_Match_All
@@ -1036,7 +1144,7 @@ func (m *TapByResourceRequest_Match) Reset()         { *m = TapByResourceRequest
 func (m *TapByResourceRequest_Match) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match) ProtoMessage()    {}
 func (*TapByResourceRequest_Match) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{9, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{9, 0}
 }
 func (m *TapByResourceRequest_Match) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match.Unmarshal(m, b)
@@ -1274,7 +1382,7 @@ func (m *TapByResourceRequest_Match_Seq) Reset()         { *m = TapByResourceReq
 func (m *TapByResourceRequest_Match_Seq) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Seq) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Seq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{9, 0, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{9, 0, 0}
 }
 func (m *TapByResourceRequest_Match_Seq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Seq.Unmarshal(m, b)
@@ -1317,7 +1425,7 @@ func (m *TapByResourceRequest_Match_Http) Reset()         { *m = TapByResourceRe
 func (m *TapByResourceRequest_Match_Http) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Http) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{9, 0, 1}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{9, 0, 1}
 }
 func (m *TapByResourceRequest_Match_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Http.Unmarshal(m, b)
@@ -1510,7 +1618,7 @@ func (m *HttpMethod) Reset()         { *m = HttpMethod{} }
 func (m *HttpMethod) String() string { return proto.CompactTextString(m) }
 func (*HttpMethod) ProtoMessage()    {}
 func (*HttpMethod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{10}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{10}
 }
 func (m *HttpMethod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HttpMethod.Unmarshal(m, b)
@@ -1646,7 +1754,7 @@ func (m *Scheme) Reset()         { *m = Scheme{} }
 func (m *Scheme) String() string { return proto.CompactTextString(m) }
 func (*Scheme) ProtoMessage()    {}
 func (*Scheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{11}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{11}
 }
 func (m *Scheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Scheme.Unmarshal(m, b)
@@ -1782,7 +1890,7 @@ func (m *IPAddress) Reset()         { *m = IPAddress{} }
 func (m *IPAddress) String() string { return proto.CompactTextString(m) }
 func (*IPAddress) ProtoMessage()    {}
 func (*IPAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{12}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{12}
 }
 func (m *IPAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPAddress.Unmarshal(m, b)
@@ -1920,7 +2028,7 @@ func (m *IPv6) Reset()         { *m = IPv6{} }
 func (m *IPv6) String() string { return proto.CompactTextString(m) }
 func (*IPv6) ProtoMessage()    {}
 func (*IPv6) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{13}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{13}
 }
 func (m *IPv6) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPv6.Unmarshal(m, b)
@@ -1966,7 +2074,7 @@ func (m *TcpAddress) Reset()         { *m = TcpAddress{} }
 func (m *TcpAddress) String() string { return proto.CompactTextString(m) }
 func (*TcpAddress) ProtoMessage()    {}
 func (*TcpAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{14}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{14}
 }
 func (m *TcpAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TcpAddress.Unmarshal(m, b)
@@ -2014,7 +2122,7 @@ func (m *Eos) Reset()         { *m = Eos{} }
 func (m *Eos) String() string { return proto.CompactTextString(m) }
 func (*Eos) ProtoMessage()    {}
 func (*Eos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{15}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{15}
 }
 func (m *Eos) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Eos.Unmarshal(m, b)
@@ -2154,7 +2262,7 @@ func (m *TapEvent) Reset()         { *m = TapEvent{} }
 func (m *TapEvent) String() string { return proto.CompactTextString(m) }
 func (*TapEvent) ProtoMessage()    {}
 func (*TapEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16}
 }
 func (m *TapEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent.Unmarshal(m, b)
@@ -2306,7 +2414,7 @@ func (m *TapEvent_EndpointMeta) Reset()         { *m = TapEvent_EndpointMeta{} }
 func (m *TapEvent_EndpointMeta) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_EndpointMeta) ProtoMessage()    {}
 func (*TapEvent_EndpointMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 0}
 }
 func (m *TapEvent_EndpointMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_EndpointMeta.Unmarshal(m, b)
@@ -2344,7 +2452,7 @@ func (m *TapEvent_RouteMeta) Reset()         { *m = TapEvent_RouteMeta{} }
 func (m *TapEvent_RouteMeta) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_RouteMeta) ProtoMessage()    {}
 func (*TapEvent_RouteMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 1}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 1}
 }
 func (m *TapEvent_RouteMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_RouteMeta.Unmarshal(m, b)
@@ -2386,7 +2494,7 @@ func (m *TapEvent_Http) Reset()         { *m = TapEvent_Http{} }
 func (m *TapEvent_Http) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http) ProtoMessage()    {}
 func (*TapEvent_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 2}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 2}
 }
 func (m *TapEvent_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http.Unmarshal(m, b)
@@ -2563,7 +2671,7 @@ func (m *TapEvent_Http_StreamId) Reset()         { *m = TapEvent_Http_StreamId{}
 func (m *TapEvent_Http_StreamId) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_StreamId) ProtoMessage()    {}
 func (*TapEvent_Http_StreamId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 2, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 2, 0}
 }
 func (m *TapEvent_Http_StreamId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_StreamId.Unmarshal(m, b)
@@ -2612,7 +2720,7 @@ func (m *TapEvent_Http_RequestInit) Reset()         { *m = TapEvent_Http_Request
 func (m *TapEvent_Http_RequestInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_RequestInit) ProtoMessage()    {}
 func (*TapEvent_Http_RequestInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 2, 1}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 2, 1}
 }
 func (m *TapEvent_Http_RequestInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_RequestInit.Unmarshal(m, b)
@@ -2680,7 +2788,7 @@ func (m *TapEvent_Http_ResponseInit) Reset()         { *m = TapEvent_Http_Respon
 func (m *TapEvent_Http_ResponseInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseInit) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 2, 2}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 2, 2}
 }
 func (m *TapEvent_Http_ResponseInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseInit.Unmarshal(m, b)
@@ -2736,7 +2844,7 @@ func (m *TapEvent_Http_ResponseEnd) Reset()         { *m = TapEvent_Http_Respons
 func (m *TapEvent_Http_ResponseEnd) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseEnd) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseEnd) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{16, 2, 3}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{16, 2, 3}
 }
 func (m *TapEvent_Http_ResponseEnd) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseEnd.Unmarshal(m, b)
@@ -2802,7 +2910,7 @@ func (m *ApiError) Reset()         { *m = ApiError{} }
 func (m *ApiError) String() string { return proto.CompactTextString(m) }
 func (*ApiError) ProtoMessage()    {}
 func (*ApiError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{17}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{17}
 }
 func (m *ApiError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApiError.Unmarshal(m, b)
@@ -2840,7 +2948,7 @@ func (m *PodErrors) Reset()         { *m = PodErrors{} }
 func (m *PodErrors) String() string { return proto.CompactTextString(m) }
 func (*PodErrors) ProtoMessage()    {}
 func (*PodErrors) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{18}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{18}
 }
 func (m *PodErrors) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors.Unmarshal(m, b)
@@ -2880,7 +2988,7 @@ func (m *PodErrors_PodError) Reset()         { *m = PodErrors_PodError{} }
 func (m *PodErrors_PodError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError) ProtoMessage()    {}
 func (*PodErrors_PodError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{18, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{18, 0}
 }
 func (m *PodErrors_PodError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError.Unmarshal(m, b)
@@ -2994,7 +3102,7 @@ func (m *PodErrors_PodError_ContainerError) Reset()         { *m = PodErrors_Pod
 func (m *PodErrors_PodError_ContainerError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError_ContainerError) ProtoMessage()    {}
 func (*PodErrors_PodError_ContainerError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{18, 0, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{18, 0, 0}
 }
 func (m *PodErrors_PodError_ContainerError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError_ContainerError.Unmarshal(m, b)
@@ -3065,7 +3173,7 @@ func (m *Resource) Reset()         { *m = Resource{} }
 func (m *Resource) String() string { return proto.CompactTextString(m) }
 func (*Resource) ProtoMessage()    {}
 func (*Resource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{19}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{19}
 }
 func (m *Resource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Resource.Unmarshal(m, b)
@@ -3124,7 +3232,7 @@ func (m *ResourceSelection) Reset()         { *m = ResourceSelection{} }
 func (m *ResourceSelection) String() string { return proto.CompactTextString(m) }
 func (*ResourceSelection) ProtoMessage()    {}
 func (*ResourceSelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{20}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{20}
 }
 func (m *ResourceSelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceSelection.Unmarshal(m, b)
@@ -3170,7 +3278,7 @@ func (m *ResourceError) Reset()         { *m = ResourceError{} }
 func (m *ResourceError) String() string { return proto.CompactTextString(m) }
 func (*ResourceError) ProtoMessage()    {}
 func (*ResourceError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{21}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{21}
 }
 func (m *ResourceError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceError.Unmarshal(m, b)
@@ -3211,18 +3319,27 @@ type StatSummaryRequest struct {
 	//	*StatSummaryRequest_None
 	//	*StatSummaryRequest_ToResource
 	//	*StatSummaryRequest_FromResource
-	Outbound             isStatSummaryRequest_Outbound `protobuf_oneof:"outbound"`
-	SkipStats            bool                          `protobuf:"varint,6,opt,name=skip_stats,json=skipStats,proto3" json:"skip_stats,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Outbound  isStatSummaryRequest_Outbound `protobuf_oneof:"outbound"`
+	SkipStats bool                          `protobuf:"varint,6,opt,name=skip_stats,json=skipStats,proto3" json:"skip_stats,omitempty"`
+	// Only valid along with from_resource, to only count the requests sent to
+	// this resource.
+	ToFilter *Resource `protobuf:"bytes,7,opt,name=to_filter,json=toFilter,proto3" json:"to_filter,omitempty"`
+	// Direction of the requests counted without to_resource and from_resource:
+	// "inbound" (the default) for the requests the resources receive, or
+	// "outbound" for the requests they send.
+	Direction string `protobuf:"bytes,8,opt,name=direction,proto3" json:"direction,omitempty"`
+	// true to return the queries the stats were computed with
+	ShowQueries          bool     `protobuf:"varint,9,opt,name=show_queries,json=showQueries,proto3" json:"show_queries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StatSummaryRequest) Reset()         { *m = StatSummaryRequest{} }
 func (m *StatSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryRequest) ProtoMessage()    {}
 func (*StatSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{22}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{22}
 }
 func (m *StatSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryRequest.Unmarshal(m, b)
@@ -3313,6 +3430,27 @@ func (m *StatSummaryRequest) GetSkipStats() bool {
 	return false
 }
 
+func (m *StatSummaryRequest) GetToFilter() *Resource {
+	if m != nil {
+		return m.ToFilter
+	}
+	return nil
+}
+
+func (m *StatSummaryRequest) GetDirection() string {
+	if m != nil {
+		return m.Direction
+	}
+	return ""
+}
+
+func (m *StatSummaryRequest) GetShowQueries() bool {
+	if m != nil {
+		return m.ShowQueries
+	}
+	return false
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*StatSummaryRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _StatSummaryRequest_OneofMarshaler, _StatSummaryRequest_OneofUnmarshaler, _StatSummaryRequest_OneofSizer, []interface{}{
@@ -3420,7 +3558,7 @@ func (m *StatSummaryResponse) Reset()         { *m = StatSummaryResponse{} }
 func (m *StatSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse) ProtoMessage()    {}
 func (*StatSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{23}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{23}
 }
 func (m *StatSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse.Unmarshal(m, b)
@@ -3562,7 +3700,7 @@ func (m *StatSummaryResponse_Ok) Reset()         { *m = StatSummaryResponse_Ok{}
 func (m *StatSummaryResponse_Ok) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse_Ok) ProtoMessage()    {}
 func (*StatSummaryResponse_Ok) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{23, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{23, 0}
 }
 func (m *StatSummaryResponse_Ok) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse_Ok.Unmarshal(m, b)
@@ -3607,7 +3745,7 @@ func (m *BasicStats) Reset()         { *m = BasicStats{} }
 func (m *BasicStats) String() string { return proto.CompactTextString(m) }
 func (*BasicStats) ProtoMessage()    {}
 func (*BasicStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{24}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{24}
 }
 func (m *BasicStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BasicStats.Unmarshal(m, b)
@@ -3683,6 +3821,62 @@ func (m *BasicStats) GetActualFailureCount() uint64 {
 	return 0
 }
 
+// TrafficSplitStats describes one of the backends of a traffic split, as
+// declared by the dstOverrides of a ServiceProfile.
+type TrafficSplitStats struct {
+	Apex                 string   `protobuf:"bytes,1,opt,name=apex,proto3" json:"apex,omitempty"`
+	Leaf                 string   `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	Weight               uint32   `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrafficSplitStats) Reset()         { *m = TrafficSplitStats{} }
+func (m *TrafficSplitStats) String() string { return proto.CompactTextString(m) }
+func (*TrafficSplitStats) ProtoMessage()    {}
+func (*TrafficSplitStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{25}
+}
+func (m *TrafficSplitStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrafficSplitStats.Unmarshal(m, b)
+}
+func (m *TrafficSplitStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrafficSplitStats.Marshal(b, m, deterministic)
+}
+func (dst *TrafficSplitStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrafficSplitStats.Merge(dst, src)
+}
+func (m *TrafficSplitStats) XXX_Size() int {
+	return xxx_messageInfo_TrafficSplitStats.Size(m)
+}
+func (m *TrafficSplitStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrafficSplitStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrafficSplitStats proto.InternalMessageInfo
+
+func (m *TrafficSplitStats) GetApex() string {
+	if m != nil {
+		return m.Apex
+	}
+	return ""
+}
+
+func (m *TrafficSplitStats) GetLeaf() string {
+	if m != nil {
+		return m.Leaf
+	}
+	return ""
+}
+
+func (m *TrafficSplitStats) GetWeight() uint32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
 type StatTable struct {
 	// Types that are valid to be assigned to Table:
 	//	*StatTable_PodGroup_
@@ -3696,7 +3890,7 @@ func (m *StatTable) Reset()         { *m = StatTable{} }
 func (m *StatTable) String() string { return proto.CompactTextString(m) }
 func (*StatTable) ProtoMessage()    {}
 func (*StatTable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{25}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{26}
 }
 func (m *StatTable) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable.Unmarshal(m, b)
@@ -3796,17 +3990,20 @@ func _StatTable_OneofSizer(msg proto.Message) (n int) {
 }
 
 type StatTable_PodGroup struct {
-	Rows                 []*StatTable_PodGroup_Row `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
-	XXX_unrecognized     []byte                    `json:"-"`
-	XXX_sizecache        int32                     `json:"-"`
+	Rows []*StatTable_PodGroup_Row `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	// PromQL queries the stats of the rows were computed with, by stat, set
+	// if the request's show_queries is
+	Queries              map[string]string `protobuf:"bytes,2,rep,name=queries,proto3" json:"queries,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *StatTable_PodGroup) Reset()         { *m = StatTable_PodGroup{} }
 func (m *StatTable_PodGroup) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup) ProtoMessage()    {}
 func (*StatTable_PodGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{25, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{26, 0}
 }
 func (m *StatTable_PodGroup) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup.Unmarshal(m, b)
@@ -3833,6 +4030,13 @@ func (m *StatTable_PodGroup) GetRows() []*StatTable_PodGroup_Row {
 	return nil
 }
 
+func (m *StatTable_PodGroup) GetQueries() map[string]string {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
 type StatTable_PodGroup_Row struct {
 	Resource   *Resource `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
 	TimeWindow string    `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
@@ -3844,17 +4048,19 @@ type StatTable_PodGroup_Row struct {
 	FailedPodCount uint64      `protobuf:"varint,6,opt,name=failed_pod_count,json=failedPodCount,proto3" json:"failed_pod_count,omitempty"`
 	Stats          *BasicStats `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
 	// Stores a set of errors for each pod name. If a pod has no errors, it may be omitted.
-	ErrorsByPod          map[string]*PodErrors `protobuf:"bytes,7,rep,name=errors_by_pod,json=errorsByPod,proto3" json:"errors_by_pod,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	ErrorsByPod map[string]*PodErrors `protobuf:"bytes,7,rep,name=errors_by_pod,json=errorsByPod,proto3" json:"errors_by_pod,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// set for traffic split rows, which have one row per backend
+	TsStats              *TrafficSplitStats `protobuf:"bytes,8,opt,name=ts_stats,json=tsStats,proto3" json:"ts_stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *StatTable_PodGroup_Row) Reset()         { *m = StatTable_PodGroup_Row{} }
 func (m *StatTable_PodGroup_Row) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup_Row) ProtoMessage()    {}
 func (*StatTable_PodGroup_Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{25, 0, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{26, 0, 0}
 }
 func (m *StatTable_PodGroup_Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup_Row.Unmarshal(m, b)
@@ -3923,6 +4129,13 @@ func (m *StatTable_PodGroup_Row) GetErrorsByPod() map[string]*PodErrors {
 	return nil
 }
 
+func (m *StatTable_PodGroup_Row) GetTsStats() *TrafficSplitStats {
+	if m != nil {
+		return m.TsStats
+	}
+	return nil
+}
+
 type TopRoutesRequest struct {
 	Selector   *ResourceSelection `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
 	TimeWindow string             `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
@@ -3939,7 +4152,7 @@ func (m *TopRoutesRequest) Reset()         { *m = TopRoutesRequest{} }
 func (m *TopRoutesRequest) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesRequest) ProtoMessage()    {}
 func (*TopRoutesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{26}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{27}
 }
 func (m *TopRoutesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesRequest.Unmarshal(m, b)
@@ -4098,7 +4311,7 @@ func (m *TopRoutesResponse) Reset()         { *m = TopRoutesResponse{} }
 func (m *TopRoutesResponse) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesResponse) ProtoMessage()    {}
 func (*TopRoutesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{27}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{28}
 }
 func (m *TopRoutesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesResponse.Unmarshal(m, b)
@@ -4240,7 +4453,7 @@ func (m *TopRoutesResponse_Ok) Reset()         { *m = TopRoutesResponse_Ok{} }
 func (m *TopRoutesResponse_Ok) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesResponse_Ok) ProtoMessage()    {}
 func (*TopRoutesResponse_Ok) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{27, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{28, 0}
 }
 func (m *TopRoutesResponse_Ok) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesResponse_Ok.Unmarshal(m, b)
@@ -4279,7 +4492,7 @@ func (m *RouteTable) Reset()         { *m = RouteTable{} }
 func (m *RouteTable) String() string { return proto.CompactTextString(m) }
 func (*RouteTable) ProtoMessage()    {}
 func (*RouteTable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{28}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{29}
 }
 func (m *RouteTable) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteTable.Unmarshal(m, b)
@@ -4314,20 +4527,22 @@ func (m *RouteTable) GetResource() string {
 }
 
 type RouteTable_Row struct {
-	Route                string      `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
-	TimeWindow           string      `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
-	Authority            string      `protobuf:"bytes,6,opt,name=authority,proto3" json:"authority,omitempty"`
-	Stats                *BasicStats `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Route      string      `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	TimeWindow string      `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	Authority  string      `protobuf:"bytes,6,opt,name=authority,proto3" json:"authority,omitempty"`
+	Stats      *BasicStats `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	// set for the routes of the service profiles that define SLOs
+	Slo                  *RouteTable_SLO `protobuf:"bytes,7,opt,name=slo,proto3" json:"slo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *RouteTable_Row) Reset()         { *m = RouteTable_Row{} }
 func (m *RouteTable_Row) String() string { return proto.CompactTextString(m) }
 func (*RouteTable_Row) ProtoMessage()    {}
 func (*RouteTable_Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ef1048c97ea7182a, []int{28, 0}
+	return fileDescriptor_public_4ca5e97d258b6401, []int{29, 0}
 }
 func (m *RouteTable_Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteTable_Row.Unmarshal(m, b)
@@ -4375,6 +4590,383 @@ func (m *RouteTable_Row) GetStats() *BasicStats {
 	return nil
 }
 
+func (m *RouteTable_Row) GetSlo() *RouteTable_SLO {
+	if m != nil {
+		return m.Slo
+	}
+	return nil
+}
+
+// The service level objectives of a route, and whether they were met over
+// the time window.
+type RouteTable_SLO struct {
+	// the objective success rate, between 0 and 1, or 0 if there is none
+	SuccessRateObjective float64 `protobuf:"fixed64,1,opt,name=success_rate_objective,json=successRateObjective,proto3" json:"success_rate_objective,omitempty"`
+	// the objective latency of the latency_percentile percentile, or 0 if
+	// there is none
+	LatencyObjectiveMs uint64 `protobuf:"varint,2,opt,name=latency_objective_ms,json=latencyObjectiveMs,proto3" json:"latency_objective_ms,omitempty"`
+	LatencyPercentile  uint32 `protobuf:"varint,3,opt,name=latency_percentile,json=latencyPercentile,proto3" json:"latency_percentile,omitempty"`
+	SuccessRateMet     bool   `protobuf:"varint,4,opt,name=success_rate_met,json=successRateMet,proto3" json:"success_rate_met,omitempty"`
+	LatencyMet         bool   `protobuf:"varint,5,opt,name=latency_met,json=latencyMet,proto3" json:"latency_met,omitempty"`
+	// the share of the error budget of the success rate objective consumed
+	// over the time window, which is over 1 once it's exhausted
+	ErrorBudgetBurn      float64  `protobuf:"fixed64,6,opt,name=error_budget_burn,json=errorBudgetBurn,proto3" json:"error_budget_burn,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RouteTable_SLO) Reset()         { *m = RouteTable_SLO{} }
+func (m *RouteTable_SLO) String() string { return proto.CompactTextString(m) }
+func (*RouteTable_SLO) ProtoMessage()    {}
+func (*RouteTable_SLO) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{29, 1}
+}
+func (m *RouteTable_SLO) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RouteTable_SLO.Unmarshal(m, b)
+}
+func (m *RouteTable_SLO) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RouteTable_SLO.Marshal(b, m, deterministic)
+}
+func (dst *RouteTable_SLO) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RouteTable_SLO.Merge(dst, src)
+}
+func (m *RouteTable_SLO) XXX_Size() int {
+	return xxx_messageInfo_RouteTable_SLO.Size(m)
+}
+func (m *RouteTable_SLO) XXX_DiscardUnknown() {
+	xxx_messageInfo_RouteTable_SLO.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RouteTable_SLO proto.InternalMessageInfo
+
+func (m *RouteTable_SLO) GetSuccessRateObjective() float64 {
+	if m != nil {
+		return m.SuccessRateObjective
+	}
+	return 0
+}
+
+func (m *RouteTable_SLO) GetLatencyObjectiveMs() uint64 {
+	if m != nil {
+		return m.LatencyObjectiveMs
+	}
+	return 0
+}
+
+func (m *RouteTable_SLO) GetLatencyPercentile() uint32 {
+	if m != nil {
+		return m.LatencyPercentile
+	}
+	return 0
+}
+
+func (m *RouteTable_SLO) GetSuccessRateMet() bool {
+	if m != nil {
+		return m.SuccessRateMet
+	}
+	return false
+}
+
+func (m *RouteTable_SLO) GetLatencyMet() bool {
+	if m != nil {
+		return m.LatencyMet
+	}
+	return false
+}
+
+func (m *RouteTable_SLO) GetErrorBudgetBurn() float64 {
+	if m != nil {
+		return m.ErrorBudgetBurn
+	}
+	return 0
+}
+
+type EdgesRequest struct {
+	Selector             *ResourceSelection `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	TimeWindow           string             `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *EdgesRequest) Reset()         { *m = EdgesRequest{} }
+func (m *EdgesRequest) String() string { return proto.CompactTextString(m) }
+func (*EdgesRequest) ProtoMessage()    {}
+func (*EdgesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{30}
+}
+func (m *EdgesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesRequest.Unmarshal(m, b)
+}
+func (m *EdgesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesRequest.Marshal(b, m, deterministic)
+}
+func (dst *EdgesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesRequest.Merge(dst, src)
+}
+func (m *EdgesRequest) XXX_Size() int {
+	return xxx_messageInfo_EdgesRequest.Size(m)
+}
+func (m *EdgesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgesRequest proto.InternalMessageInfo
+
+func (m *EdgesRequest) GetSelector() *ResourceSelection {
+	if m != nil {
+		return m.Selector
+	}
+	return nil
+}
+
+func (m *EdgesRequest) GetTimeWindow() string {
+	if m != nil {
+		return m.TimeWindow
+	}
+	return ""
+}
+
+type EdgesResponse struct {
+	// Types that are valid to be assigned to Response:
+	//	*EdgesResponse_Ok_
+	//	*EdgesResponse_Error
+	Response             isEdgesResponse_Response `protobuf_oneof:"response"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *EdgesResponse) Reset()         { *m = EdgesResponse{} }
+func (m *EdgesResponse) String() string { return proto.CompactTextString(m) }
+func (*EdgesResponse) ProtoMessage()    {}
+func (*EdgesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{31}
+}
+func (m *EdgesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesResponse.Unmarshal(m, b)
+}
+func (m *EdgesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesResponse.Marshal(b, m, deterministic)
+}
+func (dst *EdgesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesResponse.Merge(dst, src)
+}
+func (m *EdgesResponse) XXX_Size() int {
+	return xxx_messageInfo_EdgesResponse.Size(m)
+}
+func (m *EdgesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgesResponse proto.InternalMessageInfo
+
+type isEdgesResponse_Response interface {
+	isEdgesResponse_Response()
+}
+
+type EdgesResponse_Ok_ struct {
+	Ok *EdgesResponse_Ok `protobuf:"bytes,1,opt,name=ok,proto3,oneof"`
+}
+
+type EdgesResponse_Error struct {
+	Error *ResourceError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*EdgesResponse_Ok_) isEdgesResponse_Response() {}
+
+func (*EdgesResponse_Error) isEdgesResponse_Response() {}
+
+func (m *EdgesResponse) GetResponse() isEdgesResponse_Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *EdgesResponse) GetOk() *EdgesResponse_Ok {
+	if x, ok := m.GetResponse().(*EdgesResponse_Ok_); ok {
+		return x.Ok
+	}
+	return nil
+}
+
+func (m *EdgesResponse) GetError() *ResourceError {
+	if x, ok := m.GetResponse().(*EdgesResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*EdgesResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _EdgesResponse_OneofMarshaler, _EdgesResponse_OneofUnmarshaler, _EdgesResponse_OneofSizer, []interface{}{
+		(*EdgesResponse_Ok_)(nil),
+		(*EdgesResponse_Error)(nil),
+	}
+}
+
+func _EdgesResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*EdgesResponse)
+	// response
+	switch x := m.Response.(type) {
+	case *EdgesResponse_Ok_:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Ok); err != nil {
+			return err
+		}
+	case *EdgesResponse_Error:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Error); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("EdgesResponse.Response has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _EdgesResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*EdgesResponse)
+	switch tag {
+	case 1: // response.ok
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(EdgesResponse_Ok)
+		err := b.DecodeMessage(msg)
+		m.Response = &EdgesResponse_Ok_{msg}
+		return true, err
+	case 2: // response.error
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ResourceError)
+		err := b.DecodeMessage(msg)
+		m.Response = &EdgesResponse_Error{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _EdgesResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*EdgesResponse)
+	// response
+	switch x := m.Response.(type) {
+	case *EdgesResponse_Ok_:
+		s := proto.Size(x.Ok)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *EdgesResponse_Error:
+		s := proto.Size(x.Error)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type EdgesResponse_Ok struct {
+	Edges                []*Edge  `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EdgesResponse_Ok) Reset()         { *m = EdgesResponse_Ok{} }
+func (m *EdgesResponse_Ok) String() string { return proto.CompactTextString(m) }
+func (*EdgesResponse_Ok) ProtoMessage()    {}
+func (*EdgesResponse_Ok) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{31, 0}
+}
+func (m *EdgesResponse_Ok) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesResponse_Ok.Unmarshal(m, b)
+}
+func (m *EdgesResponse_Ok) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesResponse_Ok.Marshal(b, m, deterministic)
+}
+func (dst *EdgesResponse_Ok) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesResponse_Ok.Merge(dst, src)
+}
+func (m *EdgesResponse_Ok) XXX_Size() int {
+	return xxx_messageInfo_EdgesResponse_Ok.Size(m)
+}
+func (m *EdgesResponse_Ok) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesResponse_Ok.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgesResponse_Ok proto.InternalMessageInfo
+
+func (m *EdgesResponse_Ok) GetEdges() []*Edge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+// Edge is a directed edge of the graph of resources, from a resource whose
+// proxies sent requests to another resource, with the stats of the requests.
+type Edge struct {
+	Src                  *Resource   `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Dst                  *Resource   `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
+	Stats                *BasicStats `protobuf:"bytes,3,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Edge) Reset()         { *m = Edge{} }
+func (m *Edge) String() string { return proto.CompactTextString(m) }
+func (*Edge) ProtoMessage()    {}
+func (*Edge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_4ca5e97d258b6401, []int{32}
+}
+func (m *Edge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Edge.Unmarshal(m, b)
+}
+func (m *Edge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Edge.Marshal(b, m, deterministic)
+}
+func (dst *Edge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Edge.Merge(dst, src)
+}
+func (m *Edge) XXX_Size() int {
+	return xxx_messageInfo_Edge.Size(m)
+}
+func (m *Edge) XXX_DiscardUnknown() {
+	xxx_messageInfo_Edge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Edge proto.InternalMessageInfo
+
+func (m *Edge) GetSrc() *Resource {
+	if m != nil {
+		return m.Src
+	}
+	return nil
+}
+
+func (m *Edge) GetDst() *Resource {
+	if m != nil {
+		return m.Dst
+	}
+	return nil
+}
+
+func (m *Edge) GetStats() *BasicStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "linkerd2.public.Empty")
 	proto.RegisterType((*VersionInfo)(nil), "linkerd2.public.VersionInfo")
@@ -4384,6 +4976,7 @@ func init() {
 	proto.RegisterType((*ListPodsRequest)(nil), "linkerd2.public.ListPodsRequest")
 	proto.RegisterType((*ListPodsResponse)(nil), "linkerd2.public.ListPodsResponse")
 	proto.RegisterType((*Pod)(nil), "linkerd2.public.Pod")
+	proto.RegisterType((*Pod_ProxyResources)(nil), "linkerd2.public.Pod.ProxyResources")
 	proto.RegisterType((*TapRequest)(nil), "linkerd2.public.TapRequest")
 	proto.RegisterType((*TapByResourceRequest)(nil), "linkerd2.public.TapByResourceRequest")
 	proto.RegisterType((*TapByResourceRequest_Match)(nil), "linkerd2.public.TapByResourceRequest.Match")
@@ -4416,8 +5009,10 @@ func init() {
 	proto.RegisterType((*StatSummaryResponse)(nil), "linkerd2.public.StatSummaryResponse")
 	proto.RegisterType((*StatSummaryResponse_Ok)(nil), "linkerd2.public.StatSummaryResponse.Ok")
 	proto.RegisterType((*BasicStats)(nil), "linkerd2.public.BasicStats")
+	proto.RegisterType((*TrafficSplitStats)(nil), "linkerd2.public.TrafficSplitStats")
 	proto.RegisterType((*StatTable)(nil), "linkerd2.public.StatTable")
 	proto.RegisterType((*StatTable_PodGroup)(nil), "linkerd2.public.StatTable.PodGroup")
+	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.StatTable.PodGroup.QueriesEntry")
 	proto.RegisterType((*StatTable_PodGroup_Row)(nil), "linkerd2.public.StatTable.PodGroup.Row")
 	proto.RegisterMapType((map[string]*PodErrors)(nil), "linkerd2.public.StatTable.PodGroup.Row.ErrorsByPodEntry")
 	proto.RegisterType((*TopRoutesRequest)(nil), "linkerd2.public.TopRoutesRequest")
@@ -4425,6 +5020,11 @@ func init() {
 	proto.RegisterType((*TopRoutesResponse_Ok)(nil), "linkerd2.public.TopRoutesResponse.Ok")
 	proto.RegisterType((*RouteTable)(nil), "linkerd2.public.RouteTable")
 	proto.RegisterType((*RouteTable_Row)(nil), "linkerd2.public.RouteTable.Row")
+	proto.RegisterType((*RouteTable_SLO)(nil), "linkerd2.public.RouteTable.SLO")
+	proto.RegisterType((*EdgesRequest)(nil), "linkerd2.public.EdgesRequest")
+	proto.RegisterType((*EdgesResponse)(nil), "linkerd2.public.EdgesResponse")
+	proto.RegisterType((*EdgesResponse_Ok)(nil), "linkerd2.public.EdgesResponse.Ok")
+	proto.RegisterType((*Edge)(nil), "linkerd2.public.Edge")
 	proto.RegisterEnum("linkerd2.public.HttpMethod_Registered", HttpMethod_Registered_name, HttpMethod_Registered_value)
 	proto.RegisterEnum("linkerd2.public.Scheme_Registered", Scheme_Registered_name, Scheme_Registered_value)
 	proto.RegisterEnum("linkerd2.public.TapEvent_ProxyDirection", TapEvent_ProxyDirection_name, TapEvent_ProxyDirection_value)
@@ -4444,6 +5044,9 @@ const _ = grpc.SupportPackageIsVersion4
 type ApiClient interface {
 	StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error)
 	TopRoutes(ctx context.Context, in *TopRoutesRequest, opts ...grpc.CallOption) (*TopRoutesResponse, error)
+	// Returns the edges between the selected resources and the resources they
+	// send requests to, or receive requests from.
+	Edges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (*EdgesResponse, error)
 	ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error)
 	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
 	// Superceded by `TapByResource`.
@@ -4480,6 +5083,15 @@ func (c *apiClient) TopRoutes(ctx context.Context, in *TopRoutesRequest, opts ..
 	return out, nil
 }
 
+func (c *apiClient) Edges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (*EdgesResponse, error) {
+	out := new(EdgesResponse)
+	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/Edges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *apiClient) ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error) {
 	out := new(ListPodsResponse)
 	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/ListPods", in, out, opts...)
@@ -4585,6 +5197,9 @@ func (c *apiClient) SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequ
 type ApiServer interface {
 	StatSummary(context.Context, *StatSummaryRequest) (*StatSummaryResponse, error)
 	TopRoutes(context.Context, *TopRoutesRequest) (*TopRoutesResponse, error)
+	// Returns the edges between the selected resources and the resources they
+	// send requests to, or receive requests from.
+	Edges(context.Context, *EdgesRequest) (*EdgesResponse, error)
 	ListPods(context.Context, *ListPodsRequest) (*ListPodsResponse, error)
 	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
 	// Superceded by `TapByResource`.
@@ -4635,6 +5250,24 @@ func _Api_TopRoutes_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_Edges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EdgesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Edges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkerd2.public.Api/Edges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Edges(ctx, req.(*EdgesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_ListPods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPodsRequest)
 	if err := dec(in); err != nil {
@@ -4761,6 +5394,10 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TopRoutes",
 			Handler:    _Api_TopRoutes_Handler,
 		},
+		{
+			MethodName: "Edges",
+			Handler:    _Api_Edges_Handler,
+		},
 		{
 			MethodName: "ListPods",
 			Handler:    _Api_ListPods_Handler,
@@ -4793,185 +5430,216 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 	Metadata: "public.proto",
 }
 
-func init() { proto.RegisterFile("public.proto", fileDescriptor_public_ef1048c97ea7182a) }
-
-var fileDescriptor_public_ef1048c97ea7182a = []byte{
-	// 2820 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x1a, 0x4d, 0x73, 0x1b, 0x49,
-	0x55, 0xa3, 0x6f, 0x3d, 0xc9, 0xb6, 0xd2, 0xf1, 0x06, 0xed, 0xec, 0x92, 0x75, 0x26, 0x1f, 0xeb,
-	0xca, 0x82, 0xec, 0x75, 0x36, 0xd9, 0xf5, 0x66, 0xf9, 0xb0, 0x6c, 0x6d, 0x6c, 0x70, 0x6c, 0x6d,
-	0x4b, 0x61, 0xab, 0x52, 0x4b, 0xa9, 0xc6, 0x9a, 0xb6, 0x3d, 0x78, 0x34, 0x3d, 0x99, 0x69, 0x25,
-	0xab, 0x7f, 0xc0, 0x85, 0xe2, 0x02, 0x67, 0xce, 0x70, 0xe3, 0x02, 0xff, 0x01, 0xaa, 0xb8, 0x51,
-	0x70, 0x82, 0x1b, 0x17, 0x8a, 0xe2, 0xc2, 0x89, 0x03, 0x45, 0xf5, 0xd7, 0x68, 0xc6, 0x92, 0xbf,
-	0x02, 0x07, 0x38, 0x79, 0xde, 0xeb, 0xf7, 0x5e, 0xbf, 0xaf, 0x7e, 0xaf, 0x5f, 0xcb, 0x50, 0x0b,
-	0x46, 0x07, 0x9e, 0x3b, 0x68, 0x06, 0x21, 0x65, 0x14, 0x2d, 0x78, 0xae, 0x7f, 0x42, 0x42, 0x67,
-	0xad, 0x29, 0xd1, 0xe6, 0xcd, 0x23, 0x4a, 0x8f, 0x3c, 0xb2, 0x22, 0x96, 0x0f, 0x46, 0x87, 0x2b,
-	0xce, 0x28, 0xb4, 0x99, 0x4b, 0x7d, 0xc9, 0x60, 0x36, 0x06, 0x74, 0x38, 0xa4, 0xfe, 0xca, 0x31,
-	0xb1, 0x3d, 0x76, 0x3c, 0x38, 0x26, 0x83, 0x13, 0xb9, 0x62, 0x95, 0xa0, 0xd0, 0x1e, 0x06, 0x6c,
-	0x6c, 0xbd, 0x80, 0xea, 0xf7, 0x48, 0x18, 0xb9, 0xd4, 0xdf, 0xf1, 0x0f, 0x29, 0x7a, 0x1b, 0x2a,
-	0x47, 0x54, 0x21, 0x1a, 0xc6, 0x92, 0xb1, 0x5c, 0xc1, 0x13, 0x04, 0x5f, 0x3d, 0x18, 0xb9, 0x9e,
-	0xb3, 0x65, 0x33, 0xd2, 0xc8, 0xca, 0xd5, 0x18, 0x81, 0xee, 0xc1, 0x7c, 0x48, 0x3c, 0x62, 0x47,
-	0x44, 0x0b, 0xc8, 0x09, 0x92, 0x53, 0x58, 0xeb, 0x01, 0x5c, 0xdf, 0x75, 0x23, 0xd6, 0x25, 0xe1,
-	0x4b, 0x77, 0x40, 0x22, 0x4c, 0x5e, 0x8c, 0x48, 0xc4, 0xb8, 0x70, 0xdf, 0x1e, 0x92, 0x28, 0xb0,
-	0x07, 0x44, 0x6f, 0x1d, 0x23, 0xac, 0x5d, 0x58, 0x4c, 0x33, 0x45, 0x01, 0xf5, 0x23, 0x82, 0x3e,
-	0x80, 0x72, 0xa4, 0x70, 0x0d, 0x63, 0x29, 0xb7, 0x5c, 0x5d, 0x6b, 0x34, 0x4f, 0xb9, 0xa9, 0xa9,
-	0x98, 0x70, 0x4c, 0x69, 0x3d, 0x86, 0x92, 0x42, 0x22, 0x04, 0x79, 0xbe, 0x8b, 0xda, 0x51, 0x7c,
-	0xa7, 0x55, 0xc9, 0x9e, 0x56, 0x25, 0x82, 0x05, 0xae, 0x4a, 0x87, 0x3a, 0xb1, 0xee, 0x4b, 0x53,
-	0xba, 0xb7, 0xb2, 0x0d, 0x23, 0xc1, 0x84, 0xbe, 0xc9, 0xf5, 0xf4, 0xc8, 0x80, 0xd1, 0x50, 0x48,
-	0xac, 0xae, 0x59, 0x53, 0x7a, 0x62, 0x12, 0xd1, 0x51, 0x38, 0x20, 0x5d, 0x41, 0xe8, 0x52, 0x1f,
-	0xc7, 0x3c, 0xd6, 0x27, 0x50, 0x9f, 0x6c, 0xaa, 0x6c, 0x5f, 0x86, 0x7c, 0x40, 0x1d, 0x6d, 0xf7,
-	0xe2, 0x94, 0xbc, 0x0e, 0x75, 0xb0, 0xa0, 0xb0, 0xfe, 0x99, 0x87, 0x5c, 0x87, 0x3a, 0x33, 0x8d,
-	0x5d, 0x84, 0x42, 0x40, 0x9d, 0x9d, 0x8e, 0x32, 0x54, 0x02, 0x68, 0x09, 0xc0, 0x21, 0x81, 0x47,
-	0xc7, 0x43, 0xe2, 0x33, 0x19, 0xc8, 0xed, 0x0c, 0x4e, 0xe0, 0xd0, 0x2d, 0xa8, 0x86, 0x24, 0xf0,
-	0xdc, 0x81, 0xdd, 0x8f, 0x08, 0x6b, 0x80, 0x26, 0x51, 0xc8, 0x2e, 0x61, 0xe8, 0x43, 0xb8, 0xa1,
-	0x20, 0x6e, 0x4d, 0x7f, 0x40, 0x7d, 0x16, 0x52, 0xcf, 0x23, 0x61, 0xa3, 0xaa, 0xa8, 0xdf, 0x48,
-	0xac, 0x6f, 0xc6, 0xcb, 0xe8, 0x36, 0xd4, 0x22, 0x66, 0x33, 0x72, 0x38, 0xf2, 0x84, 0xf0, 0x9a,
-	0x22, 0xaf, 0x6a, 0x2c, 0x97, 0xfe, 0x0e, 0x80, 0x63, 0x93, 0x21, 0xf5, 0x05, 0xc9, 0x9c, 0x22,
-	0xa9, 0x48, 0x1c, 0x27, 0x40, 0x90, 0xfb, 0x01, 0x3d, 0x68, 0xcc, 0xab, 0x15, 0x0e, 0xa0, 0x1b,
-	0x50, 0xe4, 0x32, 0x46, 0x51, 0x23, 0x2f, 0xcc, 0x55, 0x10, 0xf7, 0x82, 0xed, 0x38, 0xc4, 0x69,
-	0x14, 0x96, 0x8c, 0xe5, 0x32, 0x96, 0x00, 0xda, 0x84, 0x85, 0xc8, 0xf5, 0x07, 0x64, 0xd7, 0x8e,
-	0x18, 0x26, 0x01, 0x0d, 0x59, 0xa3, 0x28, 0x82, 0xf7, 0x66, 0x53, 0x1e, 0xbd, 0xa6, 0x3e, 0x7a,
-	0xcd, 0x2d, 0x75, 0xf4, 0xf0, 0x69, 0x0e, 0xb4, 0x0a, 0xd7, 0x27, 0x96, 0xef, 0xc5, 0x69, 0x52,
-	0x12, 0xfb, 0xcf, 0x5a, 0x42, 0x16, 0xd4, 0x14, 0xba, 0xe3, 0xd9, 0x3e, 0x69, 0x94, 0x85, 0x4e,
-	0x29, 0x1c, 0x7a, 0x1f, 0x8a, 0xa3, 0x80, 0xb9, 0x43, 0xd2, 0xa8, 0x5c, 0xa4, 0x91, 0x22, 0x44,
-	0x37, 0x01, 0x82, 0x90, 0x7e, 0x39, 0xc6, 0xc4, 0x76, 0xc6, 0x8d, 0x05, 0x21, 0x34, 0x81, 0xe1,
-	0xdb, 0x0a, 0x48, 0x1f, 0xdf, 0xba, 0xd0, 0x30, 0x85, 0x43, 0xcb, 0xb0, 0x10, 0xaa, 0x34, 0xd5,
-	0x64, 0xd7, 0x04, 0xd9, 0x69, 0x74, 0xab, 0x04, 0x05, 0xfa, 0xca, 0x27, 0xa1, 0xf5, 0x8b, 0x2c,
-	0x40, 0xcf, 0x0e, 0xf4, 0x59, 0x41, 0x90, 0x0b, 0xa8, 0x23, 0x53, 0x90, 0x47, 0x25, 0xa0, 0xce,
-	0xa9, 0x6c, 0xcb, 0xce, 0xc8, 0xb6, 0x1b, 0x50, 0x1c, 0xda, 0x5f, 0xe2, 0x20, 0x12, 0xb9, 0x98,
-	0xc5, 0x0a, 0xe2, 0x78, 0x46, 0x3b, 0x3c, 0x30, 0x3c, 0x9e, 0x73, 0x58, 0x41, 0x3c, 0xd3, 0x19,
-	0xdd, 0xe9, 0x88, 0x70, 0x56, 0xb0, 0xf8, 0x46, 0x26, 0x94, 0x0f, 0x43, 0x3a, 0xec, 0xe8, 0x30,
-	0xce, 0xe1, 0x18, 0xe6, 0x72, 0xf8, 0xf7, 0x4e, 0x47, 0xc5, 0x45, 0x41, 0x22, 0x5f, 0x06, 0xc7,
-	0x64, 0x28, 0x83, 0xc0, 0xf3, 0x45, 0x40, 0x42, 0x1f, 0xc2, 0x8e, 0xa9, 0x23, 0xdc, 0x5f, 0xc1,
-	0x0a, 0xe2, 0xa5, 0xc3, 0x1e, 0xb1, 0x63, 0x1a, 0xba, 0x6c, 0x2c, 0xcf, 0x04, 0x9e, 0x20, 0xb8,
-	0x56, 0x81, 0xcd, 0x8e, 0x65, 0xfa, 0x63, 0xf1, 0xfd, 0x71, 0xb6, 0x61, 0xb4, 0xca, 0x50, 0x64,
-	0x76, 0x78, 0x44, 0x98, 0xf5, 0x97, 0x02, 0x2c, 0xf6, 0xec, 0xa0, 0x35, 0xd6, 0xc5, 0x40, 0xbb,
-	0xed, 0x63, 0x4d, 0x22, 0x3c, 0x77, 0xb9, 0xf2, 0xa1, 0x38, 0xd0, 0x06, 0x14, 0x86, 0x36, 0x1b,
-	0x1c, 0xab, 0xca, 0xf3, 0xde, 0x14, 0xeb, 0xac, 0x1d, 0x9b, 0x4f, 0x39, 0x0b, 0x96, 0x9c, 0x67,
-	0xf9, 0xdf, 0xfc, 0x55, 0x1e, 0x0a, 0x82, 0x10, 0x6d, 0x42, 0xce, 0xf6, 0x3c, 0xa5, 0xdd, 0xca,
-	0x15, 0xb6, 0x68, 0x76, 0xc9, 0x0b, 0x9e, 0x08, 0xb6, 0xe7, 0x09, 0x21, 0xfe, 0x58, 0xe9, 0xf9,
-	0x5a, 0x42, 0xfc, 0x31, 0xfa, 0x16, 0xe4, 0x7c, 0x2a, 0x8b, 0xd6, 0xd5, 0x8c, 0xe5, 0x02, 0x7c,
-	0xca, 0xd0, 0x36, 0xd4, 0x1c, 0x12, 0x31, 0xd7, 0x17, 0xe7, 0x47, 0x96, 0x8a, 0x4b, 0x79, 0x7c,
-	0x3b, 0x83, 0x53, 0x9c, 0xe8, 0x53, 0xc8, 0x1f, 0x33, 0x16, 0x88, 0x34, 0xac, 0xae, 0xad, 0x5e,
-	0xc5, 0xa0, 0x6d, 0xc6, 0x82, 0xed, 0x0c, 0x16, 0xfc, 0xe6, 0x2e, 0xe4, 0xba, 0xe4, 0x05, 0x6a,
-	0x43, 0x49, 0x84, 0x23, 0x6e, 0x76, 0x57, 0x0a, 0xa5, 0xe6, 0x35, 0xc7, 0x90, 0xe7, 0xd2, 0x51,
-	0x23, 0x4e, 0x6e, 0x7d, 0x1a, 0x75, 0x7a, 0x37, 0xe2, 0xf4, 0xd6, 0x87, 0x51, 0x27, 0xf8, 0xcd,
-	0x64, 0x82, 0xeb, 0xbe, 0x90, 0x48, 0xf1, 0x45, 0x95, 0xe2, 0x79, 0xb5, 0x24, 0x20, 0x5e, 0x0c,
-	0xc4, 0xe6, 0xf1, 0x87, 0xf5, 0x0f, 0x03, 0x80, 0x2b, 0xf1, 0x54, 0x8a, 0xdd, 0x06, 0x08, 0xc9,
-	0x91, 0x1b, 0x31, 0x12, 0x12, 0x59, 0x1c, 0xe6, 0xd7, 0xee, 0x4d, 0x19, 0x37, 0x61, 0x68, 0xe2,
-	0x98, 0x5a, 0x36, 0x1d, 0x0d, 0xa1, 0x3b, 0x50, 0x1b, 0xf9, 0x09, 0x59, 0xda, 0x80, 0x14, 0xd6,
-	0xf2, 0x01, 0x26, 0x12, 0x50, 0x09, 0x72, 0x4f, 0xda, 0xbd, 0x7a, 0x06, 0x95, 0x21, 0xdf, 0xd9,
-	0xef, 0xf6, 0xea, 0x06, 0x47, 0x75, 0x9e, 0xf5, 0xea, 0x59, 0x04, 0x50, 0xdc, 0x6a, 0xef, 0xb6,
-	0x7b, 0xed, 0x7a, 0x0e, 0x55, 0xa0, 0xd0, 0xd9, 0xe8, 0x6d, 0x6e, 0xd7, 0xf3, 0xa8, 0x0a, 0xa5,
-	0xfd, 0x4e, 0x6f, 0x67, 0x7f, 0xaf, 0x5b, 0x2f, 0x70, 0x60, 0x73, 0x7f, 0x6f, 0xaf, 0xbd, 0xd9,
-	0xab, 0x17, 0xb9, 0x8c, 0xed, 0xf6, 0xc6, 0x56, 0xbd, 0xc4, 0xc9, 0x7b, 0x78, 0x63, 0xb3, 0x5d,
-	0x2f, 0xb7, 0x8a, 0x90, 0x67, 0xe3, 0x80, 0x58, 0x3f, 0x33, 0xa0, 0xd8, 0x95, 0x3e, 0xde, 0x9a,
-	0x61, 0xf2, 0x74, 0x8e, 0x49, 0xe2, 0xff, 0xd4, 0xdc, 0x5b, 0x29, 0x73, 0xb9, 0x86, 0xbd, 0x5e,
-	0xa7, 0x9e, 0xe1, 0x1a, 0xf2, 0xaf, 0x6e, 0xdd, 0x88, 0x35, 0xec, 0x41, 0x65, 0xa7, 0xb3, 0xe1,
-	0x38, 0x21, 0x89, 0x78, 0x5b, 0xcc, 0xbb, 0xc1, 0xcb, 0x0f, 0x84, 0x76, 0x25, 0x1e, 0x4d, 0x0e,
-	0xa1, 0xf7, 0x04, 0xf6, 0x91, 0x3a, 0xa6, 0x6f, 0x4c, 0xe9, 0xbc, 0xd3, 0x79, 0xf9, 0x48, 0x11,
-	0x3f, 0x6a, 0xe5, 0x21, 0xeb, 0x06, 0xd6, 0x2a, 0xe4, 0x39, 0x96, 0xf7, 0xd9, 0x43, 0x37, 0x8c,
-	0x64, 0x15, 0x2b, 0x62, 0x09, 0xf0, 0xba, 0xe8, 0xd9, 0x91, 0xac, 0xfc, 0x45, 0x2c, 0xbe, 0xad,
-	0x5d, 0x80, 0xde, 0x20, 0xd0, 0x8a, 0xdc, 0xe7, 0x52, 0x54, 0x71, 0x31, 0x67, 0x6c, 0xa8, 0xe8,
-	0x70, 0xd6, 0x0d, 0x44, 0x95, 0xe5, 0x35, 0x3e, 0x2b, 0x6a, 0xbc, 0xf8, 0xb6, 0x1c, 0xc8, 0xb5,
-	0x29, 0x17, 0x53, 0x3f, 0x0a, 0x83, 0x41, 0x5f, 0x76, 0xfd, 0xfe, 0x80, 0x3a, 0x32, 0xf7, 0xe7,
-	0xb6, 0x33, 0x78, 0x9e, 0xaf, 0x74, 0xc5, 0xc2, 0x26, 0x75, 0x08, 0xa7, 0x0d, 0x49, 0x44, 0x58,
-	0x9f, 0x84, 0x21, 0x0d, 0x25, 0x6d, 0x56, 0xd3, 0x8a, 0x95, 0x36, 0x5f, 0xe0, 0xb4, 0xad, 0x02,
-	0xe4, 0x88, 0xef, 0x58, 0xbf, 0x9f, 0x87, 0x72, 0xcf, 0x0e, 0xda, 0x2f, 0x79, 0xcb, 0x7a, 0x00,
-	0x45, 0x79, 0x0a, 0x95, 0xda, 0x6f, 0x4d, 0x9f, 0xd5, 0xd8, 0x3e, 0xac, 0x48, 0xd1, 0x13, 0xa8,
-	0xca, 0xaf, 0xfe, 0x90, 0x30, 0x5b, 0xd5, 0x8d, 0x7b, 0xb3, 0x4e, 0xb9, 0xd8, 0xa4, 0xd9, 0xf6,
-	0x9d, 0x80, 0xba, 0x3e, 0x7b, 0x4a, 0x98, 0x8d, 0x41, 0xb2, 0xf2, 0x6f, 0xf4, 0x0d, 0xa8, 0x26,
-	0x2a, 0x91, 0x0a, 0xd5, 0xb9, 0x2a, 0x24, 0xe9, 0xd1, 0x67, 0x50, 0x4f, 0x80, 0x52, 0x99, 0xfc,
-	0x95, 0x94, 0x59, 0x48, 0xf0, 0x0b, 0x8d, 0x5a, 0x00, 0x21, 0x1d, 0x31, 0x65, 0x59, 0x49, 0x08,
-	0xbb, 0x7d, 0xb6, 0x30, 0xcc, 0x69, 0x85, 0xa4, 0x4a, 0xa8, 0x3f, 0xd1, 0x67, 0xb0, 0x20, 0xae,
-	0x23, 0x7d, 0xc7, 0x0d, 0x65, 0xc9, 0x15, 0x9d, 0x7c, 0x7e, 0x6d, 0xf9, 0x6c, 0x41, 0x1d, 0xce,
-	0xb0, 0xa5, 0xe9, 0xf1, 0x7c, 0x90, 0x82, 0xd1, 0x07, 0xaa, 0x44, 0xcb, 0x76, 0x71, 0xf3, 0x6c,
-	0x39, 0xa9, 0x82, 0xfc, 0x53, 0x03, 0x6a, 0x49, 0x73, 0xd1, 0x77, 0xa0, 0xe8, 0xd9, 0x07, 0xc4,
-	0xd3, 0x95, 0x79, 0xed, 0x72, 0x6e, 0x6a, 0xee, 0x0a, 0xa6, 0xb6, 0xcf, 0xc2, 0x31, 0x56, 0x12,
-	0xcc, 0x75, 0xa8, 0x26, 0xd0, 0xa8, 0x0e, 0xb9, 0x13, 0x32, 0x56, 0x97, 0x76, 0xfe, 0xc9, 0x4f,
-	0xd1, 0x4b, 0xdb, 0x1b, 0xe9, 0xe1, 0x44, 0x02, 0x1f, 0x67, 0x3f, 0x32, 0xcc, 0x1f, 0x1b, 0x50,
-	0x89, 0x3d, 0x87, 0x9e, 0x9c, 0x52, 0x6a, 0xe5, 0x12, 0xee, 0xfe, 0x6f, 0x6b, 0xf4, 0xaf, 0x92,
-	0xea, 0x36, 0xfb, 0x50, 0x0b, 0x65, 0x3f, 0xea, 0xbb, 0xbe, 0xab, 0xef, 0x31, 0xf7, 0xcf, 0x77,
-	0x78, 0x53, 0xb5, 0xb0, 0x1d, 0xdf, 0x65, 0x7c, 0x00, 0x08, 0x27, 0x20, 0xc2, 0x30, 0x17, 0xaa,
-	0x59, 0x48, 0x4a, 0x3c, 0xe7, 0x7a, 0x93, 0x92, 0x28, 0x79, 0x94, 0xc8, 0x5a, 0x98, 0x80, 0xa5,
-	0x92, 0x4a, 0x26, 0xf1, 0x1d, 0x95, 0x15, 0xf7, 0x2f, 0x29, 0xb2, 0xed, 0x3b, 0x52, 0xc9, 0x18,
-	0x34, 0x1f, 0x41, 0xb9, 0xcb, 0x42, 0x62, 0x0f, 0x77, 0xc4, 0xf8, 0x75, 0x60, 0x47, 0xaa, 0xe2,
-	0x60, 0xf1, 0x2d, 0x07, 0x12, 0xbe, 0x2e, 0xb4, 0xcf, 0x63, 0x05, 0x99, 0x7f, 0x32, 0xa0, 0x9a,
-	0xb0, 0x1d, 0x7d, 0x08, 0x59, 0xd7, 0x51, 0x3e, 0x7b, 0xf7, 0x02, 0x75, 0xf4, 0x86, 0x38, 0xeb,
-	0x3a, 0xbc, 0x0c, 0x25, 0x5a, 0xf9, 0xac, 0x1a, 0x30, 0xe9, 0xaa, 0x71, 0x97, 0x5f, 0x89, 0x6f,
-	0x06, 0xd2, 0x01, 0x5f, 0x39, 0xa3, 0x2f, 0xc5, 0x17, 0x86, 0xd4, 0xbd, 0x37, 0x7f, 0xd6, 0xbd,
-	0xb7, 0x30, 0xb9, 0xf7, 0x9a, 0xbf, 0x34, 0xa0, 0x96, 0x0c, 0xc5, 0xeb, 0x5b, 0xf8, 0x04, 0x90,
-	0x98, 0xb9, 0xfa, 0xa9, 0xf4, 0xca, 0x5e, 0x34, 0x16, 0xd5, 0x05, 0x53, 0xd2, 0xc7, 0xef, 0x40,
-	0x95, 0x1f, 0x6e, 0xd5, 0x1d, 0x84, 0xe9, 0x73, 0x18, 0x38, 0x4a, 0xb6, 0x05, 0xf3, 0xe7, 0x59,
-	0x1e, 0x94, 0x38, 0xb8, 0xff, 0x03, 0x2a, 0xef, 0xc0, 0x75, 0x2d, 0x28, 0x79, 0x12, 0x72, 0x17,
-	0x49, 0xba, 0xa6, 0x24, 0x25, 0xfc, 0x7f, 0x17, 0xe6, 0x63, 0x21, 0x07, 0x63, 0x46, 0xe4, 0xbd,
-	0x37, 0x8f, 0xe3, 0x43, 0xd6, 0xe2, 0x48, 0x74, 0x0f, 0x72, 0x84, 0x46, 0xaa, 0x33, 0x4d, 0x3f,
-	0x3a, 0xb4, 0x69, 0x84, 0x39, 0x01, 0xbf, 0xe9, 0x11, 0x6e, 0xbd, 0xf5, 0x11, 0xcc, 0xa7, 0x4b,
-	0x30, 0xbf, 0x2e, 0x3d, 0xdb, 0xfb, 0xee, 0xde, 0xfe, 0xe7, 0x7b, 0xf5, 0x0c, 0x07, 0x76, 0xf6,
-	0x5a, 0xfb, 0xcf, 0xf6, 0xb6, 0xea, 0x06, 0xaa, 0x41, 0x79, 0xff, 0x59, 0x4f, 0x42, 0xd9, 0x89,
-	0x88, 0x25, 0x28, 0x6f, 0x04, 0xae, 0x68, 0xb7, 0xbc, 0xd2, 0x88, 0x86, 0xac, 0xaa, 0x8f, 0x04,
-	0xf8, 0x90, 0x59, 0xe9, 0x50, 0x47, 0x90, 0x44, 0xe8, 0x31, 0x14, 0x05, 0x5a, 0xd7, 0xbd, 0xdb,
-	0xb3, 0xde, 0x46, 0x24, 0x6d, 0xfc, 0x85, 0x15, 0x8b, 0xf9, 0x67, 0x03, 0xca, 0x1a, 0x89, 0x30,
-	0x54, 0xf8, 0xd8, 0x6d, 0xbb, 0x3e, 0x09, 0x55, 0xa0, 0xd7, 0x2e, 0x21, 0xac, 0xb9, 0xa9, 0x99,
-	0x04, 0xc8, 0xaf, 0xc8, 0xb1, 0x18, 0xf3, 0x25, 0xcc, 0xa7, 0x97, 0x51, 0x03, 0x4a, 0x43, 0x12,
-	0x45, 0xf6, 0x91, 0x7e, 0x9a, 0xd1, 0x20, 0x3f, 0x57, 0x93, 0xfd, 0xd5, 0x53, 0x54, 0x8c, 0xe0,
-	0xbe, 0x70, 0x87, 0x9c, 0x4b, 0xbe, 0xb4, 0x49, 0x80, 0x97, 0x94, 0x90, 0xd8, 0x11, 0xf5, 0xf5,
-	0x1b, 0x87, 0x84, 0x84, 0x3b, 0x85, 0xb3, 0x3a, 0x50, 0xd6, 0x13, 0xc2, 0xf9, 0xcf, 0x6e, 0x62,
-	0x8c, 0x1e, 0x07, 0xba, 0xaa, 0x8b, 0xef, 0xf8, 0x11, 0x29, 0x37, 0x79, 0x44, 0xb2, 0x5e, 0xc0,
-	0xb5, 0xa9, 0x61, 0x08, 0x3d, 0x84, 0xb2, 0x7e, 0x14, 0x50, 0xae, 0x7b, 0xf3, 0xcc, 0x11, 0x0a,
-	0xc7, 0xa4, 0x3c, 0x0f, 0x45, 0xd7, 0xe9, 0xa7, 0x1e, 0xcc, 0x2a, 0x78, 0x4e, 0x60, 0xbb, 0xfa,
-	0x45, 0xec, 0x0b, 0x98, 0xd3, 0xcc, 0xd2, 0x89, 0xaf, 0xb9, 0x5d, 0x9c, 0x4f, 0xd9, 0x64, 0x3e,
-	0xfd, 0x2e, 0x0b, 0x88, 0x1f, 0xfa, 0xee, 0x68, 0x38, 0xb4, 0xc3, 0xb1, 0x9e, 0xc2, 0x93, 0xcf,
-	0x78, 0xc6, 0xd5, 0x9f, 0xf1, 0x78, 0x85, 0x61, 0xee, 0x90, 0xf4, 0x5f, 0xb9, 0xbe, 0x43, 0x5f,
-	0xa9, 0x2d, 0x81, 0xa3, 0x3e, 0x17, 0x18, 0xf4, 0x35, 0xc8, 0xfb, 0xd4, 0xd7, 0x65, 0xf7, 0xc6,
-	0xf4, 0xf1, 0x1a, 0x06, 0x6c, 0xcc, 0x6f, 0x21, 0x9c, 0x0a, 0x7d, 0x02, 0x55, 0x46, 0xfb, 0xb1,
-	0xd5, 0xf9, 0x0b, 0xac, 0xe6, 0xa3, 0x03, 0xa3, 0x71, 0xe8, 0xbf, 0x0d, 0x73, 0x87, 0x21, 0x1d,
-	0x4e, 0xf8, 0x0b, 0x17, 0xf3, 0xd7, 0x38, 0x47, 0x2c, 0xe1, 0xab, 0x00, 0xd1, 0x89, 0x2b, 0x0b,
-	0x66, 0x24, 0x6e, 0x62, 0x65, 0x5c, 0xe1, 0x18, 0xee, 0xba, 0xa8, 0x05, 0x50, 0xa6, 0x23, 0x76,
-	0x40, 0x47, 0xbe, 0x63, 0xfd, 0xc1, 0x80, 0xeb, 0x29, 0x87, 0xaa, 0x47, 0xcc, 0x75, 0xc8, 0xd2,
-	0x93, 0x33, 0x4b, 0xe8, 0x0c, 0x8e, 0xe6, 0xfe, 0xc9, 0x76, 0x06, 0x67, 0xe9, 0x09, 0x7a, 0x94,
-	0x8c, 0xdc, 0xac, 0xab, 0x5b, 0x2a, 0x3f, 0xb6, 0x33, 0x2a, 0xb6, 0xe6, 0x06, 0x64, 0xf7, 0x4f,
-	0xd0, 0x63, 0x10, 0xaf, 0x89, 0x7d, 0x66, 0x1f, 0x78, 0xf1, 0x3c, 0x6d, 0xce, 0xd4, 0xa0, 0xc7,
-	0x49, 0x30, 0x44, 0xfa, 0x53, 0x58, 0xa6, 0xab, 0xa2, 0xf5, 0xc7, 0x2c, 0x40, 0xcb, 0x8e, 0x5c,
-	0x31, 0x3b, 0x44, 0xe8, 0x36, 0xcc, 0x45, 0xa3, 0xc1, 0x80, 0x44, 0x7c, 0xbc, 0x18, 0xf9, 0xf2,
-	0x9e, 0x93, 0xc7, 0x35, 0x85, 0xdc, 0xe4, 0x38, 0x4e, 0x74, 0x68, 0xbb, 0xde, 0x28, 0x24, 0x8a,
-	0x48, 0x36, 0xff, 0x9a, 0x42, 0x4a, 0xa2, 0x3b, 0xfc, 0x20, 0x30, 0xe2, 0x0f, 0xc6, 0xfd, 0x61,
-	0xd4, 0x0f, 0x1e, 0xae, 0x8a, 0xac, 0xc8, 0xe3, 0x9a, 0xc2, 0x3e, 0x8d, 0x3a, 0x0f, 0x57, 0x4f,
-	0x53, 0xad, 0x3f, 0x54, 0x65, 0x3b, 0x41, 0xb5, 0xfe, 0x70, 0x8a, 0x6a, 0x5d, 0x04, 0x3b, 0x4d,
-	0xb5, 0x8e, 0xee, 0xc3, 0x35, 0xe6, 0x45, 0x71, 0x53, 0x92, 0xaa, 0x15, 0x05, 0xe1, 0x02, 0xf3,
-	0xf4, 0x73, 0xb7, 0xd4, 0x6e, 0x15, 0x16, 0xed, 0x01, 0x1b, 0xd9, 0x5e, 0x3f, 0x6d, 0x6e, 0x49,
-	0x90, 0x23, 0xb9, 0xd6, 0x4d, 0x1a, 0x3d, 0xe1, 0x48, 0xdb, 0x5e, 0x4e, 0x72, 0x7c, 0x9a, 0xf0,
-	0x80, 0xf5, 0xf7, 0x3c, 0x54, 0xe2, 0x00, 0xa0, 0x16, 0x54, 0x02, 0xea, 0xf4, 0x8f, 0x42, 0x3a,
-	0xd2, 0xa3, 0xe0, 0xed, 0xb3, 0xe3, 0xc5, 0x6b, 0xf1, 0x13, 0x4e, 0xba, 0x9d, 0xc1, 0xe5, 0x40,
-	0x7d, 0x9b, 0x3f, 0xc9, 0x8b, 0xe2, 0x2e, 0x00, 0xf4, 0x18, 0xf2, 0x21, 0x7d, 0xa5, 0x63, 0xff,
-	0xee, 0x25, 0x64, 0x35, 0x31, 0x7d, 0x85, 0x05, 0x93, 0xf9, 0x9b, 0x1c, 0xe4, 0x30, 0x7d, 0xf5,
-	0xba, 0x65, 0xe7, 0xc2, 0x4a, 0xb0, 0x0c, 0xf5, 0x21, 0x89, 0x8e, 0x89, 0xd3, 0xe7, 0x46, 0x4b,
-	0x4f, 0xc9, 0xf8, 0xcf, 0x4b, 0x7c, 0x87, 0x3a, 0xd2, 0xaf, 0xf7, 0xe1, 0x5a, 0x38, 0xf2, 0x7d,
-	0xd7, 0x3f, 0x4a, 0x90, 0xca, 0x24, 0x58, 0x50, 0x0b, 0x31, 0xed, 0x32, 0xd4, 0xb9, 0xf3, 0x53,
-	0x52, 0x65, 0x80, 0xe7, 0x25, 0x3e, 0xa6, 0x7c, 0x1f, 0x0a, 0xf2, 0x58, 0x17, 0xce, 0xb8, 0x36,
-	0x4e, 0x72, 0x1e, 0x4b, 0x4a, 0xf4, 0x05, 0xcc, 0xc9, 0x1e, 0xda, 0x3f, 0x18, 0x73, 0xf9, 0x8d,
-	0x92, 0x70, 0xec, 0x47, 0x97, 0x74, 0x6c, 0x53, 0x36, 0xd1, 0xd6, 0x98, 0x77, 0x51, 0x31, 0x7e,
-	0x54, 0xc9, 0x04, 0x63, 0x3e, 0x87, 0xfa, 0x69, 0x82, 0x19, 0x83, 0xc8, 0x6a, 0x72, 0x10, 0x99,
-	0x75, 0xa0, 0xe3, 0x66, 0x9d, 0x18, 0x52, 0x78, 0x6b, 0x14, 0x75, 0xc0, 0xfa, 0xab, 0x01, 0xf5,
-	0x1e, 0x0d, 0xc4, 0x34, 0x14, 0xfd, 0x7f, 0x54, 0xfd, 0xd2, 0x95, 0xaa, 0x7e, 0xaa, 0x28, 0xff,
-	0xd6, 0x80, 0x6b, 0x09, 0x6b, 0x55, 0x49, 0x7e, 0xcd, 0xba, 0xca, 0x6f, 0xc3, 0xf4, 0x44, 0xd9,
-	0x70, 0x77, 0xfa, 0x36, 0x7c, 0x7a, 0x9f, 0xb8, 0x90, 0x9b, 0xeb, 0xa2, 0x20, 0x3f, 0x80, 0xa2,
-	0x18, 0xf4, 0xf5, 0x79, 0x9c, 0xce, 0x38, 0xc1, 0x2f, 0x8b, 0xb1, 0x22, 0x4d, 0x15, 0xe2, 0xbf,
-	0x19, 0x00, 0x13, 0x12, 0xf4, 0x20, 0x75, 0xba, 0xdf, 0x39, 0x47, 0xda, 0xe4, 0x54, 0x23, 0x33,
-	0x71, 0x9a, 0x65, 0x9c, 0x62, 0xd8, 0xfc, 0x91, 0x21, 0x4f, 0xfc, 0x22, 0x14, 0xc4, 0xee, 0xfa,
-	0x06, 0x2a, 0x80, 0x8b, 0x83, 0x9c, 0x1a, 0x91, 0x8a, 0xa7, 0x47, 0xa4, 0xab, 0x1f, 0xb7, 0xb5,
-	0x5f, 0x17, 0x20, 0xb7, 0x11, 0xb8, 0xe8, 0x39, 0x54, 0x13, 0x7d, 0x12, 0xdd, 0x3e, 0xbf, 0x8b,
-	0x8a, 0x94, 0x36, 0xef, 0x5c, 0xa6, 0xd5, 0x5a, 0x19, 0xd4, 0x83, 0x4a, 0x1c, 0x38, 0x74, 0xeb,
-	0xbc, 0xa0, 0x4a, 0xb9, 0xd6, 0xc5, 0x71, 0xb7, 0x32, 0xe8, 0x33, 0x28, 0xeb, 0x5f, 0x33, 0xd1,
-	0xd2, 0x14, 0xc7, 0xa9, 0x5f, 0x57, 0xcd, 0x5b, 0xe7, 0x50, 0xc4, 0x22, 0xbf, 0x0f, 0xb5, 0xe4,
-	0x0f, 0xc4, 0xe8, 0xce, 0x4c, 0xa6, 0x53, 0x3f, 0x3a, 0x9b, 0x77, 0x2f, 0xa0, 0x8a, 0xc5, 0x6f,
-	0x41, 0xae, 0x67, 0x07, 0xe8, 0xad, 0x59, 0x43, 0x9e, 0x16, 0xf6, 0xe6, 0x99, 0x13, 0xa0, 0x95,
-	0xfb, 0x61, 0xd6, 0x58, 0x35, 0xd0, 0x33, 0x98, 0x4b, 0xbd, 0xcf, 0xa3, 0xbb, 0x97, 0x7a, 0xbf,
-	0x3f, 0x4f, 0x72, 0x66, 0xd5, 0x40, 0x1b, 0x50, 0xd2, 0xbf, 0xcf, 0x9d, 0x51, 0x3b, 0xcc, 0xb7,
-	0xa7, 0xf0, 0x89, 0x9f, 0xfd, 0xad, 0x0c, 0xf2, 0xa0, 0xd2, 0x25, 0xde, 0xe1, 0xe6, 0x31, 0x19,
-	0x9c, 0xa0, 0xaf, 0x4f, 0x88, 0xe5, 0x7f, 0x10, 0x34, 0x93, 0xff, 0x41, 0x10, 0xd3, 0x69, 0xed,
-	0x9a, 0x97, 0x25, 0xd7, 0xde, 0x6c, 0x3d, 0x78, 0xfe, 0xfe, 0x91, 0xcb, 0x8e, 0x47, 0x07, 0x9c,
-	0x61, 0x45, 0x71, 0xeb, 0xbf, 0x6b, 0x2b, 0x93, 0xdf, 0x44, 0x57, 0x8e, 0x88, 0xbf, 0x22, 0x15,
-	0x3e, 0x28, 0x8a, 0x29, 0xf6, 0xc1, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xc2, 0xef, 0x8d, 0x3e,
-	0x15, 0x21, 0x00, 0x00,
+func init() { proto.RegisterFile("public.proto", fileDescriptor_public_4ca5e97d258b6401) }
+
+var fileDescriptor_public_4ca5e97d258b6401 = []byte{
+	// 3324 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3a, 0x4b, 0x73, 0x1b, 0xc7,
+	0xd1, 0x5c, 0xbc, 0xd1, 0x00, 0x48, 0x70, 0x44, 0xeb, 0x83, 0x61, 0x5b, 0xa6, 0x56, 0x0f, 0xb3,
+	0x24, 0x1b, 0xa4, 0xa8, 0x87, 0x2d, 0xc9, 0xfe, 0x12, 0x82, 0x84, 0x45, 0xda, 0x14, 0x09, 0x0d,
+	0xa0, 0xb8, 0xca, 0xe5, 0x14, 0x6a, 0x89, 0x1d, 0x92, 0x6b, 0x2e, 0x76, 0x56, 0xbb, 0x03, 0x51,
+	0x38, 0xe6, 0x96, 0x9c, 0x72, 0xb0, 0x73, 0xce, 0x39, 0xb9, 0xe5, 0xe2, 0x4b, 0x2e, 0xb9, 0xa5,
+	0x52, 0x39, 0xa7, 0x92, 0xaa, 0x54, 0x25, 0x3f, 0x20, 0xd7, 0x9c, 0x53, 0xa9, 0x79, 0x2d, 0x16,
+	0x04, 0xc0, 0x87, 0x92, 0x4a, 0x25, 0x27, 0x4c, 0xf7, 0x74, 0xf7, 0x74, 0xcf, 0x4c, 0x3f, 0xa6,
+	0xb1, 0x50, 0xf4, 0xfb, 0x7b, 0xae, 0xd3, 0xad, 0xf9, 0x01, 0x65, 0x14, 0xcd, 0xb9, 0x8e, 0x77,
+	0x44, 0x02, 0x7b, 0xb5, 0x26, 0xd1, 0xd5, 0x2b, 0x07, 0x94, 0x1e, 0xb8, 0x64, 0x59, 0x4c, 0xef,
+	0xf5, 0xf7, 0x97, 0xed, 0x7e, 0x60, 0x31, 0x87, 0x7a, 0x92, 0xa1, 0x5a, 0xe9, 0xd2, 0x5e, 0x8f,
+	0x7a, 0xcb, 0x87, 0xc4, 0x72, 0xd9, 0x61, 0xf7, 0x90, 0x74, 0x8f, 0xe4, 0x8c, 0x99, 0x85, 0x74,
+	0xa3, 0xe7, 0xb3, 0x81, 0xf9, 0x02, 0x0a, 0x3f, 0x20, 0x41, 0xe8, 0x50, 0x6f, 0xcb, 0xdb, 0xa7,
+	0xe8, 0x6d, 0xc8, 0x1f, 0x50, 0x85, 0xa8, 0x18, 0x8b, 0xc6, 0x52, 0x1e, 0x0f, 0x11, 0x7c, 0x76,
+	0xaf, 0xef, 0xb8, 0xf6, 0x86, 0xc5, 0x48, 0x25, 0x21, 0x67, 0x23, 0x04, 0xba, 0x09, 0xb3, 0x01,
+	0x71, 0x89, 0x15, 0x12, 0x2d, 0x20, 0x29, 0x48, 0x4e, 0x60, 0xcd, 0xbb, 0x70, 0x69, 0xdb, 0x09,
+	0x59, 0x8b, 0x04, 0x2f, 0x9d, 0x2e, 0x09, 0x31, 0x79, 0xd1, 0x27, 0x21, 0xe3, 0xc2, 0x3d, 0xab,
+	0x47, 0x42, 0xdf, 0xea, 0x12, 0xbd, 0x74, 0x84, 0x30, 0xb7, 0x61, 0x61, 0x94, 0x29, 0xf4, 0xa9,
+	0x17, 0x12, 0x74, 0x0f, 0x72, 0xa1, 0xc2, 0x55, 0x8c, 0xc5, 0xe4, 0x52, 0x61, 0xb5, 0x52, 0x3b,
+	0xb1, 0x4d, 0x35, 0xc5, 0x84, 0x23, 0x4a, 0xf3, 0x31, 0x64, 0x15, 0x12, 0x21, 0x48, 0xf1, 0x55,
+	0xd4, 0x8a, 0x62, 0x3c, 0xaa, 0x4a, 0xe2, 0xa4, 0x2a, 0x21, 0xcc, 0x71, 0x55, 0x9a, 0xd4, 0x8e,
+	0x74, 0x5f, 0x1c, 0xd3, 0xbd, 0x9e, 0xa8, 0x18, 0x31, 0x26, 0xf4, 0xff, 0x5c, 0x4f, 0x97, 0x74,
+	0x19, 0x0d, 0x84, 0xc4, 0xc2, 0xaa, 0x39, 0xa6, 0x27, 0x26, 0x21, 0xed, 0x07, 0x5d, 0xd2, 0x12,
+	0x84, 0x0e, 0xf5, 0x70, 0xc4, 0x63, 0x7e, 0x0c, 0xe5, 0xe1, 0xa2, 0xca, 0xf6, 0x25, 0x48, 0xf9,
+	0xd4, 0xd6, 0x76, 0x2f, 0x8c, 0xc9, 0x6b, 0x52, 0x1b, 0x0b, 0x0a, 0xf3, 0x77, 0x59, 0x48, 0x36,
+	0xa9, 0x3d, 0xd1, 0xd8, 0x05, 0x48, 0xfb, 0xd4, 0xde, 0x6a, 0x2a, 0x43, 0x25, 0x80, 0x16, 0x01,
+	0x6c, 0xe2, 0xbb, 0x74, 0xd0, 0x23, 0x1e, 0x93, 0x07, 0xb9, 0x39, 0x83, 0x63, 0x38, 0x74, 0x15,
+	0x0a, 0x01, 0xf1, 0x5d, 0xa7, 0x6b, 0x75, 0x42, 0xc2, 0x2a, 0xa0, 0x49, 0x14, 0xb2, 0x45, 0x18,
+	0xfa, 0x10, 0x2e, 0x2b, 0x88, 0x5b, 0xd3, 0xe9, 0x52, 0x8f, 0x05, 0xd4, 0x75, 0x49, 0x50, 0x29,
+	0x28, 0xea, 0x37, 0x62, 0xf3, 0xeb, 0xd1, 0x34, 0xba, 0x06, 0xc5, 0x90, 0x59, 0x8c, 0xec, 0xf7,
+	0x5d, 0x21, 0xbc, 0xa8, 0xc8, 0x0b, 0x1a, 0xcb, 0xa5, 0xbf, 0x0b, 0x60, 0x5b, 0xa4, 0x47, 0x3d,
+	0x41, 0x52, 0x52, 0x24, 0x79, 0x89, 0xe3, 0x04, 0x08, 0x92, 0x5f, 0xd3, 0xbd, 0xca, 0xac, 0x9a,
+	0xe1, 0x00, 0xba, 0x0c, 0x19, 0x2e, 0xa3, 0x1f, 0x56, 0x52, 0xc2, 0x5c, 0x05, 0xf1, 0x5d, 0xb0,
+	0x6c, 0x9b, 0xd8, 0x95, 0xf4, 0xa2, 0xb1, 0x94, 0xc3, 0x12, 0x40, 0xeb, 0x30, 0x17, 0x3a, 0x5e,
+	0x97, 0x6c, 0x5b, 0x21, 0xc3, 0xc4, 0xa7, 0x01, 0xab, 0x64, 0xc4, 0xe1, 0xbd, 0x59, 0x93, 0xae,
+	0x57, 0xd3, 0xae, 0x57, 0xdb, 0x50, 0xae, 0x87, 0x4f, 0x72, 0xa0, 0x15, 0xb8, 0x34, 0xb4, 0x7c,
+	0x27, 0xba, 0x26, 0x59, 0xb1, 0xfe, 0xa4, 0x29, 0x64, 0x42, 0x51, 0xa1, 0x9b, 0xae, 0xe5, 0x91,
+	0x4a, 0x4e, 0xe8, 0x34, 0x82, 0x43, 0x77, 0x20, 0xd3, 0xf7, 0x99, 0xd3, 0x23, 0x95, 0xfc, 0x59,
+	0x1a, 0x29, 0x42, 0x74, 0x05, 0xc0, 0x0f, 0xe8, 0xab, 0x01, 0x26, 0x96, 0x3d, 0xa8, 0xcc, 0x09,
+	0xa1, 0x31, 0x0c, 0x5f, 0x56, 0x40, 0xda, 0x7d, 0xcb, 0x42, 0xc3, 0x11, 0x1c, 0x5a, 0x82, 0xb9,
+	0x40, 0x5d, 0x53, 0x4d, 0x36, 0x2f, 0xc8, 0x4e, 0xa2, 0xd1, 0xfb, 0x30, 0xaf, 0x64, 0x87, 0xcc,
+	0x0a, 0xd8, 0x3a, 0xed, 0x7b, 0xac, 0x82, 0x16, 0x8d, 0xa5, 0x12, 0x1e, 0x9f, 0x40, 0x8f, 0xa1,
+	0x20, 0x90, 0xcf, 0xa5, 0x4d, 0x97, 0xce, 0xb2, 0x29, 0x4e, 0x8d, 0x3e, 0x87, 0x59, 0x2d, 0x51,
+	0xa8, 0x10, 0x56, 0x16, 0x04, 0xff, 0xb5, 0x49, 0x2e, 0x51, 0x6b, 0x8e, 0x90, 0xe2, 0x13, 0xac,
+	0xd5, 0x6f, 0x0d, 0x98, 0x1d, 0x25, 0xe1, 0x1b, 0xd7, 0xf5, 0xfb, 0xca, 0xd9, 0x95, 0xf3, 0xc4,
+	0x30, 0xa8, 0x0a, 0xb9, 0xae, 0xdf, 0xdf, 0x76, 0x7a, 0x0e, 0x53, 0x5e, 0x14, 0xc1, 0xe8, 0x3a,
+	0x94, 0x7a, 0xa4, 0x47, 0x83, 0x81, 0x66, 0x97, 0x41, 0x71, 0x14, 0x89, 0x16, 0xa1, 0x20, 0x11,
+	0x52, 0x88, 0xbc, 0x9b, 0x71, 0x54, 0x3d, 0x0b, 0x69, 0x7a, 0xec, 0x91, 0xc0, 0xfc, 0x65, 0x02,
+	0xa0, 0x6d, 0xf9, 0x9a, 0x13, 0x41, 0xd2, 0xa7, 0x76, 0xc5, 0xd0, 0x97, 0xdc, 0xa7, 0xf6, 0x09,
+	0xe7, 0x4d, 0x4c, 0x70, 0xde, 0xcb, 0x90, 0xe9, 0x59, 0xaf, 0xb0, 0x1f, 0x0a, 0x75, 0x12, 0x58,
+	0x41, 0x1c, 0xcf, 0x68, 0x93, 0x06, 0x52, 0x85, 0x12, 0x56, 0x10, 0x0f, 0x1c, 0x8c, 0x6e, 0x35,
+	0x85, 0x77, 0xe4, 0xb1, 0x18, 0x73, 0xab, 0xf7, 0x03, 0xda, 0x6b, 0x6a, 0xaf, 0x28, 0xe1, 0x08,
+	0xe6, 0x72, 0xf8, 0x78, 0xab, 0xa9, 0xae, 0xb9, 0x82, 0x38, 0x3e, 0xec, 0x1e, 0x92, 0x9e, 0xbc,
+	0xd3, 0x79, 0xac, 0x20, 0xa1, 0x0f, 0x61, 0x87, 0xd4, 0x16, 0xb7, 0x39, 0x8f, 0x15, 0xc4, 0x23,
+	0xb1, 0xd5, 0x67, 0x87, 0x34, 0x70, 0xd8, 0x40, 0x86, 0x18, 0x3c, 0x44, 0x70, 0xad, 0x7c, 0x8b,
+	0x1d, 0xca, 0x68, 0x82, 0xc5, 0xf8, 0x51, 0xa2, 0x62, 0xd4, 0x73, 0x90, 0x61, 0x56, 0x70, 0x40,
+	0x98, 0xf9, 0x6d, 0x06, 0x16, 0xda, 0x96, 0x5f, 0x8f, 0x0e, 0x53, 0x6f, 0xdb, 0x23, 0x4d, 0x52,
+	0x31, 0xce, 0x1d, 0x8d, 0x15, 0x07, 0x5a, 0x83, 0x74, 0xcf, 0x62, 0xdd, 0x43, 0x15, 0xc8, 0x6f,
+	0x8f, 0xb1, 0x4e, 0x5a, 0xb1, 0xf6, 0x94, 0xb3, 0x60, 0xc9, 0x39, 0x75, 0xff, 0x2b, 0x90, 0xed,
+	0x59, 0xaf, 0x78, 0x94, 0x57, 0x07, 0xa0, 0x41, 0x61, 0x2b, 0x47, 0xa7, 0x17, 0x93, 0xc2, 0x56,
+	0x6a, 0x87, 0xd5, 0xef, 0x52, 0x90, 0x16, 0x62, 0xd1, 0x3a, 0x24, 0x2d, 0xd7, 0x55, 0xb6, 0x2c,
+	0x5f, 0x40, 0xa1, 0x5a, 0x8b, 0xbc, 0xe0, 0xd7, 0xc6, 0x72, 0x5d, 0x21, 0xc4, 0x1b, 0x54, 0x12,
+	0xaf, 0x2f, 0xc4, 0x1b, 0xa0, 0xef, 0x41, 0xd2, 0xa3, 0xf2, 0x96, 0x5f, 0x6c, 0x6b, 0xb8, 0x00,
+	0x8f, 0x32, 0xb4, 0x09, 0x45, 0x9b, 0x84, 0xcc, 0xf1, 0x84, 0xa3, 0xcb, 0x7d, 0x38, 0xd7, 0xf9,
+	0x6c, 0xce, 0xe0, 0x11, 0x4e, 0xf4, 0x29, 0xa4, 0x0e, 0x19, 0xf3, 0xc5, 0xa5, 0x2d, 0xac, 0xae,
+	0x5c, 0xc4, 0xa0, 0x4d, 0xc6, 0xfc, 0xcd, 0x19, 0x2c, 0xf8, 0xab, 0xdb, 0x90, 0x6c, 0x91, 0x17,
+	0xa8, 0xc1, 0xcf, 0x86, 0x75, 0x0f, 0xa3, 0x4a, 0xe3, 0x42, 0x07, 0xaf, 0x79, 0xab, 0x03, 0x48,
+	0x71, 0xe9, 0xa8, 0x12, 0xb9, 0x82, 0xf6, 0x5d, 0x05, 0xf3, 0x19, 0xe5, 0x0c, 0xda, 0x75, 0x15,
+	0x8c, 0xae, 0xc4, 0xdd, 0x41, 0x27, 0xe5, 0x21, 0x0a, 0x2d, 0x28, 0x87, 0x48, 0xa9, 0x29, 0x01,
+	0xf1, 0xd0, 0x21, 0x16, 0x8f, 0x06, 0xe6, 0xdf, 0x0d, 0x00, 0xae, 0xc4, 0x53, 0x29, 0x76, 0x13,
+	0x20, 0x20, 0x07, 0x4e, 0xc8, 0x48, 0x40, 0x64, 0x28, 0x99, 0x5d, 0xbd, 0x39, 0x66, 0xdc, 0x90,
+	0xa1, 0x86, 0x23, 0x6a, 0x99, 0xf1, 0x35, 0x84, 0xae, 0x43, 0xb1, 0xef, 0xc5, 0x64, 0x69, 0x03,
+	0x46, 0xb0, 0xa6, 0x07, 0x30, 0x94, 0x80, 0xb2, 0x90, 0x7c, 0xd2, 0x68, 0x97, 0x67, 0x50, 0x0e,
+	0x52, 0xcd, 0xdd, 0x56, 0xbb, 0x6c, 0x70, 0x54, 0xf3, 0x79, 0xbb, 0x9c, 0x40, 0x00, 0x99, 0x8d,
+	0xc6, 0x76, 0xa3, 0xdd, 0x28, 0x27, 0x51, 0x1e, 0xd2, 0xcd, 0xb5, 0xf6, 0xfa, 0x66, 0x39, 0x85,
+	0x0a, 0x90, 0xdd, 0x6d, 0xb6, 0xb7, 0x76, 0x77, 0x5a, 0xe5, 0x34, 0x07, 0xd6, 0x77, 0x77, 0x76,
+	0x1a, 0xeb, 0xed, 0x72, 0x86, 0xcb, 0xd8, 0x6c, 0xac, 0x6d, 0x94, 0xb3, 0x9c, 0xbc, 0x8d, 0xd7,
+	0xd6, 0x1b, 0xe5, 0x5c, 0x3d, 0x03, 0x29, 0x36, 0xf0, 0x89, 0xf9, 0x73, 0x03, 0x32, 0x2d, 0xb9,
+	0xc7, 0x1b, 0x13, 0x4c, 0x1e, 0xbf, 0x63, 0x92, 0xf8, 0x5f, 0x35, 0xf7, 0xea, 0x88, 0xb9, 0x5c,
+	0xc3, 0x76, 0xbb, 0x59, 0x9e, 0xe1, 0x1a, 0xf2, 0x51, 0xab, 0x6c, 0x44, 0x1a, 0xb6, 0x21, 0xbf,
+	0xd5, 0x5c, 0xb3, 0xed, 0x80, 0x84, 0xbc, 0x26, 0x49, 0x39, 0xfe, 0xcb, 0x7b, 0x42, 0xbb, 0x2c,
+	0x3f, 0x4d, 0x0e, 0xa1, 0xdb, 0x02, 0xfb, 0x40, 0xb9, 0xe9, 0x1b, 0x63, 0x3a, 0x6f, 0x35, 0x5f,
+	0x3e, 0x50, 0xc4, 0x0f, 0xea, 0x29, 0x48, 0x38, 0xbe, 0xb9, 0x02, 0x29, 0x8e, 0xe5, 0x45, 0xce,
+	0xbe, 0x13, 0xa8, 0x14, 0x96, 0xc1, 0x12, 0xe0, 0x91, 0xc5, 0xb5, 0x42, 0x99, 0x27, 0x32, 0x58,
+	0x8c, 0xcd, 0x6d, 0x80, 0x76, 0xd7, 0xd7, 0x8a, 0xdc, 0xe2, 0x52, 0x54, 0x70, 0xa9, 0x4e, 0x58,
+	0x50, 0xd1, 0xe1, 0x84, 0xe3, 0xcb, 0x38, 0x15, 0x48, 0x69, 0x25, 0x2c, 0xc6, 0xa6, 0x0d, 0xc9,
+	0x06, 0xe5, 0x62, 0xca, 0x07, 0x81, 0xdf, 0xed, 0xc8, 0x92, 0xab, 0xd3, 0xa5, 0xb6, 0xbc, 0xfb,
+	0xa5, 0xcd, 0x19, 0x3c, 0xcb, 0x67, 0x5a, 0x62, 0x62, 0x9d, 0xda, 0x84, 0xd3, 0x06, 0x24, 0x24,
+	0xac, 0x43, 0x82, 0x80, 0x06, 0x92, 0x36, 0xa1, 0x69, 0xc5, 0x4c, 0x83, 0x4f, 0x70, 0xda, 0x7a,
+	0x1a, 0x92, 0xc4, 0xb3, 0xcd, 0x3f, 0xcc, 0x42, 0xae, 0x6d, 0xf9, 0x8d, 0x97, 0x3c, 0xc1, 0xdd,
+	0x85, 0x8c, 0xf4, 0x42, 0xa5, 0xf6, 0x5b, 0xe3, 0xbe, 0x1a, 0xd9, 0x87, 0x15, 0x29, 0x7a, 0x02,
+	0x05, 0x39, 0xea, 0xf4, 0x08, 0xb3, 0x54, 0xdc, 0xb8, 0x39, 0xc9, 0xcb, 0xc5, 0x22, 0xb5, 0x86,
+	0x67, 0xfb, 0xd4, 0xf1, 0xd8, 0x53, 0xc2, 0x2c, 0x0c, 0x92, 0x95, 0x8f, 0xd1, 0x27, 0x50, 0x88,
+	0x45, 0xa2, 0x4a, 0xe2, 0x6c, 0x15, 0xe2, 0xf4, 0xe8, 0x19, 0x94, 0x63, 0xa0, 0x54, 0x26, 0x75,
+	0x21, 0x65, 0xe6, 0x62, 0xfc, 0x42, 0xa3, 0x3a, 0x40, 0x40, 0xfb, 0x4c, 0x59, 0x96, 0x9d, 0x52,
+	0x1e, 0x45, 0xc2, 0x30, 0xa7, 0x15, 0x92, 0xf2, 0x81, 0x1e, 0xa2, 0x67, 0x30, 0x27, 0x6a, 0xa5,
+	0x8e, 0xed, 0x04, 0x32, 0xe4, 0x8a, 0xbc, 0x3f, 0xbb, 0xba, 0x34, 0x5d, 0x90, 0xa8, 0xa4, 0x36,
+	0x34, 0xbd, 0x2a, 0xb6, 0x22, 0x18, 0xdd, 0x53, 0x21, 0x5a, 0xa6, 0x8b, 0x2b, 0xd3, 0xe5, 0x8c,
+	0x04, 0xe4, 0x9f, 0x19, 0x50, 0x8c, 0x9b, 0x8b, 0x3e, 0x83, 0x8c, 0x6b, 0xed, 0x11, 0x57, 0x47,
+	0xe6, 0xd5, 0xf3, 0x6d, 0x53, 0x6d, 0x5b, 0x30, 0x35, 0x3c, 0x16, 0x0c, 0xb0, 0x92, 0x50, 0x7d,
+	0x08, 0x85, 0x18, 0x1a, 0x95, 0x21, 0x79, 0x44, 0x06, 0xaa, 0xe8, 0xe3, 0x43, 0xee, 0x45, 0x2f,
+	0x2d, 0xb7, 0xaf, 0x5f, 0x86, 0x12, 0x78, 0x94, 0xf8, 0xc8, 0xa8, 0xfe, 0xd4, 0x80, 0x7c, 0xb4,
+	0x73, 0xe8, 0xc9, 0x09, 0xa5, 0x96, 0xcf, 0xb1, 0xdd, 0xff, 0x6e, 0x8d, 0xfe, 0x91, 0x55, 0xd9,
+	0x66, 0x17, 0x8a, 0x81, 0xcc, 0x47, 0x1d, 0xc7, 0x73, 0x74, 0xd5, 0x73, 0xeb, 0xf4, 0x0d, 0xaf,
+	0xa9, 0x14, 0xb6, 0xe5, 0x39, 0x8c, 0xbf, 0xbe, 0x82, 0x21, 0x88, 0x30, 0x94, 0x02, 0xf5, 0x10,
+	0x95, 0x12, 0x4f, 0x29, 0x86, 0x46, 0x24, 0x4a, 0x1e, 0x25, 0xb2, 0x18, 0xc4, 0x60, 0xa9, 0xa4,
+	0x92, 0x49, 0x3c, 0xbb, 0x92, 0x3c, 0xa7, 0x92, 0x92, 0xa5, 0xe1, 0xd9, 0x52, 0xc9, 0x08, 0xac,
+	0x3e, 0x80, 0x5c, 0x8b, 0x05, 0xc4, 0xea, 0x6d, 0x89, 0xb7, 0xef, 0x9e, 0x15, 0xaa, 0x88, 0x83,
+	0xc5, 0x58, 0xbe, 0x06, 0xf9, 0xbc, 0xd0, 0x3e, 0x85, 0x15, 0x54, 0xfd, 0x8b, 0x01, 0x85, 0x98,
+	0xed, 0xe8, 0x43, 0x48, 0x38, 0xb6, 0xda, 0xb3, 0xf7, 0xce, 0x50, 0x47, 0x2f, 0x88, 0x13, 0x8e,
+	0xcd, 0xc3, 0x50, 0x2c, 0x95, 0x4f, 0x8a, 0x01, 0xc3, 0xac, 0x1a, 0x65, 0xf9, 0xe5, 0xa8, 0x32,
+	0x90, 0x1b, 0xf0, 0x7f, 0x53, 0xf2, 0x52, 0x54, 0x30, 0x8c, 0x54, 0xc9, 0xa9, 0x69, 0x55, 0x72,
+	0x7a, 0x58, 0x25, 0x57, 0x7f, 0x65, 0x40, 0x31, 0x7e, 0x14, 0xaf, 0x6f, 0xe1, 0x13, 0x40, 0xe2,
+	0xc1, 0xdb, 0x19, 0xb9, 0x5e, 0x89, 0xb3, 0xde, 0x6f, 0x65, 0xc1, 0x14, 0xdf, 0xe3, 0x77, 0xa1,
+	0xc0, 0x9d, 0x5b, 0x65, 0x07, 0x61, 0x7a, 0x09, 0x03, 0x47, 0xc9, 0xb4, 0x50, 0xfd, 0x45, 0x02,
+	0x0a, 0x5a, 0xe7, 0x86, 0x67, 0xff, 0x17, 0xa8, 0xbc, 0x05, 0x97, 0xb4, 0xa0, 0xb8, 0x27, 0x24,
+	0xcf, 0x92, 0x34, 0xaf, 0x24, 0xc5, 0xf6, 0xff, 0x06, 0x6f, 0x9e, 0x29, 0x21, 0x7b, 0x03, 0x46,
+	0x64, 0xdd, 0x9b, 0xc2, 0x91, 0x93, 0xd5, 0x39, 0x12, 0xdd, 0x84, 0x24, 0xa1, 0xa1, 0xca, 0x4c,
+	0xe3, 0x1d, 0x9f, 0x06, 0x0d, 0x31, 0x27, 0xe0, 0x95, 0x1e, 0xe1, 0xd6, 0x9b, 0x1f, 0xa9, 0xc7,
+	0xec, 0x30, 0xe4, 0x16, 0x20, 0xfb, 0x7c, 0xe7, 0xf3, 0x9d, 0xdd, 0x2f, 0x76, 0xca, 0x33, 0x1c,
+	0xd8, 0xda, 0xa9, 0xef, 0x3e, 0xdf, 0xd9, 0x28, 0x1b, 0xa8, 0x08, 0xb9, 0xdd, 0xe7, 0x6d, 0x09,
+	0x25, 0x86, 0x22, 0x16, 0x21, 0xb7, 0xe6, 0x3b, 0x22, 0xdd, 0xf2, 0x48, 0x23, 0x12, 0xb2, 0x8a,
+	0x3e, 0x12, 0xe0, 0x4f, 0xd2, 0x7c, 0x93, 0xda, 0x82, 0x24, 0x44, 0x8f, 0x21, 0x23, 0xd0, 0x3a,
+	0xee, 0x4d, 0x7c, 0x85, 0x4b, 0xda, 0x68, 0x84, 0x15, 0x4b, 0xf5, 0xaf, 0x06, 0xe4, 0x34, 0x12,
+	0x61, 0xc8, 0xf3, 0x9e, 0x87, 0xe5, 0x78, 0x24, 0x50, 0x07, 0xbd, 0x7a, 0x0e, 0x61, 0xb5, 0x75,
+	0xcd, 0x24, 0x40, 0x5e, 0x22, 0x47, 0x62, 0xaa, 0x2f, 0x61, 0x76, 0x74, 0x5a, 0xbc, 0xb9, 0x48,
+	0x18, 0x5a, 0x07, 0xba, 0x2f, 0xa6, 0x41, 0xee, 0x57, 0xc3, 0xf5, 0x55, 0x1f, 0x30, 0x42, 0xf0,
+	0xbd, 0x70, 0x7a, 0x9c, 0x4b, 0xbe, 0xe8, 0x25, 0xc0, 0x43, 0x4a, 0x40, 0xac, 0x90, 0x7a, 0xba,
+	0xc1, 0x24, 0x21, 0xb1, 0x9d, 0x62, 0xb3, 0x9a, 0x90, 0xd3, 0x2f, 0x84, 0xd3, 0x7b, 0x9e, 0xe2,
+	0xd1, 0x3d, 0xf0, 0x75, 0x54, 0x17, 0xe3, 0xa8, 0x83, 0x97, 0x1c, 0x76, 0xf0, 0xcc, 0x17, 0x30,
+	0x3f, 0xf6, 0x18, 0x42, 0xf7, 0x21, 0xa7, 0x3b, 0x32, 0x6a, 0xeb, 0xde, 0x9c, 0xfa, 0x84, 0xc2,
+	0x11, 0x29, 0xbf, 0x87, 0x22, 0xeb, 0x74, 0x46, 0xba, 0x95, 0x79, 0x5c, 0x12, 0xd8, 0x96, 0x42,
+	0x9a, 0x5f, 0x41, 0x49, 0x33, 0xcb, 0x4d, 0x7c, 0xcd, 0xe5, 0xa2, 0xfb, 0x94, 0x88, 0xdf, 0xa7,
+	0xdf, 0x26, 0x01, 0x71, 0xa7, 0x6f, 0xf5, 0x7b, 0x3d, 0x6b, 0xd8, 0x24, 0x89, 0xf7, 0x50, 0x8d,
+	0x8b, 0xf7, 0x50, 0x79, 0x84, 0xe1, 0xed, 0xa2, 0xce, 0xb1, 0xe3, 0xd9, 0xf4, 0x58, 0x2d, 0x09,
+	0x1c, 0xf5, 0x85, 0xc0, 0xa0, 0xf7, 0x21, 0xe5, 0x51, 0x4f, 0x87, 0xdd, 0xcb, 0xe3, 0xee, 0xc5,
+	0x5b, 0xe6, 0xbc, 0x0a, 0xe1, 0x54, 0xe8, 0x63, 0x28, 0x30, 0xda, 0x89, 0xac, 0x4e, 0x9d, 0x61,
+	0x35, 0x7f, 0x3a, 0x30, 0xaa, 0x21, 0xf4, 0x7d, 0x28, 0xf1, 0x9e, 0xc8, 0x90, 0x3f, 0x7d, 0x36,
+	0x7f, 0x91, 0x73, 0x44, 0x12, 0xde, 0x01, 0x08, 0x8f, 0x1c, 0x19, 0x30, 0x43, 0x51, 0x89, 0xe5,
+	0x70, 0x9e, 0x63, 0xf8, 0xd6, 0x85, 0xe8, 0x01, 0xe4, 0x19, 0xed, 0xec, 0x3b, 0x2e, 0x23, 0x41,
+	0x25, 0x7b, 0x86, 0x70, 0x9c, 0x63, 0xf4, 0x53, 0x41, 0x5a, 0x07, 0xc8, 0xd1, 0x3e, 0xdb, 0xa3,
+	0x7d, 0x4f, 0xb4, 0x5f, 0x86, 0xb5, 0x9e, 0xec, 0xd8, 0x0c, 0x11, 0xe8, 0x2a, 0x14, 0xc3, 0x43,
+	0x7a, 0xdc, 0x79, 0xd1, 0x27, 0x81, 0x43, 0x42, 0xd1, 0xba, 0xc9, 0xe1, 0x02, 0xc7, 0x3d, 0x93,
+	0x28, 0xf3, 0x8f, 0x06, 0x5c, 0x1a, 0x39, 0x49, 0xd5, 0xba, 0x7e, 0x08, 0x09, 0x7a, 0x34, 0x35,
+	0x76, 0x4f, 0xe0, 0xa8, 0xed, 0x1e, 0x6d, 0xce, 0xe0, 0x04, 0x3d, 0x42, 0x0f, 0xe2, 0x57, 0x66,
+	0x52, 0xcd, 0x38, 0x72, 0x31, 0x37, 0x67, 0xd4, 0xa5, 0xaa, 0xae, 0x41, 0x62, 0xf7, 0x88, 0xf7,
+	0x19, 0xf9, 0x7e, 0x75, 0x98, 0xb5, 0xe7, 0x46, 0x0f, 0xf9, 0xea, 0x44, 0x0d, 0xda, 0x9c, 0x04,
+	0x43, 0xa8, 0x87, 0x21, 0xdf, 0x1a, 0x1d, 0x8e, 0xcd, 0x3f, 0x25, 0x00, 0xea, 0x56, 0xe8, 0x74,
+	0xe5, 0x6e, 0x5f, 0x83, 0x52, 0xd8, 0xef, 0x76, 0x49, 0xc8, 0xdf, 0x35, 0xbc, 0xd3, 0x69, 0x88,
+	0xf0, 0x5d, 0x54, 0x48, 0xd9, 0xe4, 0xbc, 0x06, 0xa5, 0x7d, 0xcb, 0x71, 0xfb, 0x01, 0x51, 0x44,
+	0xb2, 0xea, 0x28, 0x2a, 0xa4, 0x24, 0xba, 0xce, 0x3d, 0x90, 0x11, 0xaf, 0x3b, 0xe8, 0xf4, 0xc2,
+	0x8e, 0x7f, 0x7f, 0x45, 0x5c, 0xc7, 0x14, 0x2e, 0x2a, 0xec, 0xd3, 0xb0, 0x79, 0x7f, 0xe5, 0x24,
+	0xd5, 0xc3, 0xfb, 0x95, 0xd4, 0x49, 0xaa, 0x87, 0xf7, 0xc7, 0xa8, 0x1e, 0x56, 0xd2, 0x63, 0x54,
+	0x0f, 0xd1, 0x2d, 0x98, 0x67, 0x6e, 0x18, 0x65, 0x43, 0xa9, 0x5a, 0x46, 0x10, 0xce, 0x31, 0x57,
+	0xff, 0xc9, 0x21, 0xb5, 0x5b, 0x81, 0x05, 0xab, 0xcb, 0xfa, 0x96, 0xdb, 0x19, 0x35, 0x37, 0x2b,
+	0xc8, 0x91, 0x9c, 0x6b, 0xc5, 0x8d, 0x1e, 0x72, 0x8c, 0xda, 0x9e, 0x8b, 0x73, 0x7c, 0x1a, 0xdb,
+	0x01, 0xb3, 0x05, 0xf3, 0xed, 0xc0, 0xda, 0xdf, 0x77, 0xba, 0x2d, 0xdf, 0x75, 0x98, 0xdc, 0x60,
+	0x04, 0x29, 0xcb, 0x27, 0xaf, 0xf4, 0x5f, 0x17, 0x7c, 0xcc, 0x71, 0x2e, 0xb1, 0xf6, 0x75, 0x80,
+	0xe4, 0x63, 0x1e, 0x7f, 0x8f, 0x89, 0x73, 0x70, 0xc8, 0x54, 0x05, 0xa1, 0x20, 0xf3, 0xbb, 0x0c,
+	0xe4, 0xa3, 0x53, 0x45, 0x75, 0xc8, 0xfb, 0xd4, 0xee, 0x1c, 0x04, 0xb4, 0xaf, 0x1f, 0xb6, 0xd7,
+	0xa6, 0x5f, 0x02, 0x9e, 0x59, 0x9e, 0x70, 0xd2, 0xcd, 0x19, 0x9c, 0xf3, 0xd5, 0xb8, 0xfa, 0xe7,
+	0xb4, 0x48, 0x55, 0x02, 0x40, 0x8f, 0x21, 0x15, 0xd0, 0x63, 0x7d, 0xa1, 0xde, 0x3b, 0x87, 0xac,
+	0x1a, 0xa6, 0xc7, 0x58, 0x30, 0xa1, 0xcf, 0x20, 0xab, 0x7d, 0x28, 0xb1, 0x98, 0x9c, 0xd8, 0xab,
+	0x9a, 0xc0, 0xaf, 0x7c, 0x4c, 0xbe, 0x15, 0xb4, 0x80, 0xea, 0x4f, 0x52, 0x90, 0xc4, 0xf4, 0xf8,
+	0x75, 0x03, 0xf2, 0x99, 0x31, 0x72, 0x09, 0xca, 0x3d, 0x12, 0x1e, 0x12, 0xbb, 0xc3, 0x37, 0x50,
+	0x1e, 0xa5, 0xbc, 0xa0, 0xb3, 0x12, 0xdf, 0xa4, 0xb6, 0x3c, 0xf8, 0x5b, 0x30, 0x1f, 0xf4, 0x3d,
+	0xcf, 0xf1, 0x0e, 0x62, 0xa4, 0xf2, 0x96, 0xce, 0xa9, 0x89, 0x88, 0x76, 0x09, 0xca, 0xfc, 0x76,
+	0x8c, 0x48, 0x95, 0x37, 0x70, 0x56, 0xe2, 0x23, 0xca, 0x3b, 0x90, 0x96, 0x01, 0x2f, 0x3d, 0xa5,
+	0xa0, 0x1e, 0x3a, 0x25, 0x96, 0x94, 0xe8, 0x2b, 0x28, 0xc9, 0xea, 0xa2, 0xb3, 0x37, 0xe0, 0xf2,
+	0x2b, 0x59, 0xb1, 0xc9, 0x1f, 0x9d, 0xf3, 0x90, 0x6a, 0xb2, 0xbc, 0xa8, 0x0f, 0x78, 0x7d, 0x21,
+	0x36, 0xbb, 0x40, 0x86, 0x18, 0xf4, 0x09, 0xe4, 0x58, 0xa8, 0x82, 0x70, 0x6e, 0x4a, 0x56, 0x1a,
+	0xbb, 0xce, 0x38, 0xcb, 0x42, 0x31, 0xa8, 0x7e, 0x09, 0xe5, 0x93, 0xf2, 0x27, 0xbc, 0xf0, 0x56,
+	0xe2, 0x2f, 0xbc, 0x49, 0x01, 0x2b, 0xaa, 0x82, 0xe2, 0xaf, 0xbf, 0x47, 0x50, 0x8c, 0x5f, 0x92,
+	0x8b, 0xbc, 0x1c, 0x79, 0xbd, 0x22, 0x62, 0xa4, 0xf9, 0x37, 0x03, 0xca, 0x6d, 0xea, 0x8b, 0x27,
+	0x6a, 0xf8, 0xbf, 0x91, 0x8a, 0xb3, 0x17, 0x4a, 0xc5, 0xf1, 0x8c, 0x67, 0xfe, 0xde, 0x80, 0xf9,
+	0x98, 0xb5, 0x2a, 0x5d, 0xbd, 0x66, 0xce, 0xe1, 0x4f, 0x14, 0x7a, 0xa4, 0x6c, 0xb8, 0x31, 0x7e,
+	0x2b, 0x4e, 0xae, 0x13, 0x25, 0xb9, 0xea, 0x43, 0x91, 0xac, 0xee, 0x42, 0x46, 0x74, 0x5f, 0x74,
+	0x58, 0x19, 0xbf, 0xec, 0x82, 0x5f, 0x26, 0x2a, 0x45, 0x3a, 0x92, 0xa4, 0xbe, 0x4b, 0x01, 0x0c,
+	0x49, 0xd0, 0xdd, 0x91, 0x20, 0xf5, 0xee, 0x29, 0xd2, 0x62, 0xc1, 0xa9, 0x1a, 0x0b, 0x24, 0xea,
+	0xcf, 0x2d, 0x0d, 0x57, 0x7f, 0x63, 0xc8, 0x60, 0xb3, 0x00, 0x69, 0xb1, 0xba, 0x7e, 0x16, 0x08,
+	0xe0, 0xec, 0x43, 0x1e, 0x79, 0xb7, 0x66, 0x4e, 0xbe, 0x5b, 0x5f, 0xc3, 0xd3, 0xef, 0x40, 0x32,
+	0x74, 0xa9, 0x3a, 0xff, 0x53, 0xed, 0x6b, 0x6d, 0xef, 0x62, 0x4e, 0x5b, 0xfd, 0x26, 0x01, 0xc9,
+	0xd6, 0xf6, 0x2e, 0xba, 0x07, 0x97, 0x75, 0x46, 0x0b, 0x2c, 0x46, 0x3a, 0x74, 0xef, 0x6b, 0x7e,
+	0x6b, 0x5f, 0x4a, 0x9b, 0x0c, 0xbc, 0xa0, 0x66, 0xb1, 0xc5, 0xc8, 0xae, 0x9e, 0xe3, 0xc9, 0x4d,
+	0x27, 0xd8, 0x88, 0xa1, 0xd3, 0x0b, 0x55, 0x62, 0x47, 0x6a, 0x2e, 0xa2, 0x7f, 0x1a, 0xa2, 0x0f,
+	0x40, 0x63, 0x3b, 0x3e, 0x09, 0xba, 0xc4, 0x63, 0x8e, 0x4b, 0x54, 0xae, 0x9a, 0x57, 0x33, 0xcd,
+	0x68, 0x82, 0x07, 0xc6, 0x11, 0xb5, 0x7a, 0x44, 0xc6, 0xd0, 0x1c, 0x9e, 0x8d, 0x29, 0xf4, 0x54,
+	0xfc, 0x1d, 0x5e, 0x88, 0x72, 0x3d, 0x61, 0xea, 0x7f, 0x6c, 0xd0, 0x89, 0x9e, 0x88, 0x78, 0x2c,
+	0x9b, 0xa9, 0x7b, 0x7d, 0xfb, 0x80, 0xb0, 0xce, 0x5e, 0x3f, 0x90, 0x0d, 0x3c, 0x03, 0xcf, 0x89,
+	0x89, 0xba, 0xc0, 0xd7, 0xfb, 0x81, 0x67, 0x52, 0x28, 0x36, 0xec, 0x83, 0xff, 0x9c, 0xbf, 0x9b,
+	0xbf, 0x36, 0xa0, 0xa4, 0x56, 0x54, 0x3e, 0x77, 0x37, 0x56, 0x22, 0x5e, 0x1d, 0xf7, 0x7f, 0xfb,
+	0x60, 0x82, 0xdf, 0xbc, 0x76, 0x71, 0x78, 0x47, 0xf8, 0xdb, 0x6d, 0x48, 0x13, 0x2e, 0x57, 0x39,
+	0xc8, 0x1b, 0x13, 0x57, 0xc5, 0x92, 0x66, 0xc4, 0xcf, 0xbe, 0x31, 0x20, 0xc5, 0xe7, 0xd0, 0x6d,
+	0x48, 0x86, 0x41, 0xf7, 0xec, 0x84, 0xcb, 0xa9, 0x38, 0xb1, 0x1d, 0x0e, 0x1b, 0x0f, 0xd3, 0x89,
+	0xed, 0x30, 0x96, 0xf7, 0x92, 0xe7, 0xf5, 0x86, 0xd5, 0x1f, 0x65, 0x20, 0xb9, 0xe6, 0x3b, 0xe8,
+	0x4b, 0x28, 0xc4, 0x2a, 0x6a, 0x74, 0xed, 0xf4, 0x7a, 0x5b, 0x1c, 0x78, 0xf5, 0xfa, 0x79, 0x8a,
+	0x72, 0x73, 0x06, 0xb5, 0x21, 0x1f, 0x85, 0x31, 0x74, 0xf5, 0xb4, 0x10, 0x27, 0xe5, 0x9a, 0x67,
+	0x47, 0x41, 0x73, 0x06, 0x6d, 0x42, 0x5a, 0x1c, 0x30, 0x7a, 0x67, 0xda, 0xc1, 0x4b, 0x69, 0x57,
+	0x4e, 0xbf, 0x17, 0xe6, 0x0c, 0x7a, 0x06, 0x39, 0xfd, 0xdd, 0x0c, 0x5a, 0x1c, 0xa3, 0x3e, 0xf1,
+	0x1d, 0x4f, 0xf5, 0xea, 0x29, 0x14, 0x91, 0xc8, 0x1f, 0x42, 0x31, 0xfe, 0x29, 0x12, 0xba, 0x3e,
+	0x91, 0xe9, 0xc4, 0xe7, 0x4d, 0xd5, 0x1b, 0x67, 0x50, 0x45, 0xe2, 0x37, 0x20, 0xd9, 0xb6, 0x7c,
+	0xf4, 0xd6, 0xa4, 0x8e, 0x96, 0x16, 0xf6, 0xe6, 0xd4, 0x76, 0x97, 0x99, 0xfc, 0x71, 0xc2, 0x58,
+	0x31, 0xd0, 0x73, 0x28, 0x8d, 0xfc, 0x19, 0x89, 0x6e, 0x9c, 0xeb, 0xcf, 0xca, 0xd3, 0x24, 0xcf,
+	0xac, 0x18, 0x68, 0x0d, 0xb2, 0xfa, 0xfb, 0x8e, 0x29, 0x39, 0xb9, 0xfa, 0xf6, 0x18, 0x3e, 0xf6,
+	0x81, 0x99, 0x39, 0x83, 0x5c, 0xc8, 0xb7, 0x88, 0xbb, 0xbf, 0xce, 0xbf, 0x46, 0x43, 0x1f, 0x0c,
+	0x89, 0xe5, 0xb7, 0x6a, 0xb5, 0xf8, 0xb7, 0x6a, 0x11, 0x9d, 0xd6, 0xae, 0x76, 0x5e, 0x72, 0xbd,
+	0x9b, 0xf5, 0xbb, 0x5f, 0xde, 0x39, 0x70, 0xd8, 0x61, 0x7f, 0x8f, 0x33, 0x2c, 0x2b, 0x6e, 0xfd,
+	0xbb, 0xba, 0x3c, 0xfc, 0xfa, 0x66, 0xf9, 0x80, 0x78, 0xcb, 0x52, 0xe1, 0xbd, 0x8c, 0x68, 0xd9,
+	0xdd, 0xfd, 0xe7, 0x00, 0x0f, 0x36, 0xbf, 0xa8, 0x7f, 0x27, 0x00, 0x00,
 }
@@ -0,0 +1,89 @@
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Report holds the anonymized statistics of the mesh sent by the heartbeat.
+// It only has counts, so that the names of the namespaces and pods of the
+// cluster aren't disclosed.
+type Report struct {
+	UUID             string `json:"uuid"`
+	Version          string `json:"version"`
+	K8sVersion       string `json:"k8s_version"`
+	Namespaces       int    `json:"namespaces"`
+	MeshedNamespaces int    `json:"meshed_namespaces"`
+	Pods             int    `json:"pods"`
+	MeshedPods       int    `json:"meshed_pods"`
+}
+
+// Collect returns the statistics of the mesh whose control plane runs in
+// controllerNamespace. uuid identifies the installation of the control plane.
+func Collect(client kubernetes.Interface, controllerNamespace, uuid string) (*Report, error) {
+	serverVersion, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		UUID:       uuid,
+		Version:    version.Version,
+		K8sVersion: serverVersion.GitVersion,
+		Namespaces: len(namespaces.Items),
+		Pods:       len(pods.Items),
+	}
+
+	meshedNamespaces := map[string]struct{}{}
+	for i := range pods.Items {
+		if k8s.IsMeshed(&pods.Items[i], controllerNamespace) {
+			report.MeshedPods++
+			meshedNamespaces[pods.Items[i].Namespace] = struct{}{}
+		}
+	}
+	report.MeshedNamespaces = len(meshedNamespaces)
+
+	return report, nil
+}
+
+// Send posts the report as JSON to the endpoint.
+func Send(ctx context.Context, client *http.Client, endpoint string, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected heartbeat response: %s", rsp.Status)
+	}
+	return nil
+}
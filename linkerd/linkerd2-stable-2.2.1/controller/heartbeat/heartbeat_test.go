@@ -0,0 +1,94 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollect(t *testing.T) {
+	namespace := func(name string) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	pod := func(namespace, name string, meshed bool) *v1.Pod {
+		p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if meshed {
+			p.Labels = map[string]string{k8s.ControllerNSLabel: "linkerd"}
+		}
+		return p
+	}
+
+	client := fake.NewSimpleClientset([]runtime.Object{
+		namespace("linkerd"),
+		namespace("emojivoto"),
+		namespace("books"),
+		pod("linkerd", "controller", true),
+		pod("emojivoto", "web", true),
+		pod("emojivoto", "emoji", true),
+		pod("books", "authors", false),
+	}...)
+
+	report, err := Collect(client, "linkerd", "deaab91a-f4ab-448a-b7d1-c832a2fa0a60")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := &Report{
+		UUID:             "deaab91a-f4ab-448a-b7d1-c832a2fa0a60",
+		Version:          version.Version,
+		K8sVersion:       report.K8sVersion,
+		Namespaces:       3,
+		MeshedNamespaces: 2,
+		Pods:             4,
+		MeshedPods:       3,
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("Expected report %+v, got %+v", expected, report)
+	}
+}
+
+func TestSend(t *testing.T) {
+	report := &Report{UUID: "uuid", Version: "stable-2.2.1", Pods: 2, MeshedPods: 1}
+
+	testCases := []struct {
+		status int
+		err    bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusAccepted, false},
+		{http.StatusInternalServerError, true},
+	}
+
+	for i, tc := range testCases {
+		var received Report
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" {
+				t.Errorf("test case %d: expected a POST request, got %s", i, r.Method)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Errorf("test case %d: unexpected error: %s", i, err)
+			}
+			w.WriteHeader(tc.status)
+		}))
+
+		err := Send(context.Background(), ts.Client(), ts.URL, report)
+		ts.Close()
+
+		if tc.err != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got %v", i, tc.err, err)
+		}
+		if !reflect.DeepEqual(&received, report) {
+			t.Fatalf("test case %d: expected the report %+v to be sent, got %+v", i, report, received)
+		}
+	}
+}
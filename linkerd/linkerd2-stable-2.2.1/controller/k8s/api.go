@@ -8,7 +8,6 @@ import (
 
 	spv1alpha1 "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
-	sp "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions"
 	spinformers "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/serviceprofile/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
@@ -21,10 +20,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/informers"
 	arinformers "k8s.io/client-go/informers/admissionregistration/v1beta1"
 	appv1informers "k8s.io/client-go/informers/apps/v1"
 	appv1beta2informers "k8s.io/client-go/informers/apps/v1beta2"
+	batchv1informers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -40,7 +39,9 @@ const (
 	Deploy
 	DS
 	Endpoint
+	Job
 	MWC // mutating webhook configuration
+	Node
 	Pod
 	RC
 	RS
@@ -57,7 +58,9 @@ type API struct {
 	deploy   appv1beta2informers.DeploymentInformer
 	ds       appv1informers.DaemonSetInformer
 	endpoint coreinformers.EndpointsInformer
+	job      batchv1informers.JobInformer
 	mwc      arinformers.MutatingWebhookConfigurationInformer
+	node     coreinformers.NodeInformer
 	pod      coreinformers.PodInformer
 	rc       coreinformers.ReplicationControllerInformer
 	rs       appv1beta2informers.ReplicaSetInformer
@@ -65,76 +68,103 @@ type API struct {
 	ss       appv1informers.StatefulSetInformer
 	svc      coreinformers.ServiceInformer
 
-	syncChecks        []cache.InformerSynced
-	sharedInformers   informers.SharedInformerFactory
-	spSharedInformers sp.SharedInformerFactory
-	namespace         string
+	syncChecks []cache.InformerSynced
+	informers  *informerFactory
+	namespaces []string
 }
 
-// NewAPI takes a Kubernetes client and returns an initialized API
+// NewAPI takes a Kubernetes client and returns an initialized API, restricted
+// to the given namespace unless it's empty.
 func NewAPI(k8sClient kubernetes.Interface, spClient spclient.Interface, namespace string, resources ...APIResource) *API {
-	var sharedInformers informers.SharedInformerFactory
-	var spSharedInformers sp.SharedInformerFactory
-	if namespace == "" {
-		sharedInformers = informers.NewSharedInformerFactory(k8sClient, 10*time.Minute)
-		spSharedInformers = sp.NewSharedInformerFactory(spClient, 10*time.Minute)
-	} else {
-		sharedInformers = informers.NewFilteredSharedInformerFactory(
-			k8sClient,
-			10*time.Minute,
-			namespace,
-			nil,
-		)
-		spSharedInformers = sp.NewFilteredSharedInformerFactory(
-			spClient,
-			10*time.Minute,
-			namespace,
-			nil,
-		)
+	namespaces := []string{}
+	if namespace != "" {
+		namespaces = []string{namespace}
 	}
+	return NewAPIForNamespaces(k8sClient, spClient, namespaces, resources...)
+}
+
+// NewAPIForNamespaces is like NewAPI, but restricted to a list of namespaces,
+// or to none if the list is empty. The namespaced resources of several
+// namespaces are listed and watched in each namespace, so that only
+// permissions in these namespaces are needed.
+func NewAPIForNamespaces(k8sClient kubernetes.Interface, spClient spclient.Interface, namespaces []string, resources ...APIResource) *API {
+	return NewAPIWithOptions(k8sClient, spClient, APIOptions{Namespaces: namespaces}, resources...)
+}
+
+// APIOptions configures the informers of an API.
+type APIOptions struct {
+	// Namespaces restricts the API to a list of namespaces, as in
+	// NewAPIForNamespaces.
+	Namespaces []string
+	// PodSelector is a label selector that the cached pods must match, e.g.
+	// "linkerd.io/control-plane-ns" for meshed pods only. On clusters where
+	// only a fraction of the pods are meshed, it saves the memory and the
+	// sync time of the other pods, which the API won't return.
+	PodSelector string
+	// Resync is the period at which the informers redeliver their cached
+	// objects to their handlers. Defaults to DefaultResync if it's not
+	// positive.
+	Resync time.Duration
+}
+
+// DefaultResync is the default resync period of the informers of an API.
+const DefaultResync = 10 * time.Minute
+
+// NewAPIWithOptions is like NewAPI, but configured with the given options.
+func NewAPIWithOptions(k8sClient kubernetes.Interface, spClient spclient.Interface, options APIOptions, resources ...APIResource) *API {
+	resync := options.Resync
+	if resync <= 0 {
+		resync = DefaultResync
+	}
+	factory := newInformerFactory(k8sClient, spClient, options.Namespaces, options.PodSelector, resync)
 
 	api := &API{
-		Client:            k8sClient,
-		syncChecks:        make([]cache.InformerSynced, 0),
-		sharedInformers:   sharedInformers,
-		spSharedInformers: spSharedInformers,
-		namespace:         namespace,
+		Client:     k8sClient,
+		syncChecks: make([]cache.InformerSynced, 0),
+		informers:  factory,
+		namespaces: options.Namespaces,
 	}
 
 	for _, resource := range resources {
 		switch resource {
 		case CM:
-			api.cm = sharedInformers.Core().V1().ConfigMaps()
+			api.cm = factory.configMaps()
 			api.syncChecks = append(api.syncChecks, api.cm.Informer().HasSynced)
 		case Deploy:
-			api.deploy = sharedInformers.Apps().V1beta2().Deployments()
+			api.deploy = factory.deployments()
 			api.syncChecks = append(api.syncChecks, api.deploy.Informer().HasSynced)
 		case DS:
-			api.ds = sharedInformers.Apps().V1().DaemonSets()
+			api.ds = factory.daemonSets()
 			api.syncChecks = append(api.syncChecks, api.ds.Informer().HasSynced)
 		case Endpoint:
-			api.endpoint = sharedInformers.Core().V1().Endpoints()
+			api.endpoint = factory.endpoints()
 			api.syncChecks = append(api.syncChecks, api.endpoint.Informer().HasSynced)
+		case Job:
+			api.job = factory.jobs()
+			api.syncChecks = append(api.syncChecks, api.job.Informer().HasSynced)
 		case MWC:
-			api.mwc = sharedInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations()
+			api.mwc = factory.mutatingWebhookConfigurations()
 			api.syncChecks = append(api.syncChecks, api.mwc.Informer().HasSynced)
+		case Node:
+			api.node = factory.nodes()
+			api.syncChecks = append(api.syncChecks, api.node.Informer().HasSynced)
 		case Pod:
-			api.pod = sharedInformers.Core().V1().Pods()
+			api.pod = factory.pods()
 			api.syncChecks = append(api.syncChecks, api.pod.Informer().HasSynced)
 		case RC:
-			api.rc = sharedInformers.Core().V1().ReplicationControllers()
+			api.rc = factory.replicationControllers()
 			api.syncChecks = append(api.syncChecks, api.rc.Informer().HasSynced)
 		case RS:
-			api.rs = sharedInformers.Apps().V1beta2().ReplicaSets()
+			api.rs = factory.replicaSets()
 			api.syncChecks = append(api.syncChecks, api.rs.Informer().HasSynced)
 		case SP:
-			api.sp = spSharedInformers.Linkerd().V1alpha1().ServiceProfiles()
+			api.sp = factory.serviceProfiles()
 			api.syncChecks = append(api.syncChecks, api.sp.Informer().HasSynced)
 		case SS:
-			api.ss = sharedInformers.Apps().V1().StatefulSets()
+			api.ss = factory.statefulSets()
 			api.syncChecks = append(api.syncChecks, api.ss.Informer().HasSynced)
 		case Svc:
-			api.svc = sharedInformers.Core().V1().Services()
+			api.svc = factory.services()
 			api.syncChecks = append(api.syncChecks, api.svc.Informer().HasSynced)
 		}
 	}
@@ -142,10 +172,9 @@ func NewAPI(k8sClient kubernetes.Interface, spClient spclient.Interface, namespa
 	return api
 }
 
-// Sync waits for all informers to be synced.
-func (api *API) Sync() {
-	api.sharedInformers.Start(nil)
-	api.spSharedInformers.Start(nil)
+// Sync waits for all informers to be synced, and returns their health.
+func (api *API) Sync() []InformerHealth {
+	api.informers.start(nil)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -155,6 +184,8 @@ func (api *API) Sync() {
 		log.Fatal("failed to sync caches")
 	}
 	log.Infof("caches synced")
+
+	return api.Health()
 }
 
 // Deploy provides access to a shared informer and lister for Deployments.
@@ -189,6 +220,14 @@ func (api *API) RS() appv1beta2informers.ReplicaSetInformer {
 	return api.rs
 }
 
+// Job provides access to a shared informer and lister for Jobs.
+func (api *API) Job() batchv1informers.JobInformer {
+	if api.job == nil {
+		panic("Job informer not configured")
+	}
+	return api.job
+}
+
 // Pod provides access to a shared informer and lister for Pods.
 func (api *API) Pod() coreinformers.PodInformer {
 	if api.pod == nil {
@@ -197,6 +236,14 @@ func (api *API) Pod() coreinformers.PodInformer {
 	return api.pod
 }
 
+// Node provides access to a shared informer and lister for Nodes.
+func (api *API) Node() coreinformers.NodeInformer {
+	if api.node == nil {
+		panic("Node informer not configured")
+	}
+	return api.node
+}
+
 // RC provides access to a shared informer and lister for
 // ReplicationControllers.
 func (api *API) RC() coreinformers.ReplicationControllerInformer {
@@ -289,9 +336,87 @@ func (api *API) GetOwnerKindAndName(pod *apiv1.Pod) (string, string) {
 		return strings.ToLower(rsParent.Kind), rsParent.Name
 	}
 
+	// The Jobs of a CronJob are resolved to the CronJob, which is injected
+	// instead of them, if the API watches Jobs.
+	if parent.Kind == "Job" && api.job != nil {
+		job, err := api.Job().Lister().Jobs(pod.Namespace).Get(parent.Name)
+		if err != nil {
+			return strings.ToLower(parent.Kind), parent.Name
+		}
+		if jobParent := metav1.GetControllerOf(job); jobParent != nil && jobParent.Kind == "CronJob" {
+			return strings.ToLower(jobParent.Kind), jobParent.Name
+		}
+	}
+
 	return strings.ToLower(parent.Kind), parent.Name
 }
 
+// GetPodsForOwner returns the pods whose owner, as returned by
+// GetOwnerKindAndName, is the object of the given kind and name, e.g. the pods
+// of the ReplicaSets of a deployment. They're looked up in the index of the
+// pods by owner, rather than by resolving the owner of every pod of the
+// namespace.
+func (api *API) GetPodsForOwner(namespace, kind, name string) ([]*apiv1.Pod, error) {
+	if kind == k8s.Pod {
+		pod, err := api.Pod().Lister().Pods(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []*apiv1.Pod{pod}, nil
+	}
+
+	owners := []string{ownerIndexKey(namespace, kind, name)}
+
+	// the pods of a ReplicaSet are owned by the owner of the ReplicaSet, e.g.
+	// a deployment
+	if api.rs != nil {
+		replicaSets, err := api.RS().Lister().ReplicaSets(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range replicaSets {
+			if isOwnedBy(rs, kind, name) {
+				owners = append(owners, ownerIndexKey(namespace, "ReplicaSet", rs.Name))
+			}
+		}
+	}
+
+	// the pods of the Jobs of a CronJob are owned by the CronJob
+	if kind == "cronjob" && api.job != nil {
+		jobs, err := api.Job().Lister().Jobs(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			if jobParent := metav1.GetControllerOf(job); jobParent != nil && jobParent.Kind == "CronJob" && jobParent.Name == name {
+				owners = append(owners, ownerIndexKey(namespace, "Job", job.Name))
+			}
+		}
+	}
+
+	pods := []*apiv1.Pod{}
+	for _, owner := range owners {
+		objs, err := api.Pod().Informer().GetIndexer().ByIndex(podOwnerIndex, owner)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			pods = append(pods, obj.(*apiv1.Pod))
+		}
+	}
+	return pods, nil
+}
+
+// isOwnedBy returns whether the single owner reference of an object is the
+// object of the given kind and name.
+func isOwnedBy(obj metav1.Object, kind, name string) bool {
+	owners := obj.GetOwnerReferences()
+	return len(owners) == 1 && strings.ToLower(owners[0].Kind) == kind && owners[0].Name == name
+}
+
 // GetPodsFor returns all running and pending Pods associated with a given
 // Kubernetes object. Use includeFailed to also get failed Pods
 func (api *API) GetPodsFor(obj runtime.Object, includeFailed bool) ([]*apiv1.Pod, error) {
@@ -417,16 +542,20 @@ func GetNamespaceOf(obj runtime.Object) (string, error) {
 
 // getNamespaces returns the namespace matching the specified name. If no name
 // is given, it returns all namespaces, unless the API was configured to only
-// work with a single namespace, in which case it returns that namespace. Note
+// work with some namespaces, in which case it returns these namespaces. Note
 // that namespace reads are not cached.
 func (api *API) getNamespaces(name string) ([]runtime.Object, error) {
 	namespaces := make([]*apiv1.Namespace, 0)
 
-	if name == "" && api.namespace != "" {
-		name = api.namespace
-	}
-
-	if name == "" {
+	if name == "" && len(api.namespaces) > 0 {
+		for _, ns := range api.namespaces {
+			namespace, err := api.Client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			namespaces = append(namespaces, namespace)
+		}
+	} else if name == "" {
 		namespaceList, err := api.Client.CoreV1().Namespaces().List(metav1.ListOptions{})
 		if err != nil {
 			return nil, err
@@ -270,6 +270,107 @@ metadata:
 		})
 	})
 
+	t.Run("In multi-namespace mode", func(t *testing.T) {
+		configs := []string{}
+		for _, ns := range []string{"namespace1", "namespace2", "namespace3"} {
+			configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s`, ns), fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+  namespace: %s
+status:
+  phase: Running`, ns))
+		}
+
+		api, err := NewFakeAPIForNamespaces([]string{"namespace1", "namespace3"}, configs...)
+		if err != nil {
+			t.Fatalf("NewFakeAPIForNamespaces returned an error: %s", err)
+		}
+		api.Sync()
+
+		t.Run("Returns only the configured namespaces", func(t *testing.T) {
+			namespaces, err := api.GetObjects("", k8s.Namespace, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			names := []string{}
+			for _, ns := range namespaces {
+				names = append(names, ns.(*apiv1.Namespace).Name)
+			}
+			expected := []string{"namespace1", "namespace3"}
+			if !reflect.DeepEqual(names, expected) {
+				t.Fatalf("expected namespaces %v, got %v", expected, names)
+			}
+		})
+
+		t.Run("Returns only the objects of the configured namespaces", func(t *testing.T) {
+			pods, err := api.GetObjects("", k8s.Pod, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			namespaces := map[string]bool{}
+			for _, pod := range pods {
+				namespaces[pod.(*apiv1.Pod).Namespace] = true
+			}
+			expected := map[string]bool{"namespace1": true, "namespace3": true}
+			if !reflect.DeepEqual(namespaces, expected) {
+				t.Fatalf("expected pods in namespaces %v, got %v", expected, namespaces)
+			}
+
+			pods, err = api.GetObjects("namespace2", k8s.Pod, "my-pod")
+			if err == nil {
+				t.Fatalf("expected an error for a pod outside of the configured namespaces, got %v", pods)
+			}
+		})
+	})
+
+	t.Run("With a pod selector", func(t *testing.T) {
+		configs := []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: meshed-pod
+  namespace: my-ns
+  labels:
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: unmeshed-pod
+  namespace: my-ns
+status:
+  phase: Running`,
+		}
+
+		for _, namespaces := range [][]string{{}, {"my-ns"}, {"my-ns", "other-ns"}} {
+			api, err := NewFakeAPIWithOptions(APIOptions{
+				Namespaces:  namespaces,
+				PodSelector: k8s.ControllerNSLabel,
+			}, configs...)
+			if err != nil {
+				t.Fatalf("NewFakeAPIWithOptions returned an error: %s", err)
+			}
+			api.Sync()
+
+			pods, err := api.GetObjects("my-ns", k8s.Pod, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(pods) != 1 || pods[0].(*apiv1.Pod).Name != "meshed-pod" {
+				t.Fatalf("expected only meshed-pod with namespaces %v, got %v", namespaces, pods)
+			}
+		}
+	})
+
 	t.Run("If objects are pods", func(t *testing.T) {
 		t.Run("Return running or pending pods", func(t *testing.T) {
 			expectations := []getObjectsExpected{
@@ -662,6 +763,32 @@ metadata:
     kind: Job
     name: slow-cooker`,
 		},
+		{
+			expectedOwnerKind: "cronjob",
+			expectedOwnerName: "backup",
+			podConfig: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: backup-1551398400-7xk2p
+  namespace: default
+  ownerReferences:
+  - apiVersion: batch/v1
+    kind: Job
+    name: backup-1551398400`,
+			extraConfigs: []string{`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: backup-1551398400
+  namespace: default
+  ownerReferences:
+  - apiVersion: batch/v1beta1
+    kind: CronJob
+    name: backup
+    controller: true`,
+			},
+		},
 		{
 			expectedOwnerKind: "replicationcontroller",
 			expectedOwnerName: "web",
@@ -702,6 +829,14 @@ metadata:
 		if ownerName != tt.expectedOwnerName {
 			t.Fatalf("Expected name to be [%s], got [%s]", tt.expectedOwnerName, ownerName)
 		}
+
+		pods, err := api.GetPodsForOwner(pod.Namespace, tt.expectedOwnerKind, tt.expectedOwnerName)
+		if err != nil {
+			t.Fatalf("GetPodsForOwner error: %s", err)
+		}
+		if len(pods) != 1 || pods[0].Name != pod.Name {
+			t.Fatalf("Expected the pods of [%s/%s] to be [%s], got %v", tt.expectedOwnerKind, tt.expectedOwnerName, pod.Name, pods)
+		}
 	}
 }
 
@@ -1,20 +1,49 @@
 package k8s
 
 import (
+	"flag"
+
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// ClientOptions tunes the load that the Kubernetes clients of a controller put
+// on the Kubernetes API. Zero values keep the defaults of client-go.
+type ClientOptions struct {
+	// QPS is the maximum number of queries per second to the Kubernetes API.
+	QPS float32
+	// Burst is the maximum number of queries above QPS sent at once.
+	Burst int
+}
+
+func (o ClientOptions) apply(config *rest.Config) {
+	if o.QPS > 0 {
+		config.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		config.Burst = o.Burst
+	}
+}
+
 // NewClientSet returns a Kubernetes client for the given configuration.
 func NewClientSet(kubeConfig string) (*kubernetes.Clientset, error) {
+	return NewClientSetWithOptions(kubeConfig, ClientOptions{})
+}
+
+// NewClientSetWithOptions is like NewClientSet, but tuned with the given
+// options.
+func NewClientSetWithOptions(kubeConfig string, options ClientOptions) (*kubernetes.Clientset, error) {
 	config, err := k8s.GetConfig(kubeConfig, "")
 	if err != nil {
 		return nil, err
 	}
+	options.apply(config)
 
 	return kubernetes.NewForConfig(config)
 }
@@ -22,10 +51,52 @@ func NewClientSet(kubeConfig string) (*kubernetes.Clientset, error) {
 // NewSpClientSet returns a Kubernetes ServiceProfile client for the given
 // configuration.
 func NewSpClientSet(kubeConfig string) (*spclient.Clientset, error) {
+	return NewSpClientSetWithOptions(kubeConfig, ClientOptions{})
+}
+
+// NewSpClientSetWithOptions is like NewSpClientSet, but tuned with the given
+// options.
+func NewSpClientSetWithOptions(kubeConfig string, options ClientOptions) (*spclient.Clientset, error) {
 	config, err := k8s.GetConfig(kubeConfig, "")
 	if err != nil {
 		return nil, err
 	}
+	options.apply(config)
 
 	return spclient.NewForConfig(config)
 }
+
+// ClientFlags are the command-line flags of a controller that tune its
+// Kubernetes clients.
+type ClientFlags struct {
+	qps   *float64
+	burst *int
+}
+
+// NewClientFlags registers the -kube-api-qps and -kube-api-burst flags. They
+// must be parsed before the options they hold are used.
+func NewClientFlags() *ClientFlags {
+	return &ClientFlags{
+		qps:   flag.Float64("kube-api-qps", 0, "maximum number of queries per second to the Kubernetes API; 0 for client-go's default (5)"),
+		burst: flag.Int("kube-api-burst", 0, "maximum number of queries to the Kubernetes API above the QPS sent at once; 0 for client-go's default (10)"),
+	}
+}
+
+// Options returns the client options set by the flags.
+func (f *ClientFlags) Options() ClientOptions {
+	return ClientOptions{
+		QPS:   float32(*f.qps),
+		Burst: *f.burst,
+	}
+}
+
+// NewClientSetFromKubeConfig returns a Kubernetes client for the contents of
+// a kubeconfig file, e.g. read from a secret.
+func NewClientSetFromKubeConfig(kubeConfig []byte) (*kubernetes.Clientset, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
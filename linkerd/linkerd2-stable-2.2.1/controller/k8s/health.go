@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultMaxStaleness is how long the informers of an API may go without
+// being in sync with the Kubernetes API before the API is considered
+// unhealthy.
+const DefaultMaxStaleness = 5 * time.Minute
+
+var (
+	lastSyncDesc = prometheus.NewDesc(
+		"k8s_informer_last_sync_timestamp_seconds",
+		"The last time the informer of a resource was known to be in sync with the Kubernetes API.",
+		[]string{"resource"},
+		nil,
+	)
+	watchErrorsDesc = prometheus.NewDesc(
+		"k8s_informer_watch_errors_total",
+		"A counter for the failed lists and watches of the informer of a resource.",
+		[]string{"resource"},
+		nil,
+	)
+	cachedObjectsDesc = prometheus.NewDesc(
+		"k8s_informer_cached_objects",
+		"A gauge for the number of objects in the cache of the informer of a resource.",
+		[]string{"resource"},
+		nil,
+	)
+
+	healthCollector = &informerHealthCollector{}
+)
+
+func init() {
+	prometheus.MustRegister(healthCollector)
+}
+
+// InformerHealth is the health of the informer of a resource.
+type InformerHealth struct {
+	Resource string
+	// LastSync is the last time the informer was known to be in sync with the
+	// Kubernetes API, i.e. the time of its last list or watch, or the current
+	// time while it's watching. It's zero if the informer never listed.
+	LastSync time.Time
+	// Staleness is how long ago LastSync was, or how long ago the informer was
+	// created if it never listed.
+	Staleness     time.Duration
+	WatchErrors   uint64
+	CachedObjects int
+}
+
+// informerHealth keeps track of the health of an informer, which its
+// namespacesListWatch reports its lists, watches and errors to.
+type informerHealth struct {
+	resource string
+	informer cache.SharedIndexInformer
+	created  time.Time
+
+	mutex       sync.Mutex
+	lastSync    time.Time
+	watches     int
+	watchErrors uint64
+}
+
+func newInformerHealth(resource string) *informerHealth {
+	h := &informerHealth{
+		resource: resource,
+		created:  time.Now(),
+	}
+	healthCollector.add(h)
+	return h
+}
+
+func (h *informerHealth) listed() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.lastSync = time.Now()
+}
+
+func (h *informerHealth) watchStarted() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watches++
+}
+
+func (h *informerHealth) watchStopped() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watches--
+	h.lastSync = time.Now()
+}
+
+func (h *informerHealth) failed() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watchErrors++
+}
+
+func (h *informerHealth) get() InformerHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	health := InformerHealth{
+		Resource:    h.resource,
+		LastSync:    h.lastSync,
+		WatchErrors: h.watchErrors,
+	}
+	if h.watches > 0 && !h.lastSync.IsZero() {
+		health.LastSync = now
+	}
+	if health.LastSync.IsZero() {
+		health.Staleness = now.Sub(h.created)
+	} else {
+		health.Staleness = now.Sub(health.LastSync)
+	}
+	if h.informer != nil {
+		health.CachedObjects = len(h.informer.GetStore().ListKeys())
+	}
+	return health
+}
+
+// Health returns the health of the informers of the API.
+func (api *API) Health() []InformerHealth {
+	health := make([]InformerHealth, 0, len(api.informers.health))
+	for _, h := range api.informers.health {
+		health = append(health, h.get())
+	}
+	return health
+}
+
+// CheckHealth returns an error if an informer of the API hasn't been in sync
+// with the Kubernetes API for longer than maxStaleness, in which case the API
+// may be serving stale data.
+func (api *API) CheckHealth(maxStaleness time.Duration) error {
+	for _, health := range api.Health() {
+		if health.Staleness > maxStaleness {
+			return fmt.Errorf("the %s informer has been out of sync for %s (%d watch errors)", health.Resource, health.Staleness.Round(time.Second), health.WatchErrors)
+		}
+	}
+	return nil
+}
+
+// informerHealthCollector exports the health of all the informers to
+// Prometheus. The informers of the same resource, e.g. those of several APIs,
+// are added up.
+type informerHealthCollector struct {
+	sync.Mutex
+	health []*informerHealth
+}
+
+func (c *informerHealthCollector) add(h *informerHealth) {
+	c.Lock()
+	defer c.Unlock()
+	c.health = append(c.health, h)
+}
+
+func (c *informerHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lastSyncDesc
+	ch <- watchErrorsDesc
+	ch <- cachedObjectsDesc
+}
+
+func (c *informerHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.Lock()
+	health := make([]*informerHealth, len(c.health))
+	copy(health, c.health)
+	c.Unlock()
+
+	resources := make([]string, 0)
+	byResource := make(map[string]*InformerHealth)
+	for _, h := range health {
+		current := h.get()
+		total, ok := byResource[current.Resource]
+		if !ok {
+			resources = append(resources, current.Resource)
+			byResource[current.Resource] = &current
+			continue
+		}
+		// the oldest sync of the informers of a resource is reported
+		if current.LastSync.Before(total.LastSync) {
+			total.LastSync = current.LastSync
+		}
+		total.WatchErrors += current.WatchErrors
+		total.CachedObjects += current.CachedObjects
+	}
+
+	for _, resource := range resources {
+		total := byResource[resource]
+		lastSync := 0.0
+		if !total.LastSync.IsZero() {
+			lastSync = float64(total.LastSync.UnixNano()) / float64(time.Second)
+		}
+		ch <- prometheus.MustNewConstMetric(lastSyncDesc, prometheus.GaugeValue, lastSync, resource)
+		ch <- prometheus.MustNewConstMetric(watchErrorsDesc, prometheus.CounterValue, float64(total.WatchErrors), resource)
+		ch <- prometheus.MustNewConstMetric(cachedObjectsDesc, prometheus.GaugeValue, float64(total.CachedObjects), resource)
+	}
+}
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("Reports synced informers as healthy", func(t *testing.T) {
+		api, err := NewFakeAPI("", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+  namespace: my-ns
+status:
+  phase: Running`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		for _, health := range api.Sync() {
+			if health.LastSync.IsZero() {
+				t.Fatalf("expected the %s informer to be synced", health.Resource)
+			}
+			if health.Staleness > time.Minute {
+				t.Fatalf("expected the %s informer to be fresh, got a staleness of %s", health.Resource, health.Staleness)
+			}
+			if health.Resource == k8s.Pod && health.CachedObjects != 1 {
+				t.Fatalf("expected 1 cached pod, got %d", health.CachedObjects)
+			}
+		}
+
+		err = api.CheckHealth(DefaultMaxStaleness)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Reports informers that stopped watching as stale", func(t *testing.T) {
+		api, err := NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		h := api.informers.health[0]
+		h.listed()
+		h.watchStarted()
+		h.watchStopped()
+		h.failed()
+		h.lastSync = time.Now().Add(-10 * time.Minute)
+
+		health := h.get()
+		if health.Staleness < 10*time.Minute {
+			t.Fatalf("expected a staleness of at least 10m, got %s", health.Staleness)
+		}
+		if health.WatchErrors != 1 {
+			t.Fatalf("expected 1 watch error, got %d", health.WatchErrors)
+		}
+
+		err = api.CheckHealth(DefaultMaxStaleness)
+		if err == nil || !strings.Contains(err.Error(), "out of sync") {
+			t.Fatalf("expected an out of sync error, got %v", err)
+		}
+	})
+}
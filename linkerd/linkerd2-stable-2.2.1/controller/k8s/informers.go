@@ -0,0 +1,380 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	spv1alpha1 "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
+	splisters "github.com/linkerd/linkerd2/controller/gen/client/listers/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	arlisters "k8s.io/client-go/listers/admissionregistration/v1beta1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	appsv1beta2listers "k8s.io/client-go/listers/apps/v1beta2"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// podOwnerIndex is the name of the index of the pods by owner.
+const podOwnerIndex = "owner"
+
+// informerFactory builds the informers of an API. Unlike the shared informer
+// factories of client-go, which work with one namespace or all of them, it
+// lists and watches the namespaced resources in each of a list of namespaces,
+// so that only permissions in these namespaces are needed, and merges them
+// into a single cache. It also keeps track of the health of each informer.
+type informerFactory struct {
+	k8sClient   kubernetes.Interface
+	spClient    spclient.Interface
+	namespaces  []string
+	podSelector string
+	resync      time.Duration
+	informers   []cache.SharedIndexInformer
+	started     int
+	health      []*informerHealth
+}
+
+// newInformerFactory returns an informerFactory for the given namespaces, or
+// for all of them if the list is empty.
+func newInformerFactory(k8sClient kubernetes.Interface, spClient spclient.Interface, namespaces []string, podSelector string, resync time.Duration) *informerFactory {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	return &informerFactory{
+		k8sClient:   k8sClient,
+		spClient:    spClient,
+		namespaces:  namespaces,
+		podSelector: podSelector,
+		resync:      resync,
+	}
+}
+
+// start runs all the informers built so far that aren't running yet.
+func (f *informerFactory) start(stopCh <-chan struct{}) {
+	for _, informer := range f.informers[f.started:] {
+		go informer.Run(stopCh)
+	}
+	f.started = len(f.informers)
+}
+
+// newInformer builds the informer of a resource, given a function returning
+// its ListWatch in a namespace. Cluster-scoped resources are listed and
+// watched once, regardless of the namespaces of the factory.
+func (f *informerFactory) newInformer(resource string, objType runtime.Object, clusterScoped bool, newListWatch func(namespace string) *cache.ListWatch) cache.SharedIndexInformer {
+	namespaces := f.namespaces
+	if clusterScoped {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	listWatches := make(map[string]*cache.ListWatch)
+	for _, ns := range namespaces {
+		listWatches[ns] = newListWatch(ns)
+	}
+
+	health := newInformerHealth(resource)
+	informer := cache.NewSharedIndexInformer(
+		newNamespacesListWatch(listWatches, health),
+		objType,
+		f.resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	health.informer = informer
+
+	f.informers = append(f.informers, informer)
+	f.health = append(f.health, health)
+	return informer
+}
+
+func (f *informerFactory) configMaps() *cmInformer {
+	return &cmInformer{f.newInformer("configmap", &apiv1.ConfigMap{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.CoreV1().ConfigMaps(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.CoreV1().ConfigMaps(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) deployments() *deployInformer {
+	return &deployInformer{f.newInformer(k8s.Deployment, &appsv1beta2.Deployment{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.AppsV1beta2().Deployments(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.AppsV1beta2().Deployments(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) daemonSets() *dsInformer {
+	return &dsInformer{f.newInformer(k8s.DaemonSet, &appsv1.DaemonSet{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.AppsV1().DaemonSets(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.AppsV1().DaemonSets(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) endpoints() *endpointInformer {
+	return &endpointInformer{f.newInformer("endpoints", &apiv1.Endpoints{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.CoreV1().Endpoints(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.CoreV1().Endpoints(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) jobs() *jobInformer {
+	return &jobInformer{f.newInformer(k8s.Job, &batchv1.Job{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.BatchV1().Jobs(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.BatchV1().Jobs(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) mutatingWebhookConfigurations() *mwcInformer {
+	return &mwcInformer{f.newInformer("mutatingwebhookconfiguration", &arv1beta1.MutatingWebhookConfiguration{}, true, func(string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) nodes() *nodeInformer {
+	return &nodeInformer{f.newInformer("node", &apiv1.Node{}, true, func(string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.CoreV1().Nodes().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.CoreV1().Nodes().Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) pods() *podInformer {
+	informer := f.newInformer(k8s.Pod, &apiv1.Pod{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = f.podSelector
+				return f.k8sClient.CoreV1().Pods(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = f.podSelector
+				return f.k8sClient.CoreV1().Pods(ns).Watch(options)
+			},
+		}
+	})
+	// indexers can only fail to be added once the informer is running
+	informer.AddIndexers(cache.Indexers{podOwnerIndex: podOwnerIndexFunc})
+	return &podInformer{informer}
+}
+
+// podOwnerIndexFunc indexes the pods by their owner reference, when they have
+// exactly one, like GetOwnerKindAndName.
+func podOwnerIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*apiv1.Pod)
+	if !ok || len(pod.GetOwnerReferences()) != 1 {
+		return nil, nil
+	}
+	owner := pod.GetOwnerReferences()[0]
+	return []string{ownerIndexKey(pod.Namespace, owner.Kind, owner.Name)}, nil
+}
+
+// ownerIndexKey returns the key of the pods of an owner in the podOwnerIndex.
+func ownerIndexKey(namespace, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, strings.ToLower(kind), name)
+}
+
+func (f *informerFactory) replicationControllers() *rcInformer {
+	return &rcInformer{f.newInformer(k8s.ReplicationController, &apiv1.ReplicationController{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.CoreV1().ReplicationControllers(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.CoreV1().ReplicationControllers(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) replicaSets() *rsInformer {
+	return &rsInformer{f.newInformer(k8s.ReplicaSet, &appsv1beta2.ReplicaSet{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.AppsV1beta2().ReplicaSets(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.AppsV1beta2().ReplicaSets(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) serviceProfiles() *spInformer {
+	return &spInformer{f.newInformer(k8s.ServiceProfile, &spv1alpha1.ServiceProfile{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.spClient.LinkerdV1alpha1().ServiceProfiles(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.spClient.LinkerdV1alpha1().ServiceProfiles(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) statefulSets() *ssInformer {
+	return &ssInformer{f.newInformer(k8s.StatefulSet, &appsv1.StatefulSet{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.AppsV1().StatefulSets(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.AppsV1().StatefulSets(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+func (f *informerFactory) services() *svcInformer {
+	return &svcInformer{f.newInformer(k8s.Service, &apiv1.Service{}, false, func(ns string) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return f.k8sClient.CoreV1().Services(ns).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return f.k8sClient.CoreV1().Services(ns).Watch(options)
+			},
+		}
+	})}
+}
+
+// The following types implement the typed informer interfaces of client-go,
+// e.g. coreinformers.PodInformer, for the informers of informerFactory.
+
+type cmInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *cmInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *cmInformer) Lister() corelisters.ConfigMapLister {
+	return corelisters.NewConfigMapLister(i.informer.GetIndexer())
+}
+
+type deployInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *deployInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *deployInformer) Lister() appsv1beta2listers.DeploymentLister {
+	return appsv1beta2listers.NewDeploymentLister(i.informer.GetIndexer())
+}
+
+type dsInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *dsInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *dsInformer) Lister() appsv1listers.DaemonSetLister {
+	return appsv1listers.NewDaemonSetLister(i.informer.GetIndexer())
+}
+
+type endpointInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *endpointInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *endpointInformer) Lister() corelisters.EndpointsLister {
+	return corelisters.NewEndpointsLister(i.informer.GetIndexer())
+}
+
+type jobInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *jobInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *jobInformer) Lister() batchv1listers.JobLister {
+	return batchv1listers.NewJobLister(i.informer.GetIndexer())
+}
+
+type mwcInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *mwcInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *mwcInformer) Lister() arlisters.MutatingWebhookConfigurationLister {
+	return arlisters.NewMutatingWebhookConfigurationLister(i.informer.GetIndexer())
+}
+
+type nodeInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *nodeInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *nodeInformer) Lister() corelisters.NodeLister {
+	return corelisters.NewNodeLister(i.informer.GetIndexer())
+}
+
+type podInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *podInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *podInformer) Lister() corelisters.PodLister {
+	return corelisters.NewPodLister(i.informer.GetIndexer())
+}
+
+type rcInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *rcInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *rcInformer) Lister() corelisters.ReplicationControllerLister {
+	return corelisters.NewReplicationControllerLister(i.informer.GetIndexer())
+}
+
+type rsInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *rsInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *rsInformer) Lister() appsv1beta2listers.ReplicaSetLister {
+	return appsv1beta2listers.NewReplicaSetLister(i.informer.GetIndexer())
+}
+
+type spInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *spInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *spInformer) Lister() splisters.ServiceProfileLister {
+	return splisters.NewServiceProfileLister(i.informer.GetIndexer())
+}
+
+type ssInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *ssInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *ssInformer) Lister() appsv1listers.StatefulSetLister {
+	return appsv1listers.NewStatefulSetLister(i.informer.GetIndexer())
+}
+
+type svcInformer struct{ informer cache.SharedIndexInformer }
+
+func (i *svcInformer) Informer() cache.SharedIndexInformer { return i.informer }
+func (i *svcInformer) Lister() corelisters.ServiceLister {
+	return corelisters.NewServiceLister(i.informer.GetIndexer())
+}
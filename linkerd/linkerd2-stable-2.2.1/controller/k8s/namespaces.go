@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespacesListWatch lists and watches the objects of a resource in several
+// namespaces. Each namespace is watched from the resource version of its own
+// list, or of its last watch event, which it keeps track of since the
+// resource versions the reflector of the informer passes to Watch are those
+// of a single namespace. It reports its lists, watches and errors to the
+// health of the informer.
+type namespacesListWatch struct {
+	listWatches map[string]*cache.ListWatch
+	health      *informerHealth
+
+	mutex            sync.Mutex
+	resourceVersions map[string]string
+}
+
+func newNamespacesListWatch(listWatches map[string]*cache.ListWatch, health *informerHealth) *namespacesListWatch {
+	return &namespacesListWatch{
+		listWatches:      listWatches,
+		health:           health,
+		resourceVersions: make(map[string]string),
+	}
+}
+
+// List returns the list of the first namespace, with the items of all the
+// namespaces.
+func (lw *namespacesListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	list, err := lw.list(options)
+	if err != nil {
+		lw.health.failed()
+		return nil, err
+	}
+	lw.health.listed()
+	return list, nil
+}
+
+func (lw *namespacesListWatch) list(options metav1.ListOptions) (runtime.Object, error) {
+	var list runtime.Object
+	items := make([]runtime.Object, 0)
+	resourceVersions := make(map[string]string)
+
+	for ns, nsListWatch := range lw.listWatches {
+		nsList, err := nsListWatch.List(options)
+		if err != nil {
+			return nil, err
+		}
+		nsItems, err := meta.ExtractList(nsList)
+		if err != nil {
+			return nil, err
+		}
+		listMeta, err := meta.ListAccessor(nsList)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, nsItems...)
+		resourceVersions[ns] = listMeta.GetResourceVersion()
+		if list == nil {
+			list = nsList
+		}
+	}
+
+	err := meta.SetList(list, items)
+	if err != nil {
+		return nil, err
+	}
+
+	lw.mutex.Lock()
+	lw.resourceVersions = resourceVersions
+	lw.mutex.Unlock()
+
+	return list, nil
+}
+
+// Watch watches all the namespaces, each from its own resource version. The
+// watch stops as soon as the watch of one of the namespaces stops.
+func (lw *namespacesListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w := &namespacesWatch{
+		result: make(chan watch.Event),
+		stopCh: make(chan struct{}),
+	}
+
+	namespaces := make([]string, 0, len(lw.listWatches))
+	for ns, nsListWatch := range lw.listWatches {
+		nsOptions := options
+		lw.mutex.Lock()
+		if resourceVersion, ok := lw.resourceVersions[ns]; ok {
+			nsOptions.ResourceVersion = resourceVersion
+		}
+		lw.mutex.Unlock()
+
+		nsWatch, err := nsListWatch.Watch(nsOptions)
+		if err != nil {
+			w.Stop()
+			lw.health.failed()
+			return nil, err
+		}
+		namespaces = append(namespaces, ns)
+		w.watches = append(w.watches, nsWatch)
+	}
+
+	lw.health.watchStarted()
+	for i, nsWatch := range w.watches {
+		w.wg.Add(1)
+		go lw.forward(namespaces[i], nsWatch, w)
+	}
+
+	go func() {
+		w.wg.Wait()
+		lw.health.watchStopped()
+		close(w.result)
+	}()
+
+	return w, nil
+}
+
+// forward sends the events of the watch of a namespace to w, and tracks their
+// resource versions.
+func (lw *namespacesListWatch) forward(ns string, nsWatch watch.Interface, w *namespacesWatch) {
+	defer w.wg.Done()
+	defer w.Stop()
+
+	for event := range nsWatch.ResultChan() {
+		if event.Type == watch.Error {
+			lw.health.failed()
+		} else if obj, err := meta.Accessor(event.Object); err == nil {
+			lw.mutex.Lock()
+			lw.resourceVersions[ns] = obj.GetResourceVersion()
+			lw.mutex.Unlock()
+		} else {
+			log.Errorf("failed to get the resource version of a watch event in namespace %s: %s", ns, err)
+		}
+
+		select {
+		case w.result <- event:
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// namespacesWatch merges the watches of several namespaces.
+type namespacesWatch struct {
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	watches  []watch.Interface
+	wg       sync.WaitGroup
+}
+
+func (w *namespacesWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *namespacesWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		for _, nsWatch := range w.watches {
+			nsWatch.Stop()
+		}
+	})
+}
+
+// ParseNamespaces parses a comma-separated list of namespaces, as given to the
+// -namespaces flag of the controllers, and adds the controller namespace to
+// it, which the controllers always need access to.
+func ParseNamespaces(namespaces, controllerNamespace string) []string {
+	parsed := []string{controllerNamespace}
+	seen := map[string]struct{}{controllerNamespace: {}}
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if _, ok := seen[ns]; ns == "" || ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		parsed = append(parsed, ns)
+	}
+	return parsed
+}
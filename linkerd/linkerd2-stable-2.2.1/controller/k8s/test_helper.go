@@ -20,6 +20,22 @@ func toRuntimeObject(config string) (runtime.Object, error) {
 
 // NewFakeAPI provides a mock Kubernetes API for testing.
 func NewFakeAPI(namespace string, configs ...string) (*API, error) {
+	namespaces := []string{}
+	if namespace != "" {
+		namespaces = []string{namespace}
+	}
+	return NewFakeAPIForNamespaces(namespaces, configs...)
+}
+
+// NewFakeAPIForNamespaces provides a mock Kubernetes API restricted to a list
+// of namespaces for testing.
+func NewFakeAPIForNamespaces(namespaces []string, configs ...string) (*API, error) {
+	return NewFakeAPIWithOptions(APIOptions{Namespaces: namespaces}, configs...)
+}
+
+// NewFakeAPIWithOptions provides a mock Kubernetes API configured with the
+// given options for testing.
+func NewFakeAPIWithOptions(options APIOptions, configs ...string) (*API, error) {
 	objs := []runtime.Object{}
 	spObjs := []runtime.Object{}
 	for _, config := range configs {
@@ -36,20 +52,22 @@ func NewFakeAPI(namespace string, configs ...string) (*API, error) {
 
 	clientSet := fake.NewSimpleClientset(objs...)
 	spClientSet := spfake.NewSimpleClientset(spObjs...)
-	return NewAPI(
+	return NewAPIWithOptions(
 		clientSet,
 		spClientSet,
-		namespace,
+		options,
 		CM,
 		Deploy,
 		DS,
 		SS,
 		Endpoint,
+		Job,
 		Pod,
 		RC,
 		RS,
 		Svc,
 		SP,
 		MWC,
+		Node,
 	), nil
 }
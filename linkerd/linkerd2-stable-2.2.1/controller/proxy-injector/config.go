@@ -0,0 +1,179 @@
+package injector
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	envVarKeyProxyLog = "LINKERD2_PROXY_LOG"
+
+	initArgInboundPortsToIgnore    = "--inbound-ports-to-ignore"
+	initArgOutboundPortsToIgnore   = "--outbound-ports-to-ignore"
+	initArgInboundSubnetsToIgnore  = "--inbound-subnets-to-ignore"
+	initArgOutboundSubnetsToIgnore = "--outbound-subnets-to-ignore"
+	initArgMarksToIgnore           = "--marks-to-ignore"
+	initArgIptablesMode            = "--iptables-mode"
+
+	iptablesLegacyMode = "legacy"
+)
+
+// initArgsFromConfig are the flags of proxy-init whose values the persisted
+// configuration replaces.
+var initArgsFromConfig = map[string]bool{
+	initArgInboundPortsToIgnore:    true,
+	initArgOutboundPortsToIgnore:   true,
+	initArgInboundSubnetsToIgnore:  true,
+	initArgOutboundSubnetsToIgnore: true,
+	initArgMarksToIgnore:           true,
+	initArgIptablesMode:            true,
+}
+
+// proxyConfig holds the settings of the persisted configuration of the
+// control plane, written by linkerd install to the linkerd-config ConfigMap,
+// that the webhook applies on top of the sidecar template. The ConfigMap is
+// read on every request, so that the settings changed with linkerd config
+// edit apply without redeploying the webhook.
+type proxyConfig struct {
+	ProxyLogLevel         string   `json:"proxyLogLevel"`
+	IgnoreInboundPorts    []string `json:"ignoreInboundPorts"`
+	IgnoreOutboundPorts   []string `json:"ignoreOutboundPorts"`
+	IgnoreInboundSubnets  []string `json:"ignoreInboundSubnets"`
+	IgnoreOutboundSubnets []string `json:"ignoreOutboundSubnets"`
+	IgnoreMarks           []string `json:"ignoreMarks"`
+	IptablesMode          string   `json:"iptablesMode"`
+	ProxyControlPort      uint     `json:"proxyControlPort"`
+	ProxyMetricsPort      uint     `json:"proxyMetricsPort"`
+	ProxyAwait            bool     `json:"proxyAwait"`
+	ProxyJobShutdown      bool     `json:"proxyJobShutdown"`
+}
+
+// proxyConfig returns the persisted configuration of the control plane, or
+// nil if it can't be read, e.g. because the control plane was installed by an
+// older version, in which case the sidecar template is used as is.
+func (w *Webhook) proxyConfig() *proxyConfig {
+	cm, err := w.client.CoreV1().ConfigMaps(w.controllerNamespace).Get(k8sPkg.ConfigConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		log.Debugf("using the sidecar template as is, failed to read the %s ConfigMap: %s", k8sPkg.ConfigConfigMapName, err)
+		return nil
+	}
+
+	var config proxyConfig
+	if err := json.Unmarshal([]byte(cm.Data[k8sPkg.ConfigProxyKey]), &config); err != nil {
+		log.Errorf("using the sidecar template as is, invalid configuration in the %s ConfigMap: %s", k8sPkg.ConfigConfigMapName, err)
+		return nil
+	}
+	return &config
+}
+
+// applyTo sets the log level of the proxy container, and the ports, subnets
+// and marks the proxy-init container doesn't redirect to the proxy, along with
+// the variant of iptables it uses.
+func (c *proxyConfig) applyTo(proxy, proxyInit *corev1.Container) {
+	if c.ProxyLogLevel != "" {
+		for i, env := range proxy.Env {
+			if env.Name == envVarKeyProxyLog {
+				proxy.Env[i].Value = c.ProxyLogLevel
+			}
+		}
+	}
+
+	args := []string{}
+	for i := 0; i < len(proxyInit.Args); i++ {
+		if initArgsFromConfig[proxyInit.Args[i]] {
+			// skip the value of the flag too
+			i++
+			continue
+		}
+		args = append(args, proxyInit.Args[i])
+	}
+
+	// the proxy's own ports are never redirected, as the CLI does
+	// the ranges of ports are passed as is, proxy-init ignores each of them
+	// with a single rule
+	inboundPorts := append([]string{}, c.IgnoreInboundPorts...)
+	for _, port := range []uint{c.ProxyControlPort, c.ProxyMetricsPort} {
+		if port != 0 {
+			inboundPorts = append(inboundPorts, strconv.FormatUint(uint64(port), 10))
+		}
+	}
+	if len(inboundPorts) > 0 {
+		args = append(args, initArgInboundPortsToIgnore, strings.Join(inboundPorts, ","))
+	}
+	if len(c.IgnoreOutboundPorts) > 0 {
+		args = append(args, initArgOutboundPortsToIgnore, strings.Join(c.IgnoreOutboundPorts, ","))
+	}
+	if len(c.IgnoreInboundSubnets) > 0 {
+		args = append(args, initArgInboundSubnetsToIgnore, strings.Join(c.IgnoreInboundSubnets, ","))
+	}
+	if len(c.IgnoreOutboundSubnets) > 0 {
+		args = append(args, initArgOutboundSubnetsToIgnore, strings.Join(c.IgnoreOutboundSubnets, ","))
+	}
+	if len(c.IgnoreMarks) > 0 {
+		args = append(args, initArgMarksToIgnore, strings.Join(c.IgnoreMarks, ","))
+	}
+	// the configurations persisted by older versions have no mode
+	if c.IptablesMode != "" && c.IptablesMode != iptablesLegacyMode {
+		args = append(args, initArgIptablesMode, c.IptablesMode)
+	}
+	proxyInit.Args = args
+}
+
+// skipServerFirstPorts adds the container ports of the pod whose protocols
+// the proxy can't detect to the inbound ports proxy-init ignores, as linkerd
+// inject does, unless they're ignored already. It returns the added ports.
+func skipServerFirstPorts(proxyInit *corev1.Container, spec *corev1.PodSpec) []uint {
+	valueIndex := -1
+	ignored := []string{}
+	for i := 0; i+1 < len(proxyInit.Args); i++ {
+		if proxyInit.Args[i] == initArgInboundPortsToIgnore {
+			valueIndex = i + 1
+			if value := proxyInit.Args[valueIndex]; value != "" {
+				ignored = strings.Split(value, ",")
+			}
+			break
+		}
+	}
+
+	detected := k8sPkg.DetectServerFirstPorts(spec, func(port uint) bool { return portsInclude(ignored, port) })
+	if len(detected) == 0 {
+		return detected
+	}
+	for _, port := range detected {
+		ignored = append(ignored, strconv.FormatUint(uint64(port), 10))
+	}
+	if valueIndex < 0 {
+		proxyInit.Args = append(proxyInit.Args, initArgInboundPortsToIgnore, strings.Join(ignored, ","))
+	} else {
+		proxyInit.Args[valueIndex] = strings.Join(ignored, ",")
+	}
+	return detected
+}
+
+// portsInclude is true if the port is one of the ports or ranges of ports, as
+// proxy-init takes them.
+func portsInclude(ports []string, port uint) bool {
+	for _, p := range ports {
+		bounds := strings.SplitN(p, "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 16)
+		if err != nil {
+			continue
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 16); err != nil {
+				continue
+			}
+		}
+		if first <= uint64(port) && uint64(port) <= last {
+			return true
+		}
+	}
+	return false
+}
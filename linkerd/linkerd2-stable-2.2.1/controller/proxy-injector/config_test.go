@@ -0,0 +1,241 @@
+package injector
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func linkerdConfig(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: k8sPkg.ConfigConfigMapName, Namespace: fake.DefaultControllerNamespace},
+		Data:       map[string]string{k8sPkg.ConfigProxyKey: data},
+	}
+}
+
+func TestProxyConfigApplyTo(t *testing.T) {
+	var testCases = []struct {
+		config       proxyConfig
+		expectedLog  string
+		expectedArgs []string
+	}{
+		{
+			config:      proxyConfig{ProxyControlPort: 4190, ProxyMetricsPort: 4191},
+			expectedLog: "warn,linkerd2_proxy=info",
+			expectedArgs: []string{
+				"--incoming-proxy-port", "4143", "--outgoing-proxy-port", "4140", "--proxy-uid", "2102",
+				"--inbound-ports-to-ignore", "4190,4191",
+			},
+		},
+		{
+			config: proxyConfig{
+				ProxyLogLevel:       "debug",
+				IgnoreInboundPorts:  []string{"22", "3306"},
+				IgnoreOutboundPorts: []string{"5432", "4000-4100"},
+				ProxyControlPort:    4190,
+				ProxyMetricsPort:    4191,
+			},
+			expectedLog: "debug",
+			expectedArgs: []string{
+				"--incoming-proxy-port", "4143", "--outgoing-proxy-port", "4140", "--proxy-uid", "2102",
+				"--inbound-ports-to-ignore", "22,3306,4190,4191",
+				"--outbound-ports-to-ignore", "5432,4000-4100",
+			},
+		},
+		{
+			config: proxyConfig{
+				IgnoreOutboundSubnets: []string{"10.0.0.0/8", "192.168.0.0/16"},
+				IgnoreMarks:           []string{"0x4000/0x4000"},
+				IptablesMode:          "nft",
+				ProxyControlPort:      4190,
+				ProxyMetricsPort:      4191,
+			},
+			expectedLog: "warn,linkerd2_proxy=info",
+			expectedArgs: []string{
+				"--incoming-proxy-port", "4143", "--outgoing-proxy-port", "4140", "--proxy-uid", "2102",
+				"--inbound-ports-to-ignore", "4190,4191",
+				"--outbound-subnets-to-ignore", "10.0.0.0/8,192.168.0.0/16",
+				"--marks-to-ignore", "0x4000/0x4000",
+				"--iptables-mode", "nft",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		proxy, err := factory.Container("config-proxy.yaml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		proxyInit, err := factory.Container("config-proxy-init.yaml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		testCase.config.applyTo(proxy, proxyInit)
+
+		for _, env := range proxy.Env {
+			if env.Name == envVarKeyProxyLog && env.Value != testCase.expectedLog {
+				t.Fatalf("test case %d: expected log level %q, got %q", i, testCase.expectedLog, env.Value)
+			}
+		}
+		if !reflect.DeepEqual(proxyInit.Args, testCase.expectedArgs) {
+			t.Fatalf("test case %d: expected args %v, got %v", i, testCase.expectedArgs, proxyInit.Args)
+		}
+	}
+}
+
+func TestSkipServerFirstPorts(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name: "db",
+				Ports: []corev1.ContainerPort{
+					{ContainerPort: 5432},
+					{ContainerPort: 6379},
+					{ContainerPort: 8080},
+				},
+			},
+		},
+	}
+
+	var testCases = []struct {
+		args             []string
+		expectedArgs     []string
+		expectedDetected []uint
+	}{
+		{
+			args:             []string{"--proxy-uid", "2102", "--inbound-ports-to-ignore", "4190,4191"},
+			expectedArgs:     []string{"--proxy-uid", "2102", "--inbound-ports-to-ignore", "4190,4191,5432,6379"},
+			expectedDetected: []uint{5432, 6379},
+		},
+		{
+			args:             []string{"--proxy-uid", "2102", "--inbound-ports-to-ignore", "5000-6000,4190"},
+			expectedArgs:     []string{"--proxy-uid", "2102", "--inbound-ports-to-ignore", "5000-6000,4190,6379"},
+			expectedDetected: []uint{6379},
+		},
+		{
+			args:             []string{"--proxy-uid", "2102"},
+			expectedArgs:     []string{"--proxy-uid", "2102", "--inbound-ports-to-ignore", "5432,6379"},
+			expectedDetected: []uint{5432, 6379},
+		},
+		{
+			args:             []string{"--inbound-ports-to-ignore", "5432,6379"},
+			expectedArgs:     []string{"--inbound-ports-to-ignore", "5432,6379"},
+			expectedDetected: []uint{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		proxyInit := &corev1.Container{Args: testCase.args}
+		detected := skipServerFirstPorts(proxyInit, spec)
+		if !reflect.DeepEqual(detected, testCase.expectedDetected) {
+			t.Fatalf("test case %d: expected ports %v, got %v", i, testCase.expectedDetected, detected)
+		}
+		if !reflect.DeepEqual(proxyInit.Args, testCase.expectedArgs) {
+			t.Fatalf("test case %d: expected args %v, got %v", i, testCase.expectedArgs, proxyInit.Args)
+		}
+	}
+}
+
+func TestWebhookProxyConfig(t *testing.T) {
+	var testCases = []struct {
+		objects  []runtime.Object
+		expected *proxyConfig
+	}{
+		{
+			objects:  nil,
+			expected: nil,
+		},
+		{
+			objects:  []runtime.Object{linkerdConfig("not json")},
+			expected: nil,
+		},
+		{
+			objects: []runtime.Object{linkerdConfig(`{"proxyLogLevel":"debug","ignoreInboundPorts":["22"],"ignoreOutboundPorts":[],"proxyControlPort":4190,"proxyMetricsPort":4191,"proxyAwait":true,"proxyJobShutdown":false,"proxyUID":2102}`)},
+			expected: &proxyConfig{
+				ProxyLogLevel:       "debug",
+				IgnoreInboundPorts:  []string{"22"},
+				IgnoreOutboundPorts: []string{},
+				ProxyControlPort:    4190,
+				ProxyMetricsPort:    4191,
+				ProxyAwait:          true,
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		webhook, err := NewWebhook(fake.NewClient("", testCase.objects...), testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if actual := webhook.proxyConfig(); !reflect.DeepEqual(actual, testCase.expected) {
+			t.Fatalf("test case %d: expected %+v, got %+v", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestInjectWithProxyConfig(t *testing.T) {
+	ns, err := factory.Namespace("namespace-inject-enabled.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	config := linkerdConfig(`{"proxyLogLevel":"debug","ignoreInboundPorts":[],"ignoreOutboundPorts":[],"proxyControlPort":4190,"proxyMetricsPort":4191,"proxyAwait":true}`)
+
+	webhook, err := NewWebhook(fake.NewClient("", ns, config), testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	b, err := factory.HTTPRequestBody("deployment-inject-empty.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	raw, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	response, err := webhook.inject(&admissionv1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Kind: "Deployment"},
+		Namespace: ns.GetName(),
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var ops []struct {
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(response.Patch, &ops); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var proxy *corev1.Container
+	for _, op := range ops {
+		if op.Path == patchPathPodTemplate+patchPathFirstContainer {
+			proxy = &corev1.Container{}
+			if err := json.Unmarshal(op.Value, proxy); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+	}
+	if proxy == nil {
+		t.Fatalf("Expected the proxy to be added as the first container, got patch %s", response.Patch)
+	}
+	for _, env := range proxy.Env {
+		if env.Name == envVarKeyProxyLog && env.Value != "debug" {
+			t.Fatalf("Expected the log level of the configuration, got %q", env.Value)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createdByInjector is the prefix of the CreatedByAnnotation of the pods
+// injected by the webhook.
+const createdByInjector = "linkerd/proxy-injector"
+
+// Decision is the outcome of deciding whether or not a pod is injected.
+type Decision struct {
+	// Inject is true if the pod is, or should be, injected.
+	Inject bool `json:"inject"`
+
+	// Reason explains the decision.
+	Reason string `json:"reason"`
+}
+
+// Explain returns the decision the webhook made for an existing pod. Pods are
+// evaluated against their namespace as it is now, so the decision may differ
+// from the one made when the pod was created, if the namespace changed since.
+func (w *Webhook) Explain(ns, name string) (*Decision, error) {
+	pod, err := w.client.CoreV1().Pods(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if createdBy := pod.GetAnnotations()[k8sPkg.CreatedByAnnotation]; strings.HasPrefix(createdBy, createdByInjector) {
+		return &Decision{Inject: true, Reason: fmt.Sprintf("pod was injected by %s", createdBy)}, nil
+	}
+
+	namespace, err := w.client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if namespace.GetLabels()[k8sPkg.ProxyInjectLabel] == k8sPkg.ProxyInjectDisabled {
+		return &Decision{Inject: false, Reason: fmt.Sprintf("namespace %s has the %s label set to %q, so its pods aren't sent to the webhook", ns, k8sPkg.ProxyInjectLabel, k8sPkg.ProxyInjectDisabled)}, nil
+	}
+
+	decision, err := w.shouldInject(ns, &corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec})
+	if err != nil {
+		return nil, err
+	}
+	if decision.Inject {
+		// the pod should have been injected, but wasn't
+		decision.Inject = false
+		decision.Reason = fmt.Sprintf("%s, but the pod wasn't injected; it may predate the webhook or its namespace's settings, or have been created while the webhook was unavailable", decision.Reason)
+	}
+
+	return decision, nil
+}
+
+// ServeExplain serves the decision for the pod given by the namespace and pod
+// query parameters, as JSON.
+func (w *Webhook) ServeExplain(res http.ResponseWriter, req *http.Request) {
+	ns := req.URL.Query().Get("namespace")
+	if ns == "" {
+		ns = corev1.NamespaceDefault
+	}
+	name := req.URL.Query().Get("pod")
+	if name == "" {
+		http.Error(res, "the pod query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	decision, err := w.Explain(ns, name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(res, err.Error(), status)
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(decision); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,122 @@
+package injector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExplain(t *testing.T) {
+	nsEnabled, err := factory.Namespace("namespace-inject-enabled.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	nsExcluded := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "excluded",
+			Labels: map[string]string{k8sPkg.ProxyInjectLabel: k8sPkg.ProxyInjectDisabled},
+		},
+	}
+
+	pod := func(ns, name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+	}
+
+	fakeClient := fake.NewClient("",
+		nsEnabled,
+		nsExcluded,
+		pod(nsEnabled.GetName(), "injected", map[string]string{k8sPkg.CreatedByAnnotation: "linkerd/proxy-injector stable-2.2.1"}),
+		pod(nsEnabled.GetName(), "missed", nil),
+		pod(nsEnabled.GetName(), "opted-out", map[string]string{k8sPkg.ProxyInjectAnnotation: k8sPkg.ProxyInjectDisabled}),
+		pod(nsExcluded.GetName(), "excluded", map[string]string{k8sPkg.ProxyInjectAnnotation: k8sPkg.ProxyInjectEnabled}),
+	)
+
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	var testCases = []struct {
+		ns       string
+		pod      string
+		expected Decision
+	}{
+		{
+			ns:  nsEnabled.GetName(),
+			pod: "injected",
+			expected: Decision{
+				Inject: true,
+				Reason: "pod was injected by linkerd/proxy-injector stable-2.2.1",
+			},
+		},
+		{
+			ns:  nsEnabled.GetName(),
+			pod: "missed",
+			expected: Decision{
+				Inject: false,
+				Reason: `namespace kube-public has linkerd.io/inject set to "enabled", but the pod wasn't injected; it may predate the webhook or its namespace's settings, or have been created while the webhook was unavailable`,
+			},
+		},
+		{
+			ns:  nsEnabled.GetName(),
+			pod: "opted-out",
+			expected: Decision{
+				Inject: false,
+				Reason: `pod has the linkerd.io/inject annotation set to "disabled"`,
+			},
+		},
+		{
+			ns:  nsExcluded.GetName(),
+			pod: "excluded",
+			expected: Decision{
+				Inject: false,
+				Reason: `namespace excluded has the linkerd.io/inject label set to "disabled", so its pods aren't sent to the webhook`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.pod, func(t *testing.T) {
+			decision, err := webhook.Explain(testCase.ns, testCase.pod)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			if *decision != testCase.expected {
+				t.Fatalf("Expected decision %+v, got %+v", testCase.expected, *decision)
+			}
+		})
+	}
+
+	t.Run("serves the decision as JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		webhook.ServeExplain(rec, httptest.NewRequest(http.MethodGet, "/explain?namespace=kube-public&pod=injected", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var decision Decision
+		if err := json.Unmarshal(rec.Body.Bytes(), &decision); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if !decision.Inject {
+			t.Fatalf("Expected the pod to be injected, got %+v", decision)
+		}
+	})
+
+	t.Run("returns not found for unknown pods", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		webhook.ServeExplain(rec, httptest.NewRequest(http.MethodGet, "/explain?namespace=kube-public&pod=unknown", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
@@ -21,6 +21,7 @@ const (
 	FileTLSIdentityVolumeSpec    = "fake/data/config-linkerd-secrets.yaml"
 	DefaultNoInitContainer       = false
 	DefaultTLSEnabled            = true
+	DefaultFailurePolicy         = "Ignore"
 )
 
 // Factory is a factory that can convert in-file YAML content into Kubernetes
@@ -78,6 +79,25 @@ func (f *Factory) Deployment(filename string) (*appsv1.Deployment, error) {
 	return &deployment, nil
 }
 
+// ReplicaSet returns the content of the specified file as a ReplicaSet type. An
+// error will be returned if:
+// i. the file doesn't exist in the 'fake/data' folder or
+// ii. the file content isn't a valid YAML structure that can be unmarshalled
+// into ReplicaSet type
+func (f *Factory) ReplicaSet(filename string) (*appsv1.ReplicaSet, error) {
+	b, err := ioutil.ReadFile(filepath.Join(f.rootDir, filename))
+	if err != nil {
+		return nil, err
+	}
+
+	var rs appsv1.ReplicaSet
+	if err := yaml.Unmarshal(b, &rs); err != nil {
+		return nil, err
+	}
+
+	return &rs, nil
+}
+
 // Container returns the content of the specified file as a Container type. An
 // error will be returned if:
 // i. the file doesn't exist in the 'fake/data' folder or
@@ -0,0 +1,34 @@
+package injector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultInjected = "injected"
+	resultSkipped  = "skipped"
+	resultFailed   = "failed"
+)
+
+var (
+	admissionRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "proxy_inject_admission_requests_total",
+			Help: "A counter for admission requests to the proxy injector, by namespace, kind and result (injected, skipped or failed).",
+		},
+		[]string{"namespace", "kind", "result"},
+	)
+
+	patchDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "proxy_inject_patch_duration_seconds",
+			Help:    "A histogram of the time taken to generate the patch for an admission request, in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 8),
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(admissionRequests, patchDuration)
+}
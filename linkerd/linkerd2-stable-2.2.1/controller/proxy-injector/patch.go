@@ -4,41 +4,74 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// The pod paths below are relative to the pod, or pod template, being patched.
 const (
-	patchPathContainer         = "/spec/template/spec/containers/-"
-	patchPathInitContainerRoot = "/spec/template/spec/initContainers"
-	patchPathInitContainer     = "/spec/template/spec/initContainers/-"
-	patchPathVolumeRoot        = "/spec/template/spec/volumes"
-	patchPathVolume            = "/spec/template/spec/volumes/-"
-	patchPathDeploymentLabels  = "/metadata/labels"
-	patchPathPodLabels         = "/spec/template/metadata/labels"
-	patchPathPodAnnotations    = "/spec/template/metadata/annotations"
+	patchPathPodTemplate        = "/spec/template"
+	patchPathCronJobPodTemplate = "/spec/jobTemplate/spec/template"
+
+	patchPathContainer         = "/spec/containers/-"
+	patchPathFirstContainer    = "/spec/containers/0"
+	patchPathInitContainerRoot = "/spec/initContainers"
+	patchPathInitContainer     = "/spec/initContainers/-"
+	patchPathVolumeRoot        = "/spec/volumes"
+	patchPathVolume            = "/spec/volumes/-"
+	patchPathPodLabels         = "/metadata/labels"
+	patchPathPodAnnotations    = "/metadata/annotations"
+	patchPathShareProcessNS    = "/spec/shareProcessNamespace"
+
+	patchPathWorkloadLabels = "/metadata/labels"
 )
 
 // Patch represents a RFC 6902 patch document.
 type Patch struct {
 	patchOps []*patchOp
+
+	// podPath is the path of the pod, or pod template, being patched. It's
+	// empty when the patched object is a pod.
+	podPath string
 }
 
-// NewPatch returns a new instance of PodPatch.
-func NewPatch() *Patch {
+// NewPatch returns a new instance of Patch, which patches the pod found at
+// podPath.
+func NewPatch(podPath string) *Patch {
 	return &Patch{
 		patchOps: []*patchOp{},
+		podPath:  podPath,
 	}
 }
 
 func (p *Patch) addContainer(container *corev1.Container) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathContainer,
+		Path:  p.podPath + patchPathContainer,
+		Value: container,
+	})
+}
+
+// addFirstContainer inserts the container before the other containers of the
+// pod.
+func (p *Patch) addFirstContainer(container *corev1.Container) {
+	p.patchOps = append(p.patchOps, &patchOp{
+		Op:    "add",
+		Path:  p.podPath + patchPathFirstContainer,
 		Value: container,
 	})
 }
 
+// addShareProcessNamespace makes the containers of the pod share their process
+// namespace.
+func (p *Patch) addShareProcessNamespace() {
+	p.patchOps = append(p.patchOps, &patchOp{
+		Op:    "add",
+		Path:  p.podPath + patchPathShareProcessNS,
+		Value: true,
+	})
+}
+
 func (p *Patch) addInitContainerRoot() {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathInitContainerRoot,
+		Path:  p.podPath + patchPathInitContainerRoot,
 		Value: []*corev1.Container{},
 	})
 }
@@ -46,7 +79,7 @@ func (p *Patch) addInitContainerRoot() {
 func (p *Patch) addInitContainer(container *corev1.Container) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathInitContainer,
+		Path:  p.podPath + patchPathInitContainer,
 		Value: container,
 	})
 }
@@ -54,7 +87,7 @@ func (p *Patch) addInitContainer(container *corev1.Container) {
 func (p *Patch) addVolumeRoot() {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathVolumeRoot,
+		Path:  p.podPath + patchPathVolumeRoot,
 		Value: []*corev1.Volume{},
 	})
 }
@@ -62,7 +95,7 @@ func (p *Patch) addVolumeRoot() {
 func (p *Patch) addVolume(volume *corev1.Volume) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathVolume,
+		Path:  p.podPath + patchPathVolume,
 		Value: volume,
 	})
 }
@@ -70,7 +103,7 @@ func (p *Patch) addVolume(volume *corev1.Volume) {
 func (p *Patch) addPodLabels(label map[string]string) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathPodLabels,
+		Path:  p.podPath + patchPathPodLabels,
 		Value: label,
 	})
 }
@@ -78,15 +111,15 @@ func (p *Patch) addPodLabels(label map[string]string) {
 func (p *Patch) addPodAnnotations(annotation map[string]string) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathPodAnnotations,
+		Path:  p.podPath + patchPathPodAnnotations,
 		Value: annotation,
 	})
 }
 
-func (p *Patch) addDeploymentLabels(label map[string]string) {
+func (p *Patch) addWorkloadLabels(label map[string]string) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathDeploymentLabels,
+		Path:  patchPathWorkloadLabels,
 		Value: label,
 	})
 }
@@ -37,7 +37,7 @@ func TestPatch(t *testing.T) {
 		createdBy           = "linkerd/cli v18.8.4"
 	)
 
-	actual := NewPatch()
+	actual := NewPatch(patchPathPodTemplate)
 	actual.addContainer(sidecar)
 	actual.addInitContainerRoot()
 	actual.addInitContainer(init)
@@ -47,28 +47,28 @@ func TestPatch(t *testing.T) {
 	actual.addPodLabels(map[string]string{
 		k8sPkg.ControllerNSLabel: controllerNamespace,
 	})
-	actual.addDeploymentLabels(map[string]string{
+	actual.addWorkloadLabels(map[string]string{
 		k8sPkg.ControllerNSLabel: controllerNamespace,
 	})
 	actual.addPodAnnotations(map[string]string{
 		k8sPkg.CreatedByAnnotation: createdBy,
 	})
 
-	expected := NewPatch()
+	expected := NewPatch(patchPathPodTemplate)
 	expected.patchOps = []*patchOp{
-		&patchOp{Op: "add", Path: patchPathContainer, Value: sidecar},
-		&patchOp{Op: "add", Path: patchPathInitContainerRoot, Value: []*v1.Container{}},
-		&patchOp{Op: "add", Path: patchPathInitContainer, Value: init},
-		&patchOp{Op: "add", Path: patchPathVolumeRoot, Value: []*v1.Volume{}},
-		&patchOp{Op: "add", Path: patchPathVolume, Value: trustAnchors},
-		&patchOp{Op: "add", Path: patchPathVolume, Value: secrets},
-		&patchOp{Op: "add", Path: patchPathPodLabels, Value: map[string]string{
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathContainer, Value: sidecar},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathInitContainerRoot, Value: []*v1.Container{}},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathInitContainer, Value: init},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathVolumeRoot, Value: []*v1.Volume{}},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathVolume, Value: trustAnchors},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathVolume, Value: secrets},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathPodLabels, Value: map[string]string{
 			k8sPkg.ControllerNSLabel: controllerNamespace,
 		}},
-		&patchOp{Op: "add", Path: patchPathDeploymentLabels, Value: map[string]string{
+		&patchOp{Op: "add", Path: patchPathWorkloadLabels, Value: map[string]string{
 			k8sPkg.ControllerNSLabel: controllerNamespace,
 		}},
-		&patchOp{Op: "add", Path: patchPathPodAnnotations, Value: map[string]string{k8sPkg.CreatedByAnnotation: createdBy}},
+		&patchOp{Op: "add", Path: patchPathPodTemplate + patchPathPodAnnotations, Value: map[string]string{k8sPkg.CreatedByAnnotation: createdBy}},
 	}
 
 	if !reflect.DeepEqual(actual, expected) {
@@ -7,9 +7,8 @@ import (
 	"io/ioutil"
 	"net/http"
 
-	"github.com/linkerd/linkerd2/pkg/k8s"
-	pkgTls "github.com/linkerd/linkerd2/pkg/tls"
-	log "github.com/sirupsen/logrus"
+	"github.com/linkerd/linkerd2/controller/webhook"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -21,18 +20,17 @@ type WebhookServer struct {
 }
 
 // NewWebhookServer returns a new instance of the WebhookServer.
-func NewWebhookServer(client kubernetes.Interface, resources *WebhookResources, addr, controllerNamespace string, noInitContainer, tlsEnabled bool, rootCA *pkgTls.CA) (*WebhookServer, error) {
-	c, err := tlsConfig(rootCA, controllerNamespace)
-	if err != nil {
-		return nil, err
-	}
-
+// The server's certificate is served by certs, so that it can be rotated
+// without restarting the server.
+func NewWebhookServer(client kubernetes.Interface, resources *WebhookResources, addr, controllerNamespace string, noInitContainer, tlsEnabled bool, failurePolicy arv1beta1.FailurePolicyType, certs *webhook.CertRotator) (*WebhookServer, error) {
 	server := &http.Server{
-		Addr:      addr,
-		TLSConfig: c,
+		Addr: addr,
+		TLSConfig: &tls.Config{
+			GetCertificate: certs.GetCertificate,
+		},
 	}
 
-	webhook, err := NewWebhook(client, resources, controllerNamespace, noInitContainer, tlsEnabled)
+	webhook, err := NewWebhook(client, resources, controllerNamespace, noInitContainer, tlsEnabled, failurePolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -76,34 +74,3 @@ func (w *WebhookServer) serve(res http.ResponseWriter, req *http.Request) {
 func (w *WebhookServer) Shutdown() error {
 	return w.Server.Shutdown(context.Background())
 }
-
-func tlsConfig(rootCA *pkgTls.CA, controllerNamespace string) (*tls.Config, error) {
-	tlsIdentity := k8s.TLSIdentity{
-		Name:                "linkerd-proxy-injector",
-		Kind:                k8s.Service,
-		Namespace:           controllerNamespace,
-		ControllerNamespace: controllerNamespace,
-	}
-	dnsName := tlsIdentity.ToDNSName()
-	certAndPrivateKey, err := rootCA.IssueEndEntityCertificate(dnsName)
-
-	certPEM, err := certAndPrivateKey.EncodedCertificate()
-	if err != nil {
-		return nil, err
-	}
-	log.Debugf("PEM-encoded certificate: %s\n", certPEM)
-
-	keyPEM, err := certAndPrivateKey.EncodedPrivateKey()
-	if err != nil {
-		return nil, err
-	}
-
-	cert, err := tls.X509KeyPair(certPEM, keyPEM)
-	if err != nil {
-		return nil, err
-	}
-
-	return &tls.Config{
-		Certificates: []tls.Certificate{cert},
-	}, nil
-}
@@ -10,7 +10,8 @@ import (
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
-	"github.com/linkerd/linkerd2/pkg/tls"
+	"github.com/linkerd/linkerd2/controller/webhook"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,7 +30,7 @@ func init() {
 		FileTLSTrustAnchorVolumeSpec: fake.FileTLSTrustAnchorVolumeSpec,
 		FileTLSIdentityVolumeSpec:    fake.FileTLSIdentityVolumeSpec,
 	}
-	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, false, true)
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, false, true, fake.DefaultFailurePolicy)
 	if err != nil {
 		panic(err)
 	}
@@ -76,18 +77,19 @@ func TestShutdown(t *testing.T) {
 }
 
 func TestNewWebhookServer(t *testing.T) {
-	rootCA, err := tls.NewCA()
-	if err != nil {
-		log.Fatalf("failed to create root CA: %s", err)
-	}
-
 	var (
 		addr       = ":7070"
 		kubeconfig = ""
 	)
 	fakeClient := fake.NewClient(kubeconfig)
 
-	server, err := NewWebhookServer(fakeClient, testWebhookResources, addr, fake.DefaultControllerNamespace, false, true, rootCA)
+	webhookConfig, err := NewWebhookConfig(fakeClient, fake.DefaultControllerNamespace, "test.linkerd.io", WebhookPolicy{FailurePolicy: fake.DefaultFailurePolicy})
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	certs := webhook.NewCertRotator(fakeClient, fake.DefaultControllerNamespace, "linkerd-proxy-injector", k8sPkg.ProxyInjectorTLSSecret, webhookConfig.Publish)
+
+	server, err := NewWebhookServer(fakeClient, testWebhookResources, addr, fake.DefaultControllerNamespace, false, true, fake.DefaultFailurePolicy, certs)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -0,0 +1,228 @@
+package injector
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// sidecarTemplate holds the specs the webhook patches pods with, as parsed
+// from the files of the sidecar ConfigMap.
+type sidecarTemplate struct {
+	proxy        corev1.Container
+	proxyInit    corev1.Container
+	trustAnchors corev1.Volume
+	identity     corev1.Volume
+	extraVolumes []corev1.Volume
+}
+
+// sidecarLoader loads the sidecar template from the files of the sidecar
+// ConfigMap mounted into the webhook. The files are re-read on every request,
+// so that edits to the ConfigMap are picked up without restarting the webhook,
+// and only parsed again when they change. A template that doesn't validate is
+// rejected, and the last valid one is kept in use.
+type sidecarLoader struct {
+	resources *WebhookResources
+
+	sync.Mutex
+	raw     [][]byte
+	current *sidecarTemplate
+}
+
+func newSidecarLoader(resources *WebhookResources) *sidecarLoader {
+	return &sidecarLoader{resources: resources}
+}
+
+// load returns the current sidecar template. It only fails if no valid
+// template was ever loaded.
+func (l *sidecarLoader) load() (*sidecarTemplate, error) {
+	raw, err := l.read()
+	if err != nil {
+		return l.fallback(err)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if l.current != nil && equalFiles(raw, l.raw) {
+		return l.current, nil
+	}
+
+	sidecar, err := parseSidecarTemplate(raw)
+	if err != nil {
+		if l.current == nil {
+			return nil, err
+		}
+		log.Errorf("ignoring invalid sidecar template, keeping the last valid one: %s", err)
+		return l.current, nil
+	}
+
+	if l.current != nil {
+		log.Info("reloaded the sidecar template")
+	}
+	l.raw = raw
+	l.current = sidecar
+	return sidecar, nil
+}
+
+func (l *sidecarLoader) fallback(err error) (*sidecarTemplate, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.current == nil {
+		return nil, err
+	}
+	log.Errorf("failed to read the sidecar template, keeping the last valid one: %s", err)
+	return l.current, nil
+}
+
+// read returns the content of the proxy, proxy-init, trust anchors volume,
+// identity volume and extra volumes spec files, in that order. The extra
+// volumes file is optional.
+func (l *sidecarLoader) read() ([][]byte, error) {
+	files := []string{
+		l.resources.FileProxySpec,
+		l.resources.FileProxyInitSpec,
+		l.resources.FileTLSTrustAnchorVolumeSpec,
+		l.resources.FileTLSIdentityVolumeSpec,
+	}
+
+	raw := make([][]byte, 0, len(files)+1)
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, b)
+	}
+
+	var extraVolumes []byte
+	if l.resources.FileExtraVolumesSpec != "" {
+		b, err := ioutil.ReadFile(l.resources.FileExtraVolumesSpec)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		extraVolumes = b
+	}
+
+	return append(raw, extraVolumes), nil
+}
+
+func equalFiles(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSidecarTemplate parses the files returned by sidecarLoader.read, and
+// validates the result.
+func parseSidecarTemplate(raw [][]byte) (*sidecarTemplate, error) {
+	var sidecar sidecarTemplate
+	if err := yaml.UnmarshalStrict(raw[0], &sidecar.proxy); err != nil {
+		return nil, fmt.Errorf("invalid proxy spec: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(raw[1], &sidecar.proxyInit); err != nil {
+		return nil, fmt.Errorf("invalid proxy-init spec: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(raw[2], &sidecar.trustAnchors); err != nil {
+		return nil, fmt.Errorf("invalid trust anchors volume spec: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(raw[3], &sidecar.identity); err != nil {
+		return nil, fmt.Errorf("invalid identity volume spec: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(raw[4], &sidecar.extraVolumes); err != nil {
+		return nil, fmt.Errorf("invalid extra volumes spec: %s", err)
+	}
+
+	if err := sidecar.validate(); err != nil {
+		return nil, err
+	}
+	return &sidecar, nil
+}
+
+// validate checks that the containers have the names and images the rest of
+// Linkerd expects, and that every volume the proxy mounts is added to the pod.
+func (s *sidecarTemplate) validate() error {
+	if s.proxy.Name != k8sPkg.ProxyContainerName {
+		return fmt.Errorf("the proxy container must be named %s, not %q", k8sPkg.ProxyContainerName, s.proxy.Name)
+	}
+	if s.proxy.Image == "" {
+		return fmt.Errorf("the proxy container has no image")
+	}
+	if s.proxyInit.Name != k8sPkg.InitContainerName {
+		return fmt.Errorf("the proxy-init container must be named %s, not %q", k8sPkg.InitContainerName, s.proxyInit.Name)
+	}
+	if s.proxyInit.Image == "" {
+		return fmt.Errorf("the proxy-init container has no image")
+	}
+	if s.identity.Secret == nil {
+		return fmt.Errorf("the identity volume must be a secret volume")
+	}
+
+	volumes := map[string]struct{}{}
+	for _, volume := range append([]corev1.Volume{s.trustAnchors, s.identity}, s.extraVolumes...) {
+		if volume.Name == "" {
+			return fmt.Errorf("volumes must have a name")
+		}
+		if _, ok := volumes[volume.Name]; ok {
+			return fmt.Errorf("multiple volumes are named %s", volume.Name)
+		}
+		volumes[volume.Name] = struct{}{}
+	}
+
+	for _, mount := range s.proxy.VolumeMounts {
+		if _, ok := volumes[mount.Name]; !ok {
+			return fmt.Errorf("the proxy container mounts the unknown volume %s", mount.Name)
+		}
+	}
+
+	return nil
+}
+
+// containersSpec returns copies of the proxy and proxy-init containers, with
+// the proxy's identity set.
+func (s *sidecarTemplate) containersSpec(identity *k8sPkg.TLSIdentity) (*corev1.Container, *corev1.Container) {
+	proxy := s.proxy.DeepCopy()
+	for index, env := range proxy.Env {
+		if env.Name == envVarKeyProxyTLSPodIdentity {
+			proxy.Env[index].Value = identity.ToDNSName()
+		} else if env.Name == envVarKeyProxyTLSControllerIdentity {
+			proxy.Env[index].Value = identity.ToControllerIdentity().ToDNSName()
+		} else if env.Name == envVarKeyProxyID {
+			proxy.Env[index].Value = identity.ToDNSName()
+		}
+	}
+
+	return proxy, s.proxyInit.DeepCopy()
+}
+
+// volumesSpec returns copies of the trust anchors and identity volumes, with
+// the identity volume pointing at the secret of the given identity.
+func (s *sidecarTemplate) volumesSpec(identity *k8sPkg.TLSIdentity) (*corev1.Volume, *corev1.Volume) {
+	linkerdSecrets := s.identity.DeepCopy()
+	linkerdSecrets.VolumeSource.Secret.SecretName = identity.ToSecretName()
+
+	return s.trustAnchors.DeepCopy(), linkerdSecrets
+}
+
+// extraVolumesSpec returns copies of the extra volumes.
+func (s *sidecarTemplate) extraVolumesSpec() []*corev1.Volume {
+	volumes := make([]*corev1.Volume, 0, len(s.extraVolumes))
+	for i := range s.extraVolumes {
+		volumes = append(volumes, s.extraVolumes[i].DeepCopy())
+	}
+	return volumes
+}
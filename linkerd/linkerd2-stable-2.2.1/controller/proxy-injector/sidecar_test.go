@@ -0,0 +1,119 @@
+package injector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+)
+
+func TestSidecarLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sidecar")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// copy the fixtures, so that they can be edited like a mounted ConfigMap
+	resources := &WebhookResources{
+		FileExtraVolumesSpec: filepath.Join(dir, "extra-volumes.yaml"),
+	}
+	for src, dst := range map[string]*string{
+		fake.FileProxySpec:                &resources.FileProxySpec,
+		fake.FileProxyInitSpec:            &resources.FileProxyInitSpec,
+		fake.FileTLSTrustAnchorVolumeSpec: &resources.FileTLSTrustAnchorVolumeSpec,
+		fake.FileTLSIdentityVolumeSpec:    &resources.FileTLSIdentityVolumeSpec,
+	} {
+		b, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		*dst = filepath.Join(dir, filepath.Base(src))
+		if err := ioutil.WriteFile(*dst, b, 0644); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+	}
+	proxySpec, err := ioutil.ReadFile(resources.FileProxySpec)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	loader := newSidecarLoader(resources)
+
+	t.Run("loads the template without extra volumes", func(t *testing.T) {
+		sidecar, err := loader.load()
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if len(sidecar.extraVolumesSpec()) != 0 {
+			t.Fatalf("Expected no extra volumes, got %+v", sidecar.extraVolumesSpec())
+		}
+	})
+
+	t.Run("reloads the template when it changes", func(t *testing.T) {
+		extraVolumes := `
+- name: custom-ca
+  configMap:
+    name: custom-ca`
+		writeFile(t, resources.FileExtraVolumesSpec, extraVolumes)
+		writeFile(t, resources.FileProxySpec, strings.Replace(string(proxySpec), "volumeMounts:", `volumeMounts:
+- mountPath: /etc/custom-ca
+  name: custom-ca
+  readOnly: true`, 1))
+
+		sidecar, err := loader.load()
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if volumes := sidecar.extraVolumesSpec(); len(volumes) != 1 || volumes[0].Name != "custom-ca" {
+			t.Fatalf("Expected the custom-ca volume, got %+v", volumes)
+		}
+		if mounts := sidecar.proxy.VolumeMounts; len(mounts) != 3 || mounts[0].Name != "custom-ca" {
+			t.Fatalf("Expected the custom-ca volume to be mounted, got %+v", mounts)
+		}
+	})
+
+	t.Run("keeps the last valid template", func(t *testing.T) {
+		writeFile(t, resources.FileExtraVolumesSpec, "")
+
+		sidecar, err := loader.load()
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if volumes := sidecar.extraVolumesSpec(); len(volumes) != 1 {
+			t.Fatalf("Expected the template mounting an unknown volume to be ignored, got %+v", volumes)
+		}
+	})
+
+	t.Run("rejects invalid templates", func(t *testing.T) {
+		testCases := []struct {
+			proxySpec string
+			err       string
+		}{
+			{
+				proxySpec: strings.Replace(string(proxySpec), "name: linkerd-proxy\n", "name: sidecar\n", 1),
+				err:       `the proxy container must be named linkerd-proxy, not "sidecar"`,
+			},
+			{
+				proxySpec: string(proxySpec) + "imagePullPolicyy: Always\n",
+				err:       `invalid proxy spec: error unmarshaling JSON: while decoding JSON: json: unknown field "imagePullPolicyy"`,
+			},
+		}
+
+		for _, testCase := range testCases {
+			writeFile(t, resources.FileProxySpec, testCase.proxySpec)
+			if _, err := newSidecarLoader(resources).load(); err == nil || err.Error() != testCase.err {
+				t.Errorf("Expected error [%s], got [%v]", testCase.err, err)
+			}
+		}
+	})
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+}
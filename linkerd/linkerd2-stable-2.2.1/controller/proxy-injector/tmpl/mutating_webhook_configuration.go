@@ -15,8 +15,34 @@ webhooks:
       namespace: {{ .ControllerNamespace }}
       path: "/"
     caBundle: {{ .CABundle }}
+  failurePolicy: {{ .FailurePolicy }}
+  sideEffects: NoneOnDryRun
+  {{- if .TimeoutSeconds }}
+  timeoutSeconds: {{ .TimeoutSeconds }}
+  {{- end }}
+  {{- if .ReinvocationPolicy }}
+  reinvocationPolicy: {{ .ReinvocationPolicy }}
+  {{- end }}
+  namespaceSelector:
+    matchExpressions:
+    - key: {{ .ProxyInjectLabel }}
+      operator: NotIn
+      values:
+      - {{ .ProxyInjectDisabled }}
   rules:
   - operations: [ "CREATE" ]
     apiGroups: ["apps", "extensions"]
     apiVersions: ["v1", "v1beta1", "v1beta2"]
-    resources: ["deployments"]`
+    resources: ["deployments", "statefulsets", "daemonsets", "replicasets"]
+  - operations: [ "CREATE" ]
+    apiGroups: ["batch"]
+    apiVersions: ["v1"]
+    resources: ["jobs"]
+  - operations: [ "CREATE" ]
+    apiGroups: ["batch"]
+    apiVersions: ["v1beta1"]
+    resources: ["cronjobs"]
+  - operations: [ "CREATE" ]
+    apiGroups: [""]
+    apiVersions: ["v1"]
+    resources: ["pods", "replicationcontrollers"]`
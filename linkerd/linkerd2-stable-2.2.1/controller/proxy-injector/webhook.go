@@ -3,14 +3,14 @@ package injector
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
-	appsv1 "k8s.io/api/apps/v1"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,13 +32,16 @@ type Webhook struct {
 	client              kubernetes.Interface
 	deserializer        runtime.Decoder
 	controllerNamespace string
-	resources           *WebhookResources
+	sidecar             *sidecarLoader
 	noInitContainer     bool
 	tlsEnabled          bool
+	failurePolicy       arv1beta1.FailurePolicyType
 }
 
-// NewWebhook returns a new instance of Webhook.
-func NewWebhook(client kubernetes.Interface, resources *WebhookResources, controllerNamespace string, noInitContainer, tlsEnabled bool) (*Webhook, error) {
+// NewWebhook returns a new instance of Webhook. failurePolicy is the failure
+// policy the webhook is registered with; it also decides whether a request
+// the webhook fails to inject is admitted or rejected.
+func NewWebhook(client kubernetes.Interface, resources *WebhookResources, controllerNamespace string, noInitContainer, tlsEnabled bool, failurePolicy arv1beta1.FailurePolicyType) (*Webhook, error) {
 	var (
 		scheme = runtime.NewScheme()
 		codecs = serializer.NewCodecFactory(scheme)
@@ -48,9 +51,10 @@ func NewWebhook(client kubernetes.Interface, resources *WebhookResources, contro
 		client:              client,
 		deserializer:        codecs.UniversalDeserializer(),
 		controllerNamespace: controllerNamespace,
-		resources:           resources,
+		sidecar:             newSidecarLoader(resources),
 		noInitContainer:     noInitContainer,
 		tlsEnabled:          tlsEnabled,
+		failurePolicy:       failurePolicy,
 	}, nil
 }
 
@@ -73,9 +77,41 @@ func (w *Webhook) Mutate(data []byte) *admissionv1beta1.AdmissionReview {
 	log.Infof("received admission review request %s", admissionReview.Request.UID)
 	log.Debugf("admission request: %+v", admissionReview.Request)
 
+	var (
+		ns     = requestNamespace(admissionReview.Request)
+		kind   = strings.ToLower(admissionReview.Request.Kind.Kind)
+		dryRun = admissionReview.Request.DryRun != nil && *admissionReview.Request.DryRun
+	)
+
+	// Dry runs must not have side effects, so they're left out of the
+	// metrics. They're otherwise handled like any other request, so that the
+	// patch previews what would be injected.
+	record := func(result string, duration time.Duration) {
+		if dryRun {
+			log.Infof("not recording dry run request %s", admissionReview.Request.UID)
+			return
+		}
+		patchDuration.WithLabelValues(kind).Observe(duration.Seconds())
+		admissionRequests.WithLabelValues(ns, kind, result).Inc()
+	}
+
+	start := time.Now()
 	admissionResponse, err := w.inject(admissionReview.Request)
+	duration := time.Since(start)
 	if err != nil {
 		log.Error("failed to inject sidecar. Reason: ", err)
+		record(resultFailed, duration)
+
+		// Failing to inject is handled like failing to reach the webhook, so
+		// that the failure policy applies to both.
+		if w.failurePolicy == arv1beta1.Ignore {
+			admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+				UID:     admissionReview.Request.UID,
+				Allowed: true,
+			}
+			return admissionReview
+		}
+
 		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
 			UID:     admissionReview.Request.UID,
 			Allowed: false,
@@ -89,6 +125,9 @@ func (w *Webhook) Mutate(data []byte) *admissionv1beta1.AdmissionReview {
 
 	if len(admissionResponse.Patch) > 0 {
 		log.Infof("patch generated: %s", admissionResponse.Patch)
+		record(resultInjected, duration)
+	} else {
+		record(resultSkipped, duration)
 	}
 	log.Info("done")
 
@@ -102,25 +141,42 @@ func (w *Webhook) decode(data []byte) (*admissionv1beta1.AdmissionReview, error)
 }
 
 func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admissionv1beta1.AdmissionResponse, error) {
-	var deployment appsv1.Deployment
-	if err := yaml.Unmarshal(request.Object.Raw, &deployment); err != nil {
+	workload, err := parseWorkload(request.Kind.Kind, request.Object.Raw)
+	if err != nil {
 		return nil, err
 	}
-	log.Infof("working on %s/%s %s..", request.Kind.Version, strings.ToLower(request.Kind.Kind), deployment.ObjectMeta.Name)
+	if workload == nil {
+		log.Infof("skipping unsupported resource %s/%s", request.Kind.Version, strings.ToLower(request.Kind.Kind))
+		return &admissionv1beta1.AdmissionResponse{
+			UID:     request.UID,
+			Allowed: true,
+		}, nil
+	}
+	log.Infof("working on %s/%s %s..", request.Kind.Version, workload.kind, workload.meta.Name)
 
-	ns := request.Namespace
-	if ns == "" {
-		ns = corev1.NamespaceDefault
+	if controller := workload.injectedController(); controller != nil {
+		log.Infof("skipping %s %s controlled by %s %s", workload.kind, workload.meta.Name, strings.ToLower(controller.Kind), controller.Name)
+		return &admissionv1beta1.AdmissionResponse{
+			UID:     request.UID,
+			Allowed: true,
+		}, nil
 	}
+
+	ns := requestNamespace(request)
 	log.Infof("resource namespace: %s", ns)
 
-	inject, err := w.shouldInject(ns, &deployment)
+	decision, err := w.shouldInject(ns, workload.template)
 	if err != nil {
 		return nil, err
 	}
-
-	if !inject {
-		log.Infof("skipping deployment %s", deployment.GetName())
+	log.WithFields(log.Fields{
+		"namespace": ns,
+		"kind":      workload.kind,
+		"name":      workload.meta.Name,
+		"inject":    decision.Inject,
+	}).Info(decision.Reason)
+
+	if !decision.Inject {
 		return &admissionv1beta1.AdmissionResponse{
 			UID:     request.UID,
 			Allowed: true,
@@ -128,61 +184,94 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	}
 
 	identity := &k8sPkg.TLSIdentity{
-		Name:                deployment.ObjectMeta.Name,
-		Kind:                strings.ToLower(request.Kind.Kind),
+		Name:                workload.meta.Name,
+		Kind:                workload.kind,
 		Namespace:           ns,
 		ControllerNamespace: w.controllerNamespace,
 	}
+	labelValue := workload.meta.Name
+	if workload.kind == k8sPkg.Pod {
+		identity.Kind, identity.Name = w.podOwner(ns, workload.meta)
+		workload.label, labelValue = ownerLabel(identity.Kind), identity.Name
+	}
 
-	proxy, proxyInit, err := w.containersSpec(identity)
+	sidecar, err := w.sidecar.load()
 	if err != nil {
 		return nil, err
 	}
+
+	proxy, proxyInit := sidecar.containersSpec(identity)
+	awaitProxy, shutdownProxy := false, false
+	if config := w.proxyConfig(); config != nil {
+		config.applyTo(proxy, proxyInit)
+		awaitProxy, shutdownProxy = config.ProxyAwait, config.ProxyJobShutdown
+	}
 	log.Infof("proxy image: %s", proxy.Image)
 	log.Infof("proxy-init image: %s", proxyInit.Image)
 	log.Debugf("proxy container: %+v", proxy)
 	log.Debugf("init container: %+v", proxyInit)
 
-	patch := NewPatch()
-	patch.addContainer(proxy)
+	template := workload.template
+	patch := NewPatch(workload.podPath)
+	if (workload.kind == k8sPkg.Job || workload.kind == k8sPkg.CronJob) && k8sPkg.ShouldShutdownProxy(template.Annotations, shutdownProxy) {
+		k8sPkg.ShutdownProxy(proxy)
+		patch.addShareProcessNamespace()
+	}
+	if k8sPkg.ShouldAwaitProxy(template.Annotations, awaitProxy) {
+		// the application containers only wait for the proxy if it's started
+		// first
+		k8sPkg.AwaitProxy(proxy)
+		patch.addFirstContainer(proxy)
+	} else {
+		patch.addContainer(proxy)
+	}
 
 	if !w.noInitContainer {
-		if len(deployment.Spec.Template.Spec.InitContainers) == 0 {
+		if skipped := skipServerFirstPorts(proxyInit, &template.Spec); len(skipped) > 0 {
+			log.Infof("skipping the proxy for the ports of protocols it can't detect: %v", skipped)
+		}
+		if len(template.Spec.InitContainers) == 0 {
 			patch.addInitContainerRoot()
 		}
 		patch.addInitContainer(proxyInit)
 	}
 
+	var volumes []*corev1.Volume
 	if w.tlsEnabled {
-		caBundle, tlsSecrets, err := w.volumesSpec(identity)
-		if err != nil {
-			return nil, err
-		}
+		caBundle, tlsSecrets := sidecar.volumesSpec(identity)
 		log.Debugf("ca bundle volume: %+v", caBundle)
 		log.Debugf("tls secrets volume: %+v", tlsSecrets)
-
-		if len(deployment.Spec.Template.Spec.Volumes) == 0 {
-			patch.addVolumeRoot()
-		}
-		patch.addVolume(caBundle)
-		patch.addVolume(tlsSecrets)
+		volumes = append(volumes, caBundle, tlsSecrets)
 	}
+	volumes = append(volumes, sidecar.extraVolumesSpec()...)
 
-	if deployment.Spec.Template.Labels == nil {
-		deployment.Spec.Template.Labels = map[string]string{}
+	if len(volumes) > 0 && len(template.Spec.Volumes) == 0 {
+		patch.addVolumeRoot()
+	}
+	for _, volume := range volumes {
+		patch.addVolume(volume)
 	}
 
-	deployment.Spec.Template.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
-	deployment.Spec.Template.Labels[k8sPkg.ProxyDeploymentLabel] = deployment.ObjectMeta.Name
-	patch.addPodLabels(deployment.Spec.Template.Labels)
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
 
-	if deployment.Labels == nil {
-		deployment.Labels = map[string]string{}
+	template.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
+	if workload.label != "" {
+		template.Labels[workload.label] = labelValue
 	}
+	patch.addPodLabels(template.Labels)
+
+	// A pod has no separate template; its labels were patched above.
+	if workload.podPath != "" {
+		if workload.meta.Labels == nil {
+			workload.meta.Labels = map[string]string{}
+		}
 
-	deployment.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
-	deployment.Labels[k8sPkg.ProxyDeploymentLabel] = deployment.ObjectMeta.Name
-	patch.addDeploymentLabels(deployment.Labels)
+		workload.meta.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
+		workload.meta.Labels[workload.label] = workload.meta.Name
+		patch.addWorkloadLabels(workload.meta.Labels)
+	}
 
 	var (
 		image    = strings.Split(proxy.Image, ":")
@@ -195,12 +284,12 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 		imageTag = image[1]
 	}
 
-	if deployment.Spec.Template.Annotations == nil {
-		deployment.Spec.Template.Annotations = map[string]string{}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
 	}
-	deployment.Spec.Template.Annotations[k8sPkg.CreatedByAnnotation] = fmt.Sprintf("linkerd/proxy-injector %s", imageTag)
-	deployment.Spec.Template.Annotations[k8sPkg.ProxyVersionAnnotation] = imageTag
-	patch.addPodAnnotations(deployment.Spec.Template.Annotations)
+	template.Annotations[k8sPkg.CreatedByAnnotation] = fmt.Sprintf("linkerd/proxy-injector %s", imageTag)
+	template.Annotations[k8sPkg.ProxyVersionAnnotation] = imageTag
+	patch.addPodAnnotations(template.Annotations)
 
 	patchJSON, err := json.Marshal(patch.patchOps)
 	if err != nil {
@@ -218,91 +307,85 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	return admissionResponse, nil
 }
 
-// shouldInject determines whether or not the given deployment should be
-// injected. A deployment should be injected if it does not already contain
-// any known sidecars, and:
-// - the deployment's namespace has the linkerd.io/inject annotation set to
-//   "enabled", and the deployment's pod spec does not have the
-//   linkerd.io/inject annotation set to "disabled"; or
-// - the deployment's pod spec has the linkerd.io/inject annotation set to
-//   "enabled"
-func (w *Webhook) shouldInject(ns string, deployment *appsv1.Deployment) (bool, error) {
-	if healthcheck.HasExistingSidecars(&deployment.Spec.Template.Spec) {
-		return false, nil
-	}
-
-	namespace, err := w.client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
-	if err != nil {
-		return false, err
+// requestNamespace returns the namespace of the admitted object.
+func requestNamespace(request *admissionv1beta1.AdmissionRequest) string {
+	if request.Namespace == "" {
+		return corev1.NamespaceDefault
 	}
+	return request.Namespace
+}
 
-	nsAnnotation := namespace.GetAnnotations()[k8sPkg.ProxyInjectAnnotation]
-	podAnnotation := deployment.Spec.Template.GetAnnotations()[k8sPkg.ProxyInjectAnnotation]
-
-	if nsAnnotation == k8sPkg.ProxyInjectEnabled && podAnnotation != k8sPkg.ProxyInjectDisabled {
-		return true, nil
+// shouldInject determines whether or not the given pod template should be
+// injected, and why. A pod template should be injected if it does not already
+// contain any known sidecars, and:
+// - the workload's namespace has the linkerd.io/inject annotation, or label,
+//   set to "enabled", and the pod template does not have the
+//   linkerd.io/inject annotation set to "disabled"; or
+// - the pod template has the linkerd.io/inject annotation set to "enabled"
+//
+// Namespaces labeled with linkerd.io/inject set to "disabled" are excluded by
+// the webhook's namespace selector, so their pods never reach the webhook.
+func (w *Webhook) shouldInject(ns string, template *corev1.PodTemplateSpec) (*Decision, error) {
+	if healthcheck.HasExistingSidecars(&template.Spec) {
+		return &Decision{Inject: false, Reason: "pod already has a sidecar"}, nil
 	}
 
-	return podAnnotation == k8sPkg.ProxyInjectEnabled, nil
-}
-
-func (w *Webhook) containersSpec(identity *k8sPkg.TLSIdentity) (*corev1.Container, *corev1.Container, error) {
-	proxySpec, err := ioutil.ReadFile(w.resources.FileProxySpec)
+	namespace, err := w.client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var proxy corev1.Container
-	if err := yaml.Unmarshal(proxySpec, &proxy); err != nil {
-		return nil, nil, err
+	// A namespace opts in with either the annotation or the label. The
+	// annotation takes precedence when both are set.
+	nsAnnotation, ok := namespace.GetAnnotations()[k8sPkg.ProxyInjectAnnotation]
+	if !ok {
+		nsAnnotation = namespace.GetLabels()[k8sPkg.ProxyInjectLabel]
 	}
+	podAnnotation := template.GetAnnotations()[k8sPkg.ProxyInjectAnnotation]
 
-	for index, env := range proxy.Env {
-		if env.Name == envVarKeyProxyTLSPodIdentity {
-			proxy.Env[index].Value = identity.ToDNSName()
-		} else if env.Name == envVarKeyProxyTLSControllerIdentity {
-			proxy.Env[index].Value = identity.ToControllerIdentity().ToDNSName()
-		} else if env.Name == envVarKeyProxyID {
-			proxy.Env[index].Value = identity.ToDNSName()
+	if nsAnnotation == k8sPkg.ProxyInjectEnabled {
+		if podAnnotation == k8sPkg.ProxyInjectDisabled {
+			return &Decision{Inject: false, Reason: fmt.Sprintf("pod has the %s annotation set to %q", k8sPkg.ProxyInjectAnnotation, podAnnotation)}, nil
 		}
+		return &Decision{Inject: true, Reason: fmt.Sprintf("namespace %s has %s set to %q", ns, k8sPkg.ProxyInjectAnnotation, nsAnnotation)}, nil
 	}
 
-	proxyInitSpec, err := ioutil.ReadFile(w.resources.FileProxyInitSpec)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var proxyInit corev1.Container
-	if err := yaml.Unmarshal(proxyInitSpec, &proxyInit); err != nil {
-		return nil, nil, err
+	if podAnnotation == k8sPkg.ProxyInjectEnabled {
+		return &Decision{Inject: true, Reason: fmt.Sprintf("pod has the %s annotation set to %q", k8sPkg.ProxyInjectAnnotation, podAnnotation)}, nil
 	}
 
-	return &proxy, &proxyInit, nil
+	return &Decision{Inject: false, Reason: fmt.Sprintf("neither namespace %s nor the pod have %s set to %q", ns, k8sPkg.ProxyInjectAnnotation, k8sPkg.ProxyInjectEnabled)}, nil
 }
 
-func (w *Webhook) volumesSpec(identity *k8sPkg.TLSIdentity) (*corev1.Volume, *corev1.Volume, error) {
-	trustAnchorVolumeSpec, err := ioutil.ReadFile(w.resources.FileTLSTrustAnchorVolumeSpec)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var trustAnchors corev1.Volume
-	if err := yaml.Unmarshal(trustAnchorVolumeSpec, &trustAnchors); err != nil {
-		return nil, nil, err
+// podOwner returns the kind and name of the workload a pod belongs to, which
+// is the identity its proxy is issued a certificate for. This matches the
+// controller's API.GetOwnerKindAndName, so that the pod mounts the secret the
+// CA controller creates for it.
+func (w *Webhook) podOwner(ns string, pod *metav1.ObjectMeta) (string, string) {
+	if len(pod.GetOwnerReferences()) != 1 {
+		return k8sPkg.Pod, pod.Name
 	}
 
-	tlsVolumeSpec, err := ioutil.ReadFile(w.resources.FileTLSIdentityVolumeSpec)
-	if err != nil {
-		return nil, nil, err
+	parent := pod.GetOwnerReferences()[0]
+	if parent.Kind == "ReplicaSet" {
+		rs, err := w.client.AppsV1().ReplicaSets(ns).Get(parent.Name, metav1.GetOptions{})
+		if err != nil || len(rs.GetOwnerReferences()) != 1 {
+			return strings.ToLower(parent.Kind), parent.Name
+		}
+		rsParent := rs.GetOwnerReferences()[0]
+		return strings.ToLower(rsParent.Kind), rsParent.Name
 	}
-
-	var linkerdSecrets corev1.Volume
-	if err := yaml.Unmarshal(tlsVolumeSpec, &linkerdSecrets); err != nil {
-		return nil, nil, err
+	if parent.Kind == "Job" {
+		job, err := w.client.BatchV1().Jobs(ns).Get(parent.Name, metav1.GetOptions{})
+		if err != nil {
+			return strings.ToLower(parent.Kind), parent.Name
+		}
+		if jobParent := metav1.GetControllerOf(job); jobParent != nil && jobParent.Kind == "CronJob" {
+			return strings.ToLower(jobParent.Kind), jobParent.Name
+		}
 	}
-	linkerdSecrets.VolumeSource.Secret.SecretName = identity.ToSecretName()
 
-	return &trustAnchors, &linkerdSecrets, nil
+	return strings.ToLower(parent.Kind), parent.Name
 }
 
 // WebhookResources contain paths to all the needed file resources.
@@ -318,4 +401,8 @@ type WebhookResources struct {
 
 	// FileTLSIdentityVolumeSpec is the path to the TLS identity volume spec.
 	FileTLSIdentityVolumeSpec string
+
+	// FileExtraVolumesSpec is the path to the optional spec of the extra
+	// volumes added to injected pods.
+	FileExtraVolumesSpec string
 }
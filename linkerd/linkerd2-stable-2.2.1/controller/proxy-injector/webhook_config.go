@@ -3,57 +3,98 @@ package injector
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"text/template"
 
 	"github.com/linkerd/linkerd2/controller/proxy-injector/tmpl"
 	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
-	"github.com/linkerd/linkerd2/pkg/tls"
 	log "github.com/sirupsen/logrus"
 	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/yaml"
 )
 
+// WebhookPolicy holds the settings that decide how the Kubernetes API server
+// calls the webhook.
+type WebhookPolicy struct {
+	// FailurePolicy is either "Ignore", to admit pods without a proxy when the
+	// webhook can't be reached or fails, or "Fail", to reject them.
+	FailurePolicy arv1beta1.FailurePolicyType
+
+	// TimeoutSeconds is how long the API server waits for the webhook. Zero
+	// leaves it to the API server's default.
+	TimeoutSeconds uint
+
+	// ReinvocationPolicy is either "Never" or "IfNeeded", to call the webhook
+	// again when other webhooks change a pod after it was injected.
+	ReinvocationPolicy string
+}
+
 // WebhookConfig creates the MutatingWebhookConfiguration of the webhook.
 type WebhookConfig struct {
 	controllerNamespace string
 	webhookServiceName  string
-	trustAnchor         []byte
+	policy              WebhookPolicy
 	configTemplate      *template.Template
 	k8sAPI              kubernetes.Interface
 }
 
 // NewWebhookConfig returns a new instance of initiator.
-func NewWebhookConfig(client kubernetes.Interface, controllerNamespace, webhookServiceName string, rootCA *tls.CA) (*WebhookConfig, error) {
-	trustAnchor := []byte(rootCA.TrustAnchorPEM())
-
+func NewWebhookConfig(client kubernetes.Interface, controllerNamespace, webhookServiceName string, policy WebhookPolicy) (*WebhookConfig, error) {
 	t := template.New(k8sPkg.ProxyInjectorWebhookConfig)
 
 	return &WebhookConfig{
 		controllerNamespace: controllerNamespace,
 		webhookServiceName:  webhookServiceName,
-		trustAnchor:         trustAnchor,
+		policy:              policy,
 		configTemplate:      template.Must(t.Parse(tmpl.MutatingWebhookConfigurationSpec)),
 		k8sAPI:              client,
 	}, nil
 }
 
 // CreateOrUpdate sends the request to either create or update the
-// MutatingWebhookConfiguration resource. During an update, only the CA bundle
-// is changed.
-func (w *WebhookConfig) CreateOrUpdate() (*arv1beta1.MutatingWebhookConfiguration, error) {
-	mwc, exist, err := w.exist()
+// MutatingWebhookConfiguration resource, with trustAnchor as its CA bundle.
+// The webhooks are always written with a JSON patch of the rendered template,
+// which replaces the webhooks of a configuration created by a previous
+// version, or with a previous CA, and carries fields that the vendored API
+// types don't know about, such as timeoutSeconds and reinvocationPolicy.
+func (w *WebhookConfig) CreateOrUpdate(trustAnchor []byte) (*arv1beta1.MutatingWebhookConfiguration, error) {
+	config, webhooks, err := w.render(trustAnchor)
+	if err != nil {
+		return nil, err
+	}
+
+	_, exist, err := w.exist()
 	if err != nil {
 		return nil, err
 	}
 
 	if !exist {
-		return w.create()
+		if _, err := w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(config); err != nil {
+			return nil, err
+		}
 	}
 
-	return w.update(mwc)
+	patch, err := json.Marshal([]patchOp{{Op: "replace", Path: "/webhooks", Value: webhooks}})
+	if err != nil {
+		return nil, err
+	}
+
+	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Patch(k8sPkg.ProxyInjectorWebhookConfig, types.JSONPatchType, patch)
+}
+
+// Publish creates or updates the MutatingWebhookConfiguration with trustAnchor
+// as its CA bundle. It's meant to be called by the webhook's CertRotator.
+func (w *WebhookConfig) Publish(trustAnchor []byte) error {
+	mwc, err := w.CreateOrUpdate(trustAnchor)
+	if err != nil {
+		return err
+	}
+	log.Debugf("created or updated mutating webhook configuration: %s", mwc.ObjectMeta.SelfLink)
+	return nil
 }
 
 // exist returns true if the mutating webhook configuration exists. Otherwise,
@@ -71,7 +112,10 @@ func (w *WebhookConfig) exist() (*arv1beta1.MutatingWebhookConfiguration, bool,
 	return mwc, true, nil
 }
 
-func (w *WebhookConfig) create() (*arv1beta1.MutatingWebhookConfiguration, error) {
+// render returns the configuration rendered from the template, along with the
+// JSON of its webhooks as rendered, before any field unknown to the API types
+// is dropped.
+func (w *WebhookConfig) render(trustAnchor []byte) (*arv1beta1.MutatingWebhookConfiguration, json.RawMessage, error) {
 	var (
 		buf  = &bytes.Buffer{}
 		spec = struct {
@@ -79,30 +123,39 @@ func (w *WebhookConfig) create() (*arv1beta1.MutatingWebhookConfiguration, error
 			WebhookServiceName  string
 			ControllerNamespace string
 			CABundle            string
+			ProxyInjectLabel    string
+			ProxyInjectDisabled string
+			FailurePolicy       arv1beta1.FailurePolicyType
+			TimeoutSeconds      uint
+			ReinvocationPolicy  string
 		}{
 			WebhookConfigName:   k8sPkg.ProxyInjectorWebhookConfig,
 			WebhookServiceName:  w.webhookServiceName,
 			ControllerNamespace: w.controllerNamespace,
-			CABundle:            base64.StdEncoding.EncodeToString(w.trustAnchor),
+			CABundle:            base64.StdEncoding.EncodeToString(trustAnchor),
+			ProxyInjectLabel:    k8sPkg.ProxyInjectLabel,
+			ProxyInjectDisabled: k8sPkg.ProxyInjectDisabled,
+			FailurePolicy:       w.policy.FailurePolicy,
+			TimeoutSeconds:      w.policy.TimeoutSeconds,
+			ReinvocationPolicy:  w.policy.ReinvocationPolicy,
 		}
 	)
 	if err := w.configTemplate.Execute(buf, spec); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var config arv1beta1.MutatingWebhookConfiguration
 	if err := yaml.Unmarshal(buf.Bytes(), &config); err != nil {
 		log.Infof("failed to unmarshal mutating webhook configuration: %s\n%s\n", err, buf.String())
-		return nil, err
+		return nil, nil, err
 	}
 
-	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(&config)
-}
-
-func (w *WebhookConfig) update(mwc *arv1beta1.MutatingWebhookConfiguration) (*arv1beta1.MutatingWebhookConfiguration, error) {
-	for i := 0; i < len(mwc.Webhooks); i++ {
-		mwc.Webhooks[i].ClientConfig.CABundle = w.trustAnchor
+	var raw struct {
+		Webhooks json.RawMessage `json:"webhooks"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &raw); err != nil {
+		return nil, nil, err
 	}
 
-	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(mwc)
+	return &config, raw.Webhooks, nil
 }
@@ -3,10 +3,15 @@ package injector
 import (
 	"io/ioutil"
 	"log"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/tls"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCreateOrUpdate(t *testing.T) {
@@ -23,7 +28,9 @@ func TestCreateOrUpdate(t *testing.T) {
 		t.Fatalf("failed to create root CA: %s", err)
 	}
 
-	webhookConfig, err := NewWebhookConfig(client, namespace, webhookServiceName, rootCA)
+	trustAnchor := []byte(rootCA.TrustAnchorPEM())
+
+	webhookConfig, err := NewWebhookConfig(client, namespace, webhookServiceName, WebhookPolicy{FailurePolicy: fake.DefaultFailurePolicy})
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -38,21 +45,88 @@ func TestCreateOrUpdate(t *testing.T) {
 	}
 
 	// create the mutating webhook configuration
-	if _, err := webhookConfig.CreateOrUpdate(); err != nil {
+	if _, err := webhookConfig.CreateOrUpdate(trustAnchor); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
 	// expect mutating webhook configuration to exist
-	_, exist, err = webhookConfig.exist()
+	mwc, exist, err := webhookConfig.exist()
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 	if !exist {
 		t.Error("Expected mutating webhook configuration to exist")
 	}
+	assertNamespaceSelector(t, mwc)
+
+	// expect an outdated mutating webhook configuration to be brought up to date
+	mwc.Webhooks[0].NamespaceSelector = nil
+	if _, err := client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(mwc); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
 
 	// update the mutating webhook configuration using the same trust anchors
-	if _, err := webhookConfig.CreateOrUpdate(); err != nil {
+	mwc, err = webhookConfig.CreateOrUpdate(trustAnchor)
+	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
+	assertNamespaceSelector(t, mwc)
+}
+
+func TestRenderPolicy(t *testing.T) {
+	client := fake.NewClient("")
+
+	testCases := []struct {
+		policy   WebhookPolicy
+		expected string
+	}{
+		{
+			WebhookPolicy{FailurePolicy: arv1beta1.Ignore},
+			`"failurePolicy":"Ignore"`,
+		},
+		{
+			WebhookPolicy{FailurePolicy: arv1beta1.Fail, TimeoutSeconds: 10, ReinvocationPolicy: "IfNeeded"},
+			`"reinvocationPolicy":"IfNeeded"`,
+		},
+		{
+			WebhookPolicy{FailurePolicy: arv1beta1.Fail, TimeoutSeconds: 10},
+			`"timeoutSeconds":10`,
+		},
+	}
+
+	for _, tc := range testCases {
+		webhookConfig, err := NewWebhookConfig(client, fake.DefaultControllerNamespace, "test.linkerd.io", tc.policy)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		config, webhooks, err := webhookConfig.render([]byte("trust anchor"))
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if *config.Webhooks[0].FailurePolicy != tc.policy.FailurePolicy {
+			t.Errorf("Expected failure policy %s, got %s", tc.policy.FailurePolicy, *config.Webhooks[0].FailurePolicy)
+		}
+		if !strings.Contains(string(webhooks), tc.expected) {
+			t.Errorf("Expected rendered webhooks to contain %s, got %s", tc.expected, webhooks)
+		}
+	}
+}
+
+func assertNamespaceSelector(t *testing.T, mwc *arv1beta1.MutatingWebhookConfiguration) {
+	expected := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      k8sPkg.ProxyInjectLabel,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{k8sPkg.ProxyInjectDisabled},
+			},
+		},
+	}
+
+	for _, webhook := range mwc.Webhooks {
+		if !reflect.DeepEqual(webhook.NamespaceSelector, expected) {
+			t.Errorf("Namespace selector mismatch\nExpected: %+v\nActual: %+v", expected, webhook.NamespaceSelector)
+		}
+	}
 }
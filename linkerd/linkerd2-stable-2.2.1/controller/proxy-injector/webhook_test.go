@@ -1,14 +1,22 @@
 package injector
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -22,12 +30,12 @@ func TestMutate(t *testing.T) {
 	}
 	fakeClient := fake.NewClient("", ns)
 
-	defaultWebhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled)
+	defaultWebhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
 
-	noInitContainerWebhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, true, fake.DefaultTLSEnabled)
+	noInitContainerWebhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, true, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -35,7 +43,7 @@ func TestMutate(t *testing.T) {
 	tlsDisabledWebhookResources := *testWebhookResources
 	tlsDisabledWebhookResources.FileProxySpec = fake.FileProxyTLSDisabledSpec
 
-	tlsDisabledWebook, err := NewWebhook(fakeClient, &tlsDisabledWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, false)
+	tlsDisabledWebook, err := NewWebhook(fakeClient, &tlsDisabledWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, false, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -71,6 +79,187 @@ func TestMutate(t *testing.T) {
 	}
 }
 
+func TestMutateFailurePolicy(t *testing.T) {
+	// the request's namespace doesn't exist, so injecting it fails
+	fakeClient := fake.NewClient("")
+
+	data, err := factory.HTTPRequestBody("inject-enabled-request.json")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	var testCases = []struct {
+		failurePolicy arv1beta1.FailurePolicyType
+		allowed       bool
+	}{
+		{arv1beta1.Ignore, true},
+		{arv1beta1.Fail, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.failurePolicy), func(t *testing.T) {
+			webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, testCase.failurePolicy)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			response := webhook.Mutate(data).Response
+			if response.Allowed != testCase.allowed {
+				t.Fatalf("Expected allowed to be %t, got %t", testCase.allowed, response.Allowed)
+			}
+			if len(response.Patch) != 0 {
+				t.Fatalf("Expected no patch, got %s", response.Patch)
+			}
+		})
+	}
+}
+
+func TestMutateDryRun(t *testing.T) {
+	ns, err := factory.Namespace("namespace-inject-enabled.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	webhook, err := NewWebhook(fake.NewClient("", ns), testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	data, err := factory.HTTPRequestBody("inject-enabled-request.json")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	review, err := webhook.decode(data)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	dryRun := true
+	review.Request.DryRun = &dryRun
+	dryRunData, err := json.Marshal(review)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	injected := admissionRequests.WithLabelValues(requestNamespace(review.Request), k8s.Deployment, resultInjected)
+	before := counterValue(t, injected)
+
+	dryRunPatch := webhook.Mutate(dryRunData).Response.Patch
+	if after := counterValue(t, injected); after != before {
+		t.Fatalf("Expected dry runs not to be recorded, counter went from %v to %v", before, after)
+	}
+
+	patch := webhook.Mutate(data).Response.Patch
+	if after := counterValue(t, injected); after != before+1 {
+		t.Fatalf("Expected requests to be recorded, counter went from %v to %v", before, after)
+	}
+
+	if string(dryRunPatch) != string(patch) {
+		t.Fatalf("Expected dry runs to return the same patch\nExpected: %s\nActual: %s", patch, dryRunPatch)
+	}
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	var metric dto.Metric
+	if err := counter.Write(&metric); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestInjectWorkloads(t *testing.T) {
+	ns, err := factory.Namespace("namespace-inject-enabled.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	rs, err := factory.ReplicaSet("replicaset-owned-by-deployment.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	fakeClient := fake.NewClient("", ns, rs)
+
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	var testCases = []struct {
+		kind          string
+		filename      string
+		containerPath string
+		secretName    string
+		podLabel      string
+	}{
+		{"StatefulSet", "statefulset-inject-empty.yaml", "/spec/template/spec/containers/-", "nginx-statefulset-tls-linkerd-io", k8s.ProxyStatefulSetLabel},
+		{"Job", "job-inject-empty.yaml", "/spec/template/spec/containers/-", "nginx-job-tls-linkerd-io", k8s.ProxyJobLabel},
+		{"Pod", "pod-inject-empty.yaml", "/spec/containers/-", "nginx-pod-tls-linkerd-io", k8s.ProxyPodLabel},
+		{"Pod", "pod-owned-by-replicaset.yaml", "/spec/containers/-", "nginx-deployment-tls-linkerd-io", k8s.ProxyDeploymentLabel},
+		{"CronJob", "cronjob-inject-empty.yaml", "/spec/jobTemplate/spec/template/spec/containers/-", "nginx-cronjob-tls-linkerd-io", k8s.ProxyCronJobLabel},
+		{"ReplicaSet", "replicaset-owned-by-deployment.yaml", "", "", ""},
+		{"Job", "job-owned-by-cronjob.yaml", "", "", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.filename, func(t *testing.T) {
+			b, err := factory.HTTPRequestBody(testCase.filename)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			raw, err := yaml.YAMLToJSON(b)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			request := &admissionv1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Kind: testCase.kind},
+				Namespace: ns.GetName(),
+				Object:    runtime.RawExtension{Raw: raw},
+			}
+			response, err := webhook.inject(request)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			if !response.Allowed {
+				t.Fatalf("Expected %s to be allowed", testCase.filename)
+			}
+
+			var ops []patchOp
+			if len(response.Patch) > 0 {
+				if err := json.Unmarshal(response.Patch, &ops); err != nil {
+					t.Fatal("Unexpected error: ", err)
+				}
+			}
+
+			containerPath, secretName, podLabel := "", "", ""
+			podLabelsPath := strings.TrimSuffix(testCase.containerPath, patchPathContainer) + patchPathPodLabels
+			for _, op := range ops {
+				if op.Path == testCase.containerPath && containerPath == "" {
+					containerPath = op.Path
+				}
+				value, ok := op.Value.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if secret, ok := value["secret"].(map[string]interface{}); ok {
+					secretName, _ = secret["secretName"].(string)
+				}
+				if op.Path == podLabelsPath {
+					if _, ok := value[testCase.podLabel]; ok {
+						podLabel = testCase.podLabel
+					}
+				}
+			}
+			if containerPath != testCase.containerPath {
+				t.Errorf("Expected proxy to be added at [%s], got patch %s", testCase.containerPath, response.Patch)
+			}
+			if secretName != testCase.secretName {
+				t.Errorf("Expected TLS secret [%s], got [%s]", testCase.secretName, secretName)
+			}
+			if podLabel != testCase.podLabel {
+				t.Errorf("Expected pod label [%s] to be added, got patch %s", testCase.podLabel, response.Patch)
+			}
+		})
+	}
+}
+
 func TestShouldInject(t *testing.T) {
 	nsEnabled, err := factory.Namespace("namespace-inject-enabled.yaml")
 	if err != nil {
@@ -80,9 +269,13 @@ func TestShouldInject(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Unexpected error: %s", err)
 	}
-	fakeClient := fake.NewClient("", nsEnabled, nsDisabled)
+	nsLabeled, err := factory.Namespace("namespace-inject-enabled-label.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	fakeClient := fake.NewClient("", nsEnabled, nsDisabled, nsLabeled)
 
-	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled)
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatalf("Unexpected error: %s", err)
 	}
@@ -123,6 +316,16 @@ func TestShouldInject(t *testing.T) {
 				ns:       nsDisabled,
 				expected: false,
 			},
+			{
+				filename: "deployment-inject-empty.yaml",
+				ns:       nsLabeled,
+				expected: true,
+			},
+			{
+				filename: "deployment-inject-disabled.yaml",
+				ns:       nsLabeled,
+				expected: false,
+			},
 		}
 
 		for id, testCase := range testCases {
@@ -132,12 +335,12 @@ func TestShouldInject(t *testing.T) {
 					t.Fatalf("Unexpected error: %s", err)
 				}
 
-				inject, err := webhook.shouldInject(testCase.ns.GetName(), deployment)
+				decision, err := webhook.shouldInject(testCase.ns.GetName(), &deployment.Spec.Template)
 				if err != nil {
 					t.Fatalf("Unexpected shouldInject error: %s", err)
 				}
-				if inject != testCase.expected {
-					t.Fatalf("Boolean mismatch. Expected: %t. Actual: %t", testCase.expected, inject)
+				if decision.Inject != testCase.expected {
+					t.Fatalf("Boolean mismatch. Expected: %t. Actual: %t (%s)", testCase.expected, decision.Inject, decision.Reason)
 				}
 			})
 		}
@@ -149,11 +352,11 @@ func TestShouldInject(t *testing.T) {
 			t.Fatalf("Unexpected error: %s", err)
 		}
 
-		inject, err := webhook.shouldInject(nsEnabled.GetName(), deployment)
+		decision, err := webhook.shouldInject(nsEnabled.GetName(), &deployment.Spec.Template)
 		if err != nil {
 			t.Fatalf("Unexpected shouldInject error: %s", err)
 		}
-		if inject {
+		if decision.Inject {
 			t.Fatal("Expected deployment with injected proxy to be skipped")
 		}
 	})
@@ -162,7 +365,7 @@ func TestShouldInject(t *testing.T) {
 func TestContainersSpec(t *testing.T) {
 	fakeClient := fake.NewClient("")
 
-	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled)
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -184,10 +387,11 @@ func TestContainersSpec(t *testing.T) {
 		ControllerNamespace: fake.DefaultControllerNamespace,
 	}
 
-	actualSidecar, actualInit, err := webhook.containersSpec(identity)
+	sidecar, err := webhook.sidecar.load()
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
+	actualSidecar, actualInit := sidecar.containersSpec(identity)
 
 	if !reflect.DeepEqual(expectedSidecar, actualSidecar) {
 		t.Errorf("Content mismatch\nExpected: %+v\nActual: %+v", expectedSidecar, actualSidecar)
@@ -201,7 +405,7 @@ func TestContainersSpec(t *testing.T) {
 func TestVolumesSpec(t *testing.T) {
 	fakeClient := fake.NewClient("")
 
-	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled)
+	webhook, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace, fake.DefaultNoInitContainer, fake.DefaultTLSEnabled, fake.DefaultFailurePolicy)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -223,10 +427,11 @@ func TestVolumesSpec(t *testing.T) {
 		ControllerNamespace: fake.DefaultControllerNamespace,
 	}
 
-	actualTrustAnchors, actualLinkerdSecrets, err := webhook.volumesSpec(identity)
+	sidecar, err := webhook.sidecar.load()
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
+	actualTrustAnchors, actualLinkerdSecrets := sidecar.volumesSpec(identity)
 
 	if !reflect.DeepEqual(expectedTrustAnchors, actualTrustAnchors) {
 		t.Errorf("Content mismatch\nExpected: %+v\nActual: %+v", expectedTrustAnchors, actualTrustAnchors)
@@ -0,0 +1,195 @@
+package injector
+
+import (
+	"strings"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// workload is an admitted Kubernetes object whose pods can be injected.
+type workload struct {
+	// kind is the lowercase kind of the object, e.g. "deployment".
+	kind string
+
+	// meta is the object's metadata.
+	meta *metav1.ObjectMeta
+
+	// template is the pod template of the object. For pods, it's a copy of the
+	// pod's metadata and spec.
+	template *corev1.PodTemplateSpec
+
+	// podPath is the path of the pod template in the object, empty for pods.
+	podPath string
+
+	// label is the label that identifies the object in the pods it owns. For
+	// pods, it's set once their owner is known, see ownerLabel.
+	label string
+}
+
+// ownerLabels maps the kinds of the objects that the pods of the mesh belong
+// to, as returned by Webhook.podOwner, to the labels that identify them.
+var ownerLabels = map[string]string{
+	k8sPkg.Deployment:            k8sPkg.ProxyDeploymentLabel,
+	k8sPkg.StatefulSet:           k8sPkg.ProxyStatefulSetLabel,
+	k8sPkg.DaemonSet:             k8sPkg.ProxyDaemonSetLabel,
+	k8sPkg.ReplicaSet:            k8sPkg.ProxyReplicaSetLabel,
+	k8sPkg.ReplicationController: k8sPkg.ProxyReplicationControllerLabel,
+	k8sPkg.Job:                   k8sPkg.ProxyJobLabel,
+	k8sPkg.CronJob:               k8sPkg.ProxyCronJobLabel,
+	k8sPkg.Pod:                   k8sPkg.ProxyPodLabel,
+}
+
+// ownerLabel returns the label that identifies the owner of a pod of the given
+// kind, or an empty string for the kinds that aren't injected, e.g. custom
+// resources.
+func ownerLabel(kind string) string {
+	return ownerLabels[kind]
+}
+
+// injectedController returns the controller of the workload if it's itself a
+// workload that the webhook injects, e.g. the Deployment of a ReplicaSet or the
+// CronJob of a Job, and nil otherwise. Such workloads are skipped: their pod
+// template is copied from their controller's, which was already injected if
+// it had to be. Pods aren't considered; the ones whose workload was injected
+// already have a sidecar.
+func (w *workload) injectedController() *metav1.OwnerReference {
+	if w.podPath == "" {
+		return nil
+	}
+	controller := metav1.GetControllerOf(w.meta)
+	if controller == nil {
+		return nil
+	}
+	kind := strings.ToLower(controller.Kind)
+	if kind == k8sPkg.Pod || ownerLabel(kind) == "" {
+		return nil
+	}
+	return controller
+}
+
+// parseWorkload unmarshals raw into the Kubernetes type of the given kind. It
+// covers the same kinds as `linkerd inject`, and CronJobs, whose pod template
+// is nested in their Job template. If kind isn't supported, a nil workload is
+// returned.
+func parseWorkload(kind string, raw []byte) (*workload, error) {
+	// Deployments, DaemonSets and ReplicaSets may still be admitted through
+	// the extensions/v1beta1 and apps/v1beta* APIs. Their pod templates are the
+	// same in every version, so they're all decoded as apps/v1.
+	switch kind {
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := yaml.Unmarshal(raw, &deployment); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.Deployment,
+			meta:     &deployment.ObjectMeta,
+			template: &deployment.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyDeploymentLabel,
+		}, nil
+
+	case "StatefulSet":
+		var statefulset appsv1.StatefulSet
+		if err := yaml.Unmarshal(raw, &statefulset); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.StatefulSet,
+			meta:     &statefulset.ObjectMeta,
+			template: &statefulset.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyStatefulSetLabel,
+		}, nil
+
+	case "DaemonSet":
+		var ds appsv1.DaemonSet
+		if err := yaml.Unmarshal(raw, &ds); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.DaemonSet,
+			meta:     &ds.ObjectMeta,
+			template: &ds.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyDaemonSetLabel,
+		}, nil
+
+	case "ReplicaSet":
+		var rs appsv1.ReplicaSet
+		if err := yaml.Unmarshal(raw, &rs); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.ReplicaSet,
+			meta:     &rs.ObjectMeta,
+			template: &rs.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyReplicaSetLabel,
+		}, nil
+
+	case "ReplicationController":
+		var rc corev1.ReplicationController
+		if err := yaml.Unmarshal(raw, &rc); err != nil {
+			return nil, err
+		}
+		if rc.Spec.Template == nil {
+			rc.Spec.Template = &corev1.PodTemplateSpec{}
+		}
+		return &workload{
+			kind:     k8sPkg.ReplicationController,
+			meta:     &rc.ObjectMeta,
+			template: rc.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyReplicationControllerLabel,
+		}, nil
+
+	case "Job":
+		var job batchv1.Job
+		if err := yaml.Unmarshal(raw, &job); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.Job,
+			meta:     &job.ObjectMeta,
+			template: &job.Spec.Template,
+			podPath:  patchPathPodTemplate,
+			label:    k8sPkg.ProxyJobLabel,
+		}, nil
+
+	case "CronJob":
+		var cronjob batchv1beta1.CronJob
+		if err := yaml.Unmarshal(raw, &cronjob); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:     k8sPkg.CronJob,
+			meta:     &cronjob.ObjectMeta,
+			template: &cronjob.Spec.JobTemplate.Spec.Template,
+			podPath:  patchPathCronJobPodTemplate,
+			label:    k8sPkg.ProxyCronJobLabel,
+		}, nil
+
+	case "Pod":
+		var pod corev1.Pod
+		if err := yaml.Unmarshal(raw, &pod); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind: k8sPkg.Pod,
+			meta: &pod.ObjectMeta,
+			template: &corev1.PodTemplateSpec{
+				ObjectMeta: pod.ObjectMeta,
+				Spec:       pod.Spec,
+			},
+		}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,349 @@
+package servicemirror
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// GatewayConfig describes how the mirrored Services of a remote cluster are
+// reached: through a gateway at the given addresses and port, which forwards
+// the traffic to the remote Services.
+type GatewayConfig struct {
+	Addresses []string
+	Port      int32
+}
+
+// RemoteClusterServiceWatcher watches the Services of a remote cluster, and
+// mirrors them in the local cluster. A remote Service "name" in namespace
+// "ns" is mirrored as the Service "name-$clusterName" in the same namespace,
+// with Endpoints pointing at the remote cluster's gateway, so that the
+// destination service resolves it to the gateway's addresses.
+type RemoteClusterServiceWatcher struct {
+	clusterName   string
+	clusterDomain string
+	gateway       GatewayConfig
+	remoteAPI     *k8s.API
+	localAPI      *k8s.API
+	syncHandler   func(key string) error
+
+	// The queue is keyed on the "namespace/name" of remote Services.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewRemoteClusterServiceWatcher initializes a RemoteClusterServiceWatcher
+// for the remote cluster with the given name. The remote API must watch
+// Services, and the local API must watch Services and Endpoints.
+func NewRemoteClusterServiceWatcher(
+	clusterName, clusterDomain string,
+	gateway GatewayConfig,
+	remoteAPI, localAPI *k8s.API,
+) (*RemoteClusterServiceWatcher, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("the remote cluster must have a name")
+	}
+	if len(gateway.Addresses) == 0 || gateway.Port == 0 {
+		return nil, fmt.Errorf("the remote cluster's gateway must have addresses and a port")
+	}
+
+	w := &RemoteClusterServiceWatcher{
+		clusterName:   clusterName,
+		clusterDomain: clusterDomain,
+		gateway:       gateway,
+		remoteAPI:     remoteAPI,
+		localAPI:      localAPI,
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "service-mirror"),
+	}
+
+	remoteAPI.Svc().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    w.handleRemoteService,
+			UpdateFunc: func(_, newObj interface{}) { w.handleRemoteService(newObj) },
+			DeleteFunc: w.handleRemoteService,
+		},
+	)
+
+	w.syncHandler = w.syncService
+
+	return w, nil
+}
+
+// Run kicks off the queue processing. The mirrored Services of remote
+// Services that were deleted while the watcher wasn't running are cleaned up
+// first.
+func (w *RemoteClusterServiceWatcher) Run(stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer w.queue.ShutDown()
+
+	log.Infof("starting service mirror for cluster %s", w.clusterName)
+	defer log.Infof("shutting down service mirror for cluster %s", w.clusterName)
+
+	err := w.enqueueMirroredServices()
+	if err != nil {
+		log.Errorf("failed to list mirrored services: %s", err)
+	}
+
+	go wait.Until(w.worker, time.Second, stopCh)
+
+	<-stopCh
+}
+
+func (w *RemoteClusterServiceWatcher) worker() {
+	for w.processNextWorkItem() {
+	}
+}
+
+func (w *RemoteClusterServiceWatcher) processNextWorkItem() bool {
+	key, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(key)
+
+	err := w.syncHandler(key.(string))
+	if err != nil {
+		log.Errorf("error syncing service %s: %s", key, err)
+		w.queue.AddRateLimited(key)
+		return true
+	}
+
+	w.queue.Forget(key)
+	return true
+}
+
+// syncService creates or updates the mirror of a remote Service, or deletes
+// it if the remote Service no longer exists.
+func (w *RemoteClusterServiceWatcher) syncService(key string) error {
+	log.Debugf("syncService(%s)", key)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Errorf("Failed to parse service sync request %s", key)
+		return nil
+	}
+
+	remote, err := w.remoteAPI.Svc().Lister().Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return w.deleteMirror(namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !w.shouldMirror(remote) {
+		return nil
+	}
+
+	_, err = w.localAPI.Client.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Debugf("skipping service %s: namespace %s doesn't exist locally", key, namespace)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = w.applyService(w.mirrorService(remote))
+	if err != nil {
+		return err
+	}
+	return w.applyEndpoints(w.mirrorEndpoints(remote))
+}
+
+// shouldMirror returns false for the Services that can't be reached through
+// the gateway: ExternalName Services, which have no ports, and the mirrors of
+// other clusters' Services.
+func (w *RemoteClusterServiceWatcher) shouldMirror(svc *v1.Service) bool {
+	if svc.Spec.Type == v1.ServiceTypeExternalName || len(svc.Spec.Ports) == 0 {
+		return false
+	}
+	return svc.Labels[pkgK8s.MirroredServiceLabel] == ""
+}
+
+func (w *RemoteClusterServiceWatcher) mirrorName(name string) string {
+	return fmt.Sprintf("%s-%s", name, w.clusterName)
+}
+
+func (w *RemoteClusterServiceWatcher) mirrorMeta(remote *v1.Service) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      w.mirrorName(remote.Name),
+		Namespace: remote.Namespace,
+		Labels: map[string]string{
+			pkgK8s.MirroredServiceLabel:   "true",
+			pkgK8s.RemoteClusterNameLabel: w.clusterName,
+		},
+		Annotations: map[string]string{
+			pkgK8s.RemoteServiceFqNameAnnotation:   fmt.Sprintf("%s.%s.svc.%s", remote.Name, remote.Namespace, w.clusterDomain),
+			pkgK8s.RemoteResourceVersionAnnotation: remote.ResourceVersion,
+		},
+	}
+}
+
+// mirrorService returns the mirror of a remote Service. It has the remote
+// Service's ports, which all target the gateway's port.
+func (w *RemoteClusterServiceWatcher) mirrorService(remote *v1.Service) *v1.Service {
+	ports := make([]v1.ServicePort, len(remote.Spec.Ports))
+	for i, port := range remote.Spec.Ports {
+		ports[i] = v1.ServicePort{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.Port,
+			TargetPort: intstr.FromInt(int(w.gateway.Port)),
+		}
+	}
+
+	return &v1.Service{
+		ObjectMeta: w.mirrorMeta(remote),
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeClusterIP,
+			Ports: ports,
+		},
+	}
+}
+
+// mirrorEndpoints returns the Endpoints of the mirror of a remote Service,
+// which are the addresses of the gateway.
+func (w *RemoteClusterServiceWatcher) mirrorEndpoints(remote *v1.Service) *v1.Endpoints {
+	addresses := make([]v1.EndpointAddress, len(w.gateway.Addresses))
+	for i, address := range w.gateway.Addresses {
+		addresses[i] = v1.EndpointAddress{IP: address}
+	}
+	ports := make([]v1.EndpointPort, len(remote.Spec.Ports))
+	for i, port := range remote.Spec.Ports {
+		ports[i] = v1.EndpointPort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     w.gateway.Port,
+		}
+	}
+
+	return &v1.Endpoints{
+		ObjectMeta: w.mirrorMeta(remote),
+		Subsets: []v1.EndpointSubset{
+			v1.EndpointSubset{
+				Addresses: addresses,
+				Ports:     ports,
+			},
+		},
+	}
+}
+
+func (w *RemoteClusterServiceWatcher) applyService(svc *v1.Service) error {
+	services := w.localAPI.Client.CoreV1().Services(svc.Namespace)
+	existing, err := services.Get(svc.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Infof("creating mirrored service %s/%s", svc.Namespace, svc.Name)
+		_, err = services.Create(svc)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !w.isMirror(existing.ObjectMeta) {
+		return fmt.Errorf("service %s/%s already exists and isn't mirrored from cluster %s", svc.Namespace, svc.Name, w.clusterName)
+	}
+
+	// The cluster IP and node ports assigned to the existing service are kept.
+	existing.Labels = svc.Labels
+	existing.Annotations = svc.Annotations
+	existing.Spec.Ports = svc.Spec.Ports
+	_, err = services.Update(existing)
+	return err
+}
+
+func (w *RemoteClusterServiceWatcher) applyEndpoints(endpoints *v1.Endpoints) error {
+	client := w.localAPI.Client.CoreV1().Endpoints(endpoints.Namespace)
+	existing, err := client.Get(endpoints.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(endpoints)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	existing.Labels = endpoints.Labels
+	existing.Annotations = endpoints.Annotations
+	existing.Subsets = endpoints.Subsets
+	_, err = client.Update(existing)
+	return err
+}
+
+// deleteMirror deletes the mirror of a remote Service that was deleted, and
+// its Endpoints.
+func (w *RemoteClusterServiceWatcher) deleteMirror(namespace, name string) error {
+	mirrorName := w.mirrorName(name)
+	existing, err := w.localAPI.Client.CoreV1().Services(namespace).Get(mirrorName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !w.isMirror(existing.ObjectMeta) {
+		return nil
+	}
+
+	log.Infof("deleting mirrored service %s/%s", namespace, mirrorName)
+	err = w.localAPI.Client.CoreV1().Services(namespace).Delete(mirrorName, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	err = w.localAPI.Client.CoreV1().Endpoints(namespace).Delete(mirrorName, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (w *RemoteClusterServiceWatcher) isMirror(meta metav1.ObjectMeta) bool {
+	return meta.Labels[pkgK8s.MirroredServiceLabel] == "true" &&
+		meta.Labels[pkgK8s.RemoteClusterNameLabel] == w.clusterName
+}
+
+// enqueueMirroredServices enqueues the remote Services of all the local
+// mirrored Services of the remote cluster, so that the mirrors of the remote
+// Services that no longer exist are deleted.
+func (w *RemoteClusterServiceWatcher) enqueueMirroredServices() error {
+	selector := labels.Set{
+		pkgK8s.MirroredServiceLabel:   "true",
+		pkgK8s.RemoteClusterNameLabel: w.clusterName,
+	}.AsSelector()
+	mirrors, err := w.localAPI.Svc().Lister().List(selector)
+	if err != nil {
+		return err
+	}
+
+	suffix := "-" + w.clusterName
+	for _, mirror := range mirrors {
+		if !strings.HasSuffix(mirror.Name, suffix) {
+			continue
+		}
+		remoteName := strings.TrimSuffix(mirror.Name, suffix)
+		w.queue.Add(fmt.Sprintf("%s/%s", mirror.Namespace, remoteName))
+	}
+	return nil
+}
+
+func (w *RemoteClusterServiceWatcher) handleRemoteService(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Errorf("failed to get key for remote service: %s", err)
+		return
+	}
+	log.Debugf("enqueuing sync of remote service %s", key)
+	w.queue.Add(key)
+}
@@ -0,0 +1,171 @@
+package servicemirror
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	remoteService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: books
+  namespace: bookapp
+  resourceVersion: "42"
+spec:
+  type: ClusterIP
+  ports:
+  - name: http
+    port: 7000
+    protocol: TCP`
+
+	localNamespace = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bookapp`
+
+	gateway = GatewayConfig{
+		Addresses: []string{"192.0.2.1", "192.0.2.2"},
+		Port:      4143,
+	}
+)
+
+func TestRemoteClusterServiceWatcher(t *testing.T) {
+	t.Run("mirrors remote services", func(t *testing.T) {
+		watcher, err := newWatcher([]string{remoteService}, []string{localNamespace})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = watcher.syncService("bookapp/books")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		svc, err := watcher.localAPI.Client.CoreV1().Services("bookapp").Get("books-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Mirrored service not found: %s", err)
+		}
+		if svc.Labels[pkgK8s.MirroredServiceLabel] != "true" || svc.Labels[pkgK8s.RemoteClusterNameLabel] != "east" {
+			t.Fatalf("Unexpected labels on the mirrored service: %v", svc.Labels)
+		}
+		if fqName := svc.Annotations[pkgK8s.RemoteServiceFqNameAnnotation]; fqName != "books.bookapp.svc.cluster.local" {
+			t.Fatalf("Expected remote name [books.bookapp.svc.cluster.local], got [%s]", fqName)
+		}
+		if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 7000 || svc.Spec.Ports[0].TargetPort != intstr.FromInt(4143) {
+			t.Fatalf("Unexpected ports on the mirrored service: %+v", svc.Spec.Ports)
+		}
+
+		endpoints, err := watcher.localAPI.Client.CoreV1().Endpoints("bookapp").Get("books-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Mirrored endpoints not found: %s", err)
+		}
+		addresses := []string{}
+		for _, address := range endpoints.Subsets[0].Addresses {
+			addresses = append(addresses, address.IP)
+		}
+		if !reflect.DeepEqual(addresses, gateway.Addresses) {
+			t.Fatalf("Expected endpoints %v, got %v", gateway.Addresses, addresses)
+		}
+		if port := endpoints.Subsets[0].Ports[0]; port.Name != "http" || port.Port != 4143 {
+			t.Fatalf("Unexpected endpoints port: %+v", port)
+		}
+	})
+
+	t.Run("deletes the mirrors of deleted remote services", func(t *testing.T) {
+		watcher, err := newWatcher([]string{}, []string{localNamespace, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: books-east
+  namespace: bookapp
+  labels:
+    mirror.linkerd.io/mirrored-service: "true"
+    mirror.linkerd.io/cluster-name: east`, `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: books-east
+  namespace: bookapp`})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = watcher.syncService("bookapp/books")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		_, err = watcher.localAPI.Client.CoreV1().Services("bookapp").Get("books-east", metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("Expected the mirrored service to be deleted, got: %v", err)
+		}
+		_, err = watcher.localAPI.Client.CoreV1().Endpoints("bookapp").Get("books-east", metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("Expected the mirrored endpoints to be deleted, got: %v", err)
+		}
+	})
+
+	t.Run("skips namespaces that don't exist locally", func(t *testing.T) {
+		watcher, err := newWatcher([]string{remoteService}, []string{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = watcher.syncService("bookapp/books")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		_, err = watcher.localAPI.Client.CoreV1().Services("bookapp").Get("books-east", metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("Expected no mirrored service, got: %v", err)
+		}
+	})
+
+	t.Run("doesn't overwrite services that aren't mirrors", func(t *testing.T) {
+		watcher, err := newWatcher([]string{remoteService}, []string{localNamespace, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: books-east
+  namespace: bookapp`})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = watcher.syncService("bookapp/books")
+		if err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+	})
+}
+
+func newWatcher(remoteConfigs, localConfigs []string) (*RemoteClusterServiceWatcher, error) {
+	remoteAPI, err := k8s.NewFakeAPI("", remoteConfigs...)
+	if err != nil {
+		return nil, fmt.Errorf("NewFakeAPI returned an error: %s", err)
+	}
+	localAPI, err := k8s.NewFakeAPI("", localConfigs...)
+	if err != nil {
+		return nil, fmt.Errorf("NewFakeAPI returned an error: %s", err)
+	}
+
+	watcher, err := NewRemoteClusterServiceWatcher("east", "cluster.local", gateway, remoteAPI, localAPI)
+	if err != nil {
+		return nil, fmt.Errorf("NewRemoteClusterServiceWatcher returned an error: %s", err)
+	}
+
+	remoteAPI.Sync()
+	localAPI.Sync()
+	return watcher, nil
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/linkerd/linkerd2/controller/webhook"
+)
+
+// WebhookServer is the webhook's HTTP server. It has an embedded validator
+// which validates all the requests.
+type WebhookServer struct {
+	*http.Server
+	*Validator
+}
+
+// NewWebhookServer returns a new instance of the WebhookServer. The server's
+// certificate is served by certs, so that it can be rotated without restarting
+// the server.
+func NewWebhookServer(addr string, certs *webhook.CertRotator) *WebhookServer {
+	server := &http.Server{
+		Addr: addr,
+		TLSConfig: &tls.Config{
+			GetCertificate: certs.GetCertificate,
+		},
+	}
+
+	ws := &WebhookServer{server, NewValidator()}
+	ws.Handler = http.HandlerFunc(ws.serve)
+	return ws
+}
+
+func (w *WebhookServer) serve(res http.ResponseWriter, req *http.Request) {
+	var (
+		data []byte
+		err  error
+	)
+	if req.Body != nil {
+		data, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if len(data) == 0 {
+		return
+	}
+
+	response := w.Validate(data)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := res.Write(responseJSON); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Shutdown initiates a graceful shutdown of the underlying HTTP server.
+func (w *WebhookServer) Shutdown() error {
+	return w.Server.Shutdown(context.Background())
+}
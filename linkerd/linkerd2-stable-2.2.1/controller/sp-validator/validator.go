@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/pkg/profiles"
+	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Validator is a Kubernetes validating admission webhook that rejects
+// ServiceProfiles the proxy would otherwise ignore, such as those with path
+// regexes that don't compile, duplicate route names, malformed response
+// classes, or invalid timeouts and retry budgets.
+type Validator struct{}
+
+// NewValidator returns a new instance of Validator.
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Validate decodes the given admission review, and returns it with a response
+// that admits the ServiceProfile it holds if, and only if, it's valid.
+func (v *Validator) Validate(data []byte) *admissionv1beta1.AdmissionReview {
+	admissionReview, err := v.decode(data)
+	if err != nil {
+		log.Error("failed to decode data. Reason: ", err)
+		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Message: err.Error(),
+			},
+		}
+		return admissionReview
+	}
+	log.Infof("received admission review request %s", admissionReview.Request.UID)
+	log.Debugf("admission request: %+v", admissionReview.Request)
+
+	admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+		UID:     admissionReview.Request.UID,
+		Allowed: true,
+	}
+
+	if err := v.validate(admissionReview.Request); err != nil {
+		log.Infof("rejecting %s/%s: %s", admissionReview.Request.Namespace, admissionReview.Request.Name, err)
+		admissionReview.Response.Allowed = false
+		admissionReview.Response.Result = &metav1.Status{
+			Message: err.Error(),
+		}
+	}
+
+	return admissionReview
+}
+
+func (v *Validator) decode(data []byte) (*admissionv1beta1.AdmissionReview, error) {
+	var admissionReview admissionv1beta1.AdmissionReview
+	if err := yaml.Unmarshal(data, &admissionReview); err != nil {
+		return &admissionReview, err
+	}
+	if admissionReview.Request == nil {
+		return &admissionReview, errors.New("admission review has no request")
+	}
+	return &admissionReview, nil
+}
+
+func (v *Validator) validate(request *admissionv1beta1.AdmissionRequest) error {
+	if request.Kind.Kind != profiles.ServiceProfileMeta.Kind {
+		log.Infof("skipping unsupported resource %s", request.Kind.Kind)
+		return nil
+	}
+
+	// The object is decoded leniently: metadata fields unknown to the vendored
+	// API types must not get a ServiceProfile rejected.
+	var profile sp.ServiceProfile
+	if err := json.Unmarshal(request.Object.Raw, &profile); err != nil {
+		return err
+	}
+
+	return profiles.ValidateServiceProfile(&profile)
+}
@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	log.SetOutput(ioutil.Discard)
+}
+
+func TestValidate(t *testing.T) {
+	var testCases = []struct {
+		title   string
+		kind    string
+		object  string
+		allowed bool
+		message string
+	}{
+		{
+			title: "valid profile",
+			kind:  "ServiceProfile",
+			object: `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+  creationTimestamp: null
+  managedFields: []
+spec:
+  routes:
+  - name: GET /books/{id}.json
+    condition:
+      method: GET
+      pathRegex: /books/[^/]*\.json
+    timeout: 100ms`,
+			allowed: true,
+		},
+		{
+			title: "invalid path regex",
+			kind:  "ServiceProfile",
+			object: `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+spec:
+  routes:
+  - name: GET /books/{id}.json
+    condition:
+      method: GET
+      pathRegex: /books/[^/*\.json`,
+			allowed: false,
+			message: "ServiceProfile \"books.booksapp.svc.cluster.local\" has a route with an invalid condition: Invalid pathRegex \"/books/[^/*\\.json\": error parsing regexp: missing closing ]: `[^/*\\.json)$`",
+		},
+		{
+			title: "duplicate route names",
+			kind:  "ServiceProfile",
+			object: `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+spec:
+  routes:
+  - name: books
+    condition:
+      method: GET
+  - name: books
+    condition:
+      method: POST`,
+			allowed: false,
+			message: "ServiceProfile \"books.booksapp.svc.cluster.local\" has multiple routes named \"books\"",
+		},
+		{
+			title: "malformed response class",
+			kind:  "ServiceProfile",
+			object: `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+spec:
+  routes:
+  - name: books
+    condition:
+      method: GET
+    responseClasses:
+    - condition:
+        status:
+          min: 500
+          max: 400
+      isFailure: true`,
+			allowed: false,
+			message: "ServiceProfile \"books.booksapp.svc.cluster.local\" has a response class with an invalid condition: Range maximum cannot be smaller than minimum",
+		},
+		{
+			title: "invalid timeout",
+			kind:  "ServiceProfile",
+			object: `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+spec:
+  routes:
+  - name: books
+    condition:
+      method: GET
+    timeout: -100ms`,
+			allowed: false,
+			message: "ServiceProfile \"books.booksapp.svc.cluster.local\" has a route with a non-positive timeout: -100ms",
+		},
+		{
+			title: "other kinds",
+			kind:  "ConfigMap",
+			object: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: books
+  namespace: booksapp`,
+			allowed: true,
+		},
+	}
+
+	validator := NewValidator()
+
+	for _, testCase := range testCases {
+		t.Run(testCase.title, func(t *testing.T) {
+			object, err := yaml.YAMLToJSON([]byte(testCase.object))
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			data, err := json.Marshal(&admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					UID:    "test-uid",
+					Kind:   metav1.GroupVersionKind{Kind: testCase.kind},
+					Object: runtime.RawExtension{Raw: object},
+				},
+			})
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			review := validator.Validate(data)
+			if review.Response.UID != "test-uid" {
+				t.Errorf("Expected response UID [test-uid], got [%s]", review.Response.UID)
+			}
+			if review.Response.Allowed != testCase.allowed {
+				t.Fatalf("Expected allowed to be %t, got %t (%+v)", testCase.allowed, review.Response.Allowed, review.Response.Result)
+			}
+			if !testCase.allowed && review.Response.Result.Message != testCase.message {
+				t.Fatalf("Expected message [%s], got [%s]", testCase.message, review.Response.Result.Message)
+			}
+		})
+	}
+
+	t.Run("without a request", func(t *testing.T) {
+		review := validator.Validate([]byte("{}"))
+		if review.Response.Allowed {
+			t.Fatal("Expected an admission review without a request to be rejected")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"text/template"
+
+	"github.com/linkerd/linkerd2/controller/sp-validator/tmpl"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// WebhookConfig creates the ValidatingWebhookConfiguration of the webhook.
+type WebhookConfig struct {
+	controllerNamespace string
+	webhookServiceName  string
+	configTemplate      *template.Template
+	k8sAPI              kubernetes.Interface
+}
+
+// NewWebhookConfig returns a new instance of WebhookConfig.
+func NewWebhookConfig(client kubernetes.Interface, controllerNamespace, webhookServiceName string) *WebhookConfig {
+	t := template.New(k8sPkg.SPValidatorWebhookConfig)
+
+	return &WebhookConfig{
+		controllerNamespace: controllerNamespace,
+		webhookServiceName:  webhookServiceName,
+		configTemplate:      template.Must(t.Parse(tmpl.ValidatingWebhookConfigurationSpec)),
+		k8sAPI:              client,
+	}
+}
+
+// CreateOrUpdate sends the request to either create or update the
+// ValidatingWebhookConfiguration resource, with trustAnchor as its CA bundle.
+func (w *WebhookConfig) CreateOrUpdate(trustAnchor []byte) (*arv1beta1.ValidatingWebhookConfiguration, error) {
+	config, err := w.render(trustAnchor)
+	if err != nil {
+		return nil, err
+	}
+
+	vwcs := w.k8sAPI.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	vwc, err := vwcs.Get(k8sPkg.SPValidatorWebhookConfig, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return vwcs.Create(config)
+	}
+
+	vwc.Webhooks = config.Webhooks
+	return vwcs.Update(vwc)
+}
+
+// Publish creates or updates the ValidatingWebhookConfiguration with
+// trustAnchor as its CA bundle. It's meant to be called by the webhook's
+// CertRotator.
+func (w *WebhookConfig) Publish(trustAnchor []byte) error {
+	vwc, err := w.CreateOrUpdate(trustAnchor)
+	if err != nil {
+		return err
+	}
+	log.Debugf("created or updated validating webhook configuration: %s", vwc.ObjectMeta.SelfLink)
+	return nil
+}
+
+func (w *WebhookConfig) render(trustAnchor []byte) (*arv1beta1.ValidatingWebhookConfiguration, error) {
+	var (
+		buf  = &bytes.Buffer{}
+		spec = struct {
+			WebhookConfigName   string
+			WebhookServiceName  string
+			ControllerNamespace string
+			CABundle            string
+		}{
+			WebhookConfigName:   k8sPkg.SPValidatorWebhookConfig,
+			WebhookServiceName:  w.webhookServiceName,
+			ControllerNamespace: w.controllerNamespace,
+			CABundle:            base64.StdEncoding.EncodeToString(trustAnchor),
+		}
+	)
+	if err := w.configTemplate.Execute(buf, spec); err != nil {
+		return nil, err
+	}
+
+	var config arv1beta1.ValidatingWebhookConfiguration
+	if err := yaml.Unmarshal(buf.Bytes(), &config); err != nil {
+		log.Infof("failed to unmarshal validating webhook configuration: %s\n%s\n", err, buf.String())
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"bytes"
+	"testing"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateOrUpdate(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	webhookConfig := NewWebhookConfig(client, "linkerd", "test.linkerd.io")
+
+	for i := 0; i < 2; i++ {
+		rootCA, err := tls.NewCA()
+		if err != nil {
+			t.Fatalf("failed to create root CA: %s", err)
+		}
+		trustAnchor := []byte(rootCA.TrustAnchorPEM())
+
+		if err := webhookConfig.Publish(trustAnchor); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		vwc, err := client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(k8sPkg.SPValidatorWebhookConfig, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if len(vwc.Webhooks) != 1 {
+			t.Fatalf("Expected 1 webhook, got %d", len(vwc.Webhooks))
+		}
+		if !bytes.Equal(vwc.Webhooks[0].ClientConfig.CABundle, trustAnchor) {
+			t.Errorf("CA bundle mismatch\nExpected: %s\nActual: %s", trustAnchor, vwc.Webhooks[0].ClientConfig.CABundle)
+		}
+		if rules := vwc.Webhooks[0].Rules; len(rules) != 1 || rules[0].Resources[0] != "serviceprofiles" {
+			t.Errorf("Expected the webhook to validate serviceprofiles, got %+v", rules)
+		}
+	}
+}
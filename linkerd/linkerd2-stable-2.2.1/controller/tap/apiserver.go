@@ -0,0 +1,164 @@
+package tap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
+	"github.com/linkerd/linkerd2/pkg/util"
+	"go.opencensus.io/plugin/ochttp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// extensionAPIServerAuthConfigMap is published by the Kubernetes API server
+	// and holds the CA and headers it uses when proxying requests to aggregated
+	// API servers.
+	extensionAPIServerAuthConfigMap = "extension-apiserver-authentication"
+	extensionAPIServerAuthNamespace = "kube-system"
+
+	requestHeaderClientCAKey        = "requestheader-client-ca-file"
+	requestHeaderAllowedNamesKey    = "requestheader-allowed-names"
+	requestHeaderUsernameHeadersKey = "requestheader-username-headers"
+	requestHeaderGroupHeadersKey    = "requestheader-group-headers"
+)
+
+type apiServer struct {
+	router       *httprouter.Router
+	allowedNames []string
+}
+
+// NewAPIServer creates a server that implements the tap.linkerd.io APIService.
+//
+// Requests are proxied to this server by the Kubernetes API server, which
+// authenticates the caller and passes its identity along in request headers.
+// The proxy's client certificate is verified against the request header CA
+// published in the extension-apiserver-authentication ConfigMap, and every tap
+// is then authorized with a SubjectAccessReview, so that RBAC and audit
+// logging apply to taps just like to any other Kubernetes API request.
+//
+// The serving certificate is returned by getCertificate, e.g. the one of a
+// webhook.CertRotator, so that it's renewed without restarting the server.
+func NewAPIServer(
+	addr string,
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error),
+	k8sAPI *k8s.API,
+	grpcTapServer pb.TapServer,
+) (*http.Server, net.Listener, error) {
+	cm, err := k8sAPI.Client.CoreV1().
+		ConfigMaps(extensionAPIServerAuthNamespace).
+		Get(extensionAPIServerAuthConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load [%s] config: %s", extensionAPIServerAuthConfigMap, err)
+	}
+
+	clientCAPem, ok := cm.Data[requestHeaderClientCAKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("no client CA cert available for apiextension-server")
+	}
+	clientCertPool := x509.NewCertPool()
+	if !clientCertPool.AppendCertsFromPEM([]byte(clientCAPem)) {
+		return nil, nil, fmt.Errorf("failed to parse client CA cert for apiextension-server")
+	}
+
+	allowedNames, err := parseStringList(cm.Data[requestHeaderAllowedNamesKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	usernameHeaders, err := parseStringList(cm.Data[requestHeaderUsernameHeadersKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	groupHeaders, err := parseStringList(cm.Data[requestHeaderGroupHeadersKey])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := &handler{
+		k8sAPI:          k8sAPI,
+		usernameHeaders: usernameHeaders,
+		groupHeaders:    groupHeaders,
+		grpcTapServer:   grpcTapServer,
+	}
+
+	server := &apiServer{
+		router:       newRouter(h),
+		allowedNames: allowedNames,
+	}
+
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: util.WithRequestID(&ochttp.Handler{Handler: server}),
+		TLSConfig: &tls.Config{
+			GetCertificate: getCertificate,
+			ClientAuth:     tls.VerifyClientCertIfGiven,
+			ClientCAs:      clientCertPool,
+		},
+	}
+
+	return httpServer, lis, nil
+}
+
+func (a *apiServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := a.validate(req); err != nil {
+		util.RequestLogger(req).Debugf("rejecting request from %s: %s", req.RemoteAddr, err)
+		protohttp.WriteErrorToHTTPResponse(w, protohttp.HTTPError{
+			Code:         http.StatusUnauthorized,
+			WrappedError: err,
+		})
+		return
+	}
+
+	a.router.ServeHTTP(w, req)
+}
+
+// validate ensures that the request was proxied by the Kubernetes API server,
+// by verifying the client certificate it presented. Only requests that pass
+// this check may be trusted to carry the caller's identity in their headers.
+func (a *apiServer) validate(req *http.Request) error {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return errors.New("no valid client certificate presented")
+	}
+
+	if len(a.allowedNames) == 0 {
+		return nil
+	}
+
+	commonName := req.TLS.VerifiedChains[0][0].Subject.CommonName
+	for _, name := range a.allowedNames {
+		if name == commonName {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("client certificate common name [%s] is not allowed", commonName)
+}
+
+// parseStringList decodes the JSON-encoded string lists found in the
+// extension-apiserver-authentication ConfigMap. Missing values are treated
+// as empty lists.
+func parseStringList(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse [%s] as a list of strings: %s", value, err)
+	}
+
+	return list, nil
+}
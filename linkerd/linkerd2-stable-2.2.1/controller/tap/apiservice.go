@@ -0,0 +1,142 @@
+package tap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"text/template"
+
+	"github.com/linkerd/linkerd2/controller/tap/tmpl"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// apiServicesPath is the path of the APIService collection. The
+// apiregistration API is not part of the typed client, so its resources are
+// managed through the raw REST client.
+const apiServicesPath = "/apis/apiregistration.k8s.io/v1beta1/apiservices"
+
+// APIServiceConfig creates the APIService through which the Kubernetes API
+// server proxies tap requests to the tap controller.
+type APIServiceConfig struct {
+	controllerNamespace string
+	trustAnchor         []byte
+	configTemplate      *template.Template
+	restClient          rest.Interface
+}
+
+// NewAPIServiceConfig returns a new instance of APIServiceConfig.
+func NewAPIServiceConfig(client kubernetes.Interface, controllerNamespace string) *APIServiceConfig {
+	t := template.New(pkgK8s.TapAPIService)
+
+	return &APIServiceConfig{
+		controllerNamespace: controllerNamespace,
+		configTemplate:      template.Must(t.Parse(tmpl.APIServiceSpec)),
+		restClient:          client.Discovery().RESTClient(),
+	}
+}
+
+// CreateOrUpdate sends the request to either create or update the APIService
+// resource, with trustAnchor as its CA bundle. During an update, only the CA
+// bundle is changed.
+func (a *APIServiceConfig) CreateOrUpdate(trustAnchor []byte) error {
+	a.trustAnchor = trustAnchor
+
+	exist, err := a.exist()
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return a.create()
+	}
+
+	return a.update()
+}
+
+// Publish creates or updates the APIService with trustAnchor as its CA
+// bundle. It's meant to be called by the CertRotator of the tap APIService's
+// serving certificate.
+func (a *APIServiceConfig) Publish(trustAnchor []byte) error {
+	if err := a.CreateOrUpdate(trustAnchor); err != nil {
+		return err
+	}
+	log.Debugf("created or updated APIService: %s", pkgK8s.TapAPIService)
+	return nil
+}
+
+// exist returns true if the APIService exists. Otherwise, it returns false.
+func (a *APIServiceConfig) exist() (bool, error) {
+	err := a.restClient.Get().
+		AbsPath(apiServicesPath, pkgK8s.TapAPIService).
+		Do().
+		Error()
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (a *APIServiceConfig) create() error {
+	var (
+		buf  = &bytes.Buffer{}
+		spec = struct {
+			APIServiceName      string
+			Group               string
+			Version             string
+			ServiceName         string
+			ControllerNamespace string
+			CABundle            string
+		}{
+			APIServiceName:      pkgK8s.TapAPIService,
+			Group:               pkgK8s.TapAPIGroup,
+			Version:             pkgK8s.TapAPIVersion,
+			ServiceName:         pkgK8s.TapServiceName,
+			ControllerNamespace: a.controllerNamespace,
+			CABundle:            base64.StdEncoding.EncodeToString(a.trustAnchor),
+		}
+	)
+	if err := a.configTemplate.Execute(buf, spec); err != nil {
+		return err
+	}
+
+	body, err := yaml.YAMLToJSON(buf.Bytes())
+	if err != nil {
+		log.Infof("failed to convert APIService to JSON: %s\n%s\n", err, buf.String())
+		return err
+	}
+
+	return a.restClient.Post().
+		AbsPath(apiServicesPath).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do().
+		Error()
+}
+
+func (a *APIServiceConfig) update() error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"caBundle": a.trustAnchor,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.restClient.Patch(types.MergePatchType).
+		AbsPath(apiServicesPath, pkgK8s.TapAPIService).
+		Body(patch).
+		Do().
+		Error()
+}
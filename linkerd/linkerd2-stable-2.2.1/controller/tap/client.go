@@ -1,16 +1,143 @@
 package tap
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
 	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
+	"github.com/linkerd/linkerd2/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // NewClient creates a client for the control-plane's Tap service.
 func NewClient(addr string) (pb.TapClient, *grpc.ClientConn, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	conn, err := grpc.Dial(addr,
+		grpc.WithInsecure(),
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		grpc.WithUnaryInterceptor(util.RequestIDUnaryClientInterceptor),
+		grpc.WithStreamInterceptor(util.RequestIDStreamClientInterceptor),
+	)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return pb.NewTapClient(conn), conn, nil
 }
+
+type apiClient struct {
+	serverURL  string
+	httpClient *http.Client
+}
+
+// NewAPIClient creates a client for the tap APIService, served through the
+// Kubernetes API aggregation layer. Requests are authenticated with the
+// credentials in kubeAPI's config, and authorized by the tap controller.
+func NewAPIClient(kubeAPI *k8s.KubernetesAPI) (pb.TapClient, error) {
+	httpClient, err := kubeAPI.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		serverURL:  strings.TrimSuffix(kubeAPI.Host, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+func (c *apiClient) Tap(ctx context.Context, req *public.TapRequest, _ ...grpc.CallOption) (pb.Tap_TapClient, error) {
+	return nil, status.Error(codes.Unimplemented, "Tap is deprecated, use TapByResource")
+}
+
+func (c *apiClient) TapByResource(ctx context.Context, req *public.TapByResourceRequest, _ ...grpc.CallOption) (pb.Tap_TapByResourceClient, error) {
+	path, err := TapPath(req.GetTarget().GetResource())
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.serverURL + path
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	if id := util.RequestID(ctx); id != "" {
+		httpReq.Header.Set(util.RequestIDHeader, id)
+	}
+
+	log.Debugf("Making tap request to [%s] [%+v]", url, req)
+	httpRsp, err := c.httpClient.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if httpRsp.StatusCode == http.StatusNotFound {
+		httpRsp.Body.Close()
+		return nil, fmt.Errorf("tap APIService [%s] is not available; check that the control plane was installed with cluster-wide permissions", k8s.TapAPIService)
+	}
+
+	if err := protohttp.CheckIfResponseHasError(httpRsp); err != nil {
+		httpRsp.Body.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Debug("Closing response body after context marked as done")
+		httpRsp.Body.Close()
+	}()
+
+	return &tapByResourceClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body), header: util.ResponseMetadata(httpRsp.Header)}, nil
+}
+
+// TapPath returns the path of the tap subresource of the given target in the
+// tap APIService.
+func TapPath(resource *public.Resource) (string, error) {
+	plural, err := k8s.PluralResourceNameFromFriendlyName(resource.GetType())
+	if err != nil {
+		return "", err
+	}
+
+	root := fmt.Sprintf("/apis/%s/%s/watch/namespaces", k8s.TapAPIGroup, k8s.TapAPIVersion)
+	if plural == "namespaces" {
+		return fmt.Sprintf("%s/%s/tap", root, resource.GetName()), nil
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s/tap", root, resource.GetNamespace(), plural, resource.GetName()), nil
+}
+
+type tapByResourceClient struct {
+	ctx    context.Context
+	reader *bufio.Reader
+	header metadata.MD
+}
+
+func (c tapByResourceClient) Recv() (*public.TapEvent, error) {
+	var msg public.TapEvent
+	err := protohttp.FromByteStreamToProtocolBuffers(c.reader, &msg)
+	return &msg, err
+}
+
+// satisfy the pb.Tap_TapByResourceClient interface
+func (c tapByResourceClient) Header() (metadata.MD, error) { return c.header, nil }
+func (c tapByResourceClient) Trailer() metadata.MD         { return nil }
+func (c tapByResourceClient) CloseSend() error             { return nil }
+func (c tapByResourceClient) Context() context.Context     { return c.ctx }
+func (c tapByResourceClient) SendMsg(interface{}) error    { return nil }
+func (c tapByResourceClient) RecvMsg(interface{}) error    { return nil }
@@ -0,0 +1,282 @@
+package tap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/protohttp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+	authV1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tapVerb is the RBAC verb callers must be granted on the tap subresource of
+// a target in order to tap it.
+const tapVerb = "watch"
+
+var (
+	apiRoot = fmt.Sprintf("/apis/%s/%s", pkgK8s.TapAPIGroup, pkgK8s.TapAPIVersion)
+
+	// tapResourceTypes are the resource types that may be tapped, and thus have
+	// a tap subresource in the APIService.
+	tapResourceTypes = []string{
+		pkgK8s.DaemonSet,
+		pkgK8s.Deployment,
+		pkgK8s.Namespace,
+		pkgK8s.Pod,
+		pkgK8s.ReplicationController,
+		pkgK8s.StatefulSet,
+	}
+)
+
+type handler struct {
+	k8sAPI          *k8s.API
+	usernameHeaders []string
+	groupHeaders    []string
+	grpcTapServer   pb.TapServer
+}
+
+func newRouter(h *handler) *httprouter.Router {
+	router := &httprouter.Router{}
+	router.GET(apiRoot, h.handleAPIResourceList)
+	router.POST(apiRoot+"/watch/namespaces/*target", h.handleTap)
+	return router
+}
+
+// handleAPIResourceList serves the discovery document for the APIService, so
+// that the tap subresources show up in `kubectl api-resources` and can be
+// referenced from RBAC rules.
+func (h *handler) handleAPIResourceList(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	resources := []metav1.APIResource{}
+	for _, resourceType := range tapResourceTypes {
+		plural, err := pkgK8s.PluralResourceNameFromFriendlyName(resourceType)
+		if err != nil {
+			protohttp.WriteErrorToHTTPResponse(w, err)
+			return
+		}
+
+		resources = append(resources,
+			metav1.APIResource{
+				Name:       plural,
+				Namespaced: resourceType != pkgK8s.Namespace,
+				Kind:       resourceType,
+				Verbs:      metav1.Verbs{tapVerb},
+			},
+			metav1.APIResource{
+				Name:       plural + "/tap",
+				Namespaced: resourceType != pkgK8s.Namespace,
+				Kind:       "Tap",
+				Verbs:      metav1.Verbs{tapVerb},
+			},
+		)
+	}
+
+	list := metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: fmt.Sprintf("%s/%s", pkgK8s.TapAPIGroup, pkgK8s.TapAPIVersion),
+		APIResources: resources,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		log.Errorf("error writing APIResourceList: %s", err)
+	}
+}
+
+// handleTap serves POST requests of the form:
+//
+//	/apis/tap.linkerd.io/v1alpha1/watch/namespaces/:namespace/tap
+//	/apis/tap.linkerd.io/v1alpha1/watch/namespaces/:namespace/:resource/:name/tap
+//
+// with a protobuf-encoded TapByResourceRequest as the body. The target in the
+// body must match the one in the path, which is what the caller is authorized
+// against.
+func (h *handler) handleTap(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	target, err := parseTapTarget(p.ByName("target"))
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, protohttp.HTTPError{
+			Code:         http.StatusNotFound,
+			WrappedError: err,
+		})
+		return
+	}
+
+	tapReq := public.TapByResourceRequest{}
+	err = protohttp.HTTPRequestToProto(req, &tapReq)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+
+	if !sameResource(target, tapReq.GetTarget().GetResource()) {
+		protohttp.WriteErrorToHTTPResponse(w, protohttp.HTTPError{
+			Code:         http.StatusBadRequest,
+			WrappedError: fmt.Errorf("tap target %+v does not match request path", tapReq.GetTarget().GetResource()),
+		})
+		return
+	}
+
+	err = h.authorize(req, target)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, protohttp.HTTPError{
+			Code:         http.StatusForbidden,
+			WrappedError: err,
+		})
+		return
+	}
+
+	flushableWriter, err := protohttp.NewStreamingWriter(w)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(w, err)
+		return
+	}
+
+	serverStream := tapServer{w: flushableWriter, req: req}
+	err = h.grpcTapServer.TapByResource(&tapReq, serverStream)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(flushableWriter, err)
+		return
+	}
+}
+
+// authorize issues a SubjectAccessReview for the user the Kubernetes API
+// server authenticated, checking whether it may watch the tap subresource of
+// target.
+func (h *handler) authorize(req *http.Request, target *public.Resource) error {
+	user := ""
+	for _, header := range h.usernameHeaders {
+		if user = req.Header.Get(header); user != "" {
+			break
+		}
+	}
+	if user == "" {
+		return fmt.Errorf("tap request is missing the username header")
+	}
+
+	groups := []string{}
+	for _, header := range h.groupHeaders {
+		groups = append(groups, req.Header[http.CanonicalHeaderKey(header)]...)
+	}
+
+	plural, err := pkgK8s.PluralResourceNameFromFriendlyName(target.GetType())
+	if err != nil {
+		return err
+	}
+
+	sar := &authV1.SubjectAccessReview{
+		Spec: authV1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: groups,
+			ResourceAttributes: &authV1.ResourceAttributes{
+				Namespace:   target.GetNamespace(),
+				Verb:        tapVerb,
+				Group:       pkgK8s.TapAPIGroup,
+				Version:     pkgK8s.TapAPIVersion,
+				Resource:    plural,
+				Subresource: "tap",
+				Name:        target.GetName(),
+			},
+		},
+	}
+
+	rsp, err := h.k8sAPI.Client.AuthorizationV1().SubjectAccessReviews().Create(sar)
+	if err != nil {
+		return err
+	}
+
+	if !rsp.Status.Allowed {
+		reason := rsp.Status.Reason
+		if reason == "" {
+			reason = "not allowed"
+		}
+		return fmt.Errorf("tap authorization failed for user [%s] on %s/%s: %s", user, target.GetType(), target.GetName(), reason)
+	}
+
+	return nil
+}
+
+// parseTapTarget parses the part of a tap path that follows
+// `/watch/namespaces/` into the resource to be tapped.
+func parseTapTarget(path string) (*public.Resource, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case len(segments) == 2 && segments[1] == "tap" && segments[0] != "":
+		return &public.Resource{
+			Namespace: segments[0],
+			Type:      pkgK8s.Namespace,
+			Name:      segments[0],
+		}, nil
+
+	case len(segments) == 4 && segments[3] == "tap" && segments[0] != "" && segments[2] != "":
+		resourceType, err := pkgK8s.CanonicalResourceNameFromFriendlyName(segments[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return &public.Resource{
+			Namespace: segments[0],
+			Type:      resourceType,
+			Name:      segments[2],
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid tap path: %s", path)
+	}
+}
+
+// sameResource compares the resource named in a tap path with the one in the
+// request body. Namespace targets are identified by name only.
+func sameResource(fromPath, fromBody *public.Resource) bool {
+	if fromBody == nil {
+		return false
+	}
+
+	bodyType, err := pkgK8s.CanonicalResourceNameFromFriendlyName(fromBody.GetType())
+	if err != nil || bodyType != fromPath.GetType() {
+		return false
+	}
+
+	if bodyType == pkgK8s.Namespace {
+		return fromBody.GetName() == fromPath.GetName()
+	}
+
+	return fromBody.GetNamespace() == fromPath.GetNamespace() &&
+		fromBody.GetName() == fromPath.GetName()
+}
+
+type tapServer struct {
+	w   protohttp.FlushableResponseWriter
+	req *http.Request
+}
+
+func (s tapServer) Send(msg *public.TapEvent) error {
+	err := protohttp.WriteProtoToHTTPResponse(s.w, msg)
+	if err != nil {
+		protohttp.WriteErrorToHTTPResponse(s.w, err)
+		return err
+	}
+
+	s.w.Flush()
+	return nil
+}
+
+// satisfy the pb.Tap_TapByResourceServer interface
+func (s tapServer) SetHeader(metadata.MD) error  { return nil }
+func (s tapServer) SendHeader(metadata.MD) error { return nil }
+func (s tapServer) SetTrailer(metadata.MD)       {}
+func (s tapServer) Context() context.Context     { return s.req.Context() }
+func (s tapServer) SendMsg(interface{}) error    { return nil }
+func (s tapServer) RecvMsg(interface{}) error    { return nil }
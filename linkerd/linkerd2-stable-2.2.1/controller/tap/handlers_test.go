@@ -0,0 +1,87 @@
+package tap
+
+import (
+	"reflect"
+	"testing"
+
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestParseTapTarget(t *testing.T) {
+	t.Run("Parses valid tap paths", func(t *testing.T) {
+		expectations := map[string]*public.Resource{
+			"/emojivoto/tap": &public.Resource{
+				Namespace: "emojivoto",
+				Type:      pkgK8s.Namespace,
+				Name:      "emojivoto",
+			},
+			"/emojivoto/deployments/web/tap": &public.Resource{
+				Namespace: "emojivoto",
+				Type:      pkgK8s.Deployment,
+				Name:      "web",
+			},
+			"/emojivoto/pods/web-6b4d5c8f4d-x7g2l/tap": &public.Resource{
+				Namespace: "emojivoto",
+				Type:      pkgK8s.Pod,
+				Name:      "web-6b4d5c8f4d-x7g2l",
+			},
+		}
+
+		for path, exp := range expectations {
+			res, err := parseTapTarget(path)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing [%s]: %s", path, err)
+			}
+			if !reflect.DeepEqual(res, exp) {
+				t.Fatalf("Expected [%s] to parse as %+v, got %+v", path, exp, res)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid tap paths", func(t *testing.T) {
+		paths := []string{
+			"/",
+			"/emojivoto",
+			"/emojivoto/deployments/web",
+			"/emojivoto/deployments/web/logs",
+			"/emojivoto/widgets/web/tap",
+			"//deployments/web/tap",
+		}
+
+		for _, path := range paths {
+			if _, err := parseTapTarget(path); err == nil {
+				t.Fatalf("Expected error parsing [%s], got nil", path)
+			}
+		}
+	})
+}
+
+func TestSameResource(t *testing.T) {
+	fromPath := &public.Resource{
+		Namespace: "emojivoto",
+		Type:      pkgK8s.Deployment,
+		Name:      "web",
+	}
+
+	if !sameResource(fromPath, &public.Resource{Namespace: "emojivoto", Type: "deploy", Name: "web"}) {
+		t.Fatalf("Expected resources to match")
+	}
+
+	if sameResource(fromPath, &public.Resource{Namespace: "default", Type: pkgK8s.Deployment, Name: "web"}) {
+		t.Fatalf("Expected resources in different namespaces not to match")
+	}
+
+	if sameResource(fromPath, &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Pod, Name: "web"}) {
+		t.Fatalf("Expected resources of different types not to match")
+	}
+
+	if sameResource(fromPath, nil) {
+		t.Fatalf("Expected nil resource not to match")
+	}
+
+	namespace := &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Namespace, Name: "emojivoto"}
+	if !sameResource(namespace, &public.Resource{Type: pkgK8s.Namespace, Name: "emojivoto"}) {
+		t.Fatalf("Expected namespace resources to match by name")
+	}
+}
@@ -0,0 +1,357 @@
+package tap
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// routeLabel is the RouteMeta label that holds the name of the
+	// ServiceProfile route a request matched.
+	routeLabel = "route"
+
+	// streamRouteTTL is how long the route of a stream is remembered when the
+	// end of its response isn't observed, e.g. because the stream was reset
+	// before a response, or the tap reached its limit of requests.
+	streamRouteTTL = time.Minute
+)
+
+// streamID identifies an HTTP stream observed by a single proxy.
+type streamID struct {
+	base   uint32
+	stream uint64
+}
+
+func toStreamID(id *public.TapEvent_Http_StreamId) streamID {
+	return streamID{base: id.GetBase(), stream: id.GetStream()}
+}
+
+// streamRoutes remembers the route of each stream whose request was observed
+// by a tap, until its response ends, or for streamRouteTTL at most.
+type streamRoutes struct {
+	routes    map[streamID]streamRoute
+	lastSweep time.Time
+}
+
+type streamRoute struct {
+	name    string
+	expires time.Time
+}
+
+func newStreamRoutes() *streamRoutes {
+	return &streamRoutes{routes: map[streamID]streamRoute{}, lastSweep: time.Now()}
+}
+
+// set remembers the route of a stream, first forgetting the expired routes if
+// they haven't been swept for streamRouteTTL.
+func (r *streamRoutes) set(id streamID, route string, now time.Time) {
+	if now.Sub(r.lastSweep) >= streamRouteTTL {
+		for remembered, sr := range r.routes {
+			if now.After(sr.expires) {
+				delete(r.routes, remembered)
+			}
+		}
+		r.lastSweep = now
+	}
+	r.routes[id] = streamRoute{name: route, expires: now.Add(streamRouteTTL)}
+}
+
+func (r *streamRoutes) get(id streamID) string {
+	return r.routes[id].name
+}
+
+func (r *streamRoutes) forget(id streamID) {
+	delete(r.routes, id)
+}
+
+// annotateRoute sets the route label of ev, when the proxy didn't report one,
+// to the name of the ServiceProfile route its request matches. Routes are
+// resolved from RequestInit events and remembered in routes, keyed by stream,
+// so that the response events of a stream are labeled like its request.
+// namespace is the namespace of the pod whose proxy observed ev.
+func (s *server) annotateRoute(ev *public.TapEvent, namespace string, routes *streamRoutes) {
+	route := ev.GetRouteMeta().GetLabels()[routeLabel]
+
+	switch event := ev.GetHttp().GetEvent().(type) {
+	case *public.TapEvent_Http_RequestInit_:
+		if route == "" {
+			route = s.routeForRequest(ev, namespace, event.RequestInit)
+		}
+		if route != "" {
+			routes.set(toStreamID(event.RequestInit.GetId()), route, time.Now())
+		}
+
+	case *public.TapEvent_Http_ResponseInit_:
+		if route == "" {
+			route = routes.get(toStreamID(event.ResponseInit.GetId()))
+		}
+
+	case *public.TapEvent_Http_ResponseEnd_:
+		id := toStreamID(event.ResponseEnd.GetId())
+		if route == "" {
+			route = routes.get(id)
+		}
+		routes.forget(id)
+	}
+
+	if route == "" {
+		return
+	}
+
+	if ev.RouteMeta == nil {
+		ev.RouteMeta = &public.TapEvent_RouteMeta{}
+	}
+	if ev.RouteMeta.Labels == nil {
+		ev.RouteMeta.Labels = map[string]string{}
+	}
+	ev.RouteMeta.Labels[routeLabel] = route
+}
+
+// routeForRequest finds the ServiceProfile of the service a request was sent
+// to, looking it up the same way the destination service does for proxies,
+// and returns the name of the route the request matches, if any.
+func (s *server) routeForRequest(ev *public.TapEvent, namespace string, req *public.TapEvent_Http_RequestInit) string {
+	if s.singleNamespace {
+		return ""
+	}
+
+	// Profiles are looked up from the client's point of view. The proxy of an
+	// outbound request belongs to the client; an inbound request's client is
+	// its source.
+	clientNs := namespace
+	if ev.GetProxyDirection() == public.TapEvent_INBOUND {
+		clientNs = ""
+		pod, err := s.podForIP(ev.GetSource().GetIp())
+		if err == nil && pod != nil {
+			clientNs = pod.Namespace
+		}
+	}
+
+	name, svcNs, ok := serviceForAuthority(req.GetAuthority(), clientNs)
+	if !ok {
+		return ""
+	}
+
+	svc, err := s.k8sAPI.Svc().Lister().Services(svcNs).Get(name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Warnf("error getting service %s/%s: %s", svcNs, name, err)
+		}
+		return ""
+	}
+
+	profile := s.k8sAPI.GetServiceProfileFor(svc, clientNs)
+	method, path := httpMethod(req.GetMethod()), req.GetPath()
+	for _, route := range s.profiles.routesFor(profile) {
+		if route.condition.matches(method, path) {
+			return route.name
+		}
+	}
+
+	return ""
+}
+
+// compiledProfiles caches the compiled routes of the ServiceProfiles, so that
+// the path regexes of a profile are compiled once per version of it, rather
+// than for every tap event.
+type compiledProfiles struct {
+	sync.Mutex
+	profiles map[string]*compiledProfile
+}
+
+// compiledProfile holds the compiled routes of a version of a ServiceProfile.
+type compiledProfile struct {
+	resourceVersion string
+	routes          []*compiledRoute
+}
+
+func newCompiledProfiles() *compiledProfiles {
+	return &compiledProfiles{profiles: map[string]*compiledProfile{}}
+}
+
+// routesFor returns the compiled routes of profile, compiling them unless they
+// are cached for its version already. A profile with an invalid route has no
+// routes.
+func (c *compiledProfiles) routesFor(profile *sp.ServiceProfile) []*compiledRoute {
+	if len(profile.Spec.Routes) == 0 {
+		// e.g. the default profile of a service without one
+		return nil
+	}
+
+	key := profile.Namespace + "/" + profile.Name
+	c.Lock()
+	defer c.Unlock()
+
+	cached, ok := c.profiles[key]
+	if !ok || cached.resourceVersion != profile.ResourceVersion {
+		routes, err := compileRoutes(profile)
+		if err != nil {
+			log.Warnf("error compiling the routes of ServiceProfile %s: %s", key, err)
+		}
+		cached = &compiledProfile{resourceVersion: profile.ResourceVersion, routes: routes}
+		c.profiles[key] = cached
+	}
+	return cached.routes
+}
+
+// forget drops the compiled routes of a deleted ServiceProfile.
+func (c *compiledProfiles) forget(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Errorf("error getting the key of a deleted ServiceProfile: %s", err)
+		return
+	}
+
+	c.Lock()
+	delete(c.profiles, key)
+	c.Unlock()
+}
+
+// compiledRoute is a ServiceProfile route whose condition is compiled.
+type compiledRoute struct {
+	name      string
+	condition *requestMatcher
+}
+
+// requestMatcher is a compiled ServiceProfile request match.
+type requestMatcher struct {
+	all       []*requestMatcher
+	any       []*requestMatcher
+	not       *requestMatcher
+	pathRegex *regexp.Regexp
+	method    string
+}
+
+// compileRoutes compiles the routes of profile, in order, failing if any of
+// them is invalid.
+func compileRoutes(profile *sp.ServiceProfile) ([]*compiledRoute, error) {
+	routes := make([]*compiledRoute, len(profile.Spec.Routes))
+	for i, route := range profile.Spec.Routes {
+		condition, err := compileRequestMatch(route.Condition)
+		if err != nil {
+			return nil, err
+		}
+		routes[i] = &compiledRoute{name: route.Name, condition: condition}
+	}
+	return routes, nil
+}
+
+func compileRequestMatch(reqMatch *sp.RequestMatch) (*requestMatcher, error) {
+	if reqMatch == nil {
+		return nil, errors.New("missing request match")
+	}
+	if reqMatch.All == nil && reqMatch.Any == nil && reqMatch.Not == nil && reqMatch.PathRegex == "" && reqMatch.Method == "" {
+		return nil, errors.New("a request match must have a field set")
+	}
+
+	m := &requestMatcher{method: reqMatch.Method}
+	for _, child := range reqMatch.All {
+		compiled, err := compileRequestMatch(child)
+		if err != nil {
+			return nil, err
+		}
+		m.all = append(m.all, compiled)
+	}
+	if reqMatch.Any != nil {
+		m.any = make([]*requestMatcher, len(reqMatch.Any))
+		for i, child := range reqMatch.Any {
+			compiled, err := compileRequestMatch(child)
+			if err != nil {
+				return nil, err
+			}
+			m.any[i] = compiled
+		}
+	}
+	if reqMatch.Not != nil {
+		compiled, err := compileRequestMatch(reqMatch.Not)
+		if err != nil {
+			return nil, err
+		}
+		m.not = compiled
+	}
+	if reqMatch.PathRegex != "" {
+		// as in the proxy, a path regex must match the whole path, not just a
+		// substring of it
+		re, err := regexp.Compile("^(?:" + reqMatch.PathRegex + ")$")
+		if err != nil {
+			return nil, err
+		}
+		m.pathRegex = re
+	}
+	return m, nil
+}
+
+// matches returns true if a request with the given method and path satisfies
+// every field of the match.
+func (m *requestMatcher) matches(method, path string) bool {
+	for _, child := range m.all {
+		if !child.matches(method, path) {
+			return false
+		}
+	}
+
+	if m.any != nil {
+		matchesAny := false
+		for _, child := range m.any {
+			if child.matches(method, path) {
+				matchesAny = true
+				break
+			}
+		}
+		if !matchesAny {
+			return false
+		}
+	}
+
+	if m.not != nil && m.not.matches(method, path) {
+		return false
+	}
+	if m.pathRegex != nil && !m.pathRegex.MatchString(path) {
+		return false
+	}
+	return m.method == "" || m.method == method
+}
+
+// serviceForAuthority returns the name and namespace of the Kubernetes service
+// an authority refers to. Unqualified names are resolved relative to
+// clientNs.
+func serviceForAuthority(authority, clientNs string) (string, string, bool) {
+	host, _, err := net.SplitHostPort(authority)
+	if err != nil {
+		host = authority
+	}
+	host = strings.TrimSuffix(strings.TrimSuffix(host, "."), ".svc.cluster.local")
+	host = strings.TrimSuffix(host, ".svc")
+
+	labels := strings.Split(host, ".")
+	switch {
+	case len(labels) == 1 && labels[0] != "" && clientNs != "":
+		return labels[0], clientNs, true
+	case len(labels) == 2 && labels[0] != "" && labels[1] != "":
+		return labels[0], labels[1], true
+	default:
+		return "", "", false
+	}
+}
+
+// httpMethod returns the name of an HTTP method, as used in ServiceProfile
+// request matches.
+func httpMethod(method *public.HttpMethod) string {
+	if method == nil {
+		return ""
+	}
+	if unregistered := method.GetUnregistered(); unregistered != "" {
+		return unregistered
+	}
+	return method.GetRegistered().String()
+}
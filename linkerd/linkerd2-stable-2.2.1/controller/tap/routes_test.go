@@ -0,0 +1,257 @@
+package tap
+
+import (
+	"testing"
+	"time"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceForAuthority(t *testing.T) {
+	expectations := []struct {
+		authority string
+		name      string
+		namespace string
+		ok        bool
+	}{
+		{authority: "books.booksapp.svc.cluster.local:7002", name: "books", namespace: "booksapp", ok: true},
+		{authority: "books.booksapp.svc.cluster.local.", name: "books", namespace: "booksapp", ok: true},
+		{authority: "books.booksapp.svc:7002", name: "books", namespace: "booksapp", ok: true},
+		{authority: "books.booksapp", name: "books", namespace: "booksapp", ok: true},
+		{authority: "books:7002", name: "books", namespace: "client-ns", ok: true},
+		{authority: "www.example.com:443", ok: false},
+		{authority: "10.1.2.3:7002", ok: false},
+		{authority: "", ok: false},
+	}
+
+	for _, exp := range expectations {
+		name, namespace, ok := serviceForAuthority(exp.authority, "client-ns")
+		if ok != exp.ok || name != exp.name || namespace != exp.namespace {
+			t.Fatalf("Expected [%s] to resolve to (%s, %s, %t), got (%s, %s, %t)",
+				exp.authority, exp.name, exp.namespace, exp.ok, name, namespace, ok)
+		}
+	}
+}
+
+func TestAnnotateRoute(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: books
+  namespace: booksapp
+spec:
+  ports:
+  - name: service
+    port: 7002
+`, `
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: books.booksapp.svc.cluster.local
+  namespace: booksapp
+spec:
+  routes:
+  - name: GET /books/{id}.json
+    condition:
+      method: GET
+      pathRegex: /books/[^/]*\.json
+`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	k8sAPI.Sync()
+
+	s := &server{k8sAPI: k8sAPI, profiles: newCompiledProfiles()}
+	routes := newStreamRoutes()
+	id := &public.TapEvent_Http_StreamId{Base: 1, Stream: 2}
+
+	reqInit := &public.TapEvent{
+		ProxyDirection: public.TapEvent_OUTBOUND,
+		RouteMeta:      &public.TapEvent_RouteMeta{},
+		Event: &public.TapEvent_Http_{
+			Http: &public.TapEvent_Http{
+				Event: &public.TapEvent_Http_RequestInit_{
+					RequestInit: &public.TapEvent_Http_RequestInit{
+						Id:        id,
+						Method:    &public.HttpMethod{Type: &public.HttpMethod_Registered_{Registered: public.HttpMethod_GET}},
+						Authority: "books.booksapp.svc.cluster.local:7002",
+						Path:      "/books/1234.json",
+					},
+				},
+			},
+		},
+	}
+	s.annotateRoute(reqInit, "webapp", routes)
+	if route := reqInit.GetRouteMeta().GetLabels()[routeLabel]; route != "GET /books/{id}.json" {
+		t.Fatalf("Expected request to be labeled with route [GET /books/{id}.json], got [%s]", route)
+	}
+
+	rspEnd := &public.TapEvent{
+		ProxyDirection: public.TapEvent_OUTBOUND,
+		Event: &public.TapEvent_Http_{
+			Http: &public.TapEvent_Http{
+				Event: &public.TapEvent_Http_ResponseEnd_{
+					ResponseEnd: &public.TapEvent_Http_ResponseEnd{Id: id},
+				},
+			},
+		},
+	}
+	s.annotateRoute(rspEnd, "webapp", routes)
+	if route := rspEnd.GetRouteMeta().GetLabels()[routeLabel]; route != "GET /books/{id}.json" {
+		t.Fatalf("Expected response to be labeled with route [GET /books/{id}.json], got [%s]", route)
+	}
+	if len(routes.routes) != 0 {
+		t.Fatalf("Expected routes to be forgotten once the response ends, got %v", routes)
+	}
+
+	s.singleNamespace = true
+	reqInit.RouteMeta = nil
+	s.annotateRoute(reqInit, "webapp", routes)
+	if reqInit.GetRouteMeta() != nil {
+		t.Fatalf("Expected no route to be resolved in single-namespace mode, got %v", reqInit.GetRouteMeta())
+	}
+}
+
+func TestStreamRoutes(t *testing.T) {
+	routes := newStreamRoutes()
+	start := time.Now()
+
+	routes.set(streamID{base: 1, stream: 1}, "reset", start)
+	routes.set(streamID{base: 1, stream: 2}, "long", start.Add(streamRouteTTL/2))
+	if route := routes.get(streamID{base: 1, stream: 1}); route != "reset" {
+		t.Fatalf("Expected route [reset], got [%s]", route)
+	}
+
+	// the first route has expired, the second one hasn't
+	routes.set(streamID{base: 1, stream: 3}, "new", start.Add(streamRouteTTL+time.Second))
+	if route := routes.get(streamID{base: 1, stream: 1}); route != "" {
+		t.Fatalf("Expected the expired route to be forgotten, got [%s]", route)
+	}
+	for _, id := range []streamID{{base: 1, stream: 2}, {base: 1, stream: 3}} {
+		if route := routes.get(id); route == "" {
+			t.Fatalf("Expected the route of stream %v to be remembered", id)
+		}
+	}
+}
+
+func TestCompileRoutes(t *testing.T) {
+	profile := &sp.ServiceProfile{
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				&sp.RouteSpec{
+					Name: "GET /books/{id}",
+					Condition: &sp.RequestMatch{
+						All: []*sp.RequestMatch{
+							&sp.RequestMatch{Method: "GET"},
+							&sp.RequestMatch{PathRegex: "/books/[^/]*"},
+						},
+					},
+				},
+				&sp.RouteSpec{
+					Name: "write /books",
+					Condition: &sp.RequestMatch{
+						All: []*sp.RequestMatch{
+							&sp.RequestMatch{
+								Any: []*sp.RequestMatch{
+									&sp.RequestMatch{Method: "POST"},
+									&sp.RequestMatch{Method: "PUT"},
+								},
+							},
+							&sp.RequestMatch{PathRegex: "/books"},
+						},
+					},
+				},
+				&sp.RouteSpec{
+					Name: "not /admin",
+					Condition: &sp.RequestMatch{
+						Not: &sp.RequestMatch{PathRegex: "/admin.*"},
+					},
+				},
+			},
+		},
+	}
+
+	routes, err := compileRoutes(profile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectations := []struct {
+		method string
+		path   string
+		route  string
+	}{
+		{method: "GET", path: "/books/1234", route: "GET /books/{id}"},
+		{method: "GET", path: "/books/1234/authors", route: "not /admin"},
+		{method: "POST", path: "/books", route: "write /books"},
+		{method: "PUT", path: "/books", route: "write /books"},
+		{method: "DELETE", path: "/books", route: "not /admin"},
+		{method: "PUT", path: "/books/", route: "not /admin"},
+		{method: "GET", path: "/admin/users", route: ""},
+	}
+
+	for _, exp := range expectations {
+		name := ""
+		for _, route := range routes {
+			if route.condition.matches(exp.method, exp.path) {
+				name = route.name
+				break
+			}
+		}
+		if name != exp.route {
+			t.Fatalf("Expected %s %s to match route [%s], got [%s]", exp.method, exp.path, exp.route, name)
+		}
+	}
+
+	t.Run("Rejects empty matches", func(t *testing.T) {
+		if _, err := compileRequestMatch(&sp.RequestMatch{}); err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+	})
+
+	t.Run("Rejects invalid path regexes", func(t *testing.T) {
+		if _, err := compileRequestMatch(&sp.RequestMatch{PathRegex: "/books/("}); err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+	})
+}
+
+func TestCompiledProfiles(t *testing.T) {
+	profile := &sp.ServiceProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "books.booksapp.svc.cluster.local",
+			Namespace:       "booksapp",
+			ResourceVersion: "1",
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				&sp.RouteSpec{Name: "GET /books", Condition: &sp.RequestMatch{Method: "GET", PathRegex: "/books"}},
+			},
+		},
+	}
+	profiles := newCompiledProfiles()
+
+	routes := profiles.routesFor(profile)
+	if len(routes) != 1 {
+		t.Fatalf("Expected 1 route, got %d", len(routes))
+	}
+	if cached := profiles.routesFor(profile.DeepCopy()); cached[0] != routes[0] {
+		t.Fatalf("Expected the routes of the same version of the profile to be compiled once")
+	}
+
+	updated := profile.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Spec.Routes[0].Condition.PathRegex = "/books/("
+	if routes := profiles.routesFor(updated); len(routes) != 0 {
+		t.Fatalf("Expected a profile with an invalid route to have no routes, got %d", len(routes))
+	}
+
+	profiles.forget(updated)
+	if len(profiles.profiles) != 0 {
+		t.Fatalf("Expected the routes of a deleted profile to be forgotten, got %v", profiles.profiles)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
+	"strings"
 	"time"
 
 	httpPb "github.com/linkerd/linkerd2-proxy-api/go/http_types"
@@ -18,6 +20,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/prometheus"
 	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,6 +36,18 @@ type (
 		tapPort             uint
 		k8sAPI              *k8s.API
 		controllerNamespace string
+
+		// singleNamespace is true if the controller is restricted to its own
+		// namespace, in which case ServiceProfiles are not available.
+		singleNamespace bool
+
+		// profiles caches the compiled routes of the ServiceProfiles that
+		// tap events are matched against.
+		profiles *compiledProfiles
+
+		// dialSlots bounds the number of proxy taps being established at
+		// once. If nil, dials are not bounded.
+		dialSlots chan struct{}
 	}
 )
 
@@ -74,12 +89,15 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 		}
 	}
 
+	totalPods := len(pods)
+	pods = selectPods(pods, req.GetPods(), req.GetMaxPods())
+
 	if len(pods) == 0 {
 		return status.Errorf(codes.NotFound, "no pods found for %s/%s",
 			req.GetTarget().GetResource().GetType(), req.GetTarget().GetResource().GetName())
 	}
 
-	log.Infof("Tapping %d pods for target: %+v", len(pods), *req.Target.Resource)
+	util.ContextLogger(stream.Context()).Infof("Tapping %d of %d pods for target: %+v", len(pods), totalPods, *req.Target.Resource)
 
 	events := make(chan *public.TapEvent)
 
@@ -96,7 +114,7 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 
 	for _, pod := range pods {
 		// initiate a tap on the pod
-		go s.tapProxy(stream.Context(), rpsPerPod, match, pod.Status.PodIP, events)
+		go s.tapProxy(stream.Context(), rpsPerPod, match, pod.Status.PodIP, pod.Namespace, events)
 	}
 
 	// read events from the taps and send them back
@@ -113,6 +131,61 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 	}
 }
 
+// selectPods restricts pods to those named in names, if any, and then to the
+// first maxPods of them by name, if maxPods is non-zero.
+func selectPods(pods []*apiv1.Pod, names []string, maxPods uint32) []*apiv1.Pod {
+	if len(names) > 0 {
+		wanted := map[string]struct{}{}
+		for _, name := range names {
+			wanted[name] = struct{}{}
+		}
+
+		selected := []*apiv1.Pod{}
+		for _, pod := range pods {
+			if _, ok := wanted[pod.Name]; ok {
+				selected = append(selected, pod)
+			}
+		}
+		pods = selected
+	}
+
+	if maxPods > 0 && uint32(len(pods)) > maxPods {
+		sort.Slice(pods, func(i, j int) bool {
+			return pods[i].Name < pods[j].Name
+		})
+		pods = pods[:maxPods]
+	}
+
+	return pods
+}
+
+// makeAuthorityMatch matches the authority exactly, or by prefix if it's a
+// pattern ending with its only "*" wildcard, as the proxies can't match
+// authorities against other patterns.
+func makeAuthorityMatch(authority string) (*proxy.ObserveRequest_Match_Http_StringMatch, error) {
+	regex, err := apiUtil.AuthorityRegex(authority)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if regex == "" {
+		return &proxy.ObserveRequest_Match_Http_StringMatch{
+			Match: &proxy.ObserveRequest_Match_Http_StringMatch_Exact{
+				Exact: authority,
+			},
+		}, nil
+	}
+
+	prefix := strings.TrimSuffix(authority, "*")
+	if strings.HasPrefix(authority, "~") || strings.Contains(prefix, "*") {
+		return nil, status.Errorf(codes.InvalidArgument, "authority pattern %q isn't supported, tap only supports a trailing \"*\" wildcard", authority)
+	}
+	return &proxy.ObserveRequest_Match_Http_StringMatch{
+		Match: &proxy.ObserveRequest_Match_Http_StringMatch_Prefix{
+			Prefix: prefix,
+		},
+	}, nil
+}
+
 func makeByResourceMatch(match *public.TapByResourceRequest_Match) (*proxy.ObserveRequest_Match, error) {
 	// TODO: for now assume it's always a single, flat `All` match list
 	seq := match.GetAll()
@@ -155,13 +228,13 @@ func makeByResourceMatch(match *public.TapByResourceRequest_Match) (*proxy.Obser
 					},
 				}
 			case *public.TapByResourceRequest_Match_Http_Authority:
+				authorityMatch, err := makeAuthorityMatch(httpTyped.Authority)
+				if err != nil {
+					return nil, err
+				}
 				httpMatch = proxy.ObserveRequest_Match_Http{
 					Match: &proxy.ObserveRequest_Match_Http_Authority{
-						Authority: &proxy.ObserveRequest_Match_Http_StringMatch{
-							Match: &proxy.ObserveRequest_Match_Http_StringMatch_Exact{
-								Exact: httpTyped.Authority,
-							},
-						},
+						Authority: authorityMatch,
 					},
 				}
 			case *public.TapByResourceRequest_Match_Http_Path:
@@ -219,12 +292,31 @@ func destinationLabels(resource *public.Resource) map[string]string {
 // of maxRps * 1s at most once per 1s window.  If this limit is reached in
 // less than 1s, we sleep until the end of the window before calling Observe
 // again.
-func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.ObserveRequest_Match, addr string, events chan *public.TapEvent) {
+func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.ObserveRequest_Match, addr, namespace string, events chan *public.TapEvent) {
 	tapAddr := fmt.Sprintf("%s:%d", addr, s.tapPort)
-	log.Infof("Establishing tap on %s", tapAddr)
-	conn, err := grpc.DialContext(ctx, tapAddr, grpc.WithInsecure())
+	logger := util.ContextLogger(ctx)
+
+	if !s.acquireDialSlot(ctx) {
+		logger.Debugf("[%s] client terminated the stream before the tap was established", addr)
+		return
+	}
+	dialing := true
+	releaseDialSlot := func() {
+		if dialing {
+			dialing = false
+			s.releaseDialSlot()
+		}
+	}
+	defer releaseDialSlot()
+
+	logger.Infof("Establishing tap on %s", tapAddr)
+	conn, err := grpc.DialContext(ctx, tapAddr,
+		grpc.WithInsecure(),
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		grpc.WithStreamInterceptor(util.RequestIDStreamClientInterceptor),
+	)
 	if err != nil {
-		log.Error(err)
+		logger.Error(err)
 		return
 	}
 	client := proxy.NewTapClient(conn)
@@ -240,25 +332,34 @@ func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.Obse
 		windowEnd := windowStart.Add(tapInterval)
 		rsp, err := client.Observe(ctx, req)
 		if err != nil {
-			log.Error(err)
+			logger.Error(err)
 			return
 		}
+		releaseDialSlot()
+
+		// routes holds the ServiceProfile route of each stream whose request
+		// has been observed, so that its response events can be labeled
+		// alike. The events of a stream are observed by a single Observe
+		// call, so that they're forgotten when it ends.
+		routes := newStreamRoutes()
+
 		for { // Stream loop
 			event, err := rsp.Recv()
 			if err == io.EOF {
-				log.Debugf("[%s] proxy terminated the stream", addr)
+				logger.Debugf("[%s] proxy terminated the stream", addr)
 				break
 			}
 			if err != nil {
-				log.Errorf("[%s] encountered an error: %s", addr, err)
+				logger.Errorf("[%s] encountered an error: %s", addr, err)
 				return
 			}
 
 			translatedEvent := s.translateEvent(event)
+			s.annotateRoute(translatedEvent, namespace, routes)
 
 			select {
 			case <-ctx.Done():
-				log.Debugf("[%s] client terminated the stream", addr)
+				logger.Debugf("[%s] client terminated the stream", addr)
 				return
 			default:
 				events <- translatedEvent
@@ -270,6 +371,27 @@ func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.Obse
 	}
 }
 
+// acquireDialSlot blocks until a tap may be established, returning false if
+// ctx is done first.
+func (s *server) acquireDialSlot(ctx context.Context) bool {
+	if s.dialSlots == nil {
+		return true
+	}
+
+	select {
+	case s.dialSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *server) releaseDialSlot() {
+	if s.dialSlots != nil {
+		<-s.dialSlots
+	}
+}
+
 func (s *server) translateEvent(orig *proxy.TapEvent) *public.TapEvent {
 	direction := func(orig proxy.TapEvent_ProxyDirection) public.TapEvent_ProxyDirection {
 		switch orig {
@@ -426,27 +548,53 @@ func (s *server) translateEvent(orig *proxy.TapEvent) *public.TapEvent {
 	return ev
 }
 
-// NewServer creates a new gRPC Tap server
-func NewServer(
-	addr string,
+// NewGrpcTapServer creates a new Tap server, which fans tap requests out to
+// the proxies of the targeted pods. It is served both over gRPC, by NewServer,
+// and through the tap APIService, by NewAPIServer. At most maxConcurrentDials
+// proxy taps are established at once; if zero, dials are not bounded.
+func NewGrpcTapServer(
 	tapPort uint,
+	maxConcurrentDials uint,
 	controllerNamespace string,
+	singleNamespace bool,
 	k8sAPI *k8s.API,
-) (*grpc.Server, net.Listener, error) {
+) pb.TapServer {
 	k8sAPI.Pod().Informer().AddIndexers(cache.Indexers{podIPIndex: indexPodByIP})
 
-	lis, err := net.Listen("tcp", addr)
-	if err != nil {
-		return nil, nil, err
+	var dialSlots chan struct{}
+	if maxConcurrentDials > 0 {
+		dialSlots = make(chan struct{}, maxConcurrentDials)
 	}
 
-	s := prometheus.NewGrpcServer()
-	srv := server{
+	profiles := newCompiledProfiles()
+	if !singleNamespace {
+		k8sAPI.SP().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			DeleteFunc: profiles.forget,
+		})
+	}
+
+	return &server{
 		tapPort:             tapPort,
 		k8sAPI:              k8sAPI,
 		controllerNamespace: controllerNamespace,
+		singleNamespace:     singleNamespace,
+		profiles:            profiles,
+		dialSlots:           dialSlots,
 	}
-	pb.RegisterTapServer(s, &srv)
+}
+
+// NewServer creates a new gRPC Tap server
+func NewServer(
+	addr string,
+	grpcTapServer pb.TapServer,
+) (*grpc.Server, net.Listener, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := prometheus.NewGrpcServer()
+	pb.RegisterTapServer(s, grpcTapServer)
 
 	return s, lis, nil
 }
@@ -478,8 +626,14 @@ func (s *server) hydrateEventLabels(ev *public.TapEvent) {
 		if err != nil {
 			log.Warnf("error hydrating destination labels: %s", err)
 		}
+	} else {
+		// Outbound proxies report the destination labels they got from the
+		// destination service, but only the pod tells whom they talked to.
+		err = s.hydrateTLSIdentity(ev.GetDestination().GetIp(), ev.GetDestinationMeta().GetLabels())
+		if err != nil {
+			log.Warnf("error hydrating destination TLS identity: %s", err)
+		}
 	}
-
 }
 
 // hydrateIPMeta attempts to determine the metadata labels for `ip` and, if
@@ -499,8 +653,44 @@ func (s *server) hydrateIPLabels(ip *public.IPAddress, labels map[string]string)
 			labels[key] = value
 		}
 		labels[pkgK8s.Namespace] = pod.Namespace
+		s.addTLSIdentity(pod, labels)
+		return nil
+	}
+}
+
+// hydrateTLSIdentity adds the TLS identity of the pod at `ip` to `labels`, if
+// the proxy reported that its connection to the pod was secured with TLS.
+func (s *server) hydrateTLSIdentity(ip *public.IPAddress, labels map[string]string) error {
+	if labels["tls"] != "true" {
 		return nil
 	}
+	pod, err := s.podForIP(ip)
+	if err != nil || pod == nil {
+		return err
+	}
+	s.addTLSIdentity(pod, labels)
+	return nil
+}
+
+// addTLSIdentity adds the TLS identity of `pod` to `labels`, if the connection
+// was secured with TLS. The identity is the one the pod's certificate was
+// issued for, so it's only known for pods that are part of a mesh, and a peer
+// identity reported by the proxy itself is kept.
+func (s *server) addTLSIdentity(pod *apiv1.Pod, labels map[string]string) {
+	if labels["tls"] != "true" || labels[pkgK8s.TLSIdentityLabel] != "" {
+		return
+	}
+	controllerNS := pod.Labels[pkgK8s.ControllerNSLabel]
+	if controllerNS == "" {
+		return
+	}
+	ownerKind, ownerName := s.k8sAPI.GetOwnerKindAndName(pod)
+	labels[pkgK8s.TLSIdentityLabel] = pkgK8s.TLSIdentity{
+		Name:                ownerName,
+		Kind:                ownerKind,
+		Namespace:           pod.Namespace,
+		ControllerNamespace: controllerNS,
+	}.ToDNSName()
 }
 
 // podForIP returns the pod corresponding to a given IP address, if one exists.
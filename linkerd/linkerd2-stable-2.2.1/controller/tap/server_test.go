@@ -2,12 +2,15 @@ package tap
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
 	public "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type tapExpected struct {
@@ -189,7 +192,8 @@ status:
 				t.Fatalf("NewFakeAPI returned an error: %s", err)
 			}
 
-			server, listener, err := NewServer("localhost:0", 0, "controller-ns", k8sAPI)
+			grpcTapServer := NewGrpcTapServer(0, 0, "controller-ns", false, k8sAPI)
+			server, listener, err := NewServer("localhost:0", grpcTapServer)
 			if err != nil {
 				t.Fatalf("NewServer error: %s", err)
 			}
@@ -222,3 +226,79 @@ status:
 		}
 	})
 }
+
+func TestMakeAuthorityMatch(t *testing.T) {
+	expectations := []struct {
+		authority string
+		exact     string
+		prefix    string
+		err       bool
+	}{
+		{authority: "web.emojivoto.svc.cluster.local:80", exact: "web.emojivoto.svc.cluster.local:80"},
+		{authority: "web.*", prefix: "web."},
+		{authority: "*.emojivoto.svc.cluster.local", err: true},
+		{authority: "~web-v[12]", err: true},
+	}
+
+	for i, exp := range expectations {
+		match, err := makeAuthorityMatch(exp.authority)
+		if exp.err != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got [%v]", i, exp.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if match.GetExact() != exp.exact || match.GetPrefix() != exp.prefix {
+			t.Fatalf("test case %d: expected exact [%s] and prefix [%s], got %+v", i, exp.exact, exp.prefix, match)
+		}
+	}
+}
+
+func TestSelectPods(t *testing.T) {
+	pods := []*apiv1.Pod{}
+	for _, name := range []string{"web-c", "web-a", "web-d", "web-b"} {
+		pods = append(pods, &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	expectations := []struct {
+		names   []string
+		maxPods uint32
+		exp     []string
+	}{
+		{
+			exp: []string{"web-c", "web-a", "web-d", "web-b"},
+		},
+		{
+			maxPods: 10,
+			exp:     []string{"web-c", "web-a", "web-d", "web-b"},
+		},
+		{
+			maxPods: 2,
+			exp:     []string{"web-a", "web-b"},
+		},
+		{
+			names: []string{"web-d", "web-b", "web-x"},
+			exp:   []string{"web-d", "web-b"},
+		},
+		{
+			names:   []string{"web-d", "web-b", "web-c"},
+			maxPods: 1,
+			exp:     []string{"web-b"},
+		},
+		{
+			names: []string{"web-x"},
+			exp:   []string{},
+		},
+	}
+
+	for i, exp := range expectations {
+		selected := []string{}
+		for _, pod := range selectPods(append([]*apiv1.Pod{}, pods...), exp.names, exp.maxPods) {
+			selected = append(selected, pod.Name)
+		}
+
+		if !reflect.DeepEqual(selected, exp.exp) {
+			t.Fatalf("Expected selection %d to be %v, got %v", i, exp.exp, selected)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package tmpl
+
+// APIServiceSpec provides a template for the APIService through which tap
+// requests are served by the Kubernetes API aggregation layer.
+var APIServiceSpec = `
+apiVersion: apiregistration.k8s.io/v1beta1
+kind: APIService
+metadata:
+  name: {{ .APIServiceName }}
+spec:
+  group: {{ .Group }}
+  version: {{ .Version }}
+  groupPriorityMinimum: 1000
+  versionPriority: 100
+  service:
+    name: {{ .ServiceName }}
+    namespace: {{ .ControllerNamespace }}
+  caBundle: {{ .CABundle }}`
@@ -0,0 +1,338 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	pkgTls "github.com/linkerd/linkerd2/pkg/tls"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// certRenewBefore is how long before its expiry the serving certificate
+	// is replaced.
+	certRenewBefore = 30 * 24 * time.Hour
+
+	// certResyncPeriod is how often the secret is checked for an expiring
+	// certificate, and the CA bundle is re-published, in the absence of any
+	// change to the secret.
+	certResyncPeriod = 10 * time.Minute
+
+	// certRotationStagePeriod is how long each stage of the rotation of an
+	// expiring certificate lasts, for the API server to pick up the CA bundle
+	// published by a stage, and for all the replicas to load the certificate
+	// it serves, before the next one.
+	certRotationStagePeriod = certResyncPeriod
+
+	// nextTrustAnchorKey, nextCertKey and nextPrivateKeyKey hold the CA and
+	// the serving certificate staged by a rotation, and previousTrustAnchorKey
+	// the CA of the certificate it replaced, in the secret.
+	nextTrustAnchorKey     = "next-" + k8sPkg.TLSTrustAnchorFileName
+	nextCertKey            = "next-" + k8sPkg.TLSCertFileName
+	nextPrivateKeyKey      = "next-" + k8sPkg.TLSPrivateKeyFileName
+	previousTrustAnchorKey = "previous-" + k8sPkg.TLSTrustAnchorFileName
+
+	// rotationStagedAtAnnotation is the time the current stage of a rotation
+	// started, in RFC 3339 format.
+	rotationStagedAtAnnotation = "linkerd.io/cert-rotation-staged-at"
+)
+
+// CertRotator manages the serving certificate of a webhook server, or of an
+// aggregated APIService like tap's. The certificate and the CA that issued it
+// are kept in a secret, so that they're shared by all the replicas of the
+// server and survive restarts. The secret is watched, so that the certificate
+// is reloaded, and the CA published to the webhook's configuration, whenever
+// the secret changes, and the certificate is regenerated when the secret is
+// deleted or invalid. A certificate about to expire is rotated in stages, so
+// that its clients never see a certificate issued by a CA they don't trust.
+type CertRotator struct {
+	client              kubernetes.Interface
+	controllerNamespace string
+	serviceName         string
+	secretName          string
+	publish             func(trustAnchor []byte) error
+	renewBefore         time.Duration
+	stagePeriod         time.Duration
+
+	sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertRotator returns a new instance of CertRotator, for the webhook served
+// by the given service. The certificate is kept in the secret secretName, and
+// publish is called with the CA that issued it, to update the CA bundle of the
+// webhook's configuration. Sync must be called before the certificate is
+// served.
+func NewCertRotator(client kubernetes.Interface, controllerNamespace, serviceName, secretName string, publish func(trustAnchor []byte) error) *CertRotator {
+	return &CertRotator{
+		client:              client,
+		controllerNamespace: controllerNamespace,
+		serviceName:         serviceName,
+		secretName:          secretName,
+		publish:             publish,
+		renewBefore:         certRenewBefore,
+		stagePeriod:         certRotationStagePeriod,
+	}
+}
+
+// GetCertificate returns the current serving certificate. It's meant to be
+// used as the GetCertificate callback of the server's tls.Config, so that new
+// certificates are picked up without restarting the server.
+func (c *CertRotator) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.cert == nil {
+		return nil, errors.New("no serving certificate available")
+	}
+	return c.cert, nil
+}
+
+// Run watches the certificate secret, syncing on every change and every
+// certResyncPeriod, until stop is closed.
+func (c *CertRotator) Run(stop <-chan struct{}) {
+	selector := fields.OneTermEqualSelector("metadata.name", c.secretName).String()
+	secrets := c.client.CoreV1().Secrets(c.controllerNamespace)
+
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return secrets.List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return secrets.Watch(options)
+		},
+	}
+
+	syncCert := func() {
+		if err := c.Sync(); err != nil {
+			log.Errorf("failed to sync the webhook certificate: %s", err)
+		}
+	}
+
+	_, informer := cache.NewInformer(lw, &corev1.Secret{}, certResyncPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { syncCert() },
+		UpdateFunc: func(interface{}, interface{}) { syncCert() },
+		DeleteFunc: func(interface{}) { syncCert() },
+	})
+	informer.Run(stop)
+}
+
+// Sync makes sure the certificate secret exists and holds a valid
+// certificate, advances the rotation of that certificate if it's due, loads
+// the certificate, and publishes the CA that issued it, along with the other
+// CA of an ongoing rotation.
+func (c *CertRotator) Sync() error {
+	secrets := c.client.CoreV1().Secrets(c.controllerNamespace)
+
+	secret, err := secrets.Get(c.secretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		secret, err = c.newSecret()
+		if err != nil {
+			return err
+		}
+		log.Infof("creating webhook certificate secret %s", c.secretName)
+		secret, err = secrets.Create(secret)
+		if apierrors.IsAlreadyExists(err) {
+			// another replica created it first
+			secret, err = secrets.Get(c.secretName, metav1.GetOptions{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	cert, err := loadCertificate(secret)
+	if err != nil {
+		// nothing can trust an invalid certificate, so that it's replaced at
+		// once, along with any rotation in progress
+		log.Warnf("replacing invalid webhook certificate: %s", err)
+
+		renewed, err := c.newSecret()
+		if err != nil {
+			return err
+		}
+		secret.Data = renewed.Data
+		delete(secret.Annotations, rotationStagedAtAnnotation)
+		if secret, err = secrets.Update(secret); err != nil {
+			return err
+		}
+		if cert, err = loadCertificate(secret); err != nil {
+			return err
+		}
+	} else if rotated, err := c.rotate(secret, cert); err != nil {
+		return err
+	} else if rotated {
+		if secret, err = secrets.Update(secret); err != nil {
+			return err
+		}
+		if cert, err = loadCertificate(secret); err != nil {
+			return err
+		}
+	}
+
+	c.Lock()
+	c.cert = cert
+	c.Unlock()
+
+	return c.publish(caBundle(secret))
+}
+
+// rotate advances the rotation of the valid certificate cert held by secret
+// by one stage, if it's due, and returns true if it changed the secret. A
+// certificate about to expire is rotated in three stages, each lasting at
+// least stagePeriod:
+//
+//  1. a new CA and a certificate it issued are staged, and both the old and
+//     the new CA are published;
+//  2. the new certificate is served, with both CAs still published, as the
+//     replicas that haven't loaded it yet serve the old one;
+//  3. the old CA is dropped.
+func (c *CertRotator) rotate(secret *corev1.Secret, cert *tls.Certificate) (bool, error) {
+	_, staged := secret.Data[nextTrustAnchorKey]
+	_, replaced := secret.Data[previousTrustAnchorKey]
+	if (staged || replaced) && !c.stageElapsed(secret) {
+		return false, nil
+	}
+
+	switch {
+	case staged:
+		log.Infof("serving the staged webhook certificate")
+		secret.Data[previousTrustAnchorKey] = secret.Data[k8sPkg.TLSTrustAnchorFileName]
+		secret.Data[k8sPkg.TLSTrustAnchorFileName] = secret.Data[nextTrustAnchorKey]
+		secret.Data[k8sPkg.TLSCertFileName] = secret.Data[nextCertKey]
+		secret.Data[k8sPkg.TLSPrivateKeyFileName] = secret.Data[nextPrivateKeyKey]
+		delete(secret.Data, nextTrustAnchorKey)
+		delete(secret.Data, nextCertKey)
+		delete(secret.Data, nextPrivateKeyKey)
+		setStagedAt(secret)
+
+	case replaced:
+		log.Infof("dropping the CA of the replaced webhook certificate")
+		delete(secret.Data, previousTrustAnchorKey)
+		delete(secret.Annotations, rotationStagedAtAnnotation)
+
+	case time.Until(cert.Leaf.NotAfter) < c.renewBefore:
+		log.Infof("staging a new webhook certificate to replace the one expiring at %s", cert.Leaf.NotAfter)
+		renewed, err := c.newSecret()
+		if err != nil {
+			return false, err
+		}
+		secret.Data[nextTrustAnchorKey] = renewed.Data[k8sPkg.TLSTrustAnchorFileName]
+		secret.Data[nextCertKey] = renewed.Data[k8sPkg.TLSCertFileName]
+		secret.Data[nextPrivateKeyKey] = renewed.Data[k8sPkg.TLSPrivateKeyFileName]
+		setStagedAt(secret)
+
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// stageElapsed is true if the current stage of the rotation of the secret's
+// certificate has lasted stagePeriod, or if its start is unknown.
+func (c *CertRotator) stageElapsed(secret *corev1.Secret) bool {
+	stagedAt, err := time.Parse(time.RFC3339, secret.Annotations[rotationStagedAtAnnotation])
+	return err != nil || time.Since(stagedAt) >= c.stagePeriod
+}
+
+func setStagedAt(secret *corev1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[rotationStagedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+}
+
+// caBundle returns the CA of the served certificate, followed by the CAs of
+// the certificates staged or replaced by a rotation, if any.
+func caBundle(secret *corev1.Secret) []byte {
+	bundle := []byte{}
+	for _, key := range []string{k8sPkg.TLSTrustAnchorFileName, nextTrustAnchorKey, previousTrustAnchorKey} {
+		bundle = append(bundle, secret.Data[key]...)
+	}
+	return bundle
+}
+
+// newSecret returns a secret holding a new CA and a serving certificate it
+// issued for the webhook's service.
+func (c *CertRotator) newSecret() (*corev1.Secret, error) {
+	rootCA, err := pkgTls.NewCA()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsIdentity := k8sPkg.TLSIdentity{
+		Name:                c.serviceName,
+		Kind:                k8sPkg.Service,
+		Namespace:           c.controllerNamespace,
+		ControllerNamespace: c.controllerNamespace,
+	}
+	certAndPrivateKey, err := rootCA.IssueEndEntityCertificate(tlsIdentity.ToDNSName())
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, err := certAndPrivateKey.EncodedCertificate()
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("PEM-encoded certificate: %s\n", certPEM)
+
+	keyPEM, err := certAndPrivateKey.EncodedPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.secretName,
+			Namespace: c.controllerNamespace,
+		},
+		Data: map[string][]byte{
+			k8sPkg.TLSTrustAnchorFileName: []byte(rootCA.TrustAnchorPEM()),
+			k8sPkg.TLSCertFileName:        certPEM,
+			k8sPkg.TLSPrivateKeyFileName:  keyPEM,
+		},
+	}, nil
+}
+
+// loadCertificate parses the serving certificate held by secret, and checks
+// that it was issued by the CA held alongside it.
+func loadCertificate(secret *corev1.Secret) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(secret.Data[k8sPkg.TLSCertFileName], secret.Data[k8sPkg.TLSPrivateKeyFileName])
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	trustAnchor := secret.Data[k8sPkg.TLSTrustAnchorFileName]
+	if !roots.AppendCertsFromPEM(trustAnchor) {
+		return nil, errors.New("invalid trust anchor")
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: roots}); err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
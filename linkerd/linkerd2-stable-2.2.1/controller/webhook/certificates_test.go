@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testNamespace  = "linkerd"
+	testSecretName = "linkerd-test-webhook-tls"
+)
+
+func TestCertRotatorSync(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	var published []byte
+	certs := NewCertRotator(client, testNamespace, "linkerd-test-webhook", testSecretName, func(trustAnchor []byte) error {
+		published = trustAnchor
+		return nil
+	})
+
+	if _, err := certs.GetCertificate(nil); err == nil {
+		t.Fatal("Expected no certificate to be served before the first sync")
+	}
+
+	t.Run("creates the secret", func(t *testing.T) {
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		assertCertificatePublished(t, client, certs, published)
+	})
+
+	t.Run("keeps a valid certificate", func(t *testing.T) {
+		before := getSecret(t, client)
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		after := getSecret(t, client)
+		if !bytes.Equal(before.Data[k8sPkg.TLSCertFileName], after.Data[k8sPkg.TLSCertFileName]) {
+			t.Fatal("Expected a valid certificate not to be replaced")
+		}
+	})
+
+	t.Run("rotates an expiring certificate in stages", func(t *testing.T) {
+		defer func() {
+			certs.renewBefore, certs.stagePeriod = certRenewBefore, certRotationStagePeriod
+		}()
+		before := getSecret(t, client)
+		oldCA := before.Data[k8sPkg.TLSTrustAnchorFileName]
+
+		// the certificate is valid for a year, so that it expires within 2
+		certs.renewBefore = 2 * 365 * 24 * time.Hour
+		certs.stagePeriod = time.Hour
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		staged := getSecret(t, client)
+		newCA := staged.Data[nextTrustAnchorKey]
+		if len(newCA) == 0 || bytes.Equal(oldCA, newCA) {
+			t.Fatal("Expected a new CA to be staged")
+		}
+		if !bytes.Equal(before.Data[k8sPkg.TLSCertFileName], staged.Data[k8sPkg.TLSCertFileName]) {
+			t.Fatal("Expected the certificate not to be replaced before the new CA is published")
+		}
+		assertCertificatePublished(t, client, certs, published)
+		if !bytes.Equal(published, append(append([]byte{}, oldCA...), newCA...)) {
+			t.Fatalf("Expected both CAs to be published, got: %s", published)
+		}
+
+		// the stage hasn't lasted stagePeriod yet
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if !bytes.Equal(newCA, getSecret(t, client).Data[nextTrustAnchorKey]) {
+			t.Fatal("Expected the staged certificate not to be served before the end of the stage")
+		}
+
+		certs.renewBefore, certs.stagePeriod = certRenewBefore, 0
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		served := getSecret(t, client)
+		if !bytes.Equal(staged.Data[nextCertKey], served.Data[k8sPkg.TLSCertFileName]) {
+			t.Fatal("Expected the staged certificate to be served")
+		}
+		assertCertificatePublished(t, client, certs, published)
+		if !bytes.Equal(published, append(append([]byte{}, newCA...), oldCA...)) {
+			t.Fatalf("Expected both CAs to be published, got: %s", published)
+		}
+
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if _, ok := getSecret(t, client).Data[previousTrustAnchorKey]; ok {
+			t.Fatal("Expected the old CA to be dropped")
+		}
+		assertCertificatePublished(t, client, certs, published)
+	})
+
+	t.Run("replaces an invalid certificate", func(t *testing.T) {
+		secret := getSecret(t, client)
+		before := secret.Data[k8sPkg.TLSTrustAnchorFileName]
+		secret.Data[k8sPkg.TLSCertFileName] = []byte("invalid")
+		if _, err := client.CoreV1().Secrets(testNamespace).Update(secret); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if bytes.Equal(before, getSecret(t, client).Data[k8sPkg.TLSTrustAnchorFileName]) {
+			t.Fatal("Expected a new CA to be generated")
+		}
+		assertCertificatePublished(t, client, certs, published)
+	})
+
+	t.Run("recreates a deleted secret", func(t *testing.T) {
+		before := getSecret(t, client).Data[k8sPkg.TLSTrustAnchorFileName]
+		if err := client.CoreV1().Secrets(testNamespace).Delete(testSecretName, &metav1.DeleteOptions{}); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		if err := certs.Sync(); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if bytes.Equal(before, getSecret(t, client).Data[k8sPkg.TLSTrustAnchorFileName]) {
+			t.Fatal("Expected a new CA to be generated")
+		}
+		assertCertificatePublished(t, client, certs, published)
+	})
+}
+
+func getSecret(t *testing.T, client kubernetes.Interface) *corev1.Secret {
+	secret, err := client.CoreV1().Secrets(testNamespace).Get(testSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	return secret
+}
+
+// assertCertificatePublished checks that the certificate in the secret is
+// served, and that the CA that issued it was published, along with the other
+// CA of an ongoing rotation.
+func assertCertificatePublished(t *testing.T, client kubernetes.Interface, certs *CertRotator, published []byte) {
+	secret := getSecret(t, client)
+
+	cert, err := certs.GetCertificate(nil)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	expected, err := loadCertificate(secret)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if !bytes.Equal(cert.Certificate[0], expected.Certificate[0]) {
+		t.Error("Expected the certificate in the secret to be served")
+	}
+
+	if !bytes.Equal(published, caBundle(secret)) {
+		t.Errorf("CA mismatch\nExpected: %s\nActual: %s", caBundle(secret), published)
+	}
+}
@@ -1,7 +1,9 @@
 package addr
 
 import (
+	"encoding/binary"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
@@ -13,22 +15,25 @@ import (
 // to the Linkerd proxies.
 const DefaultWeight = 1
 
-// PublicAddressToString formats a Public API TCPAddress as a string.
+// PublicAddressToString formats a Public API TCPAddress as a string. IPv6
+// addresses are enclosed in square brackets, as in "[::1]:80".
 func PublicAddressToString(addr *public.TcpAddress) string {
-	octects := decodeIPToOctets(addr.GetIp().GetIpv4())
-	return fmt.Sprintf("%d.%d.%d.%d:%d", octects[0], octects[1], octects[2], octects[3], addr.GetPort())
+	return joinHostPort(PublicIPToString(addr.GetIp()), addr.GetPort())
 }
 
 // PublicIPToString formats a Public API IPAddress as a string.
 func PublicIPToString(ip *public.IPAddress) string {
+	if ipv6 := ip.GetIpv6(); ipv6 != nil {
+		return decodeIPv6(ipv6.GetFirst(), ipv6.GetLast()).String()
+	}
 	octets := decodeIPToOctets(ip.GetIpv4())
 	return fmt.Sprintf("%d.%d.%d.%d", octets[0], octets[1], octets[2], octets[3])
 }
 
-// ProxyAddressToString formats a Proxy API TCPAddress as a string.
+// ProxyAddressToString formats a Proxy API TCPAddress as a string. IPv6
+// addresses are enclosed in square brackets, as in "[::1]:80".
 func ProxyAddressToString(addr *pb.TcpAddress) string {
-	octects := decodeIPToOctets(addr.GetIp().GetIpv4())
-	return fmt.Sprintf("%d.%d.%d.%d:%d", octects[0], octects[1], octects[2], octects[3], addr.GetPort())
+	return joinHostPort(ProxyIPToString(addr.GetIp()), addr.GetPort())
 }
 
 // ProxyAddressesToString formats a list of Proxy API TCPAddresses as a string.
@@ -42,6 +47,9 @@ func ProxyAddressesToString(addrs []pb.TcpAddress) string {
 
 // ProxyIPToString formats a Proxy API IPAddress as a string.
 func ProxyIPToString(ip *pb.IPAddress) string {
+	if ipv6 := ip.GetIpv6(); ipv6 != nil {
+		return decodeIPv6(ipv6.GetFirst(), ipv6.GetLast()).String()
+	}
 	octets := decodeIPToOctets(ip.GetIpv4())
 	return fmt.Sprintf("%d.%d.%d.%d", octets[0], octets[1], octets[2], octets[3])
 }
@@ -56,6 +64,32 @@ func ProxyIPV4(a1, a2, a3, a4 uint8) *pb.IPAddress {
 	}
 }
 
+// ProxyIPV6 encodes a 16-byte IP address as a Proxy API IPAddress.
+func ProxyIPV6(ip net.IP) *pb.IPAddress {
+	first, last := encodeIPv6(ip)
+	return &pb.IPAddress{
+		Ip: &pb.IPAddress_Ipv6{
+			Ipv6: &pb.IPv6{
+				First: first,
+				Last:  last,
+			},
+		},
+	}
+}
+
+// ParseProxyIP parses an IPv4 or IPv6 address string into a Proxy API
+// IPAddress. IPv4-mapped IPv6 addresses are encoded as IPv4 addresses.
+func ParseProxyIP(ip string) (*pb.IPAddress, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("Invalid IP address: %s", ip)
+	}
+	if ipv4 := parsed.To4(); ipv4 != nil {
+		return ProxyIPV4(ipv4[0], ipv4[1], ipv4[2], ipv4[3]), nil
+	}
+	return ProxyIPV6(parsed), nil
+}
+
 // ParseProxyIPV4 parses an IP Address string into a Proxy API IPAddress.
 func ParseProxyIPV4(ip string) (*pb.IPAddress, error) {
 	segments := strings.Split(ip, ".")
@@ -83,6 +117,32 @@ func PublicIPV4(a1, a2, a3, a4 uint8) *public.IPAddress {
 	}
 }
 
+// PublicIPV6 encodes a 16-byte IP address as a Public API IPAddress.
+func PublicIPV6(ip net.IP) *public.IPAddress {
+	first, last := encodeIPv6(ip)
+	return &public.IPAddress{
+		Ip: &public.IPAddress_Ipv6{
+			Ipv6: &public.IPv6{
+				First: first,
+				Last:  last,
+			},
+		},
+	}
+}
+
+// ParsePublicIP parses an IPv4 or IPv6 address string into a Public API
+// IPAddress. IPv4-mapped IPv6 addresses are encoded as IPv4 addresses.
+func ParsePublicIP(ip string) (*public.IPAddress, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("Invalid IP address: %s", ip)
+	}
+	if ipv4 := parsed.To4(); ipv4 != nil {
+		return PublicIPV4(ipv4[0], ipv4[1], ipv4[2], ipv4[3]), nil
+	}
+	return PublicIPV6(parsed), nil
+}
+
 // ParsePublicIPV4 parses an IP Address string into a Public API IPAddress.
 func ParsePublicIPV4(ip string) (*public.IPAddress, error) {
 	segments := strings.Split(ip, ".")
@@ -137,3 +197,24 @@ func decodeIPToOctets(ip uint32) [4]uint8 {
 		uint8(ip & 255),
 	}
 }
+
+// encodeIPv6 splits a 16-byte IP address into its first and last 8 bytes, as
+// encoded by the IPv6 messages of the APIs.
+func encodeIPv6(ip net.IP) (uint64, uint64) {
+	ip = ip.To16()
+	if ip == nil {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint64(ip[:8]), binary.BigEndian.Uint64(ip[8:])
+}
+
+func decodeIPv6(first, last uint64) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	binary.BigEndian.PutUint64(ip[:8], first)
+	binary.BigEndian.PutUint64(ip[8:], last)
+	return ip
+}
+
+func joinHostPort(host string, port uint32) string {
+	return net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+}
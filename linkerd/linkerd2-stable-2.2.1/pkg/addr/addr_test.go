@@ -66,3 +66,57 @@ func TestNetToPublic(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProxyIP(t *testing.T) {
+	expectations := []struct {
+		ip       string
+		addr     *proxy.TcpAddress
+		expected string
+	}{
+		{
+			ip:       "10.1.2.3",
+			addr:     &proxy.TcpAddress{Port: 8080},
+			expected: "10.1.2.3:8080",
+		},
+		{
+			ip:       "2001:db8::1",
+			addr:     &proxy.TcpAddress{Port: 8080},
+			expected: "[2001:db8::1]:8080",
+		},
+		{
+			ip:       "::ffff:10.1.2.3",
+			addr:     &proxy.TcpAddress{Port: 80},
+			expected: "10.1.2.3:80",
+		},
+	}
+
+	for i, exp := range expectations {
+		t.Run(fmt.Sprintf("%d parses and formats %s", i, exp.ip), func(t *testing.T) {
+			ip, err := ParseProxyIP(exp.ip)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			exp.addr.Ip = ip
+
+			proxyStr := ProxyAddressToString(exp.addr)
+			if proxyStr != exp.expected {
+				t.Fatalf("Unexpected proxy address: [%s] expected: [%s]", proxyStr, exp.expected)
+			}
+			publicStr := PublicAddressToString(NetToPublic(exp.addr))
+			if publicStr != exp.expected {
+				t.Fatalf("Unexpected public address: [%s] expected: [%s]", publicStr, exp.expected)
+			}
+		})
+	}
+
+	t.Run("Returns an error for invalid addresses", func(t *testing.T) {
+		for _, ip := range []string{"", "10.1.2", "2001:db8:::1", "foo"} {
+			if _, err := ParseProxyIP(ip); err == nil {
+				t.Fatalf("Expected an error parsing [%s]", ip)
+			}
+			if _, err := ParsePublicIP(ip); err == nil {
+				t.Fatalf("Expected an error parsing [%s]", ip)
+			}
+		}
+	})
+}
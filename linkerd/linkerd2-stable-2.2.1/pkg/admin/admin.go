@@ -1,48 +1,127 @@
 package admin
 
 import (
+	"expvar"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimePprof "runtime/pprof"
+	"strings"
 	"time"
 
+	"github.com/linkerd/linkerd2/pkg/flags"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
 
+// enablePprof is set by the -enable-pprof flag, which is common to all the
+// processes with an admin server.
+var enablePprof = flag.Bool("enable-pprof", false, "serve the pprof profiles, expvar variables and a goroutine and memory dump under /debug/ on the admin server")
+
 type handler struct {
 	promHandler http.Handler
+	handlers    map[string]http.Handler
+	pprof       bool
+	auth        authConfig
 }
 
 // StartServer starts an admin server listening on a given address.
 func StartServer(addr string) {
+	StartServerWithHandlers(addr, nil)
+}
+
+// StartServerWithHandlers starts an admin server listening on a given address,
+// which also serves the given component-specific handlers, keyed by path. They
+// override the default handlers of the same path.
+func StartServerWithHandlers(addr string, handlers map[string]http.Handler) {
+	s, err := NewServer(addr, handlers)
+	if err != nil {
+		log.Fatal(err)
+	}
 	log.Infof("starting admin server on %s", addr)
+	log.Fatal(ListenAndServe(s))
+}
+
+// NewServer returns an admin server for the given address, which also serves
+// the given component-specific handlers, keyed by path, without starting it.
+// It's configured with the TLS and the client authentication of the -admin-*
+// flags, and must be started with ListenAndServe.
+func NewServer(addr string, handlers map[string]http.Handler) (*http.Server, error) {
+	tlsConfig, err := tlsConfigFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := authConfigFromFlags()
+	if err != nil {
+		return nil, err
+	}
 
 	h := &handler{
 		promHandler: promhttp.Handler(),
+		handlers:    handlers,
+		pprof:       *enablePprof,
+		auth:        auth,
 	}
 
 	s := &http.Server{
 		Addr:         addr,
 		Handler:      h,
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
-
-	log.Fatal(s.ListenAndServe())
+	if h.pprof {
+		// CPU profiles and traces are written once they're over, e.g. after
+		// 30 seconds by default
+		s.WriteTimeout = 0
+	}
+	return s, nil
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.auth.authenticate(req); err != nil {
+		log.Debugf("rejecting admin request from %s: %s", req.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// component-specific handlers take precedence, e.g. to check readiness
+	if handler, ok := h.handlers[req.URL.Path]; ok {
+		handler.ServeHTTP(w, req)
+		return
+	}
+
+	if h.pprof && strings.HasPrefix(req.URL.Path, "/debug/") {
+		h.serveDebug(w, req)
+		return
+	}
+
 	switch req.URL.Path {
 	case "/metrics":
 		h.promHandler.ServeHTTP(w, req)
+	case "/live":
+		h.serveLive(w, req)
 	case "/ping":
 		h.servePing(w, req)
 	case "/ready":
 		h.serveReady(w, req)
+	case "/loglevel":
+		h.serveLogLevel(w, req)
 	default:
 		http.NotFound(w, req)
 	}
 }
 
+// serveLive succeeds as long as the process serves requests. Unlike /ready, it
+// doesn't depend on the caches or the backends of the component, so that
+// Kubernetes doesn't restart a component that's only waiting for them.
+func (h *handler) serveLive(w http.ResponseWriter, req *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
 func (h *handler) servePing(w http.ResponseWriter, req *http.Request) {
 	w.Write([]byte("pong\n"))
 }
@@ -50,3 +129,98 @@ func (h *handler) servePing(w http.ResponseWriter, req *http.Request) {
 func (h *handler) serveReady(w http.ResponseWriter, req *http.Request) {
 	w.Write([]byte("ok\n"))
 }
+
+// serveDebug serves the runtime diagnostics enabled by -enable-pprof.
+func (h *handler) serveDebug(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/debug/pprof/cmdline":
+		pprof.Cmdline(w, req)
+	case "/debug/pprof/profile":
+		pprof.Profile(w, req)
+	case "/debug/pprof/symbol":
+		pprof.Symbol(w, req)
+	case "/debug/pprof/trace":
+		pprof.Trace(w, req)
+	case "/debug/vars":
+		expvar.Handler().ServeHTTP(w, req)
+	case "/debug/dump":
+		h.serveDump(w, req)
+	default:
+		// serves the index, and the profiles by name, e.g. heap or goroutine
+		if strings.HasPrefix(req.URL.Path, "/debug/pprof/") {
+			pprof.Index(w, req)
+			return
+		}
+		http.NotFound(w, req)
+	}
+}
+
+// serveDump writes the memory statistics of the process, followed by the
+// stacks of all its goroutines.
+func (h *handler) serveDump(w http.ResponseWriter, req *http.Request) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(w, "heap alloc: %d bytes\n", stats.HeapAlloc)
+	fmt.Fprintf(w, "heap in use: %d bytes\n", stats.HeapInuse)
+	fmt.Fprintf(w, "heap objects: %d\n", stats.HeapObjects)
+	fmt.Fprintf(w, "sys: %d bytes\n", stats.Sys)
+	fmt.Fprintf(w, "gc cycles: %d\n", stats.NumGC)
+	fmt.Fprintf(w, "gc pause total: %s\n\n", time.Duration(stats.PauseTotalNs))
+
+	runtimePprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// serveLogLevel returns the log level on GET, and sets it to the one in the
+// body of the request on PUT, e.g. to turn on debug logging without restarting
+// the component. The admin port is reachable from the whole cluster, e.g. by
+// Prometheus, and the requests proxied to it all come from the loopback
+// addresses, so that the PUT requests are only accepted if the admin server
+// authenticates its clients with the -admin-token-file or -admin-client-ca
+// flags.
+func (h *handler) serveLogLevel(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if !h.auth.enabled() {
+			log.Debugf("rejecting log level change from %s", req.RemoteAddr)
+			http.Error(w, "the log level can only be changed if the admin server authenticates its clients", http.StatusForbidden)
+			return
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		level, err := log.ParseLevel(strings.TrimSpace(string(body)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		flags.SetLogLevel(level)
+		log.Infof("log level set to %s", level)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, fmt.Sprintf("%s not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Write([]byte(log.GetLevel().String() + "\n"))
+}
+
+// ReadyHandler returns a handler for the /ready path that fails while the
+// given check returns an error.
+func ReadyHandler(check func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := check(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+}
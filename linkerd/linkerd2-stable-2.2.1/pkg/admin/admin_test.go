@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestServeLogLevel(t *testing.T) {
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.InfoLevel)
+
+	testCases := []struct {
+		auth          authConfig
+		method        string
+		body          string
+		token         string
+		expectedCode  int
+		expectedLevel log.Level
+	}{
+		{method: "GET", expectedCode: http.StatusOK, expectedLevel: log.InfoLevel},
+		{method: "PUT", body: "debug", expectedCode: http.StatusForbidden, expectedLevel: log.InfoLevel},
+		{auth: authConfig{token: "secret"}, method: "PUT", body: "debug\n", token: "secret", expectedCode: http.StatusOK, expectedLevel: log.DebugLevel},
+		{auth: authConfig{token: "secret"}, method: "PUT", body: "verbose", token: "secret", expectedCode: http.StatusBadRequest, expectedLevel: log.DebugLevel},
+		{auth: authConfig{token: "secret"}, method: "POST", body: "info", token: "secret", expectedCode: http.StatusMethodNotAllowed, expectedLevel: log.DebugLevel},
+		{auth: authConfig{token: "secret"}, method: "PUT", body: "info", expectedCode: http.StatusUnauthorized, expectedLevel: log.DebugLevel},
+		{auth: authConfig{token: "secret"}, method: "PUT", body: "warn", token: "secret", expectedCode: http.StatusOK, expectedLevel: log.WarnLevel},
+	}
+
+	for i, tc := range testCases {
+		h := &handler{auth: tc.auth}
+		rsp := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, "/loglevel", strings.NewReader(tc.body))
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != tc.expectedCode {
+			t.Fatalf("test case %d: expected status %d, got %d", i, tc.expectedCode, rsp.Code)
+		}
+		if log.GetLevel() != tc.expectedLevel {
+			t.Fatalf("test case %d: expected log level %s, got %s", i, tc.expectedLevel, log.GetLevel())
+		}
+		if tc.expectedCode == http.StatusOK && strings.TrimSpace(rsp.Body.String()) != tc.expectedLevel.String() {
+			t.Fatalf("test case %d: expected response %s, got %s", i, tc.expectedLevel, rsp.Body.String())
+		}
+	}
+}
+
+func TestServeLive(t *testing.T) {
+	h := &handler{
+		handlers: map[string]http.Handler{
+			"/ready": ReadyHandler(func() error { return errors.New("caches not synced") }),
+		},
+	}
+
+	rsp := httptest.NewRecorder()
+	h.ServeHTTP(rsp, httptest.NewRequest("GET", "/ready", nil))
+	if rsp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected /ready to fail with status %d, got %d", http.StatusServiceUnavailable, rsp.Code)
+	}
+
+	rsp = httptest.NewRecorder()
+	h.ServeHTTP(rsp, httptest.NewRequest("GET", "/live", nil))
+	if rsp.Code != http.StatusOK {
+		t.Fatalf("Expected /live to succeed while not ready, got status %d", rsp.Code)
+	}
+}
+
+func TestServeDebug(t *testing.T) {
+	testCases := []struct {
+		pprof        bool
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{pprof: false, path: "/debug/dump", expectedCode: http.StatusNotFound},
+		{pprof: false, path: "/debug/pprof/", expectedCode: http.StatusNotFound},
+		{pprof: true, path: "/debug/dump", expectedCode: http.StatusOK, expectedBody: "goroutines: "},
+		{pprof: true, path: "/debug/pprof/", expectedCode: http.StatusOK, expectedBody: "goroutine"},
+		{pprof: true, path: "/debug/pprof/heap?debug=1", expectedCode: http.StatusOK, expectedBody: "heap profile"},
+		{pprof: true, path: "/debug/vars", expectedCode: http.StatusOK, expectedBody: "memstats"},
+		{pprof: true, path: "/debug/unknown", expectedCode: http.StatusNotFound},
+	}
+
+	for i, tc := range testCases {
+		h := &handler{pprof: tc.pprof}
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, httptest.NewRequest("GET", tc.path, nil))
+
+		if rsp.Code != tc.expectedCode {
+			t.Fatalf("test case %d: expected status %d, got %d", i, tc.expectedCode, rsp.Code)
+		}
+		if !strings.Contains(rsp.Body.String(), tc.expectedBody) {
+			t.Fatalf("test case %d: expected response to contain [%s], got [%s]", i, tc.expectedBody, rsp.Body.String())
+		}
+	}
+}
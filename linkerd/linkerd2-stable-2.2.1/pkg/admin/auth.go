@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// The flags below are common to all the processes with an admin server. The
+// admin server serves plaintext HTTP to anyone unless they're set.
+var (
+	tlsCertFile  = flag.String("admin-tls-cert", "", "path to the PEM-encoded certificate the admin server serves TLS with; plaintext if empty")
+	tlsKeyFile   = flag.String("admin-tls-key", "", "path to the PEM-encoded private key of -admin-tls-cert")
+	clientCAFile = flag.String("admin-client-ca", "", "path to the PEM-encoded CA bundle the client certificates of the admin requests must be signed by; requires -admin-tls-cert")
+	tokenFile    = flag.String("admin-token-file", "", "path to a file with the bearer token the admin requests must carry")
+)
+
+// unauthenticatedPaths are served to any client, so that the kubelet, which
+// can't authenticate, can still probe the liveness and readiness of the
+// process.
+var unauthenticatedPaths = map[string]bool{
+	"/live":  true,
+	"/ping":  true,
+	"/ready": true,
+}
+
+// authConfig is how the admin server authenticates its clients.
+type authConfig struct {
+	// token is the bearer token the requests must carry, if not empty.
+	token string
+	// clientCert requires the requests to present a client certificate that
+	// was verified against the configured CA bundle.
+	clientCert bool
+}
+
+// tlsConfigFromFlags returns the TLS configuration of the admin server, or
+// nil if it serves plaintext.
+func tlsConfigFromFlags() (*tls.Config, error) {
+	if *tlsCertFile == "" && *tlsKeyFile == "" {
+		if *clientCAFile != "" {
+			return nil, errors.New("-admin-client-ca requires -admin-tls-cert and -admin-tls-key")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(*tlsCertFile, *tlsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the admin server certificate: %s", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if *clientCAFile != "" {
+		pem, err := ioutil.ReadFile(*clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the admin client CA bundle: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", *clientCAFile)
+		}
+		// the probes of the kubelet don't present a certificate, and the
+		// handler enforces it for the other paths
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		config.ClientCAs = pool
+	}
+
+	return config, nil
+}
+
+// authConfigFromFlags returns how the admin server authenticates its clients.
+func authConfigFromFlags() (authConfig, error) {
+	config := authConfig{clientCert: *clientCAFile != ""}
+
+	if *tokenFile != "" {
+		token, err := ioutil.ReadFile(*tokenFile)
+		if err != nil {
+			return authConfig{}, fmt.Errorf("failed to read the admin token: %s", err)
+		}
+		config.token = strings.TrimSpace(string(token))
+		if config.token == "" {
+			return authConfig{}, fmt.Errorf("the admin token in %s is empty", *tokenFile)
+		}
+	}
+
+	return config, nil
+}
+
+// enabled returns whether the admin server authenticates its clients.
+func (c authConfig) enabled() bool {
+	return c.token != "" || c.clientCert
+}
+
+// authenticate returns an error if the request is missing the credentials
+// required by the configuration.
+func (c authConfig) authenticate(req *http.Request) error {
+	if unauthenticatedPaths[req.URL.Path] {
+		return nil
+	}
+
+	if c.clientCert {
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+			return errors.New("no valid client certificate presented")
+		}
+	}
+
+	if c.token != "" {
+		header := req.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return errors.New("no bearer token presented")
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+			return errors.New("invalid bearer token")
+		}
+	}
+
+	return nil
+}
+
+// ListenAndServe serves the given admin server, with TLS if it's configured
+// with a certificate.
+func ListenAndServe(s *http.Server) error {
+	if s.TLSConfig != nil {
+		return s.ListenAndServeTLS("", "")
+	}
+	return s.ListenAndServe()
+}
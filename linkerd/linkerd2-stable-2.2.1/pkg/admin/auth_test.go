@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	verified := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}},
+	}
+
+	testCases := []struct {
+		config        authConfig
+		path          string
+		authorization string
+		tls           *tls.ConnectionState
+		authenticated bool
+	}{
+		{config: authConfig{}, path: "/metrics", authenticated: true},
+		{config: authConfig{token: "secret"}, path: "/metrics", authenticated: false},
+		{config: authConfig{token: "secret"}, path: "/metrics", authorization: "Bearer other", authenticated: false},
+		{config: authConfig{token: "secret"}, path: "/metrics", authorization: "Bearer secret", authenticated: true},
+		{config: authConfig{token: "secret"}, path: "/ready", authenticated: true},
+		{config: authConfig{clientCert: true}, path: "/debug/vars", authenticated: false},
+		{config: authConfig{clientCert: true}, path: "/debug/vars", tls: &tls.ConnectionState{}, authenticated: false},
+		{config: authConfig{clientCert: true}, path: "/debug/vars", tls: verified, authenticated: true},
+		{config: authConfig{clientCert: true}, path: "/live", tls: &tls.ConnectionState{}, authenticated: true},
+		{config: authConfig{clientCert: true, token: "secret"}, path: "/loglevel", tls: verified, authenticated: false},
+		{config: authConfig{clientCert: true, token: "secret"}, path: "/loglevel", authorization: "Bearer secret", tls: verified, authenticated: true},
+	}
+
+	for i, tc := range testCases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		req.TLS = tc.tls
+
+		err := tc.config.authenticate(req)
+		if tc.authenticated != (err == nil) {
+			t.Fatalf("test case %d: expected authenticated %t, got error: %v", i, tc.authenticated, err)
+		}
+	}
+}
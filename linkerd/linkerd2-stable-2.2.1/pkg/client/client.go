@@ -0,0 +1,288 @@
+// Package client provides a client for the Linkerd public API, for the tools
+// that query the control plane without going through the linkerd CLI.
+//
+// A Client connects either directly to the address of the public API, e.g.
+// from inside the cluster, or through a port-forward to the public API
+// service, set up from a kubeconfig:
+//
+//	c, err := client.New(client.Config{KubeConfig: "/home/me/.kube/config"})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	rsp, err := c.StatSummary(ctx, util.StatsSummaryRequestParams{
+//		StatsBaseRequestParams: util.StatsBaseRequestParams{
+//			Namespace:    "emojivoto",
+//			ResourceType: k8s.Deployment,
+//		},
+//	})
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultControlPlaneNamespace is the namespace where the control plane
+	// is installed by default.
+	DefaultControlPlaneNamespace = "linkerd"
+
+	// DefaultRetries is the number of times a failed request is retried by
+	// default.
+	DefaultRetries = 3
+
+	// DefaultRetryBackoff is the delay before the first retry of a failed
+	// request by default, which doubles with every retry.
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	// DefaultConnectTimeout is how long a port-forward to the public API is
+	// waited for by default.
+	DefaultConnectTimeout = 30 * time.Second
+
+	apiService = "linkerd-controller-api"
+	apiPort    = 8085
+)
+
+// Config configures the connection of a Client to the public API. The zero
+// value connects through a port-forward set up from the default kubeconfig,
+// to the control plane of the "linkerd" namespace.
+type Config struct {
+	// Addr is the address of the public API, e.g.
+	// "linkerd-controller-api.linkerd.svc.cluster.local:8085". If it's empty,
+	// the public API is reached through a port-forward.
+	Addr string
+
+	// KubeConfig is the path of the kubeconfig used to set up the
+	// port-forward, or the default kubeconfig if it's empty.
+	KubeConfig string
+
+	// KubeContext is the kubeconfig context used to set up the port-forward,
+	// or the current context if it's empty.
+	KubeContext string
+
+	// ControlPlaneNamespace is the namespace of the control plane, or
+	// DefaultControlPlaneNamespace if it's empty.
+	ControlPlaneNamespace string
+
+	// Retries is the number of times a request that fails is retried. It
+	// defaults to DefaultRetries, and retries are disabled if it's negative.
+	// Only the requests that don't reach the public API are retried, not the
+	// ones it rejects.
+	Retries int
+
+	// RetryBackoff is the delay before the first retry, which doubles with
+	// every retry. It defaults to DefaultRetryBackoff.
+	RetryBackoff time.Duration
+
+	// ConnectTimeout is how long the port-forward is waited for. It defaults
+	// to DefaultConnectTimeout.
+	ConnectTimeout time.Duration
+}
+
+// Client is a client for the Linkerd public API.
+type Client struct {
+	api         public.APIClient
+	portForward *k8s.PortForward
+	retries     int
+	backoff     time.Duration
+}
+
+// New returns a Client connected to the public API as configured. The Client
+// must be closed once it's not used anymore, to stop its port-forward.
+func New(config Config) (*Client, error) {
+	namespace := config.ControlPlaneNamespace
+	if namespace == "" {
+		namespace = DefaultControlPlaneNamespace
+	}
+
+	c := &Client{
+		retries: config.Retries,
+		backoff: config.RetryBackoff,
+	}
+	if c.retries == 0 {
+		c.retries = DefaultRetries
+	}
+	if c.backoff == 0 {
+		c.backoff = DefaultRetryBackoff
+	}
+
+	addr := config.Addr
+	if addr == "" {
+		pf, err := newPortForward(config, namespace)
+		if err != nil {
+			return nil, err
+		}
+		c.portForward = pf
+		addr = pf.URLFor("")
+	}
+
+	api, err := public.NewInternalClient(namespace, addr)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.api = api
+
+	return c, nil
+}
+
+func newPortForward(config Config, namespace string) (*k8s.PortForward, error) {
+	kubeAPI, err := k8s.NewAPI(config.KubeConfig, config.KubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := k8s.NewServicePortForward(kubeAPI, namespace, apiService, 0, apiPort, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to port-forward to the public API: %s", err)
+	}
+
+	go func() {
+		if err := pf.Run(); err != nil {
+			log.Errorf("port-forward to the public API failed: %s", err)
+		}
+	}()
+	go func() {
+		for event := range pf.Events() {
+			if event.Type == k8s.PortForwardDisconnected {
+				log.Warnf("%s, reconnecting", event.Err)
+			}
+		}
+	}()
+
+	timeout := config.ConnectTimeout
+	if timeout == 0 {
+		timeout = DefaultConnectTimeout
+	}
+	select {
+	case <-pf.Ready():
+		return pf, nil
+	case <-time.After(timeout):
+		pf.Stop()
+		return nil, fmt.Errorf("timed out after %s waiting for the port-forward to the public API", timeout)
+	}
+}
+
+// Close stops the port-forward of the Client, if any.
+func (c *Client) Close() {
+	if c.portForward != nil {
+		c.portForward.Stop()
+	}
+}
+
+// API returns the underlying public API client, for the requests that don't
+// have a typed helper. Its requests aren't retried.
+func (c *Client) API() pb.ApiClient {
+	return c.api
+}
+
+// StatSummary returns the stats of the resources selected by params. An
+// error is returned if the public API rejects the request.
+func (c *Client) StatSummary(ctx context.Context, params util.StatsSummaryRequestParams) (*pb.StatSummaryResponse, error) {
+	req, err := util.BuildStatSummaryRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp *pb.StatSummaryResponse
+	err = c.retry(ctx, func() error {
+		rsp, err = c.api.StatSummary(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e := rsp.GetError(); e != nil {
+		return nil, errors.New(e.GetError())
+	}
+	return rsp, nil
+}
+
+// TopRoutes returns the stats of the routes of the resources selected by
+// params. An error is returned if the public API rejects the request.
+func (c *Client) TopRoutes(ctx context.Context, params util.TopRoutesRequestParams) (*pb.TopRoutesResponse, error) {
+	req, err := util.BuildTopRoutesRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp *pb.TopRoutesResponse
+	err = c.retry(ctx, func() error {
+		rsp, err = c.api.TopRoutes(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e := rsp.GetError(); e != nil {
+		return nil, errors.New(e.GetError())
+	}
+	return rsp, nil
+}
+
+// Tap streams the events of the requests matching params to events, until
+// ctx is done or the stream ends. The stream is opened with retries, but it
+// isn't resumed once it's established.
+func (c *Client) Tap(ctx context.Context, params util.TapRequestParams, events chan<- *pb.TapEvent) error {
+	req, err := util.BuildTapByResourceRequest(params)
+	if err != nil {
+		return err
+	}
+
+	var stream pb.Api_TapByResourceClient
+	err = c.retry(ctx, func() error {
+		stream, err = c.api.TapByResource(ctx, req)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// retry calls f until it succeeds, up to the configured number of retries,
+// backing off between the calls.
+func (c *Client) retry(ctx context.Context, f func() error) error {
+	backoff := c.backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = f()
+		if err == nil || attempt >= c.retries {
+			return err
+		}
+		log.Debugf("retrying failed request in %s: %s", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc"
+)
+
+// flakyAPIClient fails the first failures StatSummary requests.
+type flakyAPIClient struct {
+	public.MockAPIClient
+	failures int
+	calls    int
+}
+
+func (c *flakyAPIClient) StatSummary(ctx context.Context, in *pb.StatSummaryRequest, opts ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.New("connection refused")
+	}
+	return c.StatSummaryResponseToReturn, nil
+}
+
+func TestStatSummary(t *testing.T) {
+	params := util.StatsSummaryRequestParams{
+		StatsBaseRequestParams: util.StatsBaseRequestParams{
+			Namespace:    "emojivoto",
+			ResourceType: k8s.Deployment,
+		},
+	}
+	ok := &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{Ok: &pb.StatSummaryResponse_Ok{}},
+	}
+	rejected := &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Error{Error: &pb.ResourceError{Error: "invalid resource"}},
+	}
+
+	testCases := []struct {
+		failures      int
+		retries       int
+		response      *pb.StatSummaryResponse
+		expectedCalls int
+		expectedErr   string
+	}{
+		{failures: 0, retries: 3, response: ok, expectedCalls: 1},
+		{failures: 2, retries: 3, response: ok, expectedCalls: 3},
+		{failures: 5, retries: 3, response: ok, expectedCalls: 4, expectedErr: "connection refused"},
+		{failures: 1, retries: -1, response: ok, expectedCalls: 1, expectedErr: "connection refused"},
+		{failures: 0, retries: 3, response: rejected, expectedCalls: 1, expectedErr: "invalid resource"},
+	}
+
+	for i, tc := range testCases {
+		api := &flakyAPIClient{failures: tc.failures}
+		api.StatSummaryResponseToReturn = tc.response
+		c := &Client{api: api, retries: tc.retries}
+
+		_, err := c.StatSummary(context.Background(), params)
+		if tc.expectedErr == "" && err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if tc.expectedErr != "" && (err == nil || err.Error() != tc.expectedErr) {
+			t.Fatalf("test case %d: expected error %q, got %v", i, tc.expectedErr, err)
+		}
+		if api.calls != tc.expectedCalls {
+			t.Fatalf("test case %d: expected %d calls, got %d", i, tc.expectedCalls, api.calls)
+		}
+	}
+}
+
+func TestTap(t *testing.T) {
+	api := &public.MockAPIClient{
+		APITapByResourceClientToReturn: &public.MockAPITapByResourceClient{
+			TapEventsToReturn: []pb.TapEvent{
+				{Source: &pb.TcpAddress{Port: 1}},
+				{Source: &pb.TcpAddress{Port: 2}},
+			},
+		},
+	}
+	c := &Client{api: api}
+
+	events := make(chan *pb.TapEvent, 2)
+	err := c.Tap(context.Background(), util.TapRequestParams{
+		Resource:  "deploy/web",
+		Namespace: "emojivoto",
+	}, events)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	close(events)
+
+	var ports []uint32
+	for event := range events {
+		ports = append(ports, event.GetSource().GetPort())
+	}
+	if len(ports) != 2 || ports[0] != 1 || ports[1] != 2 {
+		t.Fatalf("Expected the events of ports 1 and 2, got %v", ports)
+	}
+}
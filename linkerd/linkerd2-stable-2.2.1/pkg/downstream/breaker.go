@@ -0,0 +1,128 @@
+package downstream
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker is a circuit breaker that opens after a number of consecutive
+// calls failing because the downstream service is unavailable. While it's
+// open, the calls fail fast, until the cooldown lets a single probe call
+// through: the circuit closes again if it succeeds, and reopens otherwise.
+type breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(name string, threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns an Unavailable error if the call must fail fast.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if wait := b.cooldown - b.now().Sub(b.openedAt); wait > 0 {
+			return status.Errorf(codes.Unavailable, "%s is unavailable, retrying in %s", b.name, wait.Round(time.Millisecond))
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		return nil
+	case circuitHalfOpen:
+		if b.probing {
+			return status.Errorf(codes.Unavailable, "%s is unavailable, waiting for a probe request", b.name)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the circuit with the result of a call that was allowed.
+// Only the Unavailable errors count as failures, since the other ones are
+// returned by a reachable service.
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if status.Code(err) != codes.Unavailable {
+		b.state = circuitClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.openLocked()
+	}
+}
+
+// trip opens the circuit, e.g. when the connection fails.
+func (b *breaker) trip() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != circuitOpen {
+		b.openLocked()
+	}
+}
+
+// reset closes the circuit, e.g. once the connection is re-established.
+func (b *breaker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = circuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *breaker) openLocked() {
+	b.state = circuitOpen
+	b.openedAt = b.now()
+	b.probing = false
+}
+
+func (b *breaker) snapshot() (circuitState, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.failures
+}
@@ -0,0 +1,96 @@
+package downstream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreaker(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	newTestBreaker := func() (*breaker, *time.Time) {
+		now := time.Now()
+		b := newBreaker("proxy-api", 2, 10*time.Second)
+		b.now = func() time.Time { return now }
+		return b, &now
+	}
+
+	t.Run("Opens after consecutive Unavailable errors", func(t *testing.T) {
+		b, _ := newTestBreaker()
+
+		for i := 0; i < 2; i++ {
+			if err := b.allow(); err != nil {
+				t.Fatalf("Unexpected error on call %d: %s", i, err)
+			}
+			b.record(unavailable)
+		}
+
+		err := b.allow()
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected an Unavailable error once open, got: %v", err)
+		}
+		if state, _ := b.snapshot(); state != circuitOpen {
+			t.Fatalf("Expected the circuit to be open, got: %s", state)
+		}
+	})
+
+	t.Run("Only counts the Unavailable errors as failures", func(t *testing.T) {
+		b, _ := newTestBreaker()
+
+		b.record(unavailable)
+		b.record(status.Error(codes.NotFound, "no such resource"))
+		b.record(errors.New("unknown"))
+		b.record(unavailable)
+
+		if state, failures := b.snapshot(); state != circuitClosed || failures != 1 {
+			t.Fatalf("Expected a closed circuit with 1 failure, got: %s with %d", state, failures)
+		}
+	})
+
+	t.Run("Lets a single probe through after the cooldown", func(t *testing.T) {
+		b, now := newTestBreaker()
+		b.trip()
+
+		*now = now.Add(10 * time.Second)
+		if err := b.allow(); err != nil {
+			t.Fatalf("Expected the probe to be allowed, got: %s", err)
+		}
+		if err := b.allow(); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected the calls to fail during the probe, got: %v", err)
+		}
+
+		b.record(nil)
+		if err := b.allow(); err != nil {
+			t.Fatalf("Expected the circuit to close after a successful probe, got: %s", err)
+		}
+	})
+
+	t.Run("Reopens when the probe fails", func(t *testing.T) {
+		b, now := newTestBreaker()
+		b.trip()
+
+		*now = now.Add(10 * time.Second)
+		if err := b.allow(); err != nil {
+			t.Fatalf("Expected the probe to be allowed, got: %s", err)
+		}
+		b.record(unavailable)
+
+		if err := b.allow(); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected the circuit to reopen after a failed probe, got: %v", err)
+		}
+	})
+
+	t.Run("Closes on reset", func(t *testing.T) {
+		b, _ := newTestBreaker()
+		b.trip()
+		b.reset()
+
+		if err := b.allow(); err != nil {
+			t.Fatalf("Expected the circuit to close on reset, got: %s", err)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+// Package downstream supervises the connections of the controllers to the
+// downstream gRPC services they depend on, so that a restart of one of them
+// doesn't fail the requests of its callers for longer than it's down.
+package downstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/plugin/ocgrpc"
+	"google.golang.org/grpc"
+	// Register the RoundRobin balancer.
+	_ "google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// MinKeepaliveTime is the shortest interval of the keepalive pings of the
+// clients, which the controller gRPC servers permit.
+const MinKeepaliveTime = 10 * time.Second
+
+// Options configures the supervision of a connection.
+type Options struct {
+	// MaxBackoff caps the delay between the reconnection attempts, which
+	// otherwise grows up to two minutes while the service is down.
+	MaxBackoff time.Duration
+
+	// FailureThreshold is the number of consecutive calls failing with
+	// Unavailable after which the circuit opens.
+	FailureThreshold int
+
+	// Cooldown is the time during which the calls fail fast once the circuit
+	// is open, before a probe call is let through.
+	Cooldown time.Duration
+
+	// KeepaliveTime is the interval of the keepalive pings, which keep the
+	// idle connections open through the idle timeouts of the network and
+	// detect the dead ones. Disabled if 0.
+	KeepaliveTime time.Duration
+
+	// KeepaliveTimeout is the time after which the connection is closed if
+	// a keepalive ping isn't acknowledged.
+	KeepaliveTimeout time.Duration
+
+	// MaxRecvMsgSize and MaxSendMsgSize are the maximum sizes of the
+	// messages in bytes; gRPC's defaults if 0.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+
+	// MaxRetries is the number of times a unary call failing with
+	// Unavailable is retried, waiting RetryBackoff before the first retry
+	// and twice as long before each of the next ones. The streams aren't
+	// retried.
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// Balancer is the load-balancing policy across the addresses the target
+	// resolves to, either PickFirst or RoundRobin.
+	Balancer string
+}
+
+// The load-balancing policies of Options.Balancer. PickFirst is gRPC's
+// default, which sends all the calls to the first address that connects.
+const (
+	PickFirst  = "pick_first"
+	RoundRobin = "round_robin"
+)
+
+// DefaultOptions is the default supervision of the connections.
+var DefaultOptions = Options{
+	MaxBackoff:       5 * time.Second,
+	FailureThreshold: 5,
+	Cooldown:         5 * time.Second,
+	KeepaliveTime:    30 * time.Second,
+	KeepaliveTimeout: 10 * time.Second,
+	MaxRetries:       2,
+	RetryBackoff:     100 * time.Millisecond,
+	Balancer:         PickFirst,
+}
+
+// Validate returns an error if the options are invalid.
+func (o Options) Validate() error {
+	if o.KeepaliveTime != 0 && o.KeepaliveTime < MinKeepaliveTime {
+		return fmt.Errorf("keepalive time %s is shorter than the minimum of %s", o.KeepaliveTime, MinKeepaliveTime)
+	}
+	if o.MaxRecvMsgSize < 0 || o.MaxSendMsgSize < 0 {
+		return errors.New("maximum message sizes must not be negative")
+	}
+	if o.MaxRetries < 0 {
+		return errors.New("maximum retries must not be negative")
+	}
+	if o.Balancer != PickFirst && o.Balancer != RoundRobin {
+		return fmt.Errorf("unsupported load-balancing policy %q, must be %q or %q", o.Balancer, PickFirst, RoundRobin)
+	}
+	return nil
+}
+
+func (o Options) dialOptions() []grpc.DialOption {
+	dialOpts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithBackoffMaxDelay(o.MaxBackoff),
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+	}
+	if o.Balancer == RoundRobin {
+		dialOpts = append(dialOpts, grpc.WithBalancerName(RoundRobin))
+	}
+	if o.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.KeepaliveTime,
+			Timeout:             o.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	callOpts := []grpc.CallOption{}
+	if o.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(o.MaxSendMsgSize))
+	}
+	if len(callOpts) > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	return dialOpts
+}
+
+// Conn is a supervised connection to a downstream gRPC service. It
+// reconnects with a capped backoff, fails the calls fast while the service
+// is known to be unavailable, and reports its state for the admin server.
+type Conn struct {
+	name    string
+	target  string
+	opts    Options
+	conn    *grpc.ClientConn
+	breaker *breaker
+	log     *log.Entry
+
+	mu             sync.Mutex
+	lastTransition time.Time
+	reconnects     int
+}
+
+// Status is the state of a Conn, as reported by Handler.
+type Status struct {
+	Name                string    `json:"name"`
+	Target              string    `json:"target"`
+	State               string    `json:"state"`
+	Circuit             string    `json:"circuit"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Reconnects          int       `json:"reconnects"`
+	LastTransition      time.Time `json:"lastTransition"`
+}
+
+// Dial returns a supervised connection to the given gRPC service, with the
+// tracing and request ID propagation of the controllers. With the RoundRobin
+// balancer, the target should use the dns:/// scheme to resolve to all the
+// replicas of the service.
+func Dial(name, target string, opts Options) (*Conn, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Conn{
+		name:           name,
+		target:         target,
+		opts:           opts,
+		breaker:        newBreaker(name, opts.FailureThreshold, opts.Cooldown),
+		log:            log.WithFields(log.Fields{"downstream": name, "target": target}),
+		lastTransition: time.Now(),
+	}
+
+	conn, err := grpc.Dial(target, append(opts.dialOptions(),
+		grpc.WithUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithStreamInterceptor(c.streamInterceptor),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return c, nil
+}
+
+// ClientConn returns the underlying connection, to create the clients of the
+// service with.
+func (c *Conn) ClientConn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Check is a ready check that fails while the connection isn't usable, e.g.
+// while it's reconnecting, or while the circuit is open.
+func (c *Conn) Check() error {
+	switch state := c.conn.GetState(); state {
+	case connectivity.Ready, connectivity.Idle:
+	default:
+		return fmt.Errorf("connection to %s is %s", c.name, state)
+	}
+	if circuit, _ := c.breaker.snapshot(); circuit == circuitOpen {
+		return fmt.Errorf("circuit to %s is open", c.name)
+	}
+	return nil
+}
+
+// Watch logs the state transitions of the connection until the given channel
+// is closed, opening the circuit when the connection fails and closing it
+// once it's re-established, without waiting for the cooldown.
+func (c *Conn) Watch(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	connected := false
+	state := c.conn.GetState()
+	for c.conn.WaitForStateChange(ctx, state) {
+		state = c.conn.GetState()
+
+		c.mu.Lock()
+		c.lastTransition = time.Now()
+		if state == connectivity.Ready {
+			if connected {
+				c.reconnects++
+			}
+			connected = true
+		}
+		c.mu.Unlock()
+
+		switch state {
+		case connectivity.Ready:
+			c.breaker.reset()
+			c.log.Infof("connection is %s", state)
+		case connectivity.TransientFailure:
+			c.breaker.trip()
+			c.log.Warnf("connection is %s, reconnecting", state)
+		case connectivity.Shutdown:
+			c.log.Infof("connection is %s", state)
+			return
+		default:
+			c.log.Debugf("connection is %s", state)
+		}
+	}
+}
+
+// Status returns the current state of the connection.
+func (c *Conn) Status() Status {
+	circuit, failures := c.breaker.snapshot()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Status{
+		Name:                c.name,
+		Target:              c.target,
+		State:               c.conn.GetState().String(),
+		Circuit:             circuit.String(),
+		ConsecutiveFailures: failures,
+		Reconnects:          c.reconnects,
+		LastTransition:      c.lastTransition,
+	}
+}
+
+// Handler returns an admin handler reporting the state of the given
+// connections as JSON.
+func Handler(conns ...*Conn) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		statuses := make([]Status, len(conns))
+		for i, c := range conns {
+			statuses[i] = c.Status()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(statuses); err != nil {
+			log.Errorf("failed to write the downstream statuses: %s", err)
+		}
+	})
+}
+
+// unaryInterceptor retries the calls failing with Unavailable, e.g. on a
+// connection that broke during a restart of the service, until the circuit
+// opens.
+func (c *Conn) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+		err := util.RequestIDUnaryClientInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+		c.breaker.record(err)
+		if status.Code(err) != codes.Unavailable || attempt >= c.opts.MaxRetries {
+			return err
+		}
+
+		c.log.Debugf("retrying %s in %s: %s", method, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// streamInterceptor only accounts for the errors opening the streams, since
+// the long-lived ones like tap streams end with the errors of their callers.
+func (c *Conn) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	stream, err := util.RequestIDStreamClientInterceptor(ctx, desc, cc, method, streamer, opts...)
+	c.breaker.record(err)
+	return stream, err
+}
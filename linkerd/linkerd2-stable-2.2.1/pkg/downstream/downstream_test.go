@@ -0,0 +1,112 @@
+package downstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		update func(*Options)
+		valid  bool
+	}{
+		{func(*Options) {}, true},
+		{func(o *Options) { o.KeepaliveTime = 0 }, true},
+		{func(o *Options) { o.Balancer = RoundRobin }, true},
+		{func(o *Options) { o.KeepaliveTime = time.Second }, false},
+		{func(o *Options) { o.MaxRecvMsgSize = -1 }, false},
+		{func(o *Options) { o.MaxRetries = -1 }, false},
+		{func(o *Options) { o.Balancer = "least_request" }, false},
+	}
+
+	for i, tc := range testCases {
+		opts := DefaultOptions
+		tc.update(&opts)
+		err := opts.Validate()
+		if tc.valid && err != nil {
+			t.Fatalf("test case %d: expected no error, got: %s", i, err)
+		}
+		if !tc.valid && err == nil {
+			t.Fatalf("test case %d: expected an error", i)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	newTestConn := func(maxRetries int) *Conn {
+		opts := DefaultOptions
+		opts.MaxRetries = maxRetries
+		opts.RetryBackoff = time.Millisecond
+		return &Conn{
+			name:    "proxy-api",
+			opts:    opts,
+			breaker: newBreaker("proxy-api", 10, time.Minute),
+			log:     log.WithField("downstream", "proxy-api"),
+		}
+	}
+
+	testCases := []struct {
+		maxRetries     int
+		errs           []error
+		expectedCalls  int
+		expectedStatus codes.Code
+	}{
+		{
+			maxRetries:     2,
+			errs:           []error{nil},
+			expectedCalls:  1,
+			expectedStatus: codes.OK,
+		},
+		{
+			maxRetries:     2,
+			errs:           []error{status.Error(codes.Unavailable, "restarting"), nil},
+			expectedCalls:  2,
+			expectedStatus: codes.OK,
+		},
+		{
+			maxRetries:     2,
+			errs:           []error{status.Error(codes.Unavailable, "restarting")},
+			expectedCalls:  3,
+			expectedStatus: codes.Unavailable,
+		},
+		{
+			maxRetries:     0,
+			errs:           []error{status.Error(codes.Unavailable, "restarting")},
+			expectedCalls:  1,
+			expectedStatus: codes.Unavailable,
+		},
+		{
+			maxRetries:     2,
+			errs:           []error{status.Error(codes.NotFound, "no such resource")},
+			expectedCalls:  1,
+			expectedStatus: codes.NotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		c := newTestConn(tc.maxRetries)
+		calls := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			err := tc.errs[len(tc.errs)-1]
+			if calls < len(tc.errs) {
+				err = tc.errs[calls]
+			}
+			calls++
+			return err
+		}
+
+		err := c.unaryInterceptor(context.Background(), "/Discovery/Endpoints", nil, nil, nil, invoker)
+		if status.Code(err) != tc.expectedStatus {
+			t.Fatalf("test case %d: expected status %s, got: %v", i, tc.expectedStatus, err)
+		}
+		if calls != tc.expectedCalls {
+			t.Fatalf("test case %d: expected %d calls, got %d", i, tc.expectedCalls, calls)
+		}
+	}
+}
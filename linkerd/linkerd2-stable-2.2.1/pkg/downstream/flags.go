@@ -0,0 +1,51 @@
+package downstream
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// Flags are the command-line flags of a controller that tune its connections
+// to the downstream gRPC services.
+type Flags struct {
+	maxBackoff       *time.Duration
+	keepaliveTime    *time.Duration
+	keepaliveTimeout *time.Duration
+	maxRecvMsgSize   *int
+	maxSendMsgSize   *int
+	maxRetries       *int
+	retryBackoff     *time.Duration
+	balancer         *string
+}
+
+// NewFlags registers the -grpc-client-* flags, defaulting to DefaultOptions.
+// They must be parsed before the options they hold are used.
+func NewFlags() *Flags {
+	d := DefaultOptions
+	return &Flags{
+		maxBackoff:       flag.Duration("grpc-client-max-reconnect-backoff", d.MaxBackoff, "longest delay between the attempts to reconnect to the downstream gRPC services"),
+		keepaliveTime:    flag.Duration("grpc-client-keepalive-time", d.KeepaliveTime, fmt.Sprintf("interval of the keepalive pings on the connections to the downstream gRPC services, at least %s; disabled if 0", MinKeepaliveTime)),
+		keepaliveTimeout: flag.Duration("grpc-client-keepalive-timeout", d.KeepaliveTimeout, "time after which a connection whose keepalive ping isn't acknowledged is closed"),
+		maxRecvMsgSize:   flag.Int("grpc-client-max-recv-msg-size", d.MaxRecvMsgSize, "maximum size in bytes of the messages received from the downstream gRPC services; 0 for gRPC's default (4MiB)"),
+		maxSendMsgSize:   flag.Int("grpc-client-max-send-msg-size", d.MaxSendMsgSize, "maximum size in bytes of the messages sent to the downstream gRPC services; 0 for gRPC's default (unlimited)"),
+		maxRetries:       flag.Int("grpc-client-max-retries", d.MaxRetries, "number of times a unary call to a downstream gRPC service failing with Unavailable is retried"),
+		retryBackoff:     flag.Duration("grpc-client-retry-backoff", d.RetryBackoff, "delay before the first retry of a unary call, doubled before each of the next ones"),
+		balancer:         flag.String("grpc-client-balancer", d.Balancer, fmt.Sprintf("load-balancing policy across the replicas of the downstream gRPC services, %q or %q; %q requires their addresses to use the dns:/// scheme", PickFirst, RoundRobin, RoundRobin)),
+	}
+}
+
+// Options returns the options set by the flags, with the circuit breaking of
+// DefaultOptions.
+func (f *Flags) Options() Options {
+	opts := DefaultOptions
+	opts.MaxBackoff = *f.maxBackoff
+	opts.KeepaliveTime = *f.keepaliveTime
+	opts.KeepaliveTimeout = *f.keepaliveTimeout
+	opts.MaxRecvMsgSize = *f.maxRecvMsgSize
+	opts.MaxSendMsgSize = *f.maxSendMsgSize
+	opts.MaxRetries = *f.maxRetries
+	opts.RetryBackoff = *f.retryBackoff
+	opts.Balancer = *f.balancer
+	return opts
+}
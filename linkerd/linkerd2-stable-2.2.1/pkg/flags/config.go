@@ -0,0 +1,135 @@
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// envPrefix prefixes the names of the environment variables that set flags,
+// e.g. LINKERD_LOG_LEVEL sets -log-level.
+const envPrefix = "LINKERD_"
+
+// configFlag is the name of the flag with the path of the config file.
+const configFlag = "config"
+
+// envName returns the environment variable that sets the given flag.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// applyEnvAndConfig sets the flags of fs that weren't set on the command line
+// from their environment variable or, failing that, from the config file at
+// configPath, if it's not empty. The config file is a YAML map of flag names
+// to values, e.g.:
+//
+//	log-level: debug
+//	controller-namespace: linkerd
+//
+// The command line thus takes precedence over the environment, which takes
+// precedence over the config file, which takes precedence over the defaults.
+func applyEnvAndConfig(fs *flag.FlagSet, configPath string, lookupEnv func(string) (string, bool)) error {
+	config, err := readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag in %s: %s", configPath, name)
+		}
+	}
+
+	setOnCommandLine := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+
+	var setErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if setErr != nil || setOnCommandLine[f.Name] || f.Name == configFlag {
+			return
+		}
+
+		if value, ok := lookupEnv(envName(f.Name)); ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				setErr = fmt.Errorf("invalid value %q for %s: %s", value, envName(f.Name), err)
+			}
+			return
+		}
+
+		if value, ok := config[f.Name]; ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				setErr = fmt.Errorf("invalid value %q for %s in %s: %s", value, f.Name, configPath, err)
+			}
+		}
+	})
+	return setErr
+}
+
+// readConfig reads the flag values of the config file at the given path, or
+// none if it's empty. Lists are joined with commas, as expected by the flags
+// that take several values.
+func readConfig(path string) (map[string]string, error) {
+	config := map[string]string{}
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %s", err)
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %s", path, err)
+	}
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = configValue(item)
+			}
+			config[name] = strings.Join(items, ",")
+		case map[string]interface{}:
+			return nil, fmt.Errorf("invalid value for %s in %s: expected a scalar or a list", name, path)
+		default:
+			config[name] = configValue(v)
+		}
+	}
+
+	return config, nil
+}
+
+func configValue(value interface{}) string {
+	// YAML numbers are decoded as floats, which would otherwise be formatted
+	// with an exponent when they're large, e.g. 1e+06
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// configPath returns the path of the config file, which may itself be set by
+// its environment variable.
+func configPath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return os.Getenv(envName(configFlag))
+}
@@ -0,0 +1,80 @@
+package flags
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestApplyEnvAndConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	config := `
+addr: ":9000"
+controller-namespace: from-config
+log-level: from-config
+namespaces: [emojivoto, books]
+qps: 1000000
+`
+	if _, err := file.WriteString(config); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	file.Close()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	addr := fs.String("addr", ":8085", "")
+	controllerNamespace := fs.String("controller-namespace", "linkerd", "")
+	logLevel := fs.String("log-level", "info", "")
+	namespaces := fs.String("namespaces", "", "")
+	qps := fs.Int("qps", 100, "")
+	metricsAddr := fs.String("metrics-addr", ":9995", "")
+
+	if err := fs.Parse([]string{"-log-level=from-args"}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	env := map[string]string{
+		"LINKERD_LOG_LEVEL":            "from-env",
+		"LINKERD_CONTROLLER_NAMESPACE": "from-env",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	if err := applyEnvAndConfig(fs, file.Name(), lookupEnv); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"addr":                 ":9000",
+		"controller-namespace": "from-env",
+		"log-level":            "from-args",
+		"namespaces":           "emojivoto,books",
+		"metrics-addr":         ":9995",
+	}
+	actual := map[string]string{
+		"addr":                 *addr,
+		"controller-namespace": *controllerNamespace,
+		"log-level":            *logLevel,
+		"namespaces":           *namespaces,
+		"metrics-addr":         *metricsAddr,
+	}
+	for name, value := range expected {
+		if actual[name] != value {
+			t.Fatalf("Expected %s to be %q, got %q", name, value, actual[name])
+		}
+	}
+	if *qps != 1000000 {
+		t.Fatalf("Expected qps to be 1000000, got %d", *qps)
+	}
+
+	if err := applyEnvAndConfig(flag.NewFlagSet("test", flag.ContinueOnError), file.Name(), lookupEnv); err == nil {
+		t.Fatal("Expected an error for the unknown flags of the config file")
+	}
+}
@@ -4,42 +4,81 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/klog"
 )
 
+const (
+	// LogFormatText is the value of the -log-format flag for human-readable
+	// logs.
+	LogFormatText = "text"
+
+	// LogFormatJSON is the value of the -log-format flag for logs with one
+	// JSON object per line.
+	LogFormatJSON = "json"
+)
+
 // ConfigureAndParse adds flags that are common to all go processes. This
 // func calls flag.Parse(), so it should be called after all other flags have
-// been configured.
+// been configured. The flags that aren't set on the command line are then set
+// from their LINKERD_<FLAG> environment variable, or from the -config file.
 func ConfigureAndParse() {
 	klog.InitFlags(nil)
 	flag.Set("stderrthreshold", "FATAL")
-	flag.Set("logtostderr", "false")
-	flag.Set("log_file", "/dev/null")
+	flag.Set("logtostderr", "true")
 	flag.Set("v", "0")
 	logLevel := flag.String("log-level", log.InfoLevel.String(),
 		"log level, must be one of: panic, fatal, error, warn, info, debug")
+	logFormat := flag.String("log-format", LogFormatText,
+		"log format, must be one of: "+LogFormatText+", "+LogFormatJSON)
 	printVersion := flag.Bool("version", false, "print version and exit")
+	config := flag.String(configFlag, "",
+		"path to a YAML file of flag names to values, e.g. \"log-level: debug\"; the flags can also be set by "+
+			envPrefix+"<FLAG> environment variables, e.g. "+envName("log-level")+", which take precedence over the file")
 
 	flag.Parse()
+	if err := applyEnvAndConfig(flag.CommandLine, configPath(*config), os.LookupEnv); err != nil {
+		log.Fatal(err.Error())
+	}
 
+	setLogFormat(*logFormat)
 	setLogLevel(*logLevel)
+	log.AddHook(&componentHook{component: filepath.Base(os.Args[0])})
+	redirectKlog()
 	maybePrintVersionAndExit(*printVersion)
 }
 
+func setLogFormat(logFormat string) {
+	switch logFormat {
+	case LogFormatText:
+		log.SetFormatter(&log.TextFormatter{})
+	case LogFormatJSON:
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		log.Fatalf("invalid log-format: %s", logFormat)
+	}
+}
+
 func setLogLevel(logLevel string) {
 	level, err := log.ParseLevel(logLevel)
 	if err != nil {
 		log.Fatalf("invalid log-level: %s", logLevel)
 	}
+	SetLogLevel(level)
+}
+
+// SetLogLevel sets the level of logrus, and the verbosity of klog to match
+// it.
+func SetLogLevel(level log.Level) {
 	log.SetLevel(level)
 
 	if level == log.DebugLevel {
-		flag.Set("stderrthreshold", "INFO")
-		flag.Set("logtostderr", "true")
 		flag.Set("v", "10")
+	} else {
+		flag.Set("v", "0")
 	}
 }
 
@@ -50,3 +89,20 @@ func maybePrintVersionAndExit(printVersion bool) {
 	}
 	log.Infof("running version %s", version.Version)
 }
+
+// componentHook sets the component field of the log entries that don't have
+// one, so that every entry of a process names the process.
+type componentHook struct {
+	component string
+}
+
+func (h *componentHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *componentHook) Fire(entry *log.Entry) error {
+	if _, ok := entry.Data["component"]; !ok {
+		entry.Data["component"] = h.component
+	}
+	return nil
+}
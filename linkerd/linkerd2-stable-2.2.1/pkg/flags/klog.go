@@ -0,0 +1,76 @@
+package flags
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// klogHeader matches the header of a klog line, e.g.
+// "E0102 15:04:05.123456    1234 reflector.go:134] message", capturing the
+// severity, the caller and the message.
+var klogHeader = regexp.MustCompile(`^([IWEF])\d{4} \d{2}:\d{2}:\d{2}\.\d{6}\s+\d+ ([^\]]+)\] (.*)$`)
+
+// redirectKlog routes the output of klog through logrus, so that the logs of
+// client-go have the format and the fields of the other logs. This version of
+// klog can only write to stderr or to files, so os.Stderr is replaced with a
+// pipe, whose lines are logged with logrus, which keeps writing to the
+// original stderr.
+func redirectKlog() {
+	r, w, err := os.Pipe()
+	if err != nil {
+		log.Warnf("failed to redirect klog: %s", err)
+		return
+	}
+
+	log.SetOutput(os.Stderr)
+	os.Stderr = w
+
+	go forwardKlog(r, log.WithField("component", "klog"))
+}
+
+// forwardKlog logs the klog lines read from r with the given logger, until r
+// is closed. klog's info lines are logged at the debug level, as they're
+// mostly of interest when debugging client-go.
+func forwardKlog(r io.Reader, logger *log.Entry) {
+	scanner := bufio.NewScanner(r)
+	level := log.ErrorLevel
+	caller := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// lines without a header, e.g. the stack traces of fatal errors,
+		// belong to the previous line
+		msg := line
+		if match := klogHeader.FindStringSubmatch(line); match != nil {
+			level = klogLevel(match[1])
+			caller = match[2]
+			msg = match[3]
+		}
+
+		entry := logger.WithField("caller", caller)
+		switch level {
+		case log.DebugLevel:
+			entry.Debug(msg)
+		case log.WarnLevel:
+			entry.Warn(msg)
+		default:
+			entry.Error(msg)
+		}
+	}
+}
+
+func klogLevel(severity string) log.Level {
+	switch severity {
+	case "I":
+		return log.DebugLevel
+	case "W":
+		return log.WarnLevel
+	default:
+		// fatal lines are logged as errors, as klog exits on its own
+		return log.ErrorLevel
+	}
+}
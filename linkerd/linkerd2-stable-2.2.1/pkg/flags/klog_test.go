@@ -0,0 +1,51 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestForwardKlog(t *testing.T) {
+	klogOutput := strings.Join([]string{
+		"I0102 15:04:05.123456    1234 reflector.go:202] Starting reflector",
+		"W0102 15:04:05.123456    1234 reflector.go:270] watch of *v1.Pod ended with: too old resource version",
+		"E0102 15:04:05.123456    1234 reflector.go:134] Failed to list *v1.Pod: forbidden",
+		"goroutine 1 [running]:",
+	}, "\n")
+
+	var out bytes.Buffer
+	logger := log.New()
+	logger.Out = &out
+	logger.Formatter = &log.JSONFormatter{}
+	logger.Level = log.DebugLevel
+
+	forwardKlog(strings.NewReader(klogOutput), log.NewEntry(logger))
+
+	expected := []map[string]string{
+		{"level": "debug", "caller": "reflector.go:202", "msg": "Starting reflector"},
+		{"level": "warning", "caller": "reflector.go:270", "msg": "watch of *v1.Pod ended with: too old resource version"},
+		{"level": "error", "caller": "reflector.go:134", "msg": "Failed to list *v1.Pod: forbidden"},
+		{"level": "error", "caller": "reflector.go:134", "msg": "goroutine 1 [running]:"},
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d log lines, got %d: %s", len(expected), len(lines), out.String())
+	}
+
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for key, value := range expected[i] {
+			if entry[key] != value {
+				t.Fatalf("Expected %s [%s] in line %d, got [%v]", key, value, i, entry[key])
+			}
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
+	"github.com/linkerd/linkerd2/pkg/tls"
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
 	authorizationapi "k8s.io/api/authorization/v1beta1"
@@ -32,7 +33,8 @@ const (
 	KubernetesAPIChecks CategoryID = "kubernetes-api"
 
 	// KubernetesVersionChecks validate that the cluster meets the minimum version
-	// requirements.
+	// requirements, and warn about the API versions of the install manifests
+	// that it deprecates.
 	KubernetesVersionChecks CategoryID = "kubernetes-version"
 
 	// LinkerdPreInstall* checks enabled by `linkerd check --pre`
@@ -74,6 +76,14 @@ const (
 	// checks must be added first.
 	LinkerdAPIChecks CategoryID = "linkerd-api"
 
+	// LinkerdIdentityChecks adds checks to validate the operator-provided
+	// issuer of the TLS identities, if the control plane was installed with
+	// --identity-issuer-secret.
+	// These checks are dependent on the output of KubernetesAPIChecks and
+	// `controlPlanePods` from LinkerdAPIChecks, so those checks must be added
+	// first.
+	LinkerdIdentityChecks CategoryID = "linkerd-identity"
+
 	// LinkerdServiceProfileChecks add a check validate any ServiceProfiles that
 	// may already be installed.
 	// These checks are dependent on the output of KubernetesAPIChecks, so those
@@ -92,6 +102,16 @@ const (
 	// 3) `serverVersion` from `LinkerdControlPlaneExistenceChecks`
 	LinkerdControlPlaneVersionChecks CategoryID = "control-plane-version"
 
+	// LinkerdPreUpgradeChecks adds checks to validate that the control plane
+	// can be upgraded to the version of Options.UpgradeVersion: that it is
+	// newer than the running control plane, that the cluster serves the API
+	// versions of the CRDs and webhooks of the install, and that the control
+	// plane has no deprecated settings. This check only runs as part of the set
+	// of pre-upgrade checks.
+	// These checks are dependent on the output of KubernetesAPIChecks and
+	// LinkerdControlPlaneExistenceChecks, so those checks must be added first.
+	LinkerdPreUpgradeChecks CategoryID = "pre-linkerd-upgrade"
+
 	// LinkerdDataPlaneChecks adds data plane checks to validate that the data
 	// plane namespace exists, and that the the proxy containers are in a ready
 	// state and running the latest available version.
@@ -109,6 +129,17 @@ const HintBaseURL = "https://linkerd.io/checks/#"
 var (
 	retryWindow    = 5 * time.Second
 	requestTimeout = 30 * time.Second
+
+	// issuerRenewBefore is how long before its expiry the issuer certificate
+	// is reported as about to expire.
+	issuerRenewBefore = 30 * 24 * time.Hour
+
+	// deprecatedControlPlaneArgs are the arguments of the control plane
+	// containers for the settings that upgrades don't carry over, with the
+	// reason why.
+	deprecatedControlPlaneArgs = map[string]string{
+		"-single-namespace=true": "the experimental single-namespace mode is deprecated, reinstall the control plane without --single-namespace",
+	}
 )
 
 type checker struct {
@@ -167,8 +198,10 @@ type Options struct {
 	DataPlaneNamespace    string
 	KubeConfig            string
 	KubeContext           string
+	ClientAuth            k8s.ClientAuth
 	APIAddr               string
 	VersionOverride       string
+	UpgradeVersion        string
 	RetryDeadline         time.Time
 }
 
@@ -188,6 +221,7 @@ type HealthChecker struct {
 	apiClient        public.APIClient
 	latestVersions   version.Channels
 	serverVersion    string
+	issuer           *tls.CA
 }
 
 // NewHealthChecker returns an initialized HealthChecker
@@ -228,7 +262,7 @@ func (hc *HealthChecker) allCategories() []category {
 					hintAnchor:  "k8s-api",
 					fatal:       true,
 					check: func(context.Context) (err error) {
-						hc.kubeAPI, err = k8s.NewAPI(hc.KubeConfig, hc.KubeContext)
+						hc.kubeAPI, err = k8s.NewAPIWithAuth(hc.KubeConfig, hc.KubeContext, hc.ClientAuth)
 						if err != nil {
 							return
 						}
@@ -264,6 +298,14 @@ func (hc *HealthChecker) allCategories() []category {
 						return hc.kubeAPI.CheckVersion(hc.kubeVersion)
 					},
 				},
+				{
+					description: "install manifests use no deprecated API versions",
+					hintAnchor:  "k8s-api-deprecations",
+					warning:     true,
+					check: func(context.Context) error {
+						return hc.kubeAPI.CheckDeprecatedAPIVersions(hc.kubeVersion)
+					},
+				},
 			},
 		},
 		{
@@ -396,7 +438,7 @@ func (hc *HealthChecker) allCategories() []category {
 					fatal:       true,
 					check: func(context.Context) (err error) {
 						if hc.APIAddr != "" {
-							hc.apiClient, err = public.NewInternalClient(hc.ControlPlaneNamespace, hc.APIAddr)
+							hc.apiClient, err = public.NewInternalClientWithCA(hc.ControlPlaneNamespace, hc.APIAddr, hc.ClientAuth.CertificateAuthority)
 						} else {
 							hc.apiClient, err = public.NewExternalClient(hc.ControlPlaneNamespace, hc.kubeAPI)
 						}
@@ -443,6 +485,34 @@ func (hc *HealthChecker) allCategories() []category {
 				},
 			},
 		},
+		{
+			id: LinkerdIdentityChecks,
+			checkers: []checker{
+				{
+					description: "issuer certificate is valid",
+					hintAnchor:  "l5d-identity-issuer",
+					check: func(context.Context) (err error) {
+						hc.issuer, err = hc.checkIssuer()
+						return
+					},
+				},
+				{
+					description: "issuer certificate is not about to expire",
+					hintAnchor:  "l5d-identity-issuer-expiry",
+					warning:     true,
+					check: func(context.Context) error {
+						if hc.issuer == nil {
+							return nil
+						}
+						notAfter := hc.issuer.Issuer().NotAfter
+						if time.Until(notAfter) < issuerRenewBefore {
+							return fmt.Errorf("The issuer certificate expires at %s, rotate it before then", notAfter)
+						}
+						return nil
+					},
+				},
+			},
+		},
 		{
 			id: LinkerdServiceProfileChecks,
 			checkers: []checker{
@@ -521,6 +591,70 @@ func (hc *HealthChecker) allCategories() []category {
 				},
 			},
 		},
+		{
+			id: LinkerdPreUpgradeChecks,
+			checkers: []checker{
+				{
+					description: "cli matches the target version",
+					hintAnchor:  "pre-upgrade-cli-version",
+					warning:     true,
+					check: func(context.Context) error {
+						if hc.UpgradeVersion != version.Version {
+							return fmt.Errorf("the checks are based on the %s cli, run them with the %s cli to check its requirements", version.Version, hc.UpgradeVersion)
+						}
+						return nil
+					},
+				},
+				{
+					description: "target version is newer than the control plane",
+					hintAnchor:  "pre-upgrade-version",
+					check: func(context.Context) error {
+						return version.CheckUpgrade(hc.serverVersion, hc.UpgradeVersion)
+					},
+				},
+				{
+					description: "is running a Kubernetes version supported by the target version",
+					hintAnchor:  "pre-upgrade-k8s-version",
+					check: func(context.Context) error {
+						return hc.kubeAPI.CheckVersion(hc.kubeVersion)
+					},
+				},
+				{
+					description: "CustomResourceDefinition versions are served",
+					hintAnchor:  "pre-upgrade-crd",
+					check: func(context.Context) error {
+						groups, err := hc.serverGroups()
+						if err != nil {
+							return err
+						}
+						if err := checkServedVersion(groups, "apiextensions.k8s.io", "v1beta1", true); err != nil {
+							return err
+						}
+						// the ServiceProfile CRD is not installed in single-namespace mode
+						return checkServedVersion(groups, "linkerd.io", "v1alpha1", false)
+					},
+				},
+				{
+					description: "webhook API versions are served",
+					hintAnchor:  "pre-upgrade-webhook",
+					check: func(context.Context) error {
+						groups, err := hc.serverGroups()
+						if err != nil {
+							return err
+						}
+						return checkServedVersion(groups, "admissionregistration.k8s.io", "v1beta1", true)
+					},
+				},
+				{
+					description: "control plane has no deprecated settings",
+					hintAnchor:  "pre-upgrade-deprecated",
+					warning:     true,
+					check: func(context.Context) error {
+						return checkDeprecatedArgs(hc.controlPlanePods)
+					},
+				},
+			},
+		},
 		{
 			id: LinkerdDataPlaneChecks,
 			checkers: []checker{
@@ -546,6 +680,39 @@ func (hc *HealthChecker) allCategories() []category {
 						return validateDataPlanePods(pods, hc.DataPlaneNamespace)
 					},
 				},
+				{
+					description: "data plane proxies enforce the TLS policy",
+					hintAnchor:  "l5d-data-plane-tls",
+					check: func(ctx context.Context) error {
+						// only the control planes installed with --tls=required
+						// require it from the data plane
+						if !proxiesRequireTLS(hc.controlPlanePods) {
+							return nil
+						}
+
+						pods, err := hc.getDataPlanePods(ctx)
+						if err != nil {
+							return err
+						}
+
+						// the names of the pods of the public API are prefixed
+						// with their namespace
+						namespaces := map[string]bool{}
+						for _, pod := range pods {
+							namespaces[strings.Split(pod.Name, "/")[0]] = true
+						}
+						k8sPods := []v1.Pod{}
+						for namespace := range namespaces {
+							nsPods, err := hc.kubeAPI.GetPodsByNamespace(ctx, hc.httpClient, namespace)
+							if err != nil {
+								return err
+							}
+							k8sPods = append(k8sPods, nsPods...)
+						}
+
+						return validateDataPlaneTLS(pods, k8sPods)
+					},
+				},
 				{
 					description:   "data plane proxy metrics are present in Prometheus",
 					hintAnchor:    "l5d-data-plane-prom",
@@ -772,16 +939,26 @@ func (hc *HealthChecker) getDataPlanePods(ctx context.Context) ([]*pb.Pod, error
 	return pods, nil
 }
 
-func (hc *HealthChecker) checkCanCreate(namespace, group, version, resource string) error {
+// kubeClientset returns the Kubernetes clientset of the checks, initializing
+// it on first use.
+func (hc *HealthChecker) kubeClientset() (*kubernetes.Clientset, error) {
 	if hc.clientset == nil {
 		var err error
 		hc.clientset, err = kubernetes.NewForConfig(hc.kubeAPI.Config)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
+	return hc.clientset, nil
+}
 
-	auth := hc.clientset.AuthorizationV1beta1()
+func (hc *HealthChecker) checkCanCreate(namespace, group, version, resource string) error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	auth := clientset.AuthorizationV1beta1()
 
 	sar := &authorizationapi.SelfSubjectAccessReview{
 		Spec: authorizationapi.SelfSubjectAccessReviewSpec{
@@ -809,6 +986,103 @@ func (hc *HealthChecker) checkCanCreate(namespace, group, version, resource stri
 	return nil
 }
 
+// serverGroups returns the API groups served by the cluster.
+func (hc *HealthChecker) serverGroups() (*meta_v1.APIGroupList, error) {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return nil, err
+	}
+	return clientset.Discovery().ServerGroups()
+}
+
+// checkServedVersion returns an error if the API group isn't served with the
+// given version. If the group is not required, the check passes when it isn't
+// served at all.
+func checkServedVersion(groups *meta_v1.APIGroupList, group, version string, required bool) error {
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		versions := []string{}
+		for _, v := range g.Versions {
+			if v.Version == version {
+				return nil
+			}
+			versions = append(versions, v.Version)
+		}
+		return fmt.Errorf("%s/%s is not served, the cluster serves %s", group, version, strings.Join(versions, ", "))
+	}
+	if required {
+		return fmt.Errorf("%s/%s is not served", group, version)
+	}
+	return nil
+}
+
+// checkDeprecatedArgs returns an error listing the containers of the given
+// pods with deprecatedControlPlaneArgs.
+func checkDeprecatedArgs(pods []v1.Pod) error {
+	deprecated := []string{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			for _, arg := range container.Args {
+				if reason, ok := deprecatedControlPlaneArgs[arg]; ok {
+					deprecated = append(deprecated, fmt.Sprintf("%s/%s %s: %s", pod.Name, container.Name, arg, reason))
+				}
+			}
+		}
+	}
+
+	if len(deprecated) > 0 {
+		return fmt.Errorf("The control plane has deprecated settings:\n\t%s", strings.Join(deprecated, "\n\t"))
+	}
+	return nil
+}
+
+// checkIssuer validates the operator-provided issuer secret mounted to the
+// CA, and returns a CA for it, or nil if the CA generates its own.
+func (hc *HealthChecker) checkIssuer() (*tls.CA, error) {
+	secretName := issuerSecretName(hc.controlPlanePods)
+	if secretName == "" {
+		return nil, nil
+	}
+
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := clientset.CoreV1().Secrets(hc.ControlPlaneNamespace).Get(secretName, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := tls.NewCAFromPEM(
+		secret.Data[k8s.IssuerCertFileName],
+		secret.Data[k8s.IssuerPrivateKeyFileName],
+		secret.Data[k8s.IssuerTrustAnchorsFileName],
+	)
+	if err != nil {
+		return nil, fmt.Errorf("The issuer in the \"%s\" secret is invalid: %s", secretName, err)
+	}
+	return ca, nil
+}
+
+// issuerSecretName returns the name of the issuer secret mounted to the CA
+// pod, if any.
+func issuerSecretName(pods []v1.Pod) string {
+	for _, pod := range pods {
+		if !strings.HasPrefix(pod.Name, "linkerd-ca-") {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == k8s.IdentityIssuerVolumeName && volume.Secret != nil {
+				return volume.Secret.SecretName
+			}
+		}
+	}
+	return ""
+}
+
 func (hc *HealthChecker) validateServiceProfiles() error {
 	if hc.spClientset == nil {
 		var err error
@@ -863,7 +1137,12 @@ func getPodStatuses(pods []v1.Pod) map[string][]v1.ContainerStatus {
 func validateControlPlanePods(pods []v1.Pod) error {
 	statuses := getPodStatuses(pods)
 
-	names := []string{"controller", "prometheus", "web", "grafana"}
+	names := []string{"controller", "prometheus", "web"}
+	// Grafana can be disabled at install, in favor of an external one; its
+	// pods are only checked if it has any, running or not.
+	if hasPods(pods, "linkerd-grafana-") {
+		names = append(names, "grafana")
+	}
 	if _, found := statuses["ca"]; found {
 		names = append(names, "ca")
 	}
@@ -887,6 +1166,15 @@ func validateControlPlanePods(pods []v1.Pod) error {
 	return nil
 }
 
+func hasPods(pods []v1.Pod, prefix string) bool {
+	for _, pod := range pods {
+		if strings.HasPrefix(pod.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func checkControllerRunning(pods []v1.Pod) error {
 	statuses := getPodStatuses(pods)
 	if _, ok := statuses["controller"]; !ok {
@@ -919,6 +1207,54 @@ func validateDataPlanePods(pods []*pb.Pod, targetNamespace string) error {
 	return nil
 }
 
+// proxiesRequireTLS returns true if any of the proxies of the given pods
+// refuses plaintext connections.
+func proxiesRequireTLS(pods []v1.Pod) bool {
+	for _, pod := range pods {
+		if proxyRequiresTLS(pod) {
+			return true
+		}
+	}
+	return false
+}
+
+func proxyRequiresTLS(pod v1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != k8s.ProxyContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == k8s.ProxyTLSRequiredEnvVar && env.Value == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateDataPlaneTLS returns an error if any of the data plane pods runs a
+// proxy that accepts plaintext connections, e.g. because it was injected
+// before TLS was required.
+func validateDataPlaneTLS(pods []*pb.Pod, k8sPods []v1.Pod) error {
+	specs := map[string]v1.Pod{}
+	for _, pod := range k8sPods {
+		specs[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	plaintext := []string{}
+	for _, pod := range pods {
+		spec, ok := specs[pod.Name]
+		if ok && !proxyRequiresTLS(spec) {
+			plaintext = append(plaintext, pod.Name)
+		}
+	}
+
+	if len(plaintext) > 0 {
+		return fmt.Errorf("Data plane proxies accept plaintext connections, re-inject them with --tls=required:\n\t%s", strings.Join(plaintext, "\n\t"))
+	}
+	return nil
+}
+
 func validateDataPlanePodReporting(pods []*pb.Pod) error {
 	notInPrometheus := []string{}
 
@@ -354,6 +354,36 @@ func TestValidateControlPlanePods(t *testing.T) {
 		}
 	})
 
+	t.Run("Returns an error if the grafana pod isn't running", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, true),
+			pod("linkerd-grafana-5b7d796646-hh46d", v1.PodFailed, false),
+			pod("linkerd-prometheus-74d6879cd6-bbdk6", v1.PodRunning, true),
+			pod("linkerd-web-98c9ddbcd-7b5lh", v1.PodRunning, true),
+		}
+
+		err := validateControlPlanePods(pods)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "No running pods for \"linkerd-grafana\"" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns nil if grafana is disabled", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, true),
+			pod("linkerd-prometheus-74d6879cd6-bbdk6", v1.PodRunning, true),
+			pod("linkerd-web-98c9ddbcd-7b5lh", v1.PodRunning, true),
+		}
+
+		err := validateControlPlanePods(pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
 	t.Run("Returns nil if all linkerd pods are running and pod list includes non-linkerd pod", func(t *testing.T) {
 		pods := []v1.Pod{
 			pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, true),
@@ -466,3 +496,151 @@ func TestValidateDataPlanePodReporting(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateDataPlaneTLS(t *testing.T) {
+	proxyPod := func(namespace, name string, requireTLS bool) v1.Pod {
+		env := []v1.EnvVar{}
+		if requireTLS {
+			env = append(env, v1.EnvVar{Name: "LINKERD2_PROXY_TLS_REQUIRED", Value: "true"})
+		}
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app"},
+					{Name: "linkerd-proxy", Env: env},
+				},
+			},
+		}
+	}
+
+	t.Run("Detects the control planes that require TLS", func(t *testing.T) {
+		if proxiesRequireTLS([]v1.Pod{proxyPod("linkerd", "controller", false)}) {
+			t.Fatal("Expected TLS not to be required")
+		}
+		if !proxiesRequireTLS([]v1.Pod{proxyPod("linkerd", "web", false), proxyPod("linkerd", "controller", true)}) {
+			t.Fatal("Expected TLS to be required")
+		}
+	})
+
+	t.Run("Returns success if all proxies require TLS", func(t *testing.T) {
+		pods := []*pb.Pod{
+			&pb.Pod{Name: "ns1/test1"},
+			&pb.Pod{Name: "ns2/test2"},
+		}
+		k8sPods := []v1.Pod{
+			proxyPod("ns1", "test1", true),
+			proxyPod("ns2", "test2", true),
+		}
+
+		err := validateDataPlaneTLS(pods, k8sPods)
+		if err != nil {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if a proxy accepts plaintext", func(t *testing.T) {
+		pods := []*pb.Pod{
+			&pb.Pod{Name: "ns1/test1"},
+			&pb.Pod{Name: "ns2/test2"},
+		}
+		k8sPods := []v1.Pod{
+			proxyPod("ns1", "test1", true),
+			proxyPod("ns2", "test2", false),
+		}
+
+		err := validateDataPlaneTLS(pods, k8sPods)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.Contains(err.Error(), "ns2/test2") || strings.Contains(err.Error(), "ns1/test1") {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestCheckServedVersion(t *testing.T) {
+	groups := &meta.APIGroupList{
+		Groups: []meta.APIGroup{
+			{
+				Name: "admissionregistration.k8s.io",
+				Versions: []meta.GroupVersionForDiscovery{
+					{GroupVersion: "admissionregistration.k8s.io/v1", Version: "v1"},
+					{GroupVersion: "admissionregistration.k8s.io/v1beta1", Version: "v1beta1"},
+				},
+			},
+			{
+				Name: "linkerd.io",
+				Versions: []meta.GroupVersionForDiscovery{
+					{GroupVersion: "linkerd.io/v1alpha2", Version: "v1alpha2"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		group    string
+		version  string
+		required bool
+		err      string
+	}{
+		{"admissionregistration.k8s.io", "v1beta1", true, ""},
+		{"linkerd.io", "v1alpha1", false, "linkerd.io/v1alpha1 is not served, the cluster serves v1alpha2"},
+		{"apiextensions.k8s.io", "v1beta1", true, "apiextensions.k8s.io/v1beta1 is not served"},
+		{"apiextensions.k8s.io", "v1beta1", false, ""},
+	}
+
+	for i, tc := range testCases {
+		err := checkServedVersion(groups, tc.group, tc.version, tc.required)
+		if tc.err == "" && err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Fatalf("test case %d: expected error [%s], got [%v]", i, tc.err, err)
+		}
+	}
+}
+
+func TestCheckDeprecatedArgs(t *testing.T) {
+	pod := func(name string, args ...string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					v1.Container{
+						Name: strings.Split(name, "-")[1],
+						Args: args,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Returns nil if the control plane has no deprecated settings", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("linkerd-controller-6f78cbd47-bc557", "-controller-namespace=linkerd", "-single-namespace=false"),
+			pod("linkerd-web-98c9ddbcd-7b5lh", "-single-namespace=false"),
+		}
+
+		err := checkDeprecatedArgs(pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error listing the containers with deprecated settings", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("linkerd-controller-6f78cbd47-bc557", "-controller-namespace=linkerd", "-single-namespace=true"),
+			pod("linkerd-grafana-5b7d796646-hh46d"),
+		}
+
+		err := checkDeprecatedArgs(pods)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The control plane has deprecated settings:\n\tlinkerd-controller-6f78cbd47-bc557/controller -single-namespace=true: the experimental single-namespace mode is deprecated, reinstall the control plane without --single-namespace"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -75,6 +75,18 @@ func (kubeAPI *KubernetesAPI) CheckVersion(versionInfo *version.Info) error {
 	return nil
 }
 
+// CheckDeprecatedAPIVersions validates that the configured Kubernetes cluster's
+// version neither deprecates nor removes the API versions used by the install
+// manifests.
+func (kubeAPI *KubernetesAPI) CheckDeprecatedAPIVersions(versionInfo *version.Info) error {
+	apiVersion, err := getK8sVersion(versionInfo.String())
+	if err != nil {
+		return err
+	}
+
+	return checkAPIVersions(apiVersion)
+}
+
 // NamespaceExists validates whether a given namespace exists.
 func (kubeAPI *KubernetesAPI) NamespaceExists(ctx context.Context, client *http.Client, namespace string) (bool, error) {
 	rsp, err := kubeAPI.getRequest(ctx, client, "/api/v1/namespaces/"+namespace)
@@ -90,6 +102,23 @@ func (kubeAPI *KubernetesAPI) NamespaceExists(ctx context.Context, client *http.
 	return rsp.StatusCode == http.StatusOK, nil
 }
 
+// APIServiceExists validates whether the API server serves the given API group
+// and version, e.g. through an APIService registered with the aggregation
+// layer.
+func (kubeAPI *KubernetesAPI) APIServiceExists(ctx context.Context, client *http.Client, group, version string) (bool, error) {
+	rsp, err := kubeAPI.getRequest(ctx, client, fmt.Sprintf("/apis/%s/%s", group, version))
+	if err != nil {
+		return false, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+		return false, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	return rsp.StatusCode == http.StatusOK, nil
+}
+
 // GetPodsByNamespace returns all pods in a given namespace
 func (kubeAPI *KubernetesAPI) GetPodsByNamespace(ctx context.Context, client *http.Client, namespace string) ([]v1.Pod, error) {
 	return kubeAPI.getPods(ctx, client, "/api/v1/namespaces/"+namespace+"/pods")
@@ -142,7 +171,13 @@ func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, client *http.Clien
 // NewAPI validates a Kubernetes config and returns a client for accessing the
 // configured cluster
 func NewAPI(configPath, kubeContext string) (*KubernetesAPI, error) {
-	config, err := GetConfig(configPath, kubeContext)
+	return NewAPIWithAuth(configPath, kubeContext, ClientAuth{})
+}
+
+// NewAPIWithAuth is like NewAPI, but overrides the credentials of the
+// configuration with the given ones.
+func NewAPIWithAuth(configPath, kubeContext string, auth ClientAuth) (*KubernetesAPI, error) {
+	config, err := GetConfigWithAuth(configPath, kubeContext, auth)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
 	}
@@ -1,7 +1,12 @@
 package k8s
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"k8s.io/client-go/rest"
 )
 
 func TestKubernetesApiUrlFor(t *testing.T) {
@@ -42,3 +47,35 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 		}
 	})
 }
+
+func TestAPIServiceExists(t *testing.T) {
+	testCases := []struct {
+		status   int
+		expected bool
+		err      bool
+	}{
+		{http.StatusOK, true, false},
+		{http.StatusNotFound, false, false},
+		{http.StatusForbidden, false, true},
+	}
+
+	for i, tc := range testCases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/apis/tap.linkerd.io/v1alpha1" {
+				t.Errorf("test case %d: unexpected path %s", i, r.URL.Path)
+			}
+			w.WriteHeader(tc.status)
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: ts.URL}}
+		exists, err := api.APIServiceExists(context.Background(), ts.Client(), TapAPIGroup, TapAPIVersion)
+		ts.Close()
+
+		if tc.err != (err != nil) {
+			t.Fatalf("test case %d: expected error %t, got: %v", i, tc.err, err)
+		}
+		if exists != tc.expected {
+			t.Fatalf("test case %d: expected %t, got %t", i, tc.expected, exists)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 // These constants are string representations of Kubernetes resource types.
@@ -23,6 +24,10 @@ const (
 	Service               = "service"
 	ServiceProfile        = "serviceprofile"
 	StatefulSet           = "statefulset"
+	TrafficSplit          = "trafficsplit"
+
+	// CronJob is only injected, and isn't a resource of the public API.
+	CronJob = "cronjob"
 
 	// special case k8s job label, to not conflict with Prometheus' job label
 	l5dJob = "k8s_job"
@@ -41,6 +46,7 @@ var AllResources = []string{
 	Service,
 	ServiceProfile,
 	StatefulSet,
+	TrafficSplit,
 }
 
 // StatAllResourceTypes represents the resources to query in StatSummary when Resource.Type is "all"
@@ -77,14 +83,54 @@ func generateKubernetesURL(serverURL, path string) (*url.URL, error) {
 // GetConfig uses default strategy to load configuration from $KUBECONFIG,
 // .kube/config, or just returns in-cluster config.
 func GetConfig(fpath, kubeContext string) (*rest.Config, error) {
+	return GetConfigWithAuth(fpath, kubeContext, ClientAuth{})
+}
+
+// ClientAuth overrides the credentials of a kubeconfig, and the certificate
+// authority that the API server is verified with, like the --as, --as-group,
+// --token and --certificate-authority flags of kubectl.
+type ClientAuth struct {
+	// Impersonate is the user to impersonate.
+	Impersonate string
+	// ImpersonateGroups are the groups to impersonate.
+	ImpersonateGroups []string
+	// Token is the bearer token to authenticate with.
+	Token string
+	// CertificateAuthority is the path to a PEM file with the certificates
+	// of the certificate authority of the API server.
+	CertificateAuthority string
+}
+
+// GetConfigWithAuth is like GetConfig, but overrides the credentials of the
+// configuration with the given ones. Like with GetConfig, requests go through
+// the proxy set by the HTTPS_PROXY and NO_PROXY environment variables, if any.
+func GetConfigWithAuth(fpath, kubeContext string, auth ClientAuth) (*rest.Config, error) {
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if fpath != "" {
 		rules.ExplicitPath = fpath
 	}
-	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
-	return clientcmd.
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContext,
+		AuthInfo: clientcmdapi.AuthInfo{
+			Impersonate: auth.Impersonate,
+			Token:       auth.Token,
+		},
+		ClusterInfo: clientcmdapi.Cluster{
+			CertificateAuthority: auth.CertificateAuthority,
+		},
+	}
+	config, err := clientcmd.
 		NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).
 		ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	// The groups are set on the resulting configuration, because the
+	// overrides would append them to the groups of the kubeconfig.
+	if len(auth.ImpersonateGroups) > 0 {
+		config.Impersonate.Groups = auth.ImpersonateGroups
+	}
+	return config, nil
 }
 
 // CanonicalResourceNameFromFriendlyName returns a canonical name from common shorthands used in command line tools.
@@ -114,6 +160,8 @@ func CanonicalResourceNameFromFriendlyName(friendlyName string) (string, error)
 		return ServiceProfile, nil
 	case "sts", "statefulset", "statefulsets":
 		return StatefulSet, nil
+	case "ts", "trafficsplit", "trafficsplits":
+		return TrafficSplit, nil
 	case "all":
 		return All, nil
 	}
@@ -147,11 +195,32 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 		return "sp"
 	case StatefulSet:
 		return "sts"
+	case TrafficSplit:
+		return "ts"
 	default:
 		return ""
 	}
 }
 
+// PluralResourceNameFromFriendlyName returns the plural form of a Kubernetes
+// resource type, as used in API paths, from common shorthands used in command
+// line tools.
+func PluralResourceNameFromFriendlyName(friendlyName string) (string, error) {
+	canonicalName, err := CanonicalResourceNameFromFriendlyName(friendlyName)
+	if err != nil {
+		return "", err
+	}
+
+	switch canonicalName {
+	case Authority:
+		return "authorities", nil
+	case All:
+		return "", fmt.Errorf("resource type [%s] has no plural form", friendlyName)
+	default:
+		return canonicalName + "s", nil
+	}
+}
+
 // KindToL5DLabel converts a Kubernetes `kind` to a Linkerd label.
 // For example:
 //   `pod` -> `pod`
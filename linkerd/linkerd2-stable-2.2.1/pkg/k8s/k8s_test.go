@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -77,6 +78,28 @@ func TestGetConfig(t *testing.T) {
 			t.Fatalf("Expecting error when config file doesnt exist, got nothing")
 		}
 	})
+
+	t.Run("Overrides the credentials of the configuration", func(t *testing.T) {
+		auth := ClientAuth{
+			Impersonate:       "jane",
+			ImpersonateGroups: []string{"dev", "ops"},
+			Token:             "secret",
+		}
+		config, err := GetConfigWithAuth("testdata/config.test", "", auth)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if config.Impersonate.UserName != auth.Impersonate {
+			t.Fatalf("Expected to impersonate [%s] got [%s]", auth.Impersonate, config.Impersonate.UserName)
+		}
+		if !reflect.DeepEqual(config.Impersonate.Groups, auth.ImpersonateGroups) {
+			t.Fatalf("Expected to impersonate groups %v got %v", auth.ImpersonateGroups, config.Impersonate.Groups)
+		}
+		if config.BearerToken != auth.Token {
+			t.Fatalf("Expected bearer token [%s] got [%s]", auth.Token, config.BearerToken)
+		}
+	})
 }
 
 func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
@@ -115,3 +138,36 @@ func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
 		}
 	})
 }
+
+func TestPluralResourceNameFromFriendlyName(t *testing.T) {
+	t.Run("Returns plural name for all known variants", func(t *testing.T) {
+		expectations := map[string]string{
+			"po":          "pods",
+			"pod":         "pods",
+			"deploy":      "deployments",
+			"deployments": "deployments",
+			"ns":          "namespaces",
+			"au":          "authorities",
+		}
+
+		for input, expectedName := range expectations {
+			actualName, err := PluralResourceNameFromFriendlyName(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if actualName != expectedName {
+				t.Fatalf("Expected friendly name [%s] to resolve to [%s], but got [%s]", input, expectedName, actualName)
+			}
+		}
+	})
+
+	t.Run("Returns error if input has no plural form", func(t *testing.T) {
+		for _, n := range []string{"all", "pdo", ""} {
+			out, err := PluralResourceNameFromFriendlyName(n)
+			if err == nil {
+				t.Fatalf("Expecting error when resolving [%s], but it did resolve to [%s]", n, out)
+			}
+		}
+	})
+}
@@ -42,6 +42,10 @@ const (
 	// this proxy belongs to.
 	ProxyJobLabel = "linkerd.io/proxy-job"
 
+	// ProxyCronJobLabel is injected into mesh-enabled apps, identifying the
+	// CronJob that this proxy belongs to.
+	ProxyCronJobLabel = "linkerd.io/proxy-cronjob"
+
 	// ProxyDaemonSetLabel is injected into mesh-enabled apps, identifying the
 	// DaemonSet that this proxy belongs to.
 	ProxyDaemonSetLabel = "linkerd.io/proxy-daemonset"
@@ -50,6 +54,24 @@ const (
 	// StatefulSet that this proxy belongs to.
 	ProxyStatefulSetLabel = "linkerd.io/proxy-statefulset"
 
+	// ProxyPodLabel is injected into mesh-enabled pods that don't belong to a
+	// workload, identifying the pod itself.
+	ProxyPodLabel = "linkerd.io/proxy-pod"
+
+	// ProxyInjectLabel controls whether or not the pods of a namespace should
+	// be injected, like the ProxyInjectAnnotation. Unlike the annotation, it
+	// can be matched by the proxy-injector webhook's namespace selector, so
+	// that namespaces labeled with "disabled" are never sent to the webhook.
+	ProxyInjectLabel = "linkerd.io/inject"
+
+	// MirroredServiceLabel identifies the Services and Endpoints created by the
+	// service mirror to mirror the Services of a remote cluster.
+	MirroredServiceLabel = "mirror.linkerd.io/mirrored-service"
+
+	// RemoteClusterNameLabel identifies the remote cluster that a mirrored
+	// Service was mirrored from.
+	RemoteClusterNameLabel = "mirror.linkerd.io/cluster-name"
+
 	/*
 	 * Annotations
 	 */
@@ -75,20 +97,111 @@ const (
 	// disable injection for a pod or namespace.
 	ProxyInjectDisabled = "disabled"
 
+	// ProxyAwaitAnnotation controls whether the application containers of a
+	// pod wait for the proxy to be ready before they're started, overriding
+	// the default of the injector. Supported values are "enabled" or
+	// "disabled".
+	ProxyAwaitAnnotation = "config.linkerd.io/proxy-await"
+
+	// ProxyAwaitEnabled is assigned to the ProxyAwaitAnnotation annotation to
+	// make the application containers wait for the proxy.
+	ProxyAwaitEnabled = "enabled"
+
+	// ProxyAwaitDisabled is assigned to the ProxyAwaitAnnotation annotation to
+	// start the application containers along with the proxy.
+	ProxyAwaitDisabled = "disabled"
+
+	// ProxyJobShutdownAnnotation controls whether the proxy of a Job's pod
+	// exits once the other containers of the pod have exited, so that the pod
+	// can complete, overriding the default of the injector. Supported values
+	// are "enabled" or "disabled".
+	ProxyJobShutdownAnnotation = "config.linkerd.io/proxy-job-shutdown"
+
+	// ProxyJobShutdownEnabled is assigned to the ProxyJobShutdownAnnotation
+	// annotation to make the proxy exit along with the other containers.
+	ProxyJobShutdownEnabled = "enabled"
+
+	// ProxyJobShutdownDisabled is assigned to the ProxyJobShutdownAnnotation
+	// annotation to keep the proxy running.
+	ProxyJobShutdownDisabled = "disabled"
+
+	// RemoteServiceFqNameAnnotation is the fully qualified name, in the remote
+	// cluster, of the Service that a mirrored Service was mirrored from.
+	RemoteServiceFqNameAnnotation = "mirror.linkerd.io/remote-svc-fq-name"
+
+	// RemoteResourceVersionAnnotation is the resource version of the remote
+	// Service that a mirrored Service was last updated from.
+	RemoteResourceVersionAnnotation = "mirror.linkerd.io/remote-resource-version"
+
 	/*
 	 * Component Names
 	 */
 
+	// ConfigConfigMapName is the name of the ConfigMap holding the
+	// install-time configuration of the control plane.
+	ConfigConfigMapName = "linkerd-config"
+
+	// ConfigProxyKey is the key of the ConfigConfigMapName ConfigMap holding
+	// the configuration of the injected proxies, as JSON.
+	ConfigProxyKey = "proxy"
+
 	// InitContainerName is the name assigned to the injected init container.
 	InitContainerName = "linkerd-init"
 
 	// ProxyContainerName is the name assigned to the injected proxy container.
 	ProxyContainerName = "linkerd-proxy"
 
+	// ProxyMetricsPortName is the name of the port the injected proxy
+	// container serves its metrics on.
+	ProxyMetricsPortName = "linkerd-metrics"
+
+	// ProxyMetricsPort is the port the injected proxy container serves its
+	// metrics on by default.
+	ProxyMetricsPort = 4191
+
 	// ProxyInjectorWebhookConfig is the name of the mutating webhook
 	// configuration resource of the proxy-injector webhook.
 	ProxyInjectorWebhookConfig = "linkerd-proxy-injector-webhook-config"
 
+	// ProxyInjectorTLSSecret is the name of the secret holding the
+	// proxy-injector's serving certificate and the CA that issued it.
+	ProxyInjectorTLSSecret = "linkerd-proxy-injector-tls"
+
+	// SPValidatorWebhookConfig is the name of the validating webhook
+	// configuration resource of the sp-validator webhook.
+	SPValidatorWebhookConfig = "linkerd-sp-validator-webhook-config"
+
+	// SPValidatorTLSSecret is the name of the secret holding the
+	// sp-validator's serving certificate and the CA that issued it.
+	SPValidatorTLSSecret = "linkerd-sp-validator-tls"
+
+	// WebTokenSecretName is the name of the secret holding the key the tokens
+	// granting access to the dashboard from outside the cluster are signed
+	// with.
+	WebTokenSecretName = "linkerd-web-token"
+
+	// WebTokenSecretKey is the key of the WebTokenSecretName secret holding
+	// the signing key.
+	WebTokenSecretKey = "key"
+
+	// TapAPIGroup is the API group under which the tap APIService is
+	// registered with the Kubernetes aggregation layer.
+	TapAPIGroup = "tap.linkerd.io"
+
+	// TapAPIVersion is the version of the tap APIService.
+	TapAPIVersion = "v1alpha1"
+
+	// TapAPIService is the name of the APIService resource through which tap
+	// requests are served.
+	TapAPIService = TapAPIVersion + "." + TapAPIGroup
+
+	// TapServiceName is the name of the Service fronting the tap APIService.
+	TapServiceName = "linkerd-tap"
+
+	// TapTLSSecret is the name of the secret holding the tap APIService's
+	// serving certificate and the CA that issued it.
+	TapTLSSecret = "linkerd-tap-tls"
+
 	// ProxySpecFileName is the name (key) within the proxy-injector ConfigMap
 	// that contains the proxy container spec.
 	ProxySpecFileName = "proxy.yaml"
@@ -113,6 +226,11 @@ const (
 	// proxy-injector ConfigMap that contains the TLS identity secrets volume spec.
 	TLSIdentityVolumeSpecFileName = "linkerd-secrets.yaml"
 
+	// ExtraVolumesSpecFileName is the name (key) within the proxy-injector
+	// ConfigMap that contains the optional list of volumes added to injected
+	// pods, for the volume mounts added to the proxy container spec.
+	ExtraVolumesSpecFileName = "extra-volumes.yaml"
+
 	// TLSTrustAnchorConfigMapName is the name of the ConfigMap that holds the
 	// trust anchors (trusted root certificates).
 	TLSTrustAnchorConfigMapName = "linkerd-ca-bundle"
@@ -129,6 +247,28 @@ const (
 	// that contains the TLS private key.
 	TLSPrivateKeyFileName = "private-key.p8"
 
+	// IdentityIssuerVolumeName is the name of the volume of the CA that holds
+	// the operator-provided issuer, when there's one.
+	IdentityIssuerVolumeName = "linkerd-identity-issuer"
+
+	// IssuerCertFileName is the name (key) within the issuer secret that
+	// contains the issuer certificate, as in the kubernetes.io/tls secrets
+	// written by cert-manager.
+	IssuerCertFileName = "tls.crt"
+
+	// IssuerPrivateKeyFileName is the name (key) within the issuer secret that
+	// contains the issuer private key.
+	IssuerPrivateKeyFileName = "tls.key"
+
+	// IssuerTrustAnchorsFileName is the name (key) within the issuer secret
+	// that contains the optional certificates the issuer chains to.
+	IssuerTrustAnchorsFileName = "ca.crt"
+
+	// ProxyTLSRequiredEnvVar is the environment variable of the proxy
+	// container that makes the proxy refuse plaintext connections from and to
+	// meshed pods, when TLS is required.
+	ProxyTLSRequiredEnvVar = "LINKERD2_PROXY_TLS_REQUIRED"
+
 	/*
 	 * Mount paths
 	 */
@@ -139,7 +279,8 @@ const (
 
 // InjectedLabels contains the list of label keys subjected to be injected by Linkerd into resource definitions
 var InjectedLabels = []string{ControllerNSLabel, ProxyDeploymentLabel, ProxyReplicationControllerLabel,
-	ProxyReplicaSetLabel, ProxyJobLabel, ProxyDaemonSetLabel, ProxyStatefulSetLabel}
+	ProxyReplicaSetLabel, ProxyJobLabel, ProxyCronJobLabel, ProxyDaemonSetLabel, ProxyStatefulSetLabel,
+	ProxyPodLabel}
 
 var (
 	// MountPathTLSTrustAnchor is the path at which the trust anchor file is
@@ -169,6 +310,14 @@ var (
 	// MountPathTLSIdentityVolumeSpec is the path at which the TLS identity
 	// secret volume spec is mounted to the proxy-injector
 	MountPathTLSIdentityVolumeSpec = MountPathBase + "/config/" + TLSIdentityVolumeSpecFileName
+
+	// MountPathExtraVolumesSpec is the path at which the extra volumes spec is
+	// mounted to the proxy-injector
+	MountPathExtraVolumesSpec = MountPathBase + "/config/" + ExtraVolumesSpecFileName
+
+	// MountPathIdentityIssuer is the path at which the issuer secret is
+	// mounted to the CA
+	MountPathIdentityIssuer = MountPathBase + "/issuer"
 )
 
 // CreatedByAnnotationValue returns the value associated with
@@ -200,6 +349,106 @@ func IsMeshed(pod *coreV1.Pod, controllerNS string) bool {
 	return pod.Labels[ControllerNSLabel] == controllerNS
 }
 
+// ShouldAwaitProxy returns whether the application containers of a pod with
+// the given annotations must wait for the proxy to be ready, given the
+// default of the injector.
+func ShouldAwaitProxy(annotations map[string]string, defaultAwait bool) bool {
+	switch annotations[ProxyAwaitAnnotation] {
+	case ProxyAwaitEnabled:
+		return true
+	case ProxyAwaitDisabled:
+		return false
+	default:
+		return defaultAwait
+	}
+}
+
+// AwaitProxy sets a postStart hook on the proxy container that only returns
+// once the proxy serves its metrics, on its "linkerd-metrics" port. The
+// kubelet starts the containers of a pod in order, and doesn't start a
+// container until the postStart hook of the previous one has returned, so the
+// proxy container must be the first container of the pod for the application
+// containers to wait for it. The hook relies on the sh and curl binaries of
+// the proxy image.
+func AwaitProxy(proxy *coreV1.Container) {
+	port := int32(ProxyMetricsPort)
+	for _, p := range proxy.Ports {
+		if p.Name == ProxyMetricsPortName {
+			port = p.ContainerPort
+		}
+	}
+
+	proxy.Lifecycle = &coreV1.Lifecycle{
+		PostStart: &coreV1.Handler{
+			Exec: &coreV1.ExecAction{
+				Command: []string{
+					"sh", "-c",
+					fmt.Sprintf("until curl -sf -o /dev/null http://localhost:%d/metrics; do sleep 1; done", port),
+				},
+			},
+		},
+	}
+}
+
+// proxyJobShutdownScript runs the proxy until the other processes of the pod
+// have exited, which requires the pod to share its process namespace, where
+// the pause process has the PID 1. If no other process is ever seen, e.g.
+// because the application exited before the proxy started, the proxy exits
+// after a minute. The proxy is stopped when the script is terminated.
+const proxyJobShutdownScript = `./linkerd2-proxy &
+proxy=$!
+trap 'kill $proxy' INT TERM
+seen=
+idle=0
+while kill -0 $proxy 2>/dev/null; do
+  running=
+  for status in /proc/[0-9]*/status; do
+    pid=${status#/proc/}
+    pid=${pid%/status}
+    case $pid in 1|$$|$proxy) continue ;; esac
+    ppid=$(sed -n 's/^PPid:[[:space:]]*//p' $status 2>/dev/null)
+    if [ "$ppid" != "$$" ]; then running=1; fi
+  done
+  if [ -n "$running" ]; then
+    seen=1
+  elif [ -n "$seen" ] || [ $((idle += 1)) -ge 60 ]; then
+    kill $proxy
+    wait $proxy
+    exit 0
+  fi
+  sleep 1
+done
+wait $proxy
+`
+
+// ShouldShutdownProxy returns whether the proxy of a Job's pod with the given
+// annotations must exit once the other containers of the pod have exited,
+// given the default of the injector.
+func ShouldShutdownProxy(annotations map[string]string, defaultShutdown bool) bool {
+	switch annotations[ProxyJobShutdownAnnotation] {
+	case ProxyJobShutdownEnabled:
+		return true
+	case ProxyJobShutdownDisabled:
+		return false
+	default:
+		return defaultShutdown
+	}
+}
+
+// ShutdownProxy replaces the command of the proxy container with a script
+// that stops the proxy once the other containers of the pod have exited, so
+// that the pods of Jobs can complete. The pod must share its process
+// namespace, see the ShareProcessNamespace field of the PodSpec, for the
+// proxy container to see the processes of the other containers. The script
+// relies on the sh and sed binaries of the proxy image.
+func ShutdownProxy(proxy *coreV1.Container) {
+	proxy.Command = []string{"sh", "-c", proxyJobShutdownScript}
+}
+
+// TLSIdentityLabel is the tap event label holding the TLS identity of a peer
+// whose connection was secured with TLS.
+const TLSIdentityLabel = "tls_identity"
+
 // TLSIdentity is the identity of a pod owner (Deployment, Pod,
 // ReplicationController, etc.).
 type TLSIdentity struct {
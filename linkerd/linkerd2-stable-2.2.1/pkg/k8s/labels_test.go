@@ -38,3 +38,58 @@ func TestGetPodLabels(t *testing.T) {
 		}
 	})
 }
+
+func TestShouldAwaitProxy(t *testing.T) {
+	testCases := []struct {
+		annotations  map[string]string
+		defaultAwait bool
+		expected     bool
+	}{
+		{nil, false, false},
+		{nil, true, true},
+		{map[string]string{ProxyAwaitAnnotation: ProxyAwaitEnabled}, false, true},
+		{map[string]string{ProxyAwaitAnnotation: ProxyAwaitDisabled}, true, false},
+		{map[string]string{ProxyAwaitAnnotation: "unknown"}, true, true},
+	}
+
+	for i, tc := range testCases {
+		if actual := ShouldAwaitProxy(tc.annotations, tc.defaultAwait); actual != tc.expected {
+			t.Fatalf("test case %d: expected %t, got %t", i, tc.expected, actual)
+		}
+	}
+}
+
+func TestAwaitProxy(t *testing.T) {
+	proxy := &coreV1.Container{
+		Name:  ProxyContainerName,
+		Ports: []coreV1.ContainerPort{{Name: ProxyMetricsPortName, ContainerPort: 9999}},
+	}
+	AwaitProxy(proxy)
+
+	expected := []string{"sh", "-c", "until curl -sf -o /dev/null http://localhost:9999/metrics; do sleep 1; done"}
+	if proxy.Lifecycle == nil || proxy.Lifecycle.PostStart == nil || proxy.Lifecycle.PostStart.Exec == nil {
+		t.Fatalf("Expected a postStart exec hook, got %+v", proxy.Lifecycle)
+	}
+	if command := proxy.Lifecycle.PostStart.Exec.Command; !reflect.DeepEqual(command, expected) {
+		t.Fatalf("Expected command %v, got %v", expected, command)
+	}
+}
+
+func TestShouldShutdownProxy(t *testing.T) {
+	testCases := []struct {
+		annotations     map[string]string
+		defaultShutdown bool
+		expected        bool
+	}{
+		{nil, false, false},
+		{nil, true, true},
+		{map[string]string{ProxyJobShutdownAnnotation: ProxyJobShutdownEnabled}, false, true},
+		{map[string]string{ProxyJobShutdownAnnotation: ProxyJobShutdownDisabled}, true, false},
+	}
+
+	for i, tc := range testCases {
+		if actual := ShouldShutdownProxy(tc.annotations, tc.defaultShutdown); actual != tc.expected {
+			t.Fatalf("test case %d: expected %t, got %t", i, tc.expected, actual)
+		}
+	}
+}
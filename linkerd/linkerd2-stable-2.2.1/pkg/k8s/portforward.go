@@ -1,17 +1,20 @@
 package k8s
 
 import (
-	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -21,16 +24,55 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+const (
+	// PortForwardConnected is the type of the events emitted when a
+	// port-forward connection to a pod is established.
+	PortForwardConnected PortForwardEventType = "Connected"
+
+	// PortForwardDisconnected is the type of the events emitted when a
+	// port-forward connection to a pod is lost, either because the connection
+	// dropped or because the pod terminated.
+	PortForwardDisconnected PortForwardEventType = "Disconnected"
+
+	// deploymentRevisionAnnotation is set by the deployment controller on
+	// the ReplicaSets of a deployment, increasing with every rollout.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// PortForwardEventType is the type of a PortForwardEvent.
+type PortForwardEventType string
+
+// PortForwardEvent reports a change in the connection of a PortForward.
+type PortForwardEvent struct {
+	Type PortForwardEventType
+	Pod  string
+	Err  error
+}
+
+// podResolver returns the name of the pod to forward to, and its port.
+type podResolver func() (string, int, error)
+
 // PortForward provides a port-forward connection into a Kubernetes cluster.
+//
+// Unless it targets an explicit pod, a PortForward re-establishes its
+// connection to another ready pod when the connection drops or the pod
+// terminates, and reports it on its Events channel.
 type PortForward struct {
-	method     string
-	url        *url.URL
-	localPort  int
-	remotePort int
-	emitLogs   bool
-	stopCh     chan struct{}
-	readyCh    chan struct{}
-	config     *rest.Config
+	namespace string
+	resolve   podResolver
+	failover  bool
+	localPort int
+	emitLogs  bool
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	readyCh   chan struct{}
+	readyOnce sync.Once
+	events    chan PortForwardEvent
+	config    *rest.Config
+	clientset kubernetes.Interface
 }
 
 // NewPortForward returns an instance of the PortForward struct that can be used
@@ -38,77 +80,316 @@ type PortForward struct {
 // specified by namespace and deployName. If localPort is 0, it will use a
 // random ephemeral port.
 func NewPortForward(
-	configPath, kubeContext, namespace, deployName string,
+	kubeAPI *KubernetesAPI,
+	namespace, deployName string,
 	localPort, remotePort int,
 	emitLogs bool,
 ) (*PortForward, error) {
-	config, err := GetConfig(configPath, kubeContext)
+	return newPortForward(kubeAPI, namespace, localPort, emitLogs, true,
+		func(clientset kubernetes.Interface) podResolver {
+			return func() (string, int, error) {
+				podName, err := podForDeployment(clientset, namespace, deployName)
+				return podName, remotePort, err
+			}
+		})
+}
+
+// NewPodPortForward returns an instance of the PortForward struct that can be
+// used to establish a port-forward connection to the pod that's specified by
+// namespace and podName. If localPort is 0, it will use a random ephemeral
+// port.
+func NewPodPortForward(
+	kubeAPI *KubernetesAPI,
+	namespace, podName string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	return newPortForward(kubeAPI, namespace, localPort, emitLogs, false,
+		func(kubernetes.Interface) podResolver {
+			return func() (string, int, error) {
+				return podName, remotePort, nil
+			}
+		})
+}
+
+// NewSelectorPortForward returns an instance of the PortForward struct that
+// can be used to establish a port-forward connection to a running and ready
+// pod that's specified by namespace and the label selector. If localPort is 0,
+// it will use a random ephemeral port.
+func NewSelectorPortForward(
+	kubeAPI *KubernetesAPI,
+	namespace, selector string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	return newPortForward(kubeAPI, namespace, localPort, emitLogs, true,
+		func(clientset kubernetes.Interface) podResolver {
+			return func() (string, int, error) {
+				podName, err := podForSelector(clientset, namespace, selector)
+				return podName, remotePort, err
+			}
+		})
+}
+
+// NewServicePortForward returns an instance of the PortForward struct that
+// can be used to establish a port-forward connection to a ready endpoint of
+// the service that's specified by namespace and serviceName. remotePort is a
+// port of the service, which is forwarded to the target port of the endpoint,
+// like with `kubectl port-forward svc/<name>`. If localPort is 0, it will use
+// a random ephemeral port.
+func NewServicePortForward(
+	kubeAPI *KubernetesAPI,
+	namespace, serviceName string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	return newPortForward(kubeAPI, namespace, localPort, emitLogs, true,
+		func(clientset kubernetes.Interface) podResolver {
+			return func() (string, int, error) {
+				return podForService(clientset, namespace, serviceName, remotePort)
+			}
+		})
+}
+
+// newPortForward returns a PortForward to the pods returned by the resolver
+// built by newResolver. The resolver is checked once, so that a target that
+// doesn't exist fails right away.
+func newPortForward(
+	kubeAPI *KubernetesAPI,
+	namespace string,
+	localPort int,
+	emitLogs, failover bool,
+	newResolver func(kubernetes.Interface) podResolver,
+) (*PortForward, error) {
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
 	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
+	resolve := newResolver(clientset)
+	if _, _, err := resolve(); err != nil {
 		return nil, err
 	}
 
-	kubeAPI := &KubernetesAPI{Config: config}
-	client, err := kubeAPI.NewClient()
+	if localPort == 0 {
+		localPort, err = getLocalPort()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &PortForward{
+		namespace: namespace,
+		resolve:   resolve,
+		failover:  failover,
+		localPort: localPort,
+		emitLogs:  emitLogs,
+		stopCh:    make(chan struct{}),
+		readyCh:   make(chan struct{}),
+		events:    make(chan PortForwardEvent, 16),
+		config:    kubeAPI.Config,
+		clientset: clientset,
+	}, nil
+}
+
+// podForDeployment returns the name of a ready pod whose name starts with the
+// given deployment name.
+func podForDeployment(client kubernetes.Interface, namespace, deployName string) (string, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{})
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	candidates := []v1.Pod{}
+	for _, pod := range pods.Items {
+		if strings.HasPrefix(pod.Name, deployName) {
+			candidates = append(candidates, pod)
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	pods, err := kubeAPI.GetPodsByNamespace(ctx, client, namespace)
+	if podName := bestPod(client, namespace, candidates); podName != "" {
+		return podName, nil
+	}
+
+	return "", fmt.Errorf("no running and ready pods found for %s", deployName)
+}
+
+// podForSelector returns the name of a ready pod matching the given label
+// selector.
+func podForSelector(client kubernetes.Interface, namespace, selector string) (string, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	podName := ""
-	for _, pod := range pods {
-		if pod.Status.Phase == v1.PodRunning {
-			if strings.HasPrefix(pod.Name, deployName) {
-				podName = pod.Name
-				break
+	if podName := bestPod(client, namespace, pods.Items); podName != "" {
+		return podName, nil
+	}
+
+	return "", fmt.Errorf("no running and ready pods found for selector %q", selector)
+}
+
+// bestPod returns the name of a running, ready and non-terminating pod among
+// the given ones, or an empty string if there's none. Pods of the newest
+// ReplicaSet are preferred, so that a forward started during a rollout goes to
+// a pod that stays around, and then the most recently created pods.
+func bestPod(client kubernetes.Interface, namespace string, pods []v1.Pod) string {
+	revisions := map[string]int64{}
+	revisionOf := func(pod *v1.Pod) int64 {
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind != "ReplicaSet" {
+				continue
+			}
+			revision, ok := revisions[owner.Name]
+			if !ok {
+				revision = replicaSetRevision(client, namespace, owner.Name)
+				revisions[owner.Name] = revision
 			}
+			return revision
 		}
+		return 0
 	}
 
-	if podName == "" {
-		return nil, fmt.Errorf("no running pods found for %s", deployName)
+	var best *v1.Pod
+	var bestRevision int64
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning || !isPodReady(pod) {
+			continue
+		}
+
+		revision := revisionOf(pod)
+		if best == nil ||
+			revision > bestRevision ||
+			revision == bestRevision && best.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			best = pod
+			bestRevision = revision
+		}
 	}
 
-	req := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Namespace(namespace).
-		Name(podName).
-		SubResource("portforward")
+	if best == nil {
+		return ""
+	}
+	return best.Name
+}
 
-	if localPort == 0 {
-		localPort, err = getLocalPort()
-		if err != nil {
-			return nil, err
+// replicaSetRevision returns the deployment revision of the given ReplicaSet,
+// or 0 if it's unknown, e.g. because the ReplicaSet can't be read.
+func replicaSetRevision(client kubernetes.Interface, namespace, name string) int64 {
+	rs, err := client.AppsV1().ReplicaSets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return 0
+	}
+
+	revision, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// podForService returns the name of the pod of a ready endpoint of the given
+// service, and the port of the pod that the given service port targets.
+func podForService(client kubernetes.Interface, namespace, serviceName string, port int) (string, int, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+
+	var svcPort *v1.ServicePort
+	for i := range svc.Spec.Ports {
+		if int(svc.Spec.Ports[i].Port) == port {
+			svcPort = &svc.Spec.Ports[i]
+			break
 		}
 	}
+	if svcPort == nil {
+		return "", 0, fmt.Errorf("service %s has no port %d", serviceName, port)
+	}
 
-	return &PortForward{
-		method:     "POST",
-		url:        req.URL(),
-		localPort:  localPort,
-		remotePort: remotePort,
-		emitLogs:   emitLogs,
-		stopCh:     make(chan struct{}, 1),
-		readyCh:    make(chan struct{}),
-		config:     config,
-	}, nil
+	endpoints, err := client.CoreV1().Endpoints(namespace).Get(serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		targetPort := 0
+		for _, endpointPort := range subset.Ports {
+			// the ports of an endpoint are named after the ports of the
+			// service, which may be unnamed if it has a single port
+			if endpointPort.Name == svcPort.Name {
+				targetPort = int(endpointPort.Port)
+				break
+			}
+		}
+		if targetPort == 0 {
+			continue
+		}
+
+		for _, addr := range subset.Addresses {
+			if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+				return addr.TargetRef.Name, targetPort, nil
+			}
+		}
+	}
+
+	return "", 0, fmt.Errorf("no ready endpoints found for service %s on port %d", serviceName, port)
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
 }
 
-// Run creates and runs the port-forward connection.
+// Run creates and runs the port-forward connection. Unless the PortForward
+// targets an explicit pod, it reconnects to another ready pod whenever the
+// connection is lost, until Stop is called.
 func (pf *PortForward) Run() error {
+	defer close(pf.events)
+
+	backoff := minReconnectBackoff
+	for {
+		podName, remotePort, err := pf.resolve()
+		if err == nil {
+			var connected bool
+			connected, err = pf.forward(podName, remotePort)
+			if pf.stopped() {
+				return nil
+			}
+			if !pf.failover {
+				return err
+			}
+			if connected {
+				backoff = minReconnectBackoff
+			}
+			if err == nil {
+				err = fmt.Errorf("lost connection to pod %s", podName)
+			}
+			pf.emit(PortForwardEvent{Type: PortForwardDisconnected, Pod: podName, Err: err})
+		}
+
+		select {
+		case <-pf.stopCh:
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// forward runs a port-forward connection to the given pod until it's lost,
+// the pod terminates, or the PortForward is stopped. It returns whether the
+// connection was established.
+func (pf *PortForward) forward(podName string, remotePort int) (bool, error) {
 	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	out := ioutil.Discard
@@ -118,15 +399,147 @@ func (pf *PortForward) Run() error {
 		errOut = os.Stderr
 	}
 
-	ports := []string{fmt.Sprintf("%d:%d", pf.localPort, pf.remotePort)}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, pf.method, pf.url)
+	url := pf.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pf.namespace).
+		Name(podName).
+		SubResource("portforward").
+		URL()
+
+	ports := []string{fmt.Sprintf("%d:%d", pf.localPort, remotePort)}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", url)
+
+	// the connection is stopped when the PortForward is stopped, or when the
+	// pod terminates
+	connStopCh := make(chan struct{})
+	connReadyCh := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+
+	terminated := make(chan error, 1)
+	go pf.watchPod(podName, terminated, done)
+	go func() {
+		select {
+		case <-pf.stopCh:
+		case err := <-terminated:
+			terminated <- err
+		case <-done:
+			return
+		}
+		close(connStopCh)
+	}()
 
-	fw, err := portforward.New(dialer, ports, pf.stopCh, pf.readyCh, out, errOut)
+	fw, err := portforward.New(dialer, ports, connStopCh, connReadyCh, out, errOut)
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	// forward waits for the readiness to be reported before returning, so
+	// that the events aren't emitted after Run closes the channel
+	forwarded := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		select {
+		case <-connReadyCh:
+			pf.emit(PortForwardEvent{Type: PortForwardConnected, Pod: podName})
+			pf.readyOnce.Do(func() { close(pf.readyCh) })
+		case <-forwarded:
+		}
+	}()
+
+	err = fw.ForwardPorts()
+	close(forwarded)
+	<-reported
+
+	connected := false
+	select {
+	case <-connReadyCh:
+		connected = true
+	default:
 	}
 
-	return fw.ForwardPorts()
+	select {
+	case termErr := <-terminated:
+		return connected, termErr
+	default:
+		return connected, err
+	}
+}
+
+// watchPod reports on terminated when the given pod is deleted, stops running
+// or stops being ready, until done is closed. Watch failures are ignored, as
+// dropped connections are detected by the port-forward itself.
+func (pf *PortForward) watchPod(podName string, terminated chan<- error, done <-chan struct{}) {
+	if !pf.failover {
+		return
+	}
+
+	for {
+		w, err := pf.clientset.CoreV1().Pods(pf.namespace).Watch(metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", podName).String(),
+		})
+		if err != nil {
+			return
+		}
+
+		if err := podTermination(w, done); err != nil {
+			terminated <- err
+			return
+		}
+		w.Stop()
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+// podTermination returns an error once the watched pod terminates, or nil
+// when the watch ends or done is closed.
+func podTermination(w watch.Interface, done <-chan struct{}) error {
+	for {
+		select {
+		case <-done:
+			return nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			pod, ok := event.Object.(*v1.Pod)
+			if !ok {
+				continue
+			}
+			switch {
+			case event.Type == watch.Deleted:
+				return fmt.Errorf("pod %s was deleted", pod.Name)
+			case pod.DeletionTimestamp != nil:
+				return fmt.Errorf("pod %s is terminating", pod.Name)
+			case pod.Status.Phase != v1.PodRunning || !isPodReady(pod):
+				return fmt.Errorf("pod %s is not ready", pod.Name)
+			}
+		}
+	}
+}
+
+func (pf *PortForward) emit(event PortForwardEvent) {
+	// events are dropped rather than blocking the connection when the client
+	// doesn't consume them
+	select {
+	case pf.events <- event:
+	default:
+	}
+}
+
+func (pf *PortForward) stopped() bool {
+	select {
+	case <-pf.stopCh:
+		return true
+	default:
+		return false
+	}
 }
 
 // Ready returns a channel that will receive a message when the port-forward
@@ -136,9 +549,16 @@ func (pf *PortForward) Ready() <-chan struct{} {
 	return pf.readyCh
 }
 
+// Events returns a channel that receives the PortForwardEvents of the
+// port-forward connection, which is closed when Run returns. Events are
+// dropped if the channel isn't consumed.
+func (pf *PortForward) Events() <-chan PortForwardEvent {
+	return pf.events
+}
+
 // Stop terminates the port-forward connection.
 func (pf *PortForward) Stop() {
-	close(pf.stopCh)
+	pf.stopOnce.Do(func() { close(pf.stopCh) })
 }
 
 // URLFor returns the URL for the port-forward connection.
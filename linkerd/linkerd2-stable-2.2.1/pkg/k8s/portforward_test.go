@@ -0,0 +1,251 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestPod(name string, phase v1.PodPhase, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Status: v1.PodStatus{
+			Phase:      phase,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestPodForSelector(t *testing.T) {
+	testCases := []struct {
+		pods     []runtime.Object
+		expected string
+	}{
+		{
+			pods: []runtime.Object{
+				newTestPod("pending", v1.PodPending, false),
+				newTestPod("unready", v1.PodRunning, false),
+				newTestPod("ready", v1.PodRunning, true),
+			},
+			expected: "ready",
+		},
+		{
+			pods: []runtime.Object{
+				newTestPod("unready", v1.PodRunning, false),
+			},
+			expected: "",
+		},
+	}
+
+	for i, tc := range testCases {
+		client := fake.NewSimpleClientset(tc.pods...)
+
+		podName, err := podForSelector(client, "ns", "app=web")
+		if tc.expected == "" {
+			if err == nil {
+				t.Fatalf("test case %d: expected an error, got pod %s", i, podName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if podName != tc.expected {
+			t.Fatalf("test case %d: expected pod %s, got %s", i, tc.expected, podName)
+		}
+	}
+}
+
+func TestPodForService(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "admin", Port: 9994, TargetPort: intstr.FromInt(9994)},
+			},
+		},
+	}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"},
+		Subsets: []v1.EndpointSubset{
+			{
+				NotReadyAddresses: []v1.EndpointAddress{
+					{TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "web-unready"}},
+				},
+				Ports: []v1.EndpointPort{{Name: "http", Port: 8084}},
+			},
+			{
+				Addresses: []v1.EndpointAddress{
+					{TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "web-ready"}},
+				},
+				Ports: []v1.EndpointPort{
+					{Name: "http", Port: 8084},
+					{Name: "admin", Port: 9994},
+				},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(svc, endpoints)
+
+	podName, targetPort, err := podForService(client, "ns", "web", 80)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if podName != "web-ready" || targetPort != 8084 {
+		t.Fatalf("Expected web-ready:8084, got %s:%d", podName, targetPort)
+	}
+
+	if _, _, err := podForService(client, "ns", "web", 8080); err == nil {
+		t.Fatal("Expected an error for a port the service doesn't expose")
+	}
+}
+
+func TestPodForDeployment(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newTestPod("linkerd-controller-1", v1.PodRunning, true),
+		newTestPod("linkerd-web-1", v1.PodPending, false),
+		newTestPod("linkerd-web-2", v1.PodRunning, true),
+	)
+
+	podName, err := podForDeployment(client, "ns", "linkerd-web")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if podName != "linkerd-web-2" {
+		t.Fatalf("Expected pod linkerd-web-2, got %s", podName)
+	}
+
+	if _, err := podForDeployment(client, "ns", "linkerd-grafana"); err == nil {
+		t.Fatal("Expected an error for a deployment without pods")
+	}
+}
+
+func TestPodTermination(t *testing.T) {
+	terminating := newTestPod("web", v1.PodRunning, true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+
+	testCases := []struct {
+		events     []watch.Event
+		terminated bool
+	}{
+		{
+			events: []watch.Event{
+				{Type: watch.Modified, Object: newTestPod("web", v1.PodRunning, true)},
+			},
+			terminated: false,
+		},
+		{
+			events: []watch.Event{
+				{Type: watch.Modified, Object: newTestPod("web", v1.PodRunning, true)},
+				{Type: watch.Modified, Object: newTestPod("web", v1.PodRunning, false)},
+			},
+			terminated: true,
+		},
+		{
+			events: []watch.Event{
+				{Type: watch.Modified, Object: terminating},
+			},
+			terminated: true,
+		},
+		{
+			events: []watch.Event{
+				{Type: watch.Deleted, Object: newTestPod("web", v1.PodRunning, true)},
+			},
+			terminated: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		w := watch.NewFakeWithChanSize(len(tc.events), false)
+		for _, event := range tc.events {
+			w.Action(event.Type, event.Object)
+		}
+		w.Stop()
+
+		err := podTermination(w, make(chan struct{}))
+		if tc.terminated != (err != nil) {
+			t.Fatalf("test case %d: expected termination %t, got error: %v", i, tc.terminated, err)
+		}
+	}
+}
+
+func TestBestPod(t *testing.T) {
+	newRS := func(name, revision string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Annotations: map[string]string{deploymentRevisionAnnotation: revision},
+			},
+		}
+	}
+	newRSPod := func(name, rs string, created time.Time, ready bool) v1.Pod {
+		pod := newTestPod(name, v1.PodRunning, ready)
+		pod.CreationTimestamp = metav1.NewTime(created)
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: rs}}
+		return *pod
+	}
+
+	client := fake.NewSimpleClientset(newRS("web-old", "1"), newRS("web-new", "2"))
+	now := time.Now()
+	terminating := newRSPod("web-new-terminating", "web-new", now, true)
+	terminating.DeletionTimestamp = &metav1.Time{Time: now}
+
+	testCases := []struct {
+		pods     []v1.Pod
+		expected string
+	}{
+		{
+			pods: []v1.Pod{
+				newRSPod("web-old-1", "web-old", now, true),
+				newRSPod("web-new-1", "web-new", now.Add(-time.Hour), true),
+			},
+			expected: "web-new-1",
+		},
+		{
+			pods: []v1.Pod{
+				newRSPod("web-new-1", "web-new", now.Add(-time.Hour), true),
+				newRSPod("web-new-2", "web-new", now, true),
+			},
+			expected: "web-new-2",
+		},
+		{
+			pods: []v1.Pod{
+				newRSPod("web-old-1", "web-old", now, true),
+				newRSPod("web-new-unready", "web-new", now, false),
+				terminating,
+			},
+			expected: "web-old-1",
+		},
+		{
+			pods: []v1.Pod{
+				newRSPod("web-new-unready", "web-new", now, false),
+				terminating,
+			},
+			expected: "",
+		},
+	}
+
+	for i, tc := range testCases {
+		podName := bestPod(client, "ns", tc.pods)
+		if podName != tc.expected {
+			t.Fatalf("test case %d: expected pod %q, got %q", i, tc.expected, podName)
+		}
+	}
+}
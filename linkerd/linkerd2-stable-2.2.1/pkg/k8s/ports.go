@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"sort"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// ServerFirstProtocols are the protocols the proxy can't detect, with their
+// well-known ports. Their servers speak first, so that their connections hang
+// through the proxy, which must be skipped for these ports.
+var ServerFirstProtocols = map[string][]uint{
+	"ftp":        {21},
+	"memcached":  {11211},
+	"mysql":      {3306},
+	"postgresql": {5432},
+	"redis":      {6379},
+	"smtp":       {25, 587},
+}
+
+// ServerFirstProtocol returns the protocol of ServerFirstProtocols whose
+// well-known port is the given one, if any.
+func ServerFirstProtocol(port uint) (string, bool) {
+	for protocol, ports := range ServerFirstProtocols {
+		for _, p := range ports {
+			if p == port {
+				return protocol, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DetectServerFirstPorts returns the TCP container ports of the pod that are
+// well-known ports of ServerFirstProtocols, except for the ones skipped
+// already, in ascending order and without duplicates.
+func DetectServerFirstPorts(spec *coreV1.PodSpec, skipped func(port uint) bool) []uint {
+	seen := map[uint]bool{}
+
+	detected := []uint{}
+	for _, container := range spec.Containers {
+		for _, port := range container.Ports {
+			p := uint(port.ContainerPort)
+			if port.Protocol == coreV1.ProtocolUDP || seen[p] || skipped(p) {
+				continue
+			}
+			if _, ok := ServerFirstProtocol(p); ok {
+				seen[p] = true
+				detected = append(detected, p)
+			}
+		}
+	}
+	sort.Slice(detected, func(i, j int) bool { return detected[i] < detected[j] })
+	return detected
+}
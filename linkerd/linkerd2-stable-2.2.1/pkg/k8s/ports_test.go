@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestServerFirstProtocol(t *testing.T) {
+	testCases := []struct {
+		port     uint
+		protocol string
+		found    bool
+	}{
+		{21, "ftp", true},
+		{587, "smtp", true},
+		{5432, "postgresql", true},
+		{11211, "memcached", true},
+		{8080, "", false},
+	}
+
+	for i, tc := range testCases {
+		protocol, found := ServerFirstProtocol(tc.port)
+		if protocol != tc.protocol || found != tc.found {
+			t.Fatalf("test case %d: expected (%q, %t), got (%q, %t)", i, tc.protocol, tc.found, protocol, found)
+		}
+	}
+}
+
+func TestDetectServerFirstPorts(t *testing.T) {
+	podSpec := &coreV1.PodSpec{
+		Containers: []coreV1.Container{
+			{
+				Name: "db",
+				Ports: []coreV1.ContainerPort{
+					{ContainerPort: 5432},
+					{ContainerPort: 3306, Protocol: coreV1.ProtocolTCP},
+					{ContainerPort: 8080},
+				},
+			},
+			{
+				Name: "cache",
+				Ports: []coreV1.ContainerPort{
+					{ContainerPort: 11211, Protocol: coreV1.ProtocolUDP},
+					{ContainerPort: 6379},
+					{ContainerPort: 3306},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		skipped  func(uint) bool
+		expected []uint
+	}{
+		{func(uint) bool { return false }, []uint{3306, 5432, 6379}},
+		{func(p uint) bool { return p == 5432 || p == 6379 }, []uint{3306}},
+		{func(p uint) bool { return p >= 3300 }, []uint{}},
+	}
+
+	for i, tc := range testCases {
+		if actual := DetectServerFirstPorts(podSpec, tc.skipped); !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("test case %d: expected ports %v, got %v", i, tc.expected, actual)
+		}
+	}
+}
@@ -9,6 +9,57 @@ import (
 
 var revisionSeparator = regexp.MustCompile("[^0-9.]")
 
+// installAPIVersion is an API version used by the install manifests, with the
+// Kubernetes versions deprecating and removing it.
+type installAPIVersion struct {
+	apiVersion string
+	kinds      string
+	deprecated [3]int
+	removed    [3]int
+	// advice tells how to stop relying on the API version
+	advice string
+}
+
+// installAPIVersions are the API versions used by the install manifests that
+// Kubernetes deprecates.
+var installAPIVersions = []installAPIVersion{
+	{
+		apiVersion: "extensions/v1beta1",
+		kinds:      "Deployment, DaemonSet",
+		deprecated: [3]int{1, 14, 0},
+		removed:    [3]int{1, 16, 0},
+		advice:     "upgrade Linkerd to a version installing apps/v1 Deployments and DaemonSets before upgrading Kubernetes",
+	},
+	{
+		apiVersion: "apiextensions.k8s.io/v1beta1",
+		kinds:      "CustomResourceDefinition",
+		deprecated: [3]int{1, 16, 0},
+		removed:    [3]int{1, 22, 0},
+		advice:     "upgrade Linkerd to a version installing apiextensions.k8s.io/v1 CustomResourceDefinitions before upgrading Kubernetes",
+	},
+	{
+		apiVersion: "admissionregistration.k8s.io/v1beta1",
+		kinds:      "MutatingWebhookConfiguration, ValidatingWebhookConfiguration",
+		deprecated: [3]int{1, 16, 0},
+		removed:    [3]int{1, 22, 0},
+		advice:     "install without --proxy-auto-inject to skip the proxy injector webhook, and upgrade Linkerd before upgrading Kubernetes",
+	},
+	{
+		apiVersion: "rbac.authorization.k8s.io/v1beta1",
+		kinds:      "ClusterRole, ClusterRoleBinding, Role, RoleBinding",
+		deprecated: [3]int{1, 17, 0},
+		removed:    [3]int{1, 22, 0},
+		advice:     "upgrade Linkerd to a version installing rbac.authorization.k8s.io/v1 roles before upgrading Kubernetes",
+	},
+	{
+		apiVersion: "batch/v1beta1",
+		kinds:      "CronJob",
+		deprecated: [3]int{1, 21, 0},
+		removed:    [3]int{1, 25, 0},
+		advice:     "install without --enable-heartbeat to skip the heartbeat CronJob",
+	},
+}
+
 func getK8sVersion(versionString string) ([3]int, error) {
 	var version [3]int
 	justTheVersionString := strings.TrimPrefix(versionString, "v")
@@ -45,3 +96,26 @@ func isCompatibleVersion(minimalRequirementVersion [3]int, actualVersion [3]int)
 
 	return false
 }
+
+// checkAPIVersions returns an error listing the API versions used by the
+// install manifests that the given Kubernetes version deprecates or removes.
+func checkAPIVersions(actualVersion [3]int) error {
+	problems := []string{}
+	for _, v := range installAPIVersions {
+		status := ""
+		if isCompatibleVersion(v.removed, actualVersion) {
+			status = "removed"
+		} else if isCompatibleVersion(v.deprecated, actualVersion) {
+			status = "deprecated"
+		} else {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s (%s) is %s: %s", v.apiVersion, v.kinds, status, v.advice))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Kubernetes version [%d.%d.%d] deprecates API versions used by the install manifests:\n\t%s",
+			actualVersion[0], actualVersion[1], actualVersion[2], strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
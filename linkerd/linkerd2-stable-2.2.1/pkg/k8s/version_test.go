@@ -82,3 +82,27 @@ func TestIsCompatibleVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckAPIVersions(t *testing.T) {
+	t.Run("Returns nil when no API version is deprecated", func(t *testing.T) {
+		err := checkAPIVersions([3]int{1, 13, 4})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error listing the deprecated and removed API versions", func(t *testing.T) {
+		err := checkAPIVersions([3]int{1, 16, 2})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+
+		expected := `Kubernetes version [1.16.2] deprecates API versions used by the install manifests:
+	extensions/v1beta1 (Deployment, DaemonSet) is removed: upgrade Linkerd to a version installing apps/v1 Deployments and DaemonSets before upgrading Kubernetes
+	apiextensions.k8s.io/v1beta1 (CustomResourceDefinition) is deprecated: upgrade Linkerd to a version installing apiextensions.k8s.io/v1 CustomResourceDefinitions before upgrading Kubernetes
+	admissionregistration.k8s.io/v1beta1 (MutatingWebhookConfiguration, ValidatingWebhookConfiguration) is deprecated: install without --proxy-auto-inject to skip the proxy injector webhook, and upgrade Linkerd before upgrading Kubernetes`
+		if err.Error() != expected {
+			t.Fatalf("Expected error:\n%s\nbut got:\n%s", expected, err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// reflectionServiceName is the service of the reflection API itself, which is
+// left out of the generated profiles.
+const reflectionServiceName = "grpc.reflection.v1alpha.ServerReflection"
+
+// RenderGRPCReflection connects to the gRPC server at the given address, lists
+// its services and methods through the server reflection API, and renders
+// the corresponding ServiceProfile to a buffer, given a namespace, service,
+// and the retries and timeouts to set.
+func RenderGRPCReflection(addr, namespace, name string, timeout time.Duration, retries RetryConfig, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Debugf("Connecting to gRPC server at %s", addr)
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return fmt.Errorf("Error connecting to %s: %s", addr, err)
+	}
+	defer conn.Close()
+
+	profile, err := grpcReflectionToServiceProfile(ctx, rpb.NewServerReflectionClient(conn), namespace, name)
+	if err != nil {
+		return err
+	}
+
+	return writeProfile(*profile, retries, w)
+}
+
+func grpcReflectionToServiceProfile(ctx context.Context, client rpb.ServerReflectionClient, namespace, name string) (*sp.ServiceProfile, error) {
+	stream, err := client.ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Error calling the reflection API: %s", err)
+	}
+	defer stream.CloseSend()
+
+	rsp, err := reflectionRequest(stream, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	services := make([]string, 0)
+	for _, service := range rsp.GetListServicesResponse().GetService() {
+		if service.GetName() != reflectionServiceName {
+			services = append(services, service.GetName())
+		}
+	}
+	sort.Strings(services)
+
+	routes := make([]*sp.RouteSpec, 0)
+	for _, service := range services {
+		methods, err := reflectServiceMethods(stream, service)
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range methods {
+			routes = append(routes, grpcRouteSpec(service, method))
+		}
+	}
+
+	return newGRPCServiceProfile(routes, namespace, name), nil
+}
+
+// reflectServiceMethods returns the methods of a service, in the order of its
+// definition, given its fully qualified name.
+func reflectServiceMethods(stream rpb.ServerReflection_ServerReflectionInfoClient, service string) ([]string, error) {
+	rsp, err := reflectionRequest(stream, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The response may also hold the files imported by the service's file.
+	for _, file := range rsp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptor.FileDescriptorProto{}
+		err := proto.Unmarshal(file, fd)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing the descriptor of service %s: %s", service, err)
+		}
+
+		for _, svc := range fd.GetService() {
+			fullName := svc.GetName()
+			if fd.GetPackage() != "" {
+				fullName = fd.GetPackage() + "." + fullName
+			}
+			if fullName != service {
+				continue
+			}
+
+			methods := make([]string, 0)
+			for _, method := range svc.GetMethod() {
+				methods = append(methods, method.GetName())
+			}
+			return methods, nil
+		}
+	}
+
+	return nil, fmt.Errorf("The reflection API returned no descriptor for service %s", service)
+}
+
+func reflectionRequest(stream rpb.ServerReflection_ServerReflectionInfoClient, req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	err := stream.Send(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error calling the reflection API: %s", err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("Error calling the reflection API: %s", err)
+	}
+	if errRsp := rsp.GetErrorResponse(); errRsp != nil {
+		return nil, fmt.Errorf("The reflection API returned an error: %s", errRsp.GetErrorMessage())
+	}
+	return rsp, nil
+}
@@ -0,0 +1,71 @@
+package profiles
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type mockAPIServer struct {
+	pb.ApiServer
+}
+
+func TestGRPCReflectionToServiceProfile(t *testing.T) {
+	namespace := "myns"
+	name := "mysvc"
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterApiServer(server, &mockAPIServer{})
+	reflection.Register(server)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("Failed to dial the gRPC server: %v", err)
+	}
+	defer conn.Close()
+
+	actualServiceProfile, err := grpcReflectionToServiceProfile(context.Background(), rpb.NewServerReflectionClient(conn), namespace, name)
+	if err != nil {
+		t.Fatalf("Failed to create ServiceProfile: %v", err)
+	}
+
+	routes := make([]*sp.RouteSpec, 0)
+	for _, method := range []string{"StatSummary", "TopRoutes", "Edges", "ListPods", "ListServices", "Tap", "TapByResource", "Version", "SelfCheck"} {
+		routes = append(routes, &sp.RouteSpec{
+			Name: method,
+			Condition: &sp.RequestMatch{
+				PathRegex: `/linkerd2\.public\.Api/` + method,
+				Method:    "POST",
+			},
+		})
+	}
+	expectedServiceProfile := sp.ServiceProfile{
+		TypeMeta: ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "." + namespace + ".svc.cluster.local",
+			Namespace: namespace,
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: routes,
+		},
+	}
+
+	err = ServiceProfileYamlEquals(*actualServiceProfile, expectedServiceProfile)
+	if err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package profiles
+
+import (
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ForClient returns the profile as seen by a client in the given namespace
+// whose pod has the given labels: the routes and retry budget of the first
+// perClient entry that selects the client replace those of the profile. The
+// profile itself is returned if no entry selects the client.
+func ForClient(profile *sp.ServiceProfile, namespace string, podLabels map[string]string) *sp.ServiceProfile {
+	if profile == nil {
+		return nil
+	}
+
+	for _, client := range profile.Spec.PerClient {
+		if !clientMatches(client, namespace, podLabels) {
+			continue
+		}
+
+		clientProfile := profile.DeepCopy()
+		if len(client.Routes) > 0 {
+			clientProfile.Spec.Routes = client.Routes
+		}
+		if client.RetryBudget != nil {
+			clientProfile.Spec.RetryBudget = client.RetryBudget
+		}
+		clientProfile.Spec.PerClient = nil
+		return clientProfile
+	}
+
+	return profile
+}
+
+func clientMatches(client *sp.ClientProfile, namespace string, podLabels map[string]string) bool {
+	if client.Namespace != "" && client.Namespace != namespace {
+		return false
+	}
+	if client.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(client.PodSelector)
+		if err != nil || !selector.Matches(labels.Set(podLabels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesGRPCStatus returns true if rspMatch has a grpcStatus condition.
+func matchesGRPCStatus(rspMatch *sp.ResponseMatch) bool {
+	if rspMatch.GRPCStatus != nil {
+		return true
+	}
+	for _, m := range rspMatch.All {
+		if matchesGRPCStatus(m) {
+			return true
+		}
+	}
+	for _, m := range rspMatch.Any {
+		if matchesGRPCStatus(m) {
+			return true
+		}
+	}
+	return rspMatch.Not != nil && matchesGRPCStatus(rspMatch.Not)
+}
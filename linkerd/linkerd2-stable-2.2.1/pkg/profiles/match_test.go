@@ -0,0 +1,108 @@
+package profiles
+
+import (
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestForClient(t *testing.T) {
+	defaultRoutes := []*sp.RouteSpec{
+		&sp.RouteSpec{Name: "default", Condition: &sp.RequestMatch{Method: "GET"}},
+	}
+	batchBudget := &sp.RetryBudget{RetryRatio: 0.5, MinRetriesPerSecond: 1, TTL: "10s"}
+	canaryRoutes := []*sp.RouteSpec{
+		&sp.RouteSpec{Name: "canary", Condition: &sp.RequestMatch{Method: "POST"}},
+	}
+
+	profile := &sp.ServiceProfile{
+		Spec: sp.ServiceProfileSpec{
+			Routes: defaultRoutes,
+			PerClient: []*sp.ClientProfile{
+				&sp.ClientProfile{
+					Namespace:   "batch",
+					RetryBudget: batchBudget,
+				},
+				&sp.ClientProfile{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"track": "canary"},
+					},
+					Routes: canaryRoutes,
+				},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		desc                string
+		namespace           string
+		podLabels           map[string]string
+		expectedRoute       string
+		expectedRetryBudget *sp.RetryBudget
+	}{
+		{
+			desc:          "returns the profile to clients that aren't selected",
+			namespace:     "web",
+			podLabels:     map[string]string{"track": "stable"},
+			expectedRoute: "default",
+		},
+		{
+			desc:                "overrides the retry budget for clients in a namespace",
+			namespace:           "batch",
+			podLabels:           map[string]string{"track": "canary"},
+			expectedRoute:       "default",
+			expectedRetryBudget: batchBudget,
+		},
+		{
+			desc:          "overrides the routes for clients with matching pod labels",
+			namespace:     "web",
+			podLabels:     map[string]string{"track": "canary"},
+			expectedRoute: "canary",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			clientProfile := ForClient(profile, tt.namespace, tt.podLabels)
+
+			if len(clientProfile.Spec.Routes) != 1 || clientProfile.Spec.Routes[0].Name != tt.expectedRoute {
+				t.Fatalf("Expected route [%s], got %+v", tt.expectedRoute, clientProfile.Spec.Routes)
+			}
+			if clientProfile.Spec.RetryBudget != tt.expectedRetryBudget {
+				t.Fatalf("Expected retry budget %+v, got %+v", tt.expectedRetryBudget, clientProfile.Spec.RetryBudget)
+			}
+		})
+	}
+
+	if profile.Spec.Routes[0].Name != "default" || profile.Spec.RetryBudget != nil {
+		t.Fatalf("ForClient modified the profile: %+v", profile.Spec)
+	}
+}
+
+func TestToRouteSkipsGRPCStatusClasses(t *testing.T) {
+	route := &sp.RouteSpec{
+		Name:      "vote",
+		Condition: &sp.RequestMatch{Method: "POST"},
+		ResponseClasses: []*sp.ResponseClass{
+			&sp.ResponseClass{
+				Condition: &sp.ResponseMatch{GRPCStatus: &sp.Range{Min: 5}},
+				IsFailure: true,
+			},
+			&sp.ResponseClass{
+				Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 404}},
+			},
+		},
+	}
+
+	pbRoute, err := ToRoute(&sp.ServiceProfile{}, route)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(pbRoute.ResponseClasses) != 1 || pbRoute.ResponseClasses[0].GetCondition().GetStatus().GetMin() != 404 {
+		t.Fatalf("Expected only the status response class, got %+v", pbRoute.ResponseClasses)
+	}
+
+	err = ValidateResponseMatch(&sp.ResponseMatch{GRPCStatus: &sp.Range{Min: 17}})
+	if err == nil {
+		t.Fatal("Expected an error for an out of range grpc-status, got none")
+	}
+}
@@ -1,13 +1,19 @@
 package profiles
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-openapi/spec"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
@@ -15,42 +21,117 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
-var pathParamRegex = regexp.MustCompile(`\\{[^\}]*\\}`)
+var (
+	pathParamRegex = regexp.MustCompile(`\\{[^\}]*\\}`)
 
-// RenderOpenAPI reads an OpenAPI spec file and renders the corresponding
-// ServiceProfile to a buffer, given a namespace, service, and control plane
-// namespace.
-func RenderOpenAPI(fileName, namespace, name string, w io.Writer) error {
+	// openAPI3Methods are the fields of an OpenAPI 3 path item that describe
+	// operations, sorted like the routes generated from OpenAPI 2 specs.
+	openAPI3Methods = []string{"delete", "get", "head", "options", "patch", "post", "put", "trace"}
+)
+
+// openAPI3 is the subset of an OpenAPI 3 document used to generate a
+// ServiceProfile. The go-openapi packages only support OpenAPI 2.
+type openAPI3 struct {
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	// Paths maps the paths to their path items, whose fields other than
+	// operations (e.g. "parameters") are ignored.
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPI3Operation struct {
+	// Responses maps status codes (e.g. "404"), status code ranges (e.g.
+	// "5XX") or "default" to responses, whose content is ignored.
+	Responses map[string]json.RawMessage `json:"responses"`
+}
 
-	input, err := readFile(fileName)
+// RenderOpenAPI reads an OpenAPI 2 or 3 spec file, or fetches it from an
+// HTTP(S) URL, and renders the corresponding ServiceProfile to a buffer, given
+// a namespace, service, and the retries and timeouts to set.
+func RenderOpenAPI(fileName, namespace, name string, retries RetryConfig, w io.Writer) error {
+	var input io.Reader
+	var err error
+	if strings.HasPrefix(fileName, "http://") || strings.HasPrefix(fileName, "https://") {
+		input, err = fetchURL(fileName)
+	} else {
+		input, err = readFile(fileName)
+	}
 	if err != nil {
 		return err
 	}
 
-	bytes, err := ioutil.ReadAll(input)
+	data, err := ioutil.ReadAll(input)
 	if err != nil {
 		return fmt.Errorf("Error reading file: %s", err)
 	}
-	json, err := yaml.YAMLToJSON(bytes)
+	jsonBytes, err := yaml.YAMLToJSON(data)
 	if err != nil {
 		return fmt.Errorf("Error parsing yaml: %s", err)
 	}
 
-	swagger := spec.Swagger{}
-	err = swagger.UnmarshalJSON(json)
+	var version struct {
+		OpenAPI string `json:"openapi"`
+	}
+	err = json.Unmarshal(jsonBytes, &version)
 	if err != nil {
 		return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
 	}
 
-	profile := swaggerToServiceProfile(swagger, namespace, name)
+	var profile sp.ServiceProfile
+	if strings.HasPrefix(version.OpenAPI, "3.") {
+		doc := openAPI3{}
+		err = json.Unmarshal(jsonBytes, &doc)
+		if err != nil {
+			return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+		}
+		profile, err = openAPI3ToServiceProfile(doc, namespace, name)
+		if err != nil {
+			return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+		}
+	} else {
+		swagger := spec.Swagger{}
+		err = swagger.UnmarshalJSON(jsonBytes)
+		if err != nil {
+			return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+		}
+		profile = swaggerToServiceProfile(swagger, namespace, name)
+	}
+
+	return writeProfile(profile, retries, w)
+}
+
+// fetchURL fetches an OpenAPI spec, e.g. from the /openapi/v2 endpoint of a
+// live service.
+func fetchURL(specURL string) (io.Reader, error) {
+	req, err := http.NewRequest(http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+
+	client := http.Client{Timeout: 30 * time.Second}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching %s: %s", specURL, err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error fetching %s: unexpected status %s", specURL, rsp.Status)
+	}
 
-	return writeProfile(profile, w)
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching %s: %s", specURL, err)
+	}
+	return bytes.NewReader(body), nil
 }
 
 func swaggerToServiceProfile(swagger spec.Swagger, namespace, name string) sp.ServiceProfile {
 	profile := sp.ServiceProfile{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+			Name:      ProfileName(namespace, name),
 			Namespace: namespace,
 		},
 		TypeMeta: ServiceProfileMeta,
@@ -104,6 +185,62 @@ func swaggerToServiceProfile(swagger spec.Swagger, namespace, name string) sp.Se
 	return profile
 }
 
+func openAPI3ToServiceProfile(doc openAPI3, namespace, name string) (sp.ServiceProfile, error) {
+	profile := sp.ServiceProfile{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      ProfileName(namespace, name),
+			Namespace: namespace,
+		},
+		TypeMeta: ServiceProfileMeta,
+	}
+
+	// The paths are relative to the path of the first server's URL, which
+	// may itself be relative, e.g. "/v1".
+	basePath := "/"
+	if len(doc.Servers) > 0 {
+		serverURL, err := url.Parse(doc.Servers[0].URL)
+		if err != nil {
+			return profile, fmt.Errorf("invalid server URL %q: %s", doc.Servers[0].URL, err)
+		}
+		if serverURL.Path != "" {
+			basePath = serverURL.Path
+		}
+	}
+
+	paths := make([]string, 0)
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	routes := make([]*sp.RouteSpec, 0)
+	for _, relPath := range paths {
+		item := doc.Paths[relPath]
+		path := path.Join(basePath, relPath)
+		pathRegex := pathToRegex(path)
+		for _, method := range openAPI3Methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var operation openAPI3Operation
+			err := json.Unmarshal(raw, &operation)
+			if err != nil {
+				return profile, fmt.Errorf("invalid %s operation of path %s: %s", method, relPath, err)
+			}
+			httpMethod := strings.ToUpper(method)
+			routes = append(routes, &sp.RouteSpec{
+				Name:            fmt.Sprintf("%s %s", httpMethod, path),
+				Condition:       toReqMatch(pathRegex, httpMethod),
+				ResponseClasses: toOpenAPI3RspClasses(operation.Responses),
+			})
+		}
+	}
+
+	profile.Spec.Routes = routes
+	return profile, nil
+}
+
 func mkRouteSpec(path, pathRegex string, method string, responses *spec.Responses) *sp.RouteSpec {
 	return &sp.RouteSpec{
 		Name:            fmt.Sprintf("%s %s", method, path),
@@ -150,3 +287,37 @@ func toRspClasses(responses *spec.Responses) []*sp.ResponseClass {
 	}
 	return classes
 }
+
+// toOpenAPI3RspClasses returns a response class per status code or status
+// code range (e.g. "5XX") of an OpenAPI 3 operation's responses. The default
+// response is ignored.
+func toOpenAPI3RspClasses(responses map[string]json.RawMessage) []*sp.ResponseClass {
+	if responses == nil {
+		return nil
+	}
+	ranges := make([]*sp.Range, 0)
+	for code := range responses {
+		if status, err := strconv.Atoi(code); err == nil {
+			ranges = append(ranges, &sp.Range{Min: uint32(status), Max: uint32(status)})
+			continue
+		}
+		upper := strings.ToUpper(code)
+		if len(upper) == 3 && strings.HasSuffix(upper, "XX") && upper[0] >= '1' && upper[0] <= '5' {
+			hundreds := uint32(upper[0]-'0') * 100
+			ranges = append(ranges, &sp.Range{Min: hundreds, Max: hundreds + 99})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Min < ranges[j].Min ||
+			(ranges[i].Min == ranges[j].Min && ranges[i].Max < ranges[j].Max)
+	})
+
+	classes := make([]*sp.ResponseClass, 0)
+	for _, r := range ranges {
+		classes = append(classes, &sp.ResponseClass{
+			Condition: &sp.ResponseMatch{Status: r},
+			IsFailure: r.Min >= 500,
+		})
+	}
+	return classes
+}
@@ -1,11 +1,18 @@
 package profiles
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/go-openapi/spec"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 func TestSwaggerToServiceProfile(t *testing.T) {
@@ -73,3 +80,130 @@ func TestSwaggerToServiceProfile(t *testing.T) {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+var openAPI3Spec = `
+openapi: 3.0.0
+servers:
+- url: https://books.example.com/v1
+paths:
+  /authors/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+    get:
+      responses:
+        "200":
+          description: the author
+        5XX:
+          description: server error
+        default:
+          description: unexpected error
+    delete:
+      responses:
+        "404":
+          description: not found
+`
+
+func TestOpenAPI3ToServiceProfile(t *testing.T) {
+	expectedRoutes := []*sp.RouteSpec{
+		&sp.RouteSpec{
+			Name: "DELETE /v1/authors/{id}",
+			Condition: &sp.RequestMatch{
+				PathRegex: "/v1/authors/[^/]*",
+				Method:    "DELETE",
+			},
+			ResponseClasses: []*sp.ResponseClass{
+				&sp.ResponseClass{
+					Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 404, Max: 404}},
+				},
+			},
+		},
+		&sp.RouteSpec{
+			Name: "GET /v1/authors/{id}",
+			Condition: &sp.RequestMatch{
+				PathRegex: "/v1/authors/[^/]*",
+				Method:    "GET",
+			},
+			ResponseClasses: []*sp.ResponseClass{
+				&sp.ResponseClass{
+					Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 200, Max: 200}},
+				},
+				&sp.ResponseClass{
+					Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 500, Max: 599}},
+					IsFailure: true,
+				},
+			},
+		},
+	}
+
+	t.Run("Renders an OpenAPI 3 spec file", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderOpenAPIString(openAPI3Spec, &buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		checkOpenAPIRoutes(t, buf.Bytes(), expectedRoutes)
+	})
+
+	t.Run("Renders an OpenAPI spec from a URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/openapi/v3" {
+				http.NotFound(w, req)
+				return
+			}
+			fmt.Fprint(w, openAPI3Spec)
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		err := RenderOpenAPI(server.URL+"/openapi/v3", "myns", "mysvc", RetryConfig{}, &buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		checkOpenAPIRoutes(t, buf.Bytes(), expectedRoutes)
+
+		err = RenderOpenAPI(server.URL+"/missing", "myns", "mysvc", RetryConfig{}, &buf)
+		if err == nil {
+			t.Fatal("Expected an error fetching a missing spec, got none")
+		}
+	})
+}
+
+func renderOpenAPIString(spec string, buf *bytes.Buffer) error {
+	file, err := ioutil.TempFile("", "openapi")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(spec)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	return RenderOpenAPI(file.Name(), "myns", "mysvc", RetryConfig{}, buf)
+}
+
+func checkOpenAPIRoutes(t *testing.T, output []byte, expectedRoutes []*sp.RouteSpec) {
+	var actual sp.ServiceProfile
+	err := yaml.Unmarshal(output, &actual)
+	if err != nil {
+		t.Fatalf("Error parsing service profile: %s", err)
+	}
+
+	expected := sp.ServiceProfile{
+		TypeMeta: ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mysvc.myns.svc.cluster.local",
+			Namespace: "myns",
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: expectedRoutes,
+		},
+	}
+	err = ServiceProfileYamlEquals(actual, expected)
+	if err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+}
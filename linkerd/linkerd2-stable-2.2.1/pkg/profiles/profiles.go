@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
 	"text/template"
 	"time"
 
@@ -22,7 +24,35 @@ import (
 type profileTemplateConfig struct {
 	ServiceNamespace string
 	ServiceName      string
-	ClusterZone      string
+	ProfileName      string
+	RetryConfig
+}
+
+// RetryConfig configures the retries and timeouts of the ServiceProfiles
+// rendered by the `linkerd profile` command. Its zero value leaves them unset,
+// so that the proxy's defaults apply.
+type RetryConfig struct {
+	// IsRetryable marks all the routes as retryable.
+	IsRetryable bool
+	// Timeout is the timeout of all the routes, e.g. "250ms".
+	Timeout string
+	// RetryBudget is the retry budget of the service.
+	RetryBudget *sp.RetryBudget
+}
+
+// apply sets the retries and timeouts of the profile and of all its routes.
+func (c RetryConfig) apply(profile *sp.ServiceProfile) {
+	for _, route := range profile.Spec.Routes {
+		if c.IsRetryable {
+			route.IsRetryable = true
+		}
+		if c.Timeout != "" {
+			route.Timeout = c.Timeout
+		}
+	}
+	if c.RetryBudget != nil {
+		profile.Spec.RetryBudget = c.RetryBudget
+	}
 }
 
 var (
@@ -51,6 +81,8 @@ var (
 	minStatus uint32 = 100
 	maxStatus uint32 = 599
 
+	maxGRPCStatus uint32 = 16
+
 	clusterZoneSuffix = "svc.cluster.local"
 
 	errRequestMatchField  = errors.New("A request match must have a field set")
@@ -99,6 +131,11 @@ func ToRoute(profile *sp.ServiceProfile, route *sp.RouteSpec) (*pb.Route, error)
 	}
 	rcs := make([]*pb.ResponseClass, 0)
 	for _, rc := range route.ResponseClasses {
+		// the proxy can't classify responses by their grpc-status, so these
+		// classes are left to the route stats of the public API
+		if rc.Condition != nil && matchesGRPCStatus(rc.Condition) {
+			continue
+		}
 		pbRc, err := ToResponseClass(rc)
 		if err != nil {
 			return nil, err
@@ -202,6 +239,10 @@ func ToResponseMatch(rspMatch *sp.ResponseMatch) (*pb.ResponseMatch, error) {
 		})
 	}
 
+	if rspMatch.GRPCStatus != nil {
+		return nil, errors.New("A response match on the grpc-status can't be sent to the proxy")
+	}
+
 	if rspMatch.Not != nil {
 		not, err := ToResponseMatch(rspMatch.Not)
 		if err != nil {
@@ -341,64 +382,200 @@ func Validate(data []byte) error {
 		return fmt.Errorf("failed to validate ServiceProfile: %s", err)
 	}
 
-	errs := validation.IsDNS1123Subdomain(serviceProfile.Name)
+	return ValidateServiceProfile(&serviceProfile)
+}
+
+// ValidateServiceProfile validates the fields of a ServiceProfile that has
+// already been unmarshaled. On top of the checks described in Validate, it
+// checks that:
+// - route names are unique
+// - path regexes compile
+// - timeouts and retry budget TTLs are positive durations
+// - dstOverrides have authorities and a positive total weight
+// - perClient entries select clients and have valid routes and retry budgets
+func ValidateServiceProfile(serviceProfile *sp.ServiceProfile) error {
+	errs := validateServiceProfile(serviceProfile)
 	if len(errs) > 0 {
-		return fmt.Errorf("ServiceProfile \"%s\" has invalid name: %s", serviceProfile.Name, errs[0])
+		return errs[0].err
 	}
+	return nil
+}
 
-	if len(serviceProfile.Spec.Routes) == 0 {
-		return fmt.Errorf("ServiceProfile \"%s\" has no routes", serviceProfile.Name)
-	}
+// fieldError is an error in a field of a ServiceProfile, given by its path,
+// e.g. "spec.routes[1]".
+type fieldError struct {
+	path string
+	err  error
+}
 
-	for _, route := range serviceProfile.Spec.Routes {
-		if route.Name == "" {
-			return fmt.Errorf("ServiceProfile \"%s\" has a route with no name", serviceProfile.Name)
+// validateServiceProfile returns all the errors found by
+// ValidateServiceProfile, in the order of the fields. Only the first error of
+// each route is returned.
+func validateServiceProfile(serviceProfile *sp.ServiceProfile) []fieldError {
+	errs := make([]fieldError, 0)
+	addErr := func(path string, err error) {
+		if err != nil {
+			errs = append(errs, fieldError{path: path, err: err})
 		}
-		if route.Timeout != "" {
-			_, err := time.ParseDuration(route.Timeout)
-			if err != nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid timeout: %s", serviceProfile.Name, err)
+	}
+
+	nameErrs := validation.IsDNS1123Subdomain(serviceProfile.Name)
+	if len(nameErrs) > 0 {
+		addErr("metadata.name", fmt.Errorf("ServiceProfile \"%s\" has invalid name: %s", serviceProfile.Name, nameErrs[0]))
+	}
+
+	if len(serviceProfile.Spec.Routes) == 0 && len(serviceProfile.Spec.DstOverrides) == 0 {
+		addErr("spec", fmt.Errorf("ServiceProfile \"%s\" has no routes", serviceProfile.Name))
+	}
+
+	errs = append(errs, validateRoutes(serviceProfile.Name, "spec.routes", serviceProfile.Spec.Routes)...)
+
+	addErr("spec.retryBudget", validateRetryBudget(serviceProfile.Name, serviceProfile.Spec.RetryBudget))
+
+	if len(serviceProfile.Spec.DstOverrides) > 0 {
+		var totalWeight uint32
+		for i, dst := range serviceProfile.Spec.DstOverrides {
+			if dst.Authority == "" {
+				addErr(fmt.Sprintf("spec.dstOverrides[%d]", i), fmt.Errorf("ServiceProfile \"%s\" has a dstOverride with no authority", serviceProfile.Name))
 			}
+			totalWeight += dst.Weight
 		}
-		if route.Condition == nil {
-			return fmt.Errorf("ServiceProfile \"%s\" has a route with no condition", serviceProfile.Name)
+		if totalWeight == 0 {
+			addErr("spec.dstOverrides", fmt.Errorf("ServiceProfile \"%s\" dstOverrides must have a positive total weight", serviceProfile.Name))
 		}
-		err := ValidateRequestMatch(route.Condition)
-		if err != nil {
-			return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid condition: %s", serviceProfile.Name, err)
+	}
+
+	for i, client := range serviceProfile.Spec.PerClient {
+		path := fmt.Sprintf("spec.perClient[%d]", i)
+		if client.Namespace == "" && client.PodSelector == nil {
+			addErr(path, fmt.Errorf("ServiceProfile \"%s\" has a perClient entry with no namespace or podSelector", serviceProfile.Name))
 		}
-		for _, rc := range route.ResponseClasses {
-			if rc.Condition == nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a response class with no condition", serviceProfile.Name)
-			}
-			err = ValidateResponseMatch(rc.Condition)
+		if client.PodSelector != nil {
+			_, err := meta_v1.LabelSelectorAsSelector(client.PodSelector)
 			if err != nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a response class with an invalid condition: %s", serviceProfile.Name, err)
+				addErr(path+".podSelector", fmt.Errorf("ServiceProfile \"%s\" has a perClient entry with an invalid podSelector: %s", serviceProfile.Name, err))
 			}
 		}
+		errs = append(errs, validateRoutes(serviceProfile.Name, path+".routes", client.Routes)...)
+		addErr(path+".retryBudget", validateRetryBudget(serviceProfile.Name, client.RetryBudget))
 	}
 
-	rb := serviceProfile.Spec.RetryBudget
-	if rb != nil {
-		if rb.RetryRatio < 0 {
-			return fmt.Errorf("ServiceProfile \"%s\" RetryBudget RetryRatio must be non-negative: %f", serviceProfile.Name, rb.RetryRatio)
+	return errs
+}
+
+// validateRoutes returns the first error of each route, and an error for each
+// route whose name is already taken.
+func validateRoutes(name, path string, routes []*sp.RouteSpec) []fieldError {
+	errs := make([]fieldError, 0)
+	routeNames := make(map[string]struct{})
+	for i, route := range routes {
+		routePath := fmt.Sprintf("%s[%d]", path, i)
+		if route.Name != "" {
+			if _, ok := routeNames[route.Name]; ok {
+				errs = append(errs, fieldError{
+					path: routePath,
+					err:  fmt.Errorf("ServiceProfile \"%s\" has multiple routes named \"%s\"", name, route.Name),
+				})
+				continue
+			}
+			routeNames[route.Name] = struct{}{}
 		}
 
-		if rb.TTL == "" {
-			return fmt.Errorf("ServiceProfile \"%s\" RetryBudget missing TTL field", serviceProfile.Name)
+		err := validateRoute(name, route)
+		if err != nil {
+			errs = append(errs, fieldError{path: routePath, err: err})
 		}
+	}
+	return errs
+}
 
-		_, err := time.ParseDuration(rb.TTL)
+func validateRoute(name string, route *sp.RouteSpec) error {
+	if route.Name == "" {
+		return fmt.Errorf("ServiceProfile \"%s\" has a route with no name", name)
+	}
+	if route.Timeout != "" {
+		timeout, err := time.ParseDuration(route.Timeout)
 		if err != nil {
-			return fmt.Errorf("ServiceProfile \"%s\" RetryBudget: %s", serviceProfile.Name, err)
+			return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid timeout: %s", name, err)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("ServiceProfile \"%s\" has a route with a non-positive timeout: %s", name, route.Timeout)
 		}
 	}
+	if route.Condition == nil {
+		return fmt.Errorf("ServiceProfile \"%s\" has a route with no condition", name)
+	}
+	err := ValidateRequestMatch(route.Condition)
+	if err != nil {
+		return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid condition: %s", name, err)
+	}
+	for _, rc := range route.ResponseClasses {
+		if rc.Condition == nil {
+			return fmt.Errorf("ServiceProfile \"%s\" has a response class with no condition", name)
+		}
+		err = ValidateResponseMatch(rc.Condition)
+		if err != nil {
+			return fmt.Errorf("ServiceProfile \"%s\" has a response class with an invalid condition: %s", name, err)
+		}
+	}
+	if route.SLO != nil {
+		err = validateRouteSLO(route.SLO)
+		if err != nil {
+			return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid SLO: %s", name, err)
+		}
+	}
+	return nil
+}
 
+func validateRouteSLO(slo *sp.RouteSLO) error {
+	if slo.SuccessRate == 0 && slo.Latency == "" {
+		return errors.New("no objective is set")
+	}
+	if slo.SuccessRate < 0 || slo.SuccessRate >= 1 {
+		return fmt.Errorf("the success rate must be between 0 and 1: %v", slo.SuccessRate)
+	}
+	if slo.Latency != "" {
+		latency, err := time.ParseDuration(slo.Latency)
+		if err != nil {
+			return err
+		}
+		if latency <= 0 {
+			return fmt.Errorf("the latency must be positive: %s", slo.Latency)
+		}
+	}
+	switch slo.LatencyPercentile {
+	case 0, 50, 95, 99:
+	default:
+		return fmt.Errorf("the latency percentile must be 50, 95 or 99: %d", slo.LatencyPercentile)
+	}
+	return nil
+}
+
+func validateRetryBudget(name string, rb *sp.RetryBudget) error {
+	if rb == nil {
+		return nil
+	}
+
+	if rb.RetryRatio < 0 {
+		return fmt.Errorf("ServiceProfile \"%s\" RetryBudget RetryRatio must be non-negative: %f", name, rb.RetryRatio)
+	}
+
+	if rb.TTL == "" {
+		return fmt.Errorf("ServiceProfile \"%s\" RetryBudget missing TTL field", name)
+	}
+
+	ttl, err := time.ParseDuration(rb.TTL)
+	if err != nil {
+		return fmt.Errorf("ServiceProfile \"%s\" RetryBudget: %s", name, err)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("ServiceProfile \"%s\" RetryBudget TTL must be positive: %s", name, rb.TTL)
+	}
 	return nil
 }
 
 // ValidateRequestMatch validates whether a ServiceProfile RequestMatch has at
-// least one field set.
+// least one field set, and whether its path regexes compile.
 func ValidateRequestMatch(reqMatch *sp.RequestMatch) error {
 	matchKindSet := false
 	if reqMatch.All != nil {
@@ -431,6 +608,9 @@ func ValidateRequestMatch(reqMatch *sp.RequestMatch) error {
 	}
 	if reqMatch.PathRegex != "" {
 		matchKindSet = true
+		if _, err := regexp.Compile("^(?:" + reqMatch.PathRegex + ")$"); err != nil {
+			return fmt.Errorf("Invalid pathRegex \"%s\": %s", reqMatch.PathRegex, err)
+		}
 	}
 
 	if !matchKindSet {
@@ -441,7 +621,7 @@ func ValidateRequestMatch(reqMatch *sp.RequestMatch) error {
 }
 
 // ValidateResponseMatch validates whether a ServiceProfile ResponseMatch has at
-// least one field set, and sanity checks the Status Range.
+// least one field set, and sanity checks the Status and GRPCStatus Ranges.
 func ValidateResponseMatch(rspMatch *sp.ResponseMatch) error {
 	matchKindSet := false
 	if rspMatch.All != nil {
@@ -472,6 +652,14 @@ func ValidateResponseMatch(rspMatch *sp.ResponseMatch) error {
 		}
 		matchKindSet = true
 	}
+	if rspMatch.GRPCStatus != nil {
+		if rspMatch.GRPCStatus.Min > maxGRPCStatus || rspMatch.GRPCStatus.Max > maxGRPCStatus {
+			return fmt.Errorf("grpc-status range must be between 0 and %d, inclusive", maxGRPCStatus)
+		} else if rspMatch.GRPCStatus.Max != 0 && rspMatch.GRPCStatus.Min != 0 && rspMatch.GRPCStatus.Max < rspMatch.GRPCStatus.Min {
+			return errors.New("Range maximum cannot be smaller than minimum")
+		}
+		matchKindSet = true
+	}
 	if rspMatch.Not != nil {
 		matchKindSet = true
 		err := ValidateResponseMatch(rspMatch.Not)
@@ -487,18 +675,30 @@ func ValidateResponseMatch(rspMatch *sp.ResponseMatch) error {
 	return nil
 }
 
-func buildConfig(namespace, service string) *profileTemplateConfig {
+// ProfileName returns the name of the ServiceProfile of a service given its
+// namespace and name, i.e. the FQDN of the service. Names that are DNS names
+// themselves, e.g. "api.github.com", are external authorities: their profiles
+// are named after them, and live in the controller namespace.
+func ProfileName(namespace, name string) string {
+	if strings.Contains(name, ".") {
+		return strings.TrimSuffix(name, ".")
+	}
+	return fmt.Sprintf("%s.%s.%s", name, namespace, clusterZoneSuffix)
+}
+
+func buildConfig(namespace, service string, retries RetryConfig) *profileTemplateConfig {
 	return &profileTemplateConfig{
 		ServiceNamespace: namespace,
 		ServiceName:      service,
-		ClusterZone:      clusterZoneSuffix,
+		ProfileName:      ProfileName(namespace, service),
+		RetryConfig:      retries,
 	}
 }
 
 // RenderProfileTemplate renders a ServiceProfile template to a buffer, given a
-// namespace, service, and control plane namespace.
-func RenderProfileTemplate(namespace, service string, w io.Writer) error {
-	config := buildConfig(namespace, service)
+// namespace, service, and the retries and timeouts to set.
+func RenderProfileTemplate(namespace, service string, retries RetryConfig, w io.Writer) error {
+	config := buildConfig(namespace, service, retries)
 	template, err := template.New("profile").Parse(Template)
 	if err != nil {
 		return err
@@ -520,7 +720,8 @@ func readFile(fileName string) (io.Reader, error) {
 	return os.Open(fileName)
 }
 
-func writeProfile(profile sp.ServiceProfile, w io.Writer) error {
+func writeProfile(profile sp.ServiceProfile, retries RetryConfig, w io.Writer) error {
+	retries.apply(&profile)
 	output, err := yaml.Marshal(profile)
 	if err != nil {
 		return fmt.Errorf("Error writing Service Profile: %s", err)
@@ -400,6 +400,221 @@ spec:
     retryRatio: 0.2
     ttl: 10s
   routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has multiple routes named \"name-1\""),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+  - name: name-1
+    condition:
+      method: POST
+      pathRegex: /route-1`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a route with an invalid condition: Invalid pathRegex \"/route-(1\": error parsing regexp: missing closing ): `^(?:/route-(1)$`"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-(1`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a route with a non-positive timeout: -1s"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+    timeout: -1s`,
+		},
+		{
+			err: nil,
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+    slo:
+      successRate: 0.999
+      latency: 250ms
+      latencyPercentile: 95`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a route with an invalid SLO: the success rate must be between 0 and 1: 99.9"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+    slo:
+      successRate: 99.9`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a route with an invalid SLO: the latency percentile must be 50, 95 or 99: 90"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+    slo:
+      latency: 100ms
+      latencyPercentile: 90`,
+		},
+		{
+			err: nil,
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  dstOverrides:
+  - authority: name-v1.ns.svc.cluster.local
+    weight: 90
+  - authority: name-v2.ns.svc.cluster.local
+    weight: 10`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a dstOverride with no authority"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  dstOverrides:
+  - weight: 10`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" dstOverrides must have a positive total weight"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  dstOverrides:
+  - authority: name-v1.ns.svc.cluster.local
+    weight: 0`,
+		},
+		{
+			err: nil,
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+      pathRegex: /route-1
+  perClient:
+  - namespace: batch
+    retryBudget:
+      minRetriesPerSecond: 1
+      retryRatio: 0.5
+      ttl: 10s
+  - podSelector:
+      matchLabels:
+        track: canary
+    routes:
+    - name: name-2
+      condition:
+        method: POST`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a perClient entry with no namespace or podSelector"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+  perClient:
+  - retryBudget:
+      minRetriesPerSecond: 1
+      retryRatio: 0.5
+      ttl: 10s`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" has a route with no condition"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  routes:
+  - name: name-1
+    condition:
+      method: GET
+  perClient:
+  - namespace: batch
+    routes:
+    - name: name-2`,
+		},
+		{
+			err: errors.New("ServiceProfile \"name.ns.svc.cluster.local\" RetryBudget TTL must be positive: 0s"),
+			sp: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: name.ns.svc.cluster.local
+  namespace: linkerd-ns
+spec:
+  retryBudget:
+    minRetriesPerSecond: 5
+    retryRatio: 0.2
+    ttl: 0s
+  routes:
   - name: name-1
     condition:
       method: GET
@@ -4,30 +4,33 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 
 	"github.com/emicklei/proto"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	log "github.com/sirupsen/logrus"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// RenderProto reads a protobuf definition file and renders the corresponding
-// ServiceProfile to a buffer, given a namespace, service, and control plane
-// namespace.
-func RenderProto(fileName, namespace, name string, w io.Writer) error {
-	input, err := readFile(fileName)
-	if err != nil {
-		return err
-	}
-
-	parser := proto.NewParser(input)
-
-	profile, err := protoToServiceProfile(parser, namespace, name)
-	if err != nil {
-		return err
+// RenderProto reads protobuf definition files and renders the corresponding
+// ServiceProfile to a buffer, given a namespace, service, and the retries and
+// timeouts to set. The files they import are looked up in the import paths,
+// then in the directory of the importing file, and the services they define
+// are included as well. Imports that can't be found, such as the well-known
+// types, are skipped.
+func RenderProto(fileNames, importPaths []string, namespace, name string, retries RetryConfig, w io.Writer) error {
+	resolver := newProtoResolver(importPaths)
+	for _, fileName := range fileNames {
+		err := resolver.addFile(fileName)
+		if err != nil {
+			return err
+		}
 	}
 
-	return writeProfile(*profile, w)
+	profile := newGRPCServiceProfile(resolver.routes, namespace, name)
+	return writeProfile(*profile, retries, w)
 }
 
 func protoToServiceProfile(parser *proto.Parser, namespace, name string) (*sp.ServiceProfile, error) {
@@ -36,37 +39,133 @@ func protoToServiceProfile(parser *proto.Parser, namespace, name string) (*sp.Se
 		return nil, err
 	}
 
+	routes, _ := protoRoutes(definition)
+	return newGRPCServiceProfile(routes, namespace, name), nil
+}
+
+func newGRPCServiceProfile(routes []*sp.RouteSpec, namespace, name string) *sp.ServiceProfile {
+	return &sp.ServiceProfile{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      ProfileName(namespace, name),
+			Namespace: namespace,
+		},
+		TypeMeta: ServiceProfileMeta,
+		Spec: sp.ServiceProfileSpec{
+			Routes: routes,
+		},
+	}
+}
+
+// protoRoutes returns the routes of the RPCs of the services defined in a
+// protobuf file, including streaming ones, and the files it imports.
+func protoRoutes(definition *proto.Proto) ([]*sp.RouteSpec, []string) {
 	routes := make([]*sp.RouteSpec, 0)
+	imports := make([]string, 0)
 	pkg := ""
 
 	handle := func(visitee proto.Visitee) {
 		switch typed := visitee.(type) {
 		case *proto.Package:
 			pkg = typed.Name
+		case *proto.Import:
+			imports = append(imports, typed.Filename)
 		case *proto.RPC:
 			if service, ok := typed.Parent.(*proto.Service); ok {
-				route := &sp.RouteSpec{
-					Name: typed.Name,
-					Condition: &sp.RequestMatch{
-						Method:    http.MethodPost,
-						PathRegex: regexp.QuoteMeta(fmt.Sprintf("/%s.%s/%s", pkg, service.Name, typed.Name)),
-					},
+				// services of files without a package aren't qualified
+				serviceName := service.Name
+				if pkg != "" {
+					serviceName = fmt.Sprintf("%s.%s", pkg, service.Name)
 				}
-				routes = append(routes, route)
+				routes = append(routes, grpcRouteSpec(serviceName, typed.Name))
 			}
 		}
 	}
 
 	proto.Walk(definition, handle)
 
-	return &sp.ServiceProfile{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
-			Namespace: namespace,
-		},
-		TypeMeta: ServiceProfileMeta,
-		Spec: sp.ServiceProfileSpec{
-			Routes: routes,
+	return routes, imports
+}
+
+// protoResolver collects the routes of protobuf files and of the files they
+// import, parsing each file once.
+type protoResolver struct {
+	importPaths []string
+	parsed      map[string]struct{}
+	routes      []*sp.RouteSpec
+}
+
+func newProtoResolver(importPaths []string) *protoResolver {
+	return &protoResolver{
+		importPaths: importPaths,
+		parsed:      make(map[string]struct{}),
+		routes:      make([]*sp.RouteSpec, 0),
+	}
+}
+
+func (r *protoResolver) addFile(fileName string) error {
+	if fileName != "-" {
+		fileName = filepath.Clean(fileName)
+	}
+	if _, ok := r.parsed[fileName]; ok {
+		return nil
+	}
+	r.parsed[fileName] = struct{}{}
+
+	input, err := readFile(fileName)
+	if err != nil {
+		return err
+	}
+	if file, ok := input.(*os.File); ok && file != os.Stdin {
+		defer file.Close()
+	}
+
+	definition, err := proto.NewParser(input).Parse()
+	if err != nil {
+		return fmt.Errorf("Error parsing %s: %s", fileName, err)
+	}
+
+	routes, imports := protoRoutes(definition)
+	r.routes = append(r.routes, routes...)
+
+	for _, imported := range imports {
+		path, ok := r.resolve(imported, fileName)
+		if !ok {
+			log.Debugf("Skipping import %s of %s: not found in the import paths", imported, fileName)
+			continue
+		}
+		err := r.addFile(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the path of an imported file, which is looked up in the
+// import paths, then in the directory of the importing file.
+func (r *protoResolver) resolve(imported, importingFile string) (string, bool) {
+	dirs := append([]string{}, r.importPaths...)
+	if importingFile != "-" {
+		dirs = append(dirs, filepath.Dir(importingFile))
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, imported)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// grpcRouteSpec returns the route of a gRPC method, given the fully qualified
+// name of its service.
+func grpcRouteSpec(service, method string) *sp.RouteSpec {
+	return &sp.RouteSpec{
+		Name: method,
+		Condition: &sp.RequestMatch{
+			Method:    http.MethodPost,
+			PathRegex: regexp.QuoteMeta(fmt.Sprintf("/%s/%s", service, method)),
 		},
-	}, nil
+	}
 }
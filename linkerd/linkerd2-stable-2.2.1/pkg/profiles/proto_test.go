@@ -1,12 +1,17 @@
 package profiles
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/emicklei/proto"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 func TestProtoToServiceProfile(t *testing.T) {
@@ -60,3 +65,83 @@ service VotingService {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+func TestRenderProto(t *testing.T) {
+	dir, err := ioutil.TempDir("", "protos")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"api/voting.proto": `syntax = "proto3";
+
+package emojivoto.v1;
+
+import "google/protobuf/empty.proto";
+import "results.proto";
+import "health/health.proto";
+
+service VotingService {
+	rpc VoteDoughnut (google.protobuf.Empty) returns (google.protobuf.Empty);
+	rpc WatchVotes (stream VoteRequest) returns (stream VotingResult) {
+		option deprecated = true;
+	}
+}`,
+		"api/results.proto": `syntax = "proto3";
+
+package emojivoto.v1.results;
+
+import "health/health.proto";
+
+service ResultsService {
+	rpc Results (ResultsRequest) returns (ResultsResponse);
+}`,
+		"include/health/health.proto": `syntax = "proto3";
+
+service Health {
+	rpc Check (HealthCheckRequest) returns (HealthCheckResponse);
+}`,
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(content), 0644)
+		}
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	err = RenderProto(
+		[]string{filepath.Join(dir, "api/voting.proto")},
+		[]string{filepath.Join(dir, "include")},
+		"myns", "mysvc", RetryConfig{}, &buf,
+	)
+	if err != nil {
+		t.Fatalf("Failed to render ServiceProfile: %v", err)
+	}
+
+	var actualServiceProfile sp.ServiceProfile
+	err = yaml.Unmarshal(buf.Bytes(), &actualServiceProfile)
+	if err != nil {
+		t.Fatalf("Failed to parse ServiceProfile: %v", err)
+	}
+
+	expectedServiceProfile := *newGRPCServiceProfile([]*sp.RouteSpec{
+		grpcRouteSpec("emojivoto.v1.VotingService", "VoteDoughnut"),
+		grpcRouteSpec("emojivoto.v1.VotingService", "WatchVotes"),
+		grpcRouteSpec("emojivoto.v1.results.ResultsService", "Results"),
+		grpcRouteSpec("Health", "Check"),
+	}, "myns", "mysvc")
+	if path := expectedServiceProfile.Spec.Routes[3].Condition.PathRegex; path != "/Health/Check" {
+		t.Fatalf("Expected the route of a service without a package to be [/Health/Check], got [%s]", path)
+	}
+
+	err = ServiceProfileYamlEquals(actualServiceProfile, expectedServiceProfile)
+	if err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+}
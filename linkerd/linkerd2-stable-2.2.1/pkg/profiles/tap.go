@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -19,10 +19,19 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const idPathParam = "{id}"
+
+var (
+	numericIDRegex = regexp.MustCompile(`^[0-9]+$`)
+	uuidRegex      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexIDRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8,}$`)
+	opaqueIDRegex  = regexp.MustCompile(`^[A-Za-z0-9_-]{16,}$`)
+)
+
 // RenderTapOutputProfile performs a tap on the desired resource and generates
 // a service profile with routes pre-populated from the tap data
 // Only inbound tap traffic is considered.
-func RenderTapOutputProfile(client pb.ApiClient, tapResource, namespace, name string, tapDuration time.Duration, routeLimit int, w io.Writer) error {
+func RenderTapOutputProfile(client pb.ApiClient, tapResource, namespace, name string, tapDuration time.Duration, routeLimit int, retries RetryConfig, w io.Writer) error {
 	requestParams := util.TapRequestParams{
 		Resource:  tapResource,
 		Namespace: namespace,
@@ -39,18 +48,13 @@ func RenderTapOutputProfile(client pb.ApiClient, tapResource, namespace, name st
 		return err
 	}
 
-	output, err := yaml.Marshal(profile)
-	if err != nil {
-		return fmt.Errorf("Error writing Service Profile: %s", err)
-	}
-	w.Write(output)
-	return nil
+	return writeProfile(profile, retries, w)
 }
 
 func tapToServiceProfile(client pb.ApiClient, tapReq *pb.TapByResourceRequest, namespace, name string, tapDuration time.Duration, routeLimit int) (sp.ServiceProfile, error) {
 	profile := sp.ServiceProfile{
 		ObjectMeta: meta_v1.ObjectMeta{
-			Name:      fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+			Name:      ProfileName(namespace, name),
 			Namespace: namespace,
 		},
 		TypeMeta: ServiceProfileMeta,
@@ -125,17 +129,47 @@ func getPathDataFromTap(event *pb.TapEvent) *sp.RouteSpec {
 
 	switch ev := event.GetHttp().GetEvent().(type) {
 	case *pb.TapEvent_Http_RequestInit_:
-		path := ev.RequestInit.GetPath()
+		path := consolidatePath(ev.RequestInit.GetPath())
 		if path == "/" {
 			return nil
 		}
 
 		return mkRouteSpec(
 			path,
-			pathToRegex(path), // for now, no path consolidation
+			pathToRegex(path),
 			ev.RequestInit.GetMethod().GetRegistered().String(),
 			nil)
 	default:
 		return nil
 	}
 }
+
+// consolidatePath strips the query of a request path and replaces its
+// segments that look like IDs with an "{id}" parameter, so that the requests
+// for different resources of the same kind, e.g. "/authors/1" and
+// "/authors/2", are grouped into a single "/authors/{id}" route.
+func consolidatePath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIDSegment(segment) {
+			segments[i] = idPathParam
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIDSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if numericIDRegex.MatchString(segment) || uuidRegex.MatchString(segment) {
+		return true
+	}
+	// hashes and opaque tokens always have a digit, unlike most words
+	return strings.IndexAny(segment, "0123456789") >= 0 &&
+		(hexIDRegex.MatchString(segment) || opaqueIDRegex.MatchString(segment))
+}
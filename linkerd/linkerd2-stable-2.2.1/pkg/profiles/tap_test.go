@@ -112,3 +112,28 @@ func TestTapToServiceProfile(t *testing.T) {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+func TestConsolidatePath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/my/path/hi", "/my/path/hi"},
+		{"/authors/1234", "/authors/{id}"},
+		{"/authors/1234/books/42?format=json", "/authors/{id}/books/{id}"},
+		{"/orders/123e4567-e89b-12d3-a456-426655440000", "/orders/{id}"},
+		{"/commits/2f8a3c9d1e", "/commits/{id}"},
+		{"/sessions/aGVsbG8gd29ybGQ1234", "/sessions/{id}"},
+		{"/v1/api/deadbeefcafe", "/v1/api/deadbeefcafe"},
+		{"/emojivoto.v1.VotingService/VoteFire", "/emojivoto.v1.VotingService/VoteFire"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			actual := consolidatePath(tc.path)
+			if actual != tc.expected {
+				t.Fatalf("Expected consolidated path [%s], got [%s]", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package profiles
 
 // Template provides the base template for the `linkerd profile --template` command.
-const Template = `### ServiceProfile for {{.ServiceName}}.{{.ServiceNamespace}} ###
+const Template = `### ServiceProfile for {{.ProfileName}} ###
 apiVersion: linkerd.io/v1alpha1
 kind: ServiceProfile
 metadata:
-  name: {{.ServiceName}}.{{.ServiceNamespace}}.{{.ClusterZone}}
+  name: {{.ProfileName}}
   namespace: {{.ServiceNamespace}}
 spec:
   # A service profile defines a list of routes.  Linkerd can aggregate metrics
@@ -42,7 +42,7 @@ spec:
     # A route may be marked as retryable.  This indicates that requests to this
     # route are always safe to retry and will cause the proxy to retry failed
     # requests on this route whenever possible.
-    # isRetryable: true
+    {{if .IsRetryable}}isRetryable: true{{else}}# isRetryable: true{{end}}
 
     # A route may optionally define a list of response classes which describe
     # how responses from this route will be classified.
@@ -73,14 +73,49 @@ spec:
       # successes or failures.
       isFailure: true
 
+    # The first response class whose condition matches a response applies.
+    # Responses that match none are failures if their status is 5XX, or if
+    # their grpc-status is not OK.  A class can, for instance, count the 404s
+    # of this route as successes:
+    # - condition:
+    #     status:
+    #       min: 404
+    #   isFailure: false
+
+    # gRPC responses can also be classified by their grpc-status, e.g. to count
+    # NOT_FOUND (5) responses as failures.  The proxy can't evaluate these
+    # conditions, so they are only taken into account by the route stats of
+    # 'linkerd routes', not by retries.
+    # - condition:
+    #     grpcStatus:
+    #       min: 5
+    #   isFailure: true
+
     # A route can define a request timeout.  Any requests to this route that
     # exceed the timeout will be canceled.  If unspecified, the default timeout
     # is '10s' (ten seconds).
-    # timeout: 250ms
+    {{if .Timeout}}timeout: {{.Timeout}}{{else}}# timeout: 250ms{{end}}
 
   # A service profile can also define a retry budget.  This specifies the
   # maximum total number of retries that should be sent to this service as a
   # ratio of the original request volume.
+{{- with .RetryBudget}}
+  retryBudget:
+    # The retryRatio is the maximum ratio of retries requests to original
+    # requests.  A retryRatio of 0.2 means that retries may add at most an
+    # additional 20% to the request load.
+    retryRatio: {{.RetryRatio}}
+
+    # This is an allowance of retries per second in addition to those allowed
+    # by the retryRatio.  This allows retries to be performed, when the request
+    # rate is very low.
+    minRetriesPerSecond: {{.MinRetriesPerSecond}}
+
+    # This duration indicates for how long requests should be considered for the
+    # purposes of calculating the retryRatio.  A higher value considers a larger
+    # window and therefore allows burstier retries.
+    ttl: {{.TTL}}
+{{- else}}
   # retryBudget:
   #   The retryRatio is the maximum ratio of retries requests to original
   #   requests.  A retryRatio of 0.2 means that retries may add at most an
@@ -96,4 +131,19 @@ spec:
   #   purposes of calculating the retryRatio.  A higher value considers a larger
   #   window and therefore allows burstier retries.
   #   ttl: 10s
+{{- end}}
+
+  # A service profile can override its routes and retry budget for some of its
+  # clients, selected by namespace and/or by the labels of their pods.  The
+  # first entry that selects a client applies, and fields that aren't set are
+  # taken from the rest of the service profile.
+  # perClient:
+  # - namespace: batch
+  #   podSelector:
+  #     matchLabels:
+  #       app: report-generator
+  #   retryBudget:
+  #     retryRatio: 0.5
+  #     minRetriesPerSecond: 10
+  #     ttl: 10s
 `
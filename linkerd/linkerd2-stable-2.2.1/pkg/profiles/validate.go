@@ -0,0 +1,244 @@
+package profiles
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	yamlErrLineRegex    = regexp.MustCompile(`line (\d+):`)
+	unknownFieldRegex   = regexp.MustCompile(`unknown field "([^"]+)"`)
+	fieldPathTokenRegex = regexp.MustCompile(`([^.\[\]]+)|\[(\d+)\]`)
+)
+
+// ManifestError is an error in a ServiceProfile manifest. Line is the line of
+// the manifest the error is about, or 0 if it isn't known.
+type ManifestError struct {
+	Line    int
+	Message string
+}
+
+func (e ManifestError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ValidateManifest validates the ServiceProfiles of a manifest, which may hold
+// several YAML documents, without access to a cluster. On top of the checks
+// of ValidateServiceProfile, it checks that the profiles are named after the
+// FQDN of a service in their namespace, or after an external authority.
+// Unlike Validate, it returns all the errors it finds, with their lines.
+func ValidateManifest(data []byte) []ManifestError {
+	errs := make([]ManifestError, 0)
+
+	lines := strings.Split(string(data), "\n")
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && strings.TrimRight(lines[i], " \t\r") != "---" {
+			continue
+		}
+		errs = append(errs, validateManifestDocument(lines[start:i], start)...)
+		start = i + 1
+	}
+
+	return errs
+}
+
+// validateManifestDocument validates a YAML document, given its lines and the
+// number of lines that precede it in the manifest.
+func validateManifestDocument(lines []string, offset int) []ManifestError {
+	doc := newYAMLDocument(lines)
+	if len(doc.lines) == 0 {
+		return nil
+	}
+	data := []byte(strings.Join(lines, "\n"))
+
+	var typeMeta meta_v1.TypeMeta
+	err := yaml.Unmarshal(data, &typeMeta)
+	if err != nil {
+		return []ManifestError{doc.yamlError(err, offset)}
+	}
+	if typeMeta.Kind != ServiceProfileMeta.Kind {
+		return []ManifestError{{
+			Line:    doc.lineOf("kind", offset),
+			Message: fmt.Sprintf("expected a %s, got kind \"%s\"", ServiceProfileMeta.Kind, typeMeta.Kind),
+		}}
+	}
+
+	var serviceProfile sp.ServiceProfile
+	err = yaml.UnmarshalStrict(data, &serviceProfile)
+	if err != nil {
+		return []ManifestError{doc.yamlError(err, offset)}
+	}
+
+	errs := make([]ManifestError, 0)
+	if err := validateProfileName(&serviceProfile); err != nil {
+		errs = append(errs, ManifestError{
+			Line:    doc.lineOf("metadata.name", offset),
+			Message: err.Error(),
+		})
+	}
+	for _, fieldErr := range validateServiceProfile(&serviceProfile) {
+		errs = append(errs, ManifestError{
+			Line:    doc.lineOf(fieldErr.path, offset),
+			Message: fieldErr.err.Error(),
+		})
+	}
+	return errs
+}
+
+// validateProfileName checks that a ServiceProfile is named after the FQDN of
+// a service in its namespace, e.g. "web.emojivoto.svc.cluster.local", or after
+// an external authority, e.g. "api.github.com".
+func validateProfileName(serviceProfile *sp.ServiceProfile) error {
+	parts := strings.Split(serviceProfile.Name, ".")
+	if len(parts) < 3 || parts[2] != "svc" {
+		if len(parts) < 2 || len(validation.IsDNS1123Subdomain(serviceProfile.Name)) != 0 {
+			return fmt.Errorf("ServiceProfile \"%s\" must be named after the FQDN of its service, e.g. \"web.emojivoto.svc.cluster.local\", or after an external authority, e.g. \"api.github.com\"", serviceProfile.Name)
+		}
+		return nil
+	}
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("ServiceProfile \"%s\" must be named after the FQDN of its service, e.g. \"web.emojivoto.svc.cluster.local\"", serviceProfile.Name)
+	}
+	if serviceProfile.Namespace != "" && serviceProfile.Namespace != parts[1] {
+		return fmt.Errorf("ServiceProfile \"%s\" must be in the namespace of its service, \"%s\", not \"%s\"", serviceProfile.Name, parts[1], serviceProfile.Namespace)
+	}
+	return nil
+}
+
+// yamlLine is a non-empty line of a YAML document, in block style.
+type yamlLine struct {
+	number int
+	// indent is the indentation of the content of the line, after the "- "
+	// that starts a list item.
+	indent int
+	// item is whether the line starts a list item, whose "-" is at itemIndent.
+	item       bool
+	itemIndent int
+	// key is the key of the mapping entry of the line, if any.
+	key string
+}
+
+type yamlDocument struct {
+	lines []yamlLine
+}
+
+func newYAMLDocument(lines []string) *yamlDocument {
+	doc := &yamlDocument{}
+	for i, text := range lines {
+		trimmed := strings.TrimLeft(text, " ")
+		content := strings.TrimRight(trimmed, " \t\r")
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+
+		line := yamlLine{number: i + 1, indent: len(text) - len(trimmed)}
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			line.item = true
+			line.itemIndent = line.indent
+			rest := strings.TrimLeft(content[1:], " ")
+			line.indent += len(content) - len(rest)
+			content = rest
+		}
+		if i := strings.Index(content, ":"); i > 0 && (i == len(content)-1 || content[i+1] == ' ') {
+			line.key = strings.Trim(content[:i], `"'`)
+		}
+		doc.lines = append(doc.lines, line)
+	}
+	return doc
+}
+
+// lineOf returns the line of the manifest of the field at the given path, e.g.
+// "spec.routes[1].condition", or of its closest parent that can be found,
+// given the number of lines that precede the document in the manifest.
+func (d *yamlDocument) lineOf(path string, offset int) int {
+	lo, hi := 0, len(d.lines)
+	found := 0
+	for _, token := range fieldPathTokenRegex.FindAllStringSubmatch(path, -1) {
+		line := -1
+		if token[2] != "" {
+			index, _ := strconv.Atoi(token[2])
+			line, lo, hi = d.findItem(lo, hi, index)
+		} else {
+			line, lo, hi = d.findKey(lo, hi, token[1])
+		}
+		if line < 0 {
+			break
+		}
+		found = d.lines[line].number + offset
+	}
+	return found
+}
+
+// findKey returns the line of a key of the mapping held by the given range of
+// lines, and the range of lines of its value, or -1 if it isn't found.
+func (d *yamlDocument) findKey(lo, hi int, key string) (int, int, int) {
+	if lo >= hi {
+		return -1, hi, hi
+	}
+	indent := d.lines[lo].indent
+	for i := lo; i < hi; i++ {
+		line := d.lines[i]
+		if line.indent != indent || line.key != key {
+			continue
+		}
+		end := i + 1
+		for end < hi && d.lines[end].indent > indent && !(d.lines[end].item && d.lines[end].itemIndent < indent) {
+			end++
+		}
+		return i, i + 1, end
+	}
+	return -1, hi, hi
+}
+
+// findItem returns the first line of an item of the list held by the given
+// range of lines, given its index, and the range of lines of the item, or -1
+// if it isn't found.
+func (d *yamlDocument) findItem(lo, hi int, index int) (int, int, int) {
+	if lo >= hi || !d.lines[lo].item {
+		return -1, hi, hi
+	}
+
+	itemIndent := d.lines[lo].itemIndent
+	items := make([]int, 0)
+	for i := lo; i < hi; i++ {
+		if d.lines[i].item && d.lines[i].itemIndent == itemIndent {
+			items = append(items, i)
+		}
+	}
+	if index >= len(items) {
+		return -1, hi, hi
+	}
+	if index+1 < len(items) {
+		return items[index], items[index], items[index+1]
+	}
+	return items[index], items[index], hi
+}
+
+// yamlError returns the error of a document that couldn't be parsed, at the
+// line reported by the parser or of the unknown field it found.
+func (d *yamlDocument) yamlError(err error, offset int) ManifestError {
+	msg := err.Error()
+	if match := yamlErrLineRegex.FindStringSubmatch(msg); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return ManifestError{Line: line + offset, Message: msg}
+	}
+	if match := unknownFieldRegex.FindStringSubmatch(msg); match != nil {
+		for _, line := range d.lines {
+			if line.key == match[1] {
+				return ManifestError{Line: line.number + offset, Message: msg}
+			}
+		}
+	}
+	return ManifestError{Message: msg}
+}
@@ -0,0 +1,159 @@
+package profiles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateManifest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		manifest string
+		errLines []int
+		errMsgs  []string
+	}{
+		{
+			name: "valid profile",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+  namespace: emojivoto
+spec:
+  routes:
+  - name: GET /api/list
+    condition:
+      method: GET
+      pathRegex: /api/list`,
+			errLines: []int{},
+		},
+		{
+			name: "invalid routes",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+  namespace: emojivoto
+spec:
+  routes:
+  - name: GET /api/list
+    condition:
+      method: GET
+      pathRegex: /api/list
+  - name: GET /api/vote
+    condition:
+      pathRegex: /api/vote(
+  - name: GET /api/list
+    condition:
+      method: GET
+      pathRegex: /api/list
+  - name: POST /api/vote
+    condition:
+      method: POST
+    responseClasses:
+    - condition:
+        status:
+          min: 600`,
+			errLines: []int{12, 15, 19},
+			errMsgs:  []string{"error parsing regexp", `"GET /api/list"`, "Range minimum"},
+		},
+		{
+			name: "profile not named after a service FQDN",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web
+  namespace: emojivoto
+spec:
+  routes:
+  - name: GET /api/list
+    condition:
+      method: GET`,
+			errLines: []int{4},
+			errMsgs:  []string{"FQDN"},
+		},
+		{
+			name: "external authority profile",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: api.github.com
+  namespace: linkerd
+spec:
+  routes:
+  - name: GET /repos
+    condition:
+      method: GET`,
+			errLines: []int{},
+		},
+		{
+			name: "profile in another namespace than its service",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+  namespace: default
+spec:
+  routes:
+  - name: GET /api/list
+    condition:
+      method: GET`,
+			errLines: []int{4},
+			errMsgs:  []string{"namespace"},
+		},
+		{
+			name: "unknown field in the second document",
+			manifest: `apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: web.emojivoto.svc.cluster.local
+spec:
+  routes:
+  - name: GET /api/list
+    condition:
+      method: GET
+---
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  name: voting.emojivoto.svc.cluster.local
+spec:
+  routes:
+  - name: GET /api/vote
+    condition:
+      method: GET
+    retries: 3`,
+			errLines: []int{20},
+			errMsgs:  []string{`unknown field "retries"`},
+		},
+		{
+			name: "not a ServiceProfile",
+			manifest: `apiVersion: v1
+kind: Service
+metadata:
+  name: web`,
+			errLines: []int{2},
+			errMsgs:  []string{"expected a ServiceProfile"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateManifest([]byte(tc.manifest))
+
+			lines := make([]int, len(errs))
+			for i, err := range errs {
+				lines[i] = err.Line
+			}
+			if !reflect.DeepEqual(lines, tc.errLines) {
+				t.Fatalf("Expected errors on lines %v, got %v", tc.errLines, errs)
+			}
+			for i, msg := range tc.errMsgs {
+				if !strings.Contains(errs[i].Message, msg) {
+					t.Fatalf("Expected error [%s] to contain [%s]", errs[i].Message, msg)
+				}
+			}
+		})
+	}
+}
@@ -1,19 +1,32 @@
 package prometheus
 
 import (
+	"context"
 	"net/http"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/linkerd/linkerd2/pkg/downstream"
+	"github.com/linkerd/linkerd2/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
-// NewGrpcServer returns a grpc server pre-configured with prometheus interceptors
+// NewGrpcServer returns a grpc server pre-configured with prometheus
+// interceptors, whose requests are traced and have the request ID of their
+// metadata. It permits the keepalive pings of the controller clients, which
+// it would otherwise answer with a GOAWAY.
 func NewGrpcServer() *grpc.Server {
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
-		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
+		grpc.UnaryInterceptor(unaryServerInterceptor),
+		grpc.StreamInterceptor(streamServerInterceptor),
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             downstream.MinKeepaliveTime,
+			PermitWithoutStream: true,
+		}),
 	)
 
 	grpc_prometheus.EnableHandlingTimeHistogram()
@@ -21,6 +34,22 @@ func NewGrpcServer() *grpc.Server {
 	return server
 }
 
+// unaryServerInterceptor chains the request ID and prometheus interceptors, as
+// a server only has one.
+func unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return util.RequestIDUnaryServerInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return grpc_prometheus.UnaryServerInterceptor(ctx, req, info, handler)
+	})
+}
+
+// streamServerInterceptor chains the request ID and prometheus interceptors, as
+// a server only has one.
+func streamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return util.RequestIDStreamServerInterceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return grpc_prometheus.StreamServerInterceptor(srv, stream, info, handler)
+	})
+}
+
 // RequestDurationBucketsSeconds represents latency buckets to record (seconds)
 var RequestDurationBucketsSeconds = append(append(append(append(
 	prometheus.LinearBuckets(0.01, 0.01, 5),
@@ -0,0 +1,202 @@
+// Package protohttp provides helpers for sending and receiving length-prefixed
+// protobuf messages over HTTP, as used by the public API and the tap server.
+package protohttp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ErrorHeader is set on responses whose body contains an ApiError.
+	ErrorHeader                = "linkerd-error"
+	defaultHTTPErrorStatusCode = http.StatusInternalServerError
+	contentTypeHeader          = "Content-Type"
+	protobufContentType        = "application/octet-stream"
+	numBytesForMessageLength   = 4
+)
+
+// HTTPError is an error which indicates the HTTP response code to be used.
+type HTTPError struct {
+	Code         int
+	WrappedError error
+}
+
+// FlushableResponseWriter wraps a ResponseWriter for use in streaming
+// responses.
+type FlushableResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error, status Code [%d], wrapped error is: %v", e.Code, e.WrappedError)
+}
+
+// HTTPRequestToProto converts an HTTP Request to a protobuf request.
+func HTTPRequestToProto(req *http.Request, protoRequestOut proto.Message) error {
+	bytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return HTTPError{
+			Code:         http.StatusBadRequest,
+			WrappedError: err,
+		}
+	}
+
+	err = proto.Unmarshal(bytes, protoRequestOut)
+	if err != nil {
+		return HTTPError{
+			Code:         http.StatusBadRequest,
+			WrappedError: err,
+		}
+	}
+
+	return nil
+}
+
+// WriteErrorToHTTPResponse writes a protobuf-encoded error to an HTTP
+// Response.
+func WriteErrorToHTTPResponse(w http.ResponseWriter, errorObtained error) {
+	statusCode := defaultHTTPErrorStatusCode
+	errorToReturn := errorObtained
+
+	if httpErr, ok := errorObtained.(HTTPError); ok {
+		statusCode = httpErr.Code
+		errorToReturn = httpErr.WrappedError
+	}
+
+	w.Header().Set(ErrorHeader, http.StatusText(statusCode))
+
+	errorMessageToReturn := errorToReturn.Error()
+	if grpcError, ok := status.FromError(errorObtained); ok {
+		errorMessageToReturn = grpcError.Message()
+	}
+
+	errorAsProto := &pb.ApiError{Error: errorMessageToReturn}
+
+	err := WriteProtoToHTTPResponse(w, errorAsProto)
+	if err != nil {
+		log.Errorf("Error writing error to http response: %v", err)
+		w.Header().Set(ErrorHeader, err.Error())
+	}
+}
+
+// WriteProtoToHTTPResponse writes a protobuf-encoded message to an HTTP
+// Response.
+func WriteProtoToHTTPResponse(w http.ResponseWriter, msg proto.Message) error {
+	w.Header().Set(contentTypeHeader, protobufContentType)
+	marshalledProtobufMessage, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	fullPayload, err := SerializeAsPayload(marshalledProtobufMessage)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(fullPayload)
+	return err
+}
+
+// NewStreamingWriter takes a ResponseWriter and returns it wrapped in a
+// FlushableResponseWriter.
+func NewStreamingWriter(w http.ResponseWriter) (FlushableResponseWriter, error) {
+	flushableWriter, ok := w.(FlushableResponseWriter)
+	if !ok {
+		return nil, fmt.Errorf("streaming not supported by this writer")
+	}
+
+	flushableWriter.Header().Set("Connection", "keep-alive")
+	flushableWriter.Header().Set("Transfer-Encoding", "chunked")
+	return flushableWriter, nil
+}
+
+// SerializeAsPayload prefixes a serialized protobuf message with its length.
+func SerializeAsPayload(messageContentsInBytes []byte) ([]byte, error) {
+	lengthOfThePayload := uint32(len(messageContentsInBytes))
+
+	messageLengthInBytes := make([]byte, numBytesForMessageLength)
+	binary.LittleEndian.PutUint32(messageLengthInBytes, lengthOfThePayload)
+
+	return append(messageLengthInBytes, messageContentsInBytes...), nil
+}
+
+// DeserializePayloadFromReader reads a single length-prefixed message from a
+// reader.
+func DeserializePayloadFromReader(reader *bufio.Reader) ([]byte, error) {
+	messageLengthAsBytes := make([]byte, numBytesForMessageLength)
+	_, err := io.ReadFull(reader, messageLengthAsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading message length: %v", err)
+	}
+	messageLength := int(binary.LittleEndian.Uint32(messageLengthAsBytes))
+
+	messageContentsAsBytes := make([]byte, messageLength)
+	_, err = io.ReadFull(reader, messageContentsAsBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading bytes from message: %v", err)
+	}
+
+	return messageContentsAsBytes, nil
+}
+
+// CheckIfResponseHasError checks an HTTP response for errors and returns the
+// error message sent by the server, if any, along with the ID of the request
+// when the server returned one.
+func CheckIfResponseHasError(rsp *http.Response) error {
+	errorMsg := rsp.Header.Get(ErrorHeader)
+
+	if errorMsg != "" {
+		reader := bufio.NewReader(rsp.Body)
+		var apiError pb.ApiError
+
+		err := FromByteStreamToProtocolBuffers(reader, &apiError)
+		if err != nil {
+			return fmt.Errorf("Response has %s header [%s], but response body didn't contain protobuf error: %v", ErrorHeader, errorMsg, err)
+		}
+
+		return withRequestID(rsp, errors.New(apiError.Error))
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		return withRequestID(rsp, fmt.Errorf("Unexpected API response: %s", rsp.Status))
+	}
+
+	return nil
+}
+
+// withRequestID adds the request ID returned in the response, if any, to err,
+// so that the error can be matched with the logs of the control plane.
+func withRequestID(rsp *http.Response, err error) error {
+	id := rsp.Header.Get(util.RequestIDHeader)
+	if id == "" {
+		return err
+	}
+	return fmt.Errorf("%s (request ID: %s)", err, id)
+}
+
+// FromByteStreamToProtocolBuffers converts a byte stream to a protobuf message.
+func FromByteStreamToProtocolBuffers(byteStreamContainingMessage *bufio.Reader, out proto.Message) error {
+	messageAsBytes, err := DeserializePayloadFromReader(byteStreamContainingMessage)
+	if err != nil {
+		return fmt.Errorf("error reading byte stream header: %v", err)
+	}
+
+	err = proto.Unmarshal(messageAsBytes, out)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling array of [%d] bytes error: %v", len(messageAsBytes), err)
+	}
+
+	return nil
+}
@@ -1,4 +1,4 @@
-package public
+package protohttp
 
 import (
 	"bufio"
@@ -76,7 +76,7 @@ func TestHttpRequestToProto(t *testing.T) {
 		}
 
 		var actualProtoMessage pb.Pod
-		err = httpRequestToProto(req, &actualProtoMessage)
+		err = HTTPRequestToProto(req, &actualProtoMessage)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -94,18 +94,18 @@ func TestHttpRequestToProto(t *testing.T) {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		err = httpRequestToProto(req, &actualProtoMessage)
+		err = HTTPRequestToProto(req, &actualProtoMessage)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
 
-		if httpErr, ok := err.(httpError); ok {
+		if httpErr, ok := err.(HTTPError); ok {
 			expectedStatusCode := http.StatusBadRequest
 			if httpErr.Code != expectedStatusCode || httpErr.WrappedError == nil {
 				t.Fatalf("Expected error status to be [%d] and contain wrapper error, got status [%d] and error [%v]", expectedStatusCode, httpErr.Code, httpErr.WrappedError)
 			}
 		} else {
-			t.Fatalf("Expected error to be httpError, got: %v", err)
+			t.Fatalf("Expected error to be HTTPError, got: %v", err)
 		}
 	})
 }
@@ -117,16 +117,16 @@ func TestWriteErrorToHttpResponse(t *testing.T) {
 		responseWriter := newStubResponseWriter()
 		genericError := errors.New("expected generic error")
 
-		writeErrorToHTTPResponse(responseWriter, genericError)
+		WriteErrorToHTTPResponse(responseWriter, genericError)
 
 		assertResponseHasProtobufContentType(t, responseWriter)
 
-		actualErrorStatusCode := responseWriter.headers.Get(errorHeader)
+		actualErrorStatusCode := responseWriter.headers.Get(ErrorHeader)
 		if actualErrorStatusCode != http.StatusText(expectedErrorStatusCode) {
 			t.Fatalf("Expecting response to have status code [%d], got [%s]", expectedErrorStatusCode, actualErrorStatusCode)
 		}
 
-		payloadRead, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
+		payloadRead, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -146,21 +146,21 @@ func TestWriteErrorToHttpResponse(t *testing.T) {
 	t.Run("Writes http specific error correctly to response", func(t *testing.T) {
 		expectedErrorStatusCode := http.StatusBadGateway
 		responseWriter := newStubResponseWriter()
-		httpError := httpError{
+		httpError := HTTPError{
 			WrappedError: errors.New("expected to be wrapped"),
 			Code:         http.StatusBadGateway,
 		}
 
-		writeErrorToHTTPResponse(responseWriter, httpError)
+		WriteErrorToHTTPResponse(responseWriter, httpError)
 
 		assertResponseHasProtobufContentType(t, responseWriter)
 
-		actualErrorStatusCode := responseWriter.headers.Get(errorHeader)
+		actualErrorStatusCode := responseWriter.headers.Get(ErrorHeader)
 		if actualErrorStatusCode != http.StatusText(expectedErrorStatusCode) {
 			t.Fatalf("Expecting response to have status code [%d], got [%s]", expectedErrorStatusCode, actualErrorStatusCode)
 		}
 
-		payloadRead, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
+		payloadRead, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -184,16 +184,16 @@ func TestWriteErrorToHttpResponse(t *testing.T) {
 		expectedErrorMessage := "error message"
 		grpcError := status.Errorf(codes.AlreadyExists, expectedErrorMessage)
 
-		writeErrorToHTTPResponse(responseWriter, grpcError)
+		WriteErrorToHTTPResponse(responseWriter, grpcError)
 
 		assertResponseHasProtobufContentType(t, responseWriter)
 
-		actualErrorStatusCode := responseWriter.headers.Get(errorHeader)
+		actualErrorStatusCode := responseWriter.headers.Get(ErrorHeader)
 		if actualErrorStatusCode != http.StatusText(expectedErrorStatusCode) {
 			t.Fatalf("Expecting response to have status code [%d], got [%s]", expectedErrorStatusCode, actualErrorStatusCode)
 		}
 
-		payloadRead, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
+		payloadRead, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -220,14 +220,14 @@ func TestWriteProtoToHttpResponse(t *testing.T) {
 		}
 
 		responseWriter := newStubResponseWriter()
-		err := writeProtoToHTTPResponse(responseWriter, &expectedMessage)
+		err := WriteProtoToHTTPResponse(responseWriter, &expectedMessage)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
 		assertResponseHasProtobufContentType(t, responseWriter)
 
-		payloadRead, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
+		payloadRead, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(responseWriter.body.Bytes())))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -248,14 +248,14 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 	t.Run("Can read message correctly based on payload size correct payload size to message", func(t *testing.T) {
 		expectedMessage := "this is the message"
 
-		messageWithSize, err := serializeAsPayload([]byte(expectedMessage))
+		messageWithSize, err := SerializeAsPayload([]byte(expectedMessage))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
 		messageWithSomeNoise := append(messageWithSize, []byte("this is noise and should not be read")...)
 
-		actualMessage, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(messageWithSomeNoise)))
+		actualMessage, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(messageWithSomeNoise)))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -276,12 +276,12 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 			expectedMessage2 = expectedMessage2 + fmt.Sprintf("tum (%d), ", i)
 		}
 
-		messageWithSize1, err := serializeAsPayload([]byte(expectedMessage1))
+		messageWithSize1, err := SerializeAsPayload([]byte(expectedMessage1))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		messageWithSize2, err := serializeAsPayload([]byte(expectedMessage2))
+		messageWithSize2, err := SerializeAsPayload([]byte(expectedMessage2))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -289,12 +289,12 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 		streamWithManyMessages := append(messageWithSize1, messageWithSize2...)
 		reader := bufio.NewReader(bytes.NewReader(streamWithManyMessages))
 
-		actualMessage1, err := deserializePayloadFromReader(reader)
+		actualMessage1, err := DeserializePayloadFromReader(reader)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		actualMessage2, err := deserializePayloadFromReader(reader)
+		actualMessage2, err := DeserializePayloadFromReader(reader)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -320,7 +320,7 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		serialized, err := serializeAsPayload(expectedReadArray)
+		serialized, err := SerializeAsPayload(expectedReadArray)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -330,7 +330,7 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		actualReadArray, err := deserializePayloadFromReader(reader)
+		actualReadArray, err := DeserializePayloadFromReader(reader)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -369,12 +369,12 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 			t.Fatalf("Test needs data larger than [%d] bytes, currently only [%d] bytes", goDefaultChunkSize, lengthOfInputData)
 		}
 
-		payload, err := serializeAsPayload(expectedMessageAsBytes)
+		payload, err := SerializeAsPayload(expectedMessageAsBytes)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		actualMessage, err := deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(payload)))
+		actualMessage, err := DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(payload)))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -387,13 +387,13 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 	t.Run("Returns error when message has fewer bytes than declared message size", func(t *testing.T) {
 		expectedMessage := "this is the message"
 
-		messageWithSize, err := serializeAsPayload([]byte(expectedMessage))
+		messageWithSize, err := SerializeAsPayload([]byte(expectedMessage))
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
 		messageMissingOneCharacter := messageWithSize[:len(expectedMessage)-1]
-		_, err = deserializePayloadFromReader(bufio.NewReader(bytes.NewReader(messageMissingOneCharacter)))
+		_, err = DeserializePayloadFromReader(bufio.NewReader(bytes.NewReader(messageMissingOneCharacter)))
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
@@ -403,7 +403,7 @@ func TestDeserializePayloadFromReader(t *testing.T) {
 func TestNewStreamingWriter(t *testing.T) {
 	t.Run("Returns a streaming writer if the ResponseWriter is compatible with streaming", func(t *testing.T) {
 		rawWriter := newStubResponseWriter()
-		flushableWriter, err := newStreamingWriter(rawWriter)
+		flushableWriter, err := NewStreamingWriter(rawWriter)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -428,7 +428,7 @@ func TestNewStreamingWriter(t *testing.T) {
 	})
 
 	t.Run("Returns an error if writer doesnt support streaming", func(t *testing.T) {
-		_, err := newStreamingWriter(&nonStreamingResponseWriter{})
+		_, err := NewStreamingWriter(&nonStreamingResponseWriter{})
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
@@ -441,7 +441,7 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			Header:     make(http.Header),
 			StatusCode: http.StatusOK,
 		}
-		err := checkIfResponseHasError(response)
+		err := CheckIfResponseHasError(response)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -454,7 +454,7 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		message, err := serializeAsPayload(protoInBytes)
+		message, err := SerializeAsPayload(protoInBytes)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -464,9 +464,9 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			Body:       ioutil.NopCloser(bytes.NewReader(message)),
 			StatusCode: http.StatusInternalServerError,
 		}
-		response.Header.Set(errorHeader, "error")
+		response.Header.Set(ErrorHeader, "error")
 
-		err = checkIfResponseHasError(response)
+		err = CheckIfResponseHasError(response)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
@@ -477,13 +477,44 @@ func TestCheckIfResponseHasError(t *testing.T) {
 		}
 	})
 
+	t.Run("returns error with the request ID if the response contains one", func(t *testing.T) {
+		protoInBytes, err := proto.Marshal(&pb.ApiError{Error: "expected error message"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		message, err := SerializeAsPayload(protoInBytes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		response := &http.Response{
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(bytes.NewReader(message)),
+			StatusCode: http.StatusInternalServerError,
+		}
+		response.Header.Set(ErrorHeader, "error")
+		response.Header.Set("X-Request-Id", "1234abcd")
+
+		err = CheckIfResponseHasError(response)
+		if err == nil {
+			t.Fatalf("Expecting error, got nothing")
+		}
+
+		expectedErrorMessage := "expected error message (request ID: 1234abcd)"
+		actualErrorMessage := err.Error()
+		if actualErrorMessage != expectedErrorMessage {
+			t.Fatalf("Expected error message to be [%s], but it was [%s]", expectedErrorMessage, actualErrorMessage)
+		}
+	})
+
 	t.Run("returns error if response contains linkerd-error header but body isn't error message", func(t *testing.T) {
 		protoInBytes, err := proto.Marshal(&pb.VersionInfo{ReleaseVersion: "0.0.1"})
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
-		message, err := serializeAsPayload(protoInBytes)
+		message, err := SerializeAsPayload(protoInBytes)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -493,9 +524,9 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			Body:       ioutil.NopCloser(bytes.NewReader(message)),
 			StatusCode: http.StatusInternalServerError,
 		}
-		response.Header.Set(errorHeader, "error")
+		response.Header.Set(ErrorHeader, "error")
 
-		err = checkIfResponseHasError(response)
+		err = CheckIfResponseHasError(response)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
@@ -507,7 +538,7 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			Status:     "503 Service Unavailable",
 		}
 
-		err := checkIfResponseHasError(response)
+		err := CheckIfResponseHasError(response)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
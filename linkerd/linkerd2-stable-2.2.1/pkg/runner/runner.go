@@ -0,0 +1,300 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/admin"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// DefaultShutdownTimeout is the default time given to the servers of a
+// Runner to drain their connections on shutdown.
+const DefaultShutdownTimeout = 30 * time.Second
+
+type server struct {
+	name     string
+	serve    func() error
+	shutdown func(ctx context.Context) error
+}
+
+// Runner coordinates the lifecycle of the servers of a controller:
+//
+//  1. the admin server starts, with /ready failing;
+//  2. the sync functions run, e.g. to wait for the informer caches;
+//  3. the servers and the background functions start, and /ready succeeds;
+//  4. on SIGINT or SIGTERM, /ready fails again and the background functions
+//     are stopped;
+//  5. the servers drain their connections within ShutdownTimeout, in the
+//     reverse order of their registration, and the admin server stops last.
+type Runner struct {
+	// ShutdownTimeout is the time given to the servers to drain their
+	// connections, after which they're closed. Defaults to
+	// DefaultShutdownTimeout if it's not positive.
+	ShutdownTimeout time.Duration
+
+	adminAddr     string
+	adminHandlers map[string]http.Handler
+	readyChecks   []func() error
+	syncs         []func()
+	background    []func(stop <-chan struct{})
+	servers       []server
+
+	ready int32
+}
+
+// New returns a Runner that serves the admin endpoints on the given address.
+func New(adminAddr string) *Runner {
+	return &Runner{
+		adminAddr:     adminAddr,
+		adminHandlers: map[string]http.Handler{},
+	}
+}
+
+// AdminHandler adds a component-specific handler to the admin server.
+func (r *Runner) AdminHandler(path string, handler http.Handler) {
+	r.adminHandlers[path] = handler
+}
+
+// ReadyCheck adds a check to the /ready admin endpoint, which fails while
+// the check returns an error.
+func (r *Runner) ReadyCheck(check func() error) {
+	r.readyChecks = append(r.readyChecks, check)
+}
+
+// PeriodicReadyCheck is like ReadyCheck, but runs the check in the background
+// at the given interval once synced, for checks that are too slow to run on
+// every probe, e.g. ones that make requests to other services. The /ready
+// endpoint reports the result of the last check.
+func (r *Runner) PeriodicReadyCheck(interval time.Duration, check func() error) {
+	var mu sync.Mutex
+	lastErr := errors.New("not checked yet")
+
+	r.Go(func(stop <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := check()
+			mu.Lock()
+			lastErr = err
+			mu.Unlock()
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	})
+	r.ReadyCheck(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return lastErr
+	})
+}
+
+// GRPCConnCheck returns a ready check that fails while the given connection
+// to a downstream gRPC service isn't usable, e.g. while it's reconnecting.
+func GRPCConnCheck(name string, conn *grpc.ClientConn) func() error {
+	return func() error {
+		switch state := conn.GetState(); state {
+		case connectivity.Ready, connectivity.Idle:
+			return nil
+		default:
+			return fmt.Errorf("connection to %s is %s", name, state)
+		}
+	}
+}
+
+// OnSync adds a function that must return before the servers start, e.g.
+// one that blocks until the informer caches are synced.
+func (r *Runner) OnSync(waitForSync func()) {
+	r.syncs = append(r.syncs, waitForSync)
+}
+
+// Go adds a function that runs in the background once synced, until the
+// given channel is closed on shutdown. The servers only drain once all the
+// background functions have returned.
+func (r *Runner) Go(run func(stop <-chan struct{})) {
+	r.background = append(r.background, run)
+}
+
+// AddGRPCServer adds a gRPC server, served on the given listener.
+func (r *Runner) AddGRPCServer(name string, s *grpc.Server, lis net.Listener) {
+	r.servers = append(r.servers, server{
+		name: name,
+		serve: func() error {
+			return s.Serve(lis)
+		},
+		shutdown: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				s.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				s.Stop()
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// AddHTTPServer adds an HTTP server, served on the given listener, or on the
+// address of the server if it's nil.
+func (r *Runner) AddHTTPServer(name string, s *http.Server, lis net.Listener) {
+	r.addHTTPServer(name, s, lis, s.Serve)
+}
+
+// AddHTTPSServer is like AddHTTPServer, but serves TLS with the certificates
+// of the TLS configuration of the server.
+func (r *Runner) AddHTTPSServer(name string, s *http.Server, lis net.Listener) {
+	r.addHTTPServer(name, s, lis, func(lis net.Listener) error {
+		return s.ServeTLS(lis, "", "")
+	})
+}
+
+func (r *Runner) addHTTPServer(name string, s *http.Server, lis net.Listener, serve func(net.Listener) error) {
+	r.servers = append(r.servers, server{
+		name: name,
+		serve: func() error {
+			if lis == nil {
+				var err error
+				if lis, err = net.Listen("tcp", s.Addr); err != nil {
+					return err
+				}
+			}
+			return serve(lis)
+		},
+		shutdown: func(ctx context.Context) error {
+			if err := s.Shutdown(ctx); err != nil {
+				s.Close()
+				return err
+			}
+			return nil
+		},
+	})
+}
+
+// Run runs the controller until it receives SIGINT or SIGTERM, and returns
+// once it's shut down.
+func (r *Runner) Run() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	stop := make(chan struct{})
+	go func() {
+		sig := <-signals
+		log.Infof("received %s", sig)
+		close(stop)
+	}()
+
+	r.run(stop)
+}
+
+func (r *Runner) run(stop <-chan struct{}) {
+	adminHandlers := map[string]http.Handler{}
+	for path, handler := range r.adminHandlers {
+		adminHandlers[path] = handler
+	}
+	adminHandlers["/ready"] = admin.ReadyHandler(r.checkReady)
+	adminServer, err := admin.NewServer(r.adminAddr, adminHandlers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		log.Infof("starting admin server on %s", r.adminAddr)
+		if err := admin.ListenAndServe(adminServer); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	for _, waitForSync := range r.syncs {
+		waitForSync()
+	}
+
+	backgroundStop := make(chan struct{})
+	var background sync.WaitGroup
+	for _, run := range r.background {
+		background.Add(1)
+		go func(run func(<-chan struct{})) {
+			defer background.Done()
+			run(backgroundStop)
+		}(run)
+	}
+
+	for _, s := range r.servers {
+		go func(s server) {
+			log.Infof("starting %s", s.name)
+			if err := s.serve(); err != nil && err != http.ErrServerClosed && err != grpc.ErrServerStopped {
+				log.Fatalf("%s failed: %s", s.name, err)
+			}
+		}(s)
+	}
+
+	atomic.StoreInt32(&r.ready, 1)
+
+	<-stop
+
+	atomic.StoreInt32(&r.ready, 0)
+
+	timeout := r.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	close(backgroundStop)
+	backgroundDone := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(backgroundDone)
+	}()
+	select {
+	case <-backgroundDone:
+	case <-ctx.Done():
+		log.Warn("background functions didn't stop before the shutdown timeout")
+	}
+
+	for i := len(r.servers) - 1; i >= 0; i-- {
+		s := r.servers[i]
+		log.Infof("shutting down %s", s.name)
+		if err := s.shutdown(ctx); err != nil {
+			log.Errorf("failed to drain %s: %s", s.name, err)
+		}
+	}
+
+	log.Infof("shutting down admin server on %s", r.adminAddr)
+	if err := adminServer.Shutdown(ctx); err != nil {
+		adminServer.Close()
+	}
+}
+
+func (r *Runner) checkReady() error {
+	if atomic.LoadInt32(&r.ready) == 0 {
+		return errors.New("not serving")
+	}
+	for _, check := range r.readyChecks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
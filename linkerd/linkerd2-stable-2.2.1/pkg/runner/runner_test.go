@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var mu sync.Mutex
+	url := "http://" + lis.Addr().String()
+
+	synced := make(chan struct{})
+	checkErr := errors.New("unhealthy")
+	var healthy int32
+
+	r := New("127.0.0.1:0")
+	r.OnSync(func() { <-synced })
+	r.ReadyCheck(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if healthy == 0 {
+			return checkErr
+		}
+		return nil
+	})
+	var servedOnStop error
+	r.Go(func(stop <-chan struct{}) {
+		<-stop
+		// the servers only drain once the background functions return
+		rsp, err := http.Get(url)
+		if err == nil {
+			rsp.Body.Close()
+		}
+		servedOnStop = err
+	})
+	r.AddHTTPServer("test server", &http.Server{Handler: http.NotFoundHandler()}, lis)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		r.run(stop)
+		close(done)
+	}()
+
+	if err := r.checkReady(); err == nil {
+		t.Fatal("Expected not to be ready before syncing")
+	}
+
+	close(synced)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.checkReady() != checkErr {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the ready checks to run once synced, got: %v", r.checkReady())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	healthy = 1
+	mu.Unlock()
+	if err := r.checkReady(); err != nil {
+		t.Fatalf("Expected to be ready, got: %s", err)
+	}
+
+	close(stop)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the runner to shut down")
+	}
+
+	if err := r.checkReady(); err == nil {
+		t.Fatal("Expected not to be ready once shut down")
+	}
+
+	if servedOnStop != nil {
+		t.Fatalf("Expected the server to serve until the background functions stopped, got: %s", servedOnStop)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("Expected the server to be shut down")
+	}
+}
@@ -47,6 +47,10 @@ type CA struct {
 	// For now we do not attempt to meet CABForum requirements (e.g. regarding
 	// randomness).
 	nextSerialNumber uint64
+
+	// notAfter, if set, is the expiry of the issuer certificate, past which
+	// the issued certificates aren't valid either.
+	notAfter time.Time
 }
 
 // CertificateAndPrivateKey encapsulates a certificate / private key pair.
@@ -158,12 +162,16 @@ func (ca *CA) createTemplate(publicKey *ecdsa.PublicKey) x509.Certificate {
 	ca.nextSerialNumber++
 
 	notBefore := time.Now()
+	notAfter := notBefore.Add(ca.validity).Add(ca.clockSkewAllocance)
+	if !ca.notAfter.IsZero() && notAfter.After(ca.notAfter) {
+		notAfter = ca.notAfter
+	}
 
 	return x509.Certificate{
 		SerialNumber:       serialNumber,
 		SignatureAlgorithm: SignatureAlgorithm,
 		NotBefore:          notBefore.Add(-ca.clockSkewAllocance),
-		NotAfter:           notBefore.Add(ca.validity).Add(ca.clockSkewAllocance),
+		NotAfter:           notAfter,
 		PublicKey:          publicKey,
 	}
 }
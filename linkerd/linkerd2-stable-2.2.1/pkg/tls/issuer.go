@@ -0,0 +1,157 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewCAFromPEM returns a CA that issues certificates with an operator-provided
+// issuer, e.g. one that's managed by cert-manager, instead of a self-signed
+// root. certPEM holds the issuer certificate, optionally followed by the
+// intermediates up to the trust anchors, and keyPEM its P-256 ECDSA private
+// key, in either SEC 1 or PKCS#8 form. trustAnchorsPEM holds the certificates
+// the issuer chains to, if any; the issuer is trusted directly otherwise.
+//
+// The issued certificates don't carry the intermediates, so the issuer
+// certificate is always part of the trust anchors of the CA.
+func NewCAFromPEM(certPEM, keyPEM, trustAnchorsPEM []byte) (*CA, error) {
+	certs, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer certificate: %s", err)
+	}
+	issuer := certs[0]
+
+	privateKey, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer private key: %s", err)
+	}
+
+	if err := validateIssuer(issuer, certs[1:], privateKey, trustAnchorsPEM); err != nil {
+		return nil, err
+	}
+
+	issuerPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Raw})
+	trustAnchors := bytes.TrimSpace(trustAnchorsPEM)
+	if len(trustAnchors) > 0 {
+		trustAnchors = append(trustAnchors, '\n')
+	}
+	trustAnchors = append(trustAnchors, issuerPEM...)
+
+	return &CA{
+		// the certificates can't outlive their issuer, which is rotated by
+		// its operator
+		validity:           (24 * 365) * time.Hour,
+		clockSkewAllocance: 12 * time.Hour,
+		privateKey:         privateKey,
+		root:               issuer,
+		rootPEM:            string(trustAnchors),
+		// other CAs may share the issuer, or this one may be restarted, so
+		// the serial numbers can't start from 1
+		nextSerialNumber: uint64(time.Now().UnixNano()),
+		notAfter:         issuer.NotAfter,
+	}, nil
+}
+
+// Issuer returns the certificate the CA issues certificates with.
+func (ca *CA) Issuer() *x509.Certificate {
+	return ca.root
+}
+
+func validateIssuer(issuer *x509.Certificate, intermediates []*x509.Certificate, privateKey *ecdsa.PrivateKey, trustAnchorsPEM []byte) error {
+	if !issuer.IsCA || !issuer.BasicConstraintsValid {
+		return errors.New("the issuer certificate isn't a CA certificate")
+	}
+
+	publicKey, ok := issuer.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.X.Cmp(privateKey.X) != 0 || publicKey.Y.Cmp(privateKey.Y) != 0 {
+		return errors.New("the issuer private key doesn't match its certificate")
+	}
+
+	now := time.Now()
+	if now.Before(issuer.NotBefore) || now.After(issuer.NotAfter) {
+		return fmt.Errorf("the issuer certificate is only valid from %s to %s", issuer.NotBefore, issuer.NotAfter)
+	}
+
+	if len(bytes.TrimSpace(trustAnchorsPEM)) == 0 {
+		return nil
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(trustAnchorsPEM) {
+		return errors.New("no certificates found in the trust anchors")
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range intermediates {
+		pool.AddCert(cert)
+	}
+	_, err := issuer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("the issuer certificate doesn't chain to the trust anchors: %s", err)
+	}
+	return nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	return certs, nil
+}
+
+func parsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM-encoded private key found")
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the certificates are signed with ECDSAWithSHA256, see createTemplate
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 ECDSA keys are supported")
+		}
+		return ecKey, nil
+	}
+}
@@ -0,0 +1,110 @@
+package tls
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+)
+
+func encodeCA(t *testing.T, ca *CA) ([]byte, []byte) {
+	key, err := x509.MarshalECPrivateKey(ca.privateKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return []byte(ca.rootPEM), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key})
+}
+
+// newIntermediate returns an intermediate CA issued by root.
+func newIntermediate(t *testing.T, root *CA) ([]byte, []byte) {
+	privateKey, err := generateKeyPair()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	template := root.createTemplate(&privateKey.PublicKey)
+	template.Subject = pkix.Name{CommonName: "Intermediate CA"}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	der, err := x509.CreateCertificate(rand.Reader, &template, root.root, &privateKey.PublicKey, root.privateKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	p8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: p8})
+}
+
+func TestNewCAFromPEM(t *testing.T) {
+	root, err := NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	otherRoot, err := NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	rootCert, rootKey := encodeCA(t, root)
+	_, otherKey := encodeCA(t, otherRoot)
+	intermediateCert, intermediateKey := newIntermediate(t, root)
+
+	leaf, err := root.IssueEndEntityCertificate("foo.deployment.ns.linkerd-managed.linkerd.svc.cluster.local")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	leafCert, err := leaf.EncodedCertificate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	leafKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: leaf.PrivateKey})
+
+	testCases := []struct {
+		cert         []byte
+		key          []byte
+		trustAnchors []byte
+		valid        bool
+	}{
+		{cert: rootCert, key: rootKey, valid: true},
+		{cert: rootCert, key: otherKey, valid: false},
+		{cert: leafCert, key: leafKey, valid: false},
+		{cert: intermediateCert, key: intermediateKey, trustAnchors: rootCert, valid: true},
+		{cert: intermediateCert, key: intermediateKey, trustAnchors: []byte(otherRoot.TrustAnchorPEM()), valid: false},
+		{cert: intermediateCert, key: intermediateKey, valid: true},
+	}
+
+	for i, tc := range testCases {
+		ca, err := NewCAFromPEM(tc.cert, tc.key, tc.trustAnchors)
+		if !tc.valid {
+			if err == nil {
+				t.Fatalf("test case %d: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+
+		issued, err := ca.IssueEndEntityCertificate("bar.deployment.ns.linkerd-managed.linkerd.svc.cluster.local")
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		cert, err := x509.ParseCertificate(issued.Certificate)
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if cert.NotAfter.After(ca.Issuer().NotAfter) {
+			t.Fatalf("test case %d: expected the certificate to expire before its issuer", i)
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(ca.TrustAnchorPEM())) {
+			t.Fatalf("test case %d: invalid trust anchors", i)
+		}
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: roots}); err != nil {
+			t.Fatalf("test case %d: expected the certificate to be verified by the trust anchors: %s", i, err)
+		}
+	}
+}
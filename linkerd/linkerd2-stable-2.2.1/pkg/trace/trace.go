@@ -0,0 +1,64 @@
+// Package trace exports the trace spans of the control plane components to an
+// OpenCensus agent or collector, which forwards them to a tracing backend such
+// as Jaeger or Zipkin. The spans are only exported over the OpenCensus
+// protocol, so that the agent or collector is required: the components can't
+// export to Jaeger or Zipkin directly.
+package trace
+
+import (
+	"flag"
+	"fmt"
+
+	"contrib.go.opencensus.io/exporter/ocagent"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// Flags holds the values of the tracing flags.
+type Flags struct {
+	collector   *string
+	probability *float64
+}
+
+// NewFlags registers the -trace-collector and -trace-sample-probability
+// flags. They must be parsed before Init is called.
+func NewFlags() *Flags {
+	return &Flags{
+		collector:   flag.String("trace-collector", "", "address of the OpenCensus agent or collector to export trace spans to, e.g. \"oc-collector.tracing:55678\"; an agent or collector is required to forward the spans to Jaeger or Zipkin, which aren't exported to directly; tracing is disabled if empty"),
+		probability: flag.Float64("trace-sample-probability", 1, "probability with which the requests that aren't sampled by their caller yet are traced, between 0 and 1"),
+	}
+}
+
+// Init starts exporting the spans of the component named serviceName to the
+// collector set by the flags, if any. The returned func flushes the spans
+// that haven't been exported yet, and should be called on shutdown.
+func (f *Flags) Init(serviceName string) (func(), error) {
+	return initTracing(serviceName, *f.collector, *f.probability)
+}
+
+func initTracing(serviceName, collector string, probability float64) (func(), error) {
+	if collector == "" {
+		return func() {}, nil
+	}
+	if probability < 0 || probability > 1 {
+		return nil, fmt.Errorf("invalid trace sample probability %v, must be between 0 and 1", probability)
+	}
+
+	exporter, err := ocagent.NewExporter(
+		ocagent.WithInsecure(),
+		ocagent.WithAddress(collector),
+		ocagent.WithServiceName(serviceName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace exporter: %s", err)
+	}
+
+	trace.RegisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(probability)})
+	log.Infof("exporting trace spans to %s", collector)
+
+	return func() {
+		trace.UnregisterExporter(exporter)
+		exporter.Stop()
+	}, nil
+}
@@ -0,0 +1,33 @@
+package trace
+
+import (
+	"testing"
+)
+
+func TestInitTracing(t *testing.T) {
+	testCases := []struct {
+		collector   string
+		probability float64
+		valid       bool
+	}{
+		{collector: "", probability: 1, valid: true},
+		// the probability is ignored when tracing is disabled
+		{collector: "", probability: 2, valid: true},
+		{collector: "oc-collector.tracing:55678", probability: -0.1, valid: false},
+		{collector: "oc-collector.tracing:55678", probability: 1.5, valid: false},
+	}
+
+	for i, tc := range testCases {
+		stop, err := initTracing("test", tc.collector, tc.probability)
+		if !tc.valid {
+			if err == nil {
+				t.Fatalf("test case %d: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		stop()
+	}
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader is the header carrying the ID of a request, which is set
+// on the responses of the handlers wrapped by WithRequestID.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestIDMetadataKey is the gRPC metadata key carrying the ID of the request
+// a call is made for, so that the servers of the call log it too.
+const RequestIDMetadataKey = "x-request-id"
+
+type requestIDKey struct{}
+type requestLoggerKey struct{}
+
+// WithRequestID wraps the given handler so that every request has an ID,
+// either the one of its X-Request-Id header or a random one, and a logger
+// with a request-id field, returned by RequestLogger.
+func WithRequestID(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		handler.ServeHTTP(w, req.WithContext(contextWithRequestID(req.Context(), id)))
+	})
+}
+
+// RequestLogger returns the logger of a request served by a handler wrapped
+// by WithRequestID, or the standard logger otherwise.
+func RequestLogger(req *http.Request) *log.Entry {
+	return ContextLogger(req.Context())
+}
+
+// ContextLogger returns the logger of the request the context is for, with a
+// request-id field, or the standard logger if it has no request ID.
+func ContextLogger(ctx context.Context) *log.Entry {
+	if logger, ok := ctx.Value(requestLoggerKey{}).(*log.Entry); ok {
+		return logger
+	}
+	return log.NewEntry(log.StandardLogger())
+}
+
+// RequestID returns the ID of the request the context is for, or an empty
+// string if it has none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ResponseMetadata returns the request ID of the response of a handler
+// wrapped by WithRequestID as gRPC metadata, so that the clients streaming
+// gRPC messages over HTTP return it as the headers of their streams.
+func ResponseMetadata(header http.Header) metadata.MD {
+	if id := header.Get(RequestIDHeader); id != "" {
+		return metadata.Pairs(RequestIDMetadataKey, id)
+	}
+	return metadata.MD{}
+}
+
+func contextWithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return context.WithValue(ctx, requestLoggerKey{}, log.WithField("request-id", id))
+}
+
+// outgoingContext adds the ID of the request of the context to the metadata
+// of the calls made with it.
+func outgoingContext(ctx context.Context) context.Context {
+	if id := RequestID(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, id)
+	}
+	return ctx
+}
+
+// incomingContext returns the context of a call with the request ID of its
+// metadata, or a random one if it has none, and sends it back in the headers
+// of the call.
+func incomingContext(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md[RequestIDMetadataKey]; len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+	return contextWithRequestID(ctx, id)
+}
+
+// RequestIDUnaryClientInterceptor propagates the request ID of the context of
+// unary calls in their metadata.
+func RequestIDUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+}
+
+// RequestIDStreamClientInterceptor propagates the request ID of the context of
+// streaming calls in their metadata.
+func RequestIDStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+}
+
+// RequestIDUnaryServerInterceptor gives unary calls the request ID of their
+// metadata, which their handlers log with ContextLogger.
+func RequestIDUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(incomingContext(ctx), req)
+}
+
+// RequestIDStreamServerInterceptor gives streaming calls the request ID of
+// their metadata, which their handlers log with ContextLogger.
+func RequestIDStreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &requestIDServerStream{stream, incomingContext(stream.Context())})
+}
+
+type requestIDServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithRequestID(t *testing.T) {
+	var loggedID interface{}
+	handler := WithRequestID(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		loggedID = RequestLogger(req).Data["request-id"]
+	}))
+
+	t.Run("Keeps the ID of the request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+		rsp := httptest.NewRecorder()
+		handler.ServeHTTP(rsp, req)
+
+		if loggedID != "abc" {
+			t.Fatalf("Expected the logger to have request-id [abc], got [%v]", loggedID)
+		}
+		if id := rsp.Header().Get(RequestIDHeader); id != "abc" {
+			t.Fatalf("Expected the response to have request ID [abc], got [%s]", id)
+		}
+		if ids := ResponseMetadata(rsp.Header())[RequestIDMetadataKey]; len(ids) != 1 || ids[0] != "abc" {
+			t.Fatalf("Expected the response metadata to have request ID [abc], got %v", ids)
+		}
+	})
+
+	t.Run("Generates an ID for requests without one", func(t *testing.T) {
+		rsp := httptest.NewRecorder()
+		handler.ServeHTTP(rsp, httptest.NewRequest("GET", "/", nil))
+
+		id := rsp.Header().Get(RequestIDHeader)
+		if id == "" {
+			t.Fatal("Expected the response to have a request ID")
+		}
+		if loggedID != id {
+			t.Fatalf("Expected the logger to have request-id [%s], got [%v]", id, loggedID)
+		}
+	})
+}
+
+func TestRequestIDUnaryClientInterceptor(t *testing.T) {
+	var sentIDs []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sentIDs = md[RequestIDMetadataKey]
+		return nil
+	}
+
+	t.Run("Sends the ID of the request in the metadata", func(t *testing.T) {
+		ctx := contextWithRequestID(context.Background(), "abc")
+		err := RequestIDUnaryClientInterceptor(ctx, "/Method", nil, nil, nil, invoker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(sentIDs) != 1 || sentIDs[0] != "abc" {
+			t.Fatalf("Expected the metadata to have request ID [abc], got %v", sentIDs)
+		}
+	})
+
+	t.Run("Sends no ID for calls without a request", func(t *testing.T) {
+		err := RequestIDUnaryClientInterceptor(context.Background(), "/Method", nil, nil, nil, invoker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(sentIDs) != 0 {
+			t.Fatalf("Expected the metadata to have no request ID, got %v", sentIDs)
+		}
+	})
+}
+
+func TestRequestIDUnaryServerInterceptor(t *testing.T) {
+	var handledID string
+	var loggedID interface{}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handledID = RequestID(ctx)
+		loggedID = ContextLogger(ctx).Data["request-id"]
+		return nil, nil
+	}
+
+	t.Run("Keeps the ID of the call metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, "abc"))
+		_, err := RequestIDUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if handledID != "abc" {
+			t.Fatalf("Expected the call to have request ID [abc], got [%s]", handledID)
+		}
+		if loggedID != "abc" {
+			t.Fatalf("Expected the logger to have request-id [abc], got [%v]", loggedID)
+		}
+	})
+
+	t.Run("Generates an ID for calls without one", func(t *testing.T) {
+		_, err := RequestIDUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if handledID == "" {
+			t.Fatal("Expected the call to have a request ID")
+		}
+		if loggedID != handledID {
+			t.Fatalf("Expected the logger to have request-id [%s], got [%v]", handledID, loggedID)
+		}
+	})
+}
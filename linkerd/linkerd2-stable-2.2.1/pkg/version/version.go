@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // Version is updated automatically as part of the build process, and is the
@@ -60,3 +62,56 @@ func match(expectedVersion, actualVersion string) error {
 	return fmt.Errorf("is running version %s but the latest %s version is %s",
 		actual.version, actual.channel, expected.version)
 }
+
+// CheckUpgrade returns an error if toVersion is older than fromVersion. The
+// versions of different channels, or with non-numeric versions such as the dev
+// ones, can't be ordered and are accepted.
+func CheckUpgrade(fromVersion, toVersion string) error {
+	from, err := parseChannelVersion(fromVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse current version: %s", err)
+	}
+	to, err := parseChannelVersion(toVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse target version: %s", err)
+	}
+	if from.channel != to.channel {
+		return nil
+	}
+
+	fromParts, ok := parseVersionNumbers(from.version)
+	if !ok {
+		return nil
+	}
+	toParts, ok := parseVersionNumbers(to.version)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < len(fromParts) && i < len(toParts); i++ {
+		if toParts[i] != fromParts[i] {
+			if toParts[i] < fromParts[i] {
+				return fmt.Errorf("target version %s is older than the current version %s", to, from)
+			}
+			return nil
+		}
+	}
+	if len(toParts) < len(fromParts) {
+		return fmt.Errorf("target version %s is older than the current version %s", to, from)
+	}
+	return nil
+}
+
+// parseVersionNumbers splits a dotted version, such as 2.2.1, into its
+// numbers, returning false if it isn't numeric.
+func parseVersionNumbers(version string) ([]int, bool) {
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
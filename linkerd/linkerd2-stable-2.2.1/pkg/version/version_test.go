@@ -32,3 +32,34 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUpgrade(t *testing.T) {
+	testCases := []struct {
+		from string
+		to   string
+		err  error
+	}{
+		{"stable-2.2.1", "stable-2.2.1", nil},
+		{"stable-2.2.1", "stable-2.3.0", nil},
+		{"stable-2.2.1", "stable-2.10.0", nil},
+		{"edge-19.2.3", "edge-19.3.1", nil},
+		{"stable-2.2.1", "edge-19.2.3", nil},
+		{"dev-foo", "dev-bar", nil},
+		{"stable-2.2.1", "stable-2.2.0", errors.New("target version stable-2.2.0 is older than the current version stable-2.2.1")},
+		{"stable-2.2.1", "stable-2.2", errors.New("target version stable-2.2 is older than the current version stable-2.2.1")},
+		{"edge-19.3.1", "edge-19.2.3", errors.New("target version edge-19.2.3 is older than the current version edge-19.3.1")},
+		{"badformat", "stable-2.2.1", errors.New("failed to parse current version: unsupported version format: badformat")},
+		{"stable-2.2.1", "badformat", errors.New("failed to parse target version: unsupported version format: badformat")},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test %d CheckUpgrade(%s, %s)", i, tc.from, tc.to), func(t *testing.T) {
+			err := CheckUpgrade(tc.from, tc.to)
+			if (err == nil && tc.err != nil) ||
+				(err != nil && tc.err == nil) ||
+				((err != nil && tc.err != nil) && (err.Error() != tc.err.Error())) {
+				t.Fatalf("Expected \"%s\", got \"%s\"", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package webtoken generates and validates the short-lived tokens granting
+// access to the dashboard from outside the cluster. A token is the Unix time
+// it expires at, followed by the HMAC-SHA256 of that time with the key of the
+// linkerd-web-token secret, so that the web component only needs the key to
+// validate it.
+package webtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generate returns a token signed with the key, valid until expiry.
+func Generate(key []byte, expiry time.Time) string {
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+	return expires + "." + base64.RawURLEncoding.EncodeToString(sign(key, expires))
+}
+
+// Validate returns the time the token expires at if it was signed with the
+// key and it hasn't expired at now, or an error.
+func Validate(key []byte, token string, now time.Time) (time.Time, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return time.Time{}, errors.New("malformed token")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed token signature: %s", err)
+	}
+	if !hmac.Equal(signature, sign(key, parts[0])) {
+		return time.Time{}, errors.New("invalid token signature")
+	}
+
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed token expiry: %s", err)
+	}
+	expiry := time.Unix(seconds, 0)
+	if !now.Before(expiry) {
+		return time.Time{}, fmt.Errorf("token expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+	return expiry, nil
+}
+
+func sign(key []byte, expires string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(expires))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,37 @@
+package webtoken
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1550000000, 0)
+	token := Generate(key, now.Add(time.Hour))
+
+	testCases := []struct {
+		key   []byte
+		token string
+		now   time.Time
+		err   bool
+	}{
+		{key, token, now, false},
+		{key, token, now.Add(59 * time.Minute), false},
+		{key, token, now.Add(time.Hour), true},
+		{[]byte("other key"), token, now, true},
+		{key, "1550003600.c2lnbmF0dXJl", now, true},
+		{key, "1550003600", now, true},
+		{key, "", now, true},
+	}
+
+	for i, tc := range testCases {
+		expiry, err := Validate(tc.key, tc.token, tc.now)
+		if tc.err != (err != nil) {
+			t.Fatalf("test case %d: expected error to be %t, got [%v]", i, tc.err, err)
+		}
+		if err == nil && !expiry.Equal(now.Add(time.Hour)) {
+			t.Fatalf("test case %d: expected the token to expire at %s, got %s", i, now.Add(time.Hour), expiry)
+		}
+	}
+}
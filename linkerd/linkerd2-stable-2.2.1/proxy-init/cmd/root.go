@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	"github.com/linkerd/linkerd2/proxy-init/iptables"
 	"github.com/spf13/cobra"
@@ -9,26 +12,34 @@ import (
 
 // RootOptions provides the information that will be used to build a firewall configuration.
 type RootOptions struct {
-	IncomingProxyPort     int
-	OutgoingProxyPort     int
-	ProxyUserID           int
-	PortsToRedirect       []int
-	InboundPortsToIgnore  []int
-	OutboundPortsToIgnore []int
-	SimulateOnly          bool
-	NetNs                 string
+	IncomingProxyPort       int
+	OutgoingProxyPort       int
+	ProxyUserID             int
+	PortsToRedirect         []int
+	InboundPortsToIgnore    []string
+	OutboundPortsToIgnore   []string
+	InboundSubnetsToIgnore  []string
+	OutboundSubnetsToIgnore []string
+	MarksToIgnore           []string
+	IptablesMode            string
+	SimulateOnly            bool
+	NetNs                   string
 }
 
 func newRootOptions() *RootOptions {
 	return &RootOptions{
-		IncomingProxyPort:     -1,
-		OutgoingProxyPort:     -1,
-		ProxyUserID:           -1,
-		PortsToRedirect:       make([]int, 0),
-		InboundPortsToIgnore:  make([]int, 0),
-		OutboundPortsToIgnore: make([]int, 0),
-		SimulateOnly:          false,
-		NetNs:                 "",
+		IncomingProxyPort:       -1,
+		OutgoingProxyPort:       -1,
+		ProxyUserID:             -1,
+		PortsToRedirect:         make([]int, 0),
+		InboundPortsToIgnore:    make([]string, 0),
+		OutboundPortsToIgnore:   make([]string, 0),
+		InboundSubnetsToIgnore:  make([]string, 0),
+		OutboundSubnetsToIgnore: make([]string, 0),
+		MarksToIgnore:           make([]string, 0),
+		IptablesMode:            iptables.IptablesLegacyMode,
+		SimulateOnly:            false,
+		NetNs:                   "",
 	}
 }
 
@@ -54,8 +65,12 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(&options.OutgoingProxyPort, "outgoing-proxy-port", "o", options.OutgoingProxyPort, "Port to redirect outgoing traffic")
 	cmd.PersistentFlags().IntVarP(&options.ProxyUserID, "proxy-uid", "u", options.ProxyUserID, "User ID that the proxy is running under. Any traffic coming from this user will be ignored to avoid infinite redirection loops.")
 	cmd.PersistentFlags().IntSliceVarP(&options.PortsToRedirect, "ports-to-redirect", "r", options.PortsToRedirect, "Port to redirect to proxy, if no port is specified then ALL ports are redirected")
-	cmd.PersistentFlags().IntSliceVar(&options.InboundPortsToIgnore, "inbound-ports-to-ignore", options.InboundPortsToIgnore, "Inbound ports to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
-	cmd.PersistentFlags().IntSliceVar(&options.OutboundPortsToIgnore, "outbound-ports-to-ignore", options.OutboundPortsToIgnore, "Outbound ports to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
+	cmd.PersistentFlags().StringSliceVar(&options.InboundPortsToIgnore, "inbound-ports-to-ignore", options.InboundPortsToIgnore, "Inbound ports, or ranges of ports such as 4000-4100, to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
+	cmd.PersistentFlags().StringSliceVar(&options.OutboundPortsToIgnore, "outbound-ports-to-ignore", options.OutboundPortsToIgnore, "Outbound ports, or ranges of ports such as 4000-4100, to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
+	cmd.PersistentFlags().StringSliceVar(&options.InboundSubnetsToIgnore, "inbound-subnets-to-ignore", options.InboundSubnetsToIgnore, "CIDRs of the sources of the inbound traffic to ignore and not redirect to proxy")
+	cmd.PersistentFlags().StringSliceVar(&options.OutboundSubnetsToIgnore, "outbound-subnets-to-ignore", options.OutboundSubnetsToIgnore, "CIDRs of the destinations of the outbound traffic to ignore and not redirect to proxy")
+	cmd.PersistentFlags().StringSliceVar(&options.MarksToIgnore, "marks-to-ignore", options.MarksToIgnore, "Firewall marks, as value[/mask], of the traffic to ignore and not redirect to proxy")
+	cmd.PersistentFlags().StringVar(&options.IptablesMode, "iptables-mode", options.IptablesMode, "Variant of iptables to configure the rules with: \"legacy\" or \"nft\"")
 	cmd.PersistentFlags().BoolVar(&options.SimulateOnly, "simulate", options.SimulateOnly, "Don't execute any command, just print what would be executed")
 	cmd.PersistentFlags().StringVar(&options.NetNs, "netns", options.NetNs, "Optional network namespace in which to run the iptables commands")
 
@@ -72,15 +87,49 @@ func BuildFirewallConfiguration(options *RootOptions) (*iptables.FirewallConfigu
 		return nil, fmt.Errorf("--outgoing-proxy-port must be a valid TCP port number")
 	}
 
+	// the CNI plugin leaves the mode empty when it isn't configured
+	iptablesMode := options.IptablesMode
+	if iptablesMode == "" {
+		iptablesMode = iptables.IptablesLegacyMode
+	}
+	if iptablesMode != iptables.IptablesLegacyMode && iptablesMode != iptables.IptablesNftMode {
+		return nil, fmt.Errorf("--iptables-mode must be one of: %s, %s", iptables.IptablesLegacyMode, iptables.IptablesNftMode)
+	}
+
+	inboundPortsToIgnore, err := parsePortRanges(options.InboundPortsToIgnore)
+	if err != nil {
+		return nil, err
+	}
+	outboundPortsToIgnore, err := parsePortRanges(options.OutboundPortsToIgnore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subnet := range append(append([]string{}, options.InboundSubnetsToIgnore...), options.OutboundSubnetsToIgnore...) {
+		if _, _, err := net.ParseCIDR(subnet); err != nil {
+			return nil, fmt.Errorf("%s is not a valid CIDR", subnet)
+		}
+	}
+
+	for _, mark := range options.MarksToIgnore {
+		if !isValidMark(mark) {
+			return nil, fmt.Errorf("%s is not a valid firewall mark, expected value[/mask]", mark)
+		}
+	}
+
 	firewallConfiguration := &iptables.FirewallConfiguration{
-		ProxyInboundPort:       options.IncomingProxyPort,
-		ProxyOutgoingPort:      options.OutgoingProxyPort,
-		ProxyUID:               options.ProxyUserID,
-		PortsToRedirectInbound: options.PortsToRedirect,
-		InboundPortsToIgnore:   options.InboundPortsToIgnore,
-		OutboundPortsToIgnore:  options.OutboundPortsToIgnore,
-		SimulateOnly:           options.SimulateOnly,
-		NetNs:                  options.NetNs,
+		IptablesMode:            iptablesMode,
+		ProxyInboundPort:        options.IncomingProxyPort,
+		ProxyOutgoingPort:       options.OutgoingProxyPort,
+		ProxyUID:                options.ProxyUserID,
+		PortsToRedirectInbound:  options.PortsToRedirect,
+		InboundPortsToIgnore:    inboundPortsToIgnore,
+		OutboundPortsToIgnore:   outboundPortsToIgnore,
+		InboundSubnetsToIgnore:  options.InboundSubnetsToIgnore,
+		OutboundSubnetsToIgnore: options.OutboundSubnetsToIgnore,
+		MarksToIgnore:           options.MarksToIgnore,
+		SimulateOnly:            options.SimulateOnly,
+		NetNs:                   options.NetNs,
 	}
 
 	if len(options.PortsToRedirect) > 0 {
@@ -91,3 +140,36 @@ func BuildFirewallConfiguration(options *RootOptions) (*iptables.FirewallConfigu
 
 	return firewallConfiguration, nil
 }
+
+// parsePortRanges parses ports, and ranges of ports such as 4000-4100.
+func parsePortRanges(values []string) ([]iptables.PortRange, error) {
+	ranges := make([]iptables.PortRange, 0, len(values))
+	for _, value := range values {
+		bounds := strings.SplitN(value, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil || first < 1 || first > 65535 {
+			return nil, fmt.Errorf("%s is not a valid port or range of ports", value)
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil || last < first || last > 65535 {
+				return nil, fmt.Errorf("%s is not a valid port or range of ports", value)
+			}
+		}
+		ranges = append(ranges, iptables.PortRange{First: first, Last: last})
+	}
+	return ranges, nil
+}
+
+// isValidMark is true if the mark is a 32 bits value, optionally followed by
+// a mask, as the mark match of iptables takes them.
+func isValidMark(mark string) bool {
+	parts := strings.SplitN(mark, "/", 2)
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 0, 32); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -13,14 +13,18 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 		expectedOutgoingProxyPort := 2345
 		expectedProxyUserID := 33
 		expectedConfig := &iptables.FirewallConfiguration{
-			Mode:                   iptables.RedirectAllMode,
-			PortsToRedirectInbound: make([]int, 0),
-			InboundPortsToIgnore:   make([]int, 0),
-			OutboundPortsToIgnore:  make([]int, 0),
-			ProxyInboundPort:       expectedIncomingProxyPort,
-			ProxyOutgoingPort:      expectedOutgoingProxyPort,
-			ProxyUID:               expectedProxyUserID,
-			SimulateOnly:           false,
+			Mode:                    iptables.RedirectAllMode,
+			IptablesMode:            iptables.IptablesLegacyMode,
+			PortsToRedirectInbound:  make([]int, 0),
+			InboundPortsToIgnore:    make([]iptables.PortRange, 0),
+			OutboundPortsToIgnore:   make([]iptables.PortRange, 0),
+			InboundSubnetsToIgnore:  make([]string, 0),
+			OutboundSubnetsToIgnore: make([]string, 0),
+			MarksToIgnore:           make([]string, 0),
+			ProxyInboundPort:        expectedIncomingProxyPort,
+			ProxyOutgoingPort:       expectedOutgoingProxyPort,
+			ProxyUID:                expectedProxyUserID,
+			SimulateOnly:            false,
 		}
 
 		options := newRootOptions()
@@ -38,6 +42,59 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 		}
 	})
 
+	t.Run("It produces a FirewallConfiguration ignoring subnets and marks", func(t *testing.T) {
+		options := newRootOptions()
+		options.IncomingProxyPort = 1234
+		options.OutgoingProxyPort = 2345
+		options.InboundSubnetsToIgnore = []string{"10.0.0.0/8"}
+		options.OutboundSubnetsToIgnore = []string{"192.168.0.0/16", "fd00::/8"}
+		options.MarksToIgnore = []string{"0x4000/0x4000", "7"}
+		options.IptablesMode = iptables.IptablesNftMode
+
+		config, err := BuildFirewallConfiguration(options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if config.IptablesMode != iptables.IptablesNftMode {
+			t.Fatalf("Expected iptables mode [%s] but got [%s]", iptables.IptablesNftMode, config.IptablesMode)
+		}
+		if !reflect.DeepEqual(config.InboundSubnetsToIgnore, options.InboundSubnetsToIgnore) {
+			t.Fatalf("Expected inbound subnets [%v] but got [%v]", options.InboundSubnetsToIgnore, config.InboundSubnetsToIgnore)
+		}
+		if !reflect.DeepEqual(config.OutboundSubnetsToIgnore, options.OutboundSubnetsToIgnore) {
+			t.Fatalf("Expected outbound subnets [%v] but got [%v]", options.OutboundSubnetsToIgnore, config.OutboundSubnetsToIgnore)
+		}
+		if !reflect.DeepEqual(config.MarksToIgnore, options.MarksToIgnore) {
+			t.Fatalf("Expected marks [%v] but got [%v]", options.MarksToIgnore, config.MarksToIgnore)
+		}
+	})
+
+	t.Run("It produces a FirewallConfiguration ignoring ports and ranges of ports", func(t *testing.T) {
+		options := newRootOptions()
+		options.IncomingProxyPort = 1234
+		options.OutgoingProxyPort = 2345
+		options.InboundPortsToIgnore = []string{"4190", "4000-4100"}
+		options.OutboundPortsToIgnore = []string{"3306-3306"}
+
+		config, err := BuildFirewallConfiguration(options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedInbound := []iptables.PortRange{{First: 4190, Last: 4190}, {First: 4000, Last: 4100}}
+		if !reflect.DeepEqual(config.InboundPortsToIgnore, expectedInbound) {
+			t.Fatalf("Expected inbound ports [%v] but got [%v]", expectedInbound, config.InboundPortsToIgnore)
+		}
+		expectedOutbound := []iptables.PortRange{{First: 3306, Last: 3306}}
+		if !reflect.DeepEqual(config.OutboundPortsToIgnore, expectedOutbound) {
+			t.Fatalf("Expected outbound ports [%v] but got [%v]", expectedOutbound, config.OutboundPortsToIgnore)
+		}
+		if config.InboundPortsToIgnore[1].String() != "4000:4100" {
+			t.Fatalf("Expected the range to be formatted as [4000:4100] but got [%s]", config.InboundPortsToIgnore[1])
+		}
+	})
+
 	t.Run("It rejects invalid config options", func(t *testing.T) {
 		for _, tt := range []struct {
 			options      *RootOptions
@@ -71,6 +128,54 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 				},
 				errorMessage: "--outgoing-proxy-port must be a valid TCP port number",
 			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort: 1234,
+					OutgoingProxyPort: 2345,
+					IptablesMode:      "nftables",
+				},
+				errorMessage: "--iptables-mode must be one of: legacy, nft",
+			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort:    1234,
+					OutgoingProxyPort:    2345,
+					InboundPortsToIgnore: []string{"4100-4000"},
+				},
+				errorMessage: "4100-4000 is not a valid port or range of ports",
+			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort:     1234,
+					OutgoingProxyPort:     2345,
+					OutboundPortsToIgnore: []string{"65536"},
+				},
+				errorMessage: "65536 is not a valid port or range of ports",
+			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort:      1234,
+					OutgoingProxyPort:      2345,
+					InboundSubnetsToIgnore: []string{"10.0.0.1"},
+				},
+				errorMessage: "10.0.0.1 is not a valid CIDR",
+			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort:       1234,
+					OutgoingProxyPort:       2345,
+					OutboundSubnetsToIgnore: []string{"10.0.0.0/33"},
+				},
+				errorMessage: "10.0.0.0/33 is not a valid CIDR",
+			},
+			{
+				options: &RootOptions{
+					IncomingProxyPort: 1234,
+					OutgoingProxyPort: 2345,
+					MarksToIgnore:     []string{"0x4000/mask"},
+				},
+				errorMessage: "0x4000/mask is not a valid firewall mark, expected value[/mask]",
+			},
 		} {
 			_, err := BuildFirewallConfiguration(tt.options)
 			if err == nil {
@@ -22,6 +22,13 @@ const (
 
 	// IptablesOutputChainName specifies an iptables `OUTPUT` chain.
 	IptablesOutputChainName = "OUTPUT"
+
+	// IptablesLegacyMode runs the `iptables` binary of the image.
+	IptablesLegacyMode = "legacy"
+
+	// IptablesNftMode runs the `iptables-nft` binary, for hosts whose rules are
+	// managed with nftables.
+	IptablesNftMode = "nft"
 )
 
 var (
@@ -31,15 +38,35 @@ var (
 
 // FirewallConfiguration specifies how to configure a pod's iptables.
 type FirewallConfiguration struct {
-	Mode                   string
-	PortsToRedirectInbound []int
-	InboundPortsToIgnore   []int
-	OutboundPortsToIgnore  []int
-	ProxyInboundPort       int
-	ProxyOutgoingPort      int
-	ProxyUID               int
-	SimulateOnly           bool
-	NetNs                  string
+	Mode                    string
+	IptablesMode            string
+	PortsToRedirectInbound  []int
+	InboundPortsToIgnore    []PortRange
+	OutboundPortsToIgnore   []PortRange
+	InboundSubnetsToIgnore  []string
+	OutboundSubnetsToIgnore []string
+	MarksToIgnore           []string
+	ProxyInboundPort        int
+	ProxyOutgoingPort       int
+	ProxyUID                int
+	SimulateOnly            bool
+	NetNs                   string
+}
+
+// PortRange is an inclusive range of ports, with the same first and last
+// port for a single port.
+type PortRange struct {
+	First int
+	Last  int
+}
+
+// String formats the range as the destination port match of iptables takes
+// it, i.e. first:last.
+func (r PortRange) String() string {
+	if r.First == r.Last {
+		return strconv.Itoa(r.First)
+	}
+	return fmt.Sprintf("%d:%d", r.First, r.Last)
 }
 
 //ConfigureFirewall configures a pod's internal iptables to redirect all desired traffic through the proxy, allowing for
@@ -104,6 +131,9 @@ func addOutgoingTrafficRules(commands []*exec.Cmd, firewallConfiguration Firewal
 	commands = append(commands, makeIgnoreLoopback(outputChainName, "ignore-loopback"))
 	// Ignore ports
 	commands = addRulesForIgnoredPorts(firewallConfiguration.OutboundPortsToIgnore, outputChainName, commands)
+	// Ignore the traffic to the subnets that bypass the proxy
+	commands = addRulesForIgnoredSubnets(firewallConfiguration.OutboundSubnetsToIgnore, "-d", outputChainName, commands)
+	commands = addRulesForIgnoredMarks(firewallConfiguration.MarksToIgnore, outputChainName, commands)
 
 	log.Printf("Redirecting all OUTPUT to %d", firewallConfiguration.ProxyOutgoingPort)
 	commands = append(commands, makeRedirectChainToPort(outputChainName, firewallConfiguration.ProxyOutgoingPort, "redirect-all-outgoing-to-proxy-port"))
@@ -120,6 +150,8 @@ func addIncomingTrafficRules(commands []*exec.Cmd, firewallConfiguration Firewal
 
 	commands = append(commands, makeCreateNewChain(redirectChainName, "redirect-common-chain"))
 	commands = addRulesForIgnoredPorts(firewallConfiguration.InboundPortsToIgnore, redirectChainName, commands)
+	commands = addRulesForIgnoredSubnets(firewallConfiguration.InboundSubnetsToIgnore, "-s", redirectChainName, commands)
+	commands = addRulesForIgnoredMarks(firewallConfiguration.MarksToIgnore, redirectChainName, commands)
 	commands = addRulesForInboundPortRedirect(firewallConfiguration, redirectChainName, commands)
 
 	//Redirect all remaining inbound traffic to the proxy.
@@ -148,16 +180,43 @@ func addRulesForInboundPortRedirect(firewallConfiguration FirewallConfiguration,
 	return commands
 }
 
-func addRulesForIgnoredPorts(portsToIgnore []int, chainName string, commands []*exec.Cmd) []*exec.Cmd {
-	for _, ignoredPort := range portsToIgnore {
-		log.Printf("Will ignore port %d on chain %s", ignoredPort, chainName)
+// addRulesForIgnoredPorts returns from the chain for the packets destined to
+// one of the ports, with a single rule per range of ports.
+func addRulesForIgnoredPorts(portsToIgnore []PortRange, chainName string, commands []*exec.Cmd) []*exec.Cmd {
+	for _, ignoredPorts := range portsToIgnore {
+		log.Printf("Will ignore port %s on chain %s", ignoredPorts, chainName)
+
+		commands = append(commands, makeIgnorePort(chainName, ignoredPorts, fmt.Sprintf("ignore-port-%s", ignoredPorts)))
+	}
+	return commands
+}
+
+// addRulesForIgnoredSubnets returns from the chain for the packets whose
+// source or destination, depending on the match, is in one of the subnets.
+func addRulesForIgnoredSubnets(subnetsToIgnore []string, match string, chainName string, commands []*exec.Cmd) []*exec.Cmd {
+	for _, subnet := range subnetsToIgnore {
+		log.Printf("Will ignore subnet %s on chain %s", subnet, chainName)
 
-		commands = append(commands, makeIgnorePort(chainName, ignoredPort, fmt.Sprintf("ignore-port-%d", ignoredPort)))
+		commands = append(commands, makeIgnoreSubnet(chainName, match, subnet, fmt.Sprintf("ignore-subnet-%s", subnet)))
+	}
+	return commands
+}
+
+func addRulesForIgnoredMarks(marksToIgnore []string, chainName string, commands []*exec.Cmd) []*exec.Cmd {
+	for _, mark := range marksToIgnore {
+		log.Printf("Will ignore mark %s on chain %s", mark, chainName)
+
+		commands = append(commands, makeIgnoreMark(chainName, mark, fmt.Sprintf("ignore-mark-%s", mark)))
 	}
 	return commands
 }
 
 func executeCommand(firewallConfiguration FirewallConfiguration, cmd *exec.Cmd) error {
+	// the rules are built for the legacy binary, swap it in nft mode
+	if firewallConfiguration.IptablesMode == IptablesNftMode && cmd.Args[0] == "iptables" {
+		cmd = exec.Command("iptables-nft", cmd.Args[1:]...)
+	}
+
 	originalCmd := strings.Trim(fmt.Sprintf("%v", cmd.Args), "[]")
 	log.Printf("> %s", originalCmd)
 
@@ -225,12 +284,33 @@ func makeRedirectChainToPort(chainName string, portToRedirect int, comment strin
 		"--comment", formatComment(comment))
 }
 
-func makeIgnorePort(chainName string, portToIgnore int, comment string) *exec.Cmd {
+func makeIgnorePort(chainName string, portsToIgnore PortRange, comment string) *exec.Cmd {
 	return exec.Command("iptables",
 		"-t", "nat",
 		"-A", chainName,
 		"-p", "tcp",
-		"--destination-port", strconv.Itoa(portToIgnore),
+		"--destination-port", portsToIgnore.String(),
+		"-j", "RETURN",
+		"-m", "comment",
+		"--comment", formatComment(comment))
+}
+
+func makeIgnoreSubnet(chainName string, match string, subnet string, comment string) *exec.Cmd {
+	return exec.Command("iptables",
+		"-t", "nat",
+		"-A", chainName,
+		match, subnet,
+		"-j", "RETURN",
+		"-m", "comment",
+		"--comment", formatComment(comment))
+}
+
+func makeIgnoreMark(chainName string, mark string, comment string) *exec.Cmd {
+	return exec.Command("iptables",
+		"-t", "nat",
+		"-A", chainName,
+		"-m", "mark",
+		"--mark", mark,
 		"-j", "RETURN",
 		"-m", "comment",
 		"--comment", formatComment(comment))
@@ -0,0 +1,205 @@
+import BaseTable from './BaseTable.jsx';
+import Button from '@material-ui/core/Button';
+import ErrorBanner from './ErrorBanner.jsx';
+import PropTypes from 'prop-types';
+import React from 'react';
+import TextField from '@material-ui/core/TextField';
+import _each from 'lodash/each';
+import _get from 'lodash/get';
+import _take from 'lodash/take';
+import _throttle from 'lodash/throttle';
+import { publicAddressToString, wsCloseCodes } from './util/TapUtils.jsx';
+import { withContext } from './util/AppContext.jsx';
+
+const maxEventsToDisplay = 100;
+
+const columns = [
+  {
+    title: "Time",
+    dataIndex: "time",
+    render: d => d.time.toLocaleTimeString()
+  },
+  {
+    title: "Event",
+    dataIndex: "event"
+  },
+  {
+    title: "Service",
+    dataIndex: "service"
+  },
+  {
+    title: "Port",
+    dataIndex: "port",
+    isNumeric: true
+  },
+  {
+    title: "IP",
+    dataIndex: "ip"
+  },
+  {
+    title: "Pod",
+    dataIndex: "pod"
+  },
+  {
+    title: "Zone",
+    dataIndex: "zone"
+  }
+];
+
+// Endpoints streams the endpoints being added to and removed from the
+// services watched by the destination service, e.g. during rollouts.
+class Endpoints extends React.Component {
+  static propTypes = {
+    pathPrefix: PropTypes.string.isRequired
+  }
+
+  constructor(props) {
+    super(props);
+    this.events = [];
+    this.nextKey = 0;
+    this.throttledWebsocketRecvHandler = _throttle(this.updateEvents, 500);
+
+    this.state = {
+      events: [],
+      error: null,
+      namespace: "",
+      watchInProgress: false
+    };
+  }
+
+  componentDidMount() {
+    this._isMounted = true; // https://reactjs.org/blog/2015/12/16/ismounted-antipattern.html
+  }
+
+  componentWillUnmount() {
+    this._isMounted = false;
+    if (this.ws) {
+      this.ws.close(1000);
+    }
+    this.throttledWebsocketRecvHandler.cancel();
+  }
+
+  onWebsocketRecv = e => {
+    let update = JSON.parse(e.data);
+    let time = new Date();
+
+    let addEvents = (addresses, event) => {
+      _each(addresses, a => {
+        this.events.unshift({
+          key: this.nextKey++,
+          time,
+          event,
+          service: update.service,
+          port: update.port,
+          ip: publicAddressToString(_get(a, "addr.ip.ipv4")),
+          pod: _get(a, "pod.name", "-"),
+          zone: a.zone || "-"
+        });
+      });
+    };
+    addEvents(update.remove, "REMOVE");
+    addEvents(update.add, "ADD");
+
+    // don't let the events grow unbounded
+    this.events = _take(this.events, maxEventsToDisplay);
+    this.throttledWebsocketRecvHandler();
+  }
+
+  onWebsocketClose = e => {
+    this.stopWatching();
+    // see Tap.jsx for why abnormal closures are ignored
+    if (!e.wasClean && e.code !== 1006 && this._isMounted) {
+      this.setState({
+        error: {
+          error: `Websocket close error [${e.code}: ${wsCloseCodes[e.code]}] ${e.reason ? ":" : ""} ${e.reason}`
+        }
+      });
+    }
+  }
+
+  onWebsocketError = e => {
+    this.setState({
+      error: { error: `Websocket error: ${e.message}` }
+    });
+
+    this.stopWatching();
+  }
+
+  updateEvents = () => {
+    this.setState({
+      events: this.events
+    });
+  }
+
+  startWatching = e => {
+    e.preventDefault();
+    this.events = [];
+
+    this.setState({
+      events: [],
+      error: null,
+      watchInProgress: true
+    });
+
+    let protocol = window.location.protocol === "https:" ? "wss" : "ws";
+    let namespace = encodeURIComponent(this.state.namespace);
+    let url = `${protocol}://${window.location.host}${this.props.pathPrefix}/api/endpoints/watch?namespace=${namespace}`;
+
+    this.ws = new WebSocket(url);
+    this.ws.onmessage = this.onWebsocketRecv;
+    this.ws.onclose = this.onWebsocketClose;
+    this.ws.onerror = this.onWebsocketError;
+  }
+
+  stopWatching() {
+    if (!this._isMounted) {
+      return;
+    }
+
+    this.setState({
+      watchInProgress: false
+    });
+  }
+
+  handleStop = () => {
+    this.ws.close(1000);
+  }
+
+  handleNamespaceChange = e => {
+    this.setState({
+      namespace: e.target.value
+    });
+  }
+
+  render() {
+    return (
+      <div>
+        {!this.state.error ? null :
+        <ErrorBanner message={this.state.error} onHideMessage={() => this.setState({ error: null })} />}
+
+        <form onSubmit={this.startWatching}>
+          <TextField
+            id="endpoints-namespace"
+            label="Namespace"
+            placeholder="All namespaces"
+            value={this.state.namespace}
+            onChange={this.handleNamespaceChange}
+            disabled={this.state.watchInProgress} />
+          {
+            this.state.watchInProgress ?
+              <Button variant="outlined" color="primary" onClick={this.handleStop}>Stop</Button> :
+              <Button variant="outlined" color="primary" type="submit">Start</Button>
+          }
+        </form>
+
+        <BaseTable
+          tableRows={this.state.events}
+          tableColumns={columns}
+          tableClassName="metric-table"
+          padding="dense" />
+      </div>
+    );
+  }
+}
+
+export default withContext(Endpoints);
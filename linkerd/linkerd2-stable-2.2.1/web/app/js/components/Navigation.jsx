@@ -197,6 +197,7 @@ class NavigationBase extends React.Component {
   }
   render() {
     const { classes, ChildComponent, ...otherProps } = this.props;
+    const readOnly = this.props.readOnly === "true";
 
     return (
       <div className={classes.root}>
@@ -229,9 +230,10 @@ class NavigationBase extends React.Component {
 
           <MenuList>
             { this.menuItem("/overview", "Overview", <HomeIcon />) }
-            { this.menuItem("/tap", "Tap", <Icon className={classNames("fas fa-microscope", classes.shrinkIcon)} />) }
-            { this.menuItem("/top", "Top", <Icon className={classNames("fas fa-stream", classes.shrinkIcon)} />) }
+            { readOnly ? null : this.menuItem("/tap", "Tap", <Icon className={classNames("fas fa-microscope", classes.shrinkIcon)} />) }
+            { readOnly ? null : this.menuItem("/top", "Top", <Icon className={classNames("fas fa-stream", classes.shrinkIcon)} />) }
             { this.menuItem("/routes", "Top Routes", <Icon className={classNames("fas fa-random", classes.shrinkIcon)} />) }
+            { this.menuItem("/endpoints", "Endpoints", <Icon className={classNames("fas fa-sitemap", classes.shrinkIcon)} />) }
             { this.menuItem("/servicemesh", "Service Mesh", <CloudQueueIcon className={classes.shrinkIcon} />) }
             <NavigationResources />
           </MenuList>
@@ -297,9 +299,14 @@ NavigationBase.propTypes = {
   classes: PropTypes.shape({}).isRequired,
   location: ReactRouterPropTypes.location.isRequired,
   pathPrefix: PropTypes.string.isRequired,
+  readOnly: PropTypes.string,
   releaseVersion: PropTypes.string.isRequired,
   theme: PropTypes.shape({}).isRequired,
   uuid: PropTypes.string.isRequired,
 };
 
+NavigationBase.defaultProps = {
+  readOnly: "false",
+};
+
 export default withContext(withStyles(styles, { withTheme: true })(NavigationBase));
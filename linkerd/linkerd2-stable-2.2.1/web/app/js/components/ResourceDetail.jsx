@@ -47,7 +47,12 @@ export class ResourceDetailBase extends React.Component {
     match: PropTypes.shape({
       url: PropTypes.string.isRequired
     }).isRequired,
-    pathPrefix: PropTypes.string.isRequired
+    pathPrefix: PropTypes.string.isRequired,
+    readOnly: PropTypes.string,
+  }
+
+  static defaultProps = {
+    readOnly: "false",
   }
 
   constructor(props) {
@@ -286,7 +291,7 @@ export class ResourceDetailBase extends React.Component {
           query={query}
           pathPrefix={this.props.pathPrefix}
           updateUnmeshedSources={this.updateUnmeshedSources}
-          disableTop={!resourceIsMeshed} />
+          disableTop={!resourceIsMeshed || this.props.readOnly === "true"} />
 
         { _isEmpty(upstreams) ? null : (
           <React.Fragment>
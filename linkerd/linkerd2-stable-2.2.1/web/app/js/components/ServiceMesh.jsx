@@ -119,12 +119,17 @@ class ServiceMesh extends React.Component {
         pods: _map(byDeployName[deployName], p => {
           let uptimeSec = !p.uptime ? 0 : p.uptime.split(".")[0];
           let uptime = distanceInWordsToNow(subSeconds(Date.now(), parseInt(uptimeSec, 10)));
+          let proxyUptime = !p.proxyUptime ? "-" :
+            distanceInWordsToNow(subSeconds(Date.now(), parseInt(p.proxyUptime.split(".")[0], 10)));
 
           return {
             name: p.name,
             value: getPodClassification(p),
             uptime,
-            uptimeSec
+            uptimeSec,
+            proxyRestartCount: p.proxyRestartCount || 0,
+            proxyUptime,
+            proxyResources: p.proxyResources
           };
         })
       };
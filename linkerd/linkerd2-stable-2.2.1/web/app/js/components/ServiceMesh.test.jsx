@@ -86,6 +86,31 @@ describe('ServiceMesh', () => {
     });
   });
 
+  it("renders the proxy restarts and resources of the controller components", () => {
+    fetchStub.resolves({
+      ok: true,
+      json: () => Promise.resolve({
+        pods: [{
+          name: "linkerd/linkerd-web-5f97578cf6-597sr",
+          deployment: "linkerd/linkerd-web",
+          status: "Running",
+          controlPlane: true,
+          uptime: "600.5s",
+          proxyRestartCount: 2,
+          proxyUptime: "300.5s",
+          proxyResources: { cpuRequest: "100m", cpuLimit: "1", memoryRequest: "20Mi", memoryLimit: "" }
+        }]
+      })
+    });
+    component = mount(routerWrap(ServiceMesh));
+
+    return withPromise(() => {
+      component.update();
+      expect(component).toIncludeText("Proxy Restarts");
+      expect(component).toIncludeText("cpu 100m/1, memory 20Mi/-");
+    });
+  });
+
   it("renders service mesh details section", () => {
     fetchStub.resolves({
       ok: true,
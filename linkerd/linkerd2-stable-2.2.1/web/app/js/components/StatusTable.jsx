@@ -4,6 +4,7 @@ import React from 'react';
 import Tooltip from '@material-ui/core/Tooltip';
 import _get from 'lodash/get';
 import _merge from 'lodash/merge';
+import _sumBy from 'lodash/sumBy';
 import classNames from 'classnames';
 import { statusClassNames } from './util/theme.js';
 import { withStyles } from '@material-ui/core/styles';
@@ -43,6 +44,16 @@ const columnConfig = {
   }
 };
 
+// formatProxyResources returns the requests and limits of a proxy container,
+// as "request/limit" for the CPU and the memory
+const formatProxyResources = resources => {
+  if (!resources) {
+    return "-";
+  }
+  let pair = (request, limit) => `${request || "-"}/${limit || "-"}`;
+  return `cpu ${pair(resources.cpuRequest, resources.cpuLimit)}, memory ${pair(resources.memoryRequest, resources.memoryLimit)}`;
+};
+
 const StatusDot = ({status, columnName, classes}) => (
   <Tooltip
     placement="top"
@@ -51,6 +62,8 @@ const StatusDot = ({status, columnName, classes}) => (
         <div>{status.name}</div>
         <div>{_get(columnConfig, [columnName, "dotExplanation"])(status)}</div>
         <div>Uptime: {status.uptime} ({status.uptimeSec}s)</div>
+        <div>Proxy uptime: {status.proxyUptime}</div>
+        <div>Proxy restarts: {status.proxyRestartCount}</div>
       </div>
     )}>
     <div
@@ -83,6 +96,18 @@ const columns = {
     isNumeric: true,
     render: d => d.pods.length
   },
+  proxyRestarts: {
+    title: "Proxy Restarts",
+    key: "proxyRestarts",
+    isNumeric: true,
+    render: d => _sumBy(d.pods, "proxyRestartCount")
+  },
+  proxyResources: {
+    title: "Proxy Resources",
+    key: "proxyResources",
+    // the pods of a deployment share the resources of their proxy
+    render: d => d.pods.length === 0 ? "-" : formatProxyResources(d.pods[0].proxyResources)
+  },
   status: (name, classes) => {
     return {
       title: name,
@@ -116,6 +141,8 @@ class StatusTable extends React.Component {
     let tableCols = [
       columns.resourceName,
       columns.pods,
+      columns.proxyRestarts,
+      columns.proxyResources,
       columns.status(statusColumnTitle, classes)
     ];
 
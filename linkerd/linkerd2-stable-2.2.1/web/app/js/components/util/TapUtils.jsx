@@ -198,7 +198,7 @@ const decodeIPToOctets = ip => {
 /*
   converts an address to an ipv4 formatted host
 */
-const publicAddressToString = ipv4 => {
+export const publicAddressToString = ipv4 => {
   let octets = decodeIPToOctets(ipv4);
   return octets.join(".");
 };
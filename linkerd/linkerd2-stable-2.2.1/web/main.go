@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,15 +19,21 @@ import (
 
 func main() {
 	addr := flag.String("addr", ":8084", "address to serve on")
+	externalAddr := flag.String("external-addr", "", "address to serve on to the clients outside the cluster, which need a token signed with the key at -token-key-path (disabled if empty)")
+	tokenKeyPath := flag.String("token-key-path", "", "path of the key the tokens of the clients outside the cluster are signed with")
 	metricsAddr := flag.String("metrics-addr", ":9994", "address to serve scrapable metrics on")
 	apiAddr := flag.String("api-addr", "127.0.0.1:8085", "address of the linkerd-controller-api service")
 	grafanaAddr := flag.String("grafana-addr", "127.0.0.1:3000", "address of the linkerd-grafana service")
+	grafanaURL := flag.String("grafana-url", "", "URL of an external Grafana the requests to /grafana are redirected to, instead of being proxied to -grafana-addr")
 	templateDir := flag.String("template-dir", "templates", "directory to search for template files")
 	staticDir := flag.String("static-dir", "app/dist", "directory to search for static files")
 	uuid := flag.String("uuid", "", "unique linkerd install id")
 	reload := flag.Bool("reload", true, "reloading set to true or false")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	allowedNamespaces := flag.String("allowed-namespaces", "", "comma-separated list of the namespaces the dashboard serves the data of (all if empty)")
+	allowedNamespacesHeader := flag.String("allowed-namespaces-header", "", "name of a header, set by an authenticating proxy, holding the comma-separated list of the namespaces a request is further restricted to; the requests without it see no namespace")
+	readOnly := flag.Bool("read-only", false, "only serve the metrics, disabling tap and the editing of the Grafana dashboards")
 	flags.ConfigureAndParse()
 
 	_, _, err := net.SplitHostPort(*apiAddr) // Verify apiAddr is of the form host:port.
@@ -41,13 +48,23 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	server := srv.NewServer(*addr, *grafanaAddr, *templateDir, *staticDir, *uuid, *controllerNamespace, *singleNamespace, *reload, client)
+	namespaceAccess := srv.NamespaceAccess{Namespaces: *allowedNamespaces, Header: *allowedNamespacesHeader}
+	server := srv.NewServer(*addr, *grafanaAddr, *grafanaURL, *templateDir, *staticDir, *uuid, *controllerNamespace, *singleNamespace, *readOnly, namespaceAccess, *reload, client)
 
 	go func() {
 		log.Infof("starting HTTP server on %+v", *addr)
 		server.ListenAndServe()
 	}()
 
+	var externalServer *http.Server
+	if *externalAddr != "" {
+		externalServer = srv.NewExternalServer(server, *externalAddr, *tokenKeyPath)
+		go func() {
+			log.Infof("starting external HTTP server on %+v", *externalAddr)
+			externalServer.ListenAndServe()
+		}()
+	}
+
 	go admin.StartServer(*metricsAddr)
 
 	<-stop
@@ -56,4 +73,7 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	server.Shutdown(ctx)
+	if externalServer != nil {
+		externalServer.Shutdown(ctx)
+	}
 }
@@ -3,8 +3,10 @@ package srv
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
@@ -12,6 +14,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/julienschmidt/httprouter"
 	"github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/controller/gen/controller/discovery"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
@@ -70,10 +73,17 @@ func (h *handler) handleAPIVersion(w http.ResponseWriter, req *http.Request, p h
 }
 
 func (h *handler) handleAPIPods(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	scope := h.requestScope(req)
+	namespace := req.FormValue("namespace")
+	if namespace != "" && !scope.allows(namespace) {
+		renderJSONError(w, fmt.Errorf("access to the pods of namespace %q is not allowed", namespace), http.StatusForbidden)
+		return
+	}
+
 	pods, err := h.apiClient.ListPods(req.Context(), &pb.ListPodsRequest{
 		Selector: &pb.ResourceSelection{
 			Resource: &pb.Resource{
-				Namespace: req.FormValue("namespace"),
+				Namespace: namespace,
 			},
 		},
 	})
@@ -83,12 +93,20 @@ func (h *handler) handleAPIPods(w http.ResponseWriter, req *http.Request, p http
 		return
 	}
 
+	scope.filterPods(pods)
 	renderJSONPb(w, pods)
 }
 
 func (h *handler) handleAPIServices(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	scope := h.requestScope(req)
+	namespace := req.FormValue("namespace")
+	if namespace != "" && !scope.allows(namespace) {
+		renderJSONError(w, fmt.Errorf("access to the services of namespace %q is not allowed", namespace), http.StatusForbidden)
+		return
+	}
+
 	services, err := h.apiClient.ListServices(req.Context(), &pb.ListServicesRequest{
-		Namespace: req.FormValue("namespace"),
+		Namespace: namespace,
 	})
 
 	if err != nil {
@@ -96,6 +114,7 @@ func (h *handler) handleAPIServices(w http.ResponseWriter, req *http.Request, p
 		return
 	}
 
+	scope.filterServices(services)
 	renderJSONPb(w, services)
 }
 
@@ -136,11 +155,23 @@ func (h *handler) handleAPIStat(w http.ResponseWriter, req *http.Request, p http
 		return
 	}
 
+	// the rows of a selection spanning all namespaces are filtered instead
+	scope := h.requestScope(req)
+	resources := []*pb.Resource{statRequest.GetToResource(), statRequest.GetFromResource(), statRequest.GetToFilter()}
+	if selected := statRequest.GetSelector().GetResource(); resourceNamespace(selected) != "" {
+		resources = append(resources, selected)
+	}
+	if err := scope.checkResources(resources...); err != nil {
+		renderJSONError(w, err, http.StatusForbidden)
+		return
+	}
+
 	result, err := h.apiClient.StatSummary(req.Context(), statRequest)
 	if err != nil {
 		renderJSONError(w, err, http.StatusInternalServerError)
 		return
 	}
+	scope.filterStats(result)
 	renderJSONPb(w, result)
 }
 
@@ -163,6 +194,12 @@ func (h *handler) handleAPITopRoutes(w http.ResponseWriter, req *http.Request, p
 		return
 	}
 
+	err = h.requestScope(req).checkResources(topReq.GetSelector().GetResource(), topReq.GetToResource())
+	if err != nil {
+		renderJSONError(w, err, http.StatusForbidden)
+		return
+	}
+
 	result, err := h.apiClient.TopRoutes(req.Context(), topReq)
 	if err != nil {
 		renderJSONError(w, err, http.StatusInternalServerError)
@@ -210,6 +247,15 @@ func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httpr
 		return
 	}
 
+	resources := []*pb.Resource{tapReq.GetTarget().GetResource()}
+	for _, match := range tapReq.GetMatch().GetAll().GetMatches() {
+		resources = append(resources, match.GetDestinations().GetResource())
+	}
+	if err := h.requestScope(req).checkResources(resources...); err != nil {
+		websocketError(ws, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+
 	go func() {
 		tapClient, err := h.apiClient.TapByResource(req.Context(), tapReq)
 		if err != nil {
@@ -255,3 +301,77 @@ func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httpr
 		}
 	}
 }
+
+// handleAPIEndpointsWatch streams the endpoints being added and removed over a
+// websocket. The updates can be restricted to the services of a namespace
+// with the "namespace" query parameter.
+func (h *handler) handleAPIEndpointsWatch(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	scope := h.requestScope(req)
+	namespace := req.FormValue("namespace")
+	if namespace != "" && !scope.allows(namespace) {
+		renderJSONError(w, fmt.Errorf("access to the endpoints of namespace %q is not allowed", namespace), http.StatusForbidden)
+		return
+	}
+	params := &discovery.EndpointsParams{
+		ClientZone: req.FormValue("client_zone"),
+	}
+
+	ws, err := websocketUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		renderJSONError(w, err, http.StatusInternalServerError)
+		return
+	}
+	defer ws.Close()
+
+	go func() {
+		updates, err := h.apiClient.WatchEndpoints(req.Context(), params)
+		if err != nil {
+			websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+			return
+		}
+
+		for {
+			rsp, err := updates.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+				break
+			}
+
+			if namespace != "" && !strings.HasSuffix(rsp.GetService(), "."+namespace) {
+				continue
+			}
+			// the services are named name.namespace
+			if parts := strings.SplitN(rsp.GetService(), ".", 2); len(parts) != 2 || !scope.allows(parts[1]) {
+				continue
+			}
+
+			buf := new(bytes.Buffer)
+			err = pbMarshaler.Marshal(buf, rsp)
+			if err != nil {
+				websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+				break
+			}
+
+			if err := ws.WriteMessage(websocket.TextMessage, buf.Bytes()); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
+					log.Error(err)
+				}
+				break
+			}
+		}
+	}()
+
+	for {
+		_, _, err := ws.ReadMessage()
+		if err != nil {
+			log.Debugf("Received close frame: %v", err)
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
+				log.Errorf("Unexpected close error: %s", err)
+			}
+			return
+		}
+	}
+}
@@ -31,3 +31,22 @@ func newGrafanaProxy(addr string) *grafanaProxy {
 		ReverseProxy: &httputil.ReverseProxy{Director: director},
 	}
 }
+
+// grafanaRedirect redirects all web requests containing paths prefixed with
+// "/grafana" to an external Grafana, replacing the "/grafana" prefix with its
+// URL.
+type grafanaRedirect struct {
+	url string
+}
+
+func newGrafanaRedirect(url string) *grafanaRedirect {
+	return &grafanaRedirect{url: strings.TrimSuffix(url, "/")}
+}
+
+func (g *grafanaRedirect) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	target := g.url + strings.TrimPrefix(req.URL.Path, "/grafana")
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	http.Redirect(w, req, target, http.StatusFound)
+}
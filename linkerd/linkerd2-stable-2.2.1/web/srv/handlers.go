@@ -7,6 +7,7 @@ import (
 	"regexp"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	profiles "github.com/linkerd/linkerd2/pkg/profiles"
 	log "github.com/sirupsen/logrus"
@@ -19,11 +20,13 @@ type (
 
 	handler struct {
 		render              renderTemplate
-		apiClient           pb.ApiClient
+		apiClient           public.APIClient
 		uuid                string
 		controllerNamespace string
 		singleNamespace     bool
-		grafanaProxy        *grafanaProxy
+		readOnly            bool
+		namespaceAccess     NamespaceAccess
+		grafana             http.Handler
 	}
 )
 
@@ -38,6 +41,7 @@ func (h *handler) handleIndex(w http.ResponseWriter, req *http.Request, p httpro
 		UUID:                h.uuid,
 		ControllerNamespace: h.controllerNamespace,
 		SingleNamespace:     h.singleNamespace,
+		ReadOnly:            h.readOnly,
 		PathPrefix:          pathPfx,
 	}
 
@@ -69,7 +73,7 @@ func (h *handler) handleProfileDownload(w http.ResponseWriter, req *http.Request
 	}
 
 	profileYaml := &bytes.Buffer{}
-	err := profiles.RenderProfileTemplate(namespace, service, profileYaml)
+	err := profiles.RenderProfileTemplate(namespace, service, profiles.RetryConfig{}, profileYaml)
 
 	if err != nil {
 		log.Error(err)
@@ -86,5 +90,17 @@ func (h *handler) handleProfileDownload(w http.ResponseWriter, req *http.Request
 }
 
 func (h *handler) handleGrafana(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
-	h.grafanaProxy.ServeHTTP(w, req)
+	h.grafana.ServeHTTP(w, req)
+}
+
+// disabledInReadOnly wraps the handlers of tap and of the requests that can
+// edit the Grafana dashboards, which are forbidden in read-only mode.
+func (h *handler) disabledInReadOnly(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+		if h.readOnly {
+			http.Error(w, "the dashboard is read-only", http.StatusForbidden)
+			return
+		}
+		handle(w, req, p)
+	}
 }
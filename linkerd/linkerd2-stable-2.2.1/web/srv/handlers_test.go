@@ -55,6 +55,7 @@ func TestHandleIndex(t *testing.T) {
 		"data-release-version=\"0.3.3\"",
 		"data-go-version=\"the best one\"",
 		"data-controller-namespace=\"\"",
+		"data-read-only=\"false\"",
 		"data-uuid=\"\"",
 	}
 	for _, expectedSubstring := range expectedSubstrings {
@@ -116,3 +117,53 @@ func TestHandleConfigDownload(t *testing.T) {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+func TestDisabledInReadOnly(t *testing.T) {
+	handle := func(w http.ResponseWriter, req *http.Request, p httprouter.Params) {}
+
+	testCases := []struct {
+		readOnly       bool
+		expectedStatus int
+	}{
+		{false, http.StatusOK},
+		{true, http.StatusForbidden},
+	}
+
+	for i, tc := range testCases {
+		handler := &handler{readOnly: tc.readOnly}
+
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/tap", nil)
+		handler.disabledInReadOnly(handle)(recorder, req, httprouter.Params{})
+
+		if recorder.Code != tc.expectedStatus {
+			t.Fatalf("test case %d: expected status %d, got %d", i, tc.expectedStatus, recorder.Code)
+		}
+	}
+}
+
+func TestHandleGrafanaRedirect(t *testing.T) {
+	testCases := []struct {
+		url              string
+		path             string
+		expectedLocation string
+	}{
+		{"https://grafana.example.com", "/grafana/dashboard/db/linkerd-deployment?var-namespace=emojivoto", "https://grafana.example.com/dashboard/db/linkerd-deployment?var-namespace=emojivoto"},
+		{"https://example.com/grafana/", "/grafana/", "https://example.com/grafana/"},
+	}
+
+	for i, tc := range testCases {
+		handler := &handler{grafana: newGrafanaRedirect(tc.url)}
+
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tc.path, nil)
+		handler.handleGrafana(recorder, req, httprouter.Params{})
+
+		if recorder.Code != http.StatusFound {
+			t.Fatalf("test case %d: expected status %d, got %d", i, http.StatusFound, recorder.Code)
+		}
+		if location := recorder.Header().Get("Location"); location != tc.expectedLocation {
+			t.Fatalf("test case %d: expected location %s, got %s", i, tc.expectedLocation, location)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package srv
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+// namespaceScope is the set of namespaces whose data the dashboard serves to
+// a request. A nil scope allows every namespace.
+type namespaceScope map[string]struct{}
+
+// parseNamespaceScope returns the scope of a comma-separated list of
+// namespaces, which is empty, allowing no namespace, if the list is.
+func parseNamespaceScope(list string) namespaceScope {
+	scope := namespaceScope{}
+	for _, ns := range strings.Split(list, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			scope[ns] = struct{}{}
+		}
+	}
+	return scope
+}
+
+// NamespaceAccess configures the namespaces the dashboard serves the data of.
+type NamespaceAccess struct {
+	// Namespaces is the comma-separated list of the namespaces every request
+	// is restricted to. All the namespaces are allowed if it's empty.
+	Namespaces string
+
+	// Header is the name of a header, set by an authenticating proxy in front
+	// of the dashboard, holding the comma-separated list of the namespaces the
+	// request is further restricted to. The requests without it are allowed
+	// no namespace. It's ignored if it's empty.
+	Header string
+}
+
+// requestScope returns the scope of the namespaces the request is allowed to
+// see, which is nil if it isn't restricted.
+func (h *handler) requestScope(req *http.Request) namespaceScope {
+	var scope namespaceScope
+	if h.namespaceAccess.Namespaces != "" {
+		scope = parseNamespaceScope(h.namespaceAccess.Namespaces)
+	}
+	if h.namespaceAccess.Header == "" {
+		return scope
+	}
+
+	headerScope := parseNamespaceScope(req.Header.Get(h.namespaceAccess.Header))
+	if scope == nil {
+		return headerScope
+	}
+	for ns := range scope {
+		if _, ok := headerScope[ns]; !ok {
+			delete(scope, ns)
+		}
+	}
+	return scope
+}
+
+func (s namespaceScope) allows(namespace string) bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s[namespace]
+	return ok
+}
+
+// allowsResource returns whether the resource is in an allowed namespace. A
+// namespace resource is allowed if it's an allowed namespace, and a resource
+// without a namespace, selecting all of them, only if every namespace is.
+func (s namespaceScope) allowsResource(resource *pb.Resource) bool {
+	if resource.GetType() == k8s.Namespace {
+		if resource.GetName() == "" {
+			return s == nil
+		}
+		return s.allows(resource.GetName())
+	}
+	if resource.GetNamespace() == "" {
+		return s == nil
+	}
+	return s.allows(resource.GetNamespace())
+}
+
+// checkResources returns an error if any of the resources isn't in an allowed
+// namespace. The nil resources are skipped.
+func (s namespaceScope) checkResources(resources ...*pb.Resource) error {
+	for _, resource := range resources {
+		if resource != nil && !s.allowsResource(resource) {
+			return fmt.Errorf("access to the resources of namespace %q is not allowed", resourceNamespace(resource))
+		}
+	}
+	return nil
+}
+
+func resourceNamespace(resource *pb.Resource) string {
+	if resource.GetType() == k8s.Namespace {
+		return resource.GetName()
+	}
+	return resource.GetNamespace()
+}
+
+// filterPods removes the pods outside of the scope from the response.
+func (s namespaceScope) filterPods(rsp *pb.ListPodsResponse) {
+	if s == nil {
+		return
+	}
+	pods := rsp.Pods[:0]
+	for _, pod := range rsp.Pods {
+		// the pods are named namespace/name
+		if s.allows(strings.SplitN(pod.GetName(), "/", 2)[0]) {
+			pods = append(pods, pod)
+		}
+	}
+	rsp.Pods = pods
+}
+
+// filterServices removes the services outside of the scope from the response.
+func (s namespaceScope) filterServices(rsp *pb.ListServicesResponse) {
+	if s == nil {
+		return
+	}
+	services := rsp.Services[:0]
+	for _, svc := range rsp.Services {
+		if s.allows(svc.GetNamespace()) {
+			services = append(services, svc)
+		}
+	}
+	rsp.Services = services
+}
+
+// filterStats removes the rows of the resources outside of the scope from the
+// response.
+func (s namespaceScope) filterStats(rsp *pb.StatSummaryResponse) {
+	if s == nil {
+		return
+	}
+	for _, table := range rsp.GetOk().GetStatTables() {
+		podGroup := table.GetPodGroup()
+		if podGroup == nil {
+			continue
+		}
+		rows := podGroup.Rows[:0]
+		for _, row := range podGroup.Rows {
+			if s.allowsResource(row.GetResource()) {
+				rows = append(rows, row)
+			}
+		}
+		podGroup.Rows = rows
+	}
+}
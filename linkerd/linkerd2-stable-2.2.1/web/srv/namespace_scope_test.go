@@ -0,0 +1,145 @@
+package srv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestRequestScope(t *testing.T) {
+	testCases := []struct {
+		access        NamespaceAccess
+		header        string
+		expectedScope namespaceScope
+	}{
+		{NamespaceAccess{}, "", nil},
+		{NamespaceAccess{Namespaces: "books, emojivoto"}, "", namespaceScope{"books": {}, "emojivoto": {}}},
+		{NamespaceAccess{Header: "X-Namespaces"}, "books", namespaceScope{"books": {}}},
+		{NamespaceAccess{Header: "X-Namespaces"}, "", namespaceScope{}},
+		{NamespaceAccess{Namespaces: "books,emojivoto", Header: "X-Namespaces"}, "emojivoto,linkerd", namespaceScope{"emojivoto": {}}},
+	}
+
+	for i, tc := range testCases {
+		h := &handler{namespaceAccess: tc.access}
+		req := httptest.NewRequest("GET", "/api/pods", nil)
+		if tc.header != "" {
+			req.Header.Set("X-Namespaces", tc.header)
+		}
+
+		scope := h.requestScope(req)
+		if !reflect.DeepEqual(scope, tc.expectedScope) {
+			t.Fatalf("test case %d: expected scope %v, got %v", i, tc.expectedScope, scope)
+		}
+	}
+}
+
+func TestNamespaceScopeCheckResources(t *testing.T) {
+	scope := namespaceScope{"emojivoto": {}}
+
+	testCases := []struct {
+		resource *pb.Resource
+		allowed  bool
+	}{
+		{&pb.Resource{Type: k8s.Deployment, Namespace: "emojivoto", Name: "web"}, true},
+		{&pb.Resource{Type: k8s.Deployment, Namespace: "books", Name: "web"}, false},
+		{&pb.Resource{Type: k8s.Deployment}, false},
+		{&pb.Resource{Type: k8s.Namespace, Name: "emojivoto"}, true},
+		{&pb.Resource{Type: k8s.Namespace}, false},
+		{nil, true},
+	}
+
+	for i, tc := range testCases {
+		err := scope.checkResources(tc.resource)
+		if tc.allowed != (err == nil) {
+			t.Fatalf("test case %d: expected the resource to be allowed to be %t, got [%v]", i, tc.allowed, err)
+		}
+	}
+
+	if err := namespaceScope(nil).checkResources(&pb.Resource{Type: k8s.Namespace}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestNamespaceScopeFilterStats(t *testing.T) {
+	row := func(resourceType, namespace, name string) *pb.StatTable_PodGroup_Row {
+		return &pb.StatTable_PodGroup_Row{
+			Resource: &pb.Resource{Type: resourceType, Namespace: namespace, Name: name},
+		}
+	}
+	rsp := &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{
+			Ok: &pb.StatSummaryResponse_Ok{
+				StatTables: []*pb.StatTable{
+					{
+						Table: &pb.StatTable_PodGroup_{
+							PodGroup: &pb.StatTable_PodGroup{
+								Rows: []*pb.StatTable_PodGroup_Row{
+									row(k8s.Deployment, "books", "web"),
+									row(k8s.Deployment, "emojivoto", "web"),
+									row(k8s.Namespace, "", "books"),
+									row(k8s.Namespace, "", "emojivoto"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	namespaceScope{"emojivoto": {}}.filterStats(rsp)
+
+	expected := []*pb.StatTable_PodGroup_Row{
+		row(k8s.Deployment, "emojivoto", "web"),
+		row(k8s.Namespace, "", "emojivoto"),
+	}
+	rows := rsp.GetOk().GetStatTables()[0].GetPodGroup().GetRows()
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("Expected rows %v, got %v", expected, rows)
+	}
+}
+
+func TestHandleAPIPodsScope(t *testing.T) {
+	mockAPIClient := &public.MockAPIClient{
+		ListPodsResponseToReturn: &pb.ListPodsResponse{
+			Pods: []*pb.Pod{
+				{Name: "books/authors-1"},
+				{Name: "emojivoto/web-1"},
+			},
+		},
+	}
+	h := &handler{
+		apiClient:       mockAPIClient,
+		namespaceAccess: NamespaceAccess{Namespaces: "emojivoto"},
+	}
+
+	recorder := httptest.NewRecorder()
+	h.handleAPIPods(recorder, httptest.NewRequest("GET", "/api/pods", nil), httprouter.Params{})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var pods struct {
+		Pods []struct {
+			Name string `json:"name"`
+		} `json:"pods"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &pods); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(pods.Pods) != 1 || pods.Pods[0].Name != "emojivoto/web-1" {
+		t.Fatalf("Expected only the pods of the emojivoto namespace, got %+v", pods.Pods)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.handleAPIPods(recorder, httptest.NewRequest("GET", "/api/pods?namespace=books", nil), httprouter.Params{})
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("Expected status %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+}
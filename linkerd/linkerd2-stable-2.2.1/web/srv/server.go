@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/filesonly"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
@@ -35,6 +36,7 @@ type (
 		UUID                string
 		ControllerNamespace string
 		SingleNamespace     bool
+		ReadOnly            bool
 		Error               bool
 		ErrorMessage        string
 		PathPrefix          string
@@ -52,13 +54,16 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 func NewServer(
 	addr string,
 	grafanaAddr string,
+	grafanaURL string,
 	templateDir string,
 	staticDir string,
 	uuid string,
 	controllerNamespace string,
 	singleNamespace bool,
+	readOnly bool,
+	namespaceAccess NamespaceAccess,
 	reload bool,
-	apiClient pb.ApiClient,
+	apiClient public.APIClient,
 ) *http.Server {
 	server := &Server{
 		templateDir: templateDir,
@@ -72,13 +77,21 @@ func NewServer(
 	}
 
 	wrappedServer := prometheus.WithTelemetry(server)
+
+	var grafana http.Handler = newGrafanaProxy(grafanaAddr)
+	if grafanaURL != "" {
+		grafana = newGrafanaRedirect(grafanaURL)
+	}
+
 	handler := &handler{
 		apiClient:           apiClient,
 		render:              server.RenderTemplate,
 		uuid:                uuid,
 		controllerNamespace: controllerNamespace,
 		singleNamespace:     singleNamespace,
-		grafanaProxy:        newGrafanaProxy(grafanaAddr),
+		readOnly:            readOnly,
+		namespaceAccess:     namespaceAccess,
+		grafana:             grafana,
 	}
 
 	httpServer := &http.Server{
@@ -105,9 +118,10 @@ func NewServer(
 	server.router.GET("/namespaces/:namespace/statefulsets/:statefulset", handler.handleIndex)
 	server.router.GET("/namespaces/:namespace/deployments/:deployment", handler.handleIndex)
 	server.router.GET("/namespaces/:namespace/replicationcontrollers/:replicationcontroller", handler.handleIndex)
-	server.router.GET("/tap", handler.handleIndex)
-	server.router.GET("/top", handler.handleIndex)
+	server.router.GET("/tap", handler.disabledInReadOnly(handler.handleIndex))
+	server.router.GET("/top", handler.disabledInReadOnly(handler.handleIndex))
 	server.router.GET("/routes", handler.handleIndex)
+	server.router.GET("/endpoints", handler.handleIndex)
 	server.router.GET("/profiles/new", handler.handleProfileDownload)
 	// add catch-all parameter to match all files in dir
 	server.router.GET("/dist/*filepath", mkStaticHandler(staticDir))
@@ -120,17 +134,18 @@ func NewServer(
 	server.router.GET("/api/tps-reports", handler.handleAPIStat)
 	server.router.GET("/api/pods", handler.handleAPIPods)
 	server.router.GET("/api/services", handler.handleAPIServices)
-	server.router.GET("/api/tap", handler.handleAPITap)
+	server.router.GET("/api/tap", handler.disabledInReadOnly(handler.handleAPITap))
 	server.router.GET("/api/routes", handler.handleAPITopRoutes)
+	server.router.GET("/api/endpoints/watch", handler.handleAPIEndpointsWatch)
 
 	// grafana proxy
-	server.router.DELETE("/grafana/*grafanapath", handler.handleGrafana)
+	server.router.DELETE("/grafana/*grafanapath", handler.disabledInReadOnly(handler.handleGrafana))
 	server.router.GET("/grafana/*grafanapath", handler.handleGrafana)
 	server.router.HEAD("/grafana/*grafanapath", handler.handleGrafana)
 	server.router.OPTIONS("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.PATCH("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.POST("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.PUT("/grafana/*grafanapath", handler.handleGrafana)
+	server.router.PATCH("/grafana/*grafanapath", handler.disabledInReadOnly(handler.handleGrafana))
+	server.router.POST("/grafana/*grafanapath", handler.disabledInReadOnly(handler.handleGrafana))
+	server.router.PUT("/grafana/*grafanapath", handler.disabledInReadOnly(handler.handleGrafana))
 
 	return httpServer
 }
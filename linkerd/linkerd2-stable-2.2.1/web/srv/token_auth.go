@@ -0,0 +1,92 @@
+package srv
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/webtoken"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// tokenParam is the query parameter of the dashboard URLs printed by
+	// `linkerd dashboard --external`
+	tokenParam = "token"
+
+	// tokenCookie keeps the token of the first request, so that the links of
+	// the dashboard don't need to carry it
+	tokenCookie = "linkerd-web-token"
+)
+
+type tokenAuth struct {
+	handler http.Handler
+	keyPath string
+	now     func() time.Time
+}
+
+// NewExternalServer returns a copy of the server, listening on addr, that
+// only serves the requests with a token signed with the key at keyPath. The
+// key is read on every request, as its secret is only created by `linkerd
+// dashboard --external`, once the control plane is running.
+func NewExternalServer(server *http.Server, addr string, keyPath string) *http.Server {
+	return &http.Server{
+		Addr:         addr,
+		ReadTimeout:  server.ReadTimeout,
+		WriteTimeout: server.WriteTimeout,
+		Handler:      &tokenAuth{handler: server.Handler, keyPath: keyPath, now: time.Now},
+	}
+}
+
+func (a *tokenAuth) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key, err := ioutil.ReadFile(a.keyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "external access to the dashboard isn't enabled; run `linkerd dashboard --external`", http.StatusForbidden)
+			return
+		}
+		log.Errorf("failed to read the token key: %s", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if token := req.URL.Query().Get(tokenParam); token != "" {
+		expiry, err := webtoken.Validate(key, token, a.now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		setTokenCookie(w, token, expiry)
+		a.handler.ServeHTTP(w, req)
+		return
+	}
+
+	cookie, err := req.Cookie(tokenCookie)
+	if err != nil {
+		http.Error(w, "a token is required to access the dashboard from outside the cluster", http.StatusUnauthorized)
+		return
+	}
+	if _, err := webtoken.Validate(key, cookie.Value, a.now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	a.handler.ServeHTTP(w, req)
+}
+
+// setTokenCookie sets the cookie keeping the token, only sent back over
+// HTTPS, as the dashboard is only exposed through an Ingress terminating TLS,
+// and neither readable by scripts nor sent by the requests of other sites.
+// http.Cookie has no SameSite attribute before Go 1.11, hence the header is
+// written as is.
+func setTokenCookie(w http.ResponseWriter, token string, expiry time.Time) {
+	cookie := &http.Cookie{
+		Name:     tokenCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiry,
+		Secure:   true,
+		HttpOnly: true,
+	}
+	w.Header().Add("Set-Cookie", cookie.String()+"; SameSite=Strict")
+}
@@ -0,0 +1,78 @@
+package srv
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/webtoken"
+)
+
+func TestTokenAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token-auth")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyPath, []byte("key"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	now := time.Unix(1550000000, 0)
+	valid := webtoken.Generate([]byte("key"), now.Add(time.Hour))
+	expired := webtoken.Generate([]byte("key"), now.Add(-time.Hour))
+
+	auth := &tokenAuth{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}),
+		keyPath: keyPath,
+		now:     func() time.Time { return now },
+	}
+
+	testCases := []struct {
+		keyPath        string
+		url            string
+		cookie         string
+		expectedStatus int
+		expectedCookie bool
+	}{
+		{keyPath, "/?token=" + valid, "", http.StatusOK, true},
+		{keyPath, "/deployments", valid, http.StatusOK, false},
+		{keyPath, "/?token=" + expired, "", http.StatusUnauthorized, false},
+		{keyPath, "/deployments", expired, http.StatusUnauthorized, false},
+		{keyPath, "/deployments", "", http.StatusUnauthorized, false},
+		{filepath.Join(dir, "missing"), "/?token=" + valid, "", http.StatusForbidden, false},
+	}
+
+	for i, tc := range testCases {
+		auth.keyPath = tc.keyPath
+		req := httptest.NewRequest("GET", tc.url, nil)
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: tokenCookie, Value: tc.cookie})
+		}
+		recorder := httptest.NewRecorder()
+
+		auth.ServeHTTP(recorder, req)
+
+		if recorder.Code != tc.expectedStatus {
+			t.Fatalf("test case %d: expected status %d, got %d", i, tc.expectedStatus, recorder.Code)
+		}
+		cookie := recorder.Header().Get("Set-Cookie")
+		if tc.expectedCookie != (cookie != "") {
+			t.Fatalf("test case %d: expected a cookie to be set to be %t, got [%s]", i, tc.expectedCookie, cookie)
+		}
+		if cookie != "" {
+			for _, attr := range []string{"Secure", "HttpOnly", "SameSite=Strict"} {
+				if !strings.Contains(cookie, "; "+attr) {
+					t.Fatalf("test case %d: expected the cookie to be %s, got [%s]", i, attr, cookie)
+				}
+			}
+		}
+	}
+}